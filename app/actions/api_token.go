@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+const maxAPITokenExpiryDays = 365
+
+// CreateAPIToken is used to create a new named API token for current user
+type CreateAPIToken struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes" format:"lower"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateAPIToken) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *CreateAPIToken) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", propertyIsRequired(ctx, "name"))
+	} else if len(action.Name) > 50 {
+		result.AddFieldFailure("name", propertyMaxStringLen(ctx, "name", 50))
+	}
+
+	if len(action.Scopes) == 0 {
+		result.AddFieldFailure("scopes", propertyIsRequired(ctx, "scopes"))
+	}
+
+	for _, scope := range action.Scopes {
+		if !slices.Contains(entity.AllAPIScopes, scope) || !entity.CanBeGrantedScope(user, scope) {
+			result.AddFieldFailure("scopes", i18n.T(ctx, "validation.custom.apitokenscope", i18n.Params{"scope": scope}))
+		}
+	}
+
+	if action.ExpiresInDays < 0 || action.ExpiresInDays > maxAPITokenExpiryDays {
+		result.AddFieldFailure("expiresInDays", i18n.T(ctx, "validation.custom.apitokenexpiry", i18n.Params{"max": maxAPITokenExpiryDays}))
+	}
+
+	return result
+}
+
+// ExpiresAt returns when the token should expire, nil means never
+func (action *CreateAPIToken) ExpiresAt() *time.Time {
+	if action.ExpiresInDays == 0 {
+		return nil
+	}
+	expiresAt := time.Now().AddDate(0, 0, action.ExpiresInDays)
+	return &expiresAt
+}
+
+// RevokeAPIToken is used to revoke an API token, administrators can revoke tokens of any user
+type RevokeAPIToken struct {
+	ID int `route:"id"`
+
+	Token *entity.APIToken
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RevokeAPIToken) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *RevokeAPIToken) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getToken := &query.GetAPITokenByID{TokenID: action.ID}
+	if err := bus.Dispatch(ctx, getToken); err != nil {
+		return validate.Error(err)
+	}
+
+	if getToken.Result.User.ID != user.ID && !user.IsAdministrator() {
+		return validate.Error(app.ErrNotFound)
+	}
+
+	action.Token = getToken.Result
+	return validate.Success()
+}
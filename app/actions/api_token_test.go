@@ -0,0 +1,67 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateAPIToken_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	action := &actions.CreateAPIToken{Name: rand.String(51), ExpiresInDays: 400}
+	result := action.Validate(context.Background(), visitor)
+	ExpectFailed(result, "name", "scopes", "expiresInDays")
+}
+
+func TestCreateAPIToken_ScopesByRole(t *testing.T) {
+	RegisterT(t)
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 2, Role: enum.RoleCollaborator}
+	administrator := &entity.User{ID: 3, Role: enum.RoleAdministrator}
+
+	action := &actions.CreateAPIToken{Name: "Reader", Scopes: []string{entity.APIScopePostsRead}}
+	ExpectSuccess(action.Validate(context.Background(), visitor))
+
+	action = &actions.CreateAPIToken{Name: "Writer", Scopes: []string{entity.APIScopePostsWrite}}
+	ExpectFailed(action.Validate(context.Background(), visitor), "scopes")
+	ExpectSuccess(action.Validate(context.Background(), collaborator))
+
+	action = &actions.CreateAPIToken{Name: "Admin", Scopes: []string{entity.APIScopeAdmin}, ExpiresInDays: 30}
+	ExpectFailed(action.Validate(context.Background(), collaborator), "scopes")
+	ExpectSuccess(action.Validate(context.Background(), administrator))
+	Expect(action.ExpiresAt()).IsNotNil()
+
+	action = &actions.CreateAPIToken{Name: "Unknown", Scopes: []string{"everything"}}
+	ExpectFailed(action.Validate(context.Background(), administrator), "scopes")
+}
+
+func TestRevokeAPIToken_OtherUser(t *testing.T) {
+	RegisterT(t)
+
+	owner := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByID) error {
+		q.Result = &entity.APIToken{ID: q.TokenID, User: owner}
+		return nil
+	})
+
+	action := &actions.RevokeAPIToken{ID: 5}
+	result := action.Validate(context.Background(), &entity.User{ID: 2, Role: enum.RoleCollaborator})
+	Expect(result.Ok).IsFalse()
+	Expect(result.Err).IsNotNil()
+
+	action = &actions.RevokeAPIToken{ID: 5}
+	ExpectSuccess(action.Validate(context.Background(), owner))
+
+	action = &actions.RevokeAPIToken{ID: 5}
+	ExpectSuccess(action.Validate(context.Background(), &entity.User{ID: 3, Role: enum.RoleAdministrator}))
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// CreateEditCompany is used to create a new company or edit existing
+type CreateEditCompany struct {
+	ID   int    `route:"id"`
+	Name string `json:"name"`
+	MRR  int    `json:"mrr"`
+	Plan string `json:"plan"`
+
+	Company *entity.Company
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditCompany) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditCompany) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.ID > 0 {
+		getCompany := &query.GetCompanyByID{CompanyID: action.ID}
+		if err := bus.Dispatch(ctx, getCompany); err != nil {
+			return validate.Error(err)
+		}
+		action.Company = getCompany.Result
+	}
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 100 {
+		result.AddFieldFailure("name", "Name must have less than 100 characters.")
+	} else if action.Company == nil || !strings.EqualFold(action.Company.Name, action.Name) {
+		getDuplicate := &query.GetCompanyByName{Name: action.Name}
+		err := bus.Dispatch(ctx, getDuplicate)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil {
+			result.AddFieldFailure("name", "This company name is already in use.")
+		}
+	}
+
+	if action.MRR < 0 {
+		result.AddFieldFailure("mrr", "MRR cannot be negative.")
+	}
+
+	if len(action.Plan) > 50 {
+		result.AddFieldFailure("plan", "Plan must have less than 50 characters.")
+	}
+
+	return result
+}
+
+// DeleteCompany is used to delete an existing company
+type DeleteCompany struct {
+	ID int `route:"id"`
+
+	Company *entity.Company
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeleteCompany) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeleteCompany) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getCompany := &query.GetCompanyByID{CompanyID: action.ID}
+	if err := bus.Dispatch(ctx, getCompany); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Company = getCompany.Result
+	return validate.Success()
+}
+
+// SetUserCompany is used to assign a user to a company, or remove it from one when CompanyID is zero
+type SetUserCompany struct {
+	UserID    int `route:"userID"`
+	CompanyID int `json:"companyID"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetUserCompany) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *SetUserCompany) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getUser := &query.GetUserByID{UserID: action.UserID}
+	if err := bus.Dispatch(ctx, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			result.AddFieldFailure("userID", "User not found.")
+			return result
+		}
+		return validate.Error(err)
+	} else if getUser.Result.Tenant.ID != user.Tenant.ID {
+		result.AddFieldFailure("userID", "User not found.")
+		return result
+	}
+
+	if action.CompanyID > 0 {
+		getCompany := &query.GetCompanyByID{CompanyID: action.CompanyID}
+		if err := bus.Dispatch(ctx, getCompany); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				result.AddFieldFailure("companyID", "Company not found.")
+				return result
+			}
+			return validate.Error(err)
+		}
+	}
+
+	return result
+}
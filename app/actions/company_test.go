@@ -0,0 +1,67 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditCompany_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		if q.Name == "Acme Inc." {
+			q.Result = &entity.Company{ID: 1, Name: "Acme Inc."}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	for _, name := range []string{
+		"",
+		"Acme Inc.",
+		rand.String(101),
+	} {
+		action := &actions.CreateEditCompany{Name: name}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditCompany_InvalidMRRAndPlan(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditCompany{Name: "Acme Inc.", MRR: -1, Plan: rand.String(51)}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "mrr", "plan")
+}
+
+func TestCreateEditCompany_KeepSameName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByID) error {
+		q.Result = &entity.Company{ID: q.CompanyID, Name: "Acme Inc."}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		q.Result = &entity.Company{ID: 1, Name: "Acme Inc."}
+		return nil
+	})
+
+	action := &actions.CreateEditCompany{ID: 1, Name: "Acme Inc.", MRR: 500}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Company.ID).Equals(1)
+}
@@ -0,0 +1,171 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/gosimple/slug"
+)
+
+// CreateEditCustomField is used to create a new custom field or edit existing
+type CreateEditCustomField struct {
+	Key      string               `route:"key"`
+	Name     string               `json:"name"`
+	Type     enum.CustomFieldType `json:"type"`
+	Options  []string             `json:"options"`
+	IsPublic bool                 `json:"isPublic"`
+	Position int                  `json:"position"`
+
+	Field *entity.CustomField
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditCustomField) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditCustomField) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Key != "" {
+		getField := &query.GetCustomFieldByKey{Key: action.Key}
+		if err := bus.Dispatch(ctx, getField); err != nil {
+			return validate.Error(err)
+		}
+		action.Field = getField.Result
+		action.Type = action.Field.Type
+	} else if action.Type.Name() == "unknown" {
+		result.AddFieldFailure("type", "Type is invalid.")
+	}
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 50 {
+		result.AddFieldFailure("name", "Name must have less than 50 characters.")
+	} else if action.Field == nil {
+		key := slug.Make(action.Name)
+		getDuplicateKey := &query.GetCustomFieldByKey{Key: key}
+		err := bus.Dispatch(ctx, getDuplicateKey)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil {
+			result.AddFieldFailure("name", "This field name is already in use.")
+		} else if key == "" {
+			result.AddFieldFailure("name", "Name must contain at least one letter or number.")
+		}
+	}
+
+	if action.Type.HasOptions() {
+		if len(action.Options) == 0 {
+			result.AddFieldFailure("options", "At least one option is required.")
+		}
+
+		seen := make(map[string]bool, len(action.Options))
+		for _, option := range action.Options {
+			if option == "" {
+				result.AddFieldFailure("options", "Options cannot be empty.")
+			} else if len(option) > 100 {
+				result.AddFieldFailure("options", fmt.Sprintf("Option '%s' must have less than 100 characters.", option))
+			} else if seen[option] {
+				result.AddFieldFailure("options", fmt.Sprintf("Option '%s' is duplicated.", option))
+			}
+			seen[option] = true
+		}
+	}
+
+	return result
+}
+
+// DeleteCustomField is used to delete an existing custom field
+type DeleteCustomField struct {
+	Key string `route:"key"`
+
+	Field *entity.CustomField
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeleteCustomField) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeleteCustomField) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getField := &query.GetCustomFieldByKey{Key: action.Key}
+	if err := bus.Dispatch(ctx, getField); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Field = getField.Result
+	return validate.Success()
+}
+
+// SetPostCustomFields is used to set the custom field values of a post
+type SetPostCustomFields struct {
+	Number int                        `route:"number"`
+	Fields map[string]json.RawMessage `json:"fields"`
+
+	Post   *entity.Post
+	Values []*entity.CustomFieldValue
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetPostCustomFields) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *SetPostCustomFields) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	getFields := &query.GetAllCustomFields{}
+	if err := bus.Dispatch(ctx, getPost, getFields); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	fields := make(map[string]*entity.CustomField, len(getFields.Result))
+	for _, field := range getFields.Result {
+		fields[field.Key] = field
+	}
+
+	action.Values = make([]*entity.CustomFieldValue, 0, len(action.Fields))
+	for key, raw := range action.Fields {
+		fieldName := "fields." + key
+		field, ok := fields[key]
+		if !ok {
+			result.AddFieldFailure(fieldName, fmt.Sprintf("Unknown custom field '%s'.", key))
+			continue
+		}
+
+		value, ok := field.ParseValue(raw)
+		if !ok {
+			result.AddFieldFailure(fieldName, fmt.Sprintf("%s has an invalid value.", field.Name))
+			continue
+		}
+
+		if userID, isUser := value.(int); isUser {
+			getUser := &query.GetUserByID{UserID: userID}
+			err := bus.Dispatch(ctx, getUser)
+			if err != nil && errors.Cause(err) != app.ErrNotFound {
+				return validate.Error(err)
+			} else if err != nil {
+				result.AddFieldFailure(fieldName, fmt.Sprintf("%s has an invalid value.", field.Name))
+				continue
+			}
+		}
+
+		action.Values = append(action.Values, &entity.CustomFieldValue{Field: field, Value: value})
+	}
+
+	return result
+}
@@ -0,0 +1,140 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditCustomField_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomFieldByKey) error {
+		if q.Key == "platform" {
+			q.Result = &entity.CustomField{ID: 1, Key: "platform", Name: "Platform", Type: enum.CustomFieldText}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	for _, name := range []string{
+		"",
+		"Platform",
+		"!!!",
+		rand.String(51),
+	} {
+		action := &actions.CreateEditCustomField{Name: name, Type: enum.CustomFieldText}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditCustomField_InvalidOptions(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomFieldByKey) error {
+		return app.ErrNotFound
+	})
+
+	for _, options := range [][]string{
+		nil,
+		{"iOS", ""},
+		{"iOS", "iOS"},
+		{rand.String(101)},
+	} {
+		action := &actions.CreateEditCustomField{Name: "Platform", Type: enum.CustomFieldMultiSelect, Options: options}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "options")
+	}
+}
+
+func TestCreateEditCustomField_InvalidType(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomFieldByKey) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditCustomField{Name: "Platform"}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "type")
+}
+
+func TestCreateEditCustomField_EditKeepsType(t *testing.T) {
+	RegisterT(t)
+
+	field := &entity.CustomField{ID: 1, Key: "segment", Name: "Segment", Type: enum.CustomFieldSelect, Options: []string{"SMB"}}
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomFieldByKey) error {
+		if q.Key == field.Key {
+			q.Result = field
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditCustomField{Key: "segment", Name: "Customer Segment", Type: enum.CustomFieldText, Options: []string{"SMB", "Enterprise"}}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Field).Equals(field)
+	Expect(action.Type).Equals(enum.CustomFieldSelect)
+}
+
+func TestSetPostCustomFields(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllCustomFields) error {
+		q.Result = []*entity.CustomField{
+			{ID: 1, Key: "segment", Type: enum.CustomFieldSelect, Options: []string{"SMB", "Enterprise"}},
+			{ID: 2, Key: "arr-impact", Type: enum.CustomFieldNumber},
+			{ID: 3, Key: "owner", Type: enum.CustomFieldUser},
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		if q.UserID == 2 {
+			q.Result = &entity.User{ID: 2}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	action := &actions.SetPostCustomFields{
+		Number: 1,
+		Fields: map[string]json.RawMessage{
+			"segment":    json.RawMessage(`"Enterprise"`),
+			"arr-impact": json.RawMessage(`null`),
+			"owner":      json.RawMessage(`2`),
+		},
+	}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Post).Equals(post)
+	Expect(action.Values).HasLen(3)
+
+	action = &actions.SetPostCustomFields{
+		Number: 1,
+		Fields: map[string]json.RawMessage{
+			"segment": json.RawMessage(`"Unknown"`),
+			"owner":   json.RawMessage(`99`),
+			"other":   json.RawMessage(`"value"`),
+		},
+	}
+	result = action.Validate(context.Background(), nil)
+	ExpectFailed(result, "fields.segment", "fields.owner", "fields.other")
+}
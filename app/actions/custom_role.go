@@ -0,0 +1,138 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// CreateEditCustomRole is used to create a new custom role or edit existing
+type CreateEditCustomRole struct {
+	ID          int      `route:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+
+	Role *entity.CustomRole
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditCustomRole) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditCustomRole) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.ID > 0 {
+		getRole := &query.GetCustomRoleByID{RoleID: action.ID}
+		if err := bus.Dispatch(ctx, getRole); err != nil {
+			return validate.Error(err)
+		}
+		action.Role = getRole.Result
+	}
+
+	action.Name = strings.TrimSpace(action.Name)
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 50 {
+		result.AddFieldFailure("name", "Name must have less than 50 characters.")
+	} else if action.Role == nil || !strings.EqualFold(action.Role.Name, action.Name) {
+		getDuplicate := &query.GetCustomRoleByName{Name: action.Name}
+		err := bus.Dispatch(ctx, getDuplicate)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil {
+			result.AddFieldFailure("name", "This role name is already in use.")
+		}
+	}
+
+	if action.Permissions == nil {
+		action.Permissions = []string{}
+	}
+	for _, permission := range action.Permissions {
+		if !slices.Contains(entity.AllPermissions, permission) {
+			result.AddFieldFailure("permissions", "Permission '"+permission+"' is unknown.")
+		}
+	}
+	slices.Sort(action.Permissions)
+	action.Permissions = slices.Compact(action.Permissions)
+
+	return result
+}
+
+// DeleteCustomRole is used to delete an existing custom role
+type DeleteCustomRole struct {
+	ID int `route:"id"`
+
+	Role *entity.CustomRole
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeleteCustomRole) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeleteCustomRole) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getRole := &query.GetCustomRoleByID{RoleID: action.ID}
+	if err := bus.Dispatch(ctx, getRole); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Role = getRole.Result
+	return validate.Success()
+}
+
+// SetUserCustomRole is used to make a user a collaborator with a custom role, or a regular collaborator when RoleID is zero
+type SetUserCustomRole struct {
+	UserID int `route:"userID"`
+	RoleID int `json:"roleID"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetUserCustomRole) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SetUserCustomRole) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.UserID == user.ID {
+		result.AddFieldFailure("userID", "It is not allowed to change your own Role.")
+		return result
+	}
+
+	getUser := &query.GetUserByID{UserID: action.UserID}
+	if err := bus.Dispatch(ctx, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			result.AddFieldFailure("userID", "User not found.")
+			return result
+		}
+		return validate.Error(err)
+	} else if getUser.Result.Tenant.ID != user.Tenant.ID {
+		result.AddFieldFailure("userID", "User not found.")
+		return result
+	}
+
+	if action.RoleID > 0 {
+		getRole := &query.GetCustomRoleByID{RoleID: action.RoleID}
+		if err := bus.Dispatch(ctx, getRole); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				result.AddFieldFailure("roleID", "Role not found.")
+				return result
+			}
+			return validate.Error(err)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditCustomRole_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByName) error {
+		if q.Name == "Support Agent" {
+			q.Result = &entity.CustomRole{ID: 1, Name: "Support Agent"}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	for _, name := range []string{
+		"",
+		"  ",
+		"Support Agent",
+		rand.String(51),
+	} {
+		action := &actions.CreateEditCustomRole{Name: name}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditCustomRole_InvalidPermission(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByName) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditCustomRole{Name: "Support Agent", Permissions: []string{entity.PermissionRespond, "billing:manage"}}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "permissions")
+}
+
+func TestCreateEditCustomRole_DuplicatePermissions(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByName) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditCustomRole{
+		Name:        "Support Agent",
+		Permissions: []string{entity.PermissionRespond, entity.PermissionManageTags, entity.PermissionRespond},
+	}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.Permissions).Equals([]string{entity.PermissionRespond, entity.PermissionManageTags})
+}
+
+func TestCreateEditCustomRole_OnlyAdministrators(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.CreateEditCustomRole{}
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleAdministrator})).IsTrue()
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleCollaborator})).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), nil)).IsFalse()
+}
+
+func TestSetUserCustomRole_OwnRole(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.SetUserCustomRole{UserID: mock.JonSnow.ID, RoleID: 1}
+	result := action.Validate(context.Background(), mock.JonSnow)
+	ExpectFailed(result, "userID")
+}
+
+func TestSetUserCustomRole_UnknownRole(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByID) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.SetUserCustomRole{UserID: mock.AryaStark.ID, RoleID: 9}
+	result := action.Validate(context.Background(), mock.JonSnow)
+	ExpectFailed(result, "roleID")
+}
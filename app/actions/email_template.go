@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// SaveEmailTemplate overrides the default email template of a tenant for a locale
+type SaveEmailTemplate struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SaveEmailTemplate) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SaveEmailTemplate) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if !entity.IsEditableEmailTemplate(action.Name) {
+		result.AddFieldFailure("name", "Email template is not valid.")
+	}
+
+	if !i18n.IsValidLocale(action.Locale) {
+		result.AddFieldFailure("locale", "Locale is not valid.")
+	}
+
+	runCompileCheck := true
+	if strings.TrimSpace(action.Subject) == "" {
+		result.AddFieldFailure("subject", "Subject is required.")
+		runCompileCheck = false
+	} else if len(action.Subject) > 300 {
+		result.AddFieldFailure("subject", "Subject must have less than 300 characters.")
+		runCompileCheck = false
+	}
+
+	if strings.TrimSpace(action.Body) == "" {
+		result.AddFieldFailure("body", "Body is required.")
+		runCompileCheck = false
+	} else if len(action.Body) > 50_000 {
+		result.AddFieldFailure("body", "Body must have less than 50 000 characters.")
+		runCompileCheck = false
+	}
+
+	if runCompileCheck && result.Ok {
+		previewEmailTemplate := &cmd.PreviewEmailTemplate{
+			Name:    action.Name,
+			Subject: action.Subject,
+			Body:    action.Body,
+		}
+		if err := bus.Dispatch(ctx, previewEmailTemplate); err != nil {
+			return validate.Error(err)
+		}
+
+		if previewEmailTemplate.Result.Error != "" {
+			result.AddFieldFailure("body", "Template must compile: "+previewEmailTemplate.Result.Error)
+		}
+	}
+
+	return result
+}
+
+// PreviewEmailTemplate renders an email template with sample data
+type PreviewEmailTemplate struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *PreviewEmailTemplate) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *PreviewEmailTemplate) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if !entity.IsEditableEmailTemplate(action.Name) {
+		result.AddFieldFailure("name", "Email template is not valid.")
+	}
+
+	return result
+}
@@ -0,0 +1,60 @@
+package actions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestSaveEmailTemplate_Authorization(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.SaveEmailTemplate{}
+	Expect(action.IsAuthorized(context.Background(), nil)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleCollaborator})).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleAdministrator})).IsTrue()
+}
+
+func TestSaveEmailTemplate_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	for _, testCase := range []struct {
+		action *actions.SaveEmailTemplate
+		field  string
+	}{
+		{&actions.SaveEmailTemplate{Name: "echo_test", Locale: "en", Subject: "Hi", Body: "Hello"}, "name"},
+		{&actions.SaveEmailTemplate{Name: "new_post", Locale: "xx", Subject: "Hi", Body: "Hello"}, "locale"},
+		{&actions.SaveEmailTemplate{Name: "new_post", Locale: "en", Subject: "", Body: "Hello"}, "subject"},
+		{&actions.SaveEmailTemplate{Name: "new_post", Locale: "en", Subject: strings.Repeat("a", 301), Body: "Hello"}, "subject"},
+		{&actions.SaveEmailTemplate{Name: "new_post", Locale: "en", Subject: "Hi", Body: " "}, "body"},
+	} {
+		result := testCase.action.Validate(context.Background(), nil)
+		ExpectFailed(result, testCase.field)
+	}
+}
+
+func TestSaveEmailTemplate_MustCompile(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.PreviewEmailTemplate) error {
+		c.Result = &dto.EmailTemplatePreview{}
+		if !strings.HasSuffix(c.Body, "}}") {
+			c.Result.Error = "unclosed action"
+		}
+		return nil
+	})
+
+	action := &actions.SaveEmailTemplate{Name: "new_post", Locale: "pt-BR", Subject: "Nova ideia", Body: "{{ .title }}"}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+
+	action = &actions.SaveEmailTemplate{Name: "new_post", Locale: "pt-BR", Subject: "Nova ideia", Body: "{{ .title "}
+	ExpectFailed(action.Validate(context.Background(), nil), "body")
+}
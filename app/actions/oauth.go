@@ -18,21 +18,22 @@ import (
 
 // CreateEditOAuthConfig is used to create/edit OAuth config
 type CreateEditOAuthConfig struct {
-	ID                int
-	Logo              *dto.ImageUpload `json:"logo"`
-	Provider          string           `json:"provider"`
-	Status            int              `json:"status"`
-	DisplayName       string           `json:"displayName"`
-	ClientID          string           `json:"clientID"`
-	ClientSecret      string           `json:"clientSecret"`
-	AuthorizeURL      string           `json:"authorizeURL"`
-	TokenURL          string           `json:"tokenURL"`
-	Scope             string           `json:"scope"`
-	ProfileURL        string           `json:"profileURL"`
-	IsTrusted         bool             `json:"isTrusted"`
-	JSONUserIDPath    string           `json:"jsonUserIDPath"`
-	JSONUserNamePath  string           `json:"jsonUserNamePath"`
-	JSONUserEmailPath string           `json:"jsonUserEmailPath"`
+	ID                 int
+	Logo               *dto.ImageUpload `json:"logo"`
+	Provider           string           `json:"provider"`
+	Status             int              `json:"status"`
+	DisplayName        string           `json:"displayName"`
+	ClientID           string           `json:"clientID"`
+	ClientSecret       string           `json:"clientSecret"`
+	AuthorizeURL       string           `json:"authorizeURL"`
+	TokenURL           string           `json:"tokenURL"`
+	Scope              string           `json:"scope"`
+	ProfileURL         string           `json:"profileURL"`
+	IsTrusted          bool             `json:"isTrusted"`
+	JSONUserIDPath     string           `json:"jsonUserIDPath"`
+	JSONUserNamePath   string           `json:"jsonUserNamePath"`
+	JSONUserEmailPath  string           `json:"jsonUserEmailPath"`
+	JSONUserGroupsPath string           `json:"jsonUserGroupsPath"`
 }
 
 func NewCreateEditOAuthConfig() *CreateEditOAuthConfig {
@@ -151,5 +152,9 @@ func (action *CreateEditOAuthConfig) Validate(ctx context.Context, user *entity.
 		result.AddFieldFailure("jsonUserEmailPath", "JSON User Email Path must have less than 100 characters.")
 	}
 
+	if len(action.JSONUserGroupsPath) > 100 {
+		result.AddFieldFailure("jsonUserGroupsPath", "JSON User Groups Path must have less than 100 characters.")
+	}
+
 	return result
 }
@@ -0,0 +1,194 @@
+package actions
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+const maxOAuthClientRedirectURIs = 10
+
+// CreateOAuthClient is used to register a new application that can sign users in through this site
+type CreateOAuthClient struct {
+	Name           string   `json:"name"`
+	RedirectURIs   []string `json:"redirectUris"`
+	IsConfidential bool     `json:"isConfidential"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateOAuthClient) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateOAuthClient) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 50 {
+		result.AddFieldFailure("name", "Name must have less than 50 characters.")
+	}
+
+	if len(action.RedirectURIs) == 0 {
+		result.AddFieldFailure("redirectUris", "At least one redirect URI is required.")
+	} else if len(action.RedirectURIs) > maxOAuthClientRedirectURIs {
+		result.AddFieldFailure("redirectUris", "An application can have at most 10 redirect URIs.")
+	}
+
+	for _, uri := range action.RedirectURIs {
+		if !isValidOAuthRedirectURI(uri) {
+			result.AddFieldFailure("redirectUris", "'"+uri+"' is not a valid redirect URI. Use HTTPS, HTTP on localhost or a private-use scheme such as 'com.example.app:/callback'.")
+		}
+	}
+
+	return result
+}
+
+// isValidOAuthRedirectURI returns true if given URI is an absolute URI that is safe to redirect users to
+func isValidOAuthRedirectURI(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || u.Fragment != "" || len(uri) > 300 {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+
+	// Native apps use private-use schemes based on a domain name they own
+	return strings.Contains(u.Scheme, ".")
+}
+
+// RevokeOAuthClient is used to revoke an OAuth client and every refresh token issued to it
+type RevokeOAuthClient struct {
+	ID int `route:"id"`
+
+	Client *entity.OAuthClient
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RevokeOAuthClient) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *RevokeOAuthClient) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getClient := &query.GetOAuthClientByID{ID: action.ID}
+	if err := bus.Dispatch(ctx, getClient); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Client = getClient.Result
+	return validate.Success()
+}
+
+// AuthorizeOAuthClient is used when a user authorizes an OAuth client to act on their behalf
+type AuthorizeOAuthClient struct {
+	ClientID            string `json:"clientId"`
+	RedirectURI         string `json:"redirectUri"`
+	ResponseType        string `json:"responseType"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	Nonce               string `json:"nonce"`
+	CodeChallenge       string `json:"codeChallenge"`
+	CodeChallengeMethod string `json:"codeChallengeMethod"`
+	Approved            bool   `json:"approved"`
+
+	Client *entity.OAuthClient
+	Scopes []string
+
+	// Error is the OAuth error code that must be sent back to the client through its redirect URI
+	Error string
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *AuthorizeOAuthClient) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+// Only an unknown client or redirect URI fails the validation, as it's not safe to redirect the user back to it.
+// Any other problem is reported to the client through the Error field.
+func (action *AuthorizeOAuthClient) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getClient := &query.GetOAuthClientByClientID{ClientID: action.ClientID}
+	if err := bus.Dispatch(ctx, getClient); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return validate.Failed(i18n.T(ctx, "validation.custom.oauthclient"))
+		}
+		return validate.Error(err)
+	}
+
+	if !getClient.Result.HasRedirectURI(action.RedirectURI) {
+		return validate.Failed(i18n.T(ctx, "validation.custom.oauthclient"))
+	}
+
+	action.Client = getClient.Result
+
+	if action.ResponseType != "code" {
+		action.Error = "unsupported_response_type"
+		return validate.Success()
+	}
+
+	// PKCE is required for every client, confidential or not
+	if action.CodeChallenge == "" || action.CodeChallengeMethod != "S256" || len(action.CodeChallenge) > 128 || len(action.Nonce) > 200 {
+		action.Error = "invalid_request"
+		return validate.Success()
+	}
+
+	requested := strings.Fields(action.Scope)
+	if len(requested) == 0 {
+		action.Error = "invalid_scope"
+		return validate.Success()
+	}
+
+	action.Scopes = make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !slices.Contains(entity.AllOAuthScopes, scope) {
+			action.Error = "invalid_scope"
+			return validate.Success()
+		}
+
+		// API scopes the user isn't allowed to grant are left out, the client is told which scopes were granted
+		if user != nil && slices.Contains(entity.AllAPIScopes, scope) && !entity.CanBeGrantedScope(user, scope) {
+			continue
+		}
+
+		if !slices.Contains(action.Scopes, scope) {
+			action.Scopes = append(action.Scopes, scope)
+		}
+	}
+
+	if len(action.Scopes) == 0 {
+		action.Error = "invalid_scope"
+	}
+
+	return validate.Success()
+}
+
+// RedirectURL returns the client redirect URI with given parameters and the state added to it
+func (action *AuthorizeOAuthClient) RedirectURL(params url.Values) string {
+	u, _ := url.Parse(action.RedirectURI)
+	query := u.Query()
+	for key := range params {
+		query.Set(key, params.Get(key))
+	}
+	if action.State != "" {
+		query.Set("state", action.State)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,156 @@
+package actions_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateOAuthClient_InvalidInput(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.CreateOAuthClient{Name: rand.String(51)}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "name", "redirectUris")
+}
+
+func TestCreateOAuthClient_RedirectURIs(t *testing.T) {
+	RegisterT(t)
+
+	for _, uri := range []string{
+		"https://example.com/callback",
+		"http://localhost:3000/callback",
+		"http://127.0.0.1/callback",
+		"com.example.app:/callback",
+	} {
+		action := &actions.CreateOAuthClient{Name: "My App", RedirectURIs: []string{uri}}
+		ExpectSuccess(action.Validate(context.Background(), nil))
+	}
+
+	for _, uri := range []string{
+		"http://example.com/callback",
+		"https://example.com/callback#token",
+		"javascript:alert(1)",
+		"/callback",
+		"https:///callback",
+	} {
+		action := &actions.CreateOAuthClient{Name: "My App", RedirectURIs: []string{uri}}
+		ExpectFailed(action.Validate(context.Background(), nil), "redirectUris")
+	}
+}
+
+func TestAuthorizeOAuthClient_UnknownClient(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.AuthorizeOAuthClient{ClientID: "unknown", RedirectURI: "https://example.com/callback"}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result)
+	Expect(action.Client).IsNil()
+}
+
+func TestAuthorizeOAuthClient_UnregisteredRedirectURI(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		q.Result = &entity.OAuthClient{ID: 1, ClientID: q.ClientID, RedirectURIs: []string{"https://example.com/callback"}}
+		return nil
+	})
+
+	action := &actions.AuthorizeOAuthClient{ClientID: "my-app", RedirectURI: "https://evil.com/callback"}
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result)
+}
+
+func TestAuthorizeOAuthClient_ProtocolErrors(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		q.Result = &entity.OAuthClient{ID: 1, ClientID: q.ClientID, RedirectURIs: []string{"https://example.com/callback"}}
+		return nil
+	})
+
+	newAction := func() *actions.AuthorizeOAuthClient {
+		return &actions.AuthorizeOAuthClient{
+			ClientID:            "my-app",
+			RedirectURI:         "https://example.com/callback",
+			ResponseType:        "code",
+			Scope:               "openid profile",
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: "S256",
+		}
+	}
+
+	action := newAction()
+	ExpectSuccess(action.Validate(context.Background(), nil))
+	Expect(action.Error).Equals("")
+
+	action = newAction()
+	action.ResponseType = "token"
+	ExpectSuccess(action.Validate(context.Background(), nil))
+	Expect(action.Error).Equals("unsupported_response_type")
+
+	action = newAction()
+	action.CodeChallengeMethod = "plain"
+	ExpectSuccess(action.Validate(context.Background(), nil))
+	Expect(action.Error).Equals("invalid_request")
+
+	action = newAction()
+	action.Scope = "openid everything"
+	ExpectSuccess(action.Validate(context.Background(), nil))
+	Expect(action.Error).Equals("invalid_scope")
+}
+
+func TestAuthorizeOAuthClient_ScopesByRole(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		q.Result = &entity.OAuthClient{ID: 1, ClientID: q.ClientID, RedirectURIs: []string{"https://example.com/callback"}}
+		return nil
+	})
+
+	newAction := func(scope string) *actions.AuthorizeOAuthClient {
+		return &actions.AuthorizeOAuthClient{
+			ClientID:            "my-app",
+			RedirectURI:         "https://example.com/callback",
+			ResponseType:        "code",
+			Scope:               scope,
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: "S256",
+		}
+	}
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	administrator := &entity.User{ID: 2, Role: enum.RoleAdministrator}
+
+	action := newAction("openid posts:read posts:write")
+	ExpectSuccess(action.Validate(context.Background(), visitor))
+	Expect(action.Scopes).Equals([]string{"openid", "posts:read"})
+
+	action = newAction("openid posts:read posts:write")
+	ExpectSuccess(action.Validate(context.Background(), administrator))
+	Expect(action.Scopes).Equals([]string{"openid", "posts:read", "posts:write"})
+
+	action = newAction("admin")
+	ExpectSuccess(action.Validate(context.Background(), visitor))
+	Expect(action.Error).Equals("invalid_scope")
+}
+
+func TestAuthorizeOAuthClient_RedirectURL(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.AuthorizeOAuthClient{RedirectURI: "https://example.com/callback?tenant=1", State: "xyz"}
+	Expect(action.RedirectURL(url.Values{"code": {"abc"}})).Equals("https://example.com/callback?code=abc&state=xyz&tenant=1")
+}
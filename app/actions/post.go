@@ -26,6 +26,7 @@ type CreateNewPost struct {
 	Description string             `json:"description"`
 	TagSlugs    []string           `json:"tags"`
 	Attachments []*dto.ImageUpload `json:"attachments"`
+	IsPrivate   bool               `json:"isPrivate"`
 
 	Tags []*entity.Tag
 }
@@ -54,7 +55,7 @@ func (action *CreateNewPost) IsAuthorized(ctx context.Context, user *entity.User
 		return false
 	} else if env.Config.PostCreationWithTagsEnabled && !user.IsCollaborator() {
 		for _, tag := range action.Tags {
-			if !tag.IsPublic {
+			if !tag.IsVisibleTo(user) {
 				return false
 			}
 		}
@@ -208,10 +209,14 @@ type AddNewComment struct {
 	Number      int                `route:"number"`
 	Content     string             `json:"content"`
 	Attachments []*dto.ImageUpload `json:"attachments"`
+	IsInternal  bool               `json:"isInternal"`
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *AddNewComment) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	if action.IsInternal {
+		return user != nil && user.IsCollaborator()
+	}
 	return user != nil
 }
 
@@ -248,7 +253,7 @@ type SetResponse struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *SetResponse) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsCollaborator()
+	return user != nil && user.HasPermission(entity.PermissionRespond)
 }
 
 // Validate if current model is valid
@@ -276,12 +281,110 @@ func (action *SetResponse) Validate(ctx context.Context, user *entity.User) *val
 
 		if getOriginaPost.Result != nil {
 			action.Original = getOriginaPost.Result
+
+			getPost := &query.GetPostByNumber{Number: action.Number}
+			if err := bus.Dispatch(ctx, getPost); err != nil {
+				return validate.Error(err)
+			}
+			if !action.Original.IsVisibleToViewersOf(getPost.Result) {
+				result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.originalnotvisible"))
+			}
+		}
+	}
+
+	return result
+}
+
+// MergePost represents the action of merging a duplicate post into its original
+type MergePost struct {
+	Number         int  `route:"number"`
+	OriginalNumber int  `json:"originalNumber"`
+	CopyComments   bool `json:"copyComments"`
+
+	Post     *entity.Post
+	Original *entity.Post
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *MergePost) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasPermission(entity.PermissionRespond)
+}
+
+// Validate if current model is valid
+func (action *MergePost) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	if action.Post.Status == enum.PostDuplicate || action.Post.Status == enum.PostDeleted {
+		return validate.Failed(i18n.T(ctx, "validation.custom.mergeclosedpost"))
+	}
+
+	if action.OriginalNumber == action.Number {
+		result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.selfduplicate"))
+		return result
+	}
+
+	getOriginal := &query.GetPostByNumber{Number: action.OriginalNumber}
+	if err := bus.Dispatch(ctx, getOriginal); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.originalpostnotfound"))
+			return result
 		}
+		return validate.Error(err)
+	}
+	action.Original = getOriginal.Result
+
+	if action.Original.Status == enum.PostDuplicate {
+		result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.mergeintoduplicate"))
+	}
+
+	// The duplicate links to its original, which would otherwise be revealed to those who can't see it
+	// Likewise, its comments can't be copied to an original that is visible to more people
+	if !action.Original.IsVisibleToViewersOf(action.Post) {
+		result.AddFieldFailure("originalNumber", i18n.T(ctx, "validation.custom.originalnotvisible"))
+	} else if action.CopyComments && !action.Post.IsVisibleToViewersOf(action.Original) {
+		result.AddFieldFailure("copyComments", i18n.T(ctx, "validation.custom.copyrestrictedcomments"))
 	}
 
 	return result
 }
 
+// RevertPostMerge represents the action of undoing the last merge of a duplicate post
+type RevertPostMerge struct {
+	Number int `route:"number"`
+
+	Merge *entity.PostMerge
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RevertPostMerge) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasPermission(entity.PermissionRespond)
+}
+
+// Validate if current model is valid
+func (action *RevertPostMerge) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+
+	getMerge := &query.GetActivePostMerge{PostID: getPost.Result.ID}
+	if err := bus.Dispatch(ctx, getMerge); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return validate.Failed(i18n.T(ctx, "validation.custom.postnotmerged"))
+		}
+		return validate.Error(err)
+	}
+
+	action.Merge = getMerge.Result
+	return validate.Success()
+}
+
 // DeletePost represents the action of an administrator deleting an existing Post
 type DeletePost struct {
 	Number int    `route:"number"`
@@ -316,6 +419,30 @@ func (action *DeletePost) Validate(ctx context.Context, user *entity.User) *vali
 	return validate.Success()
 }
 
+// SetPostPrivacy represents the action of a staff member making a post private or public
+type SetPostPrivacy struct {
+	Number    int  `route:"number"`
+	IsPrivate bool `json:"isPrivate"`
+
+	Post *entity.Post
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetPostPrivacy) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasPermission(entity.PermissionRespond)
+}
+
+// Validate if current model is valid
+func (action *SetPostPrivacy) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Post = getPost.Result
+	return validate.Success()
+}
+
 // EditComment represents the action to update an existing comment
 type EditComment struct {
 	PostNumber  int                `route:"number"`
@@ -337,7 +464,7 @@ func (action *EditComment) IsAuthorized(ctx context.Context, user *entity.User)
 
 	action.Post = postByNumber.Result
 	action.Comment = commentByID.Result
-	return user.ID == action.Comment.User.ID || user.IsCollaborator()
+	return user.ID == action.Comment.User.ID || user.HasPermission(entity.PermissionModerateComments)
 }
 
 // Validate if current model is valid
@@ -379,10 +506,36 @@ func (action *DeleteComment) IsAuthorized(ctx context.Context, user *entity.User
 		return false
 	}
 
-	return user.ID == commentByID.Result.User.ID || user.IsCollaborator()
+	return user.ID == commentByID.Result.User.ID || user.HasPermission(entity.PermissionModerateComments)
 }
 
 // Validate if current model is valid
 func (action *DeleteComment) Validate(ctx context.Context, user *entity.User) *validate.Result {
 	return validate.Success()
 }
+
+// ConfirmEmailPost is the email verification of a post sent by email from an address that couldn't be authenticated
+type ConfirmEmailPost struct {
+	Email string
+	Name  string
+}
+
+// GetEmail returns the address that sent the post
+func (action *ConfirmEmailPost) GetEmail() string {
+	return action.Email
+}
+
+// GetName returns the name of the sender
+func (action *ConfirmEmailPost) GetName() string {
+	return action.Name
+}
+
+// GetUser returns nil, as the sender might not be registered yet
+func (action *ConfirmEmailPost) GetUser() *entity.User {
+	return nil
+}
+
+// GetKind returns EmailVerificationKindEmailPost
+func (action *ConfirmEmailPost) GetKind() enum.EmailVerificationKind {
+	return enum.EmailVerificationKindEmailPost
+}
@@ -133,3 +133,44 @@ func TestDeleteComment(t *testing.T) {
 	authorized = action.IsAuthorized(context.Background(), administrator)
 	Expect(authorized).IsTrue()
 }
+
+func TestAddNewComment_Internal(t *testing.T) {
+	RegisterT(t)
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 2, Role: enum.RoleCollaborator}
+
+	action := &actions.AddNewComment{Number: 1, Content: "Internal note", IsInternal: true}
+	Expect(action.IsAuthorized(context.Background(), nil)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), visitor)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), collaborator)).IsTrue()
+
+	action.IsInternal = false
+	Expect(action.IsAuthorized(context.Background(), visitor)).IsTrue()
+}
+
+func TestSetPostPrivacy(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post.Number {
+			q.Result = post
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 2, Role: enum.RoleCollaborator}
+
+	action := &actions.SetPostPrivacy{Number: post.Number, IsPrivate: true}
+	Expect(action.IsAuthorized(context.Background(), visitor)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), collaborator)).IsTrue()
+	ExpectSuccess(action.Validate(context.Background(), collaborator))
+	Expect(action.Post).Equals(post)
+
+	action = &actions.SetPostPrivacy{Number: 999, IsPrivate: true}
+	result := action.Validate(context.Background(), collaborator)
+	Expect(result.Err).IsNotNil()
+}
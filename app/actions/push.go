@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/webpush"
+)
+
+// SavePushSubscription registers a browser to receive push notifications
+// The fields match the JSON of a PushSubscription given by the browser
+type SavePushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SavePushSubscription) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *SavePushSubscription) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if !isPushServiceEndpoint(action.Endpoint) {
+		result.AddFieldFailure("endpoint", "Endpoint is not valid.")
+	}
+
+	err := webpush.ValidateKeys(webpush.Keys{
+		P256dh: action.Keys.P256dh,
+		Auth:   action.Keys.Auth,
+	})
+	if err != nil {
+		result.AddFieldFailure("keys", "Keys are not valid.")
+	}
+
+	return result
+}
+
+// pushServiceHosts are the push services used by browsers, any of their subdomains is also accepted
+var pushServiceHosts = []string{
+	"fcm.googleapis.com",
+	"android.googleapis.com",
+	"push.services.mozilla.com",
+	"notify.windows.com",
+	"push.apple.com",
+}
+
+// isPushServiceEndpoint returns true for HTTPS URLs of a known push service, so that push notifications can't be sent to any other address
+func isPushServiceEndpoint(endpoint string) bool {
+	if len(endpoint) > 2000 {
+		return false
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Port() != "" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, pushHost := range pushServiceHosts {
+		if host == pushHost || strings.HasSuffix(host, "."+pushHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeletePushSubscription stops sending push notifications to a browser
+type DeletePushSubscription struct {
+	Endpoint string `json:"endpoint"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeletePushSubscription) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *DeletePushSubscription) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Endpoint == "" {
+		result.AddFieldFailure("endpoint", "Endpoint is required.")
+	}
+
+	return result
+}
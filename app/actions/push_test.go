@@ -0,0 +1,62 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/actions"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func newSavePushSubscription(endpoint string) *actions.SavePushSubscription {
+	action := &actions.SavePushSubscription{Endpoint: endpoint}
+	action.Keys.P256dh = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	action.Keys.Auth = "BTBZMqHH6r4Tts7J_aSIgg"
+	return action
+}
+
+func TestSavePushSubscription_Valid(t *testing.T) {
+	RegisterT(t)
+
+	for _, endpoint := range []string{
+		"https://fcm.googleapis.com/fcm/send/abc123",
+		"https://updates.push.services.mozilla.com/wpush/v2/abc123",
+		"https://wns2-by3p.notify.windows.com/w/?token=abc123",
+		"https://web.push.apple.com/abc123",
+	} {
+		action := newSavePushSubscription(endpoint)
+		result := action.Validate(context.Background(), nil)
+		ExpectSuccess(result)
+	}
+}
+
+func TestSavePushSubscription_InvalidEndpoint(t *testing.T) {
+	RegisterT(t)
+
+	for _, endpoint := range []string{
+		"",
+		"http://fcm.googleapis.com/fcm/send/abc123",
+		"https://localhost/push",
+		"https://127.0.0.1/push",
+		"https://[::1]/push",
+		"https://intranet/push",
+		"ftp://fcm.googleapis.com/fcm/send/abc123",
+		"https://fcm.googleapis.com:8443/fcm/send/abc123",
+		"https://example.com/push",
+		"https://fcm.googleapis.com.example.com/push",
+		"https://evilpush.apple.com/push",
+	} {
+		action := newSavePushSubscription(endpoint)
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "endpoint")
+	}
+}
+
+func TestSavePushSubscription_InvalidKeys(t *testing.T) {
+	RegisterT(t)
+
+	action := newSavePushSubscription("https://fcm.googleapis.com/fcm/send/abc123")
+	action.Keys.Auth = "abc"
+	result := action.Validate(context.Background(), nil)
+	ExpectFailed(result, "keys")
+}
@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// RestoreRevision is used to bring back the content of an earlier revision
+type RestoreRevision struct {
+	Number     int `route:"number"`
+	RevisionID int `route:"id"`
+
+	Post     *entity.Post
+	Revision *entity.Revision
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RestoreRevision) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasPermission(entity.PermissionRespond)
+}
+
+// Validate if current model is valid
+func (action *RestoreRevision) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	getRevision := &query.GetRevisionByID{PostID: action.Post.ID, RevisionID: action.RevisionID}
+	if err := bus.Dispatch(ctx, getRevision); err != nil {
+		return validate.Error(err)
+	}
+	action.Revision = getRevision.Result
+
+	switch action.Revision.Kind {
+	case enum.RevisionResponse:
+		if action.Post.Status == enum.PostDuplicate {
+			return validate.Failed("The response of a duplicate post cannot be restored.")
+		}
+	case enum.RevisionComment:
+		getComment := &query.GetCommentByID{CommentID: action.Revision.CommentID}
+		if err := bus.Dispatch(ctx, getComment); err != nil {
+			return validate.Error(err)
+		}
+	}
+
+	return validate.Success()
+}
@@ -16,7 +16,7 @@ type AssignPostToRoadmap struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (a *AssignPostToRoadmap) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsCollaborator()
+	return user != nil && user.HasPermission(entity.PermissionManageRoadmap)
 }
 
 // Validate if current model is valid
@@ -45,7 +45,7 @@ type RemovePostFromRoadmap struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (a *RemovePostFromRoadmap) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsCollaborator()
+	return user != nil && user.HasPermission(entity.PermissionManageRoadmap)
 }
 
 // Validate if current model is valid
@@ -67,7 +67,7 @@ type ReorderPostInRoadmap struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (a *ReorderPostInRoadmap) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsCollaborator()
+	return user != nil && user.HasPermission(entity.PermissionManageRoadmap)
 }
 
 // Validate if current model is valid
@@ -89,6 +89,7 @@ func (a *ReorderPostInRoadmap) Validate(ctx context.Context, user *entity.User)
 type CreateRoadmapColumn struct {
 	Name              string `json:"name"`
 	IsVisibleToPublic bool   `json:"isVisibleToPublic"`
+	GroupID           int    `json:"groupId"`
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
@@ -106,6 +107,10 @@ func (a *CreateRoadmapColumn) Validate(ctx context.Context, user *entity.User) *
 		result.AddFieldFailure("name", "Name must be less than 100 characters")
 	}
 
+	if err := validateGroupID(ctx, result, a.GroupID); err != nil {
+		return validate.Error(err)
+	}
+
 	return result
 }
 
@@ -114,6 +119,7 @@ type UpdateRoadmapColumn struct {
 	ColumnID          int    `json:"columnId"`
 	Name              string `json:"name"`
 	IsVisibleToPublic bool   `json:"isVisibleToPublic"`
+	GroupID           int    `json:"groupId"`
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
@@ -135,6 +141,10 @@ func (a *UpdateRoadmapColumn) Validate(ctx context.Context, user *entity.User) *
 		result.AddFieldFailure("name", "Name must be less than 100 characters")
 	}
 
+	if err := validateGroupID(ctx, result, a.GroupID); err != nil {
+		return validate.Error(err)
+	}
+
 	return result
 }
 
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// SaveSAMLConfig is used to configure SAML single sign-on of current tenant
+type SaveSAMLConfig struct {
+	Status                 int    `json:"status"`
+	DisplayName            string `json:"displayName"`
+	IdPMetadata            string `json:"idpMetadata"`
+	AttributeName          string `json:"attributeName"`
+	AttributeEmail         string `json:"attributeEmail"`
+	AttributeRole          string `json:"attributeRole"`
+	AdministratorRoleValue string `json:"administratorRoleValue"`
+	CollaboratorRoleValue  string `json:"collaboratorRoleValue"`
+	RequireSSO             bool   `json:"requireSSO"`
+	AllowIdPInitiated      bool   `json:"allowIdPInitiated"`
+
+	IdPEntityID string
+	IdPSSOURL   string
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SaveSAMLConfig) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SaveSAMLConfig) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Status != enum.OAuthConfigEnabled &&
+		action.Status != enum.OAuthConfigDisabled {
+		result.AddFieldFailure("status", "Invalid status.")
+	}
+
+	if action.Status == enum.OAuthConfigDisabled {
+		if action.RequireSSO {
+			result.AddFieldFailure("requireSSO", "SSO can only be required while SAML is enabled.")
+		}
+
+		tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+		if !tenant.IsEmailAuthAllowed {
+			activeProviders := &query.ListAllOAuthProviders{}
+			if err := bus.Dispatch(ctx, activeProviders); err != nil {
+				return validate.Failed("Cannot retrieve OAuth providers")
+			}
+
+			hasOtherProvider := false
+			for _, p := range activeProviders.Result {
+				hasOtherProvider = hasOtherProvider || p.IsEnabled
+			}
+			if !hasOtherProvider {
+				result.AddFieldFailure("status", "You cannot disable SAML with neither email auth nor any other provider enabled.")
+			}
+		}
+	}
+
+	if action.DisplayName == "" {
+		result.AddFieldFailure("displayName", "Display Name is required.")
+	} else if len(action.DisplayName) > 50 {
+		result.AddFieldFailure("displayName", "Display Name must have less than 50 characters.")
+	}
+
+	if action.IdPMetadata == "" {
+		result.AddFieldFailure("idpMetadata", "IdP Metadata is required.")
+	} else if len(action.IdPMetadata) > 100000 {
+		result.AddFieldFailure("idpMetadata", "IdP Metadata must have less than 100000 characters.")
+	} else {
+		parseMetadata := &query.ParseSAMLIdPMetadata{Metadata: action.IdPMetadata}
+		if err := bus.Dispatch(ctx, parseMetadata); err != nil {
+			result.AddFieldFailure("idpMetadata", "IdP Metadata must be a valid XML document with an entity ID, an HTTP-Redirect single sign-on service and a signing certificate.")
+		} else {
+			action.IdPEntityID = parseMetadata.Result.EntityID
+			action.IdPSSOURL = parseMetadata.Result.SSOURL
+		}
+	}
+
+	if len(action.AttributeName) > 200 {
+		result.AddFieldFailure("attributeName", "Name Attribute must have less than 200 characters.")
+	}
+
+	if len(action.AttributeEmail) > 200 {
+		result.AddFieldFailure("attributeEmail", "Email Attribute must have less than 200 characters.")
+	}
+
+	if len(action.AttributeRole) > 200 {
+		result.AddFieldFailure("attributeRole", "Role Attribute must have less than 200 characters.")
+	}
+
+	if action.AttributeRole != "" && action.AdministratorRoleValue == "" && action.CollaboratorRoleValue == "" {
+		result.AddFieldFailure("attributeRole", "At least one role value is required when mapping roles.")
+	}
+
+	if len(action.AdministratorRoleValue) > 200 {
+		result.AddFieldFailure("administratorRoleValue", "Administrator Value must have less than 200 characters.")
+	}
+
+	if len(action.CollaboratorRoleValue) > 200 {
+		result.AddFieldFailure("collaboratorRoleValue", "Collaborator Value must have less than 200 characters.")
+	}
+
+	return result
+}
+
+// isSSORequired returns true if current tenant requires its users to sign in with SAML
+func isSSORequired(ctx context.Context) (bool, error) {
+	samlConfig := &query.GetSAMLConfig{}
+	err := bus.Dispatch(ctx, samlConfig)
+	if err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return samlConfig.Result.IsEnabled() && samlConfig.Result.RequireSSO, nil
+}
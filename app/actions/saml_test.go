@@ -0,0 +1,89 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func mockParseSAMLIdPMetadata() {
+	bus.AddHandler(func(ctx context.Context, q *query.ParseSAMLIdPMetadata) error {
+		if q.Metadata != "<EntityDescriptor/>" {
+			return errors.New("invalid metadata")
+		}
+		q.Result = &dto.SAMLIdPMetadata{EntityID: "https://idp.test/metadata", SSOURL: "https://idp.test/sso"}
+		return nil
+	})
+}
+
+func TestSaveSAMLConfig_InvalidInput(t *testing.T) {
+	RegisterT(t)
+	mockParseSAMLIdPMetadata()
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{IsEmailAuthAllowed: true})
+
+	action := &actions.SaveSAMLConfig{}
+	ExpectFailed(action.Validate(ctx, nil), "status", "displayName", "idpMetadata")
+
+	action = &actions.SaveSAMLConfig{
+		Status:         enum.OAuthConfigDisabled,
+		DisplayName:    rand.String(51),
+		IdPMetadata:    "<html/>",
+		AttributeName:  rand.String(201),
+		AttributeEmail: rand.String(201),
+		AttributeRole:  "groups",
+		RequireSSO:     true,
+	}
+	ExpectFailed(action.Validate(ctx, nil), "displayName", "idpMetadata", "attributeName", "attributeEmail", "attributeRole", "requireSSO")
+}
+
+func TestSaveSAMLConfig_ValidInput(t *testing.T) {
+	RegisterT(t)
+	mockParseSAMLIdPMetadata()
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{IsEmailAuthAllowed: true})
+
+	action := &actions.SaveSAMLConfig{
+		Status:                 enum.OAuthConfigEnabled,
+		DisplayName:            "Company SSO",
+		IdPMetadata:            "<EntityDescriptor/>",
+		AttributeEmail:         "mail",
+		AttributeRole:          "groups",
+		AdministratorRoleValue: "fider-admins",
+		RequireSSO:             true,
+	}
+	ExpectSuccess(action.Validate(ctx, nil))
+	Expect(action.IdPEntityID).Equals("https://idp.test/metadata")
+	Expect(action.IdPSSOURL).Equals("https://idp.test/sso")
+}
+
+func TestSaveSAMLConfig_CannotDisableOnlyProvider(t *testing.T) {
+	RegisterT(t)
+	mockParseSAMLIdPMetadata()
+
+	bus.AddHandler(func(ctx context.Context, q *query.ListAllOAuthProviders) error {
+		q.Result = []*dto.OAuthProviderOption{
+			{Provider: app.GoogleProvider, IsEnabled: false},
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{IsEmailAuthAllowed: false})
+
+	action := &actions.SaveSAMLConfig{
+		Status:      enum.OAuthConfigDisabled,
+		DisplayName: "Company SSO",
+		IdPMetadata: "<EntityDescriptor/>",
+	}
+	ExpectFailed(action.Validate(ctx, nil), "status")
+}
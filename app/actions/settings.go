@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"time"
 
 	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
@@ -58,9 +59,16 @@ func (action *UpdateUserSettings) Validate(ctx context.Context, user *entity.Use
 	}
 	result.AddFieldFailure("avatar", messages...)
 
+	// Notifications can only be snoozed through SnoozeNotifications, which limits for how long
 	if action.Settings != nil {
 		for k, v := range action.Settings {
 			ok := false
+			if k == enum.EmailDigestFrequencySettingsKey {
+				ok = true
+				if !enum.IsValidEmailDigestFrequency(v) {
+					result.AddFieldFailure("settings", i18n.T(ctx, "validation.invalidvalue", i18n.Params{"name": k}, i18n.Params{"value": v}))
+				}
+			}
 			for _, e := range enum.AllNotificationEvents {
 				if e.UserSettingsKeyName == k {
 					ok = true
@@ -77,3 +85,35 @@ func (action *UpdateUserSettings) Validate(ctx context.Context, user *entity.Use
 
 	return result
 }
+
+// MaxSnoozeDays is the longest time notifications can be snoozed for
+const MaxSnoozeDays = 90
+
+// SnoozeNotifications pauses all notifications of current user for a number of days, or resumes them when it's zero
+type SnoozeNotifications struct {
+	Days int `json:"days"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SnoozeNotifications) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *SnoozeNotifications) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Days < 0 || action.Days > MaxSnoozeDays {
+		result.AddFieldFailure("days", i18n.T(ctx, "validation.custom.snoozedays", i18n.Params{"max": MaxSnoozeDays}))
+	}
+
+	return result
+}
+
+// Until returns when notifications resume, or an empty string if they're not snoozed
+func (action *SnoozeNotifications) Until() string {
+	if action.Days == 0 {
+		return ""
+	}
+	return time.Now().AddDate(0, 0, action.Days).UTC().Format(time.RFC3339)
+}
@@ -3,11 +3,13 @@ package actions_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/mock"
 )
 
 func TestInvalidUserNames(t *testing.T) {
@@ -51,6 +53,24 @@ func TestInvalidSettings(t *testing.T) {
 		{
 			enum.NotificationEventNewComment.UserSettingsKeyName: "4",
 		},
+		{
+			enum.NotificationEventNewComment.UserSettingsKeyName: "16",
+		},
+		{
+			enum.NotificationEventNewComment.UserSettingsKeyName: "-1",
+		},
+		{
+			enum.EmailDigestFrequencySettingsKey: "monthly",
+		},
+		{
+			enum.NotificationsSnoozedUntilSettingsKey: "tomorrow",
+		},
+		{
+			enum.NotificationsSnoozedUntilSettingsKey: "2099-01-01T00:00:00Z",
+		},
+		{
+			enum.NotificationsSnoozedUntilSettingsKey: "",
+		},
 	} {
 		action := actions.NewUpdateUserSettings()
 		action.Name = "John Snow"
@@ -73,6 +93,12 @@ func TestValidSettings(t *testing.T) {
 		{
 			enum.NotificationEventNewComment.UserSettingsKeyName: enum.NotificationEventNewComment.DefaultSettingValue,
 		},
+		{
+			enum.NotificationEventNewPost.UserSettingsKeyName: "11",
+		},
+		{
+			enum.EmailDigestFrequencySettingsKey: enum.EmailDigestFrequencyWeekly,
+		},
 	} {
 		action := actions.NewUpdateUserSettings()
 		action.Name = "John Snow"
@@ -87,3 +113,23 @@ func TestValidSettings(t *testing.T) {
 		Expect(action.Avatar.BlobKey).Equals("jon.png")
 	}
 }
+
+func TestSnoozeNotifications(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.SnoozeNotifications{Days: -1}
+	ExpectFailed(action.Validate(context.Background(), mock.JonSnow), "days")
+
+	action = &actions.SnoozeNotifications{Days: actions.MaxSnoozeDays + 1}
+	ExpectFailed(action.Validate(context.Background(), mock.JonSnow), "days")
+
+	action = &actions.SnoozeNotifications{Days: 0}
+	ExpectSuccess(action.Validate(context.Background(), mock.JonSnow))
+	Expect(action.Until()).Equals("")
+
+	action = &actions.SnoozeNotifications{Days: 7}
+	ExpectSuccess(action.Validate(context.Background(), mock.JonSnow))
+	until, err := time.Parse(time.RFC3339, action.Until())
+	Expect(err).IsNil()
+	Expect(until).TemporarilySimilar(time.Now().AddDate(0, 0, 7), 5*time.Second)
+}
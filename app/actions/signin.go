@@ -26,8 +26,14 @@ func NewSignInByEmail() *SignInByEmail {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *SignInByEmail) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	ssoRequired, err := isSSORequired(ctx)
+	if err != nil {
+		return false
+	}
+
+	// Administrators can still sign in by email, so that they aren't locked out when other providers fail
 	tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
-	if tenant.IsEmailAuthAllowed {
+	if tenant.IsEmailAuthAllowed && !ssoRequired {
 		return true
 	}
 	getUser := &query.GetUserByEmail{
@@ -22,13 +22,14 @@ type CreateEditTag struct {
 	Name     string `json:"name"`
 	Color    string `json:"color" format:"upper"`
 	IsPublic bool   `json:"isPublic"`
+	GroupID  int    `json:"groupId"`
 
 	Tag *entity.Tag
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *CreateEditTag) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsAdministrator()
+	return user != nil && user.HasPermission(entity.PermissionManageTags)
 }
 
 // Validate if current model is valid
@@ -66,6 +67,10 @@ func (action *CreateEditTag) Validate(ctx context.Context, user *entity.User) *v
 		result.AddFieldFailure("color", "Color is invalid.")
 	}
 
+	if err := validateGroupID(ctx, result, action.GroupID); err != nil {
+		return validate.Error(err)
+	}
+
 	return result
 }
 
@@ -78,7 +83,7 @@ type DeleteTag struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *DeleteTag) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsAdministrator()
+	return user != nil && user.HasPermission(entity.PermissionManageTags)
 }
 
 // Validate if current model is valid
@@ -104,7 +109,7 @@ type AssignUnassignTag struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *AssignUnassignTag) IsAuthorized(ctx context.Context, user *entity.User) bool {
-	return user != nil && user.IsCollaborator()
+	return user != nil && user.HasPermission(entity.PermissionRespond)
 }
 
 // Validate if current model is valid
@@ -119,3 +124,30 @@ func (action *AssignUnassignTag) Validate(ctx context.Context, user *entity.User
 	action.Tag = getSlug.Result
 	return validate.Success()
 }
+
+// FollowUnfollowTag is used to follow or stop following a tag, which notifies about new posts with it
+type FollowUnfollowTag struct {
+	Slug string `route:"slug"`
+
+	Tag *entity.Tag
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *FollowUnfollowTag) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *FollowUnfollowTag) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getSlug := &query.GetTagBySlug{Slug: action.Slug}
+	if err := bus.Dispatch(ctx, getSlug); err != nil {
+		return validate.Error(err)
+	}
+
+	if !getSlug.Result.IsVisibleTo(user) {
+		return validate.Error(app.ErrNotFound)
+	}
+
+	action.Tag = getSlug.Result
+	return validate.Success()
+}
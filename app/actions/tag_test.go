@@ -12,6 +12,7 @@ import (
 
 	"github.com/getfider/fider/app/actions"
 	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/pkg/rand"
 )
 
@@ -78,3 +79,20 @@ func TestCreateEditTag_ValidInput(t *testing.T) {
 	ExpectSuccess(result)
 	Expect(action.Tag).Equals(tag)
 }
+
+func TestFollowUnfollowTag_PrivateTag(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTagBySlug) error {
+		q.Result = &entity.Tag{ID: 2, Slug: "roadmap", Name: "Roadmap", Color: "000000", IsPublic: false}
+		return nil
+	})
+
+	action := &actions.FollowUnfollowTag{Slug: "roadmap"}
+	result := action.Validate(context.Background(), mock.AryaStark)
+	Expect(result.Err).Equals(app.ErrNotFound)
+
+	action = &actions.FollowUnfollowTag{Slug: "roadmap"}
+	ExpectSuccess(action.Validate(context.Background(), mock.JonSnow))
+	Expect(action.Tag.ID).Equals(2)
+}
@@ -210,6 +210,38 @@ func (action *UpdateTenantPrivacySettings) Validate(ctx context.Context, user *e
 	return validate.Success()
 }
 
+// UpdateTenantVotingSettings is the input model used to update how users vote on posts
+type UpdateTenantVotingSettings struct {
+	VotingMode      enum.VotingMode `json:"votingMode"`
+	VoteBudget      int             `json:"voteBudget"`
+	MaxVotesPerPost int             `json:"maxVotesPerPost"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *UpdateTenantVotingSettings) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.Role == enum.RoleAdministrator
+}
+
+// Validate if current model is valid
+func (action *UpdateTenantVotingSettings) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.VotingMode == 0 {
+		result.AddFieldFailure("votingMode", "Voting mode is invalid.")
+	}
+
+	if action.VotingMode == enum.VotingModeBudget {
+		if action.VoteBudget < 1 || action.VoteBudget > 1000 {
+			result.AddFieldFailure("voteBudget", "Vote budget must be between 1 and 1000.")
+		}
+		if action.MaxVotesPerPost < 1 || action.MaxVotesPerPost > action.VoteBudget || action.MaxVotesPerPost > 100 {
+			result.AddFieldFailure("maxVotesPerPost", "Votes per post must be between 1 and 100, and not more than the vote budget.")
+		}
+	}
+
+	return result
+}
+
 // UpdateTenantEmailAuthAllowed is the input model used to update tenant privacy settings
 type UpdateTenantEmailAuthAllowed struct {
 	IsEmailAuthAllowed bool `json:"isEmailAuthAllowed"`
@@ -233,5 +265,15 @@ func (action *UpdateTenantEmailAuthAllowed) Validate(ctx context.Context, user *
 		result.AddFieldFailure("isEmailAuthAllowed", "You cannot disable email authentication without any other provider enabled.")
 	}
 
+	if action.IsEmailAuthAllowed {
+		ssoRequired, err := isSSORequired(ctx)
+		if err != nil {
+			return validate.Error(err)
+		}
+		if ssoRequired {
+			result.AddFieldFailure("isEmailAuthAllowed", "You cannot enable email authentication while SAML single sign-on is required.")
+		}
+	}
+
 	return result
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/getfider/fider/app"
 
 	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
@@ -181,3 +182,27 @@ func TestUpdateTenantSettings_ExistingTenant_WithLogo(t *testing.T) {
 	ExpectSuccess(result)
 	Expect(action.Logo.BlobKey).Equals("hello-world.png")
 }
+
+func TestUpdateTenantEmailAuthAllowed_SSORequired(t *testing.T) {
+	RegisterT(t)
+
+	samlConfig := &entity.SAMLConfig{Status: enum.OAuthConfigEnabled, RequireSSO: true}
+	bus.AddHandler(func(ctx context.Context, q *query.ListActiveOAuthProviders) error {
+		q.Result = []*dto.OAuthProviderOption{{Provider: app.SAMLProvider}}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLConfig) error {
+		q.Result = samlConfig
+		return nil
+	})
+
+	action := &actions.UpdateTenantEmailAuthAllowed{IsEmailAuthAllowed: true}
+	ExpectFailed(action.Validate(context.Background(), nil), "isEmailAuthAllowed")
+
+	action = &actions.UpdateTenantEmailAuthAllowed{IsEmailAuthAllowed: false}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+
+	samlConfig.RequireSSO = false
+	action = &actions.UpdateTenantEmailAuthAllowed{IsEmailAuthAllowed: true}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+}
@@ -0,0 +1,191 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/totp"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// EnableTwoFactor is used to confirm a newly set up authenticator app
+type EnableTwoFactor struct {
+	Code string `json:"code"`
+
+	Step int64
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *EnableTwoFactor) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *EnableTwoFactor) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getTwoFactor := &query.GetUserTwoFactor{UserID: user.ID}
+	err := bus.Dispatch(ctx, getTwoFactor)
+	if err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return validate.Failed("Two-factor authentication has not been set up.")
+		}
+		return validate.Error(err)
+	}
+
+	if getTwoFactor.Result.IsEnabled() {
+		return validate.Failed("Two-factor authentication is already enabled.")
+	}
+
+	step, ok := totp.Validate(getTwoFactor.Result.Secret, action.Code, time.Now())
+	if !ok {
+		result.AddFieldFailure("code", "The code is invalid or has expired.")
+	}
+	action.Step = step
+
+	return result
+}
+
+// VerifyTwoFactorCode is used to check the second factor of a user, either a code from an authenticator app or a recovery code
+type VerifyTwoFactorCode struct {
+	UserID int    `json:"-"`
+	Code   string `json:"code"`
+
+	LockedOut bool
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *VerifyTwoFactorCode) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return action.UserID > 0
+}
+
+// Validate if current model is valid
+func (action *VerifyTwoFactorCode) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	action.Code = strings.TrimSpace(action.Code)
+	if action.Code == "" {
+		result.AddFieldFailure("code", "Code is required.")
+		return result
+	}
+
+	getTwoFactor := &query.GetUserTwoFactor{UserID: action.UserID}
+	err := bus.Dispatch(ctx, getTwoFactor)
+	if err != nil && errors.Cause(err) != app.ErrNotFound {
+		return validate.Error(err)
+	}
+	if err != nil || !getTwoFactor.Result.IsEnabled() {
+		return validate.Failed("Two-factor authentication is not enabled.")
+	}
+
+	// Codes are not even checked while locked out, so that they can't be guessed
+	if getTwoFactor.Result.IsLockedOut(time.Now()) {
+		action.LockedOut = true
+		result.AddFieldFailure("code", twoFactorLockedOut)
+		return result
+	}
+
+	if step, ok := totp.Validate(getTwoFactor.Result.Secret, action.Code, time.Now()); ok {
+		markUsed := &cmd.MarkTwoFactorStepUsed{UserID: action.UserID, Step: step}
+		if err := bus.Dispatch(ctx, markUsed); err != nil {
+			return validate.Error(err)
+		}
+		if !markUsed.Result {
+			return action.failed(ctx, "This code has already been used, wait for the next one.")
+		}
+		return result
+	}
+
+	useRecoveryCode := &cmd.UseRecoveryCode{UserID: action.UserID, Code: action.Code}
+	if err := bus.Dispatch(ctx, useRecoveryCode); err != nil {
+		return validate.Error(err)
+	}
+	if !useRecoveryCode.Result {
+		return action.failed(ctx, "The code is invalid or has expired.")
+	}
+
+	return result
+}
+
+var twoFactorLockedOut = fmt.Sprintf("Too many invalid codes, try again in %d minutes.", int(entity.TwoFactorLockoutDuration.Minutes()))
+
+// failed records an invalid code, which locks the user out once there were too many of them
+func (action *VerifyTwoFactorCode) failed(ctx context.Context, message string) *validate.Result {
+	recordFailure := &cmd.RecordTwoFactorFailure{UserID: action.UserID}
+	if err := bus.Dispatch(ctx, recordFailure); err != nil {
+		return validate.Error(err)
+	}
+
+	result := validate.Success()
+	if recordFailure.Result {
+		action.LockedOut = true
+		message = twoFactorLockedOut
+	}
+	result.AddFieldFailure("code", message)
+	return result
+}
+
+// DisableTwoFactor is used by users to turn off two-factor authentication of their own account
+type DisableTwoFactor struct {
+	Code string `json:"code"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DisableTwoFactor) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *DisableTwoFactor) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	if user.Tenant.IsTwoFactorRequired && user.IsCollaborator() {
+		return validate.Failed("Two-factor authentication is required for staff members of this site.")
+	}
+
+	verify := &VerifyTwoFactorCode{UserID: user.ID, Code: action.Code}
+	return verify.Validate(ctx, user)
+}
+
+// RegenerateRecoveryCodes is used by users to replace the recovery codes of their own account
+type RegenerateRecoveryCodes struct {
+	Code string `json:"code"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *RegenerateRecoveryCodes) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasTwoFactor
+}
+
+// Validate if current model is valid
+func (action *RegenerateRecoveryCodes) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	verify := &VerifyTwoFactorCode{UserID: user.ID, Code: action.Code}
+	return verify.Validate(ctx, user)
+}
+
+// UpdateTenantTwoFactorRequired is the input model used to require two-factor authentication for staff members
+type UpdateTenantTwoFactorRequired struct {
+	IsTwoFactorRequired bool `json:"isTwoFactorRequired"`
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *UpdateTenantTwoFactorRequired) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *UpdateTenantTwoFactorRequired) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.IsTwoFactorRequired && !user.HasTwoFactor {
+		result.AddFieldFailure("isTwoFactorRequired", "You must enable two-factor authentication on your own account first.")
+	}
+
+	return result
+}
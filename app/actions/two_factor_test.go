@@ -0,0 +1,163 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/totp"
+)
+
+const twoFactorSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func currentTwoFactorCode() string {
+	code, _ := totp.Code(twoFactorSecret, totp.Step(time.Now()))
+	return code
+}
+
+func TestEnableTwoFactor_InvalidCode(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserTwoFactor) error {
+		q.Result = &entity.UserTwoFactor{UserID: q.UserID, Secret: twoFactorSecret}
+		return nil
+	})
+
+	user := &entity.User{ID: 1}
+	action := &actions.EnableTwoFactor{Code: "000000"}
+	if action.Code == currentTwoFactorCode() {
+		action.Code = "111111"
+	}
+	ExpectFailed(action.Validate(context.Background(), user), "code")
+
+	action = &actions.EnableTwoFactor{Code: currentTwoFactorCode()}
+	ExpectSuccess(action.Validate(context.Background(), user))
+	Expect(action.Step).Equals(totp.Step(time.Now()))
+}
+
+func TestEnableTwoFactor_NotSetup(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserTwoFactor) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.EnableTwoFactor{Code: "123456"}
+	result := action.Validate(context.Background(), &entity.User{ID: 1})
+	Expect(result.Ok).IsFalse()
+}
+
+func TestVerifyTwoFactorCode_ReplayedCode(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Now()
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserTwoFactor) error {
+		q.Result = &entity.UserTwoFactor{UserID: q.UserID, Secret: twoFactorSecret, EnabledAt: &now}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkTwoFactorStepUsed) error {
+		c.Result = false
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.RecordTwoFactorFailure) error {
+		return nil
+	})
+
+	action := &actions.VerifyTwoFactorCode{UserID: 1, Code: currentTwoFactorCode()}
+	ExpectFailed(action.Validate(context.Background(), nil), "code")
+}
+
+func TestVerifyTwoFactorCode_RecoveryCode(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Now()
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserTwoFactor) error {
+		q.Result = &entity.UserTwoFactor{UserID: q.UserID, Secret: twoFactorSecret, EnabledAt: &now}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.UseRecoveryCode) error {
+		c.Result = c.Code == "abcde-12345"
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.RecordTwoFactorFailure) error {
+		return nil
+	})
+
+	action := &actions.VerifyTwoFactorCode{UserID: 1, Code: " abcde-12345 "}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+
+	action = &actions.VerifyTwoFactorCode{UserID: 1, Code: "abcde-99999"}
+	ExpectFailed(action.Validate(context.Background(), nil), "code")
+}
+
+func TestVerifyTwoFactorCode_LockedOut(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Now()
+	twoFactor := &entity.UserTwoFactor{UserID: 1, Secret: twoFactorSecret, EnabledAt: &now}
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserTwoFactor) error {
+		q.Result = twoFactor
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkTwoFactorStepUsed) error {
+		c.Result = true
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.UseRecoveryCode) error {
+		c.Result = false
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.RecordTwoFactorFailure) error {
+		twoFactor.FailedAttempts++
+		if twoFactor.FailedAttempts >= entity.TwoFactorMaxFailedAttempts {
+			lockedUntil := time.Now().Add(entity.TwoFactorLockoutDuration)
+			twoFactor.FailedAttempts = 0
+			twoFactor.LockedUntil = &lockedUntil
+			c.Result = true
+		}
+		return nil
+	})
+
+	for i := 1; i <= entity.TwoFactorMaxFailedAttempts; i++ {
+		action := &actions.VerifyTwoFactorCode{UserID: 1, Code: "abcde-99999"}
+		ExpectFailed(action.Validate(context.Background(), nil), "code")
+		Expect(action.LockedOut).Equals(i == entity.TwoFactorMaxFailedAttempts)
+	}
+
+	action := &actions.VerifyTwoFactorCode{UserID: 1, Code: currentTwoFactorCode()}
+	ExpectFailed(action.Validate(context.Background(), nil), "code")
+	Expect(action.LockedOut).IsTrue()
+
+	lockedUntil := time.Now().Add(-time.Second)
+	twoFactor.LockedUntil = &lockedUntil
+	action = &actions.VerifyTwoFactorCode{UserID: 1, Code: currentTwoFactorCode()}
+	ExpectSuccess(action.Validate(context.Background(), nil))
+}
+
+func TestDisableTwoFactor_RequiredForStaff(t *testing.T) {
+	RegisterT(t)
+
+	user := &entity.User{ID: 1, Role: enum.RoleCollaborator, Tenant: &entity.Tenant{IsTwoFactorRequired: true}, HasTwoFactor: true}
+	action := &actions.DisableTwoFactor{Code: "123456"}
+	result := action.Validate(context.Background(), user)
+	Expect(result.Ok).IsFalse()
+}
+
+func TestUpdateTenantTwoFactorRequired_RequiresOwnTwoFactor(t *testing.T) {
+	RegisterT(t)
+
+	user := &entity.User{ID: 1, Role: enum.RoleAdministrator}
+	action := &actions.UpdateTenantTwoFactorRequired{IsTwoFactorRequired: true}
+	ExpectFailed(action.Validate(context.Background(), user), "isTwoFactorRequired")
+
+	user.HasTwoFactor = true
+	ExpectSuccess(action.Validate(context.Background(), user))
+}
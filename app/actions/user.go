@@ -162,3 +162,38 @@ func (action *ChangeUserEmail) GetUser() *entity.User {
 func (action *ChangeUserEmail) GetKind() enum.EmailVerificationKind {
 	return enum.EmailVerificationKindChangeEmail
 }
+
+// SupressUserEmail is used by administrators to stop sending emails to a member
+type SupressUserEmail struct {
+	Email string `json:"email" format:"lower"`
+
+	User *entity.User
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SupressUserEmail) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *SupressUserEmail) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.Email == "" {
+		result.AddFieldFailure("email", propertyIsRequired(ctx, "email"))
+		return result
+	}
+
+	userByEmail := &query.GetUserByEmail{Email: action.Email}
+	err := bus.Dispatch(ctx, userByEmail)
+	if errors.Cause(err) == app.ErrNotFound {
+		result.AddFieldFailure("email", "There is no member with this email address.")
+		return result
+	}
+	if err != nil {
+		return validate.Error(err)
+	}
+
+	action.User = userByEmail.Result
+	return result
+}
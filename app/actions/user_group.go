@@ -0,0 +1,172 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// CreateEditUserGroup is used to create a new user group or edit existing
+type CreateEditUserGroup struct {
+	ID           int      `route:"id"`
+	Name         string   `json:"name"`
+	EmailDomains []string `json:"emailDomains"`
+	OAuthClaim   string   `json:"oauthClaim"`
+
+	Group *entity.UserGroup
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *CreateEditUserGroup) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *CreateEditUserGroup) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	if action.ID > 0 {
+		getGroup := &query.GetUserGroupByID{GroupID: action.ID}
+		if err := bus.Dispatch(ctx, getGroup); err != nil {
+			return validate.Error(err)
+		}
+		action.Group = getGroup.Result
+	}
+
+	action.Name = strings.TrimSpace(action.Name)
+	if action.Name == "" {
+		result.AddFieldFailure("name", "Name is required.")
+	} else if len(action.Name) > 50 {
+		result.AddFieldFailure("name", "Name must have less than 50 characters.")
+	} else if action.Group == nil || !strings.EqualFold(action.Group.Name, action.Name) {
+		getDuplicate := &query.GetUserGroupByName{Name: action.Name}
+		err := bus.Dispatch(ctx, getDuplicate)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return validate.Error(err)
+		} else if err == nil {
+			result.AddFieldFailure("name", "This group name is already in use.")
+		}
+	}
+
+	domains := make([]string, 0, len(action.EmailDomains))
+	for _, domain := range action.EmailDomains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain == "" {
+			continue
+		}
+		if strings.ContainsAny(domain, "@ ") || !strings.Contains(domain, ".") {
+			result.AddFieldFailure("emailDomains", "'"+domain+"' is not a valid domain.")
+		}
+		domains = append(domains, domain)
+	}
+	slices.Sort(domains)
+	action.EmailDomains = slices.Compact(domains)
+
+	action.OAuthClaim = strings.TrimSpace(action.OAuthClaim)
+	if len(action.OAuthClaim) > 100 {
+		result.AddFieldFailure("oauthClaim", "OAuth claim must have less than 100 characters.")
+	}
+
+	return result
+}
+
+// DeleteUserGroup is used to delete an existing user group
+type DeleteUserGroup struct {
+	ID int `route:"id"`
+
+	Group *entity.UserGroup
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *DeleteUserGroup) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *DeleteUserGroup) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getGroup := &query.GetUserGroupByID{GroupID: action.ID}
+	if err := bus.Dispatch(ctx, getGroup); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Group = getGroup.Result
+	return validate.Success()
+}
+
+// AddRemoveUserGroupMember is used to manually add or remove a user to/from a group
+type AddRemoveUserGroupMember struct {
+	ID     int `route:"id"`
+	UserID int `route:"userID"`
+
+	Group *entity.UserGroup
+	User  *entity.User
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *AddRemoveUserGroupMember) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsAdministrator()
+}
+
+// Validate if current model is valid
+func (action *AddRemoveUserGroupMember) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	getGroup := &query.GetUserGroupByID{GroupID: action.ID}
+	getUser := &query.GetUserByID{UserID: action.UserID}
+	if err := bus.Dispatch(ctx, getGroup, getUser); err != nil {
+		return validate.Error(err)
+	}
+
+	action.Group = getGroup.Result
+	action.User = getUser.Result
+	return validate.Success()
+}
+
+// SetPostGroup represents the action of a staff member restricting a post to a group
+type SetPostGroup struct {
+	Number  int `route:"number"`
+	GroupID int `json:"groupId"`
+
+	Post *entity.Post
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *SetPostGroup) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.HasPermission(entity.PermissionRespond)
+}
+
+// Validate if current model is valid
+func (action *SetPostGroup) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	if err := validateGroupID(ctx, result, action.GroupID); err != nil {
+		return validate.Error(err)
+	}
+
+	return result
+}
+
+func validateGroupID(ctx context.Context, result *validate.Result, groupID int) error {
+	if groupID == 0 {
+		return nil
+	}
+
+	getGroup := &query.GetUserGroupByID{GroupID: groupID}
+	err := bus.Dispatch(ctx, getGroup)
+	if err != nil && errors.Cause(err) == app.ErrNotFound {
+		result.AddFieldFailure("groupId", "Group not found.")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+func TestCreateEditUserGroup_InvalidName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserGroupByName) error {
+		if q.Name == "Beta Testers" {
+			q.Result = &entity.UserGroup{ID: 1, Name: "Beta Testers"}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	for _, name := range []string{
+		"",
+		"  ",
+		"Beta Testers",
+		rand.String(51),
+	} {
+		action := &actions.CreateEditUserGroup{Name: name}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "name")
+	}
+}
+
+func TestCreateEditUserGroup_NormalizeDomains(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserGroupByName) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.CreateEditUserGroup{
+		Name:         "Enterprise",
+		EmailDomains: []string{" ACME.com", "@acme.com", "", "globex.com"},
+	}
+	result := action.Validate(context.Background(), nil)
+	ExpectSuccess(result)
+	Expect(action.EmailDomains).Equals([]string{"acme.com", "globex.com"})
+}
+
+func TestCreateEditUserGroup_InvalidDomain(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserGroupByName) error {
+		return app.ErrNotFound
+	})
+
+	for _, domain := range []string{"localhost", "jon@acme.com", "acme .com"} {
+		action := &actions.CreateEditUserGroup{Name: "Enterprise", EmailDomains: []string{domain}}
+		result := action.Validate(context.Background(), nil)
+		ExpectFailed(result, "emailDomains")
+	}
+}
+
+func TestCreateEditUserGroup_OnlyAdministrators(t *testing.T) {
+	RegisterT(t)
+
+	action := &actions.CreateEditUserGroup{}
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleAdministrator})).IsTrue()
+	Expect(action.IsAuthorized(context.Background(), &entity.User{Role: enum.RoleCollaborator})).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), nil)).IsFalse()
+}
+
+func TestSetPostGroup_UnknownGroup(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: q.Number}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserGroupByID) error {
+		return app.ErrNotFound
+	})
+
+	action := &actions.SetPostGroup{Number: 1, GroupID: 9}
+	result := action.Validate(context.Background(), mock.JonSnow)
+	ExpectFailed(result, "groupId")
+
+	action = &actions.SetPostGroup{Number: 1, GroupID: 0}
+	result = action.Validate(context.Background(), mock.JonSnow)
+	ExpectSuccess(result)
+}
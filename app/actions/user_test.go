@@ -189,3 +189,31 @@ func TestChangeUserRole_CurrentUser(t *testing.T) {
 	result := action.Validate(context.Background(), currentUser)
 	ExpectFailed(result, "userID")
 }
+
+func TestSupressUserEmail(t *testing.T) {
+	RegisterT(t)
+
+	member := &entity.User{ID: 2, Email: "arya.stark@got.com"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		if q.Email == member.Email {
+			q.Result = member
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	admin := &entity.User{ID: 1, Role: enum.RoleAdministrator}
+	collaborator := &entity.User{ID: 3, Role: enum.RoleCollaborator}
+
+	action := &actions.SupressUserEmail{Email: "arya.stark@got.com"}
+	Expect(action.IsAuthorized(context.Background(), collaborator)).IsFalse()
+	Expect(action.IsAuthorized(context.Background(), admin)).IsTrue()
+	ExpectSuccess(action.Validate(context.Background(), admin))
+	Expect(action.User).Equals(member)
+
+	action = &actions.SupressUserEmail{Email: "jon.snow@got.com"}
+	ExpectFailed(action.Validate(context.Background(), admin), "email")
+
+	action = &actions.SupressUserEmail{}
+	ExpectFailed(action.Validate(context.Background(), admin), "email")
+}
@@ -0,0 +1,166 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
+)
+
+// AddVote is used to vote on a post, optionally with a weight when the site uses budget or importance voting
+type AddVote struct {
+	Number int `route:"number"`
+	Weight int `json:"weight"`
+
+	Post *entity.Post
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *AddVote) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil
+}
+
+// Validate if current model is valid
+func (action *AddVote) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	if action.Weight == 0 {
+		action.Weight = 1
+	}
+
+	tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+	maxWeight := tenant.MaxVoteWeight()
+	if action.Weight < 1 || action.Weight > maxWeight {
+		result.AddFieldFailure("weight", i18n.T(ctx, "validation.custom.voteweight", i18n.Params{"max": maxWeight}))
+		return result
+	}
+
+	if tenant.VotingMode == enum.VotingModeBudget {
+		getBudget := &query.GetVoteBudget{ExcludePostID: action.Post.ID}
+		if err := bus.Dispatch(ctx, getBudget); err != nil {
+			return validate.Error(err)
+		}
+
+		if action.Weight > getBudget.Result.Remaining {
+			result.AddFieldFailure("weight", i18n.T(ctx, "validation.custom.votebudgetexceeded", i18n.Params{"remaining": getBudget.Result.Remaining}))
+		}
+	}
+
+	return result
+}
+
+// AddProxyVote is used by staff to vote on behalf of an existing user or of a contact identified by email
+type AddProxyVote struct {
+	Number  int    `route:"number"`
+	UserID  int    `json:"userID"`
+	Name    string `json:"name"`
+	Email   string `json:"email" format:"lower"`
+	Company string `json:"company"`
+	Note    string `json:"note"`
+	Weight  int    `json:"weight"`
+
+	Post *entity.Post
+	User *entity.User
+}
+
+// IsAuthorized returns true if current user is authorized to perform this action
+func (action *AddProxyVote) IsAuthorized(ctx context.Context, user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
+
+// Validate if current model is valid
+func (action *AddProxyVote) Validate(ctx context.Context, user *entity.User) *validate.Result {
+	result := validate.Success()
+
+	getPost := &query.GetPostByNumber{Number: action.Number}
+	if err := bus.Dispatch(ctx, getPost); err != nil {
+		return validate.Error(err)
+	}
+	action.Post = getPost.Result
+
+	if !action.Post.CanBeVoted() {
+		result.AddFieldFailure("", "Votes can no longer be added to this post.")
+	}
+
+	if action.UserID > 0 {
+		getUser := &query.GetUserByID{UserID: action.UserID}
+		if err := bus.Dispatch(ctx, getUser); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				result.AddFieldFailure("userID", "User not found.")
+			} else {
+				return validate.Error(err)
+			}
+		} else if getUser.Result.Tenant.ID != user.Tenant.ID {
+			result.AddFieldFailure("userID", "User not found.")
+		} else {
+			action.User = getUser.Result
+		}
+	} else if action.Email == "" {
+		result.AddFieldFailure("email", "Either a user or an email is required.")
+	} else {
+		messages := validate.Email(ctx, action.Email)
+		if len(messages) > 0 {
+			result.AddFieldFailure("email", messages...)
+		} else {
+			getUser := &query.GetUserByEmail{Email: action.Email}
+			err := bus.Dispatch(ctx, getUser)
+			if err != nil && errors.Cause(err) != app.ErrNotFound {
+				return validate.Error(err)
+			}
+			action.User = getUser.Result
+		}
+
+		if action.Name == "" {
+			action.Name = strings.Split(action.Email, "@")[0]
+		} else if len(action.Name) > 100 {
+			result.AddFieldFailure("name", "Name must have less than 100 characters.")
+		}
+	}
+
+	if action.User != nil && action.User.ID == user.ID {
+		result.AddFieldFailure("userID", "Use the vote button to vote for yourself.")
+	}
+
+	if len(action.Company) > 100 {
+		result.AddFieldFailure("company", "Company must have less than 100 characters.")
+	}
+
+	if len(action.Note) > 500 {
+		result.AddFieldFailure("note", "Note must have less than 500 characters.")
+	}
+
+	tenant := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+	if maxWeight := tenant.MaxVoteWeight(); action.Weight < 0 || action.Weight > maxWeight {
+		result.AddFieldFailure("weight", fmt.Sprintf("Weight must be between 1 and %d.", maxWeight))
+	} else if tenant.VotingMode == enum.VotingModeBudget {
+		weight := max(action.Weight, 1)
+		remaining := tenant.VoteBudget
+		if action.User != nil {
+			getBudget := &query.GetVoteBudget{UserID: action.User.ID, ExcludePostID: action.Post.ID}
+			if err := bus.Dispatch(ctx, getBudget); err != nil {
+				return validate.Error(err)
+			}
+			remaining = getBudget.Result.Remaining
+		}
+
+		if weight > remaining {
+			result.AddFieldFailure("weight", fmt.Sprintf("This user only has %d votes left to give.", remaining))
+		}
+	}
+
+	return result
+}
@@ -17,11 +17,12 @@ type CreateEditWebhook struct {
 	Content     string             `json:"content"`
 	HttpMethod  string             `json:"http_method"`
 	HttpHeaders entity.HttpHeaders `json:"http_headers"`
+	IsInternal  bool               `json:"is_internal"`
 }
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *CreateEditWebhook) IsAuthorized(_ context.Context, user *entity.User) bool {
-	return user != nil && user.IsAdministrator()
+	return user != nil && user.HasPermission(entity.PermissionManageWebhooks)
 }
 
 // Validate if current model is valid
@@ -117,7 +118,7 @@ type PreviewWebhook struct {
 
 // IsAuthorized returns true if current user is authorized to perform this action
 func (action *PreviewWebhook) IsAuthorized(_ context.Context, user *entity.User) bool {
-	return user != nil && user.IsAdministrator()
+	return user != nil && user.HasPermission(entity.PermissionManageWebhooks)
 }
 
 // Validate if current model is valid
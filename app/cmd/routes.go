@@ -8,8 +8,10 @@ import (
 	"github.com/getfider/fider/app/handlers/apiv1"
 	"github.com/getfider/fider/app/handlers/webhooks"
 	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
 	"github.com/getfider/fider/app/pkg/web"
 )
 
@@ -60,6 +62,7 @@ func routes(r *web.Engine) *web.Engine {
 	r.Use(middlewares.Session())
 
 	r.Get("/robots.txt", handlers.RobotsTXT())
+	r.Get("/service-worker.js", handlers.ServiceWorker())
 	r.Post("/_api/log-error", handlers.LogError())
 
 	r.Use(middlewares.Maintenance())
@@ -77,6 +80,68 @@ func routes(r *web.Engine) *web.Engine {
 		}
 	}
 
+	if inbound.IsEnabled() && env.Config.Email.Mailgun.WebhookSigningKey != "" {
+		r.Post("/webhooks/mailgun/inbound", webhooks.IncomingMailgunEmail())
+	}
+
+	if env.Config.Email.AWSSES.SNSTopicARN != "" {
+		r.Post("/webhooks/ses", webhooks.IncomingSESNotification())
+	}
+
+	if env.Config.Email.BounceWebhookSecret != "" {
+		r.Post("/webhooks/bounce", webhooks.IncomingBounceWebhook())
+	}
+
+	// OAuth clients post form-encoded bodies and authenticate themselves, so CSRF protection doesn't apply
+	oauth2 := r.Group()
+	{
+		oauth2.Use(middlewares.RequireTenant())
+		oauth2.Use(middlewares.BlockPendingTenants())
+		oauth2.Use(middlewares.CORS())
+		oauth2.Get("/.well-known/openid-configuration", handlers.OpenIDConfiguration())
+		oauth2.Get("/oauth2/jwks", handlers.OAuthJWKS())
+		oauth2.Post("/oauth2/token", handlers.ExchangeOAuthToken())
+	}
+
+	// Identity providers post the SAML response from their own domain, so CSRF protection doesn't apply
+	saml := r.Group()
+	{
+		saml.Use(middlewares.RequireTenant())
+		saml.Use(middlewares.BlockPendingTenants())
+		saml.Post("/saml/acs", handlers.SAMLAssertionConsumer())
+	}
+
+	// Identity providers provision users with a bearer token, so CSRF protection doesn't apply
+	scim := r.Group()
+	{
+		scim.Use(middlewares.RequireTenant())
+		scim.Use(middlewares.BlockPendingTenants())
+		scim.Use(middlewares.SetLocale("en"))
+		scim.Use(middlewares.IsSCIMClient())
+
+		scim.Get("/scim/v2/ServiceProviderConfig", handlers.SCIMServiceProviderConfig())
+		scim.Get("/scim/v2/ResourceTypes", handlers.SCIMResourceTypes())
+		scim.Get("/scim/v2/Users", handlers.SCIMListUsers())
+		scim.Post("/scim/v2/Users", handlers.SCIMCreateUser())
+		scim.Get("/scim/v2/Users/:id", handlers.SCIMGetUser())
+		scim.Put("/scim/v2/Users/:id", handlers.SCIMReplaceUser())
+		scim.Patch("/scim/v2/Users/:id", handlers.SCIMPatchUser())
+		scim.Delete("/scim/v2/Users/:id", handlers.SCIMDeleteUser())
+		scim.Get("/scim/v2/Groups", handlers.SCIMListGroups())
+		scim.Post("/scim/v2/Groups", handlers.SCIMCreateGroup())
+		scim.Get("/scim/v2/Groups/:id", handlers.SCIMGetGroup())
+		scim.Put("/scim/v2/Groups/:id", handlers.SCIMReplaceGroup())
+		scim.Patch("/scim/v2/Groups/:id", handlers.SCIMPatchGroup())
+	}
+
+	// Mailbox providers post one-click unsubscribe requests on their own (RFC 8058), so CSRF protection doesn't apply
+	unsubscribe := r.Group()
+	{
+		unsubscribe.Use(middlewares.RequireTenant())
+		unsubscribe.Use(middlewares.BlockPendingTenants())
+		unsubscribe.Post("/unsubscribe/:token", handlers.Unsubscribe())
+	}
+
 	r.Use(middlewares.CSRF())
 
 	r.Get("/terms", handlers.LegalPage("Terms of Service", "terms.md"))
@@ -118,16 +183,24 @@ func routes(r *web.Engine) *web.Engine {
 	r.Get("/signin", handlers.SignInPage())
 	r.Get("/loginemailsent", handlers.LoginEmailSentPage())
 	r.Get("/not-invited", handlers.NotInvitedPage())
+	r.Get("/unsubscribe/:token", handlers.UnsubscribePage())
 	r.Get("/signin/verify", handlers.VerifySignInKey(enum.EmailVerificationKindSignIn))
 	r.Get("/invite/verify", handlers.VerifySignInKey(enum.EmailVerificationKindUserInvitation))
+	r.Get("/inbound/confirm", handlers.ConfirmEmailPost())
 	r.Post("/_api/signin/complete", handlers.CompleteSignInProfile())
 	r.Post("/_api/signin", handlers.SignInByEmail())
+	r.Get("/oauth2/authorize", handlers.OAuthAuthorizePage())
+	r.Get("/saml/login", handlers.SignInBySAML())
+	r.Get("/saml/metadata", handlers.SAMLMetadata())
+	r.Get("/signin/2fa", handlers.TwoFactorPage())
+	r.Post("/_api/signin/2fa", handlers.VerifyTwoFactor())
 
 	// Block if it's private tenant with unauthenticated user
 	r.Use(middlewares.CheckTenantPrivacy())
 
 	r.Get("/", handlers.Index())
 	r.Get("/roadmap", handlers.RoadmapPage())
+	r.Get("/_api/events", handlers.EventStream())
 	r.Get("/posts/:number", handlers.PostDetails())
 	r.Get("/posts/:number/:slug", handlers.PostDetails())
 
@@ -144,8 +217,24 @@ func routes(r *web.Engine) *web.Engine {
 
 		ui.Delete("/_api/user", handlers.DeleteUser())
 		ui.Post("/_api/user/regenerate-apikey", handlers.RegenerateAPIKey())
+		ui.Get("/_api/user/api-tokens", handlers.ListAPITokens())
+		ui.Post("/_api/user/api-tokens", handlers.CreateAPIToken())
+		ui.Delete("/_api/user/api-tokens/:id", handlers.RevokeAPIToken())
 		ui.Post("/_api/user/settings", handlers.UpdateUserSettings())
+		ui.Post("/_api/oauth2/authorize", handlers.AuthorizeOAuthClient())
 		ui.Post("/_api/user/change-email", handlers.ChangeUserEmail())
+		ui.Post("/_api/user/2fa/setup", handlers.SetupTwoFactor())
+		ui.Post("/_api/user/2fa/enable", handlers.EnableTwoFactor())
+		ui.Post("/_api/user/2fa/disable", handlers.DisableTwoFactor())
+		ui.Post("/_api/user/2fa/recovery-codes", handlers.RegenerateRecoveryCodes())
+		ui.Get("/_api/user/sessions", handlers.ListUserSessions())
+		ui.Delete("/_api/user/sessions", handlers.SignOutEverywhere())
+		ui.Delete("/_api/user/sessions/:id", handlers.RevokeUserSession())
+		ui.Post("/_api/user/push-subscriptions", handlers.SavePushSubscription())
+		ui.Delete("/_api/user/push-subscriptions", handlers.DeletePushSubscription())
+		ui.Post("/_api/user/snooze", handlers.SnoozeNotifications())
+		ui.Post("/_api/user/followed-tags/:slug", handlers.FollowTag())
+		ui.Delete("/_api/user/followed-tags/:slug", handlers.UnfollowTag())
 		ui.Post("/_api/notifications/read-all", handlers.ReadAllNotifications())
 		ui.Get("/_api/notifications/unread/total", handlers.TotalUnreadNotifications())
 
@@ -159,39 +248,83 @@ func routes(r *web.Engine) *web.Engine {
 		ui.Get("/admin", handlers.GeneralSettingsPage())
 		ui.Get("/admin/advanced", handlers.AdvancedSettingsPage())
 		ui.Get("/admin/privacy", handlers.Page("Privacy · Site Settings", "", "Administration/pages/PrivacySettings.page"))
+		ui.Get("/admin/voting", handlers.Page("Voting · Site Settings", "", "Administration/pages/VotingSettings.page"))
 		ui.Get("/admin/invitations", handlers.Page("Invitations · Site Settings", "", "Administration/pages/Invitations.page"))
 		ui.Get("/admin/members", handlers.ManageMembers())
 		ui.Get("/admin/tags", handlers.ManageTags())
+		ui.Get("/admin/custom-fields", handlers.ManageCustomFields())
+		ui.Get("/admin/companies", handlers.ManageCompanies())
 		ui.Get("/admin/authentication", handlers.ManageAuthentication())
 		ui.Get("/admin/roadmap", handlers.ManageRoadmapSettings())
 		ui.Get("/_api/admin/oauth/:provider", handlers.GetOAuthConfig())
 
+		export := ui.Group()
+		{
+			export.Use(middlewares.HasPermission(entity.PermissionExportData))
+			export.Use(middlewares.IsRecentlyReauthenticated())
+
+			export.Get("/admin/export", handlers.Page("Export · Site Settings", "", "Administration/pages/Export.page"))
+			export.Get("/admin/export/posts.csv", handlers.ExportPostsToCSV())
+		}
+
+		webhookSettings := ui.Group()
+		{
+			webhookSettings.Use(middlewares.HasPermission(entity.PermissionManageWebhooks))
+
+			webhookSettings.Get("/admin/webhooks", handlers.ManageWebhooks())
+			webhookSettings.Get("/_api/admin/webhook/test/:id", handlers.TestWebhook())
+			webhookSettings.Post("/_api/admin/webhook/preview", handlers.PreviewWebhook())
+			webhookSettings.Get("/_api/admin/webhook/props/:type", handlers.GetWebhookProps())
+
+			webhookSettings.Use(middlewares.IsRecentlyReauthenticated())
+			webhookSettings.Post("/_api/admin/webhook", handlers.CreateWebhook())
+			webhookSettings.Put("/_api/admin/webhook/:id", handlers.UpdateWebhook())
+			webhookSettings.Delete("/_api/admin/webhook/:id", handlers.DeleteWebhook())
+		}
+
 		// From this step, only Administrators are allowed
 		ui.Use(middlewares.IsAuthorized(enum.RoleAdministrator))
 
-		ui.Get("/admin/export", handlers.Page("Export · Site Settings", "", "Administration/pages/Export.page"))
-		ui.Get("/admin/export/posts.csv", handlers.ExportPostsToCSV())
-		ui.Get("/admin/export/backup.zip", handlers.ExportBackupZip())
-		ui.Get("/admin/webhooks", handlers.ManageWebhooks())
-		ui.Post("/_api/admin/webhook", handlers.CreateWebhook())
-		ui.Put("/_api/admin/webhook/:id", handlers.UpdateWebhook())
-		ui.Delete("/_api/admin/webhook/:id", handlers.DeleteWebhook())
-		ui.Get("/_api/admin/webhook/test/:id", handlers.TestWebhook())
-		ui.Post("/_api/admin/webhook/preview", handlers.PreviewWebhook())
-		ui.Get("/_api/admin/webhook/props/:type", handlers.GetWebhookProps())
+		ui.Get("/admin/roles", handlers.ManageCustomRoles())
+		ui.Get("/admin/groups", handlers.ManageUserGroups())
+		ui.Get("/admin/api-tokens", handlers.ManageAPITokens())
+		ui.Get("/admin/oauth-apps", handlers.ManageOAuthClients())
+		ui.Get("/admin/audit-log", handlers.ManageAuditLog())
+		ui.Get("/admin/email-suppressions", handlers.ManageEmailSupressions())
+		ui.Post("/_api/admin/email-suppressions", handlers.SupressUserEmail())
+		ui.Delete("/_api/admin/users/:userID/email-suppression", handlers.UnsupressUserEmail())
+		ui.Get("/admin/email-templates", handlers.ManageEmailTemplates())
+		ui.Post("/_api/admin/email-templates", handlers.SaveEmailTemplate())
+		ui.Post("/_api/admin/email-templates/preview", handlers.PreviewEmailTemplate())
+		ui.Delete("/_api/admin/email-templates/:name/:locale", handlers.DeleteEmailTemplate())
+		ui.Get("/admin/email-deliveries", handlers.ManageEmailDeliveries())
+		ui.Get("/_api/admin/email-deliveries", handlers.SearchEmailDeliveries())
+		ui.Post("/_api/admin/oauth-apps", handlers.CreateOAuthClient())
+		ui.Delete("/_api/admin/oauth-apps/:id", handlers.RevokeOAuthClient())
+		ui.Get("/admin/saml", handlers.ManageSAMLConfig())
+		ui.Post("/_api/admin/saml", handlers.SaveSAMLConfig())
 		ui.Post("/_api/admin/settings/general", handlers.UpdateSettings())
 		ui.Post("/_api/admin/settings/advanced", handlers.UpdateAdvancedSettings())
 		ui.Post("/_api/admin/settings/privacy", handlers.UpdatePrivacySettings())
+		ui.Post("/_api/admin/settings/voting", handlers.UpdateVotingSettings())
 		ui.Post("/_api/admin/settings/emailauth", handlers.UpdateEmailAuthAllowed())
+		ui.Post("/_api/admin/settings/twofactor", handlers.UpdateTwoFactorRequired())
 		ui.Post("/_api/admin/oauth", handlers.SaveOAuthConfig())
-		ui.Post("/_api/admin/roles/:role/users", handlers.ChangeUserRole())
 		ui.Put("/_api/admin/users/:userID/block", handlers.BlockUser())
 		ui.Delete("/_api/admin/users/:userID/block", handlers.UnblockUser())
+		ui.Delete("/_api/admin/users/:userID/sessions", handlers.RevokeSessionsOfUser())
 
 		if env.IsBillingEnabled() {
 			ui.Get("/admin/billing", handlers.ManageBilling())
 			ui.Post("/_api/billing/checkout-link", handlers.GenerateCheckoutLink())
 		}
+
+		// From this step, users with two-factor enabled have to confirm it again
+		ui.Use(middlewares.IsRecentlyReauthenticated())
+
+		ui.Get("/admin/export/backup.zip", handlers.ExportBackupZip())
+		ui.Post("/_api/admin/roles/:role/users", handlers.ChangeUserRole())
+		ui.Delete("/_api/admin/sessions", handlers.RevokeAllSessions())
 	}
 
 	// Public API
@@ -200,11 +333,13 @@ func routes(r *web.Engine) *web.Engine {
 		publicApi.Get("/api/v1/similarposts", apiv1.FindSimilarPosts())
 		publicApi.Get("/api/v1/posts", apiv1.SearchPosts())
 		publicApi.Get("/api/v1/tags", apiv1.ListTags())
+		publicApi.Get("/api/v1/custom-fields", apiv1.ListCustomFields())
 		publicApi.Get("/api/v1/posts/:number", apiv1.GetPost())
 		publicApi.Get("/api/v1/posts/:number/comments", apiv1.ListComments())
 		publicApi.Get("/api/v1/posts/:number/comments/:id", apiv1.GetComment())
 		publicApi.Get("/api/v1/taggable-users", apiv1.ListTaggableUsers())
 		publicApi.Get("/api/v1/roadmap", apiv1.GetRoadmap())
+		publicApi.Get("/api/v1/userinfo", handlers.OAuthUserInfo())
 	}
 
 	// Operations used to manage the content of a site
@@ -223,11 +358,18 @@ func routes(r *web.Engine) *web.Engine {
 		membersApi.Post("/api/v1/posts/:number/votes", apiv1.AddVote())
 		membersApi.Delete("/api/v1/posts/:number/votes", apiv1.RemoveVote())
 		membersApi.Post("/api/v1/posts/:number/votes/toggle", apiv1.ToggleVote())
+		membersApi.Get("/api/v1/votes/budget", apiv1.GetVoteBudget())
 		membersApi.Post("/api/v1/posts/:number/subscription", apiv1.Subscribe())
 		membersApi.Delete("/api/v1/posts/:number/subscription", apiv1.Unsubscribe())
+		membersApi.Post("/api/v1/posts/:number/subscription/mute", apiv1.MuteComments())
+		membersApi.Delete("/api/v1/posts/:number/subscription/mute", apiv1.UnmuteComments())
 
 		membersApi.Use(middlewares.IsAuthorized(enum.RoleCollaborator, enum.RoleAdministrator))
 		membersApi.Put("/api/v1/posts/:number/status", apiv1.SetResponse())
+		membersApi.Put("/api/v1/posts/:number/privacy", apiv1.SetPostPrivacy())
+		membersApi.Put("/api/v1/posts/:number/group", apiv1.SetPostGroup())
+		membersApi.Post("/api/v1/posts/:number/merge", apiv1.MergePost())
+		membersApi.Delete("/api/v1/posts/:number/merge", apiv1.RevertPostMerge())
 		membersApi.Post("/api/v1/roadmap/posts/:number/assign", apiv1.AssignPostToColumn())
 		membersApi.Put("/api/v1/roadmap/posts/:number/position", apiv1.ReorderPostInColumn())
 		membersApi.Delete("/api/v1/roadmap/posts/:number/assign", apiv1.RemovePostFromRoadmap())
@@ -243,12 +385,22 @@ func routes(r *web.Engine) *web.Engine {
 
 		staffApi.Get("/api/v1/users", apiv1.ListUsers())
 		staffApi.Get("/api/v1/posts/:number/votes", apiv1.ListVotes())
+		staffApi.Get("/api/v1/companies", apiv1.ListCompanies())
+		staffApi.Get("/api/v1/posts/:number/revisions", apiv1.ListRevisions())
+		staffApi.Get("/api/v1/posts/:number/revisions/:id/diff", apiv1.GetRevisionDiff())
 		staffApi.Post("/api/v1/invitations/send", apiv1.SendInvites())
 		staffApi.Post("/api/v1/invitations/sample", apiv1.SendSampleInvite())
+		staffApi.Post("/api/v1/tags", apiv1.CreateEditTag())
+		staffApi.Put("/api/v1/tags/:slug", apiv1.CreateEditTag())
+		staffApi.Delete("/api/v1/tags/:slug", apiv1.DeleteTag())
 
 		staffApi.Use(middlewares.BlockLockedTenants())
 		staffApi.Post("/api/v1/posts/:number/tags/:slug", apiv1.AssignTag())
 		staffApi.Delete("/api/v1/posts/:number/tags/:slug", apiv1.UnassignTag())
+		staffApi.Put("/api/v1/posts/:number/custom-fields", apiv1.SetPostCustomFields())
+		staffApi.Post("/api/v1/posts/:number/revisions/:id/restore", apiv1.RestoreRevision())
+		staffApi.Post("/api/v1/posts/:number/votes/proxy", apiv1.AddProxyVote())
+		staffApi.Put("/api/v1/users/:userID/company", apiv1.SetUserCompany())
 	}
 
 	// Operations used to manage a site
@@ -260,9 +412,25 @@ func routes(r *web.Engine) *web.Engine {
 		adminApi.Use(middlewares.IsAuthorized(enum.RoleAdministrator))
 
 		adminApi.Post("/api/v1/users", apiv1.CreateUser())
-		adminApi.Post("/api/v1/tags", apiv1.CreateEditTag())
-		adminApi.Put("/api/v1/tags/:slug", apiv1.CreateEditTag())
-		adminApi.Delete("/api/v1/tags/:slug", apiv1.DeleteTag())
+		adminApi.Post("/api/v1/custom-fields", apiv1.CreateEditCustomField())
+		adminApi.Put("/api/v1/custom-fields/:key", apiv1.CreateEditCustomField())
+		adminApi.Delete("/api/v1/custom-fields/:key", apiv1.DeleteCustomField())
+		adminApi.Post("/api/v1/companies", apiv1.CreateEditCompany())
+		adminApi.Put("/api/v1/companies/:id", apiv1.CreateEditCompany())
+		adminApi.Delete("/api/v1/companies/:id", apiv1.DeleteCompany())
+		adminApi.Get("/api/v1/roles", apiv1.ListCustomRoles())
+		adminApi.Post("/api/v1/roles", apiv1.CreateEditCustomRole())
+		adminApi.Put("/api/v1/roles/:id", apiv1.CreateEditCustomRole())
+		adminApi.Delete("/api/v1/roles/:id", apiv1.DeleteCustomRole())
+		adminApi.Put("/api/v1/users/:userID/custom-role", middlewares.IsRecentlyReauthenticated()(apiv1.SetUserCustomRole()))
+		adminApi.Get("/api/v1/groups", apiv1.ListUserGroups())
+		adminApi.Post("/api/v1/groups", apiv1.CreateEditUserGroup())
+		adminApi.Put("/api/v1/groups/:id", apiv1.CreateEditUserGroup())
+		adminApi.Delete("/api/v1/groups/:id", apiv1.DeleteUserGroup())
+		adminApi.Get("/api/v1/groups/:id/members", apiv1.ListUserGroupMembers())
+		adminApi.Get("/api/v1/audit-log", apiv1.ListAuditLogs())
+		adminApi.Post("/api/v1/groups/:id/members/:userID", apiv1.AddUserGroupMember())
+		adminApi.Delete("/api/v1/groups/:id/members/:userID", apiv1.RemoveUserGroupMember())
 
 		adminApi.Use(middlewares.BlockLockedTenants())
 		adminApi.Delete("/api/v1/posts/:number", apiv1.DeletePost())
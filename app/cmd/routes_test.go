@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/getfider/fider/app/middlewares"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/web"
 )
@@ -13,3 +15,18 @@ func TestGetMainEngine(t *testing.T) {
 	r := routes(web.New())
 	Expect(r).IsNotNil()
 }
+
+func TestAPIRoutes_HaveExplicitScope(t *testing.T) {
+	RegisterT(t)
+
+	r := routes(web.New())
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		_, mapped := middlewares.RequiredAPIScope(route.Method, route.Path)
+		if !mapped {
+			t.Errorf("%s %s has no API scope", route.Method, route.Path)
+		}
+	}
+}
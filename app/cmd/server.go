@@ -13,8 +13,12 @@ import (
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
 	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/inbound"
 	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/realtime"
 	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+	"github.com/getfider/fider/app/tasks"
 	"github.com/robfig/cron"
 
 	_ "github.com/getfider/fider/app/services/billing/paddle"
@@ -29,9 +33,11 @@ import (
 	_ "github.com/getfider/fider/app/services/log/file"
 	_ "github.com/getfider/fider/app/services/log/sql"
 	_ "github.com/getfider/fider/app/services/oauth"
+	_ "github.com/getfider/fider/app/services/saml"
 	_ "github.com/getfider/fider/app/services/sqlstore/postgres"
 	_ "github.com/getfider/fider/app/services/userlist"
 	_ "github.com/getfider/fider/app/services/webhook"
+	_ "github.com/getfider/fider/app/services/webpush"
 )
 
 // RunServer starts the Fider Server
@@ -51,14 +57,44 @@ func RunServer() int {
 
 	e := routes(web.New())
 	go e.Start(":" + env.Config.Port)
+	startInboundSMTP(ctx, e.Worker())
+	startRealtimeListener(ctx)
 	return listenSignals(e)
 }
 
+// Starts the SMTP listener that receives replies to notification emails and new posts
+func startInboundSMTP(ctx context.Context, w worker.Worker) {
+	address := env.Config.Email.Inbound.SMTPAddress
+	if !inbound.IsEnabled() || address == "" {
+		return
+	}
+
+	go func() {
+		err := inbound.ListenAndServe(ctx, address, func(msg *inbound.Message) {
+			w.Enqueue(tasks.ReceiveEmail(msg))
+		})
+		if err != nil {
+			log.Error(ctx, err)
+		}
+	}()
+}
+
+// Starts the listener that shares real-time events between all instances
+func startRealtimeListener(ctx context.Context) {
+	go func() {
+		if err := realtime.Listen(ctx); err != nil {
+			log.Error(ctx, err)
+		}
+	}()
+}
+
 // Starts all scheduled jobs
 func startJobs(ctx context.Context) {
 	c := cron.New()
 	_ = c.AddJob(jobs.NewJob(ctx, "PurgeExpiredNotificationsJob", jobs.PurgeExpiredNotificationsJobHandler{}))
 	_ = c.AddJob(jobs.NewJob(ctx, "EmailSupressionJob", jobs.EmailSupressionJobHandler{}))
+	_ = c.AddJob(jobs.NewJob(ctx, "EmailDigestJob", jobs.EmailDigestJobHandler{}))
+	_ = c.AddJob(jobs.NewJob(ctx, "EmailQueueJob", jobs.EmailQueueJobHandler{}))
 
 	if env.IsBillingEnabled() {
 		_ = c.AddJob(jobs.NewJob(ctx, "LockExpiredTenantsJob", jobs.LockExpiredTenantsJobHandler{}))
@@ -105,6 +141,8 @@ func listenSignals(e *web.Engine) int {
 		s := <-signals
 		switch s {
 		case syscall.SIGINT, syscall.SIGTERM:
+			// Open event streams would otherwise keep the web server from shutting down
+			realtime.Shutdown()
 			err := e.Stop()
 			if err != nil {
 				return 1
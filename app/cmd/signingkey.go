@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/getfider/fider/app/pkg/jwt"
+)
+
+// RunSigningKey prints a new key to be used to sign the ID tokens issued to OAuth clients
+// Returns an exitcode, 0 for OK and 1 for ERROR
+func RunSigningKey() int {
+	key, err := jwt.GenerateSigningKey()
+	if err != nil {
+		fmt.Printf("Failed to generate key: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("OAUTH_PROVIDER_SIGNING_KEY=%s\n", key)
+	return 0
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/getfider/fider/app/pkg/webpush"
+)
+
+// RunVAPIDKeys prints a new key pair to be used for Web Push notifications
+// Returns an exitcode, 0 for OK and 1 for ERROR
+func RunVAPIDKeys() int {
+	keys, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		fmt.Printf("Failed to generate keys: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("WEBPUSH_VAPID_PUBLIC_KEY=%s\n", keys.PublicKey)
+	fmt.Printf("WEBPUSH_VAPID_PRIVATE_KEY=%s\n", keys.PrivateKey)
+	return 0
+}
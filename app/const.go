@@ -5,6 +5,9 @@ import "errors"
 // ErrNotFound represents an object not found error
 var ErrNotFound = errors.New("Object not found")
 
+// ErrVoteBudgetExceeded is used when a vote is given with more weight than the user has left to give
+var ErrVoteBudgetExceeded = errors.New("Vote budget exceeded")
+
 // InvitePlaceholder represents the placeholder used by members to invite other users
 var InvitePlaceholder = "%invite%"
 
@@ -24,6 +27,8 @@ const (
 	GoogleProvider = "google"
 	//GitHubProvider is const for 'github'
 	GitHubProvider = "github"
+	//SAMLProvider is const for 'saml'
+	SAMLProvider = "saml"
 )
 
 var (
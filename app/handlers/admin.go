@@ -10,6 +10,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/tasks"
 )
@@ -33,6 +34,7 @@ func AdvancedSettingsPage() web.HandlerFunc {
 			Data: web.Map{
 				"customCSS": c.Tenant().CustomCSS,
 				"allowedSchemes": c.Tenant().AllowedSchemes,
+				"postByEmailAddress": inbound.PostAddress(c.Tenant().Subdomain),
 			},
 		})
 	}
@@ -124,6 +126,27 @@ func UpdatePrivacySettings() web.HandlerFunc {
 	}
 }
 
+// UpdateVotingSettings update how users vote on posts of current tenant
+func UpdateVotingSettings() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.UpdateTenantVotingSettings)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		updateSettings := &cmd.UpdateTenantVotingSettings{
+			VotingMode:      action.VotingMode,
+			VoteBudget:      action.VoteBudget,
+			MaxVotesPerPost: action.MaxVotesPerPost,
+		}
+		if err := bus.Dispatch(c, updateSettings); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // UpdateEmailAuthAllowed update current tenant's allow email auth settings
 func UpdateEmailAuthAllowed() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -143,11 +166,31 @@ func UpdateEmailAuthAllowed() web.HandlerFunc {
 	}
 }
 
+// UpdateTwoFactorRequired is used to require two-factor authentication for staff members
+func UpdateTwoFactorRequired() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.UpdateTenantTwoFactorRequired)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		updateSettings := &cmd.UpdateTenantTwoFactorSettings{
+			IsTwoFactorRequired: action.IsTwoFactorRequired,
+		}
+		if err := bus.Dispatch(c, updateSettings); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // ManageMembers is the page used by administrators to change member's role
 func ManageMembers() web.HandlerFunc {
 	return func(c *web.Context) error {
 		allUsers := &query.GetAllUsers{}
-		if err := bus.Dispatch(c, allUsers); err != nil {
+		allRoles := &query.GetAllCustomRoles{}
+		if err := bus.Dispatch(c, allUsers, allRoles); err != nil {
 			return c.Failure(err)
 		}
 
@@ -164,6 +207,7 @@ func ManageMembers() web.HandlerFunc {
 			Title: "Manage Members · Site Settings",
 			Data: web.Map{
 				"users": allUsersWithEmail,
+				"roles": allRoles.Result,
 			},
 		})
 	}
@@ -215,21 +259,22 @@ func SaveOAuthConfig() web.HandlerFunc {
 				Folder: "logos",
 			},
 			&cmd.SaveCustomOAuthConfig{
-				ID:                action.ID,
-				Logo:              action.Logo,
-				Provider:          action.Provider,
-				Status:            action.Status,
-				DisplayName:       action.DisplayName,
-				ClientID:          action.ClientID,
-				ClientSecret:      action.ClientSecret,
-				AuthorizeURL:      action.AuthorizeURL,
-				TokenURL:          action.TokenURL,
-				Scope:             action.Scope,
-				ProfileURL:        action.ProfileURL,
-				IsTrusted:         action.IsTrusted,
-				JSONUserIDPath:    action.JSONUserIDPath,
-				JSONUserNamePath:  action.JSONUserNamePath,
-				JSONUserEmailPath: action.JSONUserEmailPath,
+				ID:                 action.ID,
+				Logo:               action.Logo,
+				Provider:           action.Provider,
+				Status:             action.Status,
+				DisplayName:        action.DisplayName,
+				ClientID:           action.ClientID,
+				ClientSecret:       action.ClientSecret,
+				AuthorizeURL:       action.AuthorizeURL,
+				TokenURL:           action.TokenURL,
+				Scope:              action.Scope,
+				ProfileURL:         action.ProfileURL,
+				IsTrusted:          action.IsTrusted,
+				JSONUserIDPath:     action.JSONUserIDPath,
+				JSONUserNamePath:   action.JSONUserNamePath,
+				JSONUserEmailPath:  action.JSONUserEmailPath,
+				JSONUserGroupsPath: action.JSONUserGroupsPath,
 			},
 		); err != nil {
 			return c.Failure(err)
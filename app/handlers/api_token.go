@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListAPITokens returns all API tokens of current user
+func ListAPITokens() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getTokens := &query.GetCurrentUserAPITokens{}
+		if err := bus.Dispatch(c, getTokens); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(getTokens.Result)
+	}
+}
+
+// CreateAPIToken creates a new API token for current user, the key is only returned once
+func CreateAPIToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateAPIToken)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		addNewToken := &cmd.AddNewAPIToken{
+			Name:      action.Name,
+			Scopes:    action.Scopes,
+			ExpiresAt: action.ExpiresAt(),
+		}
+		if err := bus.Dispatch(c, addNewToken); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"token": addNewToken.Result,
+			"key":   addNewToken.Key,
+		})
+	}
+}
+
+// RevokeAPIToken revokes an existing API token
+func RevokeAPIToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RevokeAPIToken)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.RevokeAPIToken{TokenID: action.Token.ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// ManageAPITokens is the page used by administrators to review and revoke API tokens
+func ManageAPITokens() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getTokens := &query.GetAllAPITokens{}
+		if err := bus.Dispatch(c, getTokens); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageAPITokens.page",
+			Title: "API Tokens · Site Settings",
+			Data: web.Map{
+				"tokens": getTokens.Result,
+			},
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package apiv1
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListAuditLogs returns the administrative changes made on current tenant, newest first
+func ListAuditLogs() web.HandlerFunc {
+	return func(c *web.Context) error {
+		search := &query.SearchAuditLogs{
+			Action:     c.QueryParam("action"),
+			TargetType: c.QueryParam("targetType"),
+		}
+
+		var err error
+		if search.ActorID, err = c.QueryParamAsInt("actorId"); err != nil {
+			return c.BadRequest(web.Map{"error": "Invalid actorId"})
+		}
+		if search.Limit, err = c.QueryParamAsInt("limit"); err != nil {
+			return c.BadRequest(web.Map{"error": "Invalid limit"})
+		}
+		if search.Since, err = parseAuditLogTime(c.QueryParam("since")); err != nil {
+			return c.BadRequest(web.Map{"error": "Invalid since, use RFC 3339 or YYYY-MM-DD"})
+		}
+		if search.Until, err = parseAuditLogTime(c.QueryParam("until")); err != nil {
+			return c.BadRequest(web.Map{"error": "Invalid until, use RFC 3339 or YYYY-MM-DD"})
+		}
+
+		if err := bus.Dispatch(c, search); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(search.Result)
+	}
+}
+
+func parseAuditLogTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
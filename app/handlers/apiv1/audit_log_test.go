@@ -0,0 +1,54 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestListAuditLogsHandler(t *testing.T) {
+	RegisterT(t)
+
+	var search *query.SearchAuditLogs
+	bus.AddHandler(func(ctx context.Context, q *query.SearchAuditLogs) error {
+		search = q
+		q.Result = []*entity.AuditLogEntry{
+			{ID: 1, Action: entity.AuditUserBlocked, ActorID: mock.JonSnow.ID, ActorName: mock.JonSnow.Name, TargetType: "user", TargetID: 5},
+		}
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/audit-log?action=user.blocked&actorId=1&since=2026-10-01&limit=50").
+		ExecuteAsJSON(apiv1.ListAuditLogs())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.ArrayLength()).Equals(1)
+	Expect(search.Action).Equals(entity.AuditUserBlocked)
+	Expect(search.ActorID).Equals(1)
+	Expect(search.Limit).Equals(50)
+	Expect(*search.Since).Equals(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	Expect(search.Until).IsNil()
+}
+
+func TestListAuditLogsHandler_InvalidDate(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL("http://demo.test.fider.io/api/v1/audit-log?since=yesterday").
+		ExecuteAsJSON(apiv1.ListAuditLogs())
+
+	Expect(status).Equals(http.StatusBadRequest)
+}
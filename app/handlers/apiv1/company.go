@@ -0,0 +1,88 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListCompanies returns all companies of current tenant
+func ListCompanies() web.HandlerFunc {
+	return func(c *web.Context) error {
+		q := &query.GetAllCompanies{}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// CreateEditCompany creates a new company on current tenant or edits an existing one
+func CreateEditCompany() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateEditCompany)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Company != nil {
+			updateCompany := &cmd.UpdateCompany{
+				CompanyID: action.Company.ID,
+				Name:      action.Name,
+				MRR:       action.MRR,
+				Plan:      action.Plan,
+			}
+			if err := bus.Dispatch(c, updateCompany); err != nil {
+				return c.Failure(err)
+			}
+			return c.Ok(updateCompany.Result)
+		}
+
+		addNewCompany := &cmd.AddNewCompany{
+			Name: action.Name,
+			MRR:  action.MRR,
+			Plan: action.Plan,
+		}
+		if err := bus.Dispatch(c, addNewCompany); err != nil {
+			return c.Failure(err)
+		}
+		return c.Ok(addNewCompany.Result)
+	}
+}
+
+// DeleteCompany deletes an existing company, its users are kept without a company
+func DeleteCompany() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeleteCompany)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.DeleteCompany{CompanyID: action.Company.ID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// SetUserCompany assigns a user to a company
+func SetUserCompany() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetUserCompany)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.SetUserCompany{UserID: action.UserID, CompanyID: action.CompanyID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,92 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestCreateCompanyHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		return app.ErrNotFound
+	})
+
+	var addNewCompany *cmd.AddNewCompany
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewCompany) error {
+		addNewCompany = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePost(apiv1.CreateEditCompany(), `{ "name": "Acme Inc.", "mrr": 1200, "plan": "Enterprise" }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(addNewCompany.Name).Equals("Acme Inc.")
+	Expect(addNewCompany.MRR).Equals(1200)
+	Expect(addNewCompany.Plan).Equals("Enterprise")
+}
+
+func TestCreateCompanyHandler_DuplicateName(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		q.Result = &entity.Company{ID: 1, Name: "Acme Inc."}
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePost(apiv1.CreateEditCompany(), `{ "name": "acme inc." }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+}
+
+func TestCreateCompanyHandler_Visitor(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		ExecutePost(apiv1.CreateEditCompany(), `{ "name": "Acme Inc." }`)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestEditCompanyHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByID) error {
+		q.Result = &entity.Company{ID: q.CompanyID, Name: "Acme Inc.", MRR: 100}
+		return nil
+	})
+
+	var updateCompany *cmd.UpdateCompany
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateCompany) error {
+		updateCompany = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", 4).
+		ExecutePost(apiv1.CreateEditCompany(), `{ "name": "Acme Inc.", "mrr": 300 }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(updateCompany.CompanyID).Equals(4)
+	Expect(updateCompany.MRR).Equals(300)
+}
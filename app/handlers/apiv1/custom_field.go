@@ -0,0 +1,90 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListCustomFields returns all custom fields visible to current user
+func ListCustomFields() web.HandlerFunc {
+	return func(c *web.Context) error {
+		q := &query.GetAllCustomFields{}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// CreateEditCustomField creates a new custom field on current tenant or edits an existing one
+func CreateEditCustomField() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateEditCustomField)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Field != nil {
+			updateField := &cmd.UpdateCustomField{
+				FieldID:  action.Field.ID,
+				Name:     action.Name,
+				Options:  action.Options,
+				IsPublic: action.IsPublic,
+				Position: action.Position,
+			}
+			if err := bus.Dispatch(c, updateField); err != nil {
+				return c.Failure(err)
+			}
+			return c.Ok(updateField.Result)
+		}
+
+		addNewField := &cmd.AddNewCustomField{
+			Name:     action.Name,
+			Type:     action.Type,
+			Options:  action.Options,
+			IsPublic: action.IsPublic,
+		}
+		if err := bus.Dispatch(c, addNewField); err != nil {
+			return c.Failure(err)
+		}
+		return c.Ok(addNewField.Result)
+	}
+}
+
+// DeleteCustomField deletes an existing custom field and all its values
+func DeleteCustomField() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeleteCustomField)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.DeleteCustomField{Field: action.Field})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// SetPostCustomFields sets the custom field values of an existing post
+func SetPostCustomFields() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetPostCustomFields)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.SetPostCustomFieldValues{Post: action.Post, Values: action.Values})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(action.Post.CustomFields)
+	}
+}
@@ -0,0 +1,112 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestCreateCustomFieldHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomFieldByKey) error {
+		return app.ErrNotFound
+	})
+
+	var addNewField *cmd.AddNewCustomField
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewCustomField) error {
+		addNewField = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		AsUser(mock.JonSnow).
+		ExecutePost(
+			apiv1.CreateEditCustomField(),
+			`{ "name": "Platform", "type": "multi-select", "options": ["iOS", "Android"], "isPublic": true }`,
+		)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(addNewField.Name).Equals("Platform")
+	Expect(addNewField.Type).Equals(enum.CustomFieldMultiSelect)
+	Expect(addNewField.Options).Equals([]string{"iOS", "Android"})
+	Expect(addNewField.IsPublic).IsTrue()
+}
+
+func TestCreateCustomFieldHandler_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		AsUser(mock.AryaStark).
+		ExecutePost(apiv1.CreateEditCustomField(), `{ "name": "Platform", "type": "text" }`)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestSetPostCustomFieldsHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	arrImpact := &entity.CustomField{ID: 2, Key: "arr-impact", Name: "ARR Impact", Type: enum.CustomFieldNumber}
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllCustomFields) error {
+		q.Result = []*entity.CustomField{arrImpact}
+		return nil
+	})
+
+	var setValues *cmd.SetPostCustomFieldValues
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetPostCustomFieldValues) error {
+		setValues = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.SetPostCustomFields(), `{ "fields": { "arr-impact": 50000 } }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(setValues.Post).Equals(post)
+	Expect(setValues.Values).HasLen(1)
+	Expect(setValues.Values[0].Field).Equals(arrImpact)
+	Expect(setValues.Values[0].Value).Equals(float64(50000))
+}
+
+func TestSetPostCustomFieldsHandler_InvalidValue(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllCustomFields) error {
+		q.Result = []*entity.CustomField{
+			{ID: 2, Key: "arr-impact", Name: "ARR Impact", Type: enum.CustomFieldNumber},
+		}
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", 1).
+		ExecutePost(apiv1.SetPostCustomFields(), `{ "fields": { "arr-impact": "a lot" } }`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+}
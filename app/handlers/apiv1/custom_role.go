@@ -0,0 +1,86 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListCustomRoles returns all custom roles of current tenant
+func ListCustomRoles() web.HandlerFunc {
+	return func(c *web.Context) error {
+		q := &query.GetAllCustomRoles{}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// CreateEditCustomRole creates a new custom role on current tenant or edits an existing one
+func CreateEditCustomRole() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateEditCustomRole)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Role != nil {
+			updateRole := &cmd.UpdateCustomRole{
+				RoleID:      action.Role.ID,
+				Name:        action.Name,
+				Permissions: action.Permissions,
+			}
+			if err := bus.Dispatch(c, updateRole); err != nil {
+				return c.Failure(err)
+			}
+			return c.Ok(updateRole.Result)
+		}
+
+		addNewRole := &cmd.AddNewCustomRole{
+			Name:        action.Name,
+			Permissions: action.Permissions,
+		}
+		if err := bus.Dispatch(c, addNewRole); err != nil {
+			return c.Failure(err)
+		}
+		return c.Ok(addNewRole.Result)
+	}
+}
+
+// DeleteCustomRole deletes an existing custom role, its users become visitors
+func DeleteCustomRole() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeleteCustomRole)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.DeleteCustomRole{RoleID: action.Role.ID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// SetUserCustomRole makes a user a collaborator with given custom role
+func SetUserCustomRole() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetUserCustomRole)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.SetUserCustomRole{UserID: action.UserID, RoleID: action.RoleID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,81 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestCreateCustomRoleHandler_ValidRequest(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByName) error {
+		return app.ErrNotFound
+	})
+
+	var addNewRole *cmd.AddNewCustomRole
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewCustomRole) error {
+		addNewRole = c
+		c.Result = &entity.CustomRole{ID: 1, Name: c.Name, Permissions: c.Permissions}
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePost(apiv1.CreateEditCustomRole(), `{ "name": "Support Agent", "permissions": ["posts:respond"] }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(addNewRole.Name).Equals("Support Agent")
+	Expect(addNewRole.Permissions).Equals([]string{entity.PermissionRespond})
+}
+
+func TestCreateCustomRoleHandler_Visitor(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		ExecutePost(apiv1.CreateEditCustomRole(), `{ "name": "Support Agent" }`)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestSetUserCustomRoleHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCustomRoleByID) error {
+		q.Result = &entity.CustomRole{ID: q.RoleID, Name: "Support Agent"}
+		return nil
+	})
+
+	var setRole *cmd.SetUserCustomRole
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetUserCustomRole) error {
+		setRole = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("userID", mock.AryaStark.ID).
+		ExecutePost(apiv1.SetUserCustomRole(), `{ "roleID": 4 }`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(setRole.UserID).Equals(mock.AryaStark.ID)
+	Expect(setRole.RoleID).Equals(4)
+}
@@ -1,6 +1,7 @@
 package apiv1
 
 import (
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/metrics"
 	"github.com/getfider/fider/app/models/cmd"
@@ -9,6 +10,9 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/validate"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/tasks"
 )
@@ -36,6 +40,7 @@ func SearchPosts() web.HandlerFunc {
 			searchPosts.MyPostsOnly = myPostsOnly
 		}
 		searchPosts.SetStatusesFromStrings(c.QueryParamAsArray("statuses"))
+		searchPosts.SetCustomFieldsFromQuery(c.Request.URL.Query())
 
 		if err := bus.Dispatch(c, searchPosts); err != nil {
 			return c.Failure(err)
@@ -73,15 +78,23 @@ func CreatePost() web.HandlerFunc {
 		newPost := &cmd.AddNewPost{
 			Title:       action.Title,
 			Description: action.Description,
+			IsPrivate:   action.IsPrivate,
 		}
 		err := bus.Dispatch(c, newPost)
 		if err != nil {
 			return c.Failure(err)
 		}
 
-		setAttachments := &cmd.SetAttachments{Post: newPost.Result, Attachments: action.Attachments}
-		addVote := &cmd.AddVote{Post: newPost.Result, User: c.User()}
-		if err = bus.Dispatch(c, setAttachments, addVote); err != nil {
+		commands := []bus.Msg{
+			&cmd.SetAttachments{Post: newPost.Result, Attachments: action.Attachments},
+		}
+
+		// On vote budgets authors choose themselves how much of their budget goes to their own posts
+		if c.Tenant().VotingMode != enum.VotingModeBudget {
+			commands = append(commands, &cmd.AddVote{Post: newPost.Result, User: c.User()})
+		}
+
+		if err = bus.Dispatch(c, commands...); err != nil {
 			return c.Failure(err)
 		}
 
@@ -196,6 +209,48 @@ func SetResponse() web.HandlerFunc {
 	}
 }
 
+// MergePost merges a duplicate post into its original, moving votes, subscribers, tags and optionally comments
+func MergePost() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.MergePost)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		prevStatus := action.Post.Status
+
+		mergePosts := &cmd.MergePosts{
+			Post:         action.Post,
+			Original:     action.Original,
+			CopyComments: action.CopyComments,
+		}
+		if err := bus.Dispatch(c, mergePosts); err != nil {
+			return c.Failure(err)
+		}
+
+		c.Enqueue(tasks.NotifyAboutStatusChange(action.Post, prevStatus))
+		c.Enqueue(tasks.NotifyAboutMergedPost(action.Post, action.Original, mergePosts.Result))
+
+		return c.Ok(mergePosts.Result)
+	}
+}
+
+// RevertPostMerge undoes the last merge of a duplicate post
+func RevertPostMerge() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RevertPostMerge)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.RevertPostMerge{Merge: action.Merge}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // DeletePost deletes an existing post of current tenant
 func DeletePost() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -219,6 +274,46 @@ func DeletePost() web.HandlerFunc {
 	}
 }
 
+// SetPostPrivacy makes an existing post private or public
+func SetPostPrivacy() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetPostPrivacy)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.SetPostPrivacy{
+			Post:      action.Post,
+			IsPrivate: action.IsPrivate,
+		})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// SetPostGroup restricts an existing post to a user group, or removes the restriction when GroupID is zero
+func SetPostGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SetPostGroup)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.SetPostGroup{
+			Post:    action.Post,
+			GroupID: action.GroupID,
+		})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // ListComments returns a list of all comments of a post
 func ListComments() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -312,8 +407,9 @@ func PostComment() web.HandlerFunc {
 		}
 
 		addNewComment := &cmd.AddNewComment{
-			Post:    getPost.Result,
-			Content: action.Content,
+			Post:       getPost.Result,
+			Content:    action.Content,
+			IsInternal: action.IsInternal,
 		}
 		if err := bus.Dispatch(c, addNewComment); err != nil {
 			return c.Failure(err)
@@ -403,21 +499,94 @@ func DeleteComment() web.HandlerFunc {
 	}
 }
 
-// AddVote adds current user to given post list of votes
+// AddVote adds current user to given post list of votes, or changes the weight of an existing vote
 func AddVote() web.HandlerFunc {
 	return func(c *web.Context) error {
-		err := addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
-			return &cmd.AddVote{Post: post, User: user}
-		})
+		action := new(actions.AddVote)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
 
-		if err == nil {
-			metrics.TotalVotes.Inc()
+		addVote := &cmd.AddVote{Post: action.Post, User: c.User(), Weight: action.Weight}
+		if err := bus.Dispatch(c, addVote); err != nil {
+			if errors.Cause(err) == app.ErrVoteBudgetExceeded {
+				return c.HandleValidation(voteBudgetExceeded(c))
+			}
+			return c.Failure(err)
 		}
 
-		return err
+		metrics.TotalVotes.Inc()
+		return c.Ok(web.Map{})
 	}
 }
 
+// AddProxyVote adds a vote on behalf of another user or contact, who is registered when unknown
+func AddProxyVote() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.AddProxyVote)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		voter := action.User
+		if voter == nil {
+			voter = &entity.User{
+				Tenant: c.Tenant(),
+				Name:   action.Name,
+				Email:  action.Email,
+				Role:   enum.RoleVisitor,
+			}
+			if err := bus.Dispatch(c, &cmd.RegisterUser{User: voter}); err != nil {
+				return c.Failure(err)
+			}
+		}
+
+		if action.Company != "" {
+			getCompany := &query.GetCompanyByName{Name: action.Company}
+			err := bus.Dispatch(c, getCompany)
+			company := getCompany.Result
+			if err != nil {
+				if errors.Cause(err) != app.ErrNotFound {
+					return c.Failure(err)
+				}
+				addNewCompany := &cmd.AddNewCompany{Name: action.Company}
+				if err := bus.Dispatch(c, addNewCompany); err != nil {
+					return c.Failure(err)
+				}
+				company = addNewCompany.Result
+			}
+
+			if err := bus.Dispatch(c, &cmd.SetUserCompany{UserID: voter.ID, CompanyID: company.ID}); err != nil {
+				return c.Failure(err)
+			}
+		}
+
+		addVote := &cmd.AddVote{
+			Post:      action.Post,
+			User:      voter,
+			Weight:    action.Weight,
+			ProxiedBy: c.User(),
+			Note:      action.Note,
+		}
+		if err := bus.Dispatch(c, addVote); err != nil {
+			if errors.Cause(err) == app.ErrVoteBudgetExceeded {
+				return c.HandleValidation(voteBudgetExceeded(c))
+			}
+			return c.Failure(err)
+		}
+
+		metrics.TotalVotes.Inc()
+		return c.Ok(web.Map{"userID": voter.ID})
+	}
+}
+
+// voteBudgetExceeded is the failure of a vote that no longer fits in the budget because other votes were given meanwhile
+func voteBudgetExceeded(c *web.Context) *validate.Result {
+	result := validate.Success()
+	result.AddFieldFailure("weight", i18n.T(c, "validation.custom.votebudgetspent"))
+	return result
+}
+
 // RemoveVote removes current user from given post list of votes
 func RemoveVote() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -464,8 +633,16 @@ func ToggleVote() web.HandlerFunc {
 			return c.Ok(web.Map{"voted": false})
 		}
 
-		err = bus.Dispatch(c, &cmd.AddVote{Post: getPost.Result, User: c.User()})
+		action := new(actions.AddVote)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err = bus.Dispatch(c, &cmd.AddVote{Post: action.Post, User: c.User(), Weight: action.Weight})
 		if err != nil {
+			if errors.Cause(err) == app.ErrVoteBudgetExceeded {
+				return c.HandleValidation(voteBudgetExceeded(c))
+			}
 			return c.Failure(err)
 		}
 		metrics.TotalVotes.Inc()
@@ -491,6 +668,24 @@ func Unsubscribe() web.HandlerFunc {
 	}
 }
 
+// MuteComments stops notifying current user about new comments on given post, but keeps the subscription to its other events
+func MuteComments() web.HandlerFunc {
+	return func(c *web.Context) error {
+		return addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
+			return &cmd.MuteComments{Post: post, User: user}
+		})
+	}
+}
+
+// UnmuteComments notifies current user about new comments on given post again
+func UnmuteComments() web.HandlerFunc {
+	return func(c *web.Context) error {
+		return addOrRemove(c, func(post *entity.Post, user *entity.User) bus.Msg {
+			return &cmd.UnmuteComments{Post: post, User: user}
+		})
+	}
+}
+
 // ListVotes returns a list of all votes on given post
 func ListVotes() web.HandlerFunc {
 	return func(c *web.Context) error {
@@ -504,7 +699,7 @@ func ListVotes() web.HandlerFunc {
 			return c.Failure(err)
 		}
 
-		listVotes := &query.ListPostVotes{PostID: getPost.Result.ID, IncludeEmail: true}
+		listVotes := &query.ListPostVotes{PostID: getPost.Result.ID, IncludeEmail: c.User().HasPermission(entity.PermissionViewVoterEmails)}
 		if err := bus.Dispatch(c, listVotes); err != nil {
 			return c.Failure(err)
 		}
@@ -513,6 +708,18 @@ func ListVotes() web.HandlerFunc {
 	}
 }
 
+// GetVoteBudget returns how many votes current user can still give
+func GetVoteBudget() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getBudget := &query.GetVoteBudget{}
+		if err := bus.Dispatch(c, getBudget); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(getBudget.Result)
+	}
+}
+
 func addOrRemove(c *web.Context, getCommand func(post *entity.Post, user *entity.User) bus.Msg) error {
 	number, err := c.ParamAsInt("number")
 	if err != nil {
@@ -593,6 +593,243 @@ func TestSetResponseHandler_Duplicate_Itself(t *testing.T) {
 	Expect(code).Equals(http.StatusBadRequest)
 }
 
+func TestSetResponseHandler_Duplicate_RestrictedOriginal(t *testing.T) {
+	RegisterT(t)
+
+	post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", User: mock.AryaStark}
+	post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", User: mock.JonSnow, IsPrivate: true}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post1.Number {
+			q.Result = post1
+			return nil
+		}
+		if q.Number == post2.Number {
+			q.Result = post2
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	body := fmt.Sprintf(`{ "status": "%s", "originalNumber": %d }`, enum.PostDuplicate.Name(), post2.Number)
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post1.Number).
+		ExecutePost(apiv1.SetResponse(), body)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestMergePostHandler(t *testing.T) {
+	RegisterT(t)
+
+	post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+	post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", Status: enum.PostOpen}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post1.Number {
+			q.Result = post1
+			return nil
+		}
+		if q.Number == post2.Number {
+			q.Result = post2
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var mergePosts *cmd.MergePosts
+	bus.AddHandler(func(ctx context.Context, c *cmd.MergePosts) error {
+		mergePosts = c
+		c.Result = &entity.PostMerge{ID: 1, PostID: c.Post.ID, OriginalID: c.Original.ID}
+		return nil
+	})
+
+	body := fmt.Sprintf(`{ "originalNumber": %d, "copyComments": true }`, post2.Number)
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post1.Number).
+		ExecutePost(apiv1.MergePost(), body)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(mergePosts.Post).Equals(post1)
+	Expect(mergePosts.Original).Equals(post2)
+	Expect(mergePosts.CopyComments).IsTrue()
+}
+
+func TestMergePostHandler_IntoDuplicate(t *testing.T) {
+	RegisterT(t)
+
+	post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+	post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", Status: enum.PostDuplicate}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		if q.Number == post1.Number {
+			q.Result = post1
+			return nil
+		}
+		if q.Number == post2.Number {
+			q.Result = post2
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	body := fmt.Sprintf(`{ "originalNumber": %d }`, post2.Number)
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post1.Number).
+		ExecutePost(apiv1.MergePost(), body)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestMergePostHandler_FromDuplicateOrDeleted(t *testing.T) {
+	RegisterT(t)
+
+	for _, status := range []enum.PostStatus{enum.PostDuplicate, enum.PostDeleted} {
+		post1 := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: status}
+		post2 := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", Status: enum.PostOpen}
+		bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+			if q.Number == post1.Number {
+				q.Result = post1
+				return nil
+			}
+			if q.Number == post2.Number {
+				q.Result = post2
+				return nil
+			}
+			return app.ErrNotFound
+		})
+
+		body := fmt.Sprintf(`{ "originalNumber": %d }`, post2.Number)
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			AsUser(mock.JonSnow).
+			AddParam("number", post1.Number).
+			ExecutePost(apiv1.MergePost(), body)
+
+		Expect(code).Equals(http.StatusBadRequest)
+	}
+}
+
+func TestMergePostHandler_RestrictedPosts(t *testing.T) {
+	RegisterT(t)
+
+	publicPost := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen, User: mock.AryaStark}
+	privatePost := &entity.Post{ID: 2, Number: 2, Title: "The Post #2", Status: enum.PostOpen, User: mock.AryaStark, IsPrivate: true}
+	groupPost := &entity.Post{ID: 3, Number: 3, Title: "The Post #3", Status: enum.PostOpen, User: mock.AryaStark, GroupID: 1}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		for _, post := range []*entity.Post{publicPost, privatePost, groupPost} {
+			if q.Number == post.Number {
+				q.Result = post
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+
+	merged := 0
+	bus.AddHandler(func(ctx context.Context, c *cmd.MergePosts) error {
+		merged++
+		c.Result = &entity.PostMerge{ID: 1, PostID: c.Post.ID, OriginalID: c.Original.ID}
+		return nil
+	})
+
+	testCases := []struct {
+		number         int
+		originalNumber int
+		copyComments   bool
+		code           int
+	}{
+		{publicPost.Number, privatePost.Number, false, http.StatusBadRequest},
+		{publicPost.Number, groupPost.Number, false, http.StatusBadRequest},
+		{privatePost.Number, publicPost.Number, true, http.StatusBadRequest},
+		{groupPost.Number, publicPost.Number, true, http.StatusBadRequest},
+		{privatePost.Number, publicPost.Number, false, http.StatusOK},
+		{groupPost.Number, publicPost.Number, false, http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		body := fmt.Sprintf(`{ "originalNumber": %d, "copyComments": %t }`, testCase.originalNumber, testCase.copyComments)
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			AsUser(mock.JonSnow).
+			AddParam("number", testCase.number).
+			ExecutePost(apiv1.MergePost(), body)
+
+		Expect(code).Equals(testCase.code)
+	}
+	Expect(merged).Equals(2)
+}
+
+func TestMergePostHandler_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.MergePost(), `{ "originalNumber": 2 }`)
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
+func TestRevertPostMergeHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostDuplicate}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	merge := &entity.PostMerge{ID: 5, PostID: post.ID, OriginalID: 2}
+	bus.AddHandler(func(ctx context.Context, q *query.GetActivePostMerge) error {
+		if q.PostID == post.ID {
+			q.Result = merge
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	var revert *cmd.RevertPostMerge
+	bus.AddHandler(func(ctx context.Context, c *cmd.RevertPostMerge) error {
+		revert = c
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		Execute(apiv1.RevertPostMerge())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(revert.Merge).Equals(merge)
+}
+
+func TestRevertPostMergeHandler_NotMerged(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActivePostMerge) error {
+		return app.ErrNotFound
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", 1).
+		Execute(apiv1.RevertPostMerge())
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
 func TestAddVoteHandler(t *testing.T) {
 	RegisterT(t)
 
@@ -619,6 +856,144 @@ func TestAddVoteHandler(t *testing.T) {
 	Expect(addVote.User).Equals(mock.AryaStark)
 }
 
+func TestAddProxyVoteHandler_ExistingUser(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	var addVote *cmd.AddVote
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		addVote = c
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.AddProxyVote(), fmt.Sprintf(`{ "userID": %d, "note": "Asked on a call" }`, mock.AryaStark.ID))
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(addVote.Post).Equals(post)
+	Expect(addVote.User).Equals(mock.AryaStark)
+	Expect(addVote.ProxiedBy).Equals(mock.JonSnow)
+	Expect(addVote.Note).Equals("Asked on a call")
+}
+
+func TestAddProxyVoteHandler_NewContact(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	var registerUser *cmd.RegisterUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 42
+		registerUser = c
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCompanyByName) error {
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewCompany) error {
+		c.Result = &entity.Company{ID: 7, Name: c.Name}
+		return nil
+	})
+
+	var setCompany *cmd.SetUserCompany
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetUserCompany) error {
+		setCompany = c
+		return nil
+	})
+
+	var addVote *cmd.AddVote
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		addVote = c
+		return nil
+	})
+
+	code, json := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecutePostAsJSON(apiv1.AddProxyVote(), `{ "email": "Jane@acme.com", "company": "Acme Inc." }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(json.Int32("userID")).Equals(42)
+	Expect(registerUser.User.Email).Equals("jane@acme.com")
+	Expect(registerUser.User.Name).Equals("jane")
+	Expect(registerUser.User.Role).Equals(enum.RoleVisitor)
+	Expect(setCompany.UserID).Equals(42)
+	Expect(setCompany.CompanyID).Equals(7)
+	Expect(addVote.User.ID).Equals(42)
+	Expect(addVote.ProxiedBy).Equals(mock.JonSnow)
+}
+
+func TestAddProxyVoteHandler_BudgetExceeded(t *testing.T) {
+	RegisterT(t)
+
+	tenant := *mock.DemoTenant
+	tenant.VotingMode = enum.VotingModeBudget
+	tenant.VoteBudget = 10
+	tenant.MaxVotesPerPost = 3
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Status: enum.PostOpen}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		Expect(q.UserID).Equals(mock.AryaStark.ID)
+		Expect(q.ExcludePostID).Equals(post.ID)
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 9, Remaining: 1, MaxPerPost: 3}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(&tenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.AddProxyVote(), fmt.Sprintf(`{ "userID": %d, "weight": 2 }`, mock.AryaStark.ID))
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestAddProxyVoteHandler_Visitor(t *testing.T) {
+	RegisterT(t)
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.AddProxyVote(), `{ "email": "jane@acme.com" }`)
+
+	Expect(code).Equals(http.StatusForbidden)
+}
+
 func TestAddVoteHandler_InvalidPost(t *testing.T) {
 	RegisterT(t)
 
@@ -635,6 +1010,145 @@ func TestAddVoteHandler_InvalidPost(t *testing.T) {
 	Expect(code).Equals(http.StatusNotFound)
 }
 
+func TestAddVoteHandler_Budget(t *testing.T) {
+	RegisterT(t)
+
+	tenant := *mock.DemoTenant
+	tenant.VotingMode = enum.VotingModeBudget
+	tenant.VoteBudget = 10
+	tenant.MaxVotesPerPost = 3
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1", Description: "The Description #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		Expect(q.ExcludePostID).Equals(post.ID)
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 7, Remaining: 3, MaxPerPost: 3}
+		return nil
+	})
+
+	var addVote *cmd.AddVote
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		addVote = c
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(&tenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", post.Number).
+		ExecutePost(apiv1.AddVote(), `{ "weight": 3 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(addVote.Post).Equals(post)
+	Expect(addVote.Weight).Equals(3)
+}
+
+func TestAddVoteHandler_BudgetExceeded(t *testing.T) {
+	RegisterT(t)
+
+	tenant := *mock.DemoTenant
+	tenant.VotingMode = enum.VotingModeBudget
+	tenant.VoteBudget = 10
+	tenant.MaxVotesPerPost = 3
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 9, Remaining: 1, MaxPerPost: 3}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(&tenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.AddVote(), `{ "weight": 2 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestAddVoteHandler_BudgetSpentMeanwhile(t *testing.T) {
+	RegisterT(t)
+
+	tenant := *mock.DemoTenant
+	tenant.VotingMode = enum.VotingModeBudget
+	tenant.VoteBudget = 10
+	tenant.MaxVotesPerPost = 3
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetVoteBudget) error {
+		q.Result = &entity.VoteBudget{Total: 10, Spent: 7, Remaining: 3, MaxPerPost: 3}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		return app.ErrVoteBudgetExceeded
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(&tenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.AddVote(), `{ "weight": 3 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestAddVoteHandler_WeightAboveMax(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.AddVote(), `{ "weight": 2 }`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+}
+
+func TestAddVoteHandler_Importance(t *testing.T) {
+	RegisterT(t)
+
+	tenant := *mock.DemoTenant
+	tenant.VotingMode = enum.VotingModeImportance
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+		return nil
+	})
+
+	var addVote *cmd.AddVote
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		addVote = c
+		return nil
+	})
+
+	code, _ := mock.NewServer().
+		OnTenant(&tenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		ExecutePost(apiv1.AddVote(), `{ "weight": 3 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(addVote.Weight).Equals(enum.ImportanceCritical)
+}
+
 func TestRemoveVoteHandler(t *testing.T) {
 	RegisterT(t)
 
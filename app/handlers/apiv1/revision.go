@@ -0,0 +1,137 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/diff"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListRevisions returns all revisions of a post, its response and its comments
+func ListRevisions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		number, err := c.ParamAsInt("number")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		getPost := &query.GetPostByNumber{Number: number}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			return c.Failure(err)
+		}
+
+		getRevisions := &query.GetRevisionsByPost{PostID: getPost.Result.ID}
+		if err := bus.Dispatch(c, getRevisions); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(getRevisions.Result)
+	}
+}
+
+// GetRevisionDiff returns the differences between a revision and the one before it,
+// or the revision given on the compare querystring parameter
+func GetRevisionDiff() web.HandlerFunc {
+	return func(c *web.Context) error {
+		number, err := c.ParamAsInt("number")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		revisionID, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		compareID, err := c.QueryParamAsInt("compare")
+		if err != nil {
+			return c.BadRequest(web.Map{})
+		}
+
+		getPost := &query.GetPostByNumber{Number: number}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			return c.Failure(err)
+		}
+
+		getRevision := &query.GetRevisionByID{PostID: getPost.Result.ID, RevisionID: revisionID}
+		if err := bus.Dispatch(c, getRevision); err != nil {
+			return c.Failure(err)
+		}
+		to := getRevision.Result
+
+		var from *entity.Revision
+		if compareID > 0 {
+			getCompare := &query.GetRevisionByID{PostID: getPost.Result.ID, RevisionID: compareID}
+			if err := bus.Dispatch(c, getCompare); err != nil {
+				return c.Failure(err)
+			}
+			if !getCompare.Result.IsSameTarget(to) {
+				return c.BadRequest(web.Map{})
+			}
+			from = getCompare.Result
+		} else {
+			getPrevious := &query.GetPreviousRevision{Revision: to}
+			err := bus.Dispatch(c, getPrevious)
+			if err != nil && errors.Cause(err) != app.ErrNotFound {
+				return c.Failure(err)
+			}
+			from = getPrevious.Result
+		}
+
+		fromTitle, fromContent := "", ""
+		if from != nil {
+			fromTitle, fromContent = from.Title, from.Content
+		}
+
+		return c.Ok(web.Map{
+			"from":    from,
+			"to":      to,
+			"title":   diff.Words(fromTitle, to.Title),
+			"content": diff.Lines(fromContent, to.Content),
+		})
+	}
+}
+
+// RestoreRevision writes the content of an earlier revision back as the latest version
+func RestoreRevision() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RestoreRevision)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		var command bus.Msg
+		revision := action.Revision
+		switch revision.Kind {
+		case enum.RevisionPost:
+			command = &cmd.UpdatePost{
+				Post:        action.Post,
+				Title:       revision.Title,
+				Description: revision.Content,
+			}
+		case enum.RevisionResponse:
+			command = &cmd.SetPostResponse{
+				Post:   action.Post,
+				Text:   revision.Content,
+				Status: action.Post.Status,
+			}
+		case enum.RevisionComment:
+			command = &cmd.UpdateComment{
+				CommentID: revision.CommentID,
+				Content:   revision.Content,
+			}
+		}
+
+		if err := bus.Dispatch(c, command); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,197 @@
+package apiv1_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers/apiv1"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestListRevisionsHandler(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetRevisionsByPost) error {
+		Expect(q.PostID).Equals(post.ID)
+		q.Result = []*entity.Revision{
+			{ID: 2, PostID: post.ID, Kind: enum.RevisionPost, Title: "The Post #1", Content: "Updated"},
+			{ID: 1, PostID: post.ID, Kind: enum.RevisionPost, Title: "The Post #1", Content: "Original"},
+		}
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		ExecuteAsJSON(apiv1.ListRevisions())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.IsArray()).IsTrue()
+	Expect(query.ArrayLength()).Equals(2)
+}
+
+func TestGetRevisionDiffHandler_Previous(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	previous := &entity.Revision{ID: 1, PostID: post.ID, Kind: enum.RevisionPost, Title: "My Post", Content: "Line 1\n"}
+	current := &entity.Revision{ID: 2, PostID: post.ID, Kind: enum.RevisionPost, Title: "My Great Post", Content: "Line 1\nLine 2\n"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetRevisionByID) error {
+		Expect(q.RevisionID).Equals(current.ID)
+		q.Result = current
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPreviousRevision) error {
+		q.Result = previous
+		return nil
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", current.ID).
+		ExecuteAsJSON(apiv1.GetRevisionDiff())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Int32("from.id")).Equals(1)
+	Expect(query.Int32("to.id")).Equals(2)
+	Expect(query.String("content[0].op")).Equals("equal")
+	Expect(query.String("content[1].op")).Equals("insert")
+	Expect(query.String("content[1].text")).Equals("Line 2\n")
+}
+
+func TestGetRevisionDiffHandler_FirstRevision(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "The Post #1"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetRevisionByID) error {
+		q.Result = &entity.Revision{ID: 1, PostID: post.ID, Kind: enum.RevisionPost, Title: "My Post", Content: "Line 1"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPreviousRevision) error {
+		return app.ErrNotFound
+	})
+
+	status, query := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", 1).
+		ExecuteAsJSON(apiv1.GetRevisionDiff())
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(query.Contains("to")).IsTrue()
+	Expect(query.String("content[0].op")).Equals("insert")
+	Expect(query.String("content[0].text")).Equals("Line 1")
+}
+
+func TestRestoreRevisionHandler_Post(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "My Great Post", Description: "Updated"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetRevisionByID) error {
+		q.Result = &entity.Revision{ID: 1, PostID: post.ID, Kind: enum.RevisionPost, Title: "My Post", Content: "Original"}
+		return nil
+	})
+
+	var updatePost *cmd.UpdatePost
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdatePost) error {
+		updatePost = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", 1).
+		ExecutePost(apiv1.RestoreRevision(), `{}`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(updatePost.Post).Equals(post)
+	Expect(updatePost.Title).Equals("My Post")
+	Expect(updatePost.Description).Equals("Original")
+}
+
+func TestRestoreRevisionHandler_Comment(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 1, Number: 1, Title: "My Great Post"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByNumber) error {
+		q.Result = post
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetRevisionByID) error {
+		q.Result = &entity.Revision{ID: 3, PostID: post.ID, CommentID: 5, Kind: enum.RevisionComment, Content: "First comment"}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetCommentByID) error {
+		q.Result = &entity.Comment{ID: q.CommentID, Content: "Edited comment"}
+		return nil
+	})
+
+	var updateComment *cmd.UpdateComment
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateComment) error {
+		updateComment = c
+		return nil
+	})
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("number", post.Number).
+		AddParam("id", 3).
+		ExecutePost(apiv1.RestoreRevision(), `{}`)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(updateComment.CommentID).Equals(5)
+	Expect(updateComment.Content).Equals("First comment")
+}
+
+func TestRestoreRevisionHandler_Unauthorized(t *testing.T) {
+	RegisterT(t)
+
+	status, _ := mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		AddParam("number", 1).
+		AddParam("id", 1).
+		ExecutePost(apiv1.RestoreRevision(), `{}`)
+
+	Expect(status).Equals(http.StatusForbidden)
+}
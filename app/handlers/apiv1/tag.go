@@ -68,6 +68,7 @@ func CreateEditTag() web.HandlerFunc {
 				Name:     action.Name,
 				Color:    action.Color,
 				IsPublic: action.IsPublic,
+				GroupID:  action.GroupID,
 			}
 			if err := bus.Dispatch(c, updateTag); err != nil {
 				return c.Failure(err)
@@ -79,6 +80,7 @@ func CreateEditTag() web.HandlerFunc {
 			Name:     action.Name,
 			Color:    action.Color,
 			IsPublic: action.IsPublic,
+			GroupID:  action.GroupID,
 		}
 		if err := bus.Dispatch(c, addNewTag); err != nil {
 			return c.Failure(err)
@@ -0,0 +1,122 @@
+package apiv1
+
+import (
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ListUserGroups returns all user groups of current tenant
+func ListUserGroups() web.HandlerFunc {
+	return func(c *web.Context) error {
+		q := &query.GetAllUserGroups{}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// CreateEditUserGroup creates a new user group on current tenant or edits an existing one
+func CreateEditUserGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateEditUserGroup)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Group != nil {
+			updateGroup := &cmd.UpdateUserGroup{
+				GroupID:      action.Group.ID,
+				Name:         action.Name,
+				EmailDomains: action.EmailDomains,
+				OAuthClaim:   action.OAuthClaim,
+			}
+			if err := bus.Dispatch(c, updateGroup); err != nil {
+				return c.Failure(err)
+			}
+			return c.Ok(updateGroup.Result)
+		}
+
+		addNewGroup := &cmd.AddNewUserGroup{
+			Name:         action.Name,
+			EmailDomains: action.EmailDomains,
+			OAuthClaim:   action.OAuthClaim,
+		}
+		if err := bus.Dispatch(c, addNewGroup); err != nil {
+			return c.Failure(err)
+		}
+		return c.Ok(addNewGroup.Result)
+	}
+}
+
+// DeleteUserGroup deletes an existing user group, restricted content becomes visible to everyone
+func DeleteUserGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeleteUserGroup)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.DeleteUserGroup{GroupID: action.Group.ID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// ListUserGroupMembers returns all members of a user group
+func ListUserGroupMembers() web.HandlerFunc {
+	return func(c *web.Context) error {
+		groupID, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		q := &query.GetUserGroupMembers{GroupID: groupID}
+		if err := bus.Dispatch(c, q); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(q.Result)
+	}
+}
+
+// AddUserGroupMember manually adds a user to a group
+func AddUserGroupMember() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.AddRemoveUserGroupMember)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.AddUserToGroup{GroupID: action.Group.ID, UserID: action.User.ID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// RemoveUserGroupMember removes a user from a group
+func RemoveUserGroupMember() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.AddRemoveUserGroupMember)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		err := bus.Dispatch(c, &cmd.RemoveUserFromGroup{GroupID: action.Group.ID, UserID: action.User.ID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageAuditLog is the page used by administrators to review changes made on this site
+func ManageAuditLog() web.HandlerFunc {
+	return func(c *web.Context) error {
+		searchLogs := &query.SearchAuditLogs{}
+		allUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, searchLogs, allUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		staff := make([]*entity.User, 0)
+		for _, user := range allUsers.Result {
+			if user.IsCollaborator() {
+				staff = append(staff, user)
+			}
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageAuditLog.page",
+			Title: "Audit Log · Site Settings",
+			Data: web.Map{
+				"entries": searchLogs.Result,
+				"actions": entity.AllAuditActions,
+				"actors":  staff,
+			},
+		})
+	}
+}
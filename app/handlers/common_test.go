@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
@@ -131,3 +132,11 @@ func TestSitemap_PrivateTenant_WithPosts(t *testing.T) {
 
 	Expect(code).Equals(http.StatusNotFound)
 }
+
+// mockCreateUserSession lets handlers that sign users in create their sessions
+func mockCreateUserSession() {
+	bus.AddHandler(func(ctx context.Context, c *cmd.CreateUserSession) error {
+		c.Result = &entity.UserSession{ID: 1, UserID: c.UserID}
+		return nil
+	})
+}
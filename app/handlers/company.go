@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageCompanies is the home page for managing companies
+func ManageCompanies() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getAllCompanies := &query.GetAllCompanies{}
+		if err := bus.Dispatch(c, getAllCompanies); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageCompanies.page",
+			Title: "Manage Companies · Site Settings",
+			Data: web.Map{
+				"companies": getAllCompanies.Result,
+			},
+		})
+	}
+}
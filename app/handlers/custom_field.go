@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageCustomFields is the home page for managing custom fields
+func ManageCustomFields() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getAllFields := &query.GetAllCustomFields{}
+		if err := bus.Dispatch(c, getAllFields); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageCustomFields.page",
+			Title: "Manage Custom Fields · Site Settings",
+			Data: web.Map{
+				"customFields": getAllFields.Result,
+			},
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageCustomRoles is the home page for managing custom roles
+func ManageCustomRoles() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getAllRoles := &query.GetAllCustomRoles{}
+		if err := bus.Dispatch(c, getAllRoles); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageRoles.page",
+			Title: "Manage Roles · Site Settings",
+			Data: web.Map{
+				"roles":       getAllRoles.Result,
+				"permissions": entity.AllPermissions,
+			},
+		})
+	}
+}
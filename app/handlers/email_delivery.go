@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageEmailDeliveries is the page used by administrators to find out what happened to the emails sent to an address
+func ManageEmailDeliveries() web.HandlerFunc {
+	return func(c *web.Context) error {
+		address := c.QueryParam("address")
+		deliveries := make([]*entity.OutboundEmail, 0)
+		if address != "" {
+			searchEmails := &query.SearchOutboundEmails{Address: address}
+			if err := bus.Dispatch(c, searchEmails); err != nil {
+				return c.Failure(err)
+			}
+			deliveries = searchEmails.Result
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageEmailDeliveries.page",
+			Title: "Email Deliveries · Site Settings",
+			Data: web.Map{
+				"address":    address,
+				"deliveries": deliveries,
+			},
+		})
+	}
+}
+
+// SearchEmailDeliveries returns the latest emails sent to an address
+func SearchEmailDeliveries() web.HandlerFunc {
+	return func(c *web.Context) error {
+		address := c.QueryParam("address")
+		if address == "" {
+			return c.Ok([]*entity.OutboundEmail{})
+		}
+
+		searchEmails := &query.SearchOutboundEmails{Address: address}
+		if err := bus.Dispatch(c, searchEmails); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(searchEmails.Result)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageEmailSupressions is the page used by administrators to review the members that no longer receive emails
+func ManageEmailSupressions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getSupressed := &query.GetSupressedEmails{}
+		if err := bus.Dispatch(c, getSupressed); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageEmailSupressions.page",
+			Title: "Email Suppressions · Site Settings",
+			Data: web.Map{
+				"supressions": getSupressed.Result,
+			},
+		})
+	}
+}
+
+// SupressUserEmail stops sending emails to a member
+func SupressUserEmail() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SupressUserEmail)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SupressUserEmail{UserID: action.User.ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// UnsupressUserEmail resumes sending emails to a member whose email was supressed
+func UnsupressUserEmail() web.HandlerFunc {
+	return func(c *web.Context) error {
+		userID, err := c.ParamAsInt("userID")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		if err := bus.Dispatch(c, &cmd.UnsupressUserEmail{UserID: userID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
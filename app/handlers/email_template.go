@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageEmailTemplates is the page used by administrators to customize the emails sent by Fider
+func ManageEmailTemplates() web.HandlerFunc {
+	return func(c *web.Context) error {
+		defaults := &query.ListDefaultEmailTemplates{}
+		overrides := &query.ListEmailTemplates{}
+		if err := bus.Dispatch(c, defaults, overrides); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageEmailTemplates.page",
+			Title: "Email Templates · Site Settings",
+			Data: web.Map{
+				"defaults":  defaults.Result,
+				"templates": overrides.Result,
+			},
+		})
+	}
+}
+
+// SaveEmailTemplate overrides a default email template for a locale
+func SaveEmailTemplate() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SaveEmailTemplate)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SaveEmailTemplate{
+			Name:    action.Name,
+			Locale:  action.Locale,
+			Subject: action.Subject,
+			Body:    action.Body,
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// DeleteEmailTemplate restores the default email template for a locale
+func DeleteEmailTemplate() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if err := bus.Dispatch(c, &cmd.DeleteEmailTemplate{
+			Name:   c.Param("name"),
+			Locale: c.Param("locale"),
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// PreviewEmailTemplate renders an email template with sample data
+func PreviewEmailTemplate() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.PreviewEmailTemplate)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		previewEmailTemplate := &cmd.PreviewEmailTemplate{
+			Name:    action.Name,
+			Subject: action.Subject,
+			Body:    action.Body,
+		}
+		if err := bus.Dispatch(c, previewEmailTemplate); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(previewEmailTemplate.Result)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
+)
+
+// ConfirmEmailPost creates a post that was sent by email once its sender confirms it through the link they received
+func ConfirmEmailPost() web.HandlerFunc {
+	return func(c *web.Context) error {
+		key := c.QueryParam("k")
+		result, err := validateKey(enum.EmailVerificationKindEmailPost, key, c)
+		if result == nil {
+			return err
+		}
+
+		// The post is gone if the link was already used, which happens when email clients preview it
+		getBlob := &query.GetBlobByKey{Key: inbound.PendingPostBlobKey(key)}
+		if err := bus.Dispatch(c, getBlob); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return c.Redirect(c.BaseURL())
+			}
+			return c.Failure(err)
+		}
+
+		msg := &inbound.Message{}
+		if err := json.Unmarshal(getBlob.Result.Content, msg); err != nil {
+			return c.Failure(errors.Wrap(err, "failed to decode email post"))
+		}
+		if !strings.EqualFold(msg.From, result.Email) {
+			return c.NotFound()
+		}
+
+		err = bus.Dispatch(c,
+			&cmd.SetKeyAsVerified{Key: key},
+			&cmd.DeleteBlob{Key: inbound.PendingPostBlobKey(key)},
+		)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		// Confirming the link proves that the sender owns the address
+		msg.SenderAuthenticated = true
+		c.Enqueue(tasks.ReceiveEmail(msg))
+
+		return c.Redirect(c.BaseURL())
+	}
+}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func setupPendingEmailPost(key, from string) (*bool, *[]string) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetVerificationByKey) error {
+		if q.Key == key && q.Kind == enum.EmailVerificationKindEmailPost {
+			q.Result = &entity.EmailVerification{
+				Key:       q.Key,
+				Kind:      q.Kind,
+				ExpiresAt: time.Now().Add(48 * time.Hour),
+				Email:     "jon.snow@got.com",
+			}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetBlobByKey) error {
+		if q.Key == inbound.PendingPostBlobKey(key) {
+			q.Result = &dto.Blob{
+				Content:     []byte(`{"From":"` + from + `","To":["feedback+demo@reply.test.fider.io"],"Subject":"Dark mode"}`),
+				ContentType: "application/json",
+			}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	verified := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetKeyAsVerified) error {
+		verified = c.Key == key
+		return nil
+	})
+
+	deleted := make([]string, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteBlob) error {
+		deleted = append(deleted, c.Key)
+		return nil
+	})
+
+	return &verified, &deleted
+}
+
+func TestConfirmEmailPostHandler(t *testing.T) {
+	RegisterT(t)
+	server := mock.NewServer()
+
+	key := "1234567890"
+	verified, deleted := setupPendingEmailPost(key, "Jon.Snow@got.com")
+
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/inbound/confirm?k=" + key).
+		Execute(handlers.ConfirmEmailPost())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("http://demo.test.fider.io")
+	Expect(*verified).IsTrue()
+	Expect(*deleted).Equals([]string{inbound.PendingPostBlobKey(key)})
+}
+
+func TestConfirmEmailPostHandler_SenderMismatch(t *testing.T) {
+	RegisterT(t)
+	server := mock.NewServer()
+
+	key := "1234567890"
+	verified, deleted := setupPendingEmailPost(key, "arya.stark@got.com")
+
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/inbound/confirm?k=" + key).
+		Execute(handlers.ConfirmEmailPost())
+
+	Expect(code).Equals(http.StatusNotFound)
+	Expect(*verified).IsFalse()
+	Expect(*deleted).HasLen(0)
+}
+
+func TestConfirmEmailPostHandler_UnknownKey(t *testing.T) {
+	RegisterT(t)
+	server := mock.NewServer()
+	setupPendingEmailPost("1234567890", "jon.snow@got.com")
+
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/inbound/confirm?k=0000000000").
+		Execute(handlers.ConfirmEmailPost())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
@@ -138,6 +138,16 @@ func OAuthToken() web.HandlerFunc {
 			}
 		}
 
+		if oauthUser.Result.Groups != nil {
+			err = bus.Dispatch(c, &cmd.SyncUserOAuthGroups{
+				UserID: user.ID,
+				Claims: oauthUser.Result.Groups,
+			})
+			if err != nil {
+				return c.Failure(err)
+			}
+		}
+
 		webutil.AddAuthUserCookie(c, user)
 
 		return c.Redirect(redirectURL.String())
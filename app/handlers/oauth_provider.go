@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// OAuthAuthorizePage is where users are sent by OAuth clients to authorize them
+func OAuthAuthorizePage() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := &actions.AuthorizeOAuthClient{
+			ClientID:            c.QueryParam("client_id"),
+			RedirectURI:         c.QueryParam("redirect_uri"),
+			ResponseType:        c.QueryParam("response_type"),
+			Scope:               c.QueryParam("scope"),
+			State:               c.QueryParam("state"),
+			Nonce:               c.QueryParam("nonce"),
+			CodeChallenge:       c.QueryParam("code_challenge"),
+			CodeChallengeMethod: c.QueryParam("code_challenge_method"),
+		}
+
+		if result := action.Validate(c, c.User()); !result.Ok {
+			if result.Err != nil {
+				return c.Failure(result.Err)
+			}
+
+			return c.Page(http.StatusBadRequest, web.Props{
+				Page:  "OAuthAuthorize/OAuthAuthorize.page",
+				Title: "Authorize",
+				Data: web.Map{
+					"error": result.Errors[0].Message,
+				},
+			})
+		}
+
+		if action.Error != "" {
+			return c.Redirect(action.RedirectURL(url.Values{"error": {action.Error}}))
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "OAuthAuthorize/OAuthAuthorize.page",
+			Title: "Authorize " + action.Client.Name,
+			Data: web.Map{
+				"clientName": action.Client.Name,
+				"scopes":     action.Scopes,
+				"request": web.Map{
+					"clientId":            action.ClientID,
+					"redirectUri":         action.RedirectURI,
+					"responseType":        action.ResponseType,
+					"scope":               action.Scope,
+					"state":               action.State,
+					"nonce":               action.Nonce,
+					"codeChallenge":       action.CodeChallenge,
+					"codeChallengeMethod": action.CodeChallengeMethod,
+				},
+			},
+		})
+	}
+}
+
+// AuthorizeOAuthClient records the decision of current user and returns where the user should be sent back to
+func AuthorizeOAuthClient() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.AuthorizeOAuthClient)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if action.Error != "" {
+			return c.Ok(web.Map{"redirectURL": action.RedirectURL(url.Values{"error": {action.Error}})})
+		}
+
+		if !action.Approved {
+			return c.Ok(web.Map{"redirectURL": action.RedirectURL(url.Values{"error": {"access_denied"}})})
+		}
+
+		addCode := &cmd.AddOAuthAuthorizationCode{
+			Grant: &entity.OAuthGrant{
+				ClientID:      action.Client.ID,
+				UserID:        c.User().ID,
+				RedirectURI:   action.RedirectURI,
+				Scopes:        action.Scopes,
+				CodeChallenge: action.CodeChallenge,
+				Nonce:         action.Nonce,
+			},
+		}
+		if err := bus.Dispatch(c, addCode); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{"redirectURL": action.RedirectURL(url.Values{"code": {addCode.Code}})})
+	}
+}
+
+// ExchangeOAuthToken is the OAuth token endpoint, it exchanges authorization codes and refresh tokens for access tokens
+func ExchangeOAuthToken() web.HandlerFunc {
+	return func(c *web.Context) error {
+		c.Response.Header().Set("Cache-Control", "no-store")
+
+		form, err := url.ParseQuery(c.Request.Body)
+		if err != nil {
+			return oauthError(c, http.StatusBadRequest, "invalid_request")
+		}
+
+		clientID, clientSecret := form.Get("client_id"), form.Get("client_secret")
+		if username, password, ok := c.Request.BasicAuth(); ok {
+			clientID, clientSecret = username, password
+		}
+
+		getClient := &query.GetOAuthClientByClientID{ClientID: clientID}
+		if err := bus.Dispatch(c, getClient); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return oauthError(c, http.StatusUnauthorized, "invalid_client")
+			}
+			return c.Failure(err)
+		}
+
+		client := getClient.Result
+		if client.IsConfidential() && !client.VerifySecret(clientSecret) {
+			return oauthError(c, http.StatusUnauthorized, "invalid_client")
+		}
+
+		var grant *entity.OAuthGrant
+		switch form.Get("grant_type") {
+		case "authorization_code":
+			useCode := &cmd.UseOAuthAuthorizationCode{Code: form.Get("code")}
+			if err := bus.Dispatch(c, useCode); err != nil {
+				if errors.Cause(err) == app.ErrNotFound {
+					return oauthError(c, http.StatusBadRequest, "invalid_grant")
+				}
+				return c.Failure(err)
+			}
+
+			grant = useCode.Result
+			if grant.RedirectURI != form.Get("redirect_uri") || !grant.VerifyCodeVerifier(form.Get("code_verifier")) {
+				return oauthError(c, http.StatusBadRequest, "invalid_grant")
+			}
+		case "refresh_token":
+			useToken := &cmd.UseOAuthRefreshToken{Token: form.Get("refresh_token")}
+			if err := bus.Dispatch(c, useToken); err != nil {
+				if errors.Cause(err) == app.ErrNotFound {
+					return oauthError(c, http.StatusBadRequest, "invalid_grant")
+				}
+				return c.Failure(err)
+			}
+
+			grant = useToken.Result
+		default:
+			return oauthError(c, http.StatusBadRequest, "unsupported_grant_type")
+		}
+
+		if grant.ClientID != client.ID {
+			return oauthError(c, http.StatusBadRequest, "invalid_grant")
+		}
+
+		getUser := &query.GetUserByID{UserID: grant.UserID}
+		if err := bus.Dispatch(c, getUser); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return oauthError(c, http.StatusBadRequest, "invalid_grant")
+			}
+			return c.Failure(err)
+		}
+
+		user := getUser.Result
+		if user.Tenant.ID != c.Tenant().ID || user.Status != enum.UserActive {
+			return oauthError(c, http.StatusBadRequest, "invalid_grant")
+		}
+
+		now := time.Now()
+		accessToken, err := jwt.Encode(jwt.OAuthAccessTokenClaims{
+			UserID:   user.ID,
+			ClientID: client.ClientID,
+			Scopes:   grant.Scopes,
+			Metadata: jwt.Metadata{
+				Issuer:    c.BaseURL(),
+				Subject:   strconv.Itoa(user.ID),
+				IssuedAt:  jwt.Time(now),
+				ExpiresAt: jwt.Time(now.Add(entity.OAuthAccessTokenLifetime)),
+			},
+		})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		addRefreshToken := &cmd.AddOAuthRefreshToken{Grant: grant}
+		if err := bus.Dispatch(c, addRefreshToken); err != nil {
+			return c.Failure(err)
+		}
+
+		response := web.Map{
+			"access_token":  accessToken,
+			"token_type":    "Bearer",
+			"expires_in":    int(entity.OAuthAccessTokenLifetime.Seconds()),
+			"refresh_token": addRefreshToken.Token,
+			"scope":         strings.Join(grant.Scopes, " "),
+		}
+
+		// ID tokens are signed with the key published on the JWKS endpoint, so they're only issued when one is configured
+		// Clients can use the userinfo endpoint otherwise
+		signingKey, err := oauthSigningKey()
+		if err != nil {
+			return c.Failure(err)
+		}
+		if slices.Contains(grant.Scopes, entity.OAuthScopeOpenID) && signingKey != nil {
+			claims := jwt.IDTokenClaims{
+				Nonce: grant.Nonce,
+				Metadata: jwt.Metadata{
+					Issuer:    c.BaseURL(),
+					Subject:   strconv.Itoa(user.ID),
+					Audience:  []string{client.ClientID},
+					IssuedAt:  jwt.Time(now),
+					ExpiresAt: jwt.Time(now.Add(entity.OAuthAccessTokenLifetime)),
+				},
+			}
+			if slices.Contains(grant.Scopes, entity.OAuthScopeProfile) {
+				claims.Name = user.Name
+				claims.Picture = user.AvatarURL
+			}
+			if slices.Contains(grant.Scopes, entity.OAuthScopeEmail) {
+				claims.Email = user.Email
+			}
+
+			idToken, err := jwt.EncodeWithKey(claims, signingKey)
+			if err != nil {
+				return c.Failure(err)
+			}
+			response["id_token"] = idToken
+		}
+
+		return c.Ok(response)
+	}
+}
+
+// OAuthUserInfo returns the profile of the user an OAuth access token was issued to
+func OAuthUserInfo() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user := c.User()
+		if user == nil {
+			c.Response.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			return oauthError(c, http.StatusUnauthorized, "invalid_token")
+		}
+
+		// Other credentials belong to the user themselves, so they can see their whole profile
+		scopes := []string{entity.OAuthScopeProfile, entity.OAuthScopeEmail}
+		if token := strings.TrimSpace(strings.TrimPrefix(c.Request.GetHeader("Authorization"), "Bearer")); token != "" {
+			if claims, err := jwt.DecodeOAuthAccessTokenClaims(token); err == nil {
+				scopes = claims.Scopes
+			}
+		}
+
+		info := web.Map{"sub": strconv.Itoa(user.ID)}
+		if slices.Contains(scopes, entity.OAuthScopeProfile) {
+			info["name"] = user.Name
+			info["picture"] = user.AvatarURL
+		}
+		if slices.Contains(scopes, entity.OAuthScopeEmail) && user.Email != "" {
+			info["email"] = user.Email
+			info["email_verified"] = true
+		}
+
+		return c.Ok(info)
+	}
+}
+
+// OpenIDConfiguration returns the OpenID Connect discovery document of current site
+func OpenIDConfiguration() web.HandlerFunc {
+	return func(c *web.Context) error {
+		baseURL := c.BaseURL()
+		return c.Ok(web.Map{
+			"issuer":                                baseURL,
+			"authorization_endpoint":                baseURL + "/oauth2/authorize",
+			"token_endpoint":                        baseURL + "/oauth2/token",
+			"userinfo_endpoint":                     baseURL + "/api/v1/userinfo",
+			"scopes_supported":                      entity.AllOAuthScopes,
+			"response_types_supported":              []string{"code"},
+			"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+			"code_challenge_methods_supported":      []string{"S256"},
+			"subject_types_supported":               []string{"public"},
+			"jwks_uri":                              baseURL + "/oauth2/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+			"claims_supported":                      []string{"sub", "name", "picture", "email", "email_verified", "nonce"},
+		})
+	}
+}
+
+// OAuthJWKS returns the public keys that ID tokens issued by this site are signed with
+func OAuthJWKS() web.HandlerFunc {
+	return func(c *web.Context) error {
+		signingKey, err := oauthSigningKey()
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		keys := make([]map[string]any, 0)
+		if signingKey != nil {
+			keys = append(keys, signingKey.JWK())
+		}
+		return c.Ok(web.Map{"keys": keys})
+	}
+}
+
+func oauthSigningKey() (*jwt.SigningKey, error) {
+	if env.Config.OAuthProvider.SigningKey == "" {
+		return nil, nil
+	}
+	return jwt.ParseSigningKey(env.Config.OAuthProvider.SigningKey)
+}
+
+func oauthError(c *web.Context, status int, code string) error {
+	return c.JSON(status, web.Map{"error": code})
+}
+
+// ManageOAuthClients is the page used by administrators to register applications that sign users in through this site
+func ManageOAuthClients() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getClients := &query.GetAllOAuthClients{}
+		if err := bus.Dispatch(c, getClients); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageOAuthClients.page",
+			Title: "OAuth Apps · Site Settings",
+			Data: web.Map{
+				"clients": getClients.Result,
+			},
+		})
+	}
+}
+
+// CreateOAuthClient registers a new OAuth client, the secret is only returned in full once
+func CreateOAuthClient() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.CreateOAuthClient)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		addNewClient := &cmd.AddNewOAuthClient{
+			Name:           action.Name,
+			RedirectURIs:   action.RedirectURIs,
+			IsConfidential: action.IsConfidential,
+		}
+		if err := bus.Dispatch(c, addNewClient); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"client":       addNewClient.Result,
+			"clientSecret": addNewClient.Secret,
+		})
+	}
+}
+
+// RevokeOAuthClient revokes an OAuth client along with every refresh token issued to it
+func RevokeOAuthClient() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RevokeOAuthClient)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.RevokeOAuthClient{ID: action.Client.ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
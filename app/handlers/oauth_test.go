@@ -41,6 +41,34 @@ func TestSignOutHandler(t *testing.T) {
 	Expect(response.Header().Get("Set-Cookie")).ContainsSubstring("Max-Age=0; HttpOnly")
 }
 
+func TestSignOutHandler_RevokesSession(t *testing.T) {
+	RegisterT(t)
+
+	var revoked *cmd.RevokeUserSession
+	bus.AddHandler(func(ctx context.Context, c *cmd.RevokeUserSession) error {
+		revoked = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		WithURL("http://demo.test.fider.io/signout").
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		Use(func(next web.HandlerFunc) web.HandlerFunc {
+			return func(c *web.Context) error {
+				c.SetUserSessionID(42)
+				return next(c)
+			}
+		}).
+		Execute(handlers.SignOut())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(revoked).IsNotNil()
+	Expect(revoked.UserID).Equals(mock.JonSnow.ID)
+	Expect(revoked.SessionID).Equals(42)
+}
+
 func TestSignInByOAuthHandler_RootRedirect(t *testing.T) {
 	RegisterT(t)
 	bus.Init(&oauth.Service{})
@@ -270,6 +298,7 @@ func TestCallbackHandler_SignUp(t *testing.T) {
 
 func TestOAuthTokenHandler_ExistingUserAndProvider(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	oauthUser := &dto.OAuthUserProfile{
 		ID:    "FB123",
@@ -309,6 +338,7 @@ func TestOAuthTokenHandler_ExistingUserAndProvider(t *testing.T) {
 
 func TestOAuthTokenHandler_NewUser(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	var registeredUser *entity.User
 	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
@@ -363,6 +393,7 @@ func TestOAuthTokenHandler_NewUser(t *testing.T) {
 
 func TestOAuthTokenHandler_NewUserWithoutEmail(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	var newUser *entity.User
@@ -415,6 +446,7 @@ func TestOAuthTokenHandler_NewUserWithoutEmail(t *testing.T) {
 
 func TestOAuthTokenHandler_ExistingUser_WithoutEmail(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	user := &entity.User{
 		ID:     3,
@@ -468,6 +500,7 @@ func TestOAuthTokenHandler_ExistingUser_WithoutEmail(t *testing.T) {
 
 func TestOAuthTokenHandler_ExistingUser_NewProvider(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	var newProvider *entity.UserProvider
 	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUserProvider) error {
@@ -561,6 +594,7 @@ func TestOAuthTokenHandler_NewUser_PrivateSite(t *testing.T) {
 
 func TestOAuthTokenHandler_NewUser_PrivateSite_UsingTrustedProvider(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	mock.AvengersTenant.IsPrivate = true
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/csv"
@@ -37,6 +38,7 @@ func Index() web.HandlerFunc {
 		}
 
 		searchPosts.SetStatusesFromStrings(c.QueryParamAsArray("statuses"))
+		searchPosts.SetCustomFieldsFromQuery(c.Request.URL.Query())
 		getAllTags := &query.GetAllTags{}
 		countPerStatus := &query.CountPostPerStatus{}
 
@@ -84,25 +86,39 @@ func PostDetails() web.HandlerFunc {
 		}
 
 		isSubscribed := &query.UserSubscribedTo{PostID: getPost.Result.ID}
+		isCommentsMuted := &query.UserMutedCommentsOn{PostID: getPost.Result.ID}
 		getComments := &query.GetCommentsByPost{Post: getPost.Result}
 		getAllTags := &query.GetAllTags{}
 		listVotes := &query.ListPostVotes{PostID: getPost.Result.ID, Limit: 24, IncludeEmail: false}
 		getAttachments := &query.GetAttachments{Post: getPost.Result}
-		if err := bus.Dispatch(c, getAllTags, getComments, listVotes, isSubscribed, getAttachments); err != nil {
+		getAllCustomFields := &query.GetAllCustomFields{}
+		if err := bus.Dispatch(c, getAllTags, getComments, listVotes, isSubscribed, isCommentsMuted, getAttachments, getAllCustomFields); err != nil {
 			return c.Failure(err)
 		}
 
+		groups := make([]*entity.UserGroup, 0)
+		if c.User() != nil && c.User().IsCollaborator() {
+			getAllGroups := &query.GetAllUserGroups{}
+			if err := bus.Dispatch(c, getAllGroups); err != nil {
+				return c.Failure(err)
+			}
+			groups = getAllGroups.Result
+		}
+
 		return c.Page(http.StatusOK, web.Props{
 			Page:        "ShowPost/ShowPost.page",
 			Title:       getPost.Result.Title,
 			Description: markdown.PlainText(getPost.Result.Description),
 			Data: web.Map{
-				"comments":    getComments.Result,
-				"subscribed":  isSubscribed.Result,
-				"post":        getPost.Result,
-				"tags":        getAllTags.Result,
-				"votes":       listVotes.Result,
-				"attachments": getAttachments.Result,
+				"comments":      getComments.Result,
+				"subscribed":    isSubscribed.Result,
+				"commentsMuted": isCommentsMuted.Result,
+				"post":          getPost.Result,
+				"tags":          getAllTags.Result,
+				"votes":         listVotes.Result,
+				"attachments":   getAttachments.Result,
+				"customFields":  getAllCustomFields.Result,
+				"groups":        groups,
 			},
 		})
 	}
@@ -113,11 +129,12 @@ func ExportPostsToCSV() web.HandlerFunc {
 	return func(c *web.Context) error {
 
 		allPosts := &query.GetAllPosts{}
-		if err := bus.Dispatch(c, allPosts); err != nil {
+		allFields := &query.GetAllCustomFields{}
+		if err := bus.Dispatch(c, allPosts, allFields); err != nil {
 			return c.Failure(err)
 		}
 
-		bytes, err := csv.FromPosts(allPosts.Result)
+		bytes, err := csv.FromPosts(allPosts.Result, allFields.Result)
 		if err != nil {
 			return c.Failure(err)
 		}
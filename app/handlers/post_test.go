@@ -69,6 +69,18 @@ func TestDetailsHandler(t *testing.T) {
 		return nil
 	})
 
+	bus.AddHandler(func(ctx context.Context, q *query.UserMutedCommentsOn) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllCustomFields) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUserGroups) error {
+		return nil
+	})
+
 	server := mock.NewServer()
 
 	code, _ := server.
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ServiceWorker returns the script that receives push notifications on the browser
+// It's served from the root path, as browsers limit a service worker to the path it's served from
+func ServiceWorker() web.HandlerFunc {
+	return func(c *web.Context) error {
+		bytes, err := os.ReadFile(env.Path("./service-worker.js"))
+		if err != nil {
+			return c.NotFound()
+		}
+		c.Response.Header().Set("Cache-Control", "no-cache")
+		return c.Blob(http.StatusOK, "application/javascript; charset=utf-8", bytes)
+	}
+}
+
+// SavePushSubscription registers the current browser to receive push notifications
+func SavePushSubscription() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if !env.IsWebPushEnabled() {
+			return c.NotFound()
+		}
+
+		action := new(actions.SavePushSubscription)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SavePushSubscription{
+			Endpoint:  action.Endpoint,
+			P256dh:    action.Keys.P256dh,
+			Auth:      action.Keys.Auth,
+			UserAgent: c.Request.GetHeader("User-Agent"),
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// DeletePushSubscription stops sending push notifications to the current browser
+func DeletePushSubscription() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DeletePushSubscription)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.DeletePushSubscription{Endpoint: action.Endpoint}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/realtime"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// keepAliveInterval is how often a comment is sent on idle streams, so that proxies don't close them
+const keepAliveInterval = 25 * time.Second
+
+// EventStream streams the events of current tenant that current user is allowed to see as Server-Sent Events
+func EventStream() web.HandlerFunc {
+	return func(c *web.Context) error {
+		// The stream can stay open for hours, so it must not hold a database connection nor be cut by the write timeout
+		// Browsers reconnect by themselves if the server doesn't support clearing the deadline
+		if err := c.Commit(); err != nil {
+			return c.Failure(err)
+		}
+		_ = http.NewResponseController(c.Response.Writer).SetWriteDeadline(time.Time{})
+
+		sub := realtime.Subscribe(c.Tenant().ID)
+		defer sub.Close()
+
+		w := &c.Response
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := fmt.Fprint(w, "retry: 5000\n\n"); err != nil {
+			return nil
+		}
+		w.Flush()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		user := c.User()
+		sessionID := c.UserSessionID()
+		for {
+			select {
+			case <-c.Done():
+				return nil
+			case <-keepAlive.C:
+				// The user was authenticated when the stream was opened, so it's closed once they are signed out or blocked
+				if user != nil {
+					refreshed, err := refreshStreamUser(c, user.ID, sessionID)
+					if err != nil {
+						return c.Failure(err)
+					}
+					if refreshed == nil {
+						return nil
+					}
+					user = refreshed
+				}
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return nil
+				}
+			case e, ok := <-sub.Events():
+				if !ok {
+					return nil
+				}
+				if !e.IsVisibleTo(user) {
+					continue
+				}
+				data, err := json.Marshal(e.Data)
+				if err != nil {
+					return c.Failure(errors.Wrap(err, "failed to encode event '%s'", e.Type))
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+					return nil
+				}
+			}
+			w.Flush()
+		}
+	}
+}
+
+// refreshStreamUser returns the latest state of given user, or nil if they are no longer active or their session was revoked
+// The transaction of the request was already committed, so a new one is used for every check
+func refreshStreamUser(c *web.Context, userID, sessionID int) (*entity.User, error) {
+	trx, err := dbx.BeginTx(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed start new transaction")
+	}
+	defer trx.MustRollback()
+	ctx := context.WithValue(c, app.TransactionCtxKey, trx)
+
+	getUser := &query.GetUserByID{UserID: userID}
+	if err := bus.Dispatch(ctx, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if getUser.Result.Status != enum.UserActive {
+		return nil, nil
+	}
+
+	// Users authenticated by other means than a session cookie have no session to check
+	if sessionID > 0 {
+		getSessions := &query.GetUserSessions{UserID: userID}
+		if err := bus.Dispatch(ctx, getSessions); err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(getSessions.Result, func(session *entity.UserSession) bool { return session.ID == sessionID }) {
+			return nil, nil
+		}
+	}
+
+	return getUser.Result, nil
+}
@@ -0,0 +1,56 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/handlers"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/realtime"
+)
+
+func TestEventStreamHandler(t *testing.T) {
+	RegisterT(t)
+	server := mock.NewServer()
+
+	// Keep broadcasting until the handler has subscribed, then end the stream
+	go func() {
+		for i := 0; i < 20; i++ {
+			realtime.Broadcast(&realtime.Event{
+				TenantID: mock.DemoTenant.ID,
+				Type:     realtime.PostVotesChanged,
+				Data:     map[string]any{"postNumber": 1, "votesCount": 10},
+				Post:     &realtime.PostVisibility{AuthorID: mock.JonSnow.ID},
+			})
+			realtime.Broadcast(&realtime.Event{
+				TenantID: mock.DemoTenant.ID,
+				Type:     realtime.PostVotesChanged,
+				Data:     map[string]any{"postNumber": 2, "votesCount": 20},
+				Post:     &realtime.PostVisibility{AuthorID: mock.JonSnow.ID, IsPrivate: true},
+			})
+			realtime.Broadcast(&realtime.Event{
+				TenantID: mock.AvengersTenant.ID,
+				Type:     realtime.PostVotesChanged,
+				Data:     map[string]any{"postNumber": 3, "votesCount": 30},
+			})
+			time.Sleep(10 * time.Millisecond)
+		}
+		realtime.Shutdown()
+	}()
+
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		Execute(handlers.EventStream())
+
+	body := response.Body.String()
+	Expect(code).Equals(http.StatusOK)
+	Expect(response.Header().Get("Content-Type")).Equals("text/event-stream")
+	Expect(strings.HasPrefix(body, "retry: 5000\n\n")).IsTrue()
+	Expect(body).ContainsSubstring("event: post.votes\ndata: {\"postNumber\":1,\"votesCount\":10}\n\n")
+	Expect(strings.Contains(body, `"postNumber":2`)).IsFalse()
+	Expect(strings.Contains(body, `"postNumber":3`)).IsFalse()
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+	webutil "github.com/getfider/fider/app/pkg/web/util"
+)
+
+// SAMLMetadata returns the SAML service provider metadata of current tenant
+func SAMLMetadata() web.HandlerFunc {
+	return func(c *web.Context) error {
+		metadata := &query.GetSAMLServiceProviderMetadata{}
+		if err := bus.Dispatch(c, metadata); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Blob(http.StatusOK, "application/samlmetadata+xml", []byte(metadata.Result))
+	}
+}
+
+// SignInBySAML redirects the user to the SAML identity provider (SP-initiated sign in)
+func SignInBySAML() web.HandlerFunc {
+	return func(c *web.Context) error {
+		c.Response.Header().Add("X-Robots-Tag", "noindex")
+
+		redirect := samlRedirectURL(c, c.QueryParam("redirect"))
+
+		if c.IsAuthenticated() {
+			return c.Redirect(redirect)
+		}
+
+		authnRequest := &query.GetSAMLAuthnRequestURL{Redirect: redirect}
+		if err := bus.Dispatch(c, authnRequest); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return c.NotFound()
+			}
+			return c.Failure(err)
+		}
+
+		return c.Redirect(authnRequest.Result)
+	}
+}
+
+// SAMLAssertionConsumer receives the SAML response posted by the identity provider, for both SP-initiated and IdP-initiated sign in
+// The asserted user is then used to either get an existing user on Fider or creating a new one
+func SAMLAssertionConsumer() web.HandlerFunc {
+	return func(c *web.Context) error {
+		form, err := url.ParseQuery(c.Request.Body)
+		if err != nil || form.Get("SAMLResponse") == "" {
+			return c.BadRequest(web.Map{})
+		}
+
+		samlUser := &query.ParseSAMLResponse{
+			SAMLResponse: form.Get("SAMLResponse"),
+			RelayState:   form.Get("RelayState"),
+		}
+		if err := bus.Dispatch(c, samlUser); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return c.NotFound()
+			}
+			log.Warnf(c, "Invalid SAML response: @{Error}", dto.Props{"Error": err.Error()})
+			return c.Forbidden()
+		}
+
+		profile := samlUser.Result
+		var user *entity.User
+
+		userByProvider := &query.GetUserByProvider{Provider: app.SAMLProvider, UID: profile.ID}
+		err = bus.Dispatch(c, userByProvider)
+		user = userByProvider.Result
+
+		if errors.Cause(err) == app.ErrNotFound && profile.Email != "" {
+			userByEmail := &query.GetUserByEmail{Email: profile.Email}
+			err = bus.Dispatch(c, userByEmail)
+			user = userByEmail.Result
+		}
+		if err != nil {
+			if errors.Cause(err) != app.ErrNotFound {
+				return c.Failure(err)
+			}
+
+			// The identity provider is trusted by the administrator, so private sites accept its users as well
+			role := enum.RoleVisitor
+			if profile.Role != 0 {
+				role = profile.Role
+			}
+
+			user = &entity.User{
+				Name:   profile.Name,
+				Tenant: c.Tenant(),
+				Email:  profile.Email,
+				Role:   role,
+				Providers: []*entity.UserProvider{
+					{
+						UID:  profile.ID,
+						Name: app.SAMLProvider,
+					},
+				},
+			}
+
+			if err = bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
+				return c.Failure(err)
+			}
+		} else {
+			if !user.HasProvider(app.SAMLProvider) {
+				if err = bus.Dispatch(c, &cmd.RegisterUserProvider{
+					UserID:       user.ID,
+					ProviderName: app.SAMLProvider,
+					ProviderUID:  profile.ID,
+				}); err != nil {
+					return c.Failure(err)
+				}
+			}
+
+			if profile.Role != 0 && profile.Role != user.Role {
+				if err = bus.Dispatch(c, &cmd.ChangeUserRole{UserID: user.ID, Role: profile.Role}); err != nil {
+					return c.Failure(err)
+				}
+				user.Role = profile.Role
+			}
+		}
+
+		webutil.AddAuthUserCookie(c, user)
+
+		return c.Redirect(samlRedirectURL(c, profile.Redirect))
+	}
+}
+
+// samlRedirectURL returns the URL of current site that given redirect, either a path or a full URL, points to
+func samlRedirectURL(c *web.Context, redirect string) string {
+	path := safeRedirectPath(strings.TrimPrefix(redirect, c.BaseURL()))
+	if path == "/" {
+		return c.BaseURL()
+	}
+	return c.BaseURL() + path
+}
+
+// ManageSAMLConfig is the page used by administrators to configure SAML single sign-on
+func ManageSAMLConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getConfig := &query.GetSAMLConfig{}
+		err := bus.Dispatch(c, getConfig)
+		if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageSAML.page",
+			Title: "SAML Single Sign-On · Site Settings",
+			Data: web.Map{
+				"config":      getConfig.Result,
+				"entityID":    c.BaseURL() + "/saml/metadata",
+				"metadataURL": c.BaseURL() + "/saml/metadata",
+				"acsURL":      c.BaseURL() + "/saml/acs",
+			},
+		})
+	}
+}
+
+// SaveSAMLConfig is used to configure SAML single sign-on of current tenant
+func SaveSAMLConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SaveSAMLConfig)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.SaveSAMLConfig{
+			Status:                 action.Status,
+			DisplayName:            action.DisplayName,
+			IdPMetadata:            action.IdPMetadata,
+			IdPEntityID:            action.IdPEntityID,
+			IdPSSOURL:              action.IdPSSOURL,
+			AttributeName:          action.AttributeName,
+			AttributeEmail:         action.AttributeEmail,
+			AttributeRole:          action.AttributeRole,
+			AdministratorRoleValue: action.AdministratorRoleValue,
+			CollaboratorRoleValue:  action.CollaboratorRoleValue,
+			RequireSSO:             action.RequireSSO,
+			AllowIdPInitiated:      action.AllowIdPInitiated,
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
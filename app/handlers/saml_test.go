@@ -0,0 +1,222 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func samlForm(response, relayState string) string {
+	return url.Values{"SAMLResponse": {response}, "RelayState": {relayState}}.Encode()
+}
+
+func TestSignInBySAMLHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLAuthnRequestURL) error {
+		Expect(q.Redirect).Equals("http://demo.test.fider.io/posts/1")
+		q.Result = "https://idp.test/sso?SAMLRequest=abc"
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/login?redirect=http://demo.test.fider.io/posts/1").
+		Execute(handlers.SignInBySAML())
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("https://idp.test/sso?SAMLRequest=abc")
+}
+
+func TestSignInBySAMLHandler_EvilRedirect(t *testing.T) {
+	RegisterT(t)
+
+	redirects := make([]string, 0)
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLAuthnRequestURL) error {
+		redirects = append(redirects, q.Redirect)
+		q.Result = "https://idp.test/sso?SAMLRequest=abc"
+		return nil
+	})
+
+	for _, redirect := range []string{"http://evil.io", "http://demo.test.fider.io.evil.io/posts", "//evil.io", "/\\evil.io"} {
+		code, _ := mock.NewServer().
+			OnTenant(mock.DemoTenant).
+			WithURL("http://demo.test.fider.io/saml/login?redirect=" + url.QueryEscape(redirect)).
+			Execute(handlers.SignInBySAML())
+
+		Expect(code).Equals(http.StatusTemporaryRedirect)
+	}
+	Expect(redirects).Equals([]string{
+		"http://demo.test.fider.io",
+		"http://demo.test.fider.io",
+		"http://demo.test.fider.io",
+		"http://demo.test.fider.io",
+	})
+}
+
+func TestSignInBySAMLHandler_NotConfigured(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLAuthnRequestURL) error {
+		return errors.Wrap(app.ErrNotFound, "failed to get SAML config")
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/login").
+		Execute(handlers.SignInBySAML())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestSAMLAssertionConsumerHandler_InvalidResponse(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.ParseSAMLResponse) error {
+		return errors.New("signature validation failed")
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/acs").
+		ExecutePost(handlers.SAMLAssertionConsumer(), samlForm("PHNhbWw+", ""))
+
+	Expect(code).Equals(http.StatusForbidden)
+	Expect(response.Header().Get("Set-Cookie")).Equals("")
+}
+
+func TestSAMLAssertionConsumerHandler_ExistingUser_UpdatesRole(t *testing.T) {
+	RegisterT(t)
+	mockCreateUserSession()
+
+	bus.AddHandler(func(ctx context.Context, q *query.ParseSAMLResponse) error {
+		Expect(q.SAMLResponse).Equals("PHNhbWw+")
+		Expect(q.RelayState).Equals("some-state")
+		q.Result = &dto.SAMLUserProfile{
+			ID:       "00u1arya",
+			Name:     "Arya Stark",
+			Email:    "arya.stark@got.com",
+			Role:     enum.RoleCollaborator,
+			Redirect: "http://demo.test.fider.io/posts/1",
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		Expect(q.Provider).Equals(app.SAMLProvider)
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		Expect(q.Email).Equals("arya.stark@got.com")
+		q.Result = &entity.User{ID: mock.AryaStark.ID, Name: mock.AryaStark.Name, Email: mock.AryaStark.Email, Tenant: mock.DemoTenant, Role: enum.RoleVisitor}
+		return nil
+	})
+
+	var registeredProvider *cmd.RegisterUserProvider
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUserProvider) error {
+		registeredProvider = c
+		return nil
+	})
+
+	var changedRole *cmd.ChangeUserRole
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserRole) error {
+		changedRole = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/acs").
+		ExecutePost(handlers.SAMLAssertionConsumer(), samlForm("PHNhbWw+", "some-state"))
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("http://demo.test.fider.io/posts/1")
+	Expect(registeredProvider.UserID).Equals(mock.AryaStark.ID)
+	Expect(registeredProvider.ProviderName).Equals(app.SAMLProvider)
+	Expect(registeredProvider.ProviderUID).Equals("00u1arya")
+	Expect(changedRole.UserID).Equals(mock.AryaStark.ID)
+	Expect(changedRole.Role).Equals(enum.RoleCollaborator)
+	ExpectFiderAuthCookie(response, mock.AryaStark)
+}
+
+func TestSAMLAssertionConsumerHandler_NewUser(t *testing.T) {
+	RegisterT(t)
+	mockCreateUserSession()
+
+	bus.AddHandler(func(ctx context.Context, q *query.ParseSAMLResponse) error {
+		q.Result = &dto.SAMLUserProfile{
+			ID:       "00u1sansa",
+			Name:     "Sansa Stark",
+			Email:    "sansa.stark@got.com",
+			Redirect: "https://evil.io",
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByProvider) error {
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	var registeredUser *entity.User
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 99
+		registeredUser = c.User
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/acs").
+		ExecutePost(handlers.SAMLAssertionConsumer(), samlForm("PHNhbWw+", ""))
+
+	Expect(code).Equals(http.StatusTemporaryRedirect)
+	Expect(response.Header().Get("Location")).Equals("http://demo.test.fider.io")
+	Expect(registeredUser.Name).Equals("Sansa Stark")
+	Expect(registeredUser.Email).Equals("sansa.stark@got.com")
+	Expect(registeredUser.Role).Equals(enum.RoleVisitor)
+	Expect(registeredUser.Providers[0].Name).Equals(app.SAMLProvider)
+	Expect(registeredUser.Providers[0].UID).Equals("00u1sansa")
+	ExpectFiderAuthCookie(response, registeredUser)
+}
+
+func TestSAMLMetadataHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLServiceProviderMetadata) error {
+		q.Result = "<EntityDescriptor/>"
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/saml/metadata").
+		Execute(handlers.SAMLMetadata())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(response.Header().Get("Content-Type")).Equals("application/samlmetadata+xml")
+	Expect(response.Body.String()).Equals("<EntityDescriptor/>")
+}
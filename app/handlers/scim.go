@@ -0,0 +1,706 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/scim"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// scimProvider is the user provider that holds the externalId assigned by the identity provider
+const scimProvider = "scim"
+
+// scimUserFilters are the attributes users can be filtered by
+var scimUserFilters = []string{"username", "emails", "emails.value", "externalid", "id"}
+
+// scimMaxResults is the page size used when the client doesn't ask for one
+const scimMaxResults = 100
+
+// Roles are exposed as groups, so identity providers can grant them through group membership
+var scimGroups = []struct {
+	ID   string
+	Name string
+	Role enum.Role
+}{
+	{"administrators", "Administrators", enum.RoleAdministrator},
+	{"collaborators", "Collaborators", enum.RoleCollaborator},
+}
+
+// SCIMServiceProviderConfig describes which SCIM features are supported
+func SCIMServiceProviderConfig() web.HandlerFunc {
+	return func(c *web.Context) error {
+		return scim.Respond(c, http.StatusOK, web.Map{
+			"schemas":        []string{scim.ServiceProviderConfigSchema},
+			"patch":          web.Map{"supported": true},
+			"bulk":           web.Map{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+			"filter":         web.Map{"supported": true, "maxResults": scimMaxResults},
+			"changePassword": web.Map{"supported": false},
+			"sort":           web.Map{"supported": false},
+			"etag":           web.Map{"supported": false},
+			"authenticationSchemes": []web.Map{{
+				"type":        "oauthbearertoken",
+				"name":        "API Token",
+				"description": "An API token with the 'scim' scope created by an administrator",
+				"primary":     true,
+			}},
+			"meta": scim.Meta{ResourceType: "ServiceProviderConfig", Location: c.BaseURL() + "/scim/v2/ServiceProviderConfig"},
+		})
+	}
+}
+
+// SCIMResourceTypes lists the resource types that can be provisioned
+func SCIMResourceTypes() web.HandlerFunc {
+	return func(c *web.Context) error {
+		resourceTypes := []web.Map{
+			{
+				"schemas":  []string{scim.ResourceTypeSchema},
+				"id":       "User",
+				"name":     "User",
+				"endpoint": "/Users",
+				"schema":   scim.UserSchema,
+				"meta":     scim.Meta{ResourceType: "ResourceType", Location: c.BaseURL() + "/scim/v2/ResourceTypes/User"},
+			},
+			{
+				"schemas":  []string{scim.ResourceTypeSchema},
+				"id":       "Group",
+				"name":     "Group",
+				"endpoint": "/Groups",
+				"schema":   scim.GroupSchema,
+				"meta":     scim.Meta{ResourceType: "ResourceType", Location: c.BaseURL() + "/scim/v2/ResourceTypes/Group"},
+			},
+		}
+		return scim.Respond(c, http.StatusOK, scim.NewListResponse(resourceTypes, 1, len(resourceTypes)))
+	}
+}
+
+// SCIMListUsers returns the users of current tenant matching the filter
+func SCIMListUsers() web.HandlerFunc {
+	return func(c *web.Context) error {
+		allUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, allUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		users := allUsers.Result
+		if filterExpr := c.QueryParam("filter"); filterExpr != "" {
+			filter, err := scim.ParseFilter(filterExpr)
+			if err != nil {
+				return scimFailure(c, err)
+			}
+
+			if !slices.Contains(scimUserFilters, strings.ToLower(filter.Attribute)) {
+				return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidFilter, fmt.Sprintf("Filtering by '%s' is not supported", filter.Attribute))
+			}
+
+			users = make([]*entity.User, 0)
+			for _, user := range allUsers.Result {
+				if scimUserMatches(user, filter) {
+					users = append(users, user)
+				}
+			}
+		}
+
+		resources := make([]*scim.User, len(users))
+		for i, user := range users {
+			resources[i] = toSCIMUser(c, user)
+		}
+
+		startIndex, count := scimPagination(c)
+		return scim.Respond(c, http.StatusOK, scim.NewListResponse(resources, startIndex, count))
+	}
+}
+
+// SCIMGetUser returns a single user
+func SCIMGetUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getSCIMUser(c, c.Param("id"))
+		if err != nil {
+			return scimFailure(c, err)
+		}
+		return scim.Respond(c, http.StatusOK, toSCIMUser(c, user))
+	}
+}
+
+// SCIMCreateUser provisions a new user
+func SCIMCreateUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		input := new(scim.User)
+		if err := scim.Decode(c, input); err != nil {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidSyntax, "Request body is not a valid User")
+		}
+
+		email := input.PrimaryEmail()
+		if err := validateSCIMEmail(c, email); err != nil {
+			return scimFailure(c, err)
+		}
+
+		getByEmail := &query.GetUserByEmail{Email: email}
+		err := bus.Dispatch(c, getByEmail)
+		if err == nil {
+			return scim.RespondError(c, http.StatusConflict, scim.ErrorUniqueness, fmt.Sprintf("A user with email '%s' already exists", email))
+		} else if errors.Cause(err) != app.ErrNotFound {
+			return c.Failure(err)
+		}
+
+		name := input.FullName()
+		if name == "" {
+			name = strings.Split(email, "@")[0]
+		}
+		if err := validateSCIMName(name); err != nil {
+			return scimFailure(c, err)
+		}
+
+		user := &entity.User{
+			Tenant: c.Tenant(),
+			Name:   name,
+			Email:  email,
+			Role:   enum.RoleVisitor,
+			Status: enum.UserActive,
+		}
+		if input.ExternalID != "" {
+			user.Providers = []*entity.UserProvider{{Name: scimProvider, UID: input.ExternalID}}
+		}
+
+		if err := bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
+			return c.Failure(err)
+		}
+
+		if input.Active != nil && !*input.Active {
+			if err := bus.Dispatch(c, &cmd.BlockUser{UserID: user.ID}); err != nil {
+				return c.Failure(err)
+			}
+			user.Status = enum.UserBlocked
+		}
+
+		return scim.Respond(c, http.StatusCreated, toSCIMUser(c, user))
+	}
+}
+
+// SCIMReplaceUser updates a user with the attributes sent by the identity provider
+func SCIMReplaceUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getSCIMUser(c, c.Param("id"))
+		if err != nil {
+			return scimFailure(c, err)
+		}
+
+		input := new(scim.User)
+		if err := scim.Decode(c, input); err != nil {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidSyntax, "Request body is not a valid User")
+		}
+
+		return saveSCIMUser(c, user, input)
+	}
+}
+
+// SCIMPatchUser applies a list of changes to a user
+func SCIMPatchUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getSCIMUser(c, c.Param("id"))
+		if err != nil {
+			return scimFailure(c, err)
+		}
+
+		patch := new(scim.PatchRequest)
+		if err := scim.Decode(c, patch); err != nil {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidSyntax, "Request body is not a valid PatchOp")
+		}
+
+		input := toSCIMUser(c, user)
+		for _, op := range patch.Operations {
+			if err := scim.ApplyUserPatch(input, op); err != nil {
+				return scimFailure(c, err)
+			}
+		}
+
+		return saveSCIMUser(c, user, input)
+	}
+}
+
+// SCIMDeleteUser removes a user and all personal data, the same way members can delete their own account
+func SCIMDeleteUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		user, err := getSCIMUser(c, c.Param("id"))
+		if err != nil {
+			return scimFailure(c, err)
+		}
+
+		if user.ID == c.User().ID {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorMutability, "The owner of the API token can't be deleted")
+		}
+
+		if err := bus.Dispatch(c, &cmd.DeleteUser{UserID: user.ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// SCIMListGroups returns the groups that represent the staff roles
+func SCIMListGroups() web.HandlerFunc {
+	return func(c *web.Context) error {
+		allUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, allUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		var filter *scim.Filter
+		if filterExpr := c.QueryParam("filter"); filterExpr != "" {
+			var err error
+			if filter, err = scim.ParseFilter(filterExpr); err != nil {
+				return scimFailure(c, err)
+			}
+		}
+
+		resources := make([]*scim.Group, 0)
+		for _, group := range scimGroups {
+			if filter != nil {
+				switch strings.ToLower(filter.Attribute) {
+				case "displayname":
+					if !strings.EqualFold(filter.Value, group.Name) {
+						continue
+					}
+				case "id":
+					if filter.Value != group.ID {
+						continue
+					}
+				default:
+					return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidFilter, fmt.Sprintf("Filtering by '%s' is not supported", filter.Attribute))
+				}
+			}
+			resources = append(resources, toSCIMGroup(c, group.ID, allUsers.Result))
+		}
+
+		startIndex, count := scimPagination(c)
+		return scim.Respond(c, http.StatusOK, scim.NewListResponse(resources, startIndex, count))
+	}
+}
+
+// SCIMGetGroup returns a single group and its members
+func SCIMGetGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if scimGroupRole(c.Param("id")) == 0 {
+			return scim.NotFound(c)
+		}
+
+		allUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, allUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		return scim.Respond(c, http.StatusOK, toSCIMGroup(c, c.Param("id"), allUsers.Result))
+	}
+}
+
+// SCIMReplaceGroup changes the role of users so that the group has exactly the given members
+func SCIMReplaceGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		groupID := c.Param("id")
+		if scimGroupRole(groupID) == 0 {
+			return scim.NotFound(c)
+		}
+
+		input := new(scim.Group)
+		if err := scim.Decode(c, input); err != nil {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidSyntax, "Request body is not a valid Group")
+		}
+
+		return saveSCIMGroupMembers(c, groupID, func(members map[int]bool) error {
+			clear(members)
+			return addSCIMMembers(members, input.Members)
+		})
+	}
+}
+
+// SCIMPatchGroup adds or removes members of a group
+func SCIMPatchGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		groupID := c.Param("id")
+		if scimGroupRole(groupID) == 0 {
+			return scim.NotFound(c)
+		}
+
+		patch := new(scim.PatchRequest)
+		if err := scim.Decode(c, patch); err != nil {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidSyntax, "Request body is not a valid PatchOp")
+		}
+
+		return saveSCIMGroupMembers(c, groupID, func(members map[int]bool) error {
+			for _, op := range patch.Operations {
+				if err := applySCIMGroupPatch(members, op); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// SCIMCreateGroup rejects new groups, as only the groups representing roles exist
+func SCIMCreateGroup() web.HandlerFunc {
+	return func(c *web.Context) error {
+		return scim.RespondError(c, http.StatusForbidden, "", "Groups can't be created, use the Administrators or Collaborators groups")
+	}
+}
+
+func saveSCIMUser(c *web.Context, user *entity.User, input *scim.User) error {
+	if user.ID == c.User().ID && input.Active != nil && !*input.Active {
+		return scim.RespondError(c, http.StatusBadRequest, scim.ErrorMutability, "The owner of the API token can't be deactivated")
+	}
+
+	if name := input.FullName(); name != "" && name != user.Name {
+		if err := validateSCIMName(name); err != nil {
+			return scimFailure(c, err)
+		}
+		if err := bus.Dispatch(c, &cmd.ChangeUserName{UserID: user.ID, Name: name}); err != nil {
+			return c.Failure(err)
+		}
+	}
+
+	if email := input.PrimaryEmail(); email != "" && !strings.EqualFold(email, user.Email) {
+		if err := validateSCIMEmail(c, email); err != nil {
+			return scimFailure(c, err)
+		}
+
+		getByEmail := &query.GetUserByEmail{Email: email}
+		err := bus.Dispatch(c, getByEmail)
+		if err == nil && getByEmail.Result.ID != user.ID {
+			return scim.RespondError(c, http.StatusConflict, scim.ErrorUniqueness, fmt.Sprintf("A user with email '%s' already exists", email))
+		} else if err != nil && errors.Cause(err) != app.ErrNotFound {
+			return c.Failure(err)
+		}
+
+		if err := bus.Dispatch(c, &cmd.ChangeUserEmail{UserID: user.ID, Email: email}); err != nil {
+			return c.Failure(err)
+		}
+	}
+
+	if input.ExternalID != "" && !user.HasProvider(scimProvider) {
+		if err := bus.Dispatch(c, &cmd.RegisterUserProvider{
+			UserID:       user.ID,
+			ProviderName: scimProvider,
+			ProviderUID:  input.ExternalID,
+		}); err != nil {
+			return c.Failure(err)
+		}
+	}
+
+	if input.Active != nil {
+		if *input.Active && user.Status == enum.UserBlocked {
+			if err := bus.Dispatch(c, &cmd.UnblockUser{UserID: user.ID}); err != nil {
+				return c.Failure(err)
+			}
+		} else if !*input.Active && user.Status == enum.UserActive {
+			if err := bus.Dispatch(c, &cmd.BlockUser{UserID: user.ID}); err != nil {
+				return c.Failure(err)
+			}
+		}
+	}
+
+	updated, err := getSCIMUser(c, strconv.Itoa(user.ID))
+	if err != nil {
+		return scimFailure(c, err)
+	}
+	return scim.Respond(c, http.StatusOK, toSCIMUser(c, updated))
+}
+
+func saveSCIMGroupMembers(c *web.Context, groupID string, change func(members map[int]bool) error) error {
+	allUsers := &query.GetAllUsers{}
+	if err := bus.Dispatch(c, allUsers); err != nil {
+		return c.Failure(err)
+	}
+
+	role := scimGroupRole(groupID)
+	current := make(map[int]bool)
+	for _, user := range allUsers.Result {
+		if user.Role == role {
+			current[user.ID] = true
+		}
+	}
+
+	members := make(map[int]bool, len(current))
+	for userID := range current {
+		members[userID] = true
+	}
+	if err := change(members); err != nil {
+		return scimFailure(c, err)
+	}
+
+	for userID := range members {
+		exists := slices.ContainsFunc(allUsers.Result, func(user *entity.User) bool {
+			return user.ID == userID
+		})
+		if !exists {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorInvalidValue, fmt.Sprintf("User '%d' doesn't exist", userID))
+		}
+	}
+
+	// joining a group never lowers the role of a user, so administrators stay administrators when added to collaborators
+	changes := make(map[*entity.User]enum.Role)
+	for _, user := range allUsers.Result {
+		if members[user.ID] && !current[user.ID] && role > user.Role {
+			changes[user] = role
+		} else if !members[user.ID] && current[user.ID] {
+			changes[user] = enum.RoleVisitor
+		}
+	}
+
+	for user := range changes {
+		if user.ID == c.User().ID {
+			return scim.RespondError(c, http.StatusBadRequest, scim.ErrorMutability, "The role of the API token owner can't be changed")
+		}
+	}
+
+	for user, newRole := range changes {
+		if err := bus.Dispatch(c, &cmd.ChangeUserRole{UserID: user.ID, Role: newRole}); err != nil {
+			return c.Failure(err)
+		}
+		user.Role = newRole
+	}
+
+	return scim.Respond(c, http.StatusOK, toSCIMGroup(c, groupID, allUsers.Result))
+}
+
+func applySCIMGroupPatch(members map[int]bool, op scim.PatchOperation) error {
+	var path *scim.Path
+	if op.Path != "" {
+		var err error
+		if path, err = scim.ParsePath(op.Path); err != nil {
+			return err
+		}
+		if strings.EqualFold(path.Attribute, "displayName") {
+			return scim.NewError(http.StatusBadRequest, scim.ErrorMutability, "Groups can't be renamed")
+		}
+		if !strings.EqualFold(path.Attribute, "members") {
+			return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidPath, fmt.Sprintf("Path '%s' is not supported", op.Path))
+		}
+	}
+
+	var values []scim.MultiValue
+	if len(op.Value) > 0 {
+		if path == nil {
+			// without a path, the value holds the attributes to change and only members can be changed
+			var group struct {
+				Members *[]scim.MultiValue `json:"members"`
+			}
+			if err := scim.DecodeValue(op.Value, &group); err != nil {
+				return err
+			}
+			if group.Members == nil {
+				return nil
+			}
+			values = *group.Members
+		} else if err := scim.DecodeValue(op.Value, &values); err != nil {
+			return err
+		}
+	}
+
+	switch strings.ToLower(op.Op) {
+	case "add":
+		return addSCIMMembers(members, values)
+	case "replace":
+		clear(members)
+		return addSCIMMembers(members, values)
+	case "remove":
+		if path == nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrorNoTarget, "Remove operation must have a path")
+		}
+		if path.Filter != nil {
+			if !strings.EqualFold(path.Filter.Attribute, "value") {
+				return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidFilter, "Members can only be filtered by value")
+			}
+			values = append(values, scim.MultiValue{Value: path.Filter.Value})
+		} else if len(values) == 0 {
+			clear(members)
+			return nil
+		}
+
+		for _, value := range values {
+			userID, err := strconv.Atoi(value.Value)
+			if err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, fmt.Sprintf("Member '%s' is invalid", value.Value))
+			}
+			delete(members, userID)
+		}
+		return nil
+	}
+	return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidSyntax, fmt.Sprintf("Operation '%s' is not supported", op.Op))
+}
+
+func addSCIMMembers(members map[int]bool, values []scim.MultiValue) error {
+	for _, value := range values {
+		userID, err := strconv.Atoi(value.Value)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, fmt.Sprintf("Member '%s' is invalid", value.Value))
+		}
+		members[userID] = true
+	}
+	return nil
+}
+
+func getSCIMUser(c *web.Context, id string) (*entity.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, app.ErrNotFound
+	}
+
+	getUser := &query.GetUserByID{UserID: userID}
+	if err := bus.Dispatch(c, getUser); err != nil {
+		return nil, err
+	}
+
+	// users are looked up by id only, so make sure they belong to current tenant
+	if getUser.Result.Tenant == nil || getUser.Result.Tenant.ID != c.Tenant().ID {
+		return nil, app.ErrNotFound
+	}
+	return getUser.Result, nil
+}
+
+func scimUserMatches(user *entity.User, filter *scim.Filter) bool {
+	switch strings.ToLower(filter.Attribute) {
+	case "username", "emails", "emails.value":
+		return strings.EqualFold(user.Email, filter.Value)
+	case "externalid":
+		for _, provider := range user.Providers {
+			if provider.Name == scimProvider && provider.UID == filter.Value {
+				return true
+			}
+		}
+		return false
+	case "id":
+		return strconv.Itoa(user.ID) == filter.Value
+	}
+	return false
+}
+
+func scimPagination(c *web.Context) (int, int) {
+	startIndex, err := c.QueryParamAsInt("startIndex")
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := c.QueryParamAsInt("count")
+	if err != nil || c.QueryParam("count") == "" || count > scimMaxResults {
+		count = scimMaxResults
+	}
+	return startIndex, count
+}
+
+func scimGroupRole(groupID string) enum.Role {
+	for _, group := range scimGroups {
+		if group.ID == groupID {
+			return group.Role
+		}
+	}
+	return 0
+}
+
+func toSCIMUser(c *web.Context, user *entity.User) *scim.User {
+	id := strconv.Itoa(user.ID)
+	active := user.Status == enum.UserActive
+
+	resource := &scim.User{
+		Schemas:     []string{scim.UserSchema},
+		ID:          id,
+		UserName:    user.Email,
+		Name:        &scim.Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Active:      &active,
+		Meta:        &scim.Meta{ResourceType: "User", Location: c.BaseURL() + "/scim/v2/Users/" + id},
+	}
+
+	// users created through the API with a reference only have no email
+	if user.Email == "" {
+		resource.UserName = id
+	} else {
+		resource.Emails = []scim.MultiValue{{Value: user.Email, Type: "work", Primary: true}}
+	}
+
+	for _, provider := range user.Providers {
+		if provider.Name == scimProvider {
+			resource.ExternalID = provider.UID
+		}
+	}
+
+	for _, group := range scimGroups {
+		if group.Role == user.Role {
+			resource.Groups = append(resource.Groups, scim.MultiValue{
+				Value:   group.ID,
+				Display: group.Name,
+				Ref:     c.BaseURL() + "/scim/v2/Groups/" + group.ID,
+			})
+		}
+	}
+
+	return resource
+}
+
+func toSCIMGroup(c *web.Context, groupID string, users []*entity.User) *scim.Group {
+	resource := &scim.Group{
+		Schemas: []string{scim.GroupSchema},
+		ID:      groupID,
+		Members: make([]scim.MultiValue, 0),
+		Meta:    &scim.Meta{ResourceType: "Group", Location: c.BaseURL() + "/scim/v2/Groups/" + groupID},
+	}
+
+	for _, group := range scimGroups {
+		if group.ID != groupID {
+			continue
+		}
+
+		resource.DisplayName = group.Name
+		for _, user := range users {
+			if user.Role == group.Role && user.Status != enum.UserDeleted {
+				id := strconv.Itoa(user.ID)
+				resource.Members = append(resource.Members, scim.MultiValue{
+					Value:   id,
+					Display: user.Name,
+					Ref:     c.BaseURL() + "/scim/v2/Users/" + id,
+				})
+			}
+		}
+	}
+
+	return resource
+}
+
+func validateSCIMEmail(c *web.Context, email string) error {
+	if email == "" {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, "An email address is required")
+	}
+	if messages := validate.Email(c, email); len(messages) > 0 {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, fmt.Sprintf("Email '%s' is invalid", email))
+	}
+	return nil
+}
+
+func validateSCIMName(name string) error {
+	if len(name) > 100 {
+		return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, "Name must have less than 100 characters")
+	}
+	return nil
+}
+
+// scimFailure responds with the SCIM error describing err
+func scimFailure(c *web.Context, err error) error {
+	if scimErr, ok := err.(*scim.Error); ok {
+		status, _ := strconv.Atoi(scimErr.Status)
+		return scim.Respond(c, status, scimErr)
+	}
+	if errors.Cause(err) == app.ErrNotFound {
+		return scim.NotFound(c)
+	}
+	return c.Failure(err)
+}
@@ -0,0 +1,332 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func mockSCIMUsers() {
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllUsers) error {
+		q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		for _, user := range []*entity.User{mock.JonSnow, mock.AryaStark} {
+			if user.ID == q.UserID {
+				q.Result = user
+				return nil
+			}
+		}
+		return app.ErrNotFound
+	})
+}
+
+func TestSCIMListUsersHandler_Filter(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL(`http://demo.test.fider.io/scim/v2/Users?filter=userName+eq+"Arya.Stark@got.com"`).
+		ExecuteAsJSON(handlers.SCIMListUsers())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(query.Int32("totalResults")).Equals(1)
+	Expect(query.String("Resources[0].id")).Equals("2")
+	Expect(query.String("Resources[0].userName")).Equals("arya.stark@got.com")
+	Expect(query.String("Resources[0].displayName")).Equals("Arya Stark")
+}
+
+func TestSCIMListUsersHandler_UnsupportedFilter(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithURL(`http://demo.test.fider.io/scim/v2/Users?filter=title+eq+"CEO"`).
+		ExecuteAsJSON(handlers.SCIMListUsers())
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("invalidFilter")
+}
+
+func TestSCIMGetUserHandler_OtherTenant(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = &entity.User{ID: q.UserID, Name: "Tony Stark", Tenant: mock.AvengersTenant}
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "9").
+		Execute(handlers.SCIMGetUser())
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestSCIMCreateUserHandler(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	var newUser *entity.User
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 7
+		newUser = c.User
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePostAsJSON(handlers.SCIMCreateUser(), `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"externalId": "00u1",
+			"userName": "sansa.stark@got.com",
+			"name": { "givenName": "Sansa", "familyName": "Stark" },
+			"emails": [{ "value": "sansa.stark@got.com", "primary": true }],
+			"active": true
+		}`)
+
+	Expect(code).Equals(http.StatusCreated)
+	Expect(query.String("id")).Equals("7")
+	Expect(query.String("externalId")).Equals("00u1")
+	Expect(newUser.Name).Equals("Sansa Stark")
+	Expect(newUser.Email).Equals("sansa.stark@got.com")
+	Expect(newUser.Role).Equals(enum.RoleVisitor)
+	Expect(newUser.Providers[0].Name).Equals("scim")
+	Expect(newUser.Providers[0].UID).Equals("00u1")
+}
+
+func TestSCIMCreateUserHandler_ExistingEmail(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		ExecutePostAsJSON(handlers.SCIMCreateUser(), `{ "userName": "arya.stark@got.com" }`)
+
+	Expect(code).Equals(http.StatusConflict)
+	Expect(query.String("scimType")).Equals("uniqueness")
+	Expect(query.String("status")).Equals("409")
+}
+
+func TestSCIMPatchUserHandler_Deactivate(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	var blocked *cmd.BlockUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.BlockUser) error {
+		blocked = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "2").
+		ExecutePost(handlers.SCIMPatchUser(), `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{ "op": "Replace", "path": "active", "value": "False" }]
+		}`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(blocked.UserID).Equals(mock.AryaStark.ID)
+}
+
+func TestSCIMPatchUserHandler_CannotDeactivateTokenOwner(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "1").
+		ExecutePostAsJSON(handlers.SCIMPatchUser(), `{
+			"Operations": [{ "op": "replace", "value": { "active": false } }]
+		}`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("mutability")
+}
+
+func TestSCIMReplaceUserHandler_ChangeNameAndEmail(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		return app.ErrNotFound
+	})
+
+	var changeName *cmd.ChangeUserName
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserName) error {
+		changeName = c
+		return nil
+	})
+
+	var changeEmail *cmd.ChangeUserEmail
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserEmail) error {
+		changeEmail = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "2").
+		ExecutePost(handlers.SCIMReplaceUser(), `{
+			"userName": "no.one@got.com",
+			"displayName": "No One",
+			"active": true
+		}`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(changeName.UserID).Equals(mock.AryaStark.ID)
+	Expect(changeName.Name).Equals("No One")
+	Expect(changeEmail.UserID).Equals(mock.AryaStark.ID)
+	Expect(changeEmail.Email).Equals("no.one@got.com")
+}
+
+func TestSCIMDeleteUserHandler(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	var deleted *cmd.DeleteUser
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteUser) error {
+		deleted = c
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "2").
+		Execute(handlers.SCIMDeleteUser())
+
+	Expect(code).Equals(http.StatusNoContent)
+	Expect(deleted.UserID).Equals(mock.AryaStark.ID)
+}
+
+func TestSCIMGetGroupHandler(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "administrators").
+		ExecuteAsJSON(handlers.SCIMGetGroup())
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(query.String("displayName")).Equals("Administrators")
+	Expect(query.String("members[0].value")).Equals("1")
+}
+
+func TestSCIMPatchGroupHandler_AddAndRemoveMembers(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	roles := make(map[int]enum.Role)
+	bus.AddHandler(func(ctx context.Context, c *cmd.ChangeUserRole) error {
+		roles[c.UserID] = c.Role
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "collaborators").
+		ExecutePost(handlers.SCIMPatchGroup(), `{
+			"Operations": [
+				{ "op": "add", "path": "members", "value": [{ "value": "2" }] }
+			]
+		}`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(roles).Equals(map[int]enum.Role{mock.AryaStark.ID: enum.RoleCollaborator})
+
+	server = mock.NewServer()
+	mock.AryaStark.Role = enum.RoleCollaborator
+	roles = make(map[int]enum.Role)
+	code, _ = server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "collaborators").
+		ExecutePost(handlers.SCIMPatchGroup(), `{
+			"Operations": [
+				{ "op": "remove", "path": "members[value eq \"2\"]" }
+			]
+		}`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(roles[mock.AryaStark.ID]).Equals(enum.RoleVisitor)
+}
+
+func TestSCIMPatchGroupHandler_CannotDemoteTokenOwner(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "administrators").
+		ExecutePostAsJSON(handlers.SCIMPatchGroup(), `{
+			"Operations": [{ "op": "replace", "path": "members", "value": [] }]
+		}`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("mutability")
+}
+
+func TestSCIMPatchGroupHandler_UnknownMember(t *testing.T) {
+	RegisterT(t)
+	mockSCIMUsers()
+
+	server := mock.NewServer()
+	code, query := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		AddParam("id", "collaborators").
+		ExecutePostAsJSON(handlers.SCIMPatchGroup(), `{
+			"Operations": [{ "op": "add", "value": { "members": [{ "value": "99" }] } }]
+		}`)
+
+	Expect(code).Equals(http.StatusBadRequest)
+	Expect(query.String("scimType")).Equals("invalidValue")
+}
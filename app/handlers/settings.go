@@ -78,7 +78,9 @@ func VerifyChangeEmailKey() web.HandlerFunc {
 func UserSettings() web.HandlerFunc {
 	return func(c *web.Context) error {
 		settings := &query.GetCurrentUserSettings{}
-		if err := bus.Dispatch(c, settings); err != nil {
+		getAllTags := &query.GetAllTags{}
+		getFollowedTags := &query.GetFollowedTags{}
+		if err := bus.Dispatch(c, settings, getAllTags, getFollowedTags); err != nil {
 			return err
 		}
 
@@ -87,6 +89,12 @@ func UserSettings() web.HandlerFunc {
 			Title: "Settings",
 			Data: web.Map{
 				"userSettings": settings.Result,
+				"tags":         getAllTags.Result,
+				"followedTags": getFollowedTags.Result,
+				"twoFactor": web.Map{
+					"enabled":  c.User().HasTwoFactor,
+					"required": c.User().Tenant.IsTwoFactorRequired && c.User().IsCollaborator(),
+				},
 			},
 		})
 	}
@@ -125,6 +133,61 @@ func UpdateUserSettings() web.HandlerFunc {
 	}
 }
 
+// SnoozeNotifications pauses all notifications of current user for some days, or resumes them
+func SnoozeNotifications() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.SnoozeNotifications)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		until := action.Until()
+		if err := bus.Dispatch(c, &cmd.UpdateCurrentUserSettings{
+			Settings: map[string]string{
+				enum.NotificationsSnoozedUntilSettingsKey: until,
+			},
+		}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"snoozedUntil": until,
+		})
+	}
+}
+
+// FollowTag notifies current user about new posts with given tag
+func FollowTag() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.FollowUnfollowTag)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.FollowTag{Tag: action.Tag}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// UnfollowTag stops notifying current user about new posts with given tag
+func UnfollowTag() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.FollowUnfollowTag)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.UnfollowTag{Tag: action.Tag}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
 // ChangeUserRole changes given user role
 func ChangeUserRole() web.HandlerFunc {
 	return func(c *web.Context) error {
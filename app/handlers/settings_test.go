@@ -28,6 +28,12 @@ func TestSettingsHandler(t *testing.T) {
 	bus.AddHandler(func(ctx context.Context, q *query.GetCurrentUserSettings) error {
 		return nil
 	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetAllTags) error {
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetFollowedTags) error {
+		return nil
+	})
 
 	server := mock.NewServer()
 	code, _ := server.
@@ -77,6 +83,35 @@ func TestUpdateUserSettingsHandler_ValidName(t *testing.T) {
 	Expect(newName).Equals("Jon Stark")
 }
 
+func TestSnoozeNotificationsHandler(t *testing.T) {
+	RegisterT(t)
+
+	var settings map[string]string
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateCurrentUserSettings) error {
+		settings = c.Settings
+		return nil
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		ExecutePost(handlers.SnoozeNotifications(), `{ "days": 3 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	until, err := time.Parse(time.RFC3339, settings[enum.NotificationsSnoozedUntilSettingsKey])
+	Expect(err).IsNil()
+	Expect(until).TemporarilySimilar(time.Now().AddDate(0, 0, 3), 5*time.Second)
+
+	code, _ = server.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.AryaStark).
+		ExecutePost(handlers.SnoozeNotifications(), `{ "days": 0 }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(settings[enum.NotificationsSnoozedUntilSettingsKey]).Equals("")
+}
+
 func TestUpdateUserSettingsHandler_NewSettings(t *testing.T) {
 	RegisterT(t)
 
@@ -165,7 +165,15 @@ func CompleteSignInProfile() web.HandlerFunc {
 // SignOut remove auth cookies
 func SignOut() web.HandlerFunc {
 	return func(c *web.Context) error {
+		if c.IsAuthenticated() && c.UserSessionID() > 0 {
+			err := bus.Dispatch(c, &cmd.RevokeUserSession{UserID: c.User().ID, SessionID: c.UserSessionID()})
+			if err != nil {
+				return c.Failure(err)
+			}
+		}
+
 		c.RemoveCookie(web.CookieAuthName)
+		c.RemoveCookie(web.CookieReauthName)
 		return c.Redirect("/")
 	}
 }
@@ -23,8 +23,19 @@ import (
 	"github.com/getfider/fider/app/pkg/web"
 )
 
+func mockSAMLConfig(config *entity.SAMLConfig) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLConfig) error {
+		if config == nil {
+			return app.ErrNotFound
+		}
+		q.Result = config
+		return nil
+	})
+}
+
 func TestSignInByEmailHandler_WithoutEmail(t *testing.T) {
 	RegisterT(t)
+	mockSAMLConfig(nil)
 
 	server := mock.NewServer()
 	code, _ := server.
@@ -36,6 +47,7 @@ func TestSignInByEmailHandler_WithoutEmail(t *testing.T) {
 
 func TestSignInByEmailHandler_WithEmail(t *testing.T) {
 	RegisterT(t)
+	mockSAMLConfig(nil)
 
 	var saveKeyCmd *cmd.SaveVerificationKey
 	bus.AddHandler(func(ctx context.Context, c *cmd.SaveVerificationKey) error {
@@ -55,6 +67,40 @@ func TestSignInByEmailHandler_WithEmail(t *testing.T) {
 	Expect(saveKeyCmd.Request.GetName()).Equals("")
 }
 
+func TestSignInByEmailHandler_SSORequired(t *testing.T) {
+	RegisterT(t)
+	mockSAMLConfig(&entity.SAMLConfig{Status: enum.OAuthConfigEnabled, RequireSSO: true})
+
+	saved := false
+	bus.AddHandler(func(ctx context.Context, c *cmd.SaveVerificationKey) error {
+		saved = true
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		if q.Email == mock.JonSnow.Email {
+			q.Result = mock.JonSnow
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		ExecutePost(handlers.SignInByEmail(), `{ "email": "arya.stark@got.com" }`)
+
+	Expect(code).Equals(http.StatusForbidden)
+	Expect(saved).IsFalse()
+
+	// Administrators can still sign in by email
+	code, _ = mock.NewServer().
+		OnTenant(mock.DemoTenant).
+		ExecutePost(handlers.SignInByEmail(), `{ "email": "jon.snow@got.com" }`)
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(saved).IsTrue()
+}
+
 func TestVerifySignInKeyHandler_UnknownKey(t *testing.T) {
 	RegisterT(t)
 
@@ -139,6 +185,7 @@ func TestVerifySignInKeyHandler_KeyUsedLongAgo_ShouldReturnGone(t *testing.T) {
 
 func TestVerifySignInKeyHandler_CorrectKey_ExistingUser(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 
@@ -181,6 +228,7 @@ func TestVerifySignInKeyHandler_CorrectKey_ExistingUser(t *testing.T) {
 
 func TestVerifySignInKeyHandler_RecentlyUsedKey_ShouldAllowReuse(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 
@@ -301,6 +349,7 @@ func TestVerifySignInKeyHandler_PrivateTenant_SignInRequest_NonInviteNewUser(t *
 
 func TestVerifySignInKeyHandler_PrivateTenant_SignInRequest_RegisteredUser(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	mock.DemoTenant.IsPrivate = true
@@ -354,6 +403,7 @@ func TestVerifySignInKeyHandler_PrivateTenant_SignInRequest_RegisteredUser(t *te
 
 func TestVerifySignInKeyHandler_PrivateTenant_InviteRequest_ExistingUser(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	mock.DemoTenant.IsPrivate = true
@@ -440,6 +490,7 @@ func TestVerifySignInKeyHandler_PrivateTenant_InviteRequest_NewUser(t *testing.T
 
 func TestVerifySignUpKeyHandler_PendingTenant(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	mock.DemoTenant.Status = enum.TenantPending
@@ -549,6 +600,7 @@ func TestCompleteSignInProfileHandler_ExistingUser_CorrectKey(t *testing.T) {
 
 func TestCompleteSignInProfileHandler_CorrectKey(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	server := mock.NewServer()
 	key := "1234567890"
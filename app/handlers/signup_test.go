@@ -123,6 +123,7 @@ func TestCreateTenantHandler_EmptyInput(t *testing.T) {
 
 func TestCreateTenantHandler_WithSocialAccount(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	var newUser *entity.User
 	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
@@ -185,6 +186,7 @@ func TestCreateTenantHandler_WithSocialAccount(t *testing.T) {
 
 func TestCreateTenantHandler_SingleHost_WithSocialAccount(t *testing.T) {
 	RegisterT(t)
+	mockCreateUserSession()
 
 	var newUser *entity.User
 	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
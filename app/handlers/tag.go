@@ -12,7 +12,8 @@ import (
 func ManageTags() web.HandlerFunc {
 	return func(c *web.Context) error {
 		getAllTags := &query.GetAllTags{}
-		if err := bus.Dispatch(c, getAllTags); err != nil {
+		getAllGroups := &query.GetAllUserGroups{}
+		if err := bus.Dispatch(c, getAllTags, getAllGroups); err != nil {
 			return c.Failure(err)
 		}
 
@@ -20,7 +21,8 @@ func ManageTags() web.HandlerFunc {
 			Page:  "Administration/pages/ManageTags.page",
 			Title: "Manage Tags · Site Settings",
 			Data: web.Map{
-				"tags": getAllTags.Result,
+				"tags":   getAllTags.Result,
+				"groups": getAllGroups.Result,
 			},
 		})
 	}
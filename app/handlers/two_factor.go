@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/totp"
+	"github.com/getfider/fider/app/pkg/web"
+	webutil "github.com/getfider/fider/app/pkg/web/util"
+)
+
+// TwoFactorPage asks for the second factor of users that are signing in or need to reauthenticate
+func TwoFactorPage() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if twoFactorUserID(c) == 0 {
+			return c.Redirect("/")
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "SignIn/TwoFactor.page",
+			Title: "Two-factor authentication",
+			Data: web.Map{
+				"redirect":    safeRedirectPath(c.QueryParam("redirect")),
+				"isSigningIn": !c.IsAuthenticated(),
+			},
+		})
+	}
+}
+
+// VerifyTwoFactor checks the second factor and completes the sign in process
+func VerifyTwoFactor() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := &actions.VerifyTwoFactorCode{UserID: twoFactorUserID(c)}
+		if result := c.BindTo(action); !result.Ok {
+			// The pending sign in is dropped as well, it has to start over once the lockout is over
+			if action.LockedOut && !c.IsAuthenticated() {
+				c.RemoveCookie(web.CookieAuthName)
+			}
+			return c.HandleValidation(result)
+		}
+
+		getUser := &query.GetUserByID{UserID: action.UserID}
+		if err := bus.Dispatch(c, getUser); err != nil {
+			return c.Failure(err)
+		}
+
+		webutil.CompleteTwoFactorSignIn(c, getUser.Result)
+		return c.Ok(web.Map{})
+	}
+}
+
+// SetupTwoFactor generates a new secret for the authenticator app of current user
+func SetupTwoFactor() web.HandlerFunc {
+	return func(c *web.Context) error {
+		if c.User().HasTwoFactor {
+			return c.BadRequest(web.Map{})
+		}
+
+		secret := totp.GenerateSecret()
+		if err := bus.Dispatch(c, &cmd.SetupUserTwoFactor{UserID: c.User().ID, Secret: secret}); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"secret": secret,
+			"url":    totp.URL(c.Tenant().Name, c.User().Email, secret),
+		})
+	}
+}
+
+// EnableTwoFactor confirms the authenticator app of current user and returns the recovery codes
+func EnableTwoFactor() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.EnableTwoFactor)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		enable := &cmd.EnableUserTwoFactor{UserID: c.User().ID, Step: action.Step}
+		if err := bus.Dispatch(c, enable); err != nil {
+			return c.Failure(err)
+		}
+
+		webutil.CompleteTwoFactorSignIn(c, c.User())
+		return c.Ok(web.Map{
+			"recoveryCodes": enable.Result,
+		})
+	}
+}
+
+// DisableTwoFactor turns off two-factor authentication of current user
+func DisableTwoFactor() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.DisableTwoFactor)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		if err := bus.Dispatch(c, &cmd.DisableUserTwoFactor{UserID: c.User().ID}); err != nil {
+			return c.Failure(err)
+		}
+
+		c.RemoveCookie(web.CookieReauthName)
+		return c.Ok(web.Map{})
+	}
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of current user
+func RegenerateRecoveryCodes() web.HandlerFunc {
+	return func(c *web.Context) error {
+		action := new(actions.RegenerateRecoveryCodes)
+		if result := c.BindTo(action); !result.Ok {
+			return c.HandleValidation(result)
+		}
+
+		regenerate := &cmd.RegenerateRecoveryCodes{UserID: c.User().ID}
+		if err := bus.Dispatch(c, regenerate); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"recoveryCodes": regenerate.Result,
+		})
+	}
+}
+
+// twoFactorUserID returns the user that is expected to provide a second factor
+// which is either a user halfway through signing in or a signed in user with two-factor enabled
+func twoFactorUserID(c *web.Context) int {
+	if c.IsAuthenticated() {
+		if c.User().HasTwoFactor {
+			return c.User().ID
+		}
+		return 0
+	}
+
+	cookie, err := c.Request.Cookie(web.CookieAuthName)
+	if err != nil {
+		return 0
+	}
+
+	claims, err := jwt.DecodeFiderClaims(cookie.Value)
+	if err != nil || !claims.TwoFactorPending {
+		return 0
+	}
+	return claims.UserID
+}
+
+// safeRedirectPath only allows redirects to paths of current site
+func safeRedirectPath(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+	return redirect
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+type unsubscribeTarget struct {
+	User  *entity.User
+	Post  *entity.Post
+	Event string
+}
+
+// UnsubscribePage asks the user to confirm before stopping the emails of an unsubscribe link
+func UnsubscribePage() web.HandlerFunc {
+	return func(c *web.Context) error {
+		target, err := getUnsubscribeTarget(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if target == nil {
+			return c.NotFound()
+		}
+
+		data := web.Map{
+			"token": c.Param("token"),
+			"event": target.Event,
+		}
+		if target.Post != nil {
+			data["post"] = web.Map{
+				"number": target.Post.Number,
+				"slug":   target.Post.Slug,
+				"title":  target.Post.Title,
+			}
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Unsubscribe/Unsubscribe.page",
+			Title: "Unsubscribe",
+			Data:  data,
+		})
+	}
+}
+
+// Unsubscribe stops the emails of an unsubscribe link without requiring the user to sign in.
+// It's also the one-click unsubscribe endpoint (RFC 8058) used by mailbox providers
+func Unsubscribe() web.HandlerFunc {
+	return func(c *web.Context) error {
+		target, err := getUnsubscribeTarget(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if target == nil {
+			return c.NotFound()
+		}
+
+		if target.Post != nil {
+			err = bus.Dispatch(c, &cmd.RemoveSubscriber{Post: target.Post, User: target.User})
+		} else {
+			err = disableEmailNotifications(context.WithValue(c, app.UserCtxKey, target.User), target.Event)
+		}
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// getUnsubscribeTarget returns what the unsubscribe token refers to, or nil when the token is not valid for current tenant
+func getUnsubscribeTarget(c *web.Context) (*unsubscribeTarget, error) {
+	claims, err := jwt.DecodeUnsubscribeClaims(c.Param("token"))
+	if err != nil {
+		return nil, nil
+	}
+
+	if claims.Event != "" && !isNotificationEvent(claims.Event) {
+		return nil, nil
+	}
+
+	getUser := &query.GetUserByID{UserID: claims.UserID}
+	if err := bus.Dispatch(c, getUser); err != nil {
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Tokens are signed with a secret that is shared by all tenants, so they're only valid on the tenant of the user
+	if getUser.Result.Tenant == nil || getUser.Result.Tenant.ID != c.Tenant().ID {
+		return nil, nil
+	}
+
+	target := &unsubscribeTarget{User: getUser.Result, Event: claims.Event}
+	if claims.PostID > 0 {
+		getPost := &query.GetPostByID{PostID: claims.PostID}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		target.Post = getPost.Result
+	}
+
+	return target, nil
+}
+
+func isNotificationEvent(name string) bool {
+	for _, event := range enum.AllNotificationEvents {
+		if event.UserSettingsKeyName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// disableEmailNotifications removes the email channel from given event, or from all events when it's empty
+func disableEmailNotifications(ctx context.Context, eventName string) error {
+	getSettings := &query.GetCurrentUserSettings{}
+	if err := bus.Dispatch(ctx, getSettings); err != nil {
+		return err
+	}
+
+	settings := make(map[string]string)
+	for _, event := range enum.AllNotificationEvents {
+		if eventName == "" || eventName == event.UserSettingsKeyName {
+			value, _ := strconv.Atoi(getSettings.Result[event.UserSettingsKeyName])
+			settings[event.UserSettingsKeyName] = strconv.Itoa(value &^ int(enum.NotificationChannelEmail))
+		}
+	}
+
+	return bus.Dispatch(ctx, &cmd.UpdateCurrentUserSettings{Settings: settings})
+}
@@ -0,0 +1,153 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app/handlers"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestUnsubscribeHandler_InvalidToken(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddParam("token", "invalid").
+		ExecutePost(handlers.Unsubscribe(), "")
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestUnsubscribeHandler_OtherTenant(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+
+	token, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, Event: enum.NotificationEventNewPost.UserSettingsKeyName})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.AvengersTenant).
+		AddParam("token", token).
+		ExecutePost(handlers.Unsubscribe(), "")
+
+	Expect(code).Equals(http.StatusNotFound)
+}
+
+func TestUnsubscribeHandler_FromPost(t *testing.T) {
+	RegisterT(t)
+
+	post := &entity.Post{ID: 5, Number: 5, Title: "Add dark mode"}
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByID) error {
+		q.Result = post
+		return nil
+	})
+
+	var removeSubscriber *cmd.RemoveSubscriber
+	bus.AddHandler(func(ctx context.Context, c *cmd.RemoveSubscriber) error {
+		removeSubscriber = c
+		return nil
+	})
+
+	token, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, PostID: post.ID})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddParam("token", token).
+		ExecutePost(handlers.Unsubscribe(), "")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(removeSubscriber.Post).Equals(post)
+	Expect(removeSubscriber.User).Equals(mock.AryaStark)
+}
+
+func TestUnsubscribeHandler_FromEvent(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetCurrentUserSettings) error {
+		q.Result = map[string]string{
+			enum.NotificationEventNewPost.UserSettingsKeyName: "3",
+			enum.NotificationEventMention.UserSettingsKeyName: "3",
+		}
+		return nil
+	})
+
+	var updateSettings *cmd.UpdateCurrentUserSettings
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateCurrentUserSettings) error {
+		updateSettings = c
+		return nil
+	})
+
+	token, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, Event: enum.NotificationEventNewPost.UserSettingsKeyName})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddParam("token", token).
+		ExecutePost(handlers.Unsubscribe(), "")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(updateSettings.Settings).Equals(map[string]string{
+		enum.NotificationEventNewPost.UserSettingsKeyName: "1",
+	})
+}
+
+func TestUnsubscribeHandler_FromAllEvents(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		q.Result = mock.AryaStark
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetCurrentUserSettings) error {
+		q.Result = map[string]string{
+			enum.NotificationEventNewPost.UserSettingsKeyName:    "3",
+			enum.NotificationEventNewComment.UserSettingsKeyName: "2",
+		}
+		return nil
+	})
+
+	var updateSettings *cmd.UpdateCurrentUserSettings
+	bus.AddHandler(func(ctx context.Context, c *cmd.UpdateCurrentUserSettings) error {
+		updateSettings = c
+		return nil
+	})
+
+	token, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID})
+
+	server := mock.NewServer()
+	code, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddParam("token", token).
+		ExecutePost(handlers.Unsubscribe(), "")
+
+	Expect(code).Equals(http.StatusOK)
+	Expect(updateSettings.Settings).Equals(map[string]string{
+		enum.NotificationEventNewPost.UserSettingsKeyName:      "1",
+		enum.NotificationEventNewComment.UserSettingsKeyName:   "0",
+		enum.NotificationEventMention.UserSettingsKeyName:      "0",
+		enum.NotificationEventChangeStatus.UserSettingsKeyName: "0",
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/web"
 )
@@ -39,3 +40,76 @@ func UnblockUser() web.HandlerFunc {
 		return c.Ok(web.Map{})
 	}
 }
+
+// ListUserSessions returns all browsers where current user is signed in
+func ListUserSessions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getSessions := &query.GetUserSessions{UserID: c.User().ID}
+		if err := bus.Dispatch(c, getSessions); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{
+			"sessions":         getSessions.Result,
+			"currentSessionId": c.UserSessionID(),
+		})
+	}
+}
+
+// RevokeUserSession signs current user out of one of their browsers
+func RevokeUserSession() web.HandlerFunc {
+	return func(c *web.Context) error {
+		sessionID, err := c.ParamAsInt("id")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		err = bus.Dispatch(c, &cmd.RevokeUserSession{UserID: c.User().ID, SessionID: sessionID})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// SignOutEverywhere signs current user out of all browsers but the current one
+func SignOutEverywhere() web.HandlerFunc {
+	return func(c *web.Context) error {
+		err := bus.Dispatch(c, &cmd.RevokeUserSessions{UserID: c.User().ID, ExceptSessionID: c.UserSessionID()})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// RevokeSessionsOfUser is used by administrators to sign a user out of all their browsers
+func RevokeSessionsOfUser() web.HandlerFunc {
+	return func(c *web.Context) error {
+		userID, err := c.ParamAsInt("userID")
+		if err != nil {
+			return c.NotFound()
+		}
+
+		err = bus.Dispatch(c, &cmd.RevokeUserSessions{UserID: userID, ExceptSessionID: c.UserSessionID()})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+// RevokeAllSessions is used by administrators to sign everyone out of this site, except themselves
+func RevokeAllSessions() web.HandlerFunc {
+	return func(c *web.Context) error {
+		err := bus.Dispatch(c, &cmd.RevokeAllUserSessions{ExceptSessionID: c.UserSessionID()})
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ManageUserGroups is the home page for managing user groups
+func ManageUserGroups() web.HandlerFunc {
+	return func(c *web.Context) error {
+		getAllGroups := &query.GetAllUserGroups{}
+		getAllUsers := &query.GetAllUsers{}
+		if err := bus.Dispatch(c, getAllGroups, getAllUsers); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Page(http.StatusOK, web.Props{
+			Page:  "Administration/pages/ManageGroups.page",
+			Title: "Manage Groups · Site Settings",
+			Data: web.Map{
+				"groups": getAllGroups.Result,
+				"users":  getAllUsers.Result,
+			},
+		})
+	}
+}
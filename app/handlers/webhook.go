@@ -46,6 +46,7 @@ func CreateWebhook() web.HandlerFunc {
 			Content:     action.Content,
 			HttpMethod:  action.HttpMethod,
 			HttpHeaders: action.HttpHeaders,
+			IsInternal:  action.IsInternal,
 		}
 		if err := bus.Dispatch(c, createWebhook); err != nil {
 			return c.Failure(err)
@@ -77,6 +78,7 @@ func UpdateWebhook() web.HandlerFunc {
 			Content:     action.Content,
 			HttpMethod:  action.HttpMethod,
 			HttpHeaders: action.HttpHeaders,
+			IsInternal:  action.IsInternal,
 		}
 		if action.Status == enum.WebhookFailed {
 			updateWebhook.Status = enum.WebhookDisabled
@@ -0,0 +1,87 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+type bounceReport struct {
+	Bounces    []string `json:"bounces"`
+	Complaints []string `json:"complaints"`
+}
+
+// IncomingBounceWebhook receives bounces and complaints from any email provider or MTA,
+// either as a JSON payload or as a raw delivery status notification
+func IncomingBounceWebhook() web.HandlerFunc {
+	return func(c *web.Context) error {
+		secret := strings.TrimPrefix(c.Request.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(env.Config.Email.BounceWebhookSecret)) != 1 {
+			return c.Unauthorized()
+		}
+
+		report := bounceReport{}
+		mediaType, _, _ := mime.ParseMediaType(c.Request.GetHeader("Content-Type"))
+		if mediaType == "application/json" {
+			if err := json.Unmarshal([]byte(c.Request.Body), &report); err != nil {
+				return c.BadRequest(web.Map{"body": "Invalid JSON payload."})
+			}
+		} else {
+			msg, err := inbound.ParseMessage(strings.NewReader(c.Request.Body))
+			if err != nil {
+				return c.BadRequest(web.Map{"body": "Invalid delivery status notification."})
+			}
+			report.Bounces = msg.Bounces
+			report.Complaints = msg.Complaints
+		}
+
+		if err := supressReportedEmails(c, report); err != nil {
+			return c.Failure(err)
+		}
+
+		return c.Ok(web.Map{})
+	}
+}
+
+func supressReportedEmails(c *web.Context, report bounceReport) error {
+	supressions := []*cmd.SupressEmail{
+		{EmailAddresses: normalizeEmails(report.Bounces), Reason: enum.EmailSupressionHardBounce},
+		{EmailAddresses: normalizeEmails(report.Complaints), Reason: enum.EmailSupressionComplaint},
+	}
+
+	for _, supression := range supressions {
+		if len(supression.EmailAddresses) == 0 {
+			continue
+		}
+
+		if err := bus.Dispatch(c, supression); err != nil {
+			return err
+		}
+
+		log.Infof(c, "@{Count} account(s) marked with supressed email (@{Reason})", dto.Props{
+			"Count":  supression.NumOfSupressedEmailAddresses,
+			"Reason": supression.Reason.String(),
+		})
+	}
+	return nil
+}
+
+func normalizeEmails(emails []string) []string {
+	result := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			result = append(result, email)
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/tasks"
+)
+
+const mailgunMaxSignatureAge = 15 * time.Minute
+
+// IncomingMailgunEmail handles emails forwarded by Mailgun Routes to Fider
+func IncomingMailgunEmail() web.HandlerFunc {
+	return func(c *web.Context) error {
+		msg, params, err := parseMailgunMessage(c)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if !verifyMailgunSig(params, env.Config.Email.Mailgun.WebhookSigningKey) {
+			return c.Unauthorized()
+		}
+
+		c.Enqueue(tasks.ReceiveEmail(msg))
+		return c.Ok(web.Map{})
+	}
+}
+
+// Mailgun forwards messages as multipart/form-data when they have attachments
+func parseMailgunMessage(c *web.Context) (*inbound.Message, url.Values, error) {
+	var params url.Values
+	attachments := make([]*inbound.Attachment, 0)
+
+	mediaType, mediaParams, err := mime.ParseMediaType(c.Request.GetHeader("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		if params, err = url.ParseQuery(c.Request.Body); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse mailgun form")
+		}
+	} else {
+		form, err := multipart.NewReader(strings.NewReader(c.Request.Body), mediaParams["boundary"]).ReadForm(10 << 20)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse mailgun form")
+		}
+		defer func() {
+			_ = form.RemoveAll()
+		}()
+
+		params = url.Values(form.Value)
+		// Attachments are named attachment-1, attachment-2, etc.
+		names := make([]string, 0, len(form.File))
+		for name := range form.File {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			for _, file := range form.File[name] {
+				attachment, err := readMailgunAttachment(file)
+				if err != nil {
+					return nil, nil, err
+				}
+				attachments = append(attachments, attachment)
+			}
+		}
+	}
+
+	sender := params.Get("sender")
+	msg := &inbound.Message{
+		From:        sender,
+		To:          strings.Split(params.Get("recipient"), ","),
+		Subject:     params.Get("subject"),
+		Text:        params.Get("body-plain"),
+		Attachments: attachments,
+	}
+	if from, err := mail.ParseAddress(params.Get("from")); err == nil {
+		msg.From = from.Address
+		msg.FromName = from.Name
+	}
+	msg.SenderAuthenticated = isMailgunSenderAuthenticated(params, sender, msg.From)
+
+	return msg, params, nil
+}
+
+// isMailgunSenderAuthenticated returns true if the SPF or DKIM checks done by Mailgun passed for the domain of the From address
+func isMailgunSenderAuthenticated(params url.Values, sender, from string) bool {
+	if strings.EqualFold(params.Get("X-Mailgun-Spf"), "pass") && inbound.IsAlignedDomain(sender, from) {
+		return true
+	}
+
+	if !strings.EqualFold(params.Get("X-Mailgun-Dkim-Check-Result"), "pass") {
+		return false
+	}
+
+	// Mailgun doesn't tell which signature passed, so every signature must be aligned with the From address
+	var headers [][]string
+	if err := json.Unmarshal([]byte(params.Get("message-headers")), &headers); err != nil {
+		return false
+	}
+
+	signed := false
+	for _, header := range headers {
+		if len(header) != 2 || !strings.EqualFold(header[0], "DKIM-Signature") {
+			continue
+		}
+		if !inbound.IsAlignedDomain(inbound.DKIMSignatureDomain(header[1]), from) {
+			return false
+		}
+		signed = true
+	}
+	return signed
+}
+
+func readMailgunAttachment(file *multipart.FileHeader) (*inbound.Attachment, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open mailgun attachment")
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read mailgun attachment")
+	}
+
+	contentType, _, _ := mime.ParseMediaType(file.Header.Get("Content-Type"))
+	return &inbound.Attachment{
+		FileName:    file.Filename,
+		ContentType: contentType,
+		Content:     content,
+	}, nil
+}
+
+func verifyMailgunSig(params url.Values, signingKey string) bool {
+	if signingKey == "" {
+		return false
+	}
+
+	timestamp := params.Get("timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(seconds, 0)).Abs() > mailgunMaxSignatureAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(timestamp + params.Get("token")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(params.Get("signature")))
+}
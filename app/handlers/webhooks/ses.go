@@ -0,0 +1,201 @@
+package webhooks
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+var snsHostRegex = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           struct {
+		BounceType        string         `json:"bounceType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// IncomingSESNotification handles bounce and complaint notifications that AWS SES publishes to an SNS topic
+func IncomingSESNotification() web.HandlerFunc {
+	return func(c *web.Context) error {
+		msg := &snsMessage{}
+		if err := json.Unmarshal([]byte(c.Request.Body), msg); err != nil {
+			return c.BadRequest(web.Map{"body": "Invalid SNS message."})
+		}
+
+		if msg.TopicArn != env.Config.Email.AWSSES.SNSTopicARN {
+			return c.Unauthorized()
+		}
+
+		if err := verifySNSSig(c, msg); err != nil {
+			log.Warnf(c, "Invalid SNS message signature: @{Error}", dto.Props{
+				"Error": err.Error(),
+			})
+			return c.Unauthorized()
+		}
+
+		switch msg.Type {
+		case "SubscriptionConfirmation":
+			return confirmSNSSubscription(c, msg)
+		case "Notification":
+			return handleSESNotification(c, msg)
+		default:
+			return c.Ok(web.Map{})
+		}
+	}
+}
+
+func confirmSNSSubscription(c *web.Context, msg *snsMessage) error {
+	if !isSNSURL(msg.SubscribeURL) {
+		return c.BadRequest(web.Map{"SubscribeURL": "Invalid subscription URL."})
+	}
+
+	req := &cmd.HTTPRequest{
+		Method: "GET",
+		URL:    msg.SubscribeURL,
+	}
+	if err := bus.Dispatch(c, req); err != nil {
+		return c.Failure(errors.Wrap(err, "failed to confirm SNS subscription"))
+	}
+	if req.ResponseStatusCode >= 300 {
+		return c.Failure(errors.New("unexpected status code while confirming SNS subscription: %d", req.ResponseStatusCode))
+	}
+
+	return c.Ok(web.Map{})
+}
+
+func handleSESNotification(c *web.Context, msg *snsMessage) error {
+	notification := &sesNotification{}
+	if err := json.Unmarshal([]byte(msg.Message), notification); err != nil {
+		return c.BadRequest(web.Map{"Message": "Invalid SES notification."})
+	}
+
+	// Notifications use notificationType while configuration set events use eventType
+	notificationType := notification.NotificationType
+	if notificationType == "" {
+		notificationType = notification.EventType
+	}
+
+	report := bounceReport{}
+	switch notificationType {
+	case "Bounce":
+		// Transient bounces such as full mailboxes might succeed later on
+		if notification.Bounce.BounceType == "Permanent" {
+			for _, recipient := range notification.Bounce.BouncedRecipients {
+				report.Bounces = append(report.Bounces, recipient.EmailAddress)
+			}
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			report.Complaints = append(report.Complaints, recipient.EmailAddress)
+		}
+	}
+
+	if err := supressReportedEmails(c, report); err != nil {
+		return c.Failure(err)
+	}
+
+	return c.Ok(web.Map{})
+}
+
+func verifySNSSig(c *web.Context, msg *snsMessage) error {
+	if !isSNSURL(msg.SigningCertURL) || !strings.HasSuffix(msg.SigningCertURL, ".pem") {
+		return errors.New("invalid signing certificate URL '%s'", msg.SigningCertURL)
+	}
+
+	algorithm := x509.SHA1WithRSA
+	if msg.SignatureVersion == "2" {
+		algorithm = x509.SHA256WithRSA
+	} else if msg.SignatureVersion != "1" {
+		return errors.New("unsupported signature version '%s'", msg.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature")
+	}
+
+	req := &cmd.HTTPRequest{
+		Method: "GET",
+		URL:    msg.SigningCertURL,
+	}
+	if err := bus.Dispatch(c, req); err != nil {
+		return errors.Wrap(err, "failed to download signing certificate")
+	}
+
+	block, _ := pem.Decode(req.ResponseBody)
+	if block == nil {
+		return errors.New("failed to decode signing certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse signing certificate")
+	}
+
+	return cert.CheckSignature(algorithm, snsStringToSign(msg), signature)
+}
+
+// snsStringToSign builds the string that SNS signs, which depends on the type of the message
+func snsStringToSign(msg *snsMessage) []byte {
+	fields := [][2]string{{"Message", msg.Message}, {"MessageId", msg.MessageID}}
+	if msg.Type == "Notification" {
+		if msg.Subject != "" {
+			fields = append(fields, [2]string{"Subject", msg.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", msg.Timestamp})
+	} else {
+		fields = append(fields,
+			[2]string{"SubscribeURL", msg.SubscribeURL},
+			[2]string{"Timestamp", msg.Timestamp},
+			[2]string{"Token", msg.Token},
+		)
+	}
+	fields = append(fields, [2]string{"TopicArn", msg.TopicArn}, [2]string{"Type", msg.Type})
+
+	var builder strings.Builder
+	for _, field := range fields {
+		builder.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return []byte(builder.String())
+}
+
+func isSNSURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "https" && snsHostRegex.MatchString(u.Hostname())
+}
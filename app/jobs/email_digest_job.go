@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+type EmailDigestJobHandler struct {
+}
+
+func (e EmailDigestJobHandler) Schedule() string {
+	return "0 0 8 * * *" // every day at 8:00
+}
+
+func (e EmailDigestJobHandler) Run(ctx Context) error {
+	frequencies := []string{enum.EmailDigestFrequencyDaily}
+	if time.Now().Weekday() == time.Monday {
+		frequencies = append(frequencies, enum.EmailDigestFrequencyWeekly)
+	}
+
+	count := 0
+	for _, frequency := range frequencies {
+		q := &query.GetPendingEmailDigests{Frequency: frequency}
+		if err := bus.Dispatch(ctx, q); err != nil {
+			return errors.Wrap(err, "failed to get pending %s email digests", frequency)
+		}
+
+		for _, digest := range q.Result {
+			if err := sendEmailDigest(ctx, frequency, digest); err != nil {
+				return err
+			}
+			count++
+		}
+	}
+
+	log.Debugf(ctx, "@{Count} email digest(s) sent", dto.Props{
+		"Count": count,
+	})
+
+	return nil
+}
+
+func sendEmailDigest(ctx context.Context, frequency string, digest *entity.EmailDigest) error {
+	ctx, err := web.WithTenantRequest(ctx, digest.Tenant)
+	if err != nil {
+		return err
+	}
+
+	baseURL, logoURL := web.BaseURL(ctx), web.LogoURL(ctx)
+	items := make([]dto.Props, 0, len(digest.Items))
+	ids := make([]int, 0, len(digest.Items))
+	for _, item := range digest.Items {
+		link := ""
+		if item.Link != "" {
+			link = baseURL + item.Link
+		}
+		items = append(items, dto.Props{
+			"title": item.Title,
+			"link":  link,
+		})
+		ids = append(ids, item.ID)
+	}
+
+	// A digest groups every event, so unsubscribing from it stops all email notifications
+	token, err := jwt.Encode(&jwt.UnsubscribeClaims{UserID: digest.User.ID})
+	if err != nil {
+		return err
+	}
+	to := dto.NewRecipient(digest.User.Name, digest.User.Email, dto.Props{})
+	to.UnsubscribeURL = fmt.Sprintf("%s/unsubscribe/%s", baseURL, token)
+
+	bus.Publish(ctx, &cmd.SendMail{
+		From:         dto.Recipient{Name: digest.Tenant.Name},
+		To:           []dto.Recipient{to},
+		TemplateName: "digest",
+		Props: dto.Props{
+			"title":    i18n.T(ctx, "email.digest.subject."+frequency),
+			"siteName": digest.Tenant.Name,
+			"items":    items,
+			"change":   fmt.Sprintf("<a href='%s/settings'>%s</a>", baseURL, i18n.T(ctx, "email.subscription.change")),
+			"logo":     logoURL,
+		},
+	})
+
+	if err := bus.Dispatch(ctx, &cmd.DeleteDigestItems{IDs: ids}); err != nil {
+		return errors.Wrap(err, "failed to delete sent digest items")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/services/email/emailmock"
+)
+
+func TestEmailDigestJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.EmailDigestJobHandler{}
+	Expect(job.Schedule()).Equals("0 0 8 * * *")
+}
+
+func TestEmailDigestJob_ShouldSendOneEmailPerUser(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPendingEmailDigests) error {
+		if q.Frequency != enum.EmailDigestFrequencyDaily {
+			return nil
+		}
+
+		q.Result = []*entity.EmailDigest{
+			{
+				Tenant: mock.DemoTenant,
+				User:   mock.AryaStark,
+				Items: []*entity.DigestItem{
+					{ID: 1, Title: "New post: **Add support for TypeScript**", Link: "/posts/1/add-support-for-typescript"},
+					{ID: 2, Title: "**Jon Snow** deleted **Dark mode**"},
+				},
+			},
+		}
+		return nil
+	})
+
+	deletedIDs := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeleteDigestItems) error {
+		deletedIDs = append(deletedIDs, c.IDs...)
+		return nil
+	})
+
+	job := &jobs.EmailDigestJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("digest")
+	Expect(emailmock.MessageHistory[0].Tenant).Equals(mock.DemoTenant)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID})
+	to := dto.NewRecipient(mock.AryaStark.Name, mock.AryaStark.Email, dto.Props{})
+	to.UnsubscribeURL = "https://demo.test.fider.io/unsubscribe/" + unsubscribeToken
+	Expect(emailmock.MessageHistory[0].To).Equals([]dto.Recipient{to})
+	Expect(emailmock.MessageHistory[0].Props["title"]).Equals("Your daily digest")
+	Expect(emailmock.MessageHistory[0].Props["items"]).Equals([]dto.Props{
+		{"title": "New post: **Add support for TypeScript**", "link": "https://demo.test.fider.io/posts/1/add-support-for-typescript"},
+		{"title": "**Jon Snow** deleted **Dark mode**", "link": ""},
+	})
+	Expect(deletedIDs).Equals([]int{1, 2})
+}
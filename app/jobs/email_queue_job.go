@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// maxEmailAttempts is how many times an email is sent to the email provider before giving up
+const maxEmailAttempts = 5
+
+type EmailQueueJobHandler struct {
+}
+
+func (e EmailQueueJobHandler) Schedule() string {
+	return "*/10 * * * * *" // every 10 seconds
+}
+
+func (e EmailQueueJobHandler) Run(ctx Context) error {
+	// Rate limits are per minute and this job runs 6 times per minute
+	limit := env.EmailRateLimit() / 6
+	if limit < 1 {
+		limit = 1
+	}
+
+	q := &query.GetQueuedEmails{Limit: limit}
+	if err := bus.Dispatch(ctx, q); err != nil {
+		return errors.Wrap(err, "failed to get queued emails")
+	}
+
+	sent, failed := 0, 0
+	tenants := make(map[int]*entity.Tenant)
+	for _, outboundEmail := range q.Result {
+		emailCtx, err := getOutboundEmailContext(ctx, tenants, outboundEmail.TenantID)
+		if err != nil {
+			return err
+		}
+
+		if err := bus.Dispatch(emailCtx, &cmd.DeliverEmail{Email: outboundEmail}); err != nil {
+			if err := markEmailAsFailed(ctx, outboundEmail, err); err != nil {
+				return err
+			}
+			failed++
+			continue
+		}
+
+		if err := bus.Dispatch(ctx, &cmd.MarkEmailAsSent{ID: outboundEmail.ID}); err != nil {
+			return errors.Wrap(err, "failed to mark email as sent")
+		}
+		sent++
+	}
+
+	// Emails were already sent at this point, so failing to purge must not fail the run
+	purge := &cmd.PurgeOutboundEmails{Before: time.Now().AddDate(0, 0, -30)}
+	if err := bus.Dispatch(ctx, purge); err != nil {
+		log.Error(ctx, errors.Wrap(err, "failed to purge old outbound emails"))
+	}
+
+	log.Debugf(ctx, "@{Sent} email(s) sent, @{Failed} failed and @{Purged} purged", dto.Props{
+		"Sent":   sent,
+		"Failed": failed,
+		"Purged": purge.NumOfDeletedEmails,
+	})
+
+	return nil
+}
+
+// markEmailAsFailed schedules the next attempt of an email with an increasing delay, or gives up after maxEmailAttempts
+func markEmailAsFailed(ctx context.Context, outboundEmail *entity.OutboundEmail, sendErr error) error {
+	attempts := outboundEmail.Attempts + 1
+	markAsFailed := &cmd.MarkEmailAsFailed{
+		ID:    outboundEmail.ID,
+		Error: sendErr.Error(),
+	}
+
+	if attempts < maxEmailAttempts {
+		retryAt := time.Now().Add(time.Duration(attempts*attempts) * time.Minute)
+		markAsFailed.RetryAt = &retryAt
+	}
+
+	log.Warnf(ctx, "Failed to send email @{EmailID} to @{Address} (attempt @{Attempts}): @{Error}", dto.Props{
+		"EmailID":  outboundEmail.ID,
+		"Address":  outboundEmail.ToAddress,
+		"Attempts": attempts,
+		"Error":    sendErr.Error(),
+	})
+
+	if err := bus.Dispatch(ctx, markAsFailed); err != nil {
+		return errors.Wrap(err, "failed to mark email as failed")
+	}
+	return nil
+}
+
+// getOutboundEmailContext returns the context of the tenant that queued an email, so that email providers can tag it
+func getOutboundEmailContext(ctx context.Context, tenants map[int]*entity.Tenant, tenantID int) (context.Context, error) {
+	if tenantID == 0 {
+		return ctx, nil
+	}
+
+	tenant, ok := tenants[tenantID]
+	if !ok {
+		getTenant := &query.GetTenantByID{TenantID: tenantID}
+		if err := bus.Dispatch(ctx, getTenant); err != nil {
+			return nil, errors.Wrap(err, "failed to get tenant '%d' of queued email", tenantID)
+		}
+		tenant = getTenant.Result
+		tenants[tenantID] = tenant
+	}
+
+	return web.WithTenantRequest(ctx, tenant)
+}
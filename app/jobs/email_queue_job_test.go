@@ -0,0 +1,146 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/jobs"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/mock"
+)
+
+func TestEmailQueueJob_Schedule_IsCorrect(t *testing.T) {
+	RegisterT(t)
+
+	job := &jobs.EmailQueueJobHandler{}
+	Expect(job.Schedule()).Equals("*/10 * * * * *")
+}
+
+func TestEmailQueueJob_ShouldSendQueuedEmails(t *testing.T) {
+	RegisterT(t)
+	env.Config.Email.Type = "smtp"
+	env.Config.Email.SMTP.RateLimit = 60
+
+	var limit int
+	bus.AddHandler(func(ctx context.Context, q *query.GetQueuedEmails) error {
+		limit = q.Limit
+		q.Result = []*entity.OutboundEmail{
+			{ID: 1, TenantID: mock.DemoTenant.ID, ToAddress: "jon.snow@got.com", Attempts: 0},
+			{ID: 2, TenantID: mock.DemoTenant.ID, ToAddress: "arya.stark@got.com", Attempts: 1},
+			{ID: 3, TenantID: mock.DemoTenant.ID, ToAddress: "sansa.stark@got.com", Attempts: 4},
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		q.Result = mock.DemoTenant
+		return nil
+	})
+
+	deliveredTenants := make([]*entity.Tenant, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeliverEmail) error {
+		deliveredTenants = append(deliveredTenants, ctx.Value(app.TenantCtxKey).(*entity.Tenant))
+		if c.Email.ID == 1 {
+			return nil
+		}
+		return errors.New("connection refused")
+	})
+
+	sentIDs := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkEmailAsSent) error {
+		sentIDs = append(sentIDs, c.ID)
+		return nil
+	})
+
+	failed := make(map[int]*cmd.MarkEmailAsFailed)
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkEmailAsFailed) error {
+		failed[c.ID] = c
+		return nil
+	})
+
+	var purge *cmd.PurgeOutboundEmails
+	bus.AddHandler(func(ctx context.Context, c *cmd.PurgeOutboundEmails) error {
+		purge = c
+		return nil
+	})
+
+	job := &jobs.EmailQueueJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+
+	Expect(limit).Equals(10)
+	Expect(deliveredTenants).Equals([]*entity.Tenant{mock.DemoTenant, mock.DemoTenant, mock.DemoTenant})
+	Expect(bus.GetCallCount(&query.GetTenantByID{})).Equals(1)
+	Expect(sentIDs).Equals([]int{1})
+
+	Expect(failed).HasLen(2)
+	Expect(failed[2].Error).Equals("connection refused")
+	Expect(failed[2].RetryAt).IsNotNil()
+	Expect(*failed[2].RetryAt).TemporarilySimilar(time.Now().Add(4*time.Minute), 5*time.Second)
+	Expect(failed[3].Error).Equals("connection refused")
+	Expect(failed[3].RetryAt).IsNil()
+
+	Expect(purge.Before).TemporarilySimilar(time.Now().AddDate(0, 0, -30), 5*time.Second)
+}
+
+func TestEmailQueueJob_ShouldSendAtLeastOneEmailPerRun(t *testing.T) {
+	RegisterT(t)
+	env.Config.Email.Type = "smtp"
+	env.Config.Email.SMTP.RateLimit = 1
+
+	var limit int
+	bus.AddHandler(func(ctx context.Context, q *query.GetQueuedEmails) error {
+		limit = q.Limit
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.PurgeOutboundEmails) error {
+		return nil
+	})
+
+	job := &jobs.EmailQueueJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(limit).Equals(1)
+}
+
+func TestEmailQueueJob_ShouldNotFailWhenPurgeFails(t *testing.T) {
+	RegisterT(t)
+	env.Config.Email.Type = "smtp"
+	env.Config.Email.SMTP.RateLimit = 60
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetQueuedEmails) error {
+		q.Result = []*entity.OutboundEmail{{ID: 1, ToAddress: "jon.snow@got.com"}}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeliverEmail) error {
+		return nil
+	})
+
+	sentIDs := make([]int, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkEmailAsSent) error {
+		sentIDs = append(sentIDs, c.ID)
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.PurgeOutboundEmails) error {
+		return errors.New("canceling statement due to lock timeout")
+	})
+
+	job := &jobs.EmailQueueJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(sentIDs).Equals([]int{1})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/errors"
@@ -35,13 +36,26 @@ func (e EmailSupressionJobHandler) Run(ctx Context) error {
 
 	c := &cmd.SupressEmail{
 		EmailAddresses: q.EmailAddresses,
+		Reason:         enum.EmailSupressionHardBounce,
 	}
 	if err := bus.Dispatch(ctx, c); err != nil {
 		return errors.Wrap(err, "failed to supress emails")
 	}
+	count := c.NumOfSupressedEmailAddresses
+
+	if len(q.ComplainedAddresses) > 0 {
+		c = &cmd.SupressEmail{
+			EmailAddresses: q.ComplainedAddresses,
+			Reason:         enum.EmailSupressionComplaint,
+		}
+		if err := bus.Dispatch(ctx, c); err != nil {
+			return errors.Wrap(err, "failed to supress emails")
+		}
+		count += c.NumOfSupressedEmailAddresses
+	}
 
 	log.Debugf(ctx, "@{Count} account(s) marked with supressed email", dto.Props{
-		"Count": c.NumOfSupressedEmailAddresses,
+		"Count": count,
 	})
 
 	return nil
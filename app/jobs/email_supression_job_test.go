@@ -6,6 +6,7 @@ import (
 
 	"github.com/getfider/fider/app/jobs"
 	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
@@ -39,3 +40,30 @@ func TestEmailSupressionJob_ShouldSupressRecentFailures(t *testing.T) {
 	})
 	Expect(err).IsNil()
 }
+
+func TestEmailSupressionJob_ShouldSupressRecentComplaints(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.FetchRecentSupressions) error {
+		q.EmailAddresses = []string{"test1@gmail.com"}
+		q.ComplainedAddresses = []string{"test2@gmail.com"}
+		return nil
+	})
+
+	supressed := make(map[enum.EmailSupressionReason][]string)
+	bus.AddHandler(func(ctx context.Context, c *cmd.SupressEmail) error {
+		supressed[c.Reason] = c.EmailAddresses
+		c.NumOfSupressedEmailAddresses = len(c.EmailAddresses)
+		return nil
+	})
+
+	job := &jobs.EmailSupressionJobHandler{}
+	err := job.Run(jobs.Context{
+		Context: context.Background(),
+	})
+	Expect(err).IsNil()
+	Expect(supressed).Equals(map[enum.EmailSupressionReason][]string{
+		enum.EmailSupressionHardBounce: {"test1@gmail.com"},
+		enum.EmailSupressionComplaint:  {"test2@gmail.com"},
+	})
+}
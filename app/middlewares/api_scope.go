@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type apiScopeRule struct {
+	method  string
+	pattern string
+	scope   string
+}
+
+// apiScopeRules lists the scope needed by each API route, routes that are not listed require the admin scope
+var apiScopeRules = []apiScopeRule{
+	{http.MethodGet, "/api/v1/userinfo", entity.OAuthScopeOpenID},
+
+	{http.MethodGet, "/api/v1/similarposts", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts/:number", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts/:number/comments", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts/:number/comments/:id", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts/:number/revisions", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/posts/:number/revisions/:id/diff", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/tags", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/custom-fields", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/taggable-users", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/votes/budget", entity.APIScopePostsRead},
+	{http.MethodGet, "/api/v1/roadmap", entity.APIScopePostsRead},
+
+	{http.MethodPost, "/api/v1/posts", entity.APIScopePostsWrite},
+	{http.MethodPut, "/api/v1/posts/:number", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/comments", entity.APIScopePostsWrite},
+	{http.MethodPut, "/api/v1/posts/:number/comments/:id", entity.APIScopePostsWrite},
+	{http.MethodDelete, "/api/v1/posts/:number/comments/:id", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/comments/:id/reactions/:reaction", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/votes", entity.APIScopePostsWrite},
+	{http.MethodDelete, "/api/v1/posts/:number/votes", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/votes/toggle", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/votes/proxy", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/subscription", entity.APIScopePostsWrite},
+	{http.MethodDelete, "/api/v1/posts/:number/subscription", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/subscription/mute", entity.APIScopePostsWrite},
+	{http.MethodDelete, "/api/v1/posts/:number/subscription/mute", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/tags/:slug", entity.APIScopePostsWrite},
+	{http.MethodDelete, "/api/v1/posts/:number/tags/:slug", entity.APIScopePostsWrite},
+	{http.MethodPut, "/api/v1/posts/:number/custom-fields", entity.APIScopePostsWrite},
+	{http.MethodPost, "/api/v1/posts/:number/revisions/:id/restore", entity.APIScopePostsWrite},
+
+	{http.MethodPost, "/api/v1/roadmap/posts/:number/assign", entity.APIScopeRoadmapWrite},
+	{http.MethodDelete, "/api/v1/roadmap/posts/:number/assign", entity.APIScopeRoadmapWrite},
+	{http.MethodPut, "/api/v1/roadmap/posts/:number/position", entity.APIScopeRoadmapWrite},
+	{http.MethodGet, "/api/v1/admin/roadmap/columns", entity.APIScopeRoadmapWrite},
+	{http.MethodPost, "/api/v1/admin/roadmap/columns", entity.APIScopeRoadmapWrite},
+	{http.MethodPut, "/api/v1/admin/roadmap/columns/:id", entity.APIScopeRoadmapWrite},
+	{http.MethodDelete, "/api/v1/admin/roadmap/columns/:id", entity.APIScopeRoadmapWrite},
+	{http.MethodPut, "/api/v1/admin/roadmap/reorder-columns", entity.APIScopeRoadmapWrite},
+
+	{http.MethodGet, "/api/v1/users", entity.APIScopeUsersRead},
+	{http.MethodGet, "/api/v1/companies", entity.APIScopeUsersRead},
+	{http.MethodGet, "/api/v1/posts/:number/votes", entity.APIScopeUsersRead},
+
+	{http.MethodPut, "/api/v1/posts/:number/status", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/posts/:number/privacy", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/posts/:number/group", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/posts/:number/merge", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/posts/:number/merge", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/posts/:number", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/tags", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/tags/:slug", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/tags/:slug", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/custom-fields", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/custom-fields/:key", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/custom-fields/:key", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/users", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/users/:userID/company", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/users/:userID/custom-role", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/companies", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/companies/:id", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/companies/:id", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/invitations/send", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/invitations/sample", entity.APIScopeAdmin},
+	{http.MethodGet, "/api/v1/audit-log", entity.APIScopeAdmin},
+	{http.MethodGet, "/api/v1/roles", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/roles", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/roles/:id", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/roles/:id", entity.APIScopeAdmin},
+	{http.MethodGet, "/api/v1/groups", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/groups", entity.APIScopeAdmin},
+	{http.MethodPut, "/api/v1/groups/:id", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/groups/:id", entity.APIScopeAdmin},
+	{http.MethodGet, "/api/v1/groups/:id/members", entity.APIScopeAdmin},
+	{http.MethodPost, "/api/v1/groups/:id/members/:userID", entity.APIScopeAdmin},
+	{http.MethodDelete, "/api/v1/groups/:id/members/:userID", entity.APIScopeAdmin},
+}
+
+// RequiredAPIScope returns the scope an API token needs to perform given request
+// The second value is false when the route isn't listed, in which case the admin scope is required
+func RequiredAPIScope(method, path string) (string, bool) {
+	if strings.HasPrefix(path, "/scim/") {
+		return entity.APIScopeSCIM, true
+	}
+
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	for _, rule := range apiScopeRules {
+		if rule.method == method && matchRoutePattern(rule.pattern, path) {
+			return rule.scope, true
+		}
+	}
+	return entity.APIScopeAdmin, false
+}
+
+// matchRoutePattern returns true if given path matches a route pattern, where segments starting with ':' match any value
+func matchRoutePattern(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+		} else if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
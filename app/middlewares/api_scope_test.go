@@ -0,0 +1,44 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/entity"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestRequiredAPIScope(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		method string
+		path   string
+		scope  string
+		mapped bool
+	}{
+		{http.MethodGet, "/api/v1/posts", entity.APIScopePostsRead, true},
+		{http.MethodHead, "/api/v1/posts/12", entity.APIScopePostsRead, true},
+		{http.MethodPost, "/api/v1/posts", entity.APIScopePostsWrite, true},
+		{http.MethodPost, "/api/v1/posts/12/comments", entity.APIScopePostsWrite, true},
+		{http.MethodGet, "/api/v1/posts/12/votes", entity.APIScopeUsersRead, true},
+		{http.MethodDelete, "/api/v1/posts/12", entity.APIScopeAdmin, true},
+		{http.MethodPut, "/api/v1/posts/12/status", entity.APIScopeAdmin, true},
+		{http.MethodPost, "/api/v1/posts/12/merge", entity.APIScopeAdmin, true},
+		{http.MethodGet, "/api/v1/roles", entity.APIScopeAdmin, true},
+		{http.MethodPost, "/api/v1/groups/3/members/4", entity.APIScopeAdmin, true},
+		{http.MethodPut, "/api/v1/roadmap/posts/12/position", entity.APIScopeRoadmapWrite, true},
+		{http.MethodGet, "/api/v1/userinfo", entity.OAuthScopeOpenID, true},
+		{http.MethodPost, "/scim/v2/Users", entity.APIScopeSCIM, true},
+		{http.MethodPost, "/api/v1/something-new", entity.APIScopeAdmin, false},
+		{http.MethodGet, "/api/v1/posts/12/unknown", entity.APIScopeAdmin, false},
+		{http.MethodGet, "/api/v1/posts//comments", entity.APIScopeAdmin, false},
+	}
+
+	for _, testCase := range testCases {
+		scope, mapped := middlewares.RequiredAPIScope(testCase.method, testCase.path)
+		Expect(scope).Equals(testCase.scope)
+		Expect(mapped).Equals(testCase.mapped)
+	}
+}
@@ -1,8 +1,14 @@
 package middlewares
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
+
 	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/scim"
 	"github.com/getfider/fider/app/pkg/web"
+	webutil "github.com/getfider/fider/app/pkg/web/util"
 )
 
 // IsAuthenticated blocks non-authenticated requests
@@ -31,3 +37,49 @@ func IsAuthorized(roles ...enum.Role) web.MiddlewareFunc {
 		}
 	}
 }
+
+// HasPermission blocks requests of users that are not granted given permission
+func HasPermission(permission string) web.MiddlewareFunc {
+	return func(next web.HandlerFunc) web.HandlerFunc {
+		return func(c *web.Context) error {
+			if !c.User().HasPermission(permission) {
+				return c.Forbidden()
+			}
+			return next(c)
+		}
+	}
+}
+
+// IsRecentlyReauthenticated asks users with two-factor enabled to confirm it again before sensitive actions
+// Requests authenticated by API keys are not affected, as there is no way to prompt for a code
+func IsRecentlyReauthenticated() web.MiddlewareFunc {
+	return func(next web.HandlerFunc) web.HandlerFunc {
+		return func(c *web.Context) error {
+			user := c.User()
+			if _, err := c.Request.Cookie(web.CookieAuthName); err != nil || !user.HasTwoFactor || webutil.HasRecentReauth(c, user) {
+				return next(c)
+			}
+
+			if c.Request.Method == http.MethodGet && !c.IsAjax() {
+				return c.Redirect("/signin/2fa?redirect=" + url.QueryEscape(c.Request.URL.RequestURI()))
+			}
+			return c.JSON(http.StatusForbidden, web.Map{"reauthenticate": true})
+		}
+	}
+}
+
+// IsSCIMClient blocks requests that are not made by an administrator's bearer token
+// A bearer token can't be attached by another site, so SCIM endpoints don't need CSRF protection
+func IsSCIMClient() web.MiddlewareFunc {
+	return func(next web.HandlerFunc) web.HandlerFunc {
+		return func(c *web.Context) error {
+			if !strings.HasPrefix(c.Request.GetHeader("Authorization"), "Bearer ") || !c.IsAuthenticated() {
+				return scim.RespondError(c, http.StatusUnauthorized, "", "A bearer token is required")
+			}
+			if !c.User().IsAdministrator() {
+				return scim.RespondError(c, http.StatusForbidden, "", "Only administrators can provision users")
+			}
+			return next(c)
+		}
+	}
+}
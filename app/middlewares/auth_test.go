@@ -3,10 +3,13 @@ package middlewares_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/pkg/web"
 )
@@ -35,6 +38,32 @@ func TestIsAuthorized_WithForbiddenRole(t *testing.T) {
 	Expect(status).Equals(http.StatusForbidden)
 }
 
+func TestHasPermission_WithCustomRole(t *testing.T) {
+	RegisterT(t)
+
+	agent := &entity.User{
+		ID:         3,
+		Name:       "Sansa Stark",
+		Tenant:     mock.DemoTenant,
+		Role:       enum.RoleCollaborator,
+		CustomRole: &entity.CustomRole{Name: "Support Agent", Permissions: []string{entity.PermissionRespond}},
+	}
+
+	server := mock.NewServer()
+	server.Use(middlewares.HasPermission(entity.PermissionRespond))
+	status, _ := server.AsUser(agent).Execute(func(c *web.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	Expect(status).Equals(http.StatusOK)
+
+	server = mock.NewServer()
+	server.Use(middlewares.HasPermission(entity.PermissionManageWebhooks))
+	status, _ = server.AsUser(agent).Execute(func(c *web.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	Expect(status).Equals(http.StatusForbidden)
+}
+
 func TestIsAuthenticated_WithUser(t *testing.T) {
 	RegisterT(t)
 
@@ -59,3 +88,96 @@ func TestIsAuthenticated_WithoutUser(t *testing.T) {
 
 	Expect(status).Equals(http.StatusUnauthorized)
 }
+
+func TestIsSCIMClient_WithAdministratorToken(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	server.Use(middlewares.IsSCIMClient())
+	status, _ := server.
+		AsUser(mock.JonSnow).
+		AddHeader("Authorization", "Bearer fdr_scim").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusOK)
+}
+
+func TestIsSCIMClient_WithoutToken(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	server.Use(middlewares.IsSCIMClient())
+	status, response := server.
+		AsUser(mock.JonSnow).
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusUnauthorized)
+	Expect(response.Header().Get("Content-Type")).Equals("application/scim+json; charset=utf-8")
+}
+
+func TestIsSCIMClient_WithVisitorToken(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	server.Use(middlewares.IsSCIMClient())
+	status, _ := server.
+		AsUser(mock.AryaStark).
+		AddHeader("Authorization", "Bearer fdr_scim").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusForbidden)
+}
+
+func TestIsRecentlyReauthenticated(t *testing.T) {
+	RegisterT(t)
+
+	admin := &entity.User{
+		ID:           1,
+		Name:         "Jon Snow",
+		Tenant:       mock.DemoTenant,
+		Role:         enum.RoleAdministrator,
+		HasTwoFactor: true,
+	}
+	reauth, _ := jwt.Encode(jwt.ReauthClaims{
+		UserID:   admin.ID,
+		Metadata: jwt.Metadata{ExpiresAt: jwt.Time(time.Now().Add(time.Minute))},
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.IsRecentlyReauthenticated())
+	status, response := server.AsUser(admin).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, "token").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+	Expect(status).Equals(http.StatusForbidden)
+	Expect(response.Body.String()).ContainsSubstring("reauthenticate")
+
+	server = mock.NewServer()
+	server.Use(middlewares.IsRecentlyReauthenticated())
+	status, _ = server.AsUser(admin).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, "token").
+		AddCookie(web.CookieReauthName, reauth).
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+	Expect(status).Equals(http.StatusOK)
+
+	// API keys can't be asked for a code
+	server = mock.NewServer()
+	server.Use(middlewares.IsRecentlyReauthenticated())
+	status, _ = server.AsUser(admin).
+		AddHeader("Accept", "application/json").
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+	Expect(status).Equals(http.StatusOK)
+}
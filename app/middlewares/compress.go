@@ -20,7 +20,9 @@ type gzipResponseWriter struct {
 func Compress() web.MiddlewareFunc {
 	return func(next web.HandlerFunc) web.HandlerFunc {
 		return func(c *web.Context) error {
-			if strings.Contains(c.Request.GetHeader("Accept-Encoding"), "gzip") {
+			// Event streams must be flushed as they're written, which gzip.Writer doesn't support
+			isEventStream := strings.Contains(c.Request.GetHeader("Accept"), "text/event-stream")
+			if strings.Contains(c.Request.GetHeader("Accept-Encoding"), "gzip") && !isEventStream {
 				res := c.Response
 				res.Header().Set("Content-Encoding", "gzip")
 				res.Header().Del("Accept-Encoding")
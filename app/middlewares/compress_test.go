@@ -58,3 +58,25 @@ func TestCompress_AfterPanic(t *testing.T) {
 	Expect(response.Header().Get("Content-Type")).Equals("text/html; charset=utf-8")
 	Expect(response.Header().Get("Content-Encoding")).Equals("gzip")
 }
+
+func TestCompress_EventStream(t *testing.T) {
+	RegisterT(t)
+
+	server := mock.NewServer()
+	server.Use(middlewares.Compress())
+	handler := func(c *web.Context) error {
+		c.Response.Header().Set("Content-Type", "text/event-stream")
+		_, _ = c.Response.Write([]byte("retry: 5000\n\n"))
+		c.Response.Flush()
+		return nil
+	}
+
+	status, response := server.
+		AddHeader("Accept-Encoding", "gzip").
+		AddHeader("Accept", "text/event-stream").
+		Execute(handler)
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(response.Body.String()).Equals("retry: 5000\n\n")
+	Expect(response.Header().Get("Content-Encoding")).Equals("")
+}
@@ -2,9 +2,13 @@ package middlewares
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
@@ -19,13 +23,17 @@ import (
 	webutil "github.com/getfider/fider/app/pkg/web/util"
 )
 
+// sessionTouchInterval is how often the last seen date of a session is updated
+const sessionTouchInterval = 5 * time.Minute
+
 // User gets JWT Auth token from cookie and insert into context
 func User() web.MiddlewareFunc {
 	return func(next web.HandlerFunc) web.HandlerFunc {
 		return func(c *web.Context) error {
 			var (
-				token string
-				user  *entity.User
+				token   string
+				user    *entity.User
+				session *entity.UserSession
 			)
 
 			cookie, err := c.Request.Cookie(web.CookieAuthName)
@@ -45,6 +53,32 @@ func User() web.MiddlewareFunc {
 					return next(c)
 				}
 
+				// users remain anonymous until they provide their second factor
+				if claims.TwoFactorPending {
+					if isTwoFactorGuardedPage(c) {
+						return c.Redirect("/signin/2fa?redirect=" + url.QueryEscape(c.Request.URL.RequestURI()))
+					}
+					return next(c)
+				}
+
+				// tokens are only valid while their session hasn't been revoked
+				// tokens issued before sessions were tracked can't be revoked, so users have to sign in again
+				if claims.SessionKey == "" {
+					c.RemoveCookie(web.CookieAuthName)
+					return next(c)
+				}
+
+				getSession := &query.GetUserSessionByKey{Key: claims.SessionKey}
+				err = bus.Dispatch(c, getSession)
+				if err != nil && errors.Cause(err) != app.ErrNotFound {
+					return err
+				}
+				if err != nil || getSession.Result.UserID != claims.UserID {
+					c.RemoveCookie(web.CookieAuthName)
+					return next(c)
+				}
+				session = getSession.Result
+
 				userByClaimsID := &query.GetUserByID{UserID: claims.UserID}
 				err = bus.Dispatch(c, userByClaimsID)
 				user = userByClaimsID.Result
@@ -60,22 +94,83 @@ func User() web.MiddlewareFunc {
 				parts := strings.Split(authHeader, "Bearer")
 				if len(parts) == 2 {
 					apiKey := strings.TrimSpace(parts[1])
-					getUserByAPIKey := &query.GetUserByAPIKey{APIKey: apiKey}
-					err = bus.Dispatch(c, getUserByAPIKey)
-					if err != nil {
-						if errors.Cause(err) == app.ErrNotFound {
-							return c.HandleValidation(validate.Failed("API Key is invalid"))
+					canImpersonate := false
+					if strings.HasPrefix(apiKey, entity.APITokenKeyPrefix) {
+						getToken := &query.GetAPITokenByKey{Key: apiKey}
+						err = bus.Dispatch(c, getToken)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("API Key is invalid"))
+							}
+							return err
 						}
-						return err
-					}
-					user = getUserByAPIKey.Result
 
-					if !user.IsCollaborator() {
-						return c.HandleValidation(validate.Failed("API Key is invalid"))
+						token := getToken.Result
+						if token.IsExpired() {
+							return c.HandleValidation(validate.Failed("API Key has expired"))
+						}
+
+						scope, _ := RequiredAPIScope(c.Request.Method, c.Request.URL.Path)
+						if !token.HasScope(scope) {
+							return c.HandleValidation(validate.Failed(fmt.Sprintf("API Key is missing the '%s' scope", scope)))
+						}
+
+						if err := bus.Dispatch(c, &cmd.MarkAPITokenAsUsed{TokenID: token.ID}); err != nil {
+							return err
+						}
+
+						user = token.User
+						canImpersonate = user.IsAdministrator() && token.HasScope(entity.APIScopeAdmin)
+					} else if strings.Count(apiKey, ".") == 2 {
+						// Access tokens issued to OAuth clients are JWTs bound to the client that requested them
+						claims, err := jwt.DecodeOAuthAccessTokenClaims(apiKey)
+						if err != nil {
+							return c.HandleValidation(validate.Failed("Access Token is invalid or has expired"))
+						}
+
+						getClient := &query.GetOAuthClientByClientID{ClientID: claims.ClientID}
+						err = bus.Dispatch(c, getClient)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("Access Token is invalid or has expired"))
+							}
+							return err
+						}
+
+						scope, _ := RequiredAPIScope(c.Request.Method, c.Request.URL.Path)
+						if !entity.HasAPIScope(claims.Scopes, scope) {
+							return c.HandleValidation(validate.Failed(fmt.Sprintf("Access Token is missing the '%s' scope", scope)))
+						}
+
+						userByClaimsID := &query.GetUserByID{UserID: claims.UserID}
+						err = bus.Dispatch(c, userByClaimsID)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("Access Token is invalid or has expired"))
+							}
+							return err
+						}
+						user = userByClaimsID.Result
+					} else {
+						// Legacy keys grant everything their collaborator owner can do
+						getUserByAPIKey := &query.GetUserByAPIKey{APIKey: apiKey}
+						err = bus.Dispatch(c, getUserByAPIKey)
+						if err != nil {
+							if errors.Cause(err) == app.ErrNotFound {
+								return c.HandleValidation(validate.Failed("API Key is invalid"))
+							}
+							return err
+						}
+						user = getUserByAPIKey.Result
+
+						if !user.IsCollaborator() {
+							return c.HandleValidation(validate.Failed("API Key is invalid"))
+						}
+						canImpersonate = user.IsAdministrator()
 					}
 
 					if impersonateUserIDStr := c.Request.GetHeader("X-Fider-UserID"); impersonateUserIDStr != "" {
-						if !user.IsAdministrator() {
+						if !canImpersonate {
 							return c.HandleValidation(validate.Failed("Only Administrators are allowed to impersonate another user"))
 						}
 						impersonateUserID, err := strconv.Atoi(impersonateUserIDStr)
@@ -103,9 +198,47 @@ func User() web.MiddlewareFunc {
 				}
 
 				c.SetUser(user)
+
+				if session != nil {
+					c.SetUserSessionID(session.ID)
+					if time.Since(session.LastSeenAt) > sessionTouchInterval {
+						err := bus.Dispatch(c, &cmd.TouchUserSession{
+							SessionID: session.ID,
+							UserAgent: c.Request.GetHeader("User-Agent"),
+							IPAddress: c.Request.ClientIP(),
+						})
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				// staff members can't do anything else until they set up two-factor when it's required
+				if token != "" && user.MustSetupTwoFactor() && !isTwoFactorSetupPath(c.Request.URL.Path) {
+					if isTwoFactorGuardedPage(c) {
+						return c.Redirect("/settings")
+					}
+					if c.Request.Method != http.MethodGet {
+						return c.HandleValidation(validate.Failed("Two-factor authentication must be enabled before continuing."))
+					}
+				}
 			}
 
 			return next(c)
 		}
 	}
 }
+
+// isTwoFactorGuardedPage returns true for page requests that can't be visited until two-factor is completed
+func isTwoFactorGuardedPage(c *web.Context) bool {
+	path := c.Request.URL.Path
+	if c.Request.Method != http.MethodGet || c.IsAjax() || c.Request.IsAPI() || strings.HasPrefix(path, "/_api/") || strings.HasPrefix(path, "/static/") {
+		return false
+	}
+	return path != "/signin/2fa" && path != "/signout"
+}
+
+// isTwoFactorSetupPath returns true for paths that are needed to set up two-factor
+func isTwoFactorSetupPath(path string) bool {
+	return path == "/settings" || path == "/signout" || path == "/signin/2fa" || strings.HasPrefix(path, "/_api/user/2fa/")
+}
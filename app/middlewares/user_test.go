@@ -4,12 +4,14 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/getfider/fider/app"
 
 	"github.com/getfider/fider/app/middlewares"
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
@@ -41,8 +43,9 @@ func TestUser_WithCookie(t *testing.T) {
 
 	server := mock.NewServer()
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   mock.JonSnow.ID,
-		UserName: mock.JonSnow.Name,
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: mockSessionKey(mock.JonSnow.ID),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -73,8 +76,9 @@ func TestUser_Blocked(t *testing.T) {
 	server := mock.NewServer()
 	mock.JonSnow.Status = enum.UserBlocked
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   mock.JonSnow.ID,
-		UserName: mock.JonSnow.Name,
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: mockSessionKey(mock.JonSnow.ID),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -103,8 +107,9 @@ func TestUser_LockedTenant_ShouldAllowSignIn(t *testing.T) {
 	server := mock.NewServer()
 	mock.DemoTenant.Status = enum.TenantLocked
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   mock.AryaStark.ID,
-		UserName: mock.AryaStark.Name,
+		UserID:     mock.AryaStark.ID,
+		UserName:   mock.AryaStark.Name,
+		SessionKey: mockSessionKey(mock.AryaStark.ID),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -131,8 +136,9 @@ func TestUser_WithCookie_InvalidUser(t *testing.T) {
 
 	server := mock.NewServer()
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   999,
-		UserName: "Unknown",
+		UserID:     999,
+		UserName:   "Unknown",
+		SessionKey: mockSessionKey(999),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -163,8 +169,9 @@ func TestUser_WithCookie_DifferentTenant(t *testing.T) {
 
 	server := mock.NewServer()
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   mock.JonSnow.ID,
-		UserName: mock.JonSnow.Name,
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: mockSessionKey(mock.JonSnow.ID),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -190,8 +197,9 @@ func TestUser_WithSignUpCookie(t *testing.T) {
 
 	server := mock.NewServer()
 	token, _ := jwt.Encode(jwt.FiderClaims{
-		UserID:   mock.JonSnow.ID,
-		UserName: mock.JonSnow.Name,
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: mockSessionKey(mock.JonSnow.ID),
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
@@ -426,3 +434,416 @@ func TestUser_Impersonation_ValidUser(t *testing.T) {
 	Expect(status).Equals(http.StatusOK)
 	Expect(response.Body.String()).Equals("Arya Stark")
 }
+
+func TestUser_ValidAPIToken(t *testing.T) {
+	RegisterT(t)
+
+	var markAsUsed *cmd.MarkAPITokenAsUsed
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkAPITokenAsUsed) error {
+		markAsUsed = c
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		if q.Key == "fdr_readonly" {
+			q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopePostsRead}, User: mock.AryaStark}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer fdr_readonly").
+		Execute(func(c *web.Context) error {
+			return c.String(http.StatusOK, c.User().Name)
+		})
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(response.Body.String()).Equals("Arya Stark")
+	Expect(markAsUsed.TokenID).Equals(3)
+}
+
+func TestUser_APIToken_MissingScope(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopePostsRead}, User: mock.JonSnow}
+		return nil
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer fdr_readonly").
+		ExecutePostAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, `{}`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("API Key is missing the 'posts:write' scope")
+}
+
+func TestUser_APIToken_AuditLogRequiresAdminScope(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopePostsRead}, User: mock.JonSnow}
+		return nil
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/audit-log").
+		AddHeader("Authorization", "Bearer fdr_readonly").
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("API Key is missing the 'admin' scope")
+}
+
+func TestUser_APIToken_SCIMScope(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopeUsersRead}, User: mock.JonSnow}
+		return nil
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/scim/v2/Users").
+		AddHeader("Authorization", "Bearer fdr_usersread").
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("API Key is missing the 'scim' scope")
+}
+
+func TestUser_APIToken_Expired(t *testing.T) {
+	RegisterT(t)
+
+	expiresAt := time.Now().Add(-1 * time.Hour)
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopeAdmin}, ExpiresAt: &expiresAt, User: mock.JonSnow}
+		return nil
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer fdr_expired").
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("API Key has expired")
+}
+
+func TestUser_APIToken_ImpersonationRequiresAdminScope(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.MarkAPITokenAsUsed) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetAPITokenByKey) error {
+		q.Result = &entity.APIToken{ID: 3, Scopes: []string{entity.APIScopePostsRead, entity.APIScopePostsWrite}, User: mock.JonSnow}
+		return nil
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer fdr_writer").
+		AddHeader("X-Fider-UserID", strconv.Itoa(mock.AryaStark.ID)).
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("Only Administrators are allowed to impersonate another user")
+}
+
+func TestUser_ValidOAuthAccessToken(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		if q.ClientID == "my-app" {
+			q.Result = &entity.OAuthClient{ID: 1, ClientID: "my-app", Name: "My App"}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		if q.UserID == mock.AryaStark.ID {
+			q.Result = mock.AryaStark
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	token, _ := jwt.Encode(&jwt.OAuthAccessTokenClaims{
+		UserID:   mock.AryaStark.ID,
+		ClientID: "my-app",
+		Scopes:   []string{entity.APIScopePostsRead},
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(time.Hour)),
+		},
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer "+token).
+		Execute(func(c *web.Context) error {
+			return c.String(http.StatusOK, c.User().Name)
+		})
+
+	Expect(status).Equals(http.StatusOK)
+	Expect(response.Body.String()).Equals("Arya Stark")
+}
+
+func TestUser_OAuthAccessToken_MissingScope(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		q.Result = &entity.OAuthClient{ID: 1, ClientID: "my-app", Name: "My App"}
+		return nil
+	})
+
+	token, _ := jwt.Encode(&jwt.OAuthAccessTokenClaims{
+		UserID:   mock.JonSnow.ID,
+		ClientID: "my-app",
+		Scopes:   []string{entity.OAuthScopeOpenID, entity.APIScopePostsRead},
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(time.Hour)),
+		},
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer "+token).
+		ExecutePostAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, `{}`)
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("Access Token is missing the 'posts:write' scope")
+}
+
+func TestUser_OAuthAccessToken_RevokedClient(t *testing.T) {
+	RegisterT(t)
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+		return app.ErrNotFound
+	})
+
+	token, _ := jwt.Encode(&jwt.OAuthAccessTokenClaims{
+		UserID:   mock.JonSnow.ID,
+		ClientID: "my-app",
+		Scopes:   []string{entity.APIScopeAdmin},
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(time.Hour)),
+		},
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer "+token).
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("Access Token is invalid or has expired")
+}
+
+func TestUser_FiderClaimsAsOAuthAccessToken(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(&jwt.FiderClaims{
+		UserID:   mock.JonSnow.ID,
+		UserName: mock.JonSnow.Name,
+	})
+
+	server := mock.NewServer()
+
+	server.Use(middlewares.User())
+	status, query := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://example.com/api/v1/posts").
+		AddHeader("Authorization", "Bearer "+token).
+		ExecuteAsJSON(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+	Expect(status).Equals(http.StatusBadRequest)
+	Expect(query.String("errors[0].message")).Equals("Access Token is invalid or has expired")
+}
+
+func TestUser_TwoFactorPending(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(jwt.FiderClaims{
+		UserID:           mock.JonSnow.ID,
+		UserName:         mock.JonSnow.Name,
+		TwoFactorPending: true,
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.User())
+	status, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, token).
+		Execute(func(c *web.Context) error {
+			if c.IsAuthenticated() {
+				return c.NoContent(http.StatusOK)
+			}
+			return c.NoContent(http.StatusNoContent)
+		})
+	Expect(status).Equals(http.StatusNoContent)
+
+	server = mock.NewServer()
+	server.Use(middlewares.User())
+	_, response := server.
+		OnTenant(mock.DemoTenant).
+		WithURL("http://demo.test.fider.io/admin?tab=1").
+		AddCookie(web.CookieAuthName, token).
+		Execute(func(c *web.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+	Expect(response.Header().Get("Location")).Equals("/signin/2fa?redirect=%2Fadmin%3Ftab%3D1")
+}
+
+// mockSessionKey returns the key of a mocked session that belongs to given user
+func mockSessionKey(userID int) string {
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserSessionByKey) error {
+		id, err := strconv.Atoi(strings.TrimPrefix(q.Key, "session-"))
+		if err != nil {
+			return app.ErrNotFound
+		}
+		q.Result = &entity.UserSession{ID: id, UserID: id, LastSeenAt: time.Now()}
+		return nil
+	})
+	return "session-" + strconv.Itoa(userID)
+}
+
+func TestUser_RevokedSession(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(jwt.FiderClaims{
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: "revoked",
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserSessionByKey) error {
+		return app.ErrNotFound
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.User())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, token).
+		Execute(func(c *web.Context) error {
+			if c.IsAuthenticated() {
+				return c.NoContent(http.StatusOK)
+			}
+			return c.NoContent(http.StatusNoContent)
+		})
+
+	Expect(status).Equals(http.StatusNoContent)
+	Expect(response.Header().Get("Set-Cookie")).ContainsSubstring(web.CookieAuthName + "=; Path=/; Expires=")
+}
+
+func TestUser_SessionOfAnotherUser(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(jwt.FiderClaims{
+		UserID:     mock.JonSnow.ID,
+		UserName:   mock.JonSnow.Name,
+		SessionKey: mockSessionKey(mock.AryaStark.ID),
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.User())
+	status, _ := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, token).
+		Execute(func(c *web.Context) error {
+			if c.IsAuthenticated() {
+				return c.NoContent(http.StatusOK)
+			}
+			return c.NoContent(http.StatusNoContent)
+		})
+
+	Expect(status).Equals(http.StatusNoContent)
+}
+
+func TestUser_TokenWithoutSession(t *testing.T) {
+	RegisterT(t)
+
+	// Tokens issued before sessions were tracked can't be revoked, so they are no longer accepted
+	token, _ := jwt.Encode(jwt.FiderClaims{
+		UserID:   mock.JonSnow.ID,
+		UserName: mock.JonSnow.Name,
+	})
+
+	server := mock.NewServer()
+	server.Use(middlewares.User())
+	status, response := server.
+		OnTenant(mock.DemoTenant).
+		AddHeader("Accept", "application/json").
+		AddCookie(web.CookieAuthName, token).
+		Execute(func(c *web.Context) error {
+			if c.User() == nil {
+				return c.NoContent(http.StatusNoContent)
+			}
+			return c.String(http.StatusOK, c.User().Name)
+		})
+
+	Expect(status).Equals(http.StatusNoContent)
+	Expect(response.Header().Get("Set-Cookie")).ContainsSubstring(web.CookieAuthName + "=; Path=/;")
+	Expect(response.Header().Get("Set-Cookie")).ContainsSubstring("Max-Age=0")
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewAPIToken struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+
+	Key    string
+	Result *entity.APIToken
+}
+
+type RevokeAPIToken struct {
+	TokenID int
+}
+
+type MarkAPITokenAsUsed struct {
+	TokenID int
+}
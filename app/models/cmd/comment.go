@@ -5,8 +5,9 @@ import (
 )
 
 type AddNewComment struct {
-	Post    *entity.Post
-	Content string
+	Post       *entity.Post
+	Content    string
+	IsInternal bool
 
 	Result *entity.Comment
 }
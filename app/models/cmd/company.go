@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewCompany struct {
+	Name string
+	MRR  int
+	Plan string
+
+	Result *entity.Company
+}
+
+type UpdateCompany struct {
+	CompanyID int
+	Name      string
+	MRR       int
+	Plan      string
+
+	Result *entity.Company
+}
+
+type DeleteCompany struct {
+	CompanyID int
+}
+
+type SetUserCompany struct {
+	UserID    int
+	CompanyID int
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+)
+
+type AddNewCustomField struct {
+	Name     string
+	Type     enum.CustomFieldType
+	Options  []string
+	IsPublic bool
+
+	Result *entity.CustomField
+}
+
+type UpdateCustomField struct {
+	FieldID  int
+	Name     string
+	Options  []string
+	IsPublic bool
+	Position int
+
+	Result *entity.CustomField
+}
+
+type DeleteCustomField struct {
+	Field *entity.CustomField
+}
+
+type SetPostCustomFieldValues struct {
+	Post   *entity.Post
+	Values []*entity.CustomFieldValue
+}
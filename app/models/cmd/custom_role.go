@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewCustomRole struct {
+	Name        string
+	Permissions []string
+
+	Result *entity.CustomRole
+}
+
+type UpdateCustomRole struct {
+	RoleID      int
+	Name        string
+	Permissions []string
+
+	Result *entity.CustomRole
+}
+
+type DeleteCustomRole struct {
+	RoleID int
+}
+
+type SetUserCustomRole struct {
+	UserID int
+	RoleID int
+}
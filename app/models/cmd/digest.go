@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/getfider/fider/app/models/entity"
+
+type AddDigestItems struct {
+	Users  []*entity.User
+	PostID int
+	Title  string
+	Link   string
+}
+
+type DeleteDigestItems struct {
+	IDs []int
+}
@@ -1,6 +1,11 @@
 package cmd
 
-import "github.com/getfider/fider/app/models/dto"
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+)
 
 type SendMail struct {
 	From         dto.Recipient
@@ -8,3 +13,28 @@ type SendMail struct {
 	TemplateName string
 	Props        dto.Props
 }
+
+type QueueEmail struct {
+	Email *entity.OutboundEmail
+}
+
+type DeliverEmail struct {
+	Email *entity.OutboundEmail
+}
+
+type MarkEmailAsSent struct {
+	ID int
+}
+
+type MarkEmailAsFailed struct {
+	ID      int
+	Error   string
+	RetryAt *time.Time
+}
+
+type PurgeOutboundEmails struct {
+	Before time.Time
+
+	//Output
+	NumOfDeletedEmails int
+}
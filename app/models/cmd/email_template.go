@@ -0,0 +1,23 @@
+package cmd
+
+import "github.com/getfider/fider/app/models/dto"
+
+type SaveEmailTemplate struct {
+	Name    string
+	Locale  string
+	Subject string
+	Body    string
+}
+
+type DeleteEmailTemplate struct {
+	Name   string
+	Locale string
+}
+
+type PreviewEmailTemplate struct {
+	Name    string
+	Subject string
+	Body    string
+
+	Result *dto.EmailTemplatePreview
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 )
 
 type MarkAllNotificationsAsRead struct{}
@@ -33,9 +34,28 @@ type RemoveSubscriber struct {
 	User *entity.User
 }
 
+type MuteComments struct {
+	Post *entity.Post
+	User *entity.User
+}
+
+type UnmuteComments struct {
+	Post *entity.Post
+	User *entity.User
+}
+
 type SupressEmail struct {
 	EmailAddresses []string
+	Reason         enum.EmailSupressionReason
 
 	//Output
 	NumOfSupressedEmailAddresses int
 }
+
+type SupressUserEmail struct {
+	UserID int
+}
+
+type UnsupressUserEmail struct {
+	UserID int
+}
@@ -5,21 +5,22 @@ import (
 )
 
 type SaveCustomOAuthConfig struct {
-	ID                int
-	Logo              *dto.ImageUpload
-	Provider          string
-	Status            int
-	DisplayName       string
-	ClientID          string
-	ClientSecret      string
-	AuthorizeURL      string
-	TokenURL          string
-	Scope             string
-	ProfileURL        string
-	IsTrusted         bool
-	JSONUserIDPath    string
-	JSONUserNamePath  string
-	JSONUserEmailPath string
+	ID                 int
+	Logo               *dto.ImageUpload
+	Provider           string
+	Status             int
+	DisplayName        string
+	ClientID           string
+	ClientSecret       string
+	AuthorizeURL       string
+	TokenURL           string
+	Scope              string
+	ProfileURL         string
+	IsTrusted          bool
+	JSONUserIDPath     string
+	JSONUserNamePath   string
+	JSONUserEmailPath  string
+	JSONUserGroupsPath string
 }
 
 type ParseOAuthRawProfile struct {
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewOAuthClient struct {
+	Name           string
+	RedirectURIs   []string
+	IsConfidential bool
+
+	Secret string
+	Result *entity.OAuthClient
+}
+
+type RevokeOAuthClient struct {
+	ID int
+}
+
+type AddOAuthAuthorizationCode struct {
+	Grant *entity.OAuthGrant
+
+	Code string
+}
+
+type UseOAuthAuthorizationCode struct {
+	Code string
+
+	Result *entity.OAuthGrant
+}
+
+type AddOAuthRefreshToken struct {
+	Grant *entity.OAuthGrant
+
+	Token string
+}
+
+type UseOAuthRefreshToken struct {
+	Token string
+
+	Result *entity.OAuthGrant
+}
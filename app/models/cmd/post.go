@@ -8,6 +8,7 @@ import (
 type AddNewPost struct {
 	Title       string
 	Description string
+	IsPrivate   bool
 
 	Result *entity.Post
 }
@@ -20,8 +21,30 @@ type UpdatePost struct {
 	Result *entity.Post
 }
 
+type SetPostPrivacy struct {
+	Post      *entity.Post
+	IsPrivate bool
+}
+
+type SetPostGroup struct {
+	Post    *entity.Post
+	GroupID int
+}
+
 type SetPostResponse struct {
 	Post   *entity.Post
 	Text   string
 	Status enum.PostStatus
 }
+
+type MergePosts struct {
+	Post         *entity.Post
+	Original     *entity.Post
+	CopyComments bool
+
+	Result *entity.PostMerge
+}
+
+type RevertPostMerge struct {
+	Merge *entity.PostMerge
+}
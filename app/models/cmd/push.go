@@ -0,0 +1,20 @@
+package cmd
+
+import "github.com/getfider/fider/app/models/entity"
+
+type SavePushSubscription struct {
+	Endpoint  string
+	P256dh    string
+	Auth      string
+	UserAgent string
+}
+
+type DeletePushSubscription struct {
+	Endpoint string
+}
+
+type SendPushNotification struct {
+	To    []*entity.User
+	Title string
+	Link  string
+}
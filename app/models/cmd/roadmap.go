@@ -34,6 +34,7 @@ type CreateRoadmapColumn struct {
 	Slug              string
 	Position          int
 	IsVisibleToPublic bool
+	GroupID           int
 	CreatedByID       int
 	Result            *entity.RoadmapColumn
 }
@@ -43,6 +44,7 @@ type UpdateRoadmapColumn struct {
 	ColumnID          int
 	Name              string
 	IsVisibleToPublic bool
+	GroupID           int
 	UpdatedByID       int
 	Result            *entity.RoadmapColumn
 }
@@ -0,0 +1,16 @@
+package cmd
+
+type SaveSAMLConfig struct {
+	Status                 int
+	DisplayName            string
+	IdPMetadata            string
+	IdPEntityID            string
+	IdPSSOURL              string
+	AttributeName          string
+	AttributeEmail         string
+	AttributeRole          string
+	AdministratorRoleValue string
+	CollaboratorRoleValue  string
+	RequireSSO             bool
+	AllowIdPInitiated      bool
+}
@@ -8,6 +8,7 @@ type AddNewTag struct {
 	Name     string
 	Color    string
 	IsPublic bool
+	GroupID  int
 
 	Result *entity.Tag
 }
@@ -17,6 +18,7 @@ type UpdateTag struct {
 	Name     string
 	Color    string
 	IsPublic bool
+	GroupID  int
 
 	Result *entity.Tag
 }
@@ -34,3 +36,11 @@ type UnassignTag struct {
 	Tag  *entity.Tag
 	Post *entity.Post
 }
+
+type FollowTag struct {
+	Tag *entity.Tag
+}
+
+type UnfollowTag struct {
+	Tag *entity.Tag
+}
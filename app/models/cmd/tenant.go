@@ -21,10 +21,20 @@ type UpdateTenantPrivacySettings struct {
 	IsFeedEnabled bool
 }
 
+type UpdateTenantVotingSettings struct {
+	VotingMode      enum.VotingMode
+	VoteBudget      int
+	MaxVotesPerPost int
+}
+
 type UpdateTenantEmailAuthAllowedSettings struct {
 	IsEmailAuthAllowed bool
 }
 
+type UpdateTenantTwoFactorSettings struct {
+	IsTwoFactorRequired bool
+}
+
 type UpdateTenantSettings struct {
 	Logo           *dto.ImageUpload
 	Title          string
@@ -0,0 +1,43 @@
+package cmd
+
+type SetupUserTwoFactor struct {
+	UserID int
+	Secret string
+}
+
+type EnableUserTwoFactor struct {
+	UserID int
+	Step   int64
+
+	Result []string
+}
+
+type DisableUserTwoFactor struct {
+	UserID int
+}
+
+type RegenerateRecoveryCodes struct {
+	UserID int
+
+	Result []string
+}
+
+type MarkTwoFactorStepUsed struct {
+	UserID int
+	Step   int64
+
+	Result bool
+}
+
+type UseRecoveryCode struct {
+	UserID int
+	Code   string
+
+	Result bool
+}
+
+type RecordTwoFactorFailure struct {
+	UserID int
+
+	Result bool
+}
@@ -21,11 +21,20 @@ type RegenerateAPIKey struct {
 type DeleteCurrentUser struct {
 }
 
+type DeleteUser struct {
+	UserID int
+}
+
 type ChangeUserRole struct {
 	UserID int
 	Role   enum.Role
 }
 
+type ChangeUserName struct {
+	UserID int
+	Name   string
+}
+
 type ChangeUserEmail struct {
 	UserID int
 	Email  string
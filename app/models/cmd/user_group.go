@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type AddNewUserGroup struct {
+	Name         string
+	EmailDomains []string
+	OAuthClaim   string
+
+	Result *entity.UserGroup
+}
+
+type UpdateUserGroup struct {
+	GroupID      int
+	Name         string
+	EmailDomains []string
+	OAuthClaim   string
+
+	Result *entity.UserGroup
+}
+
+type DeleteUserGroup struct {
+	GroupID int
+}
+
+type AddUserToGroup struct {
+	GroupID int
+	UserID  int
+}
+
+type RemoveUserFromGroup struct {
+	GroupID int
+	UserID  int
+}
+
+type SyncUserOAuthGroups struct {
+	UserID int
+	Claims []string
+}
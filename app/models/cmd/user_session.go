@@ -0,0 +1,32 @@
+package cmd
+
+import "github.com/getfider/fider/app/models/entity"
+
+type CreateUserSession struct {
+	UserID    int
+	Key       string
+	UserAgent string
+	IPAddress string
+
+	Result *entity.UserSession
+}
+
+type TouchUserSession struct {
+	SessionID int
+	UserAgent string
+	IPAddress string
+}
+
+type RevokeUserSession struct {
+	UserID    int
+	SessionID int
+}
+
+type RevokeUserSessions struct {
+	UserID          int
+	ExceptSessionID int
+}
+
+type RevokeAllUserSessions struct {
+	ExceptSessionID int
+}
@@ -5,8 +5,11 @@ import (
 )
 
 type AddVote struct {
-	Post *entity.Post
-	User *entity.User
+	Post      *entity.Post
+	User      *entity.User
+	Weight    int
+	ProxiedBy *entity.User
+	Note      string
 }
 
 type RemoveVote struct {
@@ -13,8 +13,9 @@ type TestWebhook struct {
 }
 
 type TriggerWebhooks struct {
-	Type  enum.WebhookType
-	Props webhook.Props
+	Type     enum.WebhookType
+	Props    webhook.Props
+	Internal bool
 }
 
 type PreviewWebhook struct {
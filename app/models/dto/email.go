@@ -7,6 +7,9 @@ type Recipient struct {
 	Name    string
 	Address string
 	Props   Props
+	ReplyTo string
+	// UnsubscribeURL is a one-click unsubscribe link, sent on the List-Unsubscribe header
+	UnsubscribeURL string
 }
 
 // NewRecipient creates a new Recipient
@@ -31,3 +34,10 @@ func (r Recipient) String() string {
 
 	return address.String()
 }
+
+// EmailTemplatePreview is an email template rendered with sample props
+type EmailTemplatePreview struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/getfider/fider/app/models/enum"
+
+// SAMLUserProfile represents the user asserted by a SAML identity provider
+type SAMLUserProfile struct {
+	ID    string
+	Name  string
+	Email string
+	// Role is zero when the assertion doesn't carry the mapped role attribute
+	Role     enum.Role
+	Redirect string
+}
+
+// SAMLIdPMetadata represents the relevant parts of an identity provider metadata document
+type SAMLIdPMetadata struct {
+	EntityID string
+	SSOURL   string
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+// Scopes that can be granted to an API token
+const (
+	APIScopePostsRead    = "posts:read"
+	APIScopePostsWrite   = "posts:write"
+	APIScopeRoadmapWrite = "roadmap:write"
+	APIScopeUsersRead    = "users:read"
+	APIScopeSCIM         = "scim"
+	APIScopeAdmin        = "admin"
+)
+
+// AllAPIScopes is the list of every known API token scope
+var AllAPIScopes = []string{
+	APIScopePostsRead,
+	APIScopePostsWrite,
+	APIScopeRoadmapWrite,
+	APIScopeUsersRead,
+	APIScopeSCIM,
+	APIScopeAdmin,
+}
+
+// APITokenKeyPrefix is prepended to every API token key so they are easy to recognize
+const APITokenKeyPrefix = "fdr_"
+
+// APIToken is a named key that grants a subset of its owner's permissions to the API
+type APIToken struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	User       *User      `json:"user,omitempty"`
+}
+
+// HasScope returns true if the token grants given scope, admin grants everything
+func (t *APIToken) HasScope(scope string) bool {
+	return HasAPIScope(t.Scopes, scope)
+}
+
+// HasAPIScope returns true if given list of granted scopes includes scope, admin grants everything
+func HasAPIScope(scopes []string, scope string) bool {
+	return slices.Contains(scopes, APIScopeAdmin) || slices.Contains(scopes, scope)
+}
+
+// IsExpired returns true if the token has an expiry date in the past
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now())
+}
+
+// CanBeGrantedScope returns true if given user is allowed to create a token with given scope
+func CanBeGrantedScope(user *User, scope string) bool {
+	switch scope {
+	case APIScopePostsRead:
+		return true
+	case APIScopePostsWrite, APIScopeRoadmapWrite, APIScopeUsersRead:
+		return user.IsCollaborator()
+	case APIScopeSCIM, APIScopeAdmin:
+		return user.IsAdministrator()
+	}
+	return false
+}
+
+// GenerateAPITokenKey returns a new random API token key
+func GenerateAPITokenKey() string {
+	return APITokenKeyPrefix + rand.String(40)
+}
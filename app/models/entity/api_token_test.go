@@ -0,0 +1,32 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestAPIToken_HasScope(t *testing.T) {
+	RegisterT(t)
+
+	token := &entity.APIToken{Scopes: []string{entity.APIScopePostsRead}}
+	Expect(token.HasScope(entity.APIScopePostsRead)).IsTrue()
+	Expect(token.HasScope(entity.APIScopePostsWrite)).IsFalse()
+
+	token = &entity.APIToken{Scopes: []string{entity.APIScopeAdmin}}
+	Expect(token.HasScope(entity.APIScopePostsWrite)).IsTrue()
+	Expect(token.HasScope(entity.APIScopeUsersRead)).IsTrue()
+}
+
+func TestAPIToken_IsExpired(t *testing.T) {
+	RegisterT(t)
+
+	past := time.Now().Add(-1 * time.Minute)
+	future := time.Now().Add(1 * time.Hour)
+
+	Expect((&entity.APIToken{}).IsExpired()).IsFalse()
+	Expect((&entity.APIToken{ExpiresAt: &past}).IsExpired()).IsTrue()
+	Expect((&entity.APIToken{ExpiresAt: &future}).IsExpired()).IsFalse()
+}
@@ -0,0 +1,106 @@
+package entity
+
+import "time"
+
+// Actions recorded on the audit log
+const (
+	AuditTenantSettingsUpdated  = "tenant.settings_updated"
+	AuditTenantAdvancedUpdated  = "tenant.advanced_settings_updated"
+	AuditTenantPrivacyUpdated   = "tenant.privacy_updated"
+	AuditTenantVotingUpdated    = "tenant.voting_updated"
+	AuditTenantEmailAuthUpdated = "tenant.email_auth_updated"
+	AuditTenantTwoFactorUpdated = "tenant.two_factor_updated"
+	AuditUserRoleChanged        = "user.role_changed"
+	AuditUserCustomRoleChanged  = "user.custom_role_changed"
+	AuditUserBlocked            = "user.blocked"
+	AuditUserUnblocked          = "user.unblocked"
+	AuditUserDeleted            = "user.deleted"
+	AuditUserSessionsRevoked    = "user.sessions_revoked"
+	AuditUserEmailSupressed     = "user.email_suppressed"
+	AuditUserEmailUnsupressed   = "user.email_unsuppressed"
+	AuditAllSessionsRevoked     = "tenant.sessions_revoked"
+	AuditOAuthConfigSaved       = "oauth.config_saved"
+	AuditSAMLConfigSaved        = "saml.config_saved"
+	AuditWebhookSaved           = "webhook.saved"
+	AuditWebhookDeleted         = "webhook.deleted"
+	AuditEmailTemplateSaved     = "email_template.saved"
+	AuditEmailTemplateDeleted   = "email_template.deleted"
+	AuditTagCreated             = "tag.created"
+	AuditTagUpdated             = "tag.updated"
+	AuditTagDeleted             = "tag.deleted"
+	AuditCustomRoleCreated      = "role.created"
+	AuditCustomRoleUpdated      = "role.updated"
+	AuditCustomRoleDeleted      = "role.deleted"
+	AuditPostDeleted            = "post.deleted"
+	AuditAPITokenCreated        = "api_token.created"
+	AuditAPITokenRevoked        = "api_token.revoked"
+	AuditOAuthClientCreated     = "oauth_client.created"
+	AuditOAuthClientRevoked     = "oauth_client.revoked"
+	AuditUserGroupCreated       = "group.created"
+	AuditUserGroupUpdated       = "group.updated"
+	AuditUserGroupDeleted       = "group.deleted"
+	AuditUserGroupMemberAdded   = "group.member_added"
+	AuditUserGroupMemberRemoved = "group.member_removed"
+	AuditCustomFieldCreated     = "custom_field.created"
+	AuditCustomFieldUpdated     = "custom_field.updated"
+	AuditCustomFieldDeleted     = "custom_field.deleted"
+)
+
+// AllAuditActions is the list of every action recorded on the audit log
+var AllAuditActions = []string{
+	AuditTenantSettingsUpdated,
+	AuditTenantAdvancedUpdated,
+	AuditTenantPrivacyUpdated,
+	AuditTenantVotingUpdated,
+	AuditTenantEmailAuthUpdated,
+	AuditTenantTwoFactorUpdated,
+	AuditUserRoleChanged,
+	AuditUserCustomRoleChanged,
+	AuditUserBlocked,
+	AuditUserUnblocked,
+	AuditUserDeleted,
+	AuditUserSessionsRevoked,
+	AuditUserEmailSupressed,
+	AuditUserEmailUnsupressed,
+	AuditAllSessionsRevoked,
+	AuditOAuthConfigSaved,
+	AuditSAMLConfigSaved,
+	AuditWebhookSaved,
+	AuditWebhookDeleted,
+	AuditEmailTemplateSaved,
+	AuditEmailTemplateDeleted,
+	AuditTagCreated,
+	AuditTagUpdated,
+	AuditTagDeleted,
+	AuditCustomRoleCreated,
+	AuditCustomRoleUpdated,
+	AuditCustomRoleDeleted,
+	AuditPostDeleted,
+	AuditAPITokenCreated,
+	AuditAPITokenRevoked,
+	AuditOAuthClientCreated,
+	AuditOAuthClientRevoked,
+	AuditUserGroupCreated,
+	AuditUserGroupUpdated,
+	AuditUserGroupDeleted,
+	AuditUserGroupMemberAdded,
+	AuditUserGroupMemberRemoved,
+	AuditCustomFieldCreated,
+	AuditCustomFieldUpdated,
+	AuditCustomFieldDeleted,
+}
+
+// AuditLogEntry is an administrative change made on a tenant
+type AuditLogEntry struct {
+	ID         int            `json:"id"`
+	Action     string         `json:"action"`
+	ActorID    int            `json:"actorId,omitempty"`
+	ActorName  string         `json:"actorName"`
+	TargetType string         `json:"targetType"`
+	TargetID   int            `json:"targetId,omitempty"`
+	TargetName string         `json:"targetName"`
+	Before     map[string]any `json:"before,omitempty"`
+	After      map[string]any `json:"after,omitempty"`
+	IPAddress  string         `json:"ipAddress"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
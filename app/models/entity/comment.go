@@ -20,4 +20,10 @@ type Comment struct {
 	EditedAt       *time.Time       `json:"editedAt,omitempty"`
 	EditedBy       *User            `json:"editedBy,omitempty"`
 	ReactionCounts []ReactionCounts `json:"reactionCounts,omitempty"`
+	IsInternal     bool             `json:"isInternal"`
+}
+
+// IsVisibleTo returns true if given user can see this comment, internal comments are only visible to staff
+func (c *Comment) IsVisibleTo(user *User) bool {
+	return !c.IsInternal || (user != nil && user.IsCollaborator())
 }
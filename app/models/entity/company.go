@@ -0,0 +1,10 @@
+package entity
+
+// Company is a customer organization that users of a tenant can belong to
+type Company struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	MRR        int    `json:"mrr"`
+	Plan       string `json:"plan"`
+	UsersCount int    `json:"usersCount"`
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// CustomFieldDateLayout is the format in which date custom fields are stored
+const CustomFieldDateLayout = "2006-01-02"
+
+// CustomField is an admin-defined attribute that can be set on posts
+type CustomField struct {
+	ID       int                  `json:"id"`
+	Key      string               `json:"key"`
+	Name     string               `json:"name"`
+	Type     enum.CustomFieldType `json:"type"`
+	Options  []string             `json:"options"`
+	IsPublic bool                 `json:"isPublic"`
+	Position int                  `json:"position"`
+}
+
+// CustomFieldValue is the value of a custom field on a given post
+type CustomFieldValue struct {
+	Field *CustomField
+	Value any
+}
+
+// ParseValue converts a JSON value into the representation stored for this field.
+// A nil result with ok = true means the value should be cleared.
+func (f *CustomField) ParseValue(raw json.RawMessage) (value any, ok bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, true
+	}
+
+	switch f.Type {
+	case enum.CustomFieldNumber:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, false
+		}
+		return number, true
+	case enum.CustomFieldUser:
+		var userID int
+		if err := json.Unmarshal(raw, &userID); err != nil || userID <= 0 {
+			return nil, false
+		}
+		return userID, true
+	case enum.CustomFieldMultiSelect:
+		var options []string
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return nil, false
+		}
+		if len(options) == 0 {
+			return nil, true
+		}
+		for _, option := range options {
+			if !slices.Contains(f.Options, option) {
+				return nil, false
+			}
+		}
+		return options, true
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil, false
+	}
+	return f.ParseString(text)
+}
+
+// ParseString converts a plain text value, such as a query string parameter, into the representation stored for this field
+func (f *CustomField) ParseString(text string) (value any, ok bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, true
+	}
+
+	switch f.Type {
+	case enum.CustomFieldText:
+		return text, true
+	case enum.CustomFieldNumber:
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number, true
+		}
+	case enum.CustomFieldDate:
+		if _, err := time.Parse(CustomFieldDateLayout, text); err == nil {
+			return text, true
+		}
+	case enum.CustomFieldSelect:
+		if slices.Contains(f.Options, text) {
+			return text, true
+		}
+	case enum.CustomFieldMultiSelect:
+		if slices.Contains(f.Options, text) {
+			return []string{text}, true
+		}
+	case enum.CustomFieldUser:
+		if userID, err := strconv.Atoi(text); err == nil && userID > 0 {
+			return userID, true
+		}
+	}
+	return nil, false
+}
+
+// FormatValue returns a plain text representation of a stored value of this field
+func (f *CustomField) FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case []string:
+		return strings.Join(v, ", ")
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = f.FormatValue(item)
+		}
+		return strings.Join(items, ", ")
+	}
+	return ""
+}
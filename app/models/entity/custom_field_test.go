@@ -0,0 +1,71 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestCustomField_ParseValue(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		field    *entity.CustomField
+		raw      string
+		expected any
+		ok       bool
+	}{
+		{&entity.CustomField{Type: enum.CustomFieldText}, `"Hello"`, "Hello", true},
+		{&entity.CustomField{Type: enum.CustomFieldText}, `null`, nil, true},
+		{&entity.CustomField{Type: enum.CustomFieldText}, `""`, nil, true},
+		{&entity.CustomField{Type: enum.CustomFieldText}, `12`, nil, false},
+		{&entity.CustomField{Type: enum.CustomFieldNumber}, `12.5`, 12.5, true},
+		{&entity.CustomField{Type: enum.CustomFieldNumber}, `"12"`, nil, false},
+		{&entity.CustomField{Type: enum.CustomFieldDate}, `"2026-10-19"`, "2026-10-19", true},
+		{&entity.CustomField{Type: enum.CustomFieldDate}, `"19/10/2026"`, nil, false},
+		{&entity.CustomField{Type: enum.CustomFieldSelect, Options: []string{"A", "B"}}, `"B"`, "B", true},
+		{&entity.CustomField{Type: enum.CustomFieldSelect, Options: []string{"A", "B"}}, `"C"`, nil, false},
+		{&entity.CustomField{Type: enum.CustomFieldMultiSelect, Options: []string{"A", "B"}}, `["A","B"]`, []string{"A", "B"}, true},
+		{&entity.CustomField{Type: enum.CustomFieldMultiSelect, Options: []string{"A", "B"}}, `[]`, nil, true},
+		{&entity.CustomField{Type: enum.CustomFieldMultiSelect, Options: []string{"A", "B"}}, `["A","C"]`, nil, false},
+		{&entity.CustomField{Type: enum.CustomFieldUser}, `4`, 4, true},
+		{&entity.CustomField{Type: enum.CustomFieldUser}, `-1`, nil, false},
+	}
+
+	for _, testCase := range testCases {
+		value, ok := testCase.field.ParseValue(json.RawMessage(testCase.raw))
+		Expect(ok).Equals(testCase.ok)
+		Expect(value).Equals(testCase.expected)
+	}
+}
+
+func TestCustomField_ParseString(t *testing.T) {
+	RegisterT(t)
+
+	number := &entity.CustomField{Type: enum.CustomFieldNumber}
+	value, ok := number.ParseString("1500")
+	Expect(ok).IsTrue()
+	Expect(value).Equals(float64(1500))
+
+	multiSelect := &entity.CustomField{Type: enum.CustomFieldMultiSelect, Options: []string{"iOS", "Web"}}
+	value, ok = multiSelect.ParseString("Web")
+	Expect(ok).IsTrue()
+	Expect(value).Equals([]string{"Web"})
+
+	_, ok = multiSelect.ParseString("Android")
+	Expect(ok).IsFalse()
+}
+
+func TestCustomField_FormatValue(t *testing.T) {
+	RegisterT(t)
+
+	field := &entity.CustomField{}
+	Expect(field.FormatValue(nil)).Equals("")
+	Expect(field.FormatValue("Enterprise")).Equals("Enterprise")
+	Expect(field.FormatValue(float64(125000))).Equals("125000")
+	Expect(field.FormatValue(float64(1.5))).Equals("1.5")
+	Expect(field.FormatValue([]any{"iOS", "Web"})).Equals("iOS, Web")
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "slices"
+
+// Permissions that can be granted to a custom role
+const (
+	PermissionRespond          = "posts:respond"
+	PermissionManageTags       = "tags:manage"
+	PermissionManageRoadmap    = "roadmap:manage"
+	PermissionModerateComments = "comments:moderate"
+	PermissionViewVoterEmails  = "voters:emails"
+	PermissionManageWebhooks   = "webhooks:manage"
+	PermissionExportData       = "data:export"
+)
+
+// AllPermissions is the list of every known permission
+var AllPermissions = []string{
+	PermissionRespond,
+	PermissionManageTags,
+	PermissionManageRoadmap,
+	PermissionModerateComments,
+	PermissionViewVoterEmails,
+	PermissionManageWebhooks,
+	PermissionExportData,
+}
+
+// CollaboratorPermissions are the permissions of collaborators without a custom role
+var CollaboratorPermissions = []string{
+	PermissionRespond,
+	PermissionManageRoadmap,
+	PermissionModerateComments,
+	PermissionViewVoterEmails,
+}
+
+// CustomRole is an administrator defined set of permissions given to collaborators
+type CustomRole struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	UsersCount  int      `json:"usersCount"`
+}
+
+// HasPermission returns true if the role grants given permission
+func (r *CustomRole) HasPermission(permission string) bool {
+	return slices.Contains(r.Permissions, permission)
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// DigestItem is an event waiting to be sent as part of an email digest
+type DigestItem struct {
+	ID        int
+	Title     string
+	Link      string
+	CreatedAt time.Time
+}
+
+// EmailDigest groups all pending digest items of a user
+type EmailDigest struct {
+	Tenant *Tenant
+	User   *User
+	Items  []*DigestItem
+}
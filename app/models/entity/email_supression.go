@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// SupressedEmail is a user that Fider no longer sends emails to
+type SupressedEmail struct {
+	UserID      int                        `json:"userId"`
+	UserName    string                     `json:"userName"`
+	Email       string                     `json:"email"`
+	Reason      enum.EmailSupressionReason `json:"reason"`
+	SupressedAt time.Time                  `json:"supressedAt"`
+}
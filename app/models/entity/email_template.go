@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// EditableEmailTemplates is the list of emails that can be customized by each tenant
+var EditableEmailTemplates = []string{
+	"new_post",
+	"new_comment",
+	"change_status",
+	"delete_post",
+	"merge_post",
+	"post_received",
+	"post_rejected",
+	"digest",
+	"invite_email",
+	"signin_email",
+	"signup_email",
+	"change_emailaddress_email",
+}
+
+// IsEditableEmailTemplate returns true if given email can be customized by tenants
+func IsEditableEmailTemplate(name string) bool {
+	for _, item := range EditableEmailTemplates {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailTemplate is the subject and body of an email, either the default one or a tenant override for a locale
+type EmailTemplate struct {
+	ID        int       `json:"id,omitempty"`
+	Name      string    `json:"name"`
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
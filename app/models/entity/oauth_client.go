@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+// OpenID Connect scopes that can be requested by OAuth clients on top of the API scopes
+const (
+	OAuthScopeOpenID  = "openid"
+	OAuthScopeProfile = "profile"
+	OAuthScopeEmail   = "email"
+)
+
+// Lifetime of the credentials issued to OAuth clients
+const (
+	OAuthAuthorizationCodeLifetime = 10 * time.Minute
+	OAuthAccessTokenLifetime       = 1 * time.Hour
+	OAuthRefreshTokenLifetime      = 30 * 24 * time.Hour
+)
+
+// AllOAuthScopes is the list of every scope an OAuth client can request
+var AllOAuthScopes = append([]string{
+	OAuthScopeOpenID,
+	OAuthScopeProfile,
+	OAuthScopeEmail,
+}, AllAPIScopes...)
+
+// OAuthClient is an application registered to sign users in and call the API on their behalf
+// Only a hash of the secret is stored, the secret itself is shown once when the client is registered
+type OAuthClient struct {
+	ID           int
+	ClientID     string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	CreatedAt    time.Time
+}
+
+// MarshalJSON returns the JSON encoding of OAuthClient
+func (c OAuthClient) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":             c.ID,
+		"clientId":       c.ClientID,
+		"name":           c.Name,
+		"redirectUris":   c.RedirectURIs,
+		"isConfidential": c.IsConfidential(),
+		"createdAt":      c.CreatedAt,
+	})
+}
+
+// IsConfidential returns true if the client has a secret to authenticate itself
+// Public clients, such as mobile and single-page apps, rely on PKCE only
+func (c *OAuthClient) IsConfidential() bool {
+	return c.SecretHash != ""
+}
+
+// HasRedirectURI returns true if given URI is registered for this client, an exact match is required
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// VerifySecret returns true if given secret matches the client secret
+func (c *OAuthClient) VerifySecret(secret string) bool {
+	return c.IsConfidential() && subtle.ConstantTimeCompare([]byte(c.SecretHash), []byte(crypto.SHA512(secret))) == 1
+}
+
+// OAuthGrant is what a user has authorized an OAuth client to do on their behalf
+type OAuthGrant struct {
+	ClientID      int
+	UserID        int
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	Nonce         string
+}
+
+// VerifyCodeVerifier returns true if given PKCE code verifier matches the S256 code challenge of this grant
+func (g *OAuthGrant) VerifyCodeVerifier(verifier string) bool {
+	hash := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(hash[:])
+	return g.CodeChallenge != "" && subtle.ConstantTimeCompare([]byte(g.CodeChallenge), []byte(challenge)) == 1
+}
+
+// GenerateOAuthClientID returns a new random public identifier for an OAuth client
+func GenerateOAuthClientID() string {
+	return rand.String(32)
+}
+
+// GenerateOAuthClientSecret returns a new random OAuth client secret
+func GenerateOAuthClientSecret() string {
+	return rand.String(64)
+}
@@ -0,0 +1,56 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/crypto"
+)
+
+func TestOAuthClient_HasRedirectURI(t *testing.T) {
+	RegisterT(t)
+
+	client := &entity.OAuthClient{RedirectURIs: []string{"https://example.com/callback"}}
+	Expect(client.HasRedirectURI("https://example.com/callback")).IsTrue()
+	Expect(client.HasRedirectURI("https://example.com/callback/")).IsFalse()
+	Expect(client.HasRedirectURI("https://example.com/callback?next=/")).IsFalse()
+}
+
+func TestOAuthClient_VerifySecret(t *testing.T) {
+	RegisterT(t)
+
+	public := &entity.OAuthClient{}
+	Expect(public.IsConfidential()).IsFalse()
+	Expect(public.VerifySecret("")).IsFalse()
+
+	confidential := &entity.OAuthClient{SecretHash: crypto.SHA512("s3cr3t-s3cr3t")}
+	Expect(confidential.IsConfidential()).IsTrue()
+	Expect(confidential.VerifySecret("s3cr3t-s3cr3t")).IsTrue()
+	Expect(confidential.VerifySecret("s3cr3t")).IsFalse()
+}
+
+func TestOAuthClient_MarshalJSON_HidesSecret(t *testing.T) {
+	RegisterT(t)
+
+	client := entity.OAuthClient{ClientID: "abc", SecretHash: crypto.SHA512("0123456789abcdef")}
+	bytes, err := json.Marshal(client)
+	Expect(err).IsNil()
+	Expect(strings.Contains(string(bytes), client.SecretHash)).IsFalse()
+	Expect(strings.Contains(string(bytes), "clientSecret")).IsFalse()
+	Expect(string(bytes)).ContainsSubstring(`"isConfidential":true`)
+}
+
+func TestOAuthGrant_VerifyCodeVerifier(t *testing.T) {
+	RegisterT(t)
+
+	// Example from RFC 7636, Appendix B
+	grant := &entity.OAuthGrant{CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"}
+	Expect(grant.VerifyCodeVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")).IsTrue()
+	Expect(grant.VerifyCodeVerifier("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")).IsFalse()
+	Expect(grant.VerifyCodeVerifier("")).IsFalse()
+
+	Expect((&entity.OAuthGrant{}).VerifyCodeVerifier("")).IsFalse()
+}
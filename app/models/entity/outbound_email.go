@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// OutboundEmail is an email rendered for a single recipient, kept on the queue until the email provider accepts it
+type OutboundEmail struct {
+	ID             int                      `json:"id"`
+	TenantID       int                      `json:"-"`
+	MessageID      string                   `json:"messageId"`
+	TemplateName   string                   `json:"templateName"`
+	From           string                   `json:"from"`
+	ReplyTo        string                   `json:"-"`
+	ToName         string                   `json:"toName"`
+	ToAddress      string                   `json:"toAddress"`
+	UnsubscribeURL string                   `json:"-"`
+	Subject        string                   `json:"subject"`
+	Body           string                   `json:"-"`
+	Status         enum.OutboundEmailStatus `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	LastError      string                   `json:"lastError,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	SentAt         *time.Time               `json:"sentAt,omitempty"`
+}
@@ -18,10 +18,16 @@ type Post struct {
 	User          *User           `json:"user"`
 	HasVoted      bool            `json:"hasVoted"`
 	VotesCount    int             `json:"votesCount"`
+	VotesWeight   int             `json:"votesWeight"`
+	MyVoteWeight  int             `json:"myVoteWeight"`
+	VotesRevenue  int             `json:"votesRevenue,omitempty"`
 	CommentsCount int             `json:"commentsCount"`
 	Status        enum.PostStatus `json:"status"`
 	Response      *PostResponse   `json:"response,omitempty"`
 	Tags          []string        `json:"tags"`
+	CustomFields  map[string]any  `json:"customFields"`
+	IsPrivate     bool            `json:"isPrivate"`
+	GroupID       int             `json:"groupId,omitempty"`
 }
 
 // CanBeVoted returns true if this post can have its vote changed
@@ -29,6 +35,26 @@ func (i *Post) CanBeVoted() bool {
 	return i.Status != enum.PostCompleted && i.Status != enum.PostDeclined && i.Status != enum.PostDuplicate
 }
 
+// IsVisibleTo returns true if given user can see this post
+// Private posts are only visible to their author and staff, posts restricted to a group also to the group members
+func (i *Post) IsVisibleTo(user *User) bool {
+	if user != nil && (user.IsCollaborator() || (i.User != nil && i.User.ID == user.ID)) {
+		return true
+	}
+	if i.IsPrivate {
+		return false
+	}
+	return i.GroupID == 0 || (user != nil && user.IsMemberOf(i.GroupID))
+}
+
+// IsVisibleToViewersOf returns true if everyone who can see given post can also see this one
+func (i *Post) IsVisibleToViewersOf(other *Post) bool {
+	if i.IsPrivate {
+		return other.IsPrivate && i.User != nil && other.User != nil && i.User.ID == other.User.ID
+	}
+	return i.GroupID == 0 || i.GroupID == other.GroupID
+}
+
 func (i *Post) Url(baseURL string) string {
 	return fmt.Sprintf("%s/posts/%d/%s", baseURL, i.Number, i.Slug)
 }
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// PostMerge records a duplicate post being merged into its original, so that it can be reverted later
+type PostMerge struct {
+	ID                 int             `json:"id"`
+	PostID             int             `json:"postId"`
+	OriginalID         int             `json:"originalId"`
+	MergedAt           time.Time       `json:"mergedAt"`
+	MergedBy           *User           `json:"mergedBy"`
+	RevertedAt         *time.Time      `json:"revertedAt,omitempty"`
+	PreviousStatus     enum.PostStatus `json:"previousStatus"`
+	VoterIDs           []int           `json:"voterIds"`
+	AddedVoterIDs      []int           `json:"addedVoterIds"`
+	AddedSubscriberIDs []int           `json:"addedSubscriberIds"`
+	AddedTagIDs        []int           `json:"addedTagIds"`
+	CopiedCommentIDs   []int           `json:"copiedCommentIds"`
+}
+
+// IsReverted returns true if this merge has already been reverted
+func (m *PostMerge) IsReverted() bool {
+	return m.RevertedAt != nil
+}
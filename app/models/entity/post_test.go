@@ -0,0 +1,60 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestPost_IsVisibleTo(t *testing.T) {
+	RegisterT(t)
+
+	author := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	visitor := &entity.User{ID: 2, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 3, Role: enum.RoleCollaborator}
+
+	post := &entity.Post{User: author}
+	Expect(post.IsVisibleTo(nil)).IsTrue()
+	Expect(post.IsVisibleTo(visitor)).IsTrue()
+
+	post.IsPrivate = true
+	Expect(post.IsVisibleTo(nil)).IsFalse()
+	Expect(post.IsVisibleTo(visitor)).IsFalse()
+	Expect(post.IsVisibleTo(author)).IsTrue()
+	Expect(post.IsVisibleTo(collaborator)).IsTrue()
+}
+
+func TestPost_IsVisibleTo_Group(t *testing.T) {
+	RegisterT(t)
+
+	member := &entity.User{ID: 1, Role: enum.RoleVisitor, GroupIDs: []int{5}}
+	visitor := &entity.User{ID: 2, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 3, Role: enum.RoleCollaborator}
+
+	post := &entity.Post{User: visitor, GroupID: 5}
+	Expect(post.IsVisibleTo(nil)).IsFalse()
+	Expect(post.IsVisibleTo(member)).IsTrue()
+	Expect(post.IsVisibleTo(visitor)).IsTrue()
+	Expect(post.IsVisibleTo(collaborator)).IsTrue()
+
+	post.User = collaborator
+	Expect(post.IsVisibleTo(visitor)).IsFalse()
+}
+
+func TestComment_IsVisibleTo(t *testing.T) {
+	RegisterT(t)
+
+	visitor := &entity.User{ID: 1, Role: enum.RoleVisitor}
+	administrator := &entity.User{ID: 2, Role: enum.RoleAdministrator}
+
+	comment := &entity.Comment{User: visitor}
+	Expect(comment.IsVisibleTo(nil)).IsTrue()
+	Expect(comment.IsVisibleTo(visitor)).IsTrue()
+
+	comment.IsInternal = true
+	Expect(comment.IsVisibleTo(nil)).IsFalse()
+	Expect(comment.IsVisibleTo(visitor)).IsFalse()
+	Expect(comment.IsVisibleTo(administrator)).IsTrue()
+}
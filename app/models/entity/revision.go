@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// Revision is a snapshot of a post, staff response or comment taken every time it is written
+type Revision struct {
+	ID        int               `json:"id"`
+	PostID    int               `json:"postId"`
+	CommentID int               `json:"commentId,omitempty"`
+	Kind      enum.RevisionKind `json:"kind"`
+	Title     string            `json:"title,omitempty"`
+	Content   string            `json:"content"`
+	CreatedAt time.Time         `json:"createdAt"`
+	CreatedBy *User             `json:"createdBy"`
+}
+
+// IsSameTarget returns true if both revisions are versions of the same content
+func (r *Revision) IsSameTarget(other *Revision) bool {
+	return r.PostID == other.PostID && r.Kind == other.Kind && r.CommentID == other.CommentID
+}
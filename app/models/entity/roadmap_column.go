@@ -10,6 +10,7 @@ type RoadmapColumn struct {
 	Slug              string    `json:"slug"`
 	Position          int       `json:"position"`
 	IsVisibleToPublic bool      `json:"isVisibleToPublic"`
+	GroupID           int       `json:"groupId,omitempty"`
 	CreatedAt         time.Time `json:"createdAt"`
 	Posts             []*Post   `json:"posts"`
 }
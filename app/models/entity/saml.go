@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/getfider/fider/app/models/enum"
+)
+
+// SAMLConfig is the SAML 2.0 single sign-on configuration of a tenant
+type SAMLConfig struct {
+	ID                     int    `json:"id"`
+	Status                 int    `json:"status"`
+	DisplayName            string `json:"displayName"`
+	IdPMetadata            string `json:"idpMetadata"`
+	IdPEntityID            string `json:"idpEntityID"`
+	IdPSSOURL              string `json:"idpSSOURL"`
+	AttributeName          string `json:"attributeName"`
+	AttributeEmail         string `json:"attributeEmail"`
+	AttributeRole          string `json:"attributeRole"`
+	AdministratorRoleValue string `json:"administratorRoleValue"`
+	CollaboratorRoleValue  string `json:"collaboratorRoleValue"`
+	RequireSSO             bool   `json:"requireSSO"`
+	AllowIdPInitiated      bool   `json:"allowIdPInitiated"`
+}
+
+// IsEnabled returns true if users can sign in through SAML
+func (c *SAMLConfig) IsEnabled() bool {
+	return c != nil && c.Status == enum.OAuthConfigEnabled
+}
+
+// MapRole returns the role matching given values of the role attribute
+// Values are compared case insensitive and the highest matching role wins
+func (c *SAMLConfig) MapRole(values []string) enum.Role {
+	role := enum.RoleVisitor
+	for _, value := range values {
+		if c.AdministratorRoleValue != "" && strings.EqualFold(value, c.AdministratorRoleValue) {
+			return enum.RoleAdministrator
+		}
+		if c.CollaboratorRoleValue != "" && strings.EqualFold(value, c.CollaboratorRoleValue) {
+			role = enum.RoleCollaborator
+		}
+	}
+	return role
+}
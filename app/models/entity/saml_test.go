@@ -0,0 +1,34 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestSAMLConfig_IsEnabled(t *testing.T) {
+	RegisterT(t)
+
+	var config *entity.SAMLConfig
+	Expect(config.IsEnabled()).IsFalse()
+	Expect((&entity.SAMLConfig{Status: enum.OAuthConfigDisabled}).IsEnabled()).IsFalse()
+	Expect((&entity.SAMLConfig{Status: enum.OAuthConfigEnabled}).IsEnabled()).IsTrue()
+}
+
+func TestSAMLConfig_MapRole(t *testing.T) {
+	RegisterT(t)
+
+	config := &entity.SAMLConfig{
+		AdministratorRoleValue: "fider-admins",
+		CollaboratorRoleValue:  "fider-staff",
+	}
+	Expect(config.MapRole([]string{})).Equals(enum.RoleVisitor)
+	Expect(config.MapRole([]string{"everyone"})).Equals(enum.RoleVisitor)
+	Expect(config.MapRole([]string{"everyone", "Fider-Staff"})).Equals(enum.RoleCollaborator)
+	Expect(config.MapRole([]string{"fider-staff", "fider-admins"})).Equals(enum.RoleAdministrator)
+
+	config.CollaboratorRoleValue = ""
+	Expect(config.MapRole([]string{""})).Equals(enum.RoleVisitor)
+}
@@ -7,4 +7,13 @@ type Tag struct {
 	Slug     string `json:"slug"`
 	Color    string `json:"color"`
 	IsPublic bool   `json:"isPublic"`
+	GroupID  int    `json:"groupId,omitempty"`
+}
+
+// IsVisibleTo returns true if given user can see this tag, tags restricted to a group are only visible to its members and staff
+func (t *Tag) IsVisibleTo(user *User) bool {
+	if user != nil && user.IsCollaborator() {
+		return true
+	}
+	return t.IsPublic && (t.GroupID == 0 || (user != nil && user.IsMemberOf(t.GroupID)))
 }
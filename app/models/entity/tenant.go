@@ -4,27 +4,43 @@ import "github.com/getfider/fider/app/models/enum"
 
 // Tenant represents a tenant
 type Tenant struct {
-	ID                 int               `json:"id"`
-	Name               string            `json:"name"`
-	Subdomain          string            `json:"subdomain"`
-	Invitation         string            `json:"invitation"`
-	WelcomeMessage     string            `json:"welcomeMessage"`
-	CNAME              string            `json:"cname"`
-	Status             enum.TenantStatus `json:"status"`
-	Locale             string            `json:"locale"`
-	IsPrivate          bool              `json:"isPrivate"`
-	LogoBlobKey        string            `json:"logoBlobKey"`
-	CustomCSS          string            `json:"-"`
-	AllowedSchemes     string            `json:"allowedSchemes"`
-	IsEmailAuthAllowed bool              `json:"isEmailAuthAllowed"`
-	IsFeedEnabled      bool              `json:"isFeedEnabled"`
-	PreventIndexing    bool              `json:"preventIndexing"`
+	ID                  int               `json:"id"`
+	Name                string            `json:"name"`
+	Subdomain           string            `json:"subdomain"`
+	Invitation          string            `json:"invitation"`
+	WelcomeMessage      string            `json:"welcomeMessage"`
+	CNAME               string            `json:"cname"`
+	Status              enum.TenantStatus `json:"status"`
+	Locale              string            `json:"locale"`
+	IsPrivate           bool              `json:"isPrivate"`
+	LogoBlobKey         string            `json:"logoBlobKey"`
+	CustomCSS           string            `json:"-"`
+	AllowedSchemes      string            `json:"allowedSchemes"`
+	IsEmailAuthAllowed  bool              `json:"isEmailAuthAllowed"`
+	IsTwoFactorRequired bool              `json:"isTwoFactorRequired"`
+	IsFeedEnabled       bool              `json:"isFeedEnabled"`
+	PreventIndexing     bool              `json:"preventIndexing"`
+	VotingMode          enum.VotingMode   `json:"votingMode"`
+	VoteBudget          int               `json:"voteBudget"`
+	MaxVotesPerPost     int               `json:"maxVotesPerPost"`
 }
 
 func (t *Tenant) IsDisabled() bool {
 	return t.Status == enum.TenantDisabled
 }
 
+// MaxVoteWeight returns the highest weight a single vote can have on this tenant
+func (t *Tenant) MaxVoteWeight() int {
+	switch t.VotingMode {
+	case enum.VotingModeBudget:
+		return t.MaxVotesPerPost
+	case enum.VotingModeImportance:
+		return enum.ImportanceCritical
+	default:
+		return 1
+	}
+}
+
 // TenantContact is a reference to an administrator account
 type TenantContact struct {
 	Name      string `json:"name"`
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// RecoveryCodesCount is the number of recovery codes generated when two-factor authentication is enabled
+const RecoveryCodesCount = 10
+
+// TwoFactorMaxFailedAttempts is the number of invalid codes after which a user is locked out
+const TwoFactorMaxFailedAttempts = 5
+
+// TwoFactorLockoutDuration is how long a user is locked out for after too many invalid codes
+// It outlasts the tokens of pending sign ins, so none of those in use when the lockout starts can be used afterwards
+const TwoFactorLockoutDuration = 15 * time.Minute
+
+// UserTwoFactor holds the TOTP secret of a user
+// The secret is only enabled after the user confirmed it with a valid code
+type UserTwoFactor struct {
+	UserID       int
+	Secret       string
+	LastUsedStep int64
+	EnabledAt    *time.Time
+
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+// IsEnabled returns true if the secret has been confirmed by the user
+func (t *UserTwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// IsLockedOut returns true if too many invalid codes were given recently
+func (t *UserTwoFactor) IsLockedOut(now time.Time) bool {
+	return t != nil && t.LockedUntil != nil && t.LockedUntil.After(now)
+}
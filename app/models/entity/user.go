@@ -2,6 +2,7 @@ package entity
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/getfider/fider/app/models/enum"
 )
@@ -18,6 +19,9 @@ type User struct {
 	AvatarType    enum.AvatarType `json:"-"`
 	AvatarURL     string          `json:"avatarURL,omitempty"`
 	Status        enum.UserStatus `json:"status"`
+	CustomRole    *CustomRole     `json:"customRole,omitempty"`
+	GroupIDs      []int           `json:"-"`
+	HasTwoFactor  bool            `json:"-"`
 }
 
 // HasProvider returns true if current user has registered with given provider
@@ -30,16 +34,51 @@ func (u *User) HasProvider(provider string) bool {
 	return false
 }
 
+// MustSetupTwoFactor returns true if the tenant requires staff to use two-factor authentication and the user hasn't enabled it yet
+func (u *User) MustSetupTwoFactor() bool {
+	return u.Tenant != nil && u.Tenant.IsTwoFactorRequired && u.IsCollaborator() && !u.HasTwoFactor
+}
+
 // IsCollaborator returns true if user has special permissions
 func (u *User) IsCollaborator() bool {
 	return u.Role == enum.RoleCollaborator || u.Role == enum.RoleAdministrator
 }
 
+// IsMemberOf returns true if user is a member of given group
+func (u *User) IsMemberOf(groupID int) bool {
+	return slices.Contains(u.GroupIDs, groupID)
+}
+
 // IsAdministrator returns true if user is administrator
 func (u *User) IsAdministrator() bool {
 	return u.Role == enum.RoleAdministrator
 }
 
+// HasPermission returns true if user is allowed to perform actions guarded by given permission
+// Collaborators with a custom role only have the permissions of that role
+func (u *User) HasPermission(permission string) bool {
+	switch {
+	case u.IsAdministrator():
+		return true
+	case u.Role != enum.RoleCollaborator:
+		return false
+	case u.CustomRole != nil:
+		return u.CustomRole.HasPermission(permission)
+	}
+	return slices.Contains(CollaboratorPermissions, permission)
+}
+
+// Permissions returns the list of permissions this user has
+func (u *User) Permissions() []string {
+	permissions := make([]string, 0)
+	for _, permission := range AllPermissions {
+		if u.HasPermission(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
 // UserProvider represents the relationship between an User and an Authentication provide
 type UserProvider struct {
 	Name string
@@ -55,9 +94,11 @@ func (umc UserWithEmail) MarshalJSON() ([]byte, error) {
 	type Alias User // Prevent recursion
 	return json.Marshal(&struct {
 		*Alias
-		Email string `json:"email"`
+		Email        string `json:"email"`
+		HasTwoFactor bool   `json:"hasTwoFactor,omitempty"`
 	}{
-		Alias: (*Alias)(umc.User),
-		Email: umc.User.Email,
+		Alias:        (*Alias)(umc.User),
+		Email:        umc.User.Email,
+		HasTwoFactor: umc.User.HasTwoFactor,
 	})
 }
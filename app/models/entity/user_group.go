@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+)
+
+// UserGroup is a segment of users, such as beta testers or enterprise customers
+// Users are members when added manually, when their email matches one of the domains or when their OAuth profile has the claim
+type UserGroup struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	EmailDomains []string `json:"emailDomains"`
+	OAuthClaim   string   `json:"oauthClaim"`
+	MembersCount int      `json:"membersCount"`
+}
+
+// MatchesEmail returns true if given email belongs to one of the domains of this group
+func (g *UserGroup) MatchesEmail(email string) bool {
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return false
+	}
+	return slices.Contains(g.EmailDomains, strings.ToLower(email[idx+1:]))
+}
+
+// MatchesOAuthClaims returns true if the claim of this group is one of given claims
+func (g *UserGroup) MatchesOAuthClaims(claims []string) bool {
+	if g.OAuthClaim == "" {
+		return false
+	}
+	return slices.ContainsFunc(claims, func(claim string) bool {
+		return strings.EqualFold(claim, g.OAuthClaim)
+	})
+}
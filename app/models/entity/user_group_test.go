@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+)
+
+func TestUserGroup_MatchesEmail(t *testing.T) {
+	RegisterT(t)
+
+	group := &entity.UserGroup{EmailDomains: []string{"acme.com"}}
+	Expect(group.MatchesEmail("jon@acme.com")).IsTrue()
+	Expect(group.MatchesEmail("jon@ACME.com")).IsTrue()
+	Expect(group.MatchesEmail("jon@mail.acme.com")).IsFalse()
+	Expect(group.MatchesEmail("jon@other.com")).IsFalse()
+	Expect(group.MatchesEmail("")).IsFalse()
+}
+
+func TestUserGroup_MatchesOAuthClaims(t *testing.T) {
+	RegisterT(t)
+
+	group := &entity.UserGroup{OAuthClaim: "beta-testers"}
+	Expect(group.MatchesOAuthClaims([]string{"staff", "Beta-Testers"})).IsTrue()
+	Expect(group.MatchesOAuthClaims([]string{"staff"})).IsFalse()
+	Expect(group.MatchesOAuthClaims(nil)).IsFalse()
+
+	group.OAuthClaim = ""
+	Expect(group.MatchesOAuthClaims([]string{""})).IsFalse()
+}
+
+func TestTag_IsVisibleTo(t *testing.T) {
+	RegisterT(t)
+
+	member := &entity.User{ID: 1, Role: enum.RoleVisitor, GroupIDs: []int{5}}
+	visitor := &entity.User{ID: 2, Role: enum.RoleVisitor}
+	collaborator := &entity.User{ID: 3, Role: enum.RoleCollaborator}
+
+	tag := &entity.Tag{IsPublic: true}
+	Expect(tag.IsVisibleTo(nil)).IsTrue()
+	Expect(tag.IsVisibleTo(visitor)).IsTrue()
+
+	tag.GroupID = 5
+	Expect(tag.IsVisibleTo(nil)).IsFalse()
+	Expect(tag.IsVisibleTo(visitor)).IsFalse()
+	Expect(tag.IsVisibleTo(member)).IsTrue()
+	Expect(tag.IsVisibleTo(collaborator)).IsTrue()
+
+	tag.IsPublic = false
+	Expect(tag.IsVisibleTo(member)).IsFalse()
+	Expect(tag.IsVisibleTo(collaborator)).IsTrue()
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// UserSession is a browser where a user is signed in
+type UserSession struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
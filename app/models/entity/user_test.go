@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
 	. "github.com/getfider/fider/app/pkg/assert"
 )
 
@@ -53,3 +54,48 @@ func TestUser_MarshalJSON(t *testing.T) {
 	Expect(string(jsonData)).Equals(expectedJSON)
 
 }
+
+func TestUser_HasPermission(t *testing.T) {
+	RegisterT(t)
+
+	admin := &entity.User{Role: enum.RoleAdministrator}
+	Expect(admin.HasPermission(entity.PermissionExportData)).IsTrue()
+
+	visitor := &entity.User{Role: enum.RoleVisitor}
+	Expect(visitor.HasPermission(entity.PermissionRespond)).IsFalse()
+	Expect(visitor.Permissions()).HasLen(0)
+
+	collaborator := &entity.User{Role: enum.RoleCollaborator}
+	Expect(collaborator.HasPermission(entity.PermissionRespond)).IsTrue()
+	Expect(collaborator.HasPermission(entity.PermissionManageWebhooks)).IsFalse()
+	Expect(collaborator.Permissions()).Equals(entity.CollaboratorPermissions)
+
+	agent := &entity.User{
+		Role:       enum.RoleCollaborator,
+		CustomRole: &entity.CustomRole{Name: "Support Agent", Permissions: []string{entity.PermissionRespond}},
+	}
+	Expect(agent.HasPermission(entity.PermissionRespond)).IsTrue()
+	Expect(agent.HasPermission(entity.PermissionModerateComments)).IsFalse()
+	Expect(agent.Permissions()).Equals([]string{entity.PermissionRespond})
+
+	// a role that couldn't be loaded has no permissions
+	unknown := &entity.User{Role: enum.RoleCollaborator, CustomRole: &entity.CustomRole{ID: 4}}
+	Expect(unknown.HasPermission(entity.PermissionRespond)).IsFalse()
+}
+
+func TestUser_MustSetupTwoFactor(t *testing.T) {
+	RegisterT(t)
+
+	tenant := &entity.Tenant{IsTwoFactorRequired: true}
+	admin := &entity.User{Role: enum.RoleAdministrator, Tenant: tenant}
+	visitor := &entity.User{Role: enum.RoleVisitor, Tenant: tenant}
+	Expect(admin.MustSetupTwoFactor()).IsTrue()
+	Expect(visitor.MustSetupTwoFactor()).IsFalse()
+
+	admin.HasTwoFactor = true
+	Expect(admin.MustSetupTwoFactor()).IsFalse()
+
+	tenant.IsTwoFactorRequired = false
+	admin.HasTwoFactor = false
+	Expect(admin.MustSetupTwoFactor()).IsFalse()
+}
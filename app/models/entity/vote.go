@@ -6,14 +6,26 @@ import (
 
 //VoteUser represents a user that voted on a post
 type VoteUser struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email,omitempty"`
-	AvatarURL string `json:"avatarURL,omitempty"`
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Email     string   `json:"email,omitempty"`
+	AvatarURL string   `json:"avatarURL,omitempty"`
+	Company   *Company `json:"company,omitempty"`
 }
 
 //Vote represents a vote given by a user on a post
 type Vote struct {
 	User      *VoteUser `json:"user"`
+	Weight    int       `json:"weight"`
+	ProxiedBy *VoteUser `json:"proxiedBy,omitempty"`
+	Note      string    `json:"note,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 }
+
+//VoteBudget is how many votes a user can still distribute between posts
+type VoteBudget struct {
+	Total      int `json:"total"`
+	Spent      int `json:"spent"`
+	Remaining  int `json:"remaining"`
+	MaxPerPost int `json:"maxPerPost"`
+}
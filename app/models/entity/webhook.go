@@ -18,6 +18,7 @@ type Webhook struct {
 	Content     string             `json:"content" db:"content"`
 	HttpMethod  string             `json:"http_method" db:"http_method"`
 	HttpHeaders HttpHeaders        `json:"http_headers" db:"http_headers"`
+	IsInternal  bool               `json:"is_internal" db:"is_internal"`
 }
 
 type HttpHeaders map[string]string
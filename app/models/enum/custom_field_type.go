@@ -0,0 +1,62 @@
+package enum
+
+// CustomFieldType is the type of value a custom field holds
+type CustomFieldType int
+
+const (
+	// CustomFieldText holds free text
+	CustomFieldText CustomFieldType = 1
+	// CustomFieldNumber holds a decimal number
+	CustomFieldNumber CustomFieldType = 2
+	// CustomFieldDate holds a calendar date in YYYY-MM-DD format
+	CustomFieldDate CustomFieldType = 3
+	// CustomFieldSelect holds one of the field options
+	CustomFieldSelect CustomFieldType = 4
+	// CustomFieldMultiSelect holds any number of the field options
+	CustomFieldMultiSelect CustomFieldType = 5
+	// CustomFieldUser holds the ID of a user of the tenant
+	CustomFieldUser CustomFieldType = 6
+)
+
+var customFieldTypeIDs = map[CustomFieldType]string{
+	CustomFieldText:        "text",
+	CustomFieldNumber:      "number",
+	CustomFieldDate:        "date",
+	CustomFieldSelect:      "select",
+	CustomFieldMultiSelect: "multi-select",
+	CustomFieldUser:        "user",
+}
+
+var customFieldTypeNames = map[string]CustomFieldType{
+	"text":         CustomFieldText,
+	"number":       CustomFieldNumber,
+	"date":         CustomFieldDate,
+	"select":       CustomFieldSelect,
+	"multi-select": CustomFieldMultiSelect,
+	"user":         CustomFieldUser,
+}
+
+// MarshalText returns the Text version of the custom field type
+func (t CustomFieldType) MarshalText() ([]byte, error) {
+	return []byte(customFieldTypeIDs[t]), nil
+}
+
+// UnmarshalText parse string into a custom field type
+func (t *CustomFieldType) UnmarshalText(text []byte) error {
+	*t = customFieldTypeNames[string(text)]
+	return nil
+}
+
+// Name returns the name of a custom field type
+func (t CustomFieldType) Name() string {
+	name, ok := customFieldTypeIDs[t]
+	if ok {
+		return name
+	}
+	return "unknown"
+}
+
+// HasOptions returns true if values of this type are picked from a list of options
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldSelect || t == CustomFieldMultiSelect
+}
@@ -12,4 +12,6 @@ const (
 	EmailVerificationKindChangeEmail EmailVerificationKind = 3
 	//EmailVerificationKindUserInvitation is the sign in invitation sent to an user
 	EmailVerificationKindUserInvitation EmailVerificationKind = 4
+	//EmailVerificationKindEmailPost is the confirmation of a post sent by email from an unauthenticated address
+	EmailVerificationKindEmailPost EmailVerificationKind = 5
 )
@@ -0,0 +1,41 @@
+package enum
+
+// EmailSupressionReason is the reason why Fider stopped sending emails to an address
+type EmailSupressionReason int
+
+var (
+	//EmailSupressionHardBounce is used for addresses that permanently failed to receive emails
+	EmailSupressionHardBounce EmailSupressionReason = 1
+	//EmailSupressionComplaint is used for addresses whose owner marked an email as spam
+	EmailSupressionComplaint EmailSupressionReason = 2
+	//EmailSupressionManual is used for addresses supressed by an administrator
+	EmailSupressionManual EmailSupressionReason = 3
+)
+
+var emailSupressionReasonIDs = map[EmailSupressionReason]string{
+	EmailSupressionHardBounce: "hard_bounce",
+	EmailSupressionComplaint:  "complaint",
+	EmailSupressionManual:     "manual",
+}
+
+var emailSupressionReasonName = map[string]EmailSupressionReason{
+	"hard_bounce": EmailSupressionHardBounce,
+	"complaint":   EmailSupressionComplaint,
+	"manual":      EmailSupressionManual,
+}
+
+// String returns the string version of the supression reason
+func (reason EmailSupressionReason) String() string {
+	return emailSupressionReasonIDs[reason]
+}
+
+// MarshalText returns the Text version of the supression reason
+func (reason EmailSupressionReason) MarshalText() ([]byte, error) {
+	return []byte(emailSupressionReasonIDs[reason]), nil
+}
+
+// UnmarshalText parse string into a supression reason
+func (reason *EmailSupressionReason) UnmarshalText(text []byte) error {
+	*reason = emailSupressionReasonName[string(text)]
+	return nil
+}
@@ -12,8 +12,34 @@ var (
 	NotificationChannelWeb NotificationChannel = 1
 	//NotificationChannelEmail is an email notification
 	NotificationChannelEmail NotificationChannel = 2
+	//NotificationChannelDigest is an email notification delivered later as part of a digest
+	//It's enabled by the same setting as NotificationChannelEmail, based on the user's digest frequency
+	NotificationChannelDigest NotificationChannel = 4
+	//NotificationChannelPush is a browser push notification sent to the devices the user subscribed
+	//It's opt-in, so default setting values never enable it
+	NotificationChannelPush NotificationChannel = 8
 )
 
+// EmailDigestFrequencySettingsKey is the user setting key that stores how often email notifications are sent
+const EmailDigestFrequencySettingsKey = "email_digest_frequency"
+
+var (
+	//EmailDigestFrequencyInstant sends one email per event
+	EmailDigestFrequencyInstant = "instant"
+	//EmailDigestFrequencyDaily groups events into a daily digest email
+	EmailDigestFrequencyDaily = "daily"
+	//EmailDigestFrequencyWeekly groups events into a weekly digest email
+	EmailDigestFrequencyWeekly = "weekly"
+)
+
+// NotificationsSnoozedUntilSettingsKey is the user setting key that stores when snoozed notifications resume, in RFC 3339 format
+const NotificationsSnoozedUntilSettingsKey = "notifications_snoozed_until"
+
+// IsValidEmailDigestFrequency returns true if given value is a known digest frequency
+func IsValidEmailDigestFrequency(v string) bool {
+	return v == EmailDigestFrequencyInstant || v == EmailDigestFrequencyDaily || v == EmailDigestFrequencyWeekly
+}
+
 // NotificationEvent represents all possible notification events
 type NotificationEvent struct {
 	UserSettingsKeyName           string
@@ -24,7 +50,11 @@ type NotificationEvent struct {
 }
 
 func notificationEventValidation(v string) bool {
-	return v == "0" || v == "1" || v == "2" || v == "3"
+	channels, err := strconv.Atoi(v)
+	if err != nil || channels < 0 {
+		return false
+	}
+	return channels&^int(NotificationChannelWeb|NotificationChannelEmail|NotificationChannelPush) == 0
 }
 
 var (
@@ -0,0 +1,45 @@
+package enum
+
+// OutboundEmailStatus is the delivery status of an email sent by Fider
+type OutboundEmailStatus int
+
+var (
+	//OutboundEmailQueued is used for emails waiting to be sent, including the ones that will be retried
+	OutboundEmailQueued OutboundEmailStatus = 1
+	//OutboundEmailSent is used for emails accepted by the email provider
+	OutboundEmailSent OutboundEmailStatus = 2
+	//OutboundEmailFailed is used for emails that could not be sent after all attempts
+	OutboundEmailFailed OutboundEmailStatus = 3
+	//OutboundEmailBounced is used for sent emails that the recipient's mail server rejected
+	OutboundEmailBounced OutboundEmailStatus = 4
+)
+
+var outboundEmailStatusIDs = map[OutboundEmailStatus]string{
+	OutboundEmailQueued:  "queued",
+	OutboundEmailSent:    "sent",
+	OutboundEmailFailed:  "failed",
+	OutboundEmailBounced: "bounced",
+}
+
+var outboundEmailStatusName = map[string]OutboundEmailStatus{
+	"queued":  OutboundEmailQueued,
+	"sent":    OutboundEmailSent,
+	"failed":  OutboundEmailFailed,
+	"bounced": OutboundEmailBounced,
+}
+
+// String returns the string version of the delivery status
+func (status OutboundEmailStatus) String() string {
+	return outboundEmailStatusIDs[status]
+}
+
+// MarshalText returns the Text version of the delivery status
+func (status OutboundEmailStatus) MarshalText() ([]byte, error) {
+	return []byte(outboundEmailStatusIDs[status]), nil
+}
+
+// UnmarshalText parse string into a delivery status
+func (status *OutboundEmailStatus) UnmarshalText(text []byte) error {
+	*status = outboundEmailStatusName[string(text)]
+	return nil
+}
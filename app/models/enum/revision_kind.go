@@ -0,0 +1,36 @@
+package enum
+
+// RevisionKind is the kind of content a revision holds
+type RevisionKind int
+
+const (
+	// RevisionPost holds the title and description of a post
+	RevisionPost RevisionKind = 1
+	// RevisionResponse holds the staff response of a post
+	RevisionResponse RevisionKind = 2
+	// RevisionComment holds the content of a comment
+	RevisionComment RevisionKind = 3
+)
+
+var revisionKindIDs = map[RevisionKind]string{
+	RevisionPost:     "post",
+	RevisionResponse: "response",
+	RevisionComment:  "comment",
+}
+
+var revisionKindNames = map[string]RevisionKind{
+	"post":     RevisionPost,
+	"response": RevisionResponse,
+	"comment":  RevisionComment,
+}
+
+// MarshalText returns the Text version of the revision kind
+func (k RevisionKind) MarshalText() ([]byte, error) {
+	return []byte(revisionKindIDs[k]), nil
+}
+
+// UnmarshalText parse string into a revision kind
+func (k *RevisionKind) UnmarshalText(text []byte) error {
+	*k = revisionKindNames[string(text)]
+	return nil
+}
@@ -0,0 +1,43 @@
+package enum
+
+// VotingMode is the way users vote on posts of a tenant
+type VotingMode int
+
+const (
+	// VotingModeSimple gives each user a single vote per post
+	VotingModeSimple VotingMode = 1
+	// VotingModeBudget gives each user a fixed number of votes to distribute between posts
+	VotingModeBudget VotingMode = 2
+	// VotingModeImportance lets users say how important a post is to them
+	VotingModeImportance VotingMode = 3
+)
+
+var votingModeIDs = map[VotingMode]string{
+	VotingModeSimple:     "simple",
+	VotingModeBudget:     "budget",
+	VotingModeImportance: "importance",
+}
+
+var votingModeNames = map[string]VotingMode{
+	"simple":     VotingModeSimple,
+	"budget":     VotingModeBudget,
+	"importance": VotingModeImportance,
+}
+
+// MarshalText returns the Text version of the voting mode
+func (m VotingMode) MarshalText() ([]byte, error) {
+	return []byte(votingModeIDs[m]), nil
+}
+
+// UnmarshalText parse string into a voting mode
+func (m *VotingMode) UnmarshalText(text []byte) error {
+	*m = votingModeNames[string(text)]
+	return nil
+}
+
+// Importance levels used as vote weight on VotingModeImportance
+const (
+	ImportanceNiceToHave = 1
+	ImportanceImportant  = 2
+	ImportanceCritical   = 3
+)
@@ -0,0 +1,25 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetAPITokenByKey struct {
+	Key string
+
+	Result *entity.APIToken
+}
+
+type GetAPITokenByID struct {
+	TokenID int
+
+	Result *entity.APIToken
+}
+
+type GetCurrentUserAPITokens struct {
+	Result []*entity.APIToken
+}
+
+type GetAllAPITokens struct {
+	Result []*entity.APIToken
+}
@@ -0,0 +1,18 @@
+package query
+
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type SearchAuditLogs struct {
+	Action     string
+	ActorID    int
+	TargetType string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+
+	Result []*entity.AuditLogEntry
+}
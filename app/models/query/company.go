@@ -0,0 +1,21 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetCompanyByID struct {
+	CompanyID int
+
+	Result *entity.Company
+}
+
+type GetCompanyByName struct {
+	Name string
+
+	Result *entity.Company
+}
+
+type GetAllCompanies struct {
+	Result []*entity.Company
+}
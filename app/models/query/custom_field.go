@@ -0,0 +1,15 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetCustomFieldByKey struct {
+	Key string
+
+	Result *entity.CustomField
+}
+
+type GetAllCustomFields struct {
+	Result []*entity.CustomField
+}
@@ -0,0 +1,21 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetCustomRoleByID struct {
+	RoleID int
+
+	Result *entity.CustomRole
+}
+
+type GetCustomRoleByName struct {
+	Name string
+
+	Result *entity.CustomRole
+}
+
+type GetAllCustomRoles struct {
+	Result []*entity.CustomRole
+}
@@ -1,10 +1,31 @@
 package query
 
-import "time"
+import (
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+)
 
 type FetchRecentSupressions struct {
 	StartTime time.Time
 
 	//Output
-	EmailAddresses []string
+	EmailAddresses      []string
+	ComplainedAddresses []string
+}
+
+type GetSupressedEmails struct {
+	Result []*entity.SupressedEmail
+}
+
+type GetQueuedEmails struct {
+	Limit int
+
+	Result []*entity.OutboundEmail
+}
+
+type SearchOutboundEmails struct {
+	Address string
+
+	Result []*entity.OutboundEmail
 }
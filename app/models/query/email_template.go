@@ -0,0 +1,18 @@
+package query
+
+import "github.com/getfider/fider/app/models/entity"
+
+type GetEmailTemplate struct {
+	Name   string
+	Locale string
+
+	Result *entity.EmailTemplate
+}
+
+type ListEmailTemplates struct {
+	Result []*entity.EmailTemplate
+}
+
+type ListDefaultEmailTemplates struct {
+	Result []*entity.EmailTemplate
+}
@@ -0,0 +1,21 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetOAuthClientByID struct {
+	ID int
+
+	Result *entity.OAuthClient
+}
+
+type GetOAuthClientByClientID struct {
+	ClientID string
+
+	Result *entity.OAuthClient
+}
+
+type GetAllOAuthClients struct {
+	Result []*entity.OAuthClient
+}
@@ -1,6 +1,9 @@
 package query
 
 import (
+	"net/url"
+	"strings"
+
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 )
@@ -43,6 +46,9 @@ type SearchPosts struct {
 	NoTagsOnly  bool
 	MyPostsOnly bool
 
+	// CustomFields filters posts by custom field key and value
+	CustomFields map[string]string
+
 	Result []*entity.Post
 }
 
@@ -56,6 +62,12 @@ type GetAllPosts struct {
 	Result []*entity.Post
 }
 
+type GetActivePostMerge struct {
+	PostID int
+
+	Result *entity.PostMerge
+}
+
 func (q *SearchPosts) SetStatusesFromStrings(statuses []string) {
 	for _, v := range statuses {
 		var postStatus enum.PostStatus
@@ -64,3 +76,15 @@ func (q *SearchPosts) SetStatusesFromStrings(statuses []string) {
 		}
 	}
 }
+
+// SetCustomFieldsFromQuery reads custom field filters from "cf.<key>" parameters
+func (q *SearchPosts) SetCustomFieldsFromQuery(values url.Values) {
+	for name := range values {
+		if key, ok := strings.CutPrefix(name, "cf."); ok && key != "" {
+			if q.CustomFields == nil {
+				q.CustomFields = make(map[string]string)
+			}
+			q.CustomFields[key] = values.Get(name)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetRevisionsByPost struct {
+	PostID int
+
+	Result []*entity.Revision
+}
+
+type GetRevisionByID struct {
+	PostID     int
+	RevisionID int
+
+	Result *entity.Revision
+}
+
+type GetPreviousRevision struct {
+	Revision *entity.Revision
+
+	Result *entity.Revision
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetSAMLConfig struct {
+	Result *entity.SAMLConfig
+}
+
+type GetSAMLAuthnRequestURL struct {
+	Redirect string
+
+	Result string
+}
+
+type ParseSAMLResponse struct {
+	SAMLResponse string
+	RelayState   string
+
+	Result *dto.SAMLUserProfile
+}
+
+type ParseSAMLIdPMetadata struct {
+	Metadata string
+
+	Result *dto.SAMLIdPMetadata
+}
+
+type GetSAMLServiceProviderMetadata struct {
+	Result string
+}
@@ -19,3 +19,7 @@ type GetAssignedTags struct {
 type GetAllTags struct {
 	Result []*entity.Tag
 }
+
+type GetFollowedTags struct {
+	Result []*entity.Tag
+}
@@ -0,0 +1,11 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetUserTwoFactor struct {
+	UserID int
+
+	Result *entity.UserTwoFactor
+}
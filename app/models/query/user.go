@@ -15,6 +15,12 @@ type UserSubscribedTo struct {
 	Result bool
 }
 
+type UserMutedCommentsOn struct {
+	PostID int
+
+	Result bool
+}
+
 type GetUserByAPIKey struct {
 	APIKey string
 
@@ -0,0 +1,27 @@
+package query
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+)
+
+type GetUserGroupByID struct {
+	GroupID int
+
+	Result *entity.UserGroup
+}
+
+type GetUserGroupByName struct {
+	Name string
+
+	Result *entity.UserGroup
+}
+
+type GetAllUserGroups struct {
+	Result []*entity.UserGroup
+}
+
+type GetUserGroupMembers struct {
+	GroupID int
+
+	Result []*entity.User
+}
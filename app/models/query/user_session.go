@@ -0,0 +1,15 @@
+package query
+
+import "github.com/getfider/fider/app/models/entity"
+
+type GetUserSessionByKey struct {
+	Key string
+
+	Result *entity.UserSession
+}
+
+type GetUserSessions struct {
+	UserID int
+
+	Result []*entity.UserSession
+}
@@ -9,3 +9,10 @@ type ListPostVotes struct {
 
 	Result []*entity.Vote
 }
+
+type GetVoteBudget struct {
+	UserID        int
+	ExcludePostID int
+
+	Result *entity.VoteBudget
+}
@@ -36,6 +36,7 @@ type CreateEditWebhook struct {
 	Content     string
 	HttpMethod  string
 	HttpHeaders entity.HttpHeaders
+	IsInternal  bool
 
 	Result int
 }
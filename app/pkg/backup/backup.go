@@ -18,17 +18,27 @@ func Create(ctx context.Context) (*bytes.Buffer, error) {
 	zipWriter := zip.NewWriter(buffer)
 
 	for _, tableName := range []string{
+		"api_tokens",
 		"attachments",
 		"comments",
+		"companies",
+		"custom_fields",
+		"custom_roles",
 		"email_verifications",
 		"notifications",
+		"oauth_clients",
 		"oauth_providers",
 		"posts",
+		"post_custom_field_values",
 		"post_subscribers",
 		"post_tags",
 		"post_votes",
+		"revisions",
+		"saml_configs",
 		"tags",
 		"tenants",
+		"user_group_members",
+		"user_groups",
 		"user_providers",
 		"users",
 		"user_settings",
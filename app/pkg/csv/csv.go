@@ -10,8 +10,8 @@ import (
 	"github.com/getfider/fider/app/models/entity"
 )
 
-//FromPosts return a byte array of CSV file containing all posts
-func FromPosts(posts []*entity.Post) ([]byte, error) {
+//FromPosts return a byte array of CSV file containing all posts, with one extra column per custom field
+func FromPosts(posts []*entity.Post, fields []*entity.CustomField) ([]byte, error) {
 	buffer := &bytes.Buffer{}
 	writer := gocsv.NewWriter(buffer)
 
@@ -31,6 +31,9 @@ func FromPosts(posts []*entity.Post) ([]byte, error) {
 		"original_title",
 		"tags",
 	}
+	for _, field := range fields {
+		header = append(header, field.Key)
+	}
 	if err := writer.Write(header); err != nil {
 		return nil, err
 	}
@@ -70,6 +73,9 @@ func FromPosts(posts []*entity.Post) ([]byte, error) {
 			originalTitle,
 			strings.Join(post.Tags, ", "),
 		}
+		for _, field := range fields {
+			record = append(record, field.FormatValue(post.CustomFields[field.Key]))
+		}
 		if err := writer.Write(record); err != nil {
 			return nil, err
 		}
@@ -17,7 +17,7 @@ func TestExportPostsToCSV_Empty(t *testing.T) {
 	posts := []*entity.Post{}
 	expected, err := os.ReadFile("./testdata/empty.csv")
 	Expect(err).IsNil()
-	actual, err := csv.FromPosts(posts)
+	actual, err := csv.FromPosts(posts, nil)
 	Expect(err).IsNil()
 	Expect(actual).Equals(expected)
 }
@@ -31,7 +31,7 @@ func TestExportPostsToCSV_OnePost(t *testing.T) {
 
 	expected, err := os.ReadFile("./testdata/one-post.csv")
 	Expect(err).IsNil()
-	actual, err := csv.FromPosts(posts)
+	actual, err := csv.FromPosts(posts, nil)
 	Expect(err).IsNil()
 	Expect(actual).Equals(expected)
 }
@@ -47,7 +47,41 @@ func TestExportPostsToCSV_MorePosts(t *testing.T) {
 
 	expected, err := os.ReadFile("./testdata/more-posts.csv")
 	Expect(err).IsNil()
-	actual, err := csv.FromPosts(posts)
+	actual, err := csv.FromPosts(posts, nil)
+	Expect(err).IsNil()
+	Expect(actual).Equals(expected)
+}
+
+func TestExportPostsToCSV_WithCustomFields(t *testing.T) {
+	RegisterT(t)
+
+	fields := []*entity.CustomField{
+		{ID: 1, Key: "segment", Name: "Segment", Type: enum.CustomFieldSelect, Options: []string{"SMB", "Enterprise"}},
+		{ID: 2, Key: "arr-impact", Name: "ARR Impact", Type: enum.CustomFieldNumber},
+		{ID: 3, Key: "platform", Name: "Platform", Type: enum.CustomFieldMultiSelect, Options: []string{"iOS", "Android", "Web"}},
+	}
+
+	posts := []*entity.Post{
+		{
+			Number:    20,
+			Title:     "Go is fun",
+			CreatedAt: time.Date(2018, 2, 21, 15, 51, 35, 0, time.UTC),
+			User: &entity.User{
+				Name: "Someone else",
+			},
+			Status: enum.PostOpen,
+			Tags:   []string{},
+			CustomFields: map[string]any{
+				"segment":    "Enterprise",
+				"arr-impact": float64(125000),
+				"platform":   []any{"iOS", "Web"},
+			},
+		},
+	}
+
+	expected, err := os.ReadFile("./testdata/custom-fields.csv")
+	Expect(err).IsNil()
+	actual, err := csv.FromPosts(posts, fields)
 	Expect(err).IsNil()
 	Expect(actual).Equals(expected)
 }
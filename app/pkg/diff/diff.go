@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Operation describes how a piece of text changed between two versions
+type Operation string
+
+const (
+	// Equal is text present in both versions
+	Equal Operation = "equal"
+	// Insert is text only present in the newer version
+	Insert Operation = "insert"
+	// Delete is text only present in the older version
+	Delete Operation = "delete"
+)
+
+// Change is a contiguous piece of text with the same operation
+type Change struct {
+	Op   Operation `json:"op"`
+	Text string    `json:"text"`
+}
+
+// Lines compares two texts line by line
+func Lines(from, to string) []Change {
+	return compare(splitLines(from), splitLines(to))
+}
+
+// Words compares two texts word by word, which is better suited for short texts such as titles
+func Words(from, to string) []Change {
+	return compare(splitWords(from), splitWords(to))
+}
+
+// maxMatrixSize caps the cells of the LCS matrix, above which the changed part is shown as fully replaced
+const maxMatrixSize = 1000000
+
+func compare(a, b []string) []Change {
+	changes := make([]Change, 0)
+	add := func(op Operation, text string) {
+		if last := len(changes) - 1; last >= 0 && changes[last].Op == op {
+			changes[last].Text += text
+			return
+		}
+		changes = append(changes, Change{Op: op, Text: text})
+	}
+
+	// Edits are usually small, so the common prefix and suffix are left out of the matrix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, text := range a[:prefix] {
+		add(Equal, text)
+	}
+
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(middleA)*len(middleB) > maxMatrixSize {
+		for _, text := range middleA {
+			add(Delete, text)
+		}
+		for _, text := range middleB {
+			add(Insert, text)
+		}
+	} else {
+		compareLCS(middleA, middleB, add)
+	}
+
+	for _, text := range a[len(a)-suffix:] {
+		add(Equal, text)
+	}
+	return changes
+}
+
+func compareLCS(a, b []string, add func(op Operation, text string)) {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(Equal, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(Delete, a[i])
+			i++
+		default:
+			add(Insert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(Delete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(Insert, b[j])
+	}
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords keeps trailing whitespace attached to each word so that joining the tokens gives back the original text
+func splitWords(text string) []string {
+	tokens := make([]string, 0)
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		isSpace := unicode.IsSpace(r)
+		if !isSpace && inSpace {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		inSpace = isSpace
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
@@ -0,0 +1,62 @@
+package diff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/diff"
+)
+
+func TestLines(t *testing.T) {
+	RegisterT(t)
+
+	changes := diff.Lines("first\nsecond\nthird\n", "first\nchanged\nthird\nfourth\n")
+	Expect(changes).Equals([]diff.Change{
+		{Op: diff.Equal, Text: "first\n"},
+		{Op: diff.Delete, Text: "second\n"},
+		{Op: diff.Insert, Text: "changed\n"},
+		{Op: diff.Equal, Text: "third\n"},
+		{Op: diff.Insert, Text: "fourth\n"},
+	})
+}
+
+func TestLines_Empty(t *testing.T) {
+	RegisterT(t)
+
+	Expect(diff.Lines("", "")).Equals([]diff.Change{})
+	Expect(diff.Lines("", "new")).Equals([]diff.Change{{Op: diff.Insert, Text: "new"}})
+	Expect(diff.Lines("old", "")).Equals([]diff.Change{{Op: diff.Delete, Text: "old"}})
+}
+
+func TestWords(t *testing.T) {
+	RegisterT(t)
+
+	changes := diff.Words("Add dark mode to mobile app", "Add light mode to mobile app")
+	Expect(changes).Equals([]diff.Change{
+		{Op: diff.Equal, Text: "Add "},
+		{Op: diff.Delete, Text: "dark "},
+		{Op: diff.Insert, Text: "light "},
+		{Op: diff.Equal, Text: "mode to mobile app"},
+	})
+}
+
+func TestLines_LargeChange(t *testing.T) {
+	RegisterT(t)
+
+	from := strings.Builder{}
+	to := strings.Builder{}
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&from, "old %d\n", i)
+		fmt.Fprintf(&to, "new %d\n", i)
+	}
+
+	changes := diff.Lines("header\n"+from.String()+"footer\n", "header\n"+to.String()+"footer\n")
+	Expect(changes).Equals([]diff.Change{
+		{Op: diff.Equal, Text: "header\n"},
+		{Op: diff.Delete, Text: from.String()},
+		{Op: diff.Insert, Text: to.String()},
+		{Op: diff.Equal, Text: "footer\n"},
+	})
+}
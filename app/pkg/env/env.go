@@ -101,20 +101,28 @@ type config struct {
 			Secret   string `env:"OAUTH_GITHUB_SECRET"`
 		}
 	}
+	OAuthProvider struct {
+		SigningKey string `env:"OAUTH_PROVIDER_SIGNING_KEY"` // signs ID tokens issued to OAuth clients, generate one with: fider signingkey
+	}
 	Email struct {
-		Type      string `env:"EMAIL"` // possible values: smtp, mailgun, awsses
-		NoReply   string `env:"EMAIL_NOREPLY,required"`
-		Allowlist string `env:"EMAIL_ALLOWLIST"`
-		Blocklist string `env:"EMAIL_BLOCKLIST"`
-		AWSSES    struct {
+		Type                string `env:"EMAIL"` // possible values: smtp, mailgun, awsses
+		NoReply             string `env:"EMAIL_NOREPLY,required"`
+		Allowlist           string `env:"EMAIL_ALLOWLIST"`
+		Blocklist           string `env:"EMAIL_BLOCKLIST"`
+		BounceWebhookSecret string `env:"EMAIL_BOUNCE_WEBHOOK_SECRET"`
+		AWSSES              struct {
 			Region          string `env:"EMAIL_AWSSES_REGION"`
 			AccessKeyID     string `env:"EMAIL_AWSSES_ACCESS_KEY_ID"`
 			SecretAccessKey string `env:"EMAIL_AWSSES_SECRET_ACCESS_KEY"`
+			SNSTopicARN     string `env:"EMAIL_AWSSES_SNS_TOPIC_ARN"`          // topic that SES publishes bounces and complaints to
+			RateLimit       int    `env:"EMAIL_AWSSES_RATE_LIMIT,default=600"` // emails per minute
 		}
 		Mailgun struct {
-			APIKey string `env:"EMAIL_MAILGUN_API"`
-			Domain string `env:"EMAIL_MAILGUN_DOMAIN"`
-			Region string `env:"EMAIL_MAILGUN_REGION,default=US"` // possible values: US or EU
+			APIKey            string `env:"EMAIL_MAILGUN_API"`
+			Domain            string `env:"EMAIL_MAILGUN_DOMAIN"`
+			Region            string `env:"EMAIL_MAILGUN_REGION,default=US"` // possible values: US or EU
+			WebhookSigningKey string `env:"EMAIL_MAILGUN_WEBHOOK_SIGNING_KEY"`
+			RateLimit         int    `env:"EMAIL_MAILGUN_RATE_LIMIT,default=600"` // emails per minute
 		}
 		SMTP struct {
 			Host           string `env:"EMAIL_SMTP_HOST"`
@@ -122,8 +130,19 @@ type config struct {
 			Username       string `env:"EMAIL_SMTP_USERNAME"`
 			Password       string `env:"EMAIL_SMTP_PASSWORD"`
 			EnableStartTLS bool   `env:"EMAIL_SMTP_ENABLE_STARTTLS,default=true"`
+			RateLimit      int    `env:"EMAIL_SMTP_RATE_LIMIT,default=60"` // emails per minute
+		}
+		Inbound struct {
+			Domain      string `env:"EMAIL_INBOUND_DOMAIN"`       // domain of reply addresses, e.g: reply.mysite.com
+			SMTPAddress string `env:"EMAIL_INBOUND_SMTP_ADDRESS"` // address of the local SMTP listener, e.g: :2525
+			AuthServID  string `env:"EMAIL_INBOUND_AUTHSERV_ID"`  // id of the MTA whose Authentication-Results header is trusted, e.g: mx.mysite.com
 		}
 	}
+	WebPush struct {
+		VAPIDPublicKey  string `env:"WEBPUSH_VAPID_PUBLIC_KEY"` // generate a key pair with: fider vapidkeys
+		VAPIDPrivateKey string `env:"WEBPUSH_VAPID_PRIVATE_KEY"`
+		Subject         string `env:"WEBPUSH_SUBJECT"` // contact of this instance for push services, e.g: mailto:admin@mysite.com
+	}
 	BlobStorage struct {
 		Type string `env:"BLOB_STORAGE,default=sql"` // possible values: sql, fs or s3
 		S3   struct {
@@ -239,6 +258,23 @@ func MultiTenantDomain() string {
 	return ""
 }
 
+// EmailRateLimit returns how many emails per minute can be sent by the configured email provider
+func EmailRateLimit() int {
+	switch Config.Email.Type {
+	case "mailgun":
+		return Config.Email.Mailgun.RateLimit
+	case "awsses":
+		return Config.Email.AWSSES.RateLimit
+	default:
+		return Config.Email.SMTP.RateLimit
+	}
+}
+
+// IsWebPushEnabled returns true if VAPID keys are configured
+func IsWebPushEnabled() bool {
+	return Config.WebPush.VAPIDPublicKey != "" && Config.WebPush.VAPIDPrivateKey != ""
+}
+
 // IsBillingEnabled returns true if Paddle is configured
 func IsBillingEnabled() bool {
 	return Config.Paddle.VendorID != "" && Config.Paddle.VendorAuthCode != ""
@@ -0,0 +1,90 @@
+package inbound
+
+import (
+	"regexp"
+	"strings"
+)
+
+var commentRegex = regexp.MustCompile(`\([^()]*\)`)
+
+// IsAlignedDomain returns true if given domain is the domain of given address or one of its parent or sub domains
+// This is the relaxed alignment used by DMARC to tie SPF and DKIM results to the From header
+func IsAlignedDomain(domain, address string) bool {
+	domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if i := strings.LastIndex(domain, "@"); i >= 0 {
+		domain = domain[i+1:]
+	}
+
+	i := strings.LastIndex(address, "@")
+	if domain == "" || i < 0 {
+		return false
+	}
+	fromDomain := strings.Trim(strings.ToLower(address[i+1:]), ".")
+	if fromDomain == "" {
+		return false
+	}
+
+	return domain == fromDomain ||
+		strings.HasSuffix(fromDomain, "."+domain) && strings.Contains(domain, ".") ||
+		strings.HasSuffix(domain, "."+fromDomain)
+}
+
+// DKIMSignatureDomain returns the signing domain (d= tag) of a DKIM-Signature header
+func DKIMSignatureDomain(signature string) string {
+	for _, tag := range strings.Split(signature, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(tag), "=")
+		if ok && strings.TrimSpace(name) == "d" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// IsAuthenticatedSender returns true if given Authentication-Results header was added by the trusted server
+// and reports a passing DMARC, DKIM or SPF check for the domain of given From address
+func IsAuthenticatedSender(header, authservID, from string) bool {
+	if authservID == "" || from == "" {
+		return false
+	}
+
+	results := strings.Split(commentRegex.ReplaceAllString(header, ""), ";")
+	id := strings.Fields(results[0])
+	if len(id) == 0 || !strings.EqualFold(id[0], authservID) {
+		return false
+	}
+
+	for _, result := range results[1:] {
+		fields := strings.Fields(result)
+		if len(fields) == 0 {
+			continue
+		}
+
+		method, value, _ := strings.Cut(strings.ToLower(fields[0]), "=")
+		if value != "pass" {
+			continue
+		}
+
+		props := make(map[string]string)
+		for _, field := range fields[1:] {
+			if name, value, ok := strings.Cut(field, "="); ok {
+				props[strings.ToLower(name)] = strings.Trim(value, `"`)
+			}
+		}
+
+		switch method {
+		case "dmarc":
+			if IsAlignedDomain(props["header.from"], from) {
+				return true
+			}
+		case "dkim":
+			if IsAlignedDomain(props["header.d"], from) || IsAlignedDomain(props["header.i"], from) {
+				return true
+			}
+		case "spf":
+			if IsAlignedDomain(props["smtp.mailfrom"], from) {
+				return true
+			}
+		}
+	}
+	return false
+}
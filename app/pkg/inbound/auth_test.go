@@ -0,0 +1,87 @@
+package inbound_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
+)
+
+func TestIsAlignedDomain(t *testing.T) {
+	RegisterT(t)
+
+	Expect(inbound.IsAlignedDomain("got.com", "jon.snow@got.com")).IsTrue()
+	Expect(inbound.IsAlignedDomain("GOT.com", "jon.snow@got.com")).IsTrue()
+	Expect(inbound.IsAlignedDomain("bounces@got.com", "jon.snow@got.com")).IsTrue()
+	Expect(inbound.IsAlignedDomain("mail.got.com", "jon.snow@got.com")).IsTrue()
+	Expect(inbound.IsAlignedDomain("got.com", "jon.snow@north.got.com")).IsTrue()
+	Expect(inbound.IsAlignedDomain("evil.com", "jon.snow@got.com")).IsFalse()
+	Expect(inbound.IsAlignedDomain("notgot.com", "jon.snow@got.com")).IsFalse()
+	Expect(inbound.IsAlignedDomain("com", "jon.snow@got.com")).IsFalse()
+	Expect(inbound.IsAlignedDomain("", "jon.snow@got.com")).IsFalse()
+	Expect(inbound.IsAlignedDomain("got.com", "")).IsFalse()
+}
+
+func TestDKIMSignatureDomain(t *testing.T) {
+	RegisterT(t)
+
+	Expect(inbound.DKIMSignatureDomain("v=1; a=rsa-sha256; c=relaxed/relaxed; d=got.com; s=mail; h=from:to; bh=abc; b=def")).Equals("got.com")
+	Expect(inbound.DKIMSignatureDomain("v=1; a=rsa-sha256")).Equals("")
+}
+
+func TestIsAuthenticatedSender(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		header        string
+		authenticated bool
+	}{
+		{"mx.fider.io; dmarc=pass (p=reject dis=none) header.from=got.com", true},
+		{"mx.fider.io; dkim=pass header.d=got.com header.s=mail", true},
+		{"mx.fider.io; dkim=pass header.i=@mail.got.com", true},
+		{"mx.fider.io; spf=pass (sender IP is 1.2.3.4) smtp.mailfrom=bounces@got.com", true},
+		{"mx.fider.io 1; spf=fail smtp.mailfrom=got.com; dkim=pass header.d=got.com", true},
+		{"MX.fider.io; dmarc=pass header.from=got.com", true},
+		{"mx.fider.io; dmarc=fail header.from=got.com", false},
+		{"mx.fider.io; dkim=pass header.d=evil.com; spf=pass smtp.mailfrom=evil.com", false},
+		{"mx.fider.io; dmarc=pass", false},
+		{"mx.evil.com; dmarc=pass header.from=got.com", false},
+		{"mx.fider.io; none", false},
+		{"", false},
+	}
+
+	for _, testCase := range testCases {
+		Expect(inbound.IsAuthenticatedSender(testCase.header, "mx.fider.io", "jon.snow@got.com")).Equals(testCase.authenticated)
+	}
+
+	Expect(inbound.IsAuthenticatedSender("mx.fider.io; dmarc=pass header.from=got.com", "", "jon.snow@got.com")).IsFalse()
+}
+
+func TestParseMessage_AuthenticationResults(t *testing.T) {
+	RegisterT(t)
+	env.Config.Email.Inbound.AuthServID = "mx.fider.io"
+	t.Cleanup(func() {
+		env.Config.Email.Inbound.AuthServID = ""
+	})
+
+	parse := func(headers string) *inbound.Message {
+		msg, err := inbound.ParseMessage(strings.NewReader(headers +
+			"From: Jon Snow <jon.snow@got.com>\r\n" +
+			"To: feedback+demo@reply.test.fider.io\r\n" +
+			"Subject: Dark mode\r\n" +
+			"\r\n" +
+			"Please!\r\n"))
+		Expect(err).IsNil()
+		return msg
+	}
+
+	Expect(parse("").SenderAuthenticated).IsFalse()
+	Expect(parse("Authentication-Results: mx.fider.io; dmarc=pass header.from=got.com\r\n").SenderAuthenticated).IsTrue()
+
+	// Only the header added by the trusted server, which is the topmost one, is considered
+	msg := parse("Authentication-Results: mx.fider.io; dmarc=fail header.from=got.com\r\n" +
+		"Authentication-Results: mx.fider.io; dmarc=pass header.from=got.com\r\n")
+	Expect(msg.SenderAuthenticated).IsFalse()
+}
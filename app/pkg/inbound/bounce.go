@@ -0,0 +1,101 @@
+package inbound
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/env"
+)
+
+const bouncePrefix = "bounce+"
+
+// BounceAddress returns the envelope sender to use when emailing given recipient, so that its bounces are sent back to Fider
+// An empty string is returned unless the inbound SMTP listener is enabled, as bounces are not received through provider webhooks
+func BounceAddress(recipient string) string {
+	if !IsEnabled() || env.Config.Email.Inbound.SMTPAddress == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s@%s", bouncePrefix, signRecipient(recipient), strings.ToLower(env.Config.Email.Inbound.Domain))
+}
+
+// IsBounceAddressFor returns true if given address is the bounce address of given recipient
+func IsBounceAddressFor(address, recipient string) bool {
+	if !IsEnabled() {
+		return false
+	}
+
+	local := localPart(address)
+	if !strings.HasPrefix(local, bouncePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(strings.TrimPrefix(local, bouncePrefix)), []byte(signRecipient(recipient)))
+}
+
+func signRecipient(recipient string) string {
+	return sign("bounce:" + strings.ToLower(strings.TrimSpace(recipient)))
+}
+
+// readReportFields reads the groups of fields of a delivery status or feedback report, which are separated by blank lines
+func readReportFields(r io.Reader) []textproto.MIMEHeader {
+	groups := make([]textproto.MIMEHeader, 0)
+	reader := textproto.NewReader(bufio.NewReader(r))
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			groups = append(groups, fields)
+		}
+		if err != nil {
+			return groups
+		}
+	}
+}
+
+// readFailedRecipients returns the recipients of a delivery status notification that permanently failed
+func readFailedRecipients(r io.Reader) []string {
+	recipients := make([]string, 0)
+	for _, fields := range readReportFields(r) {
+		recipient := reportAddress(fields.Get("Final-Recipient"))
+		if recipient == "" {
+			recipient = reportAddress(fields.Get("Original-Recipient"))
+		}
+
+		action := strings.ToLower(strings.TrimSpace(fields.Get("Action")))
+		status := strings.TrimSpace(fields.Get("Status"))
+		if recipient != "" && action == "failed" && strings.HasPrefix(status, "5") {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// readComplainedRecipients returns the recipients of an abuse feedback report
+func readComplainedRecipients(r io.Reader) []string {
+	recipients := make([]string, 0)
+	for _, fields := range readReportFields(r) {
+		for _, value := range fields.Values("Original-Rcpt-To") {
+			if recipient := reportAddress(value); recipient != "" {
+				recipients = append(recipients, recipient)
+			}
+		}
+	}
+	return recipients
+}
+
+// reportAddress returns the address of report fields such as "rfc822; jon@got.com"
+func reportAddress(value string) string {
+	if _, address, ok := strings.Cut(value, ";"); ok {
+		value = address
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(value))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(address.Address)
+}
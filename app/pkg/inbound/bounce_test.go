@@ -0,0 +1,93 @@
+package inbound_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
+)
+
+func TestBounceAddress(t *testing.T) {
+	RegisterT(t)
+
+	Expect(inbound.BounceAddress("jon.snow@got.com")).Equals("")
+
+	enableInbound(t)
+	Expect(inbound.BounceAddress("jon.snow@got.com")).Equals("")
+
+	env.Config.Email.Inbound.SMTPAddress = ":2525"
+	address := inbound.BounceAddress("jon.snow@got.com")
+	Expect(strings.HasPrefix(address, "bounce+")).IsTrue()
+	Expect(strings.HasSuffix(address, "@reply.test.fider.io")).IsTrue()
+
+	Expect(inbound.IsBounceAddressFor(address, "jon.snow@got.com")).IsTrue()
+	Expect(inbound.IsBounceAddressFor(address, "Jon.Snow@got.com")).IsTrue()
+	Expect(inbound.IsBounceAddressFor(address, "arya.stark@got.com")).IsFalse()
+	Expect(inbound.IsBounceAddressFor(inbound.BounceAddress("arya.stark@got.com"), "jon.snow@got.com")).IsFalse()
+	Expect(inbound.IsBounceAddressFor("bounce+0000000000000000@reply.test.fider.io", "jon.snow@got.com")).IsFalse()
+}
+
+func TestParseMessage_DeliveryStatusNotification(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: MAILER-DAEMON@mail.got.com\r\n" +
+		"To: bounce+abc@reply.test.fider.io\r\n" +
+		"Subject: Undelivered Mail Returned to Sender\r\n" +
+		"Content-Type: multipart/report; report-type=delivery-status; boundary=\"XYZ\"\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"I'm sorry to have to inform you that your message could not be delivered.\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: message/delivery-status\r\n" +
+		"\r\n" +
+		"Reporting-MTA: dns; mail.got.com\r\n" +
+		"\r\n" +
+		"Final-Recipient: rfc822; Jon.Snow@got.com\r\n" +
+		"Action: failed\r\n" +
+		"Status: 5.1.1\r\n" +
+		"\r\n" +
+		"Final-Recipient: rfc822; arya.stark@got.com\r\n" +
+		"Action: delayed\r\n" +
+		"Status: 4.4.1\r\n" +
+		"--XYZ--\r\n"))
+
+	Expect(err).IsNil()
+	Expect(msg.Bounces).Equals([]string{"jon.snow@got.com"})
+	Expect(msg.Complaints).HasLen(0)
+}
+
+func TestParseMessage_FeedbackReport(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: feedback@mail.got.com\r\n" +
+		"To: bounce+abc@reply.test.fider.io\r\n" +
+		"Content-Type: multipart/report; report-type=feedback-report; boundary=\"XYZ\"\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"This is an email abuse report\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: message/feedback-report\r\n" +
+		"\r\n" +
+		"Feedback-Type: abuse\r\n" +
+		"Version: 1\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: message/rfc822\r\n" +
+		"\r\n" +
+		"From: noreply@fider.io\r\n" +
+		"To: \"Jon Snow\" <Jon.Snow@got.com>\r\n" +
+		"Subject: [Demonstration] New post\r\n" +
+		"\r\n" +
+		"Hello\r\n" +
+		"--XYZ--\r\n"))
+
+	Expect(err).IsNil()
+	Expect(msg.Bounces).HasLen(0)
+	Expect(msg.Complaints).Equals([]string{"jon.snow@got.com"})
+	Expect(msg.Attachments).HasLen(0)
+}
@@ -0,0 +1,205 @@
+package inbound
+
+import (
+	"bufio"
+	"encoding/base64"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Message is an email received by Fider
+type Message struct {
+	From        string
+	FromName    string
+	To          []string
+	Subject     string
+	Text        string
+	Attachments []*Attachment
+
+	// SenderAuthenticated is true when the provider verified that the From address isn't forged, using DMARC, DKIM or SPF
+	SenderAuthenticated bool
+
+	// Recipients reported by delivery status notifications and abuse feedback reports
+	Bounces    []string
+	Complaints []string
+}
+
+// Attachment is a file attached to an email received by Fider
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// Images returns the attachments of the message that are images
+func (m *Message) Images() []*Attachment {
+	images := make([]*Attachment, 0)
+	for _, attachment := range m.Attachments {
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			images = append(images, attachment)
+		}
+	}
+	return images
+}
+
+var (
+	strictHtmlPolicy = bluemonday.StrictPolicy()
+	wordDecoder      = new(mime.WordDecoder)
+)
+
+// ParseMessage reads a raw MIME message and returns its sender, recipients, subject, text content and attachments
+func ParseMessage(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read message")
+	}
+
+	result := &Message{
+		To:          make([]string, 0),
+		Attachments: make([]*Attachment, 0),
+	}
+
+	if from, err := mail.ParseAddress(msg.Header.Get("From")); err == nil {
+		result.From = from.Address
+		result.FromName = from.Name
+	}
+
+	// Only the topmost header is considered, as it's added by the server in front of Fider and can't come from the sender
+	if results := msg.Header["Authentication-Results"]; len(results) > 0 {
+		result.SenderAuthenticated = IsAuthenticatedSender(results[0], env.Config.Email.Inbound.AuthServID, result.From)
+	}
+
+	result.Subject = msg.Header.Get("Subject")
+	if subject, err := wordDecoder.DecodeHeader(result.Subject); err == nil {
+		result.Subject = subject
+	}
+
+	if to, err := msg.Header.AddressList("To"); err == nil {
+		for _, address := range to {
+			result.To = append(result.To, address.Address)
+		}
+	}
+
+	body := &messageBody{}
+	if err := body.read(textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, err
+	}
+
+	result.Text = body.text
+	result.Attachments = append(result.Attachments, body.attachments...)
+	result.Bounces = body.bounces
+	result.Complaints = body.complaints
+	if body.isFeedbackReport && len(result.Complaints) == 0 {
+		result.Complaints = body.originalTo
+	}
+	if htmlText := body.html; result.Text == "" && htmlText != "" {
+		htmlText = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n\n", "</div>", "\n").Replace(htmlText)
+		result.Text = html.UnescapeString(strictHtmlPolicy.Sanitize(htmlText))
+	}
+
+	return result, nil
+}
+
+type messageBody struct {
+	text        string
+	html        string
+	attachments []*Attachment
+
+	bounces          []string
+	complaints       []string
+	isFeedbackReport bool
+	originalTo       []string
+}
+
+// read keeps the first text/plain and text/html contents and all the attachments of given part, decoding multipart bodies recursively
+func (b *messageBody) read(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return errors.Wrap(err, "failed to read message part")
+			}
+
+			if err := b.read(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	switch mediaType {
+	case "message/delivery-status":
+		b.bounces = append(b.bounces, readFailedRecipients(body)...)
+		return nil
+	case "message/feedback-report":
+		b.isFeedbackReport = true
+		b.complaints = append(b.complaints, readComplainedRecipients(body)...)
+		return nil
+	case "message/rfc822", "text/rfc822-headers":
+		// Reports include the original message, which tells who it was sent to
+		original, _ := textproto.NewReader(bufio.NewReader(body)).ReadMIMEHeader()
+		if to, err := mail.Header(original).AddressList("To"); err == nil && b.originalTo == nil {
+			for _, address := range to {
+				b.originalTo = append(b.originalTo, strings.ToLower(address.Address))
+			}
+		}
+		return nil
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	isAttachment := disposition == "attachment" || (mediaType != "text/plain" && mediaType != "text/html")
+	if isAttachment {
+		content, err := io.ReadAll(io.LimitReader(body, maxMessageSize))
+		if err != nil {
+			return errors.Wrap(err, "failed to read message attachment")
+		}
+
+		fileName := dispositionParams["filename"]
+		if fileName == "" {
+			fileName = params["name"]
+		}
+		b.attachments = append(b.attachments, &Attachment{
+			FileName:    fileName,
+			ContentType: mediaType,
+			Content:     content,
+		})
+		return nil
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read message content")
+	}
+
+	if mediaType == "text/html" && b.html == "" {
+		b.html = string(content)
+	} else if mediaType == "text/plain" && b.text == "" {
+		b.text = string(content)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package inbound_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/inbound"
+)
+
+func TestParseMessage_PlainText(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: Jon Snow <jon.snow@got.com>\r\n" +
+		"To: reply+1.2.3.abc@reply.test.fider.io\r\n" +
+		"Subject: Re: New comment\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		"Sounds great!\r\n"))
+
+	Expect(err).IsNil()
+	Expect(msg.From).Equals("jon.snow@got.com")
+	Expect(msg.To).Equals([]string{"reply+1.2.3.abc@reply.test.fider.io"})
+	Expect(msg.Text).Equals("Sounds great!\r\n")
+}
+
+func TestParseMessage_Multipart(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: jon.snow@got.com\r\n" +
+		"To: reply+1.2.3.abc@reply.test.fider.io\r\n" +
+		"Content-Type: multipart/alternative; boundary=\"XYZ\"\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Caf=C3=A9 is great\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"\r\n" +
+		"<p>Caf&eacute; is great</p>\r\n" +
+		"--XYZ--\r\n"))
+
+	Expect(err).IsNil()
+	Expect(msg.From).Equals("jon.snow@got.com")
+	Expect(msg.Text).Equals("Café is great")
+}
+
+func TestParseMessage_HTMLOnly(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: jon.snow@got.com\r\n" +
+		"Content-Type: text/html; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"PHA+VG9tICZhbXA7IEplcnJ5PC9wPg==\r\n"))
+
+	Expect(err).IsNil()
+	Expect(strings.TrimSpace(msg.Text)).Equals("Tom & Jerry")
+}
+
+func TestParseMessage_WithAttachments(t *testing.T) {
+	RegisterT(t)
+
+	msg, err := inbound.ParseMessage(strings.NewReader("From: \"Jon Snow\" <jon.snow@got.com>\r\n" +
+		"To: feedback+demo@reply.test.fider.io\r\n" +
+		"Subject: =?UTF-8?Q?Caf=C3=A9_menu_in_dark_mode?=\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"XYZ\"\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		"Please add it\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: image/png; name=\"screenshot.png\"\r\n" +
+		"Content-Disposition: attachment; filename=\"screenshot.png\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"aGVsbG8=\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+		"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+		"\r\n" +
+		"Some notes\r\n" +
+		"--XYZ--\r\n"))
+
+	Expect(err).IsNil()
+	Expect(msg.From).Equals("jon.snow@got.com")
+	Expect(msg.FromName).Equals("Jon Snow")
+	Expect(msg.Subject).Equals("Café menu in dark mode")
+	Expect(msg.Text).Equals("Please add it")
+	Expect(msg.Attachments).HasLen(2)
+	Expect(msg.Images()).HasLen(1)
+	Expect(msg.Images()[0].FileName).Equals("screenshot.png")
+	Expect(msg.Images()[0].ContentType).Equals("image/png")
+	Expect(msg.Images()[0].Content).Equals([]byte("hello"))
+}
@@ -0,0 +1,38 @@
+package inbound
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/env"
+)
+
+const postPrefix = "feedback+"
+
+// PostAddress returns the address that new posts can be sent to for the tenant with given subdomain
+// An empty string is returned when inbound email is not enabled
+func PostAddress(subdomain string) string {
+	if !IsEnabled() || subdomain == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s@%s", postPrefix, strings.ToLower(subdomain), strings.ToLower(env.Config.Email.Inbound.Domain))
+}
+
+// ParsePostAddress returns the subdomain of the tenant that given address submits posts to, or an empty string if it's not a post address
+func ParsePostAddress(address string) string {
+	if !IsEnabled() {
+		return ""
+	}
+
+	local := localPart(address)
+	if !strings.HasPrefix(local, postPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(local, postPrefix)
+}
+
+// PendingPostBlobKey returns the key of the blob that keeps a post waiting for its sender to confirm it
+func PendingPostBlobKey(key string) string {
+	return fmt.Sprintf("inbound/pending/%s.json", key)
+}
@@ -0,0 +1,36 @@
+package inbound_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/inbound"
+)
+
+func TestPostAddress_Disabled(t *testing.T) {
+	RegisterT(t)
+
+	Expect(inbound.PostAddress("demo")).Equals("")
+	Expect(inbound.ParsePostAddress("feedback+demo@reply.test.fider.io")).Equals("")
+}
+
+func TestPostAddress_RoundTrip(t *testing.T) {
+	RegisterT(t)
+	enableInbound(t)
+
+	address := inbound.PostAddress("Demo")
+	Expect(address).Equals("feedback+demo@reply.test.fider.io")
+	Expect(inbound.ParsePostAddress(address)).Equals("demo")
+	Expect(inbound.ParsePostAddress("Demo Feedback <FEEDBACK+demo@reply.test.fider.io>")).Equals("demo")
+	Expect(inbound.PostAddress("")).Equals("")
+}
+
+func TestParsePostAddress_Invalid(t *testing.T) {
+	RegisterT(t)
+	enableInbound(t)
+
+	Expect(inbound.ParsePostAddress("feedback+demo@other.fider.io")).Equals("")
+	Expect(inbound.ParsePostAddress("reply+1.2.3.abc@reply.test.fider.io")).Equals("")
+	Expect(inbound.ParsePostAddress("demo@reply.test.fider.io")).Equals("")
+	Expect(inbound.ParsePostAddress("not an address")).Equals("")
+}
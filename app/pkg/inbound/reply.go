@@ -0,0 +1,139 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/env"
+)
+
+const replyPrefix = "reply+"
+
+// Reply identifies the user and the post that a reply address was issued for
+type Reply struct {
+	TenantID int
+	UserID   int
+	PostID   int
+}
+
+// IsEnabled returns true if users can reply to notification emails
+func IsEnabled() bool {
+	return env.Config.Email.Inbound.Domain != ""
+}
+
+// ReplyAddress returns a signed address that given user can reply to in order to comment on given post
+// An empty string is returned when inbound email is not enabled
+func ReplyAddress(tenantID, userID, postID int) string {
+	if !IsEnabled() {
+		return ""
+	}
+
+	payload := fmt.Sprintf("%d.%d.%d", tenantID, userID, postID)
+	return fmt.Sprintf("%s%s.%s@%s", replyPrefix, payload, sign(payload), strings.ToLower(env.Config.Email.Inbound.Domain))
+}
+
+// ParseReplyAddress returns the details of given reply address, or nil if it's not a valid one
+func ParseReplyAddress(address string) *Reply {
+	if !IsEnabled() {
+		return nil
+	}
+
+	local := localPart(address)
+	if !strings.HasPrefix(local, replyPrefix) {
+		return nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(local, replyPrefix), ".")
+	if len(parts) != 4 {
+		return nil
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(sign(payload))) {
+		return nil
+	}
+
+	ids := make([]int, 3)
+	for i, part := range parts[:3] {
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return nil
+		}
+		ids[i] = id
+	}
+
+	return &Reply{TenantID: ids[0], UserID: ids[1], PostID: ids[2]}
+}
+
+// localPart returns the lowercased local part of given address if it belongs to the inbound domain
+func localPart(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(address)), "@")
+	if !ok || domain != strings.ToLower(env.Config.Email.Inbound.Domain) {
+		return ""
+	}
+	return local
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(env.Config.JWTSecret))
+	mac.Write([]byte(replyPrefix + payload))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+var (
+	quoteHeaderRegex = regexp.MustCompile(`(?i)^(on\s.+\swrote:|.+\s(a écrit|schrieb|escribió|ha scritto|schreef|escreveu)\s?:)$`)
+	separatorRegex   = regexp.MustCompile(`^(-{2,}\s*original message\s*-{2,}|_{10,})$`)
+	mobileRegex      = regexp.MustCompile(`(?i)^sent from my `)
+	headerRegex      = regexp.MustCompile(`(?i)^(from|sent|date|to|subject):\s`)
+)
+
+// StripReply returns the text written by the sender of a reply,
+// without the quoted message, the quote header and the signature
+func StripReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	result := make([]string, 0, len(lines))
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		// Signature delimiter as described on RFC 3676
+		if line == "--" || mobileRegex.MatchString(trimmed) || separatorRegex.MatchString(strings.ToLower(trimmed)) {
+			break
+		}
+
+		// Quote headers might be wrapped over two lines by some clients
+		if quoteHeaderRegex.MatchString(trimmed) {
+			break
+		}
+		if i+1 < len(lines) {
+			next := strings.TrimSpace(lines[i+1])
+			if !quoteHeaderRegex.MatchString(next) && quoteHeaderRegex.MatchString(trimmed+" "+next) {
+				break
+			}
+		}
+
+		// Outlook quotes the original message below a block of headers
+		if headerRegex.MatchString(trimmed) && i+1 < len(lines) && headerRegex.MatchString(strings.TrimSpace(lines[i+1])) {
+			break
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
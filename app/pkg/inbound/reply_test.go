@@ -0,0 +1,110 @@
+package inbound_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
+)
+
+func enableInbound(t *testing.T) {
+	env.Config.Email.Inbound.Domain = "reply.test.fider.io"
+	t.Cleanup(func() {
+		env.Config.Email.Inbound.Domain = ""
+		env.Config.Email.Inbound.SMTPAddress = ""
+	})
+}
+
+func TestReplyAddress_Disabled(t *testing.T) {
+	RegisterT(t)
+
+	Expect(inbound.IsEnabled()).IsFalse()
+	Expect(inbound.ReplyAddress(1, 2, 3)).Equals("")
+	Expect(inbound.ParseReplyAddress("reply+1.2.3.abc@reply.test.fider.io")).IsNil()
+}
+
+func TestReplyAddress_RoundTrip(t *testing.T) {
+	RegisterT(t)
+	enableInbound(t)
+
+	address := inbound.ReplyAddress(1, 2, 3)
+	Expect(strings.HasPrefix(address, "reply+1.2.3.")).IsTrue()
+	Expect(strings.HasSuffix(address, "@reply.test.fider.io")).IsTrue()
+
+	reply := inbound.ParseReplyAddress(address)
+	Expect(reply).Equals(&inbound.Reply{TenantID: 1, UserID: 2, PostID: 3})
+
+	reply = inbound.ParseReplyAddress("Jon Snow <" + address + ">")
+	Expect(reply).Equals(&inbound.Reply{TenantID: 1, UserID: 2, PostID: 3})
+}
+
+func TestReplyAddress_InvalidAddresses(t *testing.T) {
+	RegisterT(t)
+	enableInbound(t)
+
+	address := inbound.ReplyAddress(1, 2, 3)
+	signature := address[len("reply+1.2.3.") : len(address)-len("@reply.test.fider.io")]
+
+	for _, invalid := range []string{
+		"",
+		"jon.snow@got.com",
+		"reply+1.2.4." + signature + "@reply.test.fider.io",
+		"reply+1.2.3." + signature + "@other.domain.com",
+		"reply+1.2.3@reply.test.fider.io",
+		"reply+1.2.3.0000000000000000@reply.test.fider.io",
+	} {
+		Expect(inbound.ParseReplyAddress(invalid)).IsNil()
+	}
+}
+
+func TestStripReply(t *testing.T) {
+	RegisterT(t)
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "Sounds great!\r\n\r\nOn Mon, Jan 1, 2024 at 10:00 AM Jon Snow <noreply@fider.io> wrote:\r\n> Previous message",
+			expected: "Sounds great!",
+		},
+		{
+			input:    "Sounds great!\n\nOn Mon, Jan 1, 2024 at 10:00 AM Jon Snow\n<noreply@fider.io> wrote:\n> Previous message",
+			expected: "Sounds great!",
+		},
+		{
+			input:    "Me too.\n\nLe lun. 1 janv. 2024 à 10:00, Jon Snow <noreply@fider.io> a écrit :\n> Previous message",
+			expected: "Me too.",
+		},
+		{
+			input:    "First line\nSecond line\n\n-- \nArya Stark\nFaceless Inc.",
+			expected: "First line\nSecond line",
+		},
+		{
+			input:    "Agreed\n\nSent from my iPhone",
+			expected: "Agreed",
+		},
+		{
+			input:    "Agreed\n\n-----Original Message-----\nFrom: Jon Snow\nSent: Monday",
+			expected: "Agreed",
+		},
+		{
+			input:    "Agreed\n\nFrom: Jon Snow <noreply@fider.io>\nSent: Monday, January 1, 2024\nSubject: New comment",
+			expected: "Agreed",
+		},
+		{
+			input:    "> quoted\nmy answer\n> more quoted\nsecond answer",
+			expected: "my answer\nsecond answer",
+		},
+		{
+			input:    "> only quoted text",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		Expect(inbound.StripReply(testCase.input)).Equals(testCase.expected)
+	}
+}
@@ -0,0 +1,154 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+)
+
+const (
+	maxMessageSize = 10 << 20 // 10MB
+	maxRecipients  = 50
+	sessionTimeout = 5 * time.Minute
+)
+
+// Handler processes a message received by the SMTP listener
+type Handler func(msg *Message)
+
+// ListenAndServe starts a minimal SMTP server that only accepts messages sent to the inbound domain
+// It's meant to sit behind the MTA of self-hosted instances, which should handle TLS and spam filtering
+func ListenAndServe(ctx context.Context, address string, handler Handler) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on '%s'", address)
+	}
+
+	log.Infof(ctx, "Inbound SMTP listener started on @{Address}", dto.Props{
+		"Address": address,
+	})
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return errors.Wrap(err, "failed to accept connection")
+		}
+		go serve(ctx, conn, handler)
+	}
+}
+
+func serve(ctx context.Context, conn net.Conn, handler Handler) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(sessionTimeout))
+
+	text := textproto.NewConn(conn)
+	reply := func(code int, message string) {
+		_ = text.PrintfLine("%d %s", code, message)
+	}
+
+	var recipients []string
+	reply(220, "Fider inbound SMTP ready")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			recipients = nil
+			reply(250, "Hello")
+		case "MAIL":
+			recipients = nil
+			reply(250, "OK")
+		case "RCPT":
+			address, ok := parsePathArg(arg, "TO:")
+			if !ok {
+				reply(501, "Syntax error")
+			} else if !isInboundAddress(address) {
+				reply(550, "Mailbox unavailable")
+			} else if len(recipients) >= maxRecipients {
+				reply(452, "Too many recipients")
+			} else {
+				recipients = append(recipients, address)
+				reply(250, "OK")
+			}
+		case "DATA":
+			if len(recipients) == 0 {
+				reply(503, "Need RCPT command")
+				continue
+			}
+
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			dot := text.DotReader()
+			content, err := io.ReadAll(io.LimitReader(dot, maxMessageSize+1))
+			if err == nil {
+				_, err = io.Copy(io.Discard, dot)
+			}
+			if err != nil {
+				return
+			}
+			if len(content) > maxMessageSize {
+				reply(552, "Message too big")
+				recipients = nil
+				continue
+			}
+
+			msg, err := ParseMessage(bytes.NewReader(content))
+			if err != nil {
+				log.Warnf(ctx, "Failed to parse inbound email: @{Error}", dto.Props{
+					"Error": err.Error(),
+				})
+				reply(554, "Failed to parse message")
+			} else {
+				msg.To = recipients
+				handler(msg)
+				reply(250, "OK")
+			}
+			recipients = nil
+		case "RSET":
+			recipients = nil
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+func parsePathArg(arg, prefix string) (string, bool) {
+	if !strings.HasPrefix(strings.ToUpper(arg), prefix) {
+		return "", false
+	}
+
+	path := strings.TrimSpace(arg[len(prefix):])
+	if i := strings.Index(path, ">"); i >= 0 {
+		path = path[:i+1]
+	}
+
+	address, err := mail.ParseAddress(path)
+	if err != nil {
+		return "", false
+	}
+	return address.Address, true
+}
+
+func isInboundAddress(address string) bool {
+	_, domain, ok := strings.Cut(address, "@")
+	return ok && strings.EqualFold(domain, env.Config.Email.Inbound.Domain)
+}
@@ -49,6 +49,23 @@ func (q *Query) String(selector string) string {
 	return ""
 }
 
+//Strings returns a list of strings from the json object based on its selector, a single string becomes a list with one element
+func (q *Query) Strings(selector string) []string {
+	data := q.get(selector)
+	if data != nil {
+		var arr []string
+		if err := json.Unmarshal(*data, &arr); err == nil {
+			return arr
+		}
+
+		var str string
+		if err := json.Unmarshal(*data, &str); err == nil && str != "" {
+			return []string{str}
+		}
+	}
+	return []string{}
+}
+
 //Int32 returns a integer value from the json object based on its selector
 func (q *Query) Int32(selector string) int {
 	data := q.get(selector)
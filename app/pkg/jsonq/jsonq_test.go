@@ -136,3 +136,14 @@ func TestContainsNested(t *testing.T) {
 	Expect(query.Contains("name")).IsFalse()
 	Expect(query.Contains("failures.what")).IsFalse()
 }
+
+func TestGetStrings(t *testing.T) {
+	RegisterT(t)
+
+	query := jsonq.New(`{ "groups": [ "beta", "enterprise" ], "team": "engineering", "age": 23 }`)
+	Expect(query.Strings("groups")).Equals([]string{"beta", "enterprise"})
+	Expect(query.Strings("team")).Equals([]string{"engineering"})
+	Expect(query.Strings("age")).Equals([]string{})
+	Expect(query.Strings("unknown")).Equals([]string{})
+	Expect(query.Strings("")).Equals([]string{})
+}
@@ -31,6 +31,10 @@ type FiderClaims struct {
 	UserName  string `json:"user/name"`
 	UserEmail string `json:"user/email"`
 	Origin    string `json:"origin"`
+	// TwoFactorPending is set on tokens issued to users that still need to provide their second factor
+	TwoFactorPending bool `json:"user/2fa_pending,omitempty"`
+	// SessionKey identifies the server-side session this token belongs to, which can be revoked
+	SessionKey string `json:"session/key,omitempty"`
 	Metadata
 }
 
@@ -51,6 +55,45 @@ type OAuthStateClaims struct {
 	Metadata
 }
 
+// SAMLStateClaims represents what goes into JWT tokens used as SAML RelayState
+type SAMLStateClaims struct {
+	RequestID string `json:"samlstate/request_id"`
+	Redirect  string `json:"samlstate/redirect"`
+	Metadata
+}
+
+// OAuthAccessTokenClaims represents what goes into access tokens issued to OAuth clients
+type OAuthAccessTokenClaims struct {
+	UserID   int      `json:"oauthtoken/user_id"`
+	ClientID string   `json:"oauthtoken/client_id"`
+	Scopes   []string `json:"oauthtoken/scopes"`
+	Metadata
+}
+
+// IDTokenClaims represents what goes into OpenID Connect ID tokens
+type IDTokenClaims struct {
+	Nonce   string `json:"nonce,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Picture string `json:"picture,omitempty"`
+	Metadata
+}
+
+// ReauthClaims represents what goes into tokens that prove a user recently confirmed their second factor
+type ReauthClaims struct {
+	UserID int `json:"reauth/user_id"`
+	Metadata
+}
+
+// UnsubscribeClaims represents what goes into the unsubscribe links of notification emails
+// The user is unsubscribed from a post, from a notification event or, when neither is set, from all email notifications
+type UnsubscribeClaims struct {
+	UserID int    `json:"unsubscribe/user_id"`
+	PostID int    `json:"unsubscribe/post_id,omitempty"`
+	Event  string `json:"unsubscribe/event,omitempty"`
+	Metadata
+}
+
 // Encode creates new JWT token with given claims
 func Encode(claims jwtgo.Claims) (string, error) {
 	jwtToken := jwtgo.NewWithClaims(jwtgo.GetSigningMethod("HS256"), claims)
@@ -91,6 +134,58 @@ func DecodeOAuthStateClaims(token string) (*OAuthStateClaims, error) {
 	return claims, nil
 }
 
+// DecodeSAMLStateClaims extract SAMLStateClaims from given JWT token
+func DecodeSAMLStateClaims(token string) (*SAMLStateClaims, error) {
+	claims := &SAMLStateClaims{}
+	err := decode(token, claims)
+	if err == nil && claims.RequestID == "" {
+		err = errors.New("token is not a SAML state")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode SAMLState claims")
+	}
+	return claims, nil
+}
+
+// DecodeOAuthAccessTokenClaims extract OAuthAccessTokenClaims from given JWT token
+func DecodeOAuthAccessTokenClaims(token string) (*OAuthAccessTokenClaims, error) {
+	claims := &OAuthAccessTokenClaims{}
+	err := decode(token, claims)
+	if err == nil && (claims.UserID == 0 || claims.ClientID == "") {
+		err = errors.New("token is not an OAuth access token")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode OAuth access token claims")
+	}
+	return claims, nil
+}
+
+// DecodeReauthClaims extract ReauthClaims from given JWT token
+func DecodeReauthClaims(token string) (*ReauthClaims, error) {
+	claims := &ReauthClaims{}
+	err := decode(token, claims)
+	if err == nil && claims.UserID == 0 {
+		err = errors.New("token is not a reauthentication token")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode reauth claims")
+	}
+	return claims, nil
+}
+
+// DecodeUnsubscribeClaims extract UnsubscribeClaims from given JWT token
+func DecodeUnsubscribeClaims(token string) (*UnsubscribeClaims, error) {
+	claims := &UnsubscribeClaims{}
+	err := decode(token, claims)
+	if err == nil && claims.UserID == 0 {
+		err = errors.New("token is not an unsubscribe token")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode unsubscribe claims")
+	}
+	return claims, nil
+}
+
 func decode(token string, claims jwtgo.Claims) error {
 	jwtToken, err := jwtgo.ParseWithClaims(token, claims, func(t *jwtgo.Token) (any, error) {
 		if _, ok := t.Method.(*jwtgo.SigningMethodHMAC); !ok {
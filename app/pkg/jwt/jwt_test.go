@@ -135,3 +135,80 @@ func TestJWT_DecodeOAuthClaimsExpired(t *testing.T) {
 	Expect(err).IsNotNil()
 	Expect(decoded).IsNil()
 }
+
+func TestJWT_DecodeOAuthAccessTokenClaims(t *testing.T) {
+	RegisterT(t)
+
+	claims := &jwt.OAuthAccessTokenClaims{
+		UserID:   424,
+		ClientID: "my-client",
+		Scopes:   []string{"openid", "posts:read"},
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(time.Hour)),
+		},
+	}
+
+	token, _ := jwt.Encode(claims)
+
+	decoded, err := jwt.DecodeOAuthAccessTokenClaims(token)
+	Expect(err).IsNil()
+	Expect(decoded.UserID).Equals(424)
+	Expect(decoded.ClientID).Equals("my-client")
+	Expect(decoded.Scopes).Equals([]string{"openid", "posts:read"})
+}
+
+func TestJWT_DecodeOAuthAccessTokenClaims_FiderClaims(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(&jwt.FiderClaims{
+		UserID:    424,
+		UserName:  "Jon Snow",
+		UserEmail: "jon.snow@got.com",
+	})
+
+	decoded, err := jwt.DecodeOAuthAccessTokenClaims(token)
+	Expect(err).IsNotNil()
+	Expect(decoded).IsNil()
+}
+
+func TestJWT_DecodeSAMLStateClaims(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(&jwt.SAMLStateClaims{
+		RequestID: "id-123",
+		Redirect:  "https://demo.test.fider.io/posts/1",
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(10 * time.Minute)),
+		},
+	})
+
+	decoded, err := jwt.DecodeSAMLStateClaims(token)
+	Expect(err).IsNil()
+	Expect(decoded.RequestID).Equals("id-123")
+	Expect(decoded.Redirect).Equals("https://demo.test.fider.io/posts/1")
+
+	token, _ = jwt.Encode(&jwt.OAuthStateClaims{Redirect: "https://demo.test.fider.io/posts/1"})
+	decoded, err = jwt.DecodeSAMLStateClaims(token)
+	Expect(err).IsNotNil()
+	Expect(decoded).IsNil()
+}
+
+func TestJWT_DecodeUnsubscribeClaims(t *testing.T) {
+	RegisterT(t)
+
+	token, _ := jwt.Encode(&jwt.UnsubscribeClaims{
+		UserID: 424,
+		PostID: 12,
+	})
+
+	decoded, err := jwt.DecodeUnsubscribeClaims(token)
+	Expect(err).IsNil()
+	Expect(decoded.UserID).Equals(424)
+	Expect(decoded.PostID).Equals(12)
+	Expect(decoded.Event).Equals("")
+
+	token, _ = jwt.Encode(&jwt.OAuthStateClaims{Redirect: "https://demo.test.fider.io/posts/1"})
+	decoded, err = jwt.DecodeUnsubscribeClaims(token)
+	Expect(err).IsNotNil()
+	Expect(decoded).IsNil()
+}
@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/getfider/fider/app/pkg/errors"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+// SigningKey is an RSA key that signs tokens verified by third parties, such as ID tokens
+// Its public part is published as a JSON Web Key, so that no secret has to be shared with them
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+// GenerateSigningKey returns a new RSA key encoded as base64 PKCS #8, which is the format expected by ParseSigningKey
+func GenerateSigningKey() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate RSA key")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode RSA key")
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// ParseSigningKey decodes an RSA key encoded as base64 PKCS #8
+func ParseSigningKey(value string) (*SigningKey, error) {
+	der, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signing key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key is not an RSA key")
+	}
+
+	// The key ID only depends on the public key, so it changes whenever the key is rotated
+	public, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+	hash := sha256.Sum256(public)
+
+	return &SigningKey{
+		ID:         base64.RawURLEncoding.EncodeToString(hash[:12]),
+		PrivateKey: rsaKey,
+	}, nil
+}
+
+// JWK returns the public part of the key as a JSON Web Key
+func (k *SigningKey) JWK() map[string]any {
+	return map[string]any{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"kid": k.ID,
+		"n":   base64.RawURLEncoding.EncodeToString(k.PrivateKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.PrivateKey.E)).Bytes()),
+	}
+}
+
+// EncodeWithKey creates new JWT token with given claims signed by given key with RS256
+func EncodeWithKey(claims jwtgo.Claims, key *SigningKey) (string, error) {
+	jwtToken := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+	jwtToken.Header["kid"] = key.ID
+	token, err := jwtToken.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the requested claims")
+	}
+	return token, nil
+}
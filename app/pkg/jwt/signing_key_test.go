@@ -0,0 +1,48 @@
+package jwt_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/jwt"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+func TestSigningKey_EncodeWithKey(t *testing.T) {
+	RegisterT(t)
+
+	value, err := jwt.GenerateSigningKey()
+	Expect(err).IsNil()
+
+	key, err := jwt.ParseSigningKey(value)
+	Expect(err).IsNil()
+	Expect(key.ID).IsNotEmpty()
+
+	again, err := jwt.ParseSigningKey(value)
+	Expect(err).IsNil()
+	Expect(again.ID).Equals(key.ID)
+
+	token, err := jwt.EncodeWithKey(&jwt.IDTokenClaims{Nonce: "abc"}, key)
+	Expect(err).IsNil()
+
+	claims := &jwt.IDTokenClaims{}
+	parsed, err := jwtgo.ParseWithClaims(token, claims, func(token *jwtgo.Token) (any, error) {
+		return &key.PrivateKey.PublicKey, nil
+	})
+	Expect(err).IsNil()
+	Expect(parsed.Method.Alg()).Equals("RS256")
+	Expect(parsed.Header["kid"]).Equals(key.ID)
+	Expect(claims.Nonce).Equals("abc")
+
+	jwk := key.JWK()
+	Expect(jwk["kid"]).Equals(key.ID)
+	Expect(jwk["e"]).Equals("AQAB")
+}
+
+func TestSigningKey_ParseInvalid(t *testing.T) {
+	RegisterT(t)
+
+	key, err := jwt.ParseSigningKey("not-a-key")
+	Expect(err).IsNotNil()
+	Expect(key).IsNil()
+}
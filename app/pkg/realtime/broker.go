@@ -0,0 +1,126 @@
+package realtime
+
+import "sync"
+
+// bufferSize is the number of events kept for a subscriber before new ones are dropped
+const bufferSize = 32
+
+// Broker fans out events to the subscribers of each tenant connected to this instance
+type Broker struct {
+	mu          sync.RWMutex
+	closed      bool
+	subscribers map[int]map[*Subscription]bool
+}
+
+// Subscription receives the events of a tenant until it's closed
+type Subscription struct {
+	broker   *Broker
+	tenantID int
+	events   chan *Event
+}
+
+// NewBroker creates a new Broker
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int]map[*Subscription]bool),
+	}
+}
+
+// Subscribe starts receiving the events of given tenant
+// The subscription is already closed when the broker has been shut down
+func (b *Broker) Subscribe(tenantID int) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &Subscription{
+		broker:   b,
+		tenantID: tenantID,
+		events:   make(chan *Event, bufferSize),
+	}
+
+	if b.closed {
+		close(s.events)
+		return s
+	}
+
+	if b.subscribers[tenantID] == nil {
+		b.subscribers[tenantID] = make(map[*Subscription]bool)
+	}
+	b.subscribers[tenantID][s] = true
+	return s
+}
+
+// Broadcast sends given event to all subscribers of its tenant
+// It never blocks, so subscribers that are too slow to keep up miss some events
+func (b *Broker) Broadcast(e *Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subscribers[e.TenantID] {
+		select {
+		case s.events <- e:
+		default:
+		}
+	}
+}
+
+// Count returns the number of subscribers of given tenant
+func (b *Broker) Count(tenantID int) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[tenantID])
+}
+
+// Close closes all subscriptions and refuses new ones
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for tenantID, subscribers := range b.subscribers {
+		for s := range subscribers {
+			close(s.events)
+		}
+		delete(b.subscribers, tenantID)
+	}
+}
+
+// Events returns the channel of events, which is closed when the subscription ends
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Close stops receiving events
+func (s *Subscription) Close() {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscribers := b.subscribers[s.tenantID]
+	if !subscribers[s] {
+		return
+	}
+
+	close(s.events)
+	delete(subscribers, s)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, s.tenantID)
+	}
+}
+
+var defaultBroker = NewBroker()
+
+// Subscribe starts receiving the events of given tenant from the default broker
+func Subscribe(tenantID int) *Subscription {
+	return defaultBroker.Subscribe(tenantID)
+}
+
+// Broadcast sends given event to the subscribers of the default broker
+func Broadcast(e *Event) {
+	defaultBroker.Broadcast(e)
+}
+
+// Shutdown closes all subscriptions of the default broker, so that open streams end before the web server stops
+func Shutdown() {
+	defaultBroker.Close()
+}
@@ -0,0 +1,62 @@
+package realtime_test
+
+import (
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/realtime"
+)
+
+func TestBroker_Broadcast(t *testing.T) {
+	RegisterT(t)
+
+	broker := realtime.NewBroker()
+	demo1 := broker.Subscribe(1)
+	demo2 := broker.Subscribe(1)
+	avengers := broker.Subscribe(2)
+	Expect(broker.Count(1)).Equals(2)
+
+	e := &realtime.Event{TenantID: 1, Type: realtime.PostVotesChanged}
+	broker.Broadcast(e)
+
+	Expect(<-demo1.Events()).Equals(e)
+	Expect(<-demo2.Events()).Equals(e)
+	Expect(avengers.Events()).HasLen(0)
+}
+
+func TestBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	RegisterT(t)
+
+	broker := realtime.NewBroker()
+	sub := broker.Subscribe(1)
+
+	for i := 0; i < 100; i++ {
+		broker.Broadcast(&realtime.Event{TenantID: 1, Type: realtime.PostVotesChanged})
+	}
+	Expect(len(sub.Events()) < 100).IsTrue()
+}
+
+func TestBroker_Close(t *testing.T) {
+	RegisterT(t)
+
+	broker := realtime.NewBroker()
+	sub1 := broker.Subscribe(1)
+	sub2 := broker.Subscribe(1)
+
+	sub1.Close()
+	sub1.Close()
+	_, ok := <-sub1.Events()
+	Expect(ok).IsFalse()
+	Expect(broker.Count(1)).Equals(1)
+
+	broker.Close()
+	_, ok = <-sub2.Events()
+	Expect(ok).IsFalse()
+	Expect(broker.Count(1)).Equals(0)
+	sub2.Close()
+
+	sub3 := broker.Subscribe(1)
+	_, ok = <-sub3.Events()
+	Expect(ok).IsFalse()
+	broker.Broadcast(&realtime.Event{TenantID: 1, Type: realtime.PostVotesChanged})
+}
@@ -0,0 +1,112 @@
+package realtime
+
+import (
+	"encoding/json"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+// Channel is the Postgres channel used to share events between all instances
+const Channel = "fider_events"
+
+// MaxPayloadSize is the maximum length of an encoded event, as Postgres only accepts notifications shorter than 8000 bytes
+const MaxPayloadSize = 7999
+
+// Types of the events streamed to the browsers
+const (
+	CommentCreated      = "comment.created"
+	PostVotesChanged    = "post.votes"
+	PostStatusChanged   = "post.status"
+	RoadmapPostMoved    = "roadmap.moved"
+	NotificationCreated = "notification.created"
+)
+
+// Event is something that happened on a tenant that connected browsers might want to know about
+type Event struct {
+	TenantID int            `json:"tenantId"`
+	Type     string         `json:"type"`
+	Data     map[string]any `json:"data"`
+
+	// Restricts the event to a single user
+	UserID int `json:"userId,omitempty"`
+	// Restricts the event to staff members
+	StaffOnly bool `json:"staffOnly,omitempty"`
+	// Restricts the event to the users that can see this post
+	Post *PostVisibility `json:"post,omitempty"`
+	// Restricts the event to the users that can see this roadmap column
+	Column *ColumnVisibility `json:"column,omitempty"`
+}
+
+// PostVisibility holds what's needed to know who can see a post, without having to load it again for each connected user
+type PostVisibility struct {
+	AuthorID  int  `json:"authorId"`
+	IsPrivate bool `json:"isPrivate,omitempty"`
+	GroupID   int  `json:"groupId,omitempty"`
+}
+
+// ColumnVisibility holds what's needed to know who can see a roadmap column
+type ColumnVisibility struct {
+	IsVisibleToPublic bool `json:"isVisibleToPublic"`
+	GroupID           int  `json:"groupId,omitempty"`
+}
+
+// VisibilityOf returns the visibility of given post
+func VisibilityOf(post *entity.Post) *PostVisibility {
+	visibility := &PostVisibility{
+		IsPrivate: post.IsPrivate,
+		GroupID:   post.GroupID,
+	}
+	if post.User != nil {
+		visibility.AuthorID = post.User.ID
+	}
+	return visibility
+}
+
+// IsVisibleTo returns true if given user is allowed to receive this event
+func (e *Event) IsVisibleTo(user *entity.User) bool {
+	if e.UserID > 0 && (user == nil || user.ID != e.UserID) {
+		return false
+	}
+	if e.StaffOnly && (user == nil || !user.IsCollaborator()) {
+		return false
+	}
+	if e.Column != nil && (user == nil || !user.IsCollaborator()) {
+		if !e.Column.IsVisibleToPublic {
+			return false
+		}
+		if e.Column.GroupID > 0 && (user == nil || !user.IsMemberOf(e.Column.GroupID)) {
+			return false
+		}
+	}
+	if e.Post != nil {
+		post := &entity.Post{
+			User:      &entity.User{ID: e.Post.AuthorID},
+			IsPrivate: e.Post.IsPrivate,
+			GroupID:   e.Post.GroupID,
+		}
+		return post.IsVisibleTo(user)
+	}
+	return true
+}
+
+// Encode returns the payload used to send this event through Postgres
+func Encode(e *Event) (string, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode event '%s'", e.Type)
+	}
+	if len(payload) > MaxPayloadSize {
+		return "", errors.New("event '%s' has %d bytes, but the limit is %d", e.Type, len(payload), MaxPayloadSize)
+	}
+	return string(payload), nil
+}
+
+// Decode parses a payload created by Encode
+func Decode(payload string) (*Event, error) {
+	e := &Event{}
+	if err := json.Unmarshal([]byte(payload), e); err != nil {
+		return nil, errors.Wrap(err, "failed to decode event")
+	}
+	return e, nil
+}
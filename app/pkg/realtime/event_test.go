@@ -0,0 +1,87 @@
+package realtime_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/realtime"
+)
+
+var (
+	staff   = &entity.User{ID: 1, Role: enum.RoleCollaborator}
+	author  = &entity.User{ID: 2, Role: enum.RoleVisitor}
+	member  = &entity.User{ID: 3, Role: enum.RoleVisitor, GroupIDs: []int{7}}
+	visitor = &entity.User{ID: 4, Role: enum.RoleVisitor}
+)
+
+func TestEvent_IsVisibleTo(t *testing.T) {
+	RegisterT(t)
+
+	public := &realtime.Event{Type: realtime.PostVotesChanged, Post: &realtime.PostVisibility{AuthorID: author.ID}}
+	Expect(public.IsVisibleTo(nil)).IsTrue()
+	Expect(public.IsVisibleTo(visitor)).IsTrue()
+
+	private := &realtime.Event{Type: realtime.PostVotesChanged, Post: &realtime.PostVisibility{AuthorID: author.ID, IsPrivate: true}}
+	Expect(private.IsVisibleTo(nil)).IsFalse()
+	Expect(private.IsVisibleTo(visitor)).IsFalse()
+	Expect(private.IsVisibleTo(author)).IsTrue()
+	Expect(private.IsVisibleTo(staff)).IsTrue()
+
+	restricted := &realtime.Event{Type: realtime.PostStatusChanged, Post: &realtime.PostVisibility{AuthorID: author.ID, GroupID: 7}}
+	Expect(restricted.IsVisibleTo(nil)).IsFalse()
+	Expect(restricted.IsVisibleTo(visitor)).IsFalse()
+	Expect(restricted.IsVisibleTo(member)).IsTrue()
+
+	internal := &realtime.Event{Type: realtime.CommentCreated, StaffOnly: true, Post: &realtime.PostVisibility{AuthorID: author.ID}}
+	Expect(internal.IsVisibleTo(author)).IsFalse()
+	Expect(internal.IsVisibleTo(staff)).IsTrue()
+
+	hiddenColumn := &realtime.Event{Type: realtime.RoadmapPostMoved, Post: &realtime.PostVisibility{AuthorID: author.ID}, Column: &realtime.ColumnVisibility{}}
+	Expect(hiddenColumn.IsVisibleTo(nil)).IsFalse()
+	Expect(hiddenColumn.IsVisibleTo(author)).IsFalse()
+	Expect(hiddenColumn.IsVisibleTo(staff)).IsTrue()
+
+	restrictedColumn := &realtime.Event{Type: realtime.RoadmapPostMoved, Post: &realtime.PostVisibility{AuthorID: author.ID}, Column: &realtime.ColumnVisibility{IsVisibleToPublic: true, GroupID: 7}}
+	Expect(restrictedColumn.IsVisibleTo(nil)).IsFalse()
+	Expect(restrictedColumn.IsVisibleTo(visitor)).IsFalse()
+	Expect(restrictedColumn.IsVisibleTo(member)).IsTrue()
+	Expect(restrictedColumn.IsVisibleTo(staff)).IsTrue()
+
+	publicColumn := &realtime.Event{Type: realtime.RoadmapPostMoved, Post: &realtime.PostVisibility{AuthorID: author.ID}, Column: &realtime.ColumnVisibility{IsVisibleToPublic: true}}
+	Expect(publicColumn.IsVisibleTo(nil)).IsTrue()
+
+	personal := &realtime.Event{Type: realtime.NotificationCreated, UserID: visitor.ID}
+	Expect(personal.IsVisibleTo(nil)).IsFalse()
+	Expect(personal.IsVisibleTo(staff)).IsFalse()
+	Expect(personal.IsVisibleTo(visitor)).IsTrue()
+}
+
+func TestEncodeDecode(t *testing.T) {
+	RegisterT(t)
+
+	payload, err := realtime.Encode(&realtime.Event{
+		TenantID: 1,
+		Type:     realtime.PostVotesChanged,
+		Data:     map[string]any{"postNumber": 5, "votesCount": 10},
+		Post:     &realtime.PostVisibility{AuthorID: 2, IsPrivate: true},
+	})
+	Expect(err).IsNil()
+
+	e, err := realtime.Decode(payload)
+	Expect(err).IsNil()
+	Expect(e.TenantID).Equals(1)
+	Expect(e.Type).Equals(realtime.PostVotesChanged)
+	Expect(e.Data["postNumber"]).Equals(float64(5))
+	Expect(e.Post.AuthorID).Equals(2)
+	Expect(e.Post.IsPrivate).IsTrue()
+
+	_, err = realtime.Encode(&realtime.Event{
+		TenantID: 1,
+		Type:     realtime.CommentCreated,
+		Data:     map[string]any{"content": strings.Repeat("a", realtime.MaxPayloadSize)},
+	})
+	Expect(err).IsNotNil()
+}
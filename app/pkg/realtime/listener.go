@@ -0,0 +1,55 @@
+package realtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/lib/pq"
+)
+
+const pingInterval = 90 * time.Second
+
+// Listen receives the events notified by all instances through Postgres and broadcasts them to the default broker
+// It blocks until given context is done, reconnecting to the database whenever the connection is lost
+func Listen(ctx context.Context) error {
+	listener := pq.NewListener(env.Config.Database.URL, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error(ctx, errors.Wrap(err, "realtime listener lost its database connection"))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return errors.Wrap(err, "failed to listen on '%s'", Channel)
+	}
+
+	log.Infof(ctx, "Realtime listener started on channel @{Channel}", dto.Props{
+		"Channel": Channel,
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// A nil notification is sent after reconnecting, events sent in between are lost
+			if n == nil {
+				continue
+			}
+			e, err := Decode(n.Extra)
+			if err != nil {
+				log.Error(ctx, err)
+				continue
+			}
+			Broadcast(e)
+		case <-time.After(pingInterval):
+			go func() {
+				_ = listener.Ping()
+			}()
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func badRequest(scimType, format string, a ...any) *Error {
+	return NewError(http.StatusBadRequest, scimType, fmt.Sprintf(format, a...))
+}
+
+// Filter is a single attribute comparison, the only kind of filter supported
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+var filterRegex = regexp.MustCompile(`^\s*([\w.:$-]+)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*"|true|false|[0-9]+)\s*$`)
+
+// ParseFilter parses expressions like `userName eq "jon.snow@got.com"`
+func ParseFilter(filter string) (*Filter, error) {
+	matches := filterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, badRequest(ErrorInvalidFilter, "Only filters like 'attribute eq \"value\"' are supported")
+	}
+
+	value := matches[2]
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal([]byte(value), &value); err != nil {
+			return nil, badRequest(ErrorInvalidFilter, "Filter value %s is invalid", matches[2])
+		}
+	}
+
+	return &Filter{Attribute: stripSchema(matches[1]), Value: value}, nil
+}
+
+// Path is the target of a PATCH operation, e.g. `emails[type eq "work"].value`
+type Path struct {
+	Attribute    string
+	Filter       *Filter
+	SubAttribute string
+}
+
+// ParsePath parses the path of a PATCH operation
+func ParsePath(path string) (*Path, error) {
+	path = stripSchema(strings.TrimSpace(path))
+	open := strings.Index(path, "[")
+	if open < 0 {
+		return &Path{Attribute: path}, nil
+	}
+
+	end := strings.LastIndex(path, "]")
+	if end < open {
+		return nil, badRequest(ErrorInvalidPath, "Path '%s' is invalid", path)
+	}
+
+	filter, err := ParseFilter(path[open+1 : end])
+	if err != nil {
+		return nil, badRequest(ErrorInvalidPath, "Path '%s' is invalid", path)
+	}
+
+	return &Path{
+		Attribute:    path[:open],
+		Filter:       filter,
+		SubAttribute: strings.TrimPrefix(path[end+1:], "."),
+	}, nil
+}
+
+func stripSchema(attribute string) string {
+	for _, schema := range []string{UserSchema, GroupSchema} {
+		if len(attribute) > len(schema) && strings.EqualFold(attribute[:len(schema)+1], schema+":") {
+			return attribute[len(schema)+1:]
+		}
+	}
+	return attribute
+}
+
+// ApplyUserPatch applies a PATCH operation to given user. Attributes that aren't part of the User resource are ignored.
+func ApplyUserPatch(u *User, op PatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		if op.Path == "" {
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return badRequest(ErrorInvalidValue, "Operation without a path must have an object value")
+			}
+			for attribute, value := range values {
+				path, err := ParsePath(attribute)
+				if err != nil {
+					return err
+				}
+				if err := setUserAttribute(u, path, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		path, err := ParsePath(op.Path)
+		if err != nil {
+			return err
+		}
+		return setUserAttribute(u, path, op.Value)
+	case "remove":
+		if op.Path == "" {
+			return badRequest(ErrorNoTarget, "Remove operation must have a path")
+		}
+
+		path, err := ParsePath(op.Path)
+		if err != nil {
+			return err
+		}
+		return setUserAttribute(u, path, nil)
+	}
+	return badRequest(ErrorInvalidSyntax, "Operation '%s' is not supported", op.Op)
+}
+
+func setUserAttribute(u *User, path *Path, value json.RawMessage) error {
+	attribute := strings.ToLower(path.Attribute)
+	switch attribute {
+	case "active":
+		if value == nil {
+			return nil
+		}
+		active, err := decodeBool(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+		return nil
+	case "username":
+		return decodeString(value, &u.UserName)
+	case "displayname":
+		return decodeString(value, &u.DisplayName)
+	case "externalid":
+		return decodeString(value, &u.ExternalID)
+	case "name":
+		u.Name = &Name{}
+		if value == nil {
+			return nil
+		}
+		if err := json.Unmarshal(value, u.Name); err != nil {
+			return badRequest(ErrorInvalidValue, "Value of 'name' must be an object")
+		}
+		return nil
+	case "name.formatted", "name.givenname", "name.familyname":
+		if u.Name == nil {
+			u.Name = &Name{}
+		}
+		switch attribute {
+		case "name.formatted":
+			return decodeString(value, &u.Name.Formatted)
+		case "name.givenname":
+			return decodeString(value, &u.Name.GivenName)
+		default:
+			return decodeString(value, &u.Name.FamilyName)
+		}
+	case "emails":
+		if path.Filter != nil || strings.EqualFold(path.SubAttribute, "value") {
+			var email string
+			if err := decodeString(value, &email); err != nil {
+				return err
+			}
+			u.Emails = []MultiValue{{Value: email, Primary: true}}
+			return nil
+		}
+
+		u.Emails = nil
+		if value == nil {
+			return nil
+		}
+		if err := json.Unmarshal(value, &u.Emails); err != nil {
+			return badRequest(ErrorInvalidValue, "Value of 'emails' must be a list")
+		}
+		return nil
+	}
+	return nil
+}
+
+func decodeString(value json.RawMessage, target *string) error {
+	if value == nil {
+		*target = ""
+		return nil
+	}
+	if err := json.Unmarshal(value, target); err != nil {
+		return badRequest(ErrorInvalidValue, "Value %s must be a string", value)
+	}
+	return nil
+}
+
+// decodeBool accepts both booleans and strings, as some identity providers send "False"
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, badRequest(ErrorInvalidValue, "Value %s must be a boolean", value)
+}
@@ -0,0 +1,217 @@
+// Package scim implements the resources and messages of the SCIM 2.0 protocol (RFC 7643 and RFC 7644)
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+// ContentType is the media type of every SCIM request and response
+const ContentType = "application/scim+json; charset=utf-8"
+
+// Schemas used by the resources and messages of SCIM
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ResourceTypeSchema          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Detail error types of a 400 Bad Request error
+const (
+	ErrorInvalidFilter = "invalidFilter"
+	ErrorInvalidSyntax = "invalidSyntax"
+	ErrorInvalidPath   = "invalidPath"
+	ErrorInvalidValue  = "invalidValue"
+	ErrorUniqueness    = "uniqueness"
+	ErrorMutability    = "mutability"
+	ErrorNoTarget      = "noTarget"
+)
+
+// Meta is the metadata of a resource
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Name is the components of a user's name
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValue is an item of a multi-valued attribute such as emails, groups or members
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is the SCIM representation of a user
+type User struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *Name        `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []MultiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Groups      []MultiValue `json:"groups,omitempty"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user, falling back to the first email and then to userName
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary && email.Value != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+	for _, email := range u.Emails {
+		if email.Value != "" {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+	if strings.Contains(u.UserName, "@") {
+		return strings.TrimSpace(u.UserName)
+	}
+	return ""
+}
+
+// FullName returns the name that best describes the user
+func (u *User) FullName() string {
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+	if u.Name != nil {
+		if name := strings.TrimSpace(u.Name.Formatted); name != "" {
+			return name
+		}
+		return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+	}
+	return ""
+}
+
+// Group is the SCIM representation of a group
+type Group struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []MultiValue `json:"members"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// ListResponse is the response of a query on a resource type
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// NewListResponse returns the page of resources starting at 1-based startIndex with up to count items
+func NewListResponse[T any](resources []T, startIndex, count int) *ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+
+	page := make([]any, 0)
+	for i := startIndex - 1; i < len(resources) && len(page) < count; i++ {
+		page = append(page, resources[i])
+	}
+
+	return &ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// PatchRequest is the body of a PATCH request
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single change of a PATCH request
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is the body of an error response
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError returns an error response with given HTTP status
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{ErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// Error describes why a request couldn't be applied, so it can be returned as a regular error
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// Respond writes given resource or message as a SCIM response
+func Respond(c *web.Context, status int, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SCIM response")
+	}
+	return c.Blob(status, ContentType, b)
+}
+
+// RespondError writes an error response with given HTTP status
+func RespondError(c *web.Context, status int, scimType, detail string) error {
+	return Respond(c, status, NewError(status, scimType, detail))
+}
+
+// NotFound writes an error response for a resource that doesn't exist
+func NotFound(c *web.Context) error {
+	return RespondError(c, http.StatusNotFound, "", "Resource not found")
+}
+
+// Decode parses the body of a SCIM request into v
+func Decode(c *web.Context, v any) error {
+	if err := json.Unmarshal([]byte(c.Request.Body), v); err != nil {
+		return errors.Wrap(err, "failed to decode SCIM request")
+	}
+	return nil
+}
+
+// DecodeValue parses the value of a PATCH operation into v
+func DecodeValue(value json.RawMessage, v any) error {
+	if err := json.Unmarshal(value, v); err != nil {
+		return NewError(http.StatusBadRequest, ErrorInvalidValue, "Operation value is invalid")
+	}
+	return nil
+}
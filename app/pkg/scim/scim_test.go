@@ -0,0 +1,103 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	RegisterT(t)
+
+	filter, err := scim.ParseFilter(`userName eq "jon.snow@got.com"`)
+	Expect(err).IsNil()
+	Expect(filter.Attribute).Equals("userName")
+	Expect(filter.Value).Equals("jon.snow@got.com")
+
+	filter, err = scim.ParseFilter(`urn:ietf:params:scim:schemas:core:2.0:User:externalId EQ "00u\"1"`)
+	Expect(err).IsNil()
+	Expect(filter.Attribute).Equals("externalId")
+	Expect(filter.Value).Equals(`00u"1`)
+
+	for _, invalid := range []string{`userName co "jon"`, `userName eq "a" and active eq true`, `userName eq jon`, ``} {
+		filter, err = scim.ParseFilter(invalid)
+		Expect(filter).IsNil()
+		Expect(err.(*scim.Error).ScimType).Equals(scim.ErrorInvalidFilter)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	RegisterT(t)
+
+	path, err := scim.ParsePath("name.givenName")
+	Expect(err).IsNil()
+	Expect(path.Attribute).Equals("name.givenName")
+	Expect(path.Filter).IsNil()
+
+	path, err = scim.ParsePath(`emails[type eq "work"].value`)
+	Expect(err).IsNil()
+	Expect(path.Attribute).Equals("emails")
+	Expect(path.Filter.Attribute).Equals("type")
+	Expect(path.Filter.Value).Equals("work")
+	Expect(path.SubAttribute).Equals("value")
+
+	path, err = scim.ParsePath(`members[value eq "12"`)
+	Expect(path).IsNil()
+	Expect(err.(*scim.Error).ScimType).Equals(scim.ErrorInvalidPath)
+}
+
+func TestApplyUserPatch(t *testing.T) {
+	RegisterT(t)
+
+	user := &scim.User{UserName: "jon.snow@got.com", DisplayName: "Jon Snow"}
+	ops := []scim.PatchOperation{
+		{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		{Op: "add", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"aegon@got.com"`)},
+		{Op: "replace", Value: json.RawMessage(`{"displayName": "Aegon Targaryen", "externalId": "00u1", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department": "Night's Watch"}`)},
+	}
+	for _, op := range ops {
+		Expect(scim.ApplyUserPatch(user, op)).IsNil()
+	}
+
+	Expect(*user.Active).IsFalse()
+	Expect(user.PrimaryEmail()).Equals("aegon@got.com")
+	Expect(user.FullName()).Equals("Aegon Targaryen")
+	Expect(user.ExternalID).Equals("00u1")
+
+	err := scim.ApplyUserPatch(user, scim.PatchOperation{Op: "remove", Path: "displayName"})
+	Expect(err).IsNil()
+	Expect(user.DisplayName).Equals("")
+
+	err = scim.ApplyUserPatch(user, scim.PatchOperation{Op: "move", Path: "displayName"})
+	Expect(err.(*scim.Error).ScimType).Equals(scim.ErrorInvalidSyntax)
+
+	err = scim.ApplyUserPatch(user, scim.PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)})
+	Expect(err.(*scim.Error).ScimType).Equals(scim.ErrorInvalidValue)
+}
+
+func TestUser_FullName(t *testing.T) {
+	RegisterT(t)
+
+	Expect((&scim.User{Name: &scim.Name{GivenName: "Jon", FamilyName: "Snow"}}).FullName()).Equals("Jon Snow")
+	Expect((&scim.User{Name: &scim.Name{Formatted: "Lord Snow", GivenName: "Jon"}}).FullName()).Equals("Lord Snow")
+	Expect((&scim.User{UserName: "jon"}).FullName()).Equals("")
+	Expect((&scim.User{UserName: "jon@got.com"}).PrimaryEmail()).Equals("jon@got.com")
+	Expect((&scim.User{UserName: "jon"}).PrimaryEmail()).Equals("")
+}
+
+func TestNewListResponse(t *testing.T) {
+	RegisterT(t)
+
+	list := scim.NewListResponse([]string{"a", "b", "c"}, 2, 5)
+	Expect(list.TotalResults).Equals(3)
+	Expect(list.StartIndex).Equals(2)
+	Expect(list.ItemsPerPage).Equals(2)
+	Expect(list.Resources).Equals([]any{"b", "c"})
+
+	list = scim.NewListResponse([]string{"a", "b", "c"}, 0, 0)
+	Expect(list.StartIndex).Equals(1)
+	Expect(list.ItemsPerPage).Equals(0)
+	Expect(list.Resources).Equals([]any{})
+}
@@ -0,0 +1,77 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/totp"
+)
+
+// RFC 6238 test secret, "12345678901234567890" in base32
+var secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFCVectors(t *testing.T) {
+	RegisterT(t)
+
+	for unix, expected := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		code, err := totp.Code(secret, totp.Step(time.Unix(unix, 0)))
+		Expect(err).IsNil()
+		Expect(code).Equals(expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	RegisterT(t)
+
+	now := time.Unix(1234567890, 0)
+
+	step, ok := totp.Validate(secret, "005924", now)
+	Expect(ok).IsTrue()
+	Expect(step).Equals(totp.Step(now))
+
+	// codes from the previous and next steps are accepted to allow for clock drift
+	previous, _ := totp.Code(secret, totp.Step(now)-1)
+	step, ok = totp.Validate(secret, previous, now)
+	Expect(ok).IsTrue()
+	Expect(step).Equals(totp.Step(now) - 1)
+
+	older, _ := totp.Code(secret, totp.Step(now)-2)
+	_, ok = totp.Validate(secret, older, now)
+	Expect(ok).IsFalse()
+
+	_, ok = totp.Validate(secret, "005 924", now)
+	Expect(ok).IsTrue()
+
+	_, ok = totp.Validate(secret, "", now)
+	Expect(ok).IsFalse()
+
+	_, ok = totp.Validate("not base32!", "005924", now)
+	Expect(ok).IsFalse()
+}
+
+func TestGenerateSecret(t *testing.T) {
+	RegisterT(t)
+
+	secret := totp.GenerateSecret()
+	Expect(secret).HasLen(32)
+	Expect(secret).NotEquals(totp.GenerateSecret())
+
+	_, err := totp.Code(secret, 1)
+	Expect(err).IsNil()
+}
+
+func TestURL(t *testing.T) {
+	RegisterT(t)
+
+	url := totp.URL("Demo Tenant", "jon.snow@got.com", "JBSWY3DPEHPK3PXP")
+	Expect(url).Equals("otpauth://totp/Demo%20Tenant:jon.snow@got.com?digits=6&issuer=Demo+Tenant&period=30&secret=JBSWY3DPEHPK3PXP")
+}
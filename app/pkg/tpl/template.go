@@ -40,3 +40,18 @@ func Render(ctx context.Context, tmpl *template.Template, w io.Writer, data any)
 	}
 	return nil
 }
+
+// Override returns a copy of given template with some of its blocks redefined
+func Override(tmpl *template.Template, blocks map[string]string) (*template.Template, error) {
+	overridden, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, text := range blocks {
+		if _, err := overridden.New(name).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+	return overridden, nil
+}
@@ -27,3 +27,29 @@ func TestGetTemplate_Render(t *testing.T) {
 </body>
 </html>`)
 }
+
+func TestOverride_Render(t *testing.T) {
+	RegisterT(t)
+
+	tmpl := tpl.GetTemplate("app/pkg/tpl/testdata/base.html", "app/pkg/tpl/testdata/echo.html")
+	overridden, err := tpl.Override(tmpl, map[string]string{
+		"body": "Goodbye, {{ .name | upper }}!",
+	})
+	Expect(err).IsNil()
+
+	bf := new(bytes.Buffer)
+	err = tpl.Render(context.Background(), overridden, bf, dto.Props{
+		"name": "John",
+	})
+
+	Expect(err).IsNil()
+	Expect(bf.String()).Equals(`<html>
+  <head>This goes on the head.</head>
+  <body>Goodbye, JOHN!</body>
+</html>`)
+
+	_, err = tpl.Override(tmpl, map[string]string{
+		"body": "Goodbye, {{ .name ",
+	})
+	Expect(err).IsNotNil()
+}
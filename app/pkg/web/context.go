@@ -63,16 +63,20 @@ const CookieAuthName = "auth"
 // CookieSignUpAuthName is the name of the cookie that holds the temporary Authentication Token
 const CookieSignUpAuthName = "__signup_auth"
 
+// CookieReauthName is the name of the cookie that holds the proof of a recent two-factor authentication
+const CookieReauthName = "__fider_reauth"
+
 // Context shared between http pipeline
 type Context struct {
 	context.Context
-	Response  Response
-	Request   Request
-	id        string
-	sessionID string
-	engine    *Engine
-	params    StringMap
-	tasks     []worker.Task
+	Response      Response
+	Request       Request
+	id            string
+	sessionID     string
+	userSessionID int
+	engine        *Engine
+	params        StringMap
+	tasks         []worker.Task
 }
 
 // NewContext creates a new web Context
@@ -115,6 +119,16 @@ func (c *Context) SetSessionID(id string) {
 	c.Context = log.WithProperty(c.Context, log.PropertyKeySessionID, id)
 }
 
+// UserSessionID returns the ID of the server-side session of current user
+func (c *Context) UserSessionID() int {
+	return c.userSessionID
+}
+
+// SetUserSessionID sets the server-side session ID of current user on current context
+func (c *Context) SetUserSessionID(id int) {
+	c.userSessionID = id
+}
+
 // ContextID returns the unique id for this context
 func (c *Context) ContextID() string {
 	return c.id
@@ -566,6 +580,30 @@ func TenantBaseURL(ctx context.Context, tenant *entity.Tenant) string {
 	return address
 }
 
+// WithTenantRequest returns a context that behaves as a request to given tenant's site
+// This is required to build absolute URLs when running outside of an HTTP request, like jobs
+func WithTenantRequest(ctx context.Context, tenant *entity.Tenant) (context.Context, error) {
+	baseURL := env.Config.BaseURL
+	if !env.IsSingleHostMode() {
+		host := tenant.Subdomain + env.MultiTenantDomain()
+		if tenant.CNAME != "" {
+			host = tenant.CNAME
+		}
+		baseURL = "https://" + host
+	}
+
+	req, err := http.NewRequest("GET", baseURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request for tenant '%s'", tenant.Subdomain)
+	}
+	req.Header.Set("X-Forwarded-Proto", req.URL.Scheme)
+
+	ctx = context.WithValue(ctx, app.RequestCtxKey, WrapRequest(req))
+	ctx = context.WithValue(ctx, app.TenantCtxKey, tenant)
+	ctx = context.WithValue(ctx, app.LocaleCtxKey, tenant.Locale)
+	return ctx, nil
+}
+
 // AssetsURL return the full URL to a tenant-specific static asset
 // It should always return an absolute URL
 func AssetsURL(ctx context.Context, path string, a ...any) string {
@@ -609,6 +647,15 @@ func BaseURL(ctx context.Context) string {
 	return ""
 }
 
+// ClientIP returns the IP address of the client that made the request on given context
+func ClientIP(ctx context.Context) string {
+	request, ok := ctx.Value(app.RequestCtxKey).(Request)
+	if ok {
+		return request.ClientIP()
+	}
+	return ""
+}
+
 // OAuthBaseURL returns the OAuth base URL used for host-wide OAuth authentication
 // For Single Tenant HostMode, BaseURL is the current BaseURL
 // For Multi Tenant HostMode, BaseURL is //login.{HOST_DOMAIN}
@@ -217,6 +217,7 @@ func (r *Renderer) Render(w io.Writer, statusCode int, props Props, ctx *Context
 		"oauth":               oauthProviders.Result,
 		"postWithTags":        env.Config.PostCreationWithTagsEnabled,
 		"allowAllowedSchemes": env.Config.AllowAllowedSchemes,
+		"vapidPublicKey":      env.Config.WebPush.VAPIDPublicKey,
 	}
 
 	if ctx.IsAuthenticated() {
@@ -232,6 +233,7 @@ func (r *Renderer) Render(w io.Writer, statusCode int, props Props, ctx *Context
 			"avatarBlobKey":   u.AvatarBlobKey,
 			"isAdministrator": u.IsAdministrator(),
 			"isCollaborator":  u.IsCollaborator(),
+			"permissions":     u.Permissions(),
 		}
 	}
 
@@ -2,6 +2,7 @@ package web
 
 import (
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -84,9 +85,26 @@ func (r *Request) AddCookie(cookie *http.Cookie) {
 	r.instance.AddCookie(cookie)
 }
 
+// ClientIP returns the IP address of the client, which might be behind a proxy
+func (r *Request) ClientIP() string {
+	if forwardedFor := r.instance.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		return strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.instance.RemoteAddr)
+	if err != nil {
+		return r.instance.RemoteAddr
+	}
+	return host
+}
+
+// BasicAuth returns the username and password provided in the request's Authorization header
+func (r *Request) BasicAuth() (string, string, bool) {
+	return r.instance.BasicAuth()
+}
+
 // IsAPI returns true if its a request for an API resource
 func (r *Request) IsAPI() bool {
-	return strings.HasPrefix(r.URL.Path, "/api/")
+	return strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, "/scim/")
 }
 
 var crawlerRegex = regexp.MustCompile("(?i)(baidu)|(msnbot)|(bingbot)|(bingpreview)|(duckduckbot)|(googlebot)|(adsbot-google)|(mediapartners-google)|(slurp)|(yandexbot)|(yandexmetrika)|(ahrefsbot)|(twitterbot)|(slackbot)|(discordbot)|(semrushBot)|(exabot)")
@@ -97,6 +97,19 @@ func TestIsCustomDomain(t *testing.T) {
 	Expect(req.IsAPI()).IsTrue()
 }
 
+func TestRequest_IsAPI_SCIM(t *testing.T) {
+	RegisterT(t)
+
+	req := web.WrapRequest(
+		&http.Request{
+			Host:       "demo.test.fider.io",
+			RequestURI: "/scim/v2/Users",
+		},
+	)
+
+	Expect(req.IsAPI()).IsTrue()
+}
+
 func TestRequest_FullURL(t *testing.T) {
 	RegisterT(t)
 
@@ -4,21 +4,42 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
 	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/rand"
 	"github.com/getfider/fider/app/pkg/web"
 )
 
-func encode(user *entity.User) string {
-	token, err := jwt.Encode(jwt.FiderClaims{
+func encode(ctx *web.Context, user *entity.User) string {
+	key := rand.String(64)
+	createSession := &cmd.CreateUserSession{
 		UserID:    user.ID,
-		UserName:  user.Name,
-		UserEmail: user.Email,
-		Origin:    jwt.FiderClaimsOriginUI,
+		Key:       key,
+		UserAgent: ctx.Request.GetHeader("User-Agent"),
+		IPAddress: ctx.Request.ClientIP(),
+	}
+	if err := bus.Dispatch(ctx, createSession); err != nil {
+		panic(errors.Wrap(err, "failed to create user session"))
+	}
+	ctx.SetUserSessionID(createSession.Result.ID)
+
+	return encodeClaims(user, false, key, time.Now().Add(365*24*time.Hour))
+}
+
+func encodeClaims(user *entity.User, twoFactorPending bool, sessionKey string, expiresAt time.Time) string {
+	token, err := jwt.Encode(jwt.FiderClaims{
+		UserID:           user.ID,
+		UserName:         user.Name,
+		UserEmail:        user.Email,
+		Origin:           jwt.FiderClaimsOriginUI,
+		TwoFactorPending: twoFactorPending,
+		SessionKey:       sessionKey,
 		Metadata: jwt.Metadata{
-			ExpiresAt: jwt.Time(time.Now().Add(365 * 24 * time.Hour)),
+			ExpiresAt: jwt.Time(expiresAt),
 		},
 	})
 
@@ -30,8 +51,47 @@ func encode(user *entity.User) string {
 }
 
 //AddAuthUserCookie generates Auth Token and adds a cookie
+//Users with two-factor enabled get a short-lived token that is only upgraded once the second factor is verified
 func AddAuthUserCookie(ctx *web.Context, user *entity.User) {
-	AddAuthTokenCookie(ctx, encode(user))
+	if user.HasTwoFactor {
+		expiresAt := time.Now().Add(10 * time.Minute)
+		ctx.AddCookie(web.CookieAuthName, encodeClaims(user, true, "", expiresAt), expiresAt)
+		return
+	}
+	AddAuthTokenCookie(ctx, encode(ctx, user))
+}
+
+//CompleteTwoFactorSignIn replaces a pending Auth Token with a regular one and marks the user as recently reauthenticated
+func CompleteTwoFactorSignIn(ctx *web.Context, user *entity.User) {
+	if !ctx.IsAuthenticated() {
+		AddAuthTokenCookie(ctx, encode(ctx, user))
+	}
+	AddReauthCookie(ctx, user)
+}
+
+//AddReauthCookie adds a short-lived cookie that proves the user has just confirmed their second factor
+func AddReauthCookie(ctx *web.Context, user *entity.User) {
+	expiresAt := time.Now().Add(10 * time.Minute)
+	token, err := jwt.Encode(jwt.ReauthClaims{
+		UserID: user.ID,
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(expiresAt),
+		},
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to add reauth cookie"))
+	}
+	ctx.AddCookie(web.CookieReauthName, token, expiresAt)
+}
+
+//HasRecentReauth returns true if the request carries a valid reauth cookie for given user
+func HasRecentReauth(ctx *web.Context, user *entity.User) bool {
+	cookie, err := ctx.Request.Cookie(web.CookieReauthName)
+	if err != nil {
+		return false
+	}
+	claims, err := jwt.DecodeReauthClaims(cookie.Value)
+	return err == nil && claims.UserID == user.ID
 }
 
 //AddAuthTokenCookie adds given token to a cookie
@@ -45,7 +105,7 @@ func SetSignUpAuthCookie(ctx *web.Context, user *entity.User) {
 	http.SetCookie(&ctx.Response, &http.Cookie{
 		Name:     web.CookieSignUpAuthName,
 		Domain:   env.MultiTenantDomain(),
-		Value:    encode(user),
+		Value:    encode(ctx, user),
 		HttpOnly: true,
 		Path:     "/",
 		Expires:  time.Now().Add(5 * time.Minute),
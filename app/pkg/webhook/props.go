@@ -1,6 +1,8 @@
 package webhook
 
 import (
+	"strings"
+
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 )
@@ -52,9 +54,11 @@ func (p Props) SetPost(post *entity.Post, keyPrefix, baseURL string, includeAllF
 		if includeAllFields {
 			postResponse := post.Response
 			p[keyPrefix+"_votes"] = post.VotesCount
+			p[keyPrefix+"_votes_weight"] = post.VotesWeight
 			p[keyPrefix+"_comments"] = post.CommentsCount
 			p[keyPrefix+"_status"] = post.Status.Name()
 			p[keyPrefix+"_tags"] = post.Tags
+			p[keyPrefix+"_custom_fields"] = post.CustomFields
 			p[keyPrefix+"_response"] = postResponse != nil
 
 			if postResponse != nil {
@@ -77,3 +81,29 @@ func (p Props) SetPost(post *entity.Post, keyPrefix, baseURL string, includeAllF
 	}
 	return p
 }
+
+// WithPublicCustomFields returns a copy of the props where custom fields are limited to the public ones of given list
+// Posts only include the custom fields their loader could see, so this keeps the props the same no matter who that was
+func (p Props) WithPublicCustomFields(fields []*entity.CustomField) Props {
+	public := make(map[string]bool)
+	for _, field := range fields {
+		if field.IsPublic {
+			public[field.Key] = true
+		}
+	}
+
+	result := make(Props, len(p))
+	for key, value := range p {
+		if values, ok := value.(map[string]any); ok && strings.HasSuffix(key, "_custom_fields") {
+			publicValues := make(map[string]any)
+			for fieldKey, fieldValue := range values {
+				if public[fieldKey] {
+					publicValues[fieldKey] = fieldValue
+				}
+			}
+			value = publicValues
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package webhook_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/entity"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/webhook"
+)
+
+func TestProps_WithPublicCustomFields(t *testing.T) {
+	RegisterT(t)
+
+	props := webhook.Props{
+		"post_title":         "Add a dark mode",
+		"post_custom_fields": map[string]any{"platform": "iOS", "customer-tier": "enterprise"},
+	}
+	fields := []*entity.CustomField{
+		{Key: "platform", IsPublic: true},
+		{Key: "customer-tier", IsPublic: false},
+	}
+
+	public := props.WithPublicCustomFields(fields)
+	Expect(public["post_title"]).Equals("Add a dark mode")
+	Expect(public["post_custom_fields"]).Equals(map[string]any{"platform": "iOS"})
+	Expect(props["post_custom_fields"]).Equals(map[string]any{"platform": "iOS", "customer-tier": "enterprise"})
+
+	public = props.WithPublicCustomFields(nil)
+	Expect(public["post_custom_fields"]).Equals(map[string]any{})
+}
@@ -0,0 +1,197 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/hkdf"
+)
+
+// recordSize is the size of the single record used to encrypt a message
+const recordSize = 4096
+
+// MaxPayloadSize is the maximum length of a message, as push services only accept encrypted messages of up to 4096 bytes
+const MaxPayloadSize = recordSize - 16 - 1 - 86
+
+// Keys are the keys given by the browser when subscribing to push messages
+type Keys struct {
+	P256dh string
+	Auth   string
+}
+
+// VAPIDKeys is the key pair used by this server to identify itself to push services (RFC 8292)
+type VAPIDKeys struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+// GenerateVAPIDKeys returns a new base64url encoded key pair
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate VAPID keys")
+	}
+
+	return &VAPIDKeys{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+	}, nil
+}
+
+// ValidateKeys returns an error if the keys of a subscription can't be used to encrypt messages
+func ValidateKeys(keys Keys) error {
+	publicKey, err := decode(keys.P256dh)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode p256dh key")
+	}
+	if _, err := ecdh.P256().NewPublicKey(publicKey); err != nil {
+		return errors.Wrap(err, "invalid p256dh key")
+	}
+
+	authSecret, err := decode(keys.Auth)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode auth secret")
+	}
+	if len(authSecret) != 16 {
+		return errors.New("auth secret must have 16 bytes, but has %d", len(authSecret))
+	}
+	return nil
+}
+
+// Encrypt encrypts a message to a subscription with the aes128gcm content encoding (RFC 8291)
+func Encrypt(payload []byte, keys Keys) ([]byte, error) {
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate message key")
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate message salt")
+	}
+
+	return encrypt(payload, keys, serverKey, salt)
+}
+
+func encrypt(payload []byte, keys Keys, serverKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, errors.New("message has %d bytes, but the limit is %d", len(payload), MaxPayloadSize)
+	}
+
+	if err := ValidateKeys(keys); err != nil {
+		return nil, err
+	}
+
+	userAgentPublicKeyBytes, _ := decode(keys.P256dh)
+	authSecret, _ := decode(keys.Auth)
+	userAgentPublicKey, _ := ecdh.P256().NewPublicKey(userAgentPublicKeyBytes)
+
+	sharedSecret, err := serverKey.ECDH(userAgentPublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute shared secret")
+	}
+
+	serverPublicKeyBytes := serverKey.PublicKey().Bytes()
+	keyInfo := append([]byte("WebPush: info\x00"), userAgentPublicKeyBytes...)
+	keyInfo = append(keyInfo, serverPublicKeyBytes...)
+	ikm, err := expand(sharedSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	contentKey, err := expand(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := expand(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	// The 0x02 delimiter marks the last (and only) record of the message
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, 21+len(serverPublicKeyBytes))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublicKeyBytes)))
+	header = append(header, serverPublicKeyBytes...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// VAPIDAuthorization returns the Authorization header value that identifies this server to the push service of given endpoint
+func VAPIDAuthorization(endpoint, subject string, keys VAPIDKeys) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse endpoint '%s'", endpoint)
+	}
+
+	privateKeyBytes, err := decode(keys.PrivateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode VAPID private key")
+	}
+	privateKey, err := ecdh.P256().NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid VAPID private key")
+	}
+
+	publicKeyBytes := privateKey.PublicKey().Bytes()
+	signingKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(publicKeyBytes[1:33]),
+			Y:     new(big.Int).SetBytes(publicKeyBytes[33:]),
+		},
+		D: new(big.Int).SetBytes(privateKeyBytes),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": fmt.Sprintf("%s://%s", u.Scheme, u.Host),
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	}).SignedString(signingKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign VAPID token")
+	}
+
+	return fmt.Sprintf("vapid t=%s, k=%s", token, base64.RawURLEncoding.EncodeToString(publicKeyBytes)), nil
+}
+
+func expand(secret, salt, info []byte, length int) ([]byte, error) {
+	result := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), result); err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+	return result, nil
+}
+
+// decode accepts both padded and unpadded base64url, as browsers differ on what they give
+func decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
@@ -0,0 +1,92 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Test vector from RFC 8291 Section 5
+var rfcKeys = Keys{
+	P256dh: "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+	Auth:   "BTBZMqHH6r4Tts7J_aSIgg",
+}
+
+func TestEncrypt_RFC8291(t *testing.T) {
+	RegisterT(t)
+
+	serverKeyBytes, _ := decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw")
+	serverKey, err := ecdh.P256().NewPrivateKey(serverKeyBytes)
+	Expect(err).IsNil()
+	salt, _ := decode("DGv6ra1nlYgDCS1FRnbzlw")
+
+	result, err := encrypt([]byte("When I grow up, I want to be a watermelon"), rfcKeys, serverKey, salt)
+	Expect(err).IsNil()
+	Expect(base64.RawURLEncoding.EncodeToString(result)).Equals("DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN")
+}
+
+func TestEncrypt_UsesNewKeysForEachMessage(t *testing.T) {
+	RegisterT(t)
+
+	first, err := Encrypt([]byte("Hello"), rfcKeys)
+	Expect(err).IsNil()
+	second, err := Encrypt([]byte("Hello"), rfcKeys)
+	Expect(err).IsNil()
+	Expect(first).HasLen(86 + len("Hello") + 1 + 16)
+	Expect(first[:16]).NotEquals(second[:16])
+}
+
+func TestEncrypt_PayloadTooLarge(t *testing.T) {
+	RegisterT(t)
+
+	_, err := Encrypt([]byte(strings.Repeat("a", MaxPayloadSize)), rfcKeys)
+	Expect(err).IsNil()
+
+	_, err = Encrypt([]byte(strings.Repeat("a", MaxPayloadSize+1)), rfcKeys)
+	Expect(err).IsNotNil()
+}
+
+func TestValidateKeys(t *testing.T) {
+	RegisterT(t)
+
+	Expect(ValidateKeys(rfcKeys)).IsNil()
+	Expect(ValidateKeys(Keys{P256dh: rfcKeys.P256dh, Auth: "BTBZMqHH6r4Tts7J_aSIgg=="})).IsNil()
+	Expect(ValidateKeys(Keys{P256dh: rfcKeys.P256dh, Auth: "abc"})).IsNotNil()
+	Expect(ValidateKeys(Keys{P256dh: "BCVxsr7N", Auth: rfcKeys.Auth})).IsNotNil()
+	Expect(ValidateKeys(Keys{P256dh: "not base64!", Auth: rfcKeys.Auth})).IsNotNil()
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	RegisterT(t)
+
+	keys, err := GenerateVAPIDKeys()
+	Expect(err).IsNil()
+
+	authorization, err := VAPIDAuthorization("https://fcm.googleapis.com/fcm/send/abc123", "mailto:admin@fider.io", *keys)
+	Expect(err).IsNil()
+	Expect(authorization).ContainsSubstring("vapid t=")
+	Expect(authorization).ContainsSubstring(", k=" + keys.PublicKey)
+
+	publicKeyBytes, _ := decode(keys.PublicKey)
+	publicKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(publicKeyBytes[1:33]),
+		Y:     new(big.Int).SetBytes(publicKeyBytes[33:]),
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(authorization, "vapid t="), ", k="+keys.PublicKey)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return publicKey, nil
+	})
+	Expect(err).IsNil()
+	Expect(claims["aud"]).Equals("https://fcm.googleapis.com")
+	Expect(claims["sub"]).Equals("mailto:admin@fider.io")
+}
@@ -1,7 +1,10 @@
 package awsses
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"mime/quotedprintable"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -51,81 +54,91 @@ func (s Service) Init() {
 	}
 
 	sesClient = ses.New(awsSession)
-	bus.AddListener(sendMail)
+	bus.AddHandler(sendMail)
 	bus.AddHandler(fetchRecentSupressions)
 }
 
-func sendMail(ctx context.Context, c *cmd.SendMail) {
-	if c.Props == nil {
-		c.Props = dto.Props{}
-	}
-
-	if c.From.Address == "" {
-		c.From.Address = email.NoReply
-	}
-
-	for _, to := range c.To {
-		if to.Address == "" {
-			return
-		}
-
-		if !email.CanSendTo(to.Address) {
-			log.Warnf(ctx, "Skipping email to '@{Name} <@{Address}>'.", dto.Props{
-				"Name":    to.Name,
-				"Address": to.Address,
-			})
-			return
-		}
+func sendMail(ctx context.Context, c *cmd.DeliverEmail) error {
+	to := dto.Recipient{Name: c.Email.ToName, Address: c.Email.ToAddress}
 
-		log.Debugf(ctx, "Sending email to @{Address} with template @{TemplateName} and params @{Props}.", dto.Props{
-			"Address":      to.Address,
-			"TemplateName": c.TemplateName,
-			"Props":        to.Props,
-		})
+	log.Debugf(ctx, "Sending email to @{Address} with template @{TemplateName}.", dto.Props{
+		"Address":      to.Address,
+		"TemplateName": c.Email.TemplateName,
+	})
 
-		message := email.RenderMessage(ctx, c.TemplateName, c.From.Address, c.Props.Merge(to.Props))
-		tags := []*ses.MessageTag{
-			{Name: aws.String("template"), Value: aws.String(c.TemplateName)},
-		}
+	tags := []*ses.MessageTag{
+		{Name: aws.String("template"), Value: aws.String(c.Email.TemplateName)},
+	}
 
-		tenant, ok := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
-		if ok && !env.IsSingleHostMode() {
-			tags = append(tags, &ses.MessageTag{Name: aws.String("tenant"), Value: aws.String(tenant.Subdomain)})
-		}
+	tenant, ok := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+	if ok && !env.IsSingleHostMode() {
+		tags = append(tags, &ses.MessageTag{Name: aws.String("tenant"), Value: aws.String(tenant.Subdomain)})
+	}
 
-		input := &ses.SendEmailInput{
-			FromEmailAddress: aws.String(c.From.String()),
-			Destination: &ses.Destination{
-				ToAddresses: []*string{
-					aws.String(to.String()),
-				},
+	input := &ses.SendEmailInput{
+		FromEmailAddress: aws.String(c.Email.From),
+		Destination: &ses.Destination{
+			ToAddresses: []*string{
+				aws.String(to.String()),
 			},
-			Content: &ses.EmailContent{
-				Simple: &ses.Message{
-					Body: &ses.Body{
-						Html: &ses.Content{
-							Charset: aws.String("UTF-8"),
-							Data:    aws.String(message.Body),
-						},
-					},
-					Subject: &ses.Content{
+		},
+		Content: &ses.EmailContent{
+			Simple: &ses.Message{
+				Body: &ses.Body{
+					Html: &ses.Content{
 						Charset: aws.String("UTF-8"),
-						Data:    aws.String(email.EncodeSubject(message.Subject)),
+						Data:    aws.String(c.Email.Body),
 					},
 				},
+				Subject: &ses.Content{
+					Charset: aws.String("UTF-8"),
+					Data:    aws.String(email.EncodeSubject(c.Email.Subject)),
+				},
 			},
-			EmailTags: tags,
-		}
+		},
+		ReplyToAddresses: []*string{aws.String(c.Email.ReplyTo)},
+		EmailTags:        tags,
+	}
 
-		result, err := sesClient.SendEmailWithContext(ctx, input)
-		if err != nil {
-			panic(errors.Wrap(err, "failed to send email with template %s", c.TemplateName))
+	// Custom headers such as List-Unsubscribe can only be sent on raw messages
+	if c.Email.UnsubscribeURL != "" {
+		input.ReplyToAddresses = nil
+		input.Content = &ses.EmailContent{
+			Raw: &ses.RawMessage{Data: rawMessage(c.Email)},
 		}
+	}
 
-		log.Debugf(ctx, "Email sent with ID @{MessageId}.", dto.Props{
-			"MessageId": *result.MessageId,
-		})
+	result, err := sesClient.SendEmailWithContext(ctx, input)
+	if err != nil {
+		return errors.Wrap(err, "failed to send email with template %s", c.Email.TemplateName)
 	}
+
+	log.Debugf(ctx, "Email sent with ID @{MessageId}.", dto.Props{
+		"MessageId": *result.MessageId,
+	})
+	return nil
+}
+
+// rawMessage returns the MIME message of an email with one-click unsubscribe headers
+func rawMessage(outboundEmail *entity.OutboundEmail) []byte {
+	to := dto.Recipient{Name: outboundEmail.ToName, Address: outboundEmail.ToAddress}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", outboundEmail.From)
+	fmt.Fprintf(&b, "Reply-To: %s\r\n", outboundEmail.ReplyTo)
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", email.EncodeSubject(outboundEmail.Subject))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", outboundEmail.MessageID)
+	fmt.Fprintf(&b, "List-Unsubscribe: <%s>\r\n", outboundEmail.UnsubscribeURL)
+	fmt.Fprintf(&b, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	fmt.Fprintf(&b, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	_, _ = w.Write([]byte(outboundEmail.Body))
+	_ = w.Close()
+	return b.Bytes()
 }
 
 func fetchRecentSupressions(ctx context.Context, q *query.FetchRecentSupressions) error {
@@ -138,8 +151,13 @@ func fetchRecentSupressions(ctx context.Context, q *query.FetchRecentSupressions
 	}
 
 	q.EmailAddresses = make([]string, 0)
+	q.ComplainedAddresses = make([]string, 0)
 	for _, destination := range response.SuppressedDestinationSummaries {
-		q.EmailAddresses = append(q.EmailAddresses, *destination.EmailAddress)
+		if aws.StringValue(destination.Reason) == ses.SuppressionListReasonComplaint {
+			q.ComplainedAddresses = append(q.ComplainedAddresses, *destination.EmailAddress)
+		} else {
+			q.EmailAddresses = append(q.EmailAddresses, *destination.EmailAddress)
+		}
 	}
 	return nil
 }
@@ -4,7 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/services/email"
 
 	. "github.com/getfider/fider/app/pkg/assert"
@@ -73,6 +78,180 @@ Hello World Fider!
 </html>`)
 }
 
+func TestRenderMessage_WithOverride(t *testing.T) {
+	RegisterT(t)
+	bus.Init()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailTemplate) error {
+		if q.Name == "signin_email" && q.Locale == "pt-BR" {
+			q.Result = &entity.EmailTemplate{
+				Name:    q.Name,
+				Locale:  q.Locale,
+				Subject: "Entre no {{ .siteName }}",
+				Body:    "<tr><td>Olá! {{ .link | html }}</td></tr>",
+			}
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{ID: 1, Name: "Fider"})
+	props := dto.Props{
+		"siteName": "Fider",
+		"link":     "<a href='https://demo.test.fider.io/signin/verify?k=123'>Sign in</a>",
+	}
+
+	message := email.RenderMessage(context.WithValue(ctx, app.LocaleCtxKey, "pt-BR"), "signin_email", email.NoReply, props)
+	Expect(message.Subject).Equals("Entre no Fider")
+	Expect(message.Body).ContainsSubstring("<tr><td>Olá! <a href='https://demo.test.fider.io/signin/verify?k=123'>Sign in</a></td></tr>")
+
+	message = email.RenderMessage(context.WithValue(ctx, app.LocaleCtxKey, "en"), "signin_email", email.NoReply, props)
+	Expect(message.Subject).Equals("Sign in to Fider")
+}
+
+func TestRenderMessage_WithFailingOverride(t *testing.T) {
+	RegisterT(t)
+	bus.Init()
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetEmailTemplate) error {
+		q.Result = &entity.EmailTemplate{
+			Name:    q.Name,
+			Locale:  q.Locale,
+			Subject: "Sign in to {{ .siteName }}",
+			Body:    "<tr><td>{{ index .reasons 0 }}</td></tr>",
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{ID: 1, Name: "Fider"})
+	message := email.RenderMessage(context.WithValue(ctx, app.LocaleCtxKey, "en"), "signin_email", email.NoReply, dto.Props{
+		"siteName": "Fider",
+		"link":     "<a href='https://demo.test.fider.io/signin/verify?k=123'>Sign in</a>",
+	})
+	Expect(message.Subject).Equals("Sign in to Fider")
+	Expect(message.Body).ContainsSubstring("https://demo.test.fider.io/signin/verify?k=123")
+}
+
+func TestPreviewEmailTemplate(t *testing.T) {
+	RegisterT(t)
+	bus.Init(email.Service{})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{ID: 1, Name: "Demo"})
+	preview := &cmd.PreviewEmailTemplate{
+		Name:    "new_post",
+		Subject: "New idea on {{ .siteName }}: {{ .title }}",
+		Body:    "<tr><td>{{ .userName }} wrote {{ .content }}</td></tr>",
+	}
+	err := bus.Dispatch(ctx, preview)
+	Expect(err).IsNil()
+	Expect(preview.Result.Error).Equals("")
+	Expect(preview.Result.Subject).Equals("New idea on Demo: Add a dark mode")
+	Expect(preview.Result.Body).ContainsSubstring("<tr><td>Jon Snow wrote <p>It would be easier on the eyes at night.</p></td></tr>")
+
+	preview = &cmd.PreviewEmailTemplate{
+		Name:    "new_post",
+		Subject: "{{ .siteName }}",
+		Body:    "{{ .title ",
+	}
+	err = bus.Dispatch(ctx, preview)
+	Expect(err).IsNil()
+	Expect(preview.Result.Error).IsNotEmpty()
+}
+
+func TestPreviewEmailTemplate_OnlyPropsOfTemplate(t *testing.T) {
+	RegisterT(t)
+	bus.Init(email.Service{})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{ID: 1, Name: "Demo"})
+	preview := &cmd.PreviewEmailTemplate{
+		Name:    "signin_email",
+		Subject: "Sign in to {{ .siteName }}",
+		Body:    "<tr><td>{{ index .reasons 0 }}</td></tr>",
+	}
+	err := bus.Dispatch(ctx, preview)
+	Expect(err).IsNil()
+	Expect(preview.Result.Error).IsNotEmpty()
+
+	preview.Name = "post_rejected"
+	err = bus.Dispatch(ctx, preview)
+	Expect(err).IsNil()
+	Expect(preview.Result.Error).Equals("")
+	Expect(preview.Result.Body).ContainsSubstring("Title must have more than 10 characters.")
+}
+
+func TestPreviewEmailTemplate_Defaults(t *testing.T) {
+	RegisterT(t)
+	bus.Init(email.Service{})
+
+	defaults := &query.ListDefaultEmailTemplates{}
+	err := bus.Dispatch(context.Background(), defaults)
+	Expect(err).IsNil()
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, &entity.Tenant{ID: 1, Name: "Demo"})
+	for _, template := range defaults.Result {
+		preview := &cmd.PreviewEmailTemplate{Name: template.Name, Subject: template.Subject, Body: template.Body}
+		err := bus.Dispatch(ctx, preview)
+		Expect(err).IsNil()
+		Expect(preview.Result.Error).Equals("")
+	}
+}
+
+func TestListDefaultEmailTemplates(t *testing.T) {
+	RegisterT(t)
+	bus.Init(email.Service{})
+
+	defaults := &query.ListDefaultEmailTemplates{}
+	err := bus.Dispatch(context.Background(), defaults)
+	Expect(err).IsNil()
+	Expect(defaults.Result).HasLen(len(entity.EditableEmailTemplates))
+	Expect(defaults.Result[0].Name).Equals("new_post")
+	Expect(defaults.Result[0].Subject).Equals("[{{.siteName}}] {{.title}}")
+	Expect(defaults.Result[0].Body).ContainsSubstring(`{{translate "email.new_post.text"`)
+}
+
+func TestQueueMail(t *testing.T) {
+	RegisterT(t)
+	bus.Init(email.Service{})
+	email.SetAllowlist("")
+	email.SetBlocklist("(^.+@blocked.io$)")
+	defer email.SetBlocklist("")
+
+	queued := make([]*entity.OutboundEmail, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.QueueEmail) error {
+		queued = append(queued, c.Email)
+		return nil
+	})
+
+	bus.Publish(context.Background(), &cmd.SendMail{
+		From:         dto.Recipient{Name: "Fider"},
+		TemplateName: "echo_test",
+		Props:        dto.Props{"name": "World"},
+		To: []dto.Recipient{
+			{Name: "Jon Snow", Address: "jon.snow@got.com", Props: dto.Props{"name": "Jon"}},
+			{Name: "Nobody", Address: ""},
+			{Name: "Blocked", Address: "someone@blocked.io"},
+			{Name: "Arya Stark", Address: "arya.stark@got.com", ReplyTo: "reply+abc@reply.fider.io", UnsubscribeURL: "https://got.test.fider.io/unsubscribe/abc"},
+		},
+	})
+
+	Expect(queued).HasLen(2)
+
+	Expect(queued[0].TemplateName).Equals("echo_test")
+	Expect(queued[0].From).Equals(`"Fider" <noreply@random.org>`)
+	Expect(queued[0].ReplyTo).Equals(email.NoReply)
+	Expect(queued[0].ToName).Equals("Jon Snow")
+	Expect(queued[0].ToAddress).Equals("jon.snow@got.com")
+	Expect(queued[0].Subject).Equals("Message to: Jon")
+	Expect(queued[0].Body).ContainsSubstring("Hello World Jon!")
+	Expect(queued[0].MessageID).ContainsSubstring("@localhost>")
+
+	Expect(queued[1].ReplyTo).Equals("reply+abc@reply.fider.io")
+	Expect(queued[1].ToAddress).Equals("arya.stark@got.com")
+	Expect(queued[1].UnsubscribeURL).Equals("https://got.test.fider.io/unsubscribe/abc")
+	Expect(queued[1].Subject).Equals("Message to: World")
+	Expect(queued[1].MessageID).NotEquals(queued[0].MessageID)
+}
+
 func TestCanSendTo(t *testing.T) {
 	RegisterT(t)
 
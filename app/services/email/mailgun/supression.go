@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/dto"
@@ -27,32 +28,52 @@ type EventsResponse struct {
 }
 
 func fetchRecentSupressions(ctx context.Context, q *query.FetchRecentSupressions) error {
-	supressedAddresses := make(map[string]bool)
+	failed := url.Values{}
+	failed.Set("event", "failed")
+	failed.Set("severity", "permanent")
+
+	bounced, err := fetchEventRecipients(ctx, failed, q.StartTime)
+	if err != nil {
+		return err
+	}
+
+	complained := url.Values{}
+	complained.Set("event", "complained")
+
+	complaints, err := fetchEventRecipients(ctx, complained, q.StartTime)
+	if err != nil {
+		return err
+	}
+
+	q.EmailAddresses = bounced
+	q.ComplainedAddresses = complaints
+	return nil
+}
+
+func fetchEventRecipients(ctx context.Context, params url.Values, startTime time.Time) ([]string, error) {
+	recipients := make(map[string]bool)
 
-	params := url.Values{}
-	params.Set("event", "failed")
 	params.Set("limit", "300")
-	params.Set("severity", "permanent")
-	params.Set("begin", strconv.FormatInt(q.StartTime.Unix(), 10))
+	params.Set("begin", strconv.FormatInt(startTime.Unix(), 10))
 
 	endpointURL := fmt.Sprintf("%s?%s", getEndpoint(ctx, env.Config.Email.Mailgun.Domain, "/events"), params.Encode())
 	for {
 		res, err := fetchEvents(ctx, endpointURL)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, item := range res.Items {
-			supressedAddresses[strings.TrimSpace(item.Recipient)] = true
+			recipients[strings.TrimSpace(item.Recipient)] = true
 		}
 
 		// No more emails to fetch
 		if len(res.Items) == 0 {
-			q.EmailAddresses = make([]string, 0)
-			for email := range supressedAddresses {
-				q.EmailAddresses = append(q.EmailAddresses, email)
+			result := make([]string, 0, len(recipients))
+			for email := range recipients {
+				result = append(result, email)
 			}
-			return nil
+			return result, nil
 		}
 
 		// continue on the next page
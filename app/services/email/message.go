@@ -1,14 +1,13 @@
 package email
 
 import (
-	"bytes"
 	"context"
 	"mime"
-	"strings"
 	"unicode"
 
 	"github.com/getfider/fider/app/models/dto"
-	"github.com/getfider/fider/app/pkg/tpl"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
 )
 
 // Message represents what is sent by email
@@ -37,27 +36,31 @@ func EncodeSubject(subject string) string {
 	return mime.QEncoding.Encode("utf-8", subject)
 }
 
-// RenderMessage returns the HTML of an email based on template and params
-func RenderMessage(ctx context.Context, templateName string, fromAddress string, params dto.Props) *Message {
+// RenderMessage returns the HTML of an email based on template and params, using the tenant override of the template when there is one
+// The notice about not replying to the email is only shown when the reply address is the no-reply one
+func RenderMessage(ctx context.Context, templateName string, replyAddress string, params dto.Props) *Message {
 	noreply := false
-	if fromAddress == NoReply {
+	if replyAddress == NoReply {
 		noreply = true
 	}
 
-	tmpl := tpl.GetTemplate("/views/email/base_email.html", "/views/email/"+templateName+".html")
-	var bf bytes.Buffer
-	if err := tpl.Render(ctx, tmpl, &bf, params.Merge(dto.Props{
+	params = params.Merge(dto.Props{
 		"logo":    params["logo"],
 		"noreply": noreply,
-	})); err != nil {
-		panic(err)
-	}
+	})
 
-	lines := strings.Split(bf.String(), "\n")
-	body := strings.TrimLeft(strings.Join(lines[2:], "\n"), " ")
+	// Overrides are validated when saved, but they can still fail with actual data, so the default email is sent instead
+	if override := getOverride(ctx, templateName); override != nil {
+		message, err := renderTemplate(ctx, override, params)
+		if err == nil {
+			return message
+		}
+		log.Error(ctx, errors.Wrap(err, "failed to render email template '%s' override", templateName))
+	}
 
-	return &Message{
-		Subject: strings.TrimLeft(lines[0], "subject: "),
-		Body:    body,
+	message, err := renderTemplate(ctx, getDefaultTemplate(templateName), params)
+	if err != nil {
+		panic(err)
 	}
+	return message
 }
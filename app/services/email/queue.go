@@ -0,0 +1,115 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+func init() {
+	bus.Register(Service{})
+}
+
+// Service queues the emails sent by Fider and handles the email templates that tenants can customize
+type Service struct{}
+
+func (s Service) Name() string {
+	return "Email Queue"
+}
+
+func (s Service) Category() string {
+	return "emailqueue"
+}
+
+func (s Service) Enabled() bool {
+	return true
+}
+
+func (s Service) Init() {
+	bus.AddListener(queueMail)
+	bus.AddHandler(listDefaultEmailTemplates)
+	bus.AddHandler(previewEmailTemplate)
+}
+
+// queueMail renders the email of each recipient and stores it on the outbound email queue.
+// The email provider only receives it later from the queue job, so a slow provider doesn't hold up background tasks
+func queueMail(ctx context.Context, c *cmd.SendMail) error {
+	if c.Props == nil {
+		c.Props = dto.Props{}
+	}
+
+	if c.From.Address == "" {
+		c.From.Address = NoReply
+	}
+
+	for _, to := range c.To {
+		if to.Address == "" {
+			continue
+		}
+
+		if !CanSendTo(to.Address) {
+			log.Warnf(ctx, "Skipping email to '@{Name} <@{Address}>'.", dto.Props{
+				"Name":    to.Name,
+				"Address": to.Address,
+			})
+			continue
+		}
+
+		replyTo := c.From.Address
+		if to.ReplyTo != "" {
+			replyTo = to.ReplyTo
+		}
+
+		message := RenderMessage(ctx, c.TemplateName, replyTo, c.Props.Merge(to.Props))
+		queueEmail := &cmd.QueueEmail{
+			Email: &entity.OutboundEmail{
+				MessageID:      generateMessageID(ctx),
+				TemplateName:   c.TemplateName,
+				From:           c.From.String(),
+				ReplyTo:        replyTo,
+				ToName:         to.Name,
+				ToAddress:      to.Address,
+				UnsubscribeURL: to.UnsubscribeURL,
+				Subject:        message.Subject,
+				Body:           message.Body,
+			},
+		}
+		if err := bus.Dispatch(ctx, queueEmail); err != nil {
+			return err
+		}
+
+		log.Debugf(ctx, "Email to @{Address} with template @{TemplateName} queued.", dto.Props{
+			"Address":      to.Address,
+			"TemplateName": c.TemplateName,
+		})
+	}
+
+	return nil
+}
+
+// generateMessageID returns an unique Message-ID for an email, which is used to track it across email providers
+func generateMessageID(ctx context.Context) string {
+	localName := "localhost"
+	if u, err := url.Parse(web.BaseURL(ctx)); err == nil && u.Hostname() != "" {
+		localName = u.Hostname()
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UTC().UnixNano(), 10)
+	buf := make([]byte, 16)
+	_, err := rand.Read(buf)
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("<%s.%s@%s>", hex.EncodeToString(buf), timestamp, localName)
+}
@@ -2,14 +2,11 @@ package smtp
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/tls"
-	"encoding/hex"
 	"fmt"
 	"net"
 	gosmtp "net/smtp"
 	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/getfider/fider/app/models/cmd"
@@ -18,6 +15,7 @@ import (
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/env"
 	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/inbound"
 	"github.com/getfider/fider/app/pkg/log"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/services/email"
@@ -42,7 +40,7 @@ func (s Service) Enabled() bool {
 }
 
 func (s Service) Init() {
-	bus.AddListener(sendMail)
+	bus.AddHandler(sendMail)
 	bus.AddHandler(fetchRecentSupressions)
 }
 
@@ -51,61 +49,47 @@ func fetchRecentSupressions(ctx context.Context, c *query.FetchRecentSupressions
 	return nil
 }
 
-func sendMail(ctx context.Context, c *cmd.SendMail) {
-	if c.Props == nil {
-		c.Props = dto.Props{}
+func sendMail(ctx context.Context, c *cmd.DeliverEmail) error {
+	u, err := url.Parse(web.BaseURL(ctx))
+	localname := "localhost"
+	if err == nil && u.Hostname() != "" {
+		localname = u.Hostname()
 	}
 
-	if c.From.Address == "" {
-		c.From.Address = email.NoReply
+	log.Debugf(ctx, "Sending email to @{Address} with template @{TemplateName}.", dto.Props{
+		"Address":      c.Email.ToAddress,
+		"TemplateName": c.Email.TemplateName,
+	})
+
+	to := dto.Recipient{Name: c.Email.ToName, Address: c.Email.ToAddress}
+	b := builder{}
+	b.Set("From", c.Email.From)
+	b.Set("Reply-To", c.Email.ReplyTo)
+	b.Set("To", to.String())
+	b.Set("Subject", email.EncodeSubject(c.Email.Subject))
+	b.Set("MIME-version", "1.0")
+	b.Set("Content-Type", "text/html; charset=\"UTF-8\"")
+	b.Set("Date", time.Now().Format(time.RFC1123Z))
+	b.Set("Message-ID", c.Email.MessageID)
+	if c.Email.UnsubscribeURL != "" {
+		b.Set("List-Unsubscribe", fmt.Sprintf("<%s>", c.Email.UnsubscribeURL))
+		b.Set("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
 	}
-
-	for _, to := range c.To {
-		if to.Address == "" {
-			return
-		}
-
-		u, err := url.Parse(web.BaseURL(ctx))
-		localname := "localhost"
-		if err == nil {
-			localname = u.Hostname()
-		}
-
-		if !email.CanSendTo(to.Address) {
-			log.Warnf(ctx, "Skipping email to '@{Name} <@{Address}>'.", dto.Props{
-				"Name":    to.Name,
-				"Address": to.Address,
-			})
-			return
-		}
-
-		log.Debugf(ctx, "Sending email to @{Address} with template @{TemplateName} and params @{Props}.", dto.Props{
-			"Address":      to.Address,
-			"TemplateName": c.TemplateName,
-			"Props":        to.Props,
-		})
-
-		message := email.RenderMessage(ctx, c.TemplateName, c.From.Address, c.Props.Merge(to.Props))
-		b := builder{}
-		b.Set("From", c.From.String())
-		b.Set("Reply-To", c.From.Address)
-		b.Set("To", to.String())
-		b.Set("Subject", email.EncodeSubject(message.Subject))
-		b.Set("MIME-version", "1.0")
-		b.Set("Content-Type", "text/html; charset=\"UTF-8\"")
-		b.Set("Date", time.Now().Format(time.RFC1123Z))
-		b.Set("Message-ID", generateMessageID(localname))
-		b.Body(message.Body)
-
-		smtpConfig := env.Config.Email.SMTP
-		servername := fmt.Sprintf("%s:%s", smtpConfig.Host, smtpConfig.Port)
-		auth := authenticate(smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)
-		err = Send(localname, servername, smtpConfig.EnableStartTLS, auth, email.NoReply, []string{to.Address}, b.Bytes())
-		if err != nil {
-			panic(errors.Wrap(err, "failed to send email with template %s", c.TemplateName))
-		}
-		log.Debug(ctx, "Email sent.")
+	b.Body(c.Email.Body)
+
+	smtpConfig := env.Config.Email.SMTP
+	servername := fmt.Sprintf("%s:%s", smtpConfig.Host, smtpConfig.Port)
+	auth := authenticate(smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)
+	envelopeFrom := email.NoReply
+	if bounceAddress := inbound.BounceAddress(to.Address); bounceAddress != "" {
+		envelopeFrom = bounceAddress
 	}
+	err = Send(localname, servername, smtpConfig.EnableStartTLS, auth, envelopeFrom, []string{to.Address}, b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "failed to send email with template %s", c.Email.TemplateName)
+	}
+	log.Debug(ctx, "Email sent.")
+	return nil
 }
 
 var Send = func(localName, serverAddress string, enableStartTLS bool, a gosmtp.Auth, from string, to []string, msg []byte) error {
@@ -157,18 +141,6 @@ var Send = func(localName, serverAddress string, enableStartTLS bool, a gosmtp.A
 	return c.Quit()
 }
 
-func generateMessageID(localName string) string {
-	timestamp := strconv.FormatInt(time.Now().UTC().UnixNano(), 10)
-	buf := make([]byte, 16)
-	_, err := rand.Read(buf)
-	if err != nil {
-		panic(err)
-	}
-	randStr := hex.EncodeToString(buf)
-	messageID := fmt.Sprintf("<%s.%s@%s>", randStr, timestamp, localName)
-	return messageID
-}
-
 func authenticate(username string, password string, host string) gosmtp.Auth {
 	if username == "" && password == "" {
 		return nil
@@ -2,18 +2,19 @@ package smtp_test
 
 import (
 	"context"
+	"errors"
 	gosmtp "net/smtp"
-	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/getfider/fider/app"
 
 	"github.com/getfider/fider/app/models/cmd"
-	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
-	"github.com/getfider/fider/app/services/email"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
 	"github.com/getfider/fider/app/services/email/smtp"
 )
 
@@ -43,23 +44,26 @@ func reset() {
 	bus.Init(smtp.Service{})
 }
 
+func newOutboundEmail() *entity.OutboundEmail {
+	return &entity.OutboundEmail{
+		ID:           1,
+		MessageID:    "<abc.123@got.test.fider.io>",
+		TemplateName: "echo_test",
+		From:         "\"Fider Test\" <noreply@random.org>",
+		ReplyTo:      "noreply@random.org",
+		ToName:       "Jon Sow",
+		ToAddress:    "jon.snow@got.com",
+		Subject:      "Message to: Hello",
+		Body:         "Hello World Hello!",
+	}
+}
+
 func TestSend_Success(t *testing.T) {
 	RegisterT(t)
 	reset()
 
-	bus.Publish(ctx, &cmd.SendMail{
-		From: dto.Recipient{Name: "Fider Test"},
-		To: []dto.Recipient{
-			{
-				Name:    "Jon Sow",
-				Address: "jon.snow@got.com",
-			},
-		},
-		TemplateName: "echo_test",
-		Props: dto.Props{
-			"name": "Hello",
-		},
-	})
+	err := bus.Dispatch(ctx, &cmd.DeliverEmail{Email: newOutboundEmail()})
+	Expect(err).IsNil()
 
 	Expect(requests).HasLen(1)
 	Expect(requests[0].servername).Equals("localhost:1234")
@@ -67,96 +71,63 @@ func TestSend_Success(t *testing.T) {
 	Expect(requests[0].from).Equals("noreply@random.org")
 	Expect(requests[0].to).Equals([]string{"jon.snow@got.com"})
 	Expect(string(requests[0].body)).ContainsSubstring("From: \"Fider Test\" <noreply@random.org>\r\nReply-To: noreply@random.org\r\nTo: \"Jon Sow\" <jon.snow@got.com>\r\nSubject: Message to: Hello\r\nMIME-version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\nDate: ")
-	Expect(string(requests[0].body)).ContainsSubstring("Message-ID: ")
-	Expect(string(requests[0].body)).ContainsSubstring("Hello World Hello!")
+	Expect(string(requests[0].body)).ContainsSubstring("Message-ID: <abc.123@got.test.fider.io>\r\n")
+	Expect(string(requests[0].body)).ContainsSubstring("\r\n\r\nHello World Hello!")
+	Expect(strings.Contains(string(requests[0].body), "List-Unsubscribe")).IsFalse()
+}
+
+func TestSend_WithRecipientReplyTo(t *testing.T) {
+	RegisterT(t)
+	reset()
 
-	var validID = regexp.MustCompile(`.*Message-ID: <[a-z0-9\-].*\.[0-9].*@.*>.*`)
-	Expect(validID.MatchString(string(requests[0].body))).IsTrue()
+	outboundEmail := newOutboundEmail()
+	outboundEmail.ReplyTo = "reply+1.2.3.abc@reply.random.org"
+	err := bus.Dispatch(ctx, &cmd.DeliverEmail{Email: outboundEmail})
+	Expect(err).IsNil()
+
+	Expect(requests).HasLen(1)
+	Expect(requests[0].from).Equals("noreply@random.org")
+	Expect(string(requests[0].body)).ContainsSubstring("From: \"Fider Test\" <noreply@random.org>\r\nReply-To: reply+1.2.3.abc@reply.random.org\r\n")
 }
-func TestSend_SkipEmptyAddress(t *testing.T) {
+
+func TestSend_WithUnsubscribeURL(t *testing.T) {
 	RegisterT(t)
 	reset()
 
-	bus.Publish(ctx, &cmd.SendMail{
-		From: dto.Recipient{Name: "Fider Test"},
-		To: []dto.Recipient{
-			{
-				Name:    "Jon Sow",
-				Address: "",
-			},
-		},
-		TemplateName: "echo_test",
-		Props: dto.Props{
-			"name": "Hello",
-		},
-	})
+	outboundEmail := newOutboundEmail()
+	outboundEmail.UnsubscribeURL = "https://got.test.fider.io/unsubscribe/abc"
+	err := bus.Dispatch(ctx, &cmd.DeliverEmail{Email: outboundEmail})
+	Expect(err).IsNil()
 
-	Expect(requests).HasLen(0)
+	Expect(requests).HasLen(1)
+	Expect(string(requests[0].body)).ContainsSubstring("List-Unsubscribe: <https://got.test.fider.io/unsubscribe/abc>\r\nList-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
 }
 
-func TestSend_SkipUnlistedAddress(t *testing.T) {
+func TestSend_WithBounceAddress(t *testing.T) {
 	RegisterT(t)
 	reset()
-	email.SetAllowlist("^.*@gmail.com$")
-
-	bus.Publish(ctx, &cmd.SendMail{
-		From: dto.Recipient{Name: "Fider Test"},
-		To: []dto.Recipient{
-			{
-				Name:    "Jon Sow",
-				Address: "jon.snow@got.com",
-			},
-		},
-		TemplateName: "echo_test",
-		Props: dto.Props{
-			"name": "Hello",
-		},
-	})
 
-	Expect(requests).HasLen(0)
+	env.Config.Email.Inbound.Domain = "reply.random.org"
+	env.Config.Email.Inbound.SMTPAddress = ":2525"
+
+	err := bus.Dispatch(ctx, &cmd.DeliverEmail{Email: newOutboundEmail()})
+	Expect(err).IsNil()
+
+	Expect(requests).HasLen(1)
+	Expect(requests[0].from).Equals(inbound.BounceAddress("jon.snow@got.com"))
+	Expect(inbound.IsBounceAddressFor(requests[0].from, "jon.snow@got.com")).IsTrue()
+	Expect(string(requests[0].body)).ContainsSubstring("From: \"Fider Test\" <noreply@random.org>\r\n")
 }
 
-func TestBatch_Success(t *testing.T) {
+func TestSend_Failure(t *testing.T) {
 	RegisterT(t)
 	reset()
-	email.SetAllowlist("")
-
-	bus.Publish(ctx, &cmd.SendMail{
-		From: dto.Recipient{Name: "Fider Test"},
-		To: []dto.Recipient{
-			{
-				Name:    "Jon Sow",
-				Address: "jon.snow@got.com",
-				Props: dto.Props{
-					"name": "Jon",
-				},
-			},
-			{
-				Name:    "Arya Stark",
-				Address: "arya.start@got.com",
-				Props: dto.Props{
-					"name": "Arya",
-				},
-			},
-		},
-		TemplateName: "echo_test",
-	})
 
-	Expect(requests).HasLen(2)
+	smtp.Send = func(localname, servername string, enableStartTLS bool, auth gosmtp.Auth, from string, to []string, body []byte) error {
+		return errors.New("421 Service not available")
+	}
 
-	Expect(requests[0].servername).Equals("localhost:1234")
-	Expect(requests[0].auth).Equals(smtp.AgnosticAuth("", "us3r", "p4ss", "localhost"))
-	Expect(requests[0].from).Equals("noreply@random.org")
-	Expect(requests[0].to).Equals([]string{"jon.snow@got.com"})
-	Expect(string(requests[0].body)).ContainsSubstring("From: \"Fider Test\" <noreply@random.org>\r\nReply-To: noreply@random.org\r\nTo: \"Jon Sow\" <jon.snow@got.com>\r\nSubject: Message to: Jon\r\nMIME-version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\nDate: ")
-	Expect(string(requests[0].body)).ContainsSubstring("Message-ID: ")
-	Expect(string(requests[0].body)).ContainsSubstring("Hello World Jon!")
-
-	Expect(requests[1].servername).Equals("localhost:1234")
-	Expect(requests[1].auth).Equals(smtp.AgnosticAuth("", "us3r", "p4ss", "localhost"))
-	Expect(requests[1].from).Equals("noreply@random.org")
-	Expect(requests[1].to).Equals([]string{"arya.start@got.com"})
-	Expect(string(requests[1].body)).ContainsSubstring("From: \"Fider Test\" <noreply@random.org>\r\nReply-To: noreply@random.org\r\nTo: \"Arya Stark\" <arya.start@got.com>\r\nSubject: Message to: Arya\r\nMIME-version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\nDate: ")
-	Expect(string(requests[1].body)).ContainsSubstring("Message-ID: ")
-	Expect(string(requests[1].body)).ContainsSubstring("Hello World Arya!")
+	err := bus.Dispatch(ctx, &cmd.DeliverEmail{Email: newOutboundEmail()})
+	Expect(err).IsNotNil()
+	Expect(err.Error()).ContainsSubstring("421 Service not available")
 }
@@ -0,0 +1,163 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"strings"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/tpl"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+func getDefaultTemplate(templateName string) *template.Template {
+	return tpl.GetTemplate("/views/email/base_email.html", "/views/email/"+templateName+".html")
+}
+
+// getOverride returns the tenant override of an email for the current locale, or nil when there is none
+func getOverride(ctx context.Context, templateName string) *template.Template {
+	if tenant, ok := ctx.Value(app.TenantCtxKey).(*entity.Tenant); !ok || tenant == nil || !entity.IsEditableEmailTemplate(templateName) {
+		return nil
+	}
+
+	getOverride := &query.GetEmailTemplate{Name: templateName, Locale: i18n.GetLocale(ctx)}
+	if err := bus.Dispatch(ctx, getOverride); err != nil {
+		if errors.Cause(err) != app.ErrNotFound {
+			log.Error(ctx, err)
+		}
+		return nil
+	}
+
+	overridden, err := overrideTemplate(getDefaultTemplate(templateName), getOverride.Result.Subject, getOverride.Result.Body)
+	if err != nil {
+		log.Error(ctx, errors.Wrap(err, "failed to parse email template '%s' override", templateName))
+		return nil
+	}
+	return overridden
+}
+
+func overrideTemplate(tmpl *template.Template, subject, body string) (*template.Template, error) {
+	return tpl.Override(tmpl, map[string]string{
+		"subject": strings.Join(strings.Fields(subject), " "),
+		"body":    body,
+	})
+}
+
+func renderTemplate(ctx context.Context, tmpl *template.Template, params dto.Props) (*Message, error) {
+	var bf bytes.Buffer
+	if err := tpl.Render(ctx, tmpl, &bf, params); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(bf.String(), "\n")
+	body := strings.TrimLeft(strings.Join(lines[2:], "\n"), " ")
+
+	return &Message{
+		Subject: strings.TrimLeft(lines[0], "subject: "),
+		Body:    body,
+	}, nil
+}
+
+func listDefaultEmailTemplates(ctx context.Context, q *query.ListDefaultEmailTemplates) error {
+	q.Result = make([]*entity.EmailTemplate, len(entity.EditableEmailTemplates))
+	for i, name := range entity.EditableEmailTemplates {
+		tmpl := getDefaultTemplate(name)
+		q.Result[i] = &entity.EmailTemplate{
+			Name:    name,
+			Subject: strings.TrimSpace(tmpl.Lookup("subject").Tree.Root.String()),
+			Body:    strings.TrimSpace(tmpl.Lookup("body").Tree.Root.String()),
+		}
+	}
+	return nil
+}
+
+func previewEmailTemplate(ctx context.Context, c *cmd.PreviewEmailTemplate) error {
+	c.Result = &dto.EmailTemplatePreview{}
+
+	tmpl, err := overrideTemplate(getDefaultTemplate(c.Name), c.Subject, c.Body)
+	if err == nil {
+		var message *Message
+		if message, err = renderTemplate(ctx, tmpl, sampleProps(ctx, c.Name)); err == nil {
+			c.Result.Subject = message.Subject
+			c.Result.Body = message.Body
+		}
+	}
+
+	if err != nil {
+		// Do not propagate error: it's a preview
+		c.Result.Error = err.Error()
+	}
+	return nil
+}
+
+// templateProps lists the props each email is sent with, in addition to logo and noreply
+var templateProps = map[string][]string{
+	"new_post":                  {"title", "siteName", "userName", "content", "postLink", "view", "change", "unsubscribe"},
+	"new_comment":               {"title", "messageLocaleString", "siteName", "userName", "content", "postLink", "view", "change", "unsubscribe"},
+	"change_status":             {"title", "postLink", "siteName", "content", "status", "duplicate", "view", "change", "unsubscribe"},
+	"delete_post":               {"title", "siteName", "content", "change", "unsubscribe"},
+	"merge_post":                {"title", "originalTitle", "siteName", "postLink", "original", "view", "change", "unsubscribe"},
+	"post_received":             {"title", "siteName", "postLink", "change", "unsubscribe"},
+	"post_rejected":             {"title", "siteName", "reasons"},
+	"digest":                    {"title", "siteName", "items", "change"},
+	"invite_email":              {"subject", "message"},
+	"signin_email":              {"siteName", "link"},
+	"signup_email":              {"link"},
+	"change_emailaddress_email": {"name", "oldEmail", "newEmail", "link"},
+}
+
+// sampleProps returns the props used to preview given email template
+// Only the props the email is actually sent with are set, so that previews fail where sending would
+func sampleProps(ctx context.Context, templateName string) dto.Props {
+	siteName := "Fider"
+	if tenant, ok := ctx.Value(app.TenantCtxKey).(*entity.Tenant); ok && tenant != nil {
+		siteName = tenant.Name
+	}
+
+	baseURL := web.BaseURL(ctx)
+	link := func(text, path string) string {
+		return "<a href='" + baseURL + path + "'>" + text + "</a>"
+	}
+
+	all := dto.Props{
+		"siteName":            siteName,
+		"title":               "Add a dark mode",
+		"originalTitle":       "Support dark themes",
+		"userName":            "Jon Snow",
+		"name":                "Jon Snow",
+		"postLink":            link("#42", "/posts/42/add-a-dark-mode"),
+		"original":            link("#7", "/posts/7/support-dark-themes"),
+		"status":              "Planned",
+		"duplicate":           link("Support dark themes", "/posts/7/support-dark-themes"),
+		"messageLocaleString": "email.new_comment.text",
+		"content":             template.HTML("<p>It would be easier on the eyes at night.</p>"),
+		"view":                link(i18n.T(ctx, "email.subscription.view"), "/posts/42/add-a-dark-mode"),
+		"unsubscribe":         link(i18n.T(ctx, "email.subscription.unsubscribe"), "/posts/42/add-a-dark-mode"),
+		"change":              link(i18n.T(ctx, "email.subscription.change"), "/settings"),
+		"link":                link(baseURL+"/signin/verify?k=sample", "/signin/verify?k=sample"),
+		"oldEmail":            "jon.snow@got.com",
+		"newEmail":            "lord.commander@got.com",
+		"subject":             "Share your feedback with us",
+		"message":             template.HTML("<p>We would love to hear your ideas!</p>"),
+		"reasons":             []string{"Title must have more than 10 characters."},
+		"items":               []dto.Props{{"title": "Add a dark mode", "link": baseURL + "/posts/42/add-a-dark-mode"}},
+	}
+
+	props := dto.Props{
+		"logo":    web.LogoURL(ctx),
+		"noreply": true,
+	}
+	for _, name := range templateProps[templateName] {
+		props[name] = all[name]
+	}
+	return props
+}
@@ -121,9 +121,10 @@ func parseOAuthRawProfile(ctx context.Context, c *cmd.ParseOAuthRawProfile) erro
 	name := extractCompositeName(query, config.JSONUserNamePath)
 
 	profile := &dto.OAuthUserProfile{
-		ID:    strings.TrimSpace(query.String(config.JSONUserIDPath)),
-		Name:  name,
-		Email: strings.ToLower(strings.TrimSpace(query.String(config.JSONUserEmailPath))),
+		ID:     strings.TrimSpace(query.String(config.JSONUserIDPath)),
+		Name:   name,
+		Email:  strings.ToLower(strings.TrimSpace(query.String(config.JSONUserEmailPath))),
+		Groups: query.Strings(config.JSONUserGroupsPath),
 	}
 
 	if profile.ID == "" {
@@ -234,6 +235,15 @@ func getOAuthProfile(ctx context.Context, q *query.GetOAuthProfile) error {
 		return errors.New("Provider %s is disabled", q.Provider)
 	}
 
+	samlConfig := &query.GetSAMLConfig{}
+	err = bus.Dispatch(ctx, samlConfig)
+	if err != nil && errors.Cause(err) != app.ErrNotFound {
+		return err
+	}
+	if err == nil && samlConfig.Result.IsEnabled() && samlConfig.Result.RequireSSO {
+		return errors.New("Provider %s is disabled because SAML single sign-on is required", q.Provider)
+	}
+
 	rawProfile := &query.GetOAuthRawProfile{Provider: q.Provider, Code: q.Code}
 	err = bus.Dispatch(ctx, rawProfile)
 	if err != nil {
@@ -311,6 +321,27 @@ func listActiveOAuthProviders(ctx context.Context, q *query.ListActiveOAuthProvi
 	}
 
 	list := make([]*dto.OAuthProviderOption, 0)
+
+	samlConfig := &query.GetSAMLConfig{}
+	err = bus.Dispatch(ctx, samlConfig)
+	if err != nil && errors.Cause(err) != app.ErrNotFound {
+		return err
+	}
+	if err == nil && samlConfig.Result.IsEnabled() {
+		list = append(list, &dto.OAuthProviderOption{
+			Provider:    app.SAMLProvider,
+			DisplayName: samlConfig.Result.DisplayName,
+			URL:         "/saml/login",
+			IsEnabled:   true,
+		})
+
+		// When SSO is required, SAML is the only way to sign in
+		if samlConfig.Result.RequireSSO {
+			q.Result = list
+			return nil
+		}
+	}
+
 	for _, p := range allOAuthProviders.Result {
 		if p.IsEnabled {
 			list = append(list, p)
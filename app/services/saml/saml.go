@@ -0,0 +1,282 @@
+package saml
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/validate"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+func init() {
+	bus.Register(Service{})
+}
+
+type Service struct{}
+
+func (s Service) Name() string {
+	return "crewjam"
+}
+
+func (s Service) Category() string {
+	return "SAML"
+}
+
+func (s Service) Enabled() bool {
+	return true
+}
+
+func (s Service) Init() {
+	bus.AddHandler(getSAMLAuthnRequestURL)
+	bus.AddHandler(parseSAMLResponse)
+	bus.AddHandler(parseSAMLIdPMetadata)
+	bus.AddHandler(getSAMLServiceProviderMetadata)
+}
+
+func getSAMLAuthnRequestURL(ctx context.Context, q *query.GetSAMLAuthnRequestURL) error {
+	config, err := getEnabledConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	sp, err := newServiceProvider(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	ssoURL := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	req, err := sp.MakeAuthenticationRequest(ssoURL, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return errors.Wrap(err, "failed to create SAML authentication request")
+	}
+
+	// The request ID travels in the signed RelayState, so the ACS can verify InResponseTo without server side state
+	relayState, err := jwt.Encode(jwt.SAMLStateClaims{
+		RequestID: req.ID,
+		Redirect:  q.Redirect,
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(10 * time.Minute)),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode SAML state")
+	}
+
+	redirectURL, err := req.Redirect(relayState, sp)
+	if err != nil {
+		return errors.Wrap(err, "failed to create SAML redirect URL")
+	}
+
+	q.Result = redirectURL.String()
+	return nil
+}
+
+func parseSAMLResponse(ctx context.Context, q *query.ParseSAMLResponse) error {
+	config, err := getEnabledConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	sp, err := newServiceProvider(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	rawResponse, err := base64.StdEncoding.DecodeString(q.SAMLResponse)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode SAML response")
+	}
+
+	profile := &dto.SAMLUserProfile{}
+	possibleRequestIDs := []string{}
+	if state, err := jwt.DecodeSAMLStateClaims(q.RelayState); err == nil {
+		possibleRequestIDs = append(possibleRequestIDs, state.RequestID)
+		profile.Redirect = state.Redirect
+	} else if config.AllowIdPInitiated {
+		// Without a state issued by us, this can only be an IdP-initiated sign in, which administrators must opt in to
+		// as there's no request to tie the response to
+		sp.AllowIDPInitiated = true
+		profile.Redirect = q.RelayState
+	} else {
+		return errors.New("SAML response has no valid state and IdP-initiated sign in is disabled")
+	}
+
+	assertion, err := sp.ParseXMLResponse(rawResponse, possibleRequestIDs, sp.AcsURL)
+	if err != nil {
+		if invalid, ok := err.(*saml.InvalidResponseError); ok {
+			err = invalid.PrivateErr
+		}
+		return errors.Wrap(err, "failed to validate SAML response")
+	}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		profile.ID = strings.TrimSpace(assertion.Subject.NameID.Value)
+	}
+	if profile.ID == "" {
+		return app.ErrUserIDRequired
+	}
+
+	profile.Name = firstAttributeValue(assertion, config.AttributeName)
+	profile.Email = strings.ToLower(firstAttributeValue(assertion, config.AttributeEmail))
+	if profile.Email == "" && config.AttributeEmail == "" {
+		profile.Email = strings.ToLower(profile.ID)
+	}
+
+	if profile.Name == "" && profile.Email != "" {
+		parts := strings.Split(profile.Email, "@")
+		profile.Name = parts[0]
+	}
+
+	if profile.Name == "" {
+		profile.Name = "Anonymous"
+	}
+
+	if len(validate.Email(ctx, profile.Email)) != 0 {
+		profile.Email = ""
+	}
+
+	if config.AttributeRole != "" {
+		if values := attributeValues(assertion, config.AttributeRole); len(values) > 0 {
+			profile.Role = config.MapRole(values)
+		}
+	}
+
+	q.Result = profile
+	return nil
+}
+
+func parseSAMLIdPMetadata(ctx context.Context, q *query.ParseSAMLIdPMetadata) error {
+	idpMetadata, err := samlsp.ParseMetadata([]byte(q.Metadata))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse SAML IdP metadata")
+	}
+
+	sp := &saml.ServiceProvider{IDPMetadata: idpMetadata}
+	result := &dto.SAMLIdPMetadata{
+		EntityID: idpMetadata.EntityID,
+		SSOURL:   sp.GetSSOBindingLocation(saml.HTTPRedirectBinding),
+	}
+
+	if result.EntityID == "" {
+		return errors.New("SAML IdP metadata has no entity ID")
+	}
+	if result.SSOURL == "" {
+		return errors.New("SAML IdP metadata has no HTTP-Redirect single sign-on service")
+	}
+	if !hasSigningCertificate(idpMetadata) {
+		return errors.New("SAML IdP metadata has no signing certificate")
+	}
+
+	q.Result = result
+	return nil
+}
+
+func getSAMLServiceProviderMetadata(ctx context.Context, q *query.GetSAMLServiceProviderMetadata) error {
+	sp, err := newServiceProvider(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode SAML SP metadata")
+	}
+
+	q.Result = xml.Header + string(metadata)
+	return nil
+}
+
+func getEnabledConfig(ctx context.Context) (*entity.SAMLConfig, error) {
+	getConfig := &query.GetSAMLConfig{}
+	if err := bus.Dispatch(ctx, getConfig); err != nil {
+		return nil, err
+	}
+
+	if !getConfig.Result.IsEnabled() {
+		return nil, app.ErrNotFound
+	}
+
+	return getConfig.Result, nil
+}
+
+// newServiceProvider describes current tenant as a SAML service provider of given config
+// Requests are not signed and assertions are not encrypted, so no SP key pair is needed
+func newServiceProvider(ctx context.Context, config *entity.SAMLConfig) (*saml.ServiceProvider, error) {
+	baseURL := web.BaseURL(ctx)
+	metadataURL, err := url.Parse(baseURL + "/saml/metadata")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse SAML metadata URL")
+	}
+	acsURL, err := url.Parse(baseURL + "/saml/acs")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse SAML ACS URL")
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+	}
+
+	if config != nil {
+		sp.IDPMetadata, err = samlsp.ParseMetadata([]byte(config.IdPMetadata))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse SAML IdP metadata")
+		}
+	}
+
+	return sp, nil
+}
+
+func hasSigningCertificate(idpMetadata *saml.EntityDescriptor) bool {
+	for _, descriptor := range idpMetadata.IDPSSODescriptors {
+		for _, key := range descriptor.KeyDescriptors {
+			if key.Use != "encryption" && len(key.KeyInfo.X509Data.X509Certificates) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func attributeValues(assertion *saml.Assertion, name string) []string {
+	values := make([]string, 0)
+	if name == "" {
+		return values
+	}
+
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			if attr.Name == name || attr.FriendlyName == name {
+				for _, value := range attr.Values {
+					if v := strings.TrimSpace(value.Value); v != "" {
+						values = append(values, v)
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+func firstAttributeValue(assertion *saml.Assertion, name string) string {
+	values := attributeValues(assertion, name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
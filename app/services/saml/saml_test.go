@@ -0,0 +1,350 @@
+package saml_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/web"
+	fidersaml "github.com/getfider/fider/app/services/saml"
+)
+
+func newGetContext(rawurl string) *web.Context {
+	u, _ := url.Parse(rawurl)
+	e := web.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", u.RequestURI(), nil)
+	req.Host = u.Host
+
+	if u.Scheme == "https" {
+		req.TLS = &tls.ConnectionState{}
+	}
+
+	return web.NewContext(e, req, res, nil)
+}
+
+// newIdentityProvider creates a local IdP with a freshly generated self-signed certificate
+func newIdentityProvider(t *testing.T) *saml.IdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).IsNil()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).IsNil()
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).IsNil()
+
+	metadataURL, _ := url.Parse("https://idp.test/metadata")
+	ssoURL, _ := url.Parse("https://idp.test/sso")
+	return &saml.IdentityProvider{
+		Key:         key,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+}
+
+func idpMetadata(idp *saml.IdentityProvider) string {
+	metadata, _ := xml.Marshal(idp.Metadata())
+	return string(metadata)
+}
+
+func mockSAMLConfig(config *entity.SAMLConfig) {
+	bus.AddHandler(func(ctx context.Context, q *query.GetSAMLConfig) error {
+		if config == nil {
+			return app.ErrNotFound
+		}
+		q.Result = config
+		return nil
+	})
+}
+
+// newSAMLResponse creates a signed SAML response from given IdP to the SP of current tenant
+func newSAMLResponse(ctx context.Context, idp *saml.IdentityProvider, requestID string, session *saml.Session) string {
+	spMetadata := &query.GetSAMLServiceProviderMetadata{}
+	Expect(bus.Dispatch(ctx, spMetadata)).IsNil()
+
+	sp := &saml.EntityDescriptor{}
+	Expect(xml.Unmarshal([]byte(spMetadata.Result), sp)).IsNil()
+
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest("GET", "https://idp.test/sso", nil),
+		Now:                     saml.TimeNow(),
+		Request:                 saml.AuthnRequest{ID: requestID, IssueInstant: saml.TimeNow()},
+		ServiceProviderMetadata: sp,
+		SPSSODescriptor:         &sp.SPSSODescriptors[0],
+		ACSEndpoint:             &sp.SPSSODescriptors[0].AssertionConsumerServices[0],
+	}
+	Expect(saml.DefaultAssertionMaker{}.MakeAssertion(req, session)).IsNil()
+
+	form, err := req.PostBinding()
+	Expect(err).IsNil()
+	return form.SAMLResponse
+}
+
+func newSAMLState(requestID, redirect string) string {
+	state, _ := jwt.Encode(jwt.SAMLStateClaims{
+		RequestID: requestID,
+		Redirect:  redirect,
+		Metadata: jwt.Metadata{
+			ExpiresAt: jwt.Time(time.Now().Add(10 * time.Minute)),
+		},
+	})
+	return state
+}
+
+func newConfig(idp *saml.IdentityProvider) *entity.SAMLConfig {
+	return &entity.SAMLConfig{
+		Status:                 enum.OAuthConfigEnabled,
+		DisplayName:            "Company SSO",
+		IdPMetadata:            idpMetadata(idp),
+		AttributeName:          "cn",
+		AttributeEmail:         "mail",
+		AttributeRole:          "eduPersonAffiliation",
+		AdministratorRoleValue: "fider-admins",
+		CollaboratorRoleValue:  "fider-staff",
+	}
+}
+
+var jonSession = &saml.Session{
+	ID:             "session-1",
+	NameID:         "00u1jon",
+	UserEmail:      "Jon.Snow@got.com",
+	UserCommonName: "Jon Snow",
+	Groups:         []string{"everyone", "fider-staff"},
+}
+
+func TestGetSAMLServiceProviderMetadata(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.GetSAMLServiceProviderMetadata{}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNil()
+	Expect(q.Result).ContainsSubstring(`entityID="https://demo.test.fider.io:3000/saml/metadata"`)
+	Expect(q.Result).ContainsSubstring(`Location="https://demo.test.fider.io:3000/saml/acs"`)
+}
+
+func TestParseSAMLIdPMetadata(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	q := &query.ParseSAMLIdPMetadata{Metadata: idpMetadata(idp)}
+	err := bus.Dispatch(context.Background(), q)
+	Expect(err).IsNil()
+	Expect(q.Result.EntityID).Equals("https://idp.test/metadata")
+	Expect(q.Result.SSOURL).Equals("https://idp.test/sso")
+
+	q = &query.ParseSAMLIdPMetadata{Metadata: "<html></html>"}
+	err = bus.Dispatch(context.Background(), q)
+	Expect(err).IsNotNil()
+	Expect(q.Result).IsNil()
+}
+
+func TestGetSAMLAuthnRequestURL(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	mockSAMLConfig(newConfig(idp))
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.GetSAMLAuthnRequestURL{Redirect: "https://demo.test.fider.io:3000/posts/1"}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNil()
+
+	authURL, _ := url.Parse(q.Result)
+	Expect(authURL.Host).Equals("idp.test")
+	Expect(authURL.Path).Equals("/sso")
+	Expect(authURL.Query().Get("SAMLRequest")).IsNotEmpty()
+
+	state, err := jwt.DecodeSAMLStateClaims(authURL.Query().Get("RelayState"))
+	Expect(err).IsNil()
+	Expect(state.RequestID).IsNotEmpty()
+	Expect(state.Redirect).Equals("https://demo.test.fider.io:3000/posts/1")
+}
+
+func TestGetSAMLAuthnRequestURL_Disabled(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	config := newConfig(newIdentityProvider(t))
+	config.Status = enum.OAuthConfigDisabled
+	mockSAMLConfig(config)
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.GetSAMLAuthnRequestURL{}
+	err := bus.Dispatch(ctx, q)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestParseSAMLResponse_SPInitiated(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	mockSAMLConfig(newConfig(idp))
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, idp, "id-request-1", jonSession),
+		RelayState:   newSAMLState("id-request-1", "https://demo.test.fider.io:3000/posts/1"),
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNil()
+	Expect(q.Result.ID).Equals("00u1jon")
+	Expect(q.Result.Name).Equals("Jon Snow")
+	Expect(q.Result.Email).Equals("jon.snow@got.com")
+	Expect(q.Result.Role).Equals(enum.RoleCollaborator)
+	Expect(q.Result.Redirect).Equals("https://demo.test.fider.io:3000/posts/1")
+}
+
+func TestParseSAMLResponse_SPInitiated_WrongRequestID(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	mockSAMLConfig(newConfig(idp))
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, idp, "id-request-1", jonSession),
+		RelayState:   newSAMLState("id-request-2", ""),
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNotNil()
+	Expect(q.Result).IsNil()
+}
+
+func TestParseSAMLResponse_IdPInitiated(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	config := newConfig(idp)
+	config.AttributeRole = ""
+	config.AllowIdPInitiated = true
+	mockSAMLConfig(config)
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, idp, "", jonSession),
+		RelayState:   "/posts/2",
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNil()
+	Expect(q.Result.ID).Equals("00u1jon")
+	Expect(q.Result.Email).Equals("jon.snow@got.com")
+	Expect(q.Result.Role).Equals(enum.Role(0))
+	Expect(q.Result.Redirect).Equals("/posts/2")
+}
+
+func TestParseSAMLResponse_IdPInitiated_Disabled(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	mockSAMLConfig(newConfig(idp))
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, idp, "", jonSession),
+		RelayState:   "/posts/2",
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNotNil()
+	Expect(q.Result).IsNil()
+}
+
+func TestParseSAMLResponse_NameIDAsEmail(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	config := newConfig(idp)
+	config.AttributeName = ""
+	config.AttributeEmail = ""
+	config.AllowIdPInitiated = true
+	mockSAMLConfig(config)
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, idp, "", &saml.Session{ID: "session-2", NameID: "arya@got.com", Groups: []string{"everyone"}}),
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNil()
+	Expect(q.Result.ID).Equals("arya@got.com")
+	Expect(q.Result.Name).Equals("arya")
+	Expect(q.Result.Email).Equals("arya@got.com")
+	Expect(q.Result.Role).Equals(enum.RoleVisitor)
+}
+
+func TestParseSAMLResponse_TamperedAssertion(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	config := newConfig(idp)
+	config.AllowIdPInitiated = true
+	mockSAMLConfig(config)
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	response, _ := base64.StdEncoding.DecodeString(newSAMLResponse(ctx, idp, "", jonSession))
+	tampered := strings.ReplaceAll(string(response), "fider-staff", "fider-admins")
+
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: base64.StdEncoding.EncodeToString([]byte(tampered)),
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNotNil()
+	Expect(q.Result).IsNil()
+}
+
+func TestParseSAMLResponse_UntrustedIdP(t *testing.T) {
+	RegisterT(t)
+	bus.Init(fidersaml.Service{})
+
+	idp := newIdentityProvider(t)
+	config := newConfig(idp)
+	config.AllowIdPInitiated = true
+	mockSAMLConfig(config)
+
+	// Same IdP identity, but signed with a different key
+	attacker := newIdentityProvider(t)
+
+	ctx := newGetContext("https://demo.test.fider.io:3000")
+	q := &query.ParseSAMLResponse{
+		SAMLResponse: newSAMLResponse(ctx, attacker, "", jonSession),
+	}
+	err := bus.Dispatch(ctx, q)
+	Expect(err).IsNotNil()
+	Expect(q.Result).IsNil()
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbAPIToken struct {
+	ID         int          `db:"id"`
+	Name       string       `db:"name"`
+	Prefix     string       `db:"prefix"`
+	Scopes     []string     `db:"scopes"`
+	ExpiresAt  dbx.NullTime `db:"expires_at"`
+	LastUsedAt dbx.NullTime `db:"last_used_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+	User       *dbUser      `db:"user"`
+}
+
+func (t *dbAPIToken) toModel(ctx context.Context) *entity.APIToken {
+	token := &entity.APIToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt,
+		User:      t.User.toModel(ctx),
+	}
+	if t.ExpiresAt.Valid {
+		token.ExpiresAt = &t.ExpiresAt.Time
+	}
+	if t.LastUsedAt.Valid {
+		token.LastUsedAt = &t.LastUsedAt.Time
+	}
+	return token
+}
+
+const sqlSelectAPITokens = `
+	SELECT t.id, t.name, t.prefix, t.scopes, t.expires_at, t.last_used_at, t.created_at,
+		u.id AS user_id,
+		u.name AS user_name,
+		u.email AS user_email,
+		u.tenant_id AS user_tenant_id,
+		u.role AS user_role,
+		u.status AS user_status,
+		u.avatar_type AS user_avatar_type,
+		u.avatar_bkey AS user_avatar_bkey,
+		u.custom_role_id AS user_custom_role_id
+	FROM api_tokens t
+	INNER JOIN users u
+	ON u.id = t.user_id
+	AND u.tenant_id = t.tenant_id
+	WHERE t.tenant_id = $1
+	AND t.revoked_at IS NULL`
+
+func getAPITokenByKey(ctx context.Context, q *query.GetAPITokenByKey) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := dbAPIToken{}
+		err := trx.Get(&token, sqlSelectAPITokens+" AND t.key_hash = $2", tenant.ID, crypto.SHA512(q.Key))
+		if err != nil {
+			return errors.Wrap(err, "failed to get API token by key")
+		}
+
+		if err := loadCustomRoles(trx, tenant.ID, token.User); err != nil {
+			return err
+		}
+
+		if err := loadUserGroups(trx, tenant.ID, token.User); err != nil {
+			return err
+		}
+
+		q.Result = token.toModel(ctx)
+		return nil
+	})
+}
+
+func getAPITokenByID(ctx context.Context, q *query.GetAPITokenByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := dbAPIToken{}
+		err := trx.Get(&token, sqlSelectAPITokens+" AND t.id = $2", tenant.ID, q.TokenID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get API token with id '%d'", q.TokenID)
+		}
+
+		if err := loadCustomRoles(trx, tenant.ID, token.User); err != nil {
+			return err
+		}
+
+		if err := loadUserGroups(trx, tenant.ID, token.User); err != nil {
+			return err
+		}
+
+		q.Result = token.toModel(ctx)
+		return nil
+	})
+}
+
+func getCurrentUserAPITokens(ctx context.Context, q *query.GetCurrentUserAPITokens) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		tokens := []*dbAPIToken{}
+		err := trx.Select(&tokens, sqlSelectAPITokens+" AND t.user_id = $2 ORDER BY t.created_at DESC", tenant.ID, user.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get API tokens of current user")
+		}
+
+		q.Result = make([]*entity.APIToken, len(tokens))
+		for i, token := range tokens {
+			q.Result[i] = token.toModel(ctx)
+		}
+		return nil
+	})
+}
+
+func getAllAPITokens(ctx context.Context, q *query.GetAllAPITokens) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		tokens := []*dbAPIToken{}
+		err := trx.Select(&tokens, sqlSelectAPITokens+" ORDER BY t.created_at DESC", tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all API tokens")
+		}
+
+		q.Result = make([]*entity.APIToken, len(tokens))
+		for i, token := range tokens {
+			q.Result[i] = token.toModel(ctx)
+		}
+		return nil
+	})
+}
+
+func addNewAPIToken(ctx context.Context, c *cmd.AddNewAPIToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Only a hash of the key is stored, the key itself is shown once to its owner
+		key := entity.GenerateAPITokenKey()
+		prefix := key[:len(entity.APITokenKeyPrefix)+8]
+
+		token := dbAPIToken{}
+		err := trx.Get(&token, `
+			INSERT INTO api_tokens (tenant_id, user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, name, prefix, scopes, expires_at, last_used_at, created_at
+		`, tenant.ID, user.ID, c.Name, prefix, crypto.SHA512(key), pq.Array(c.Scopes), c.ExpiresAt, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to add new API token")
+		}
+
+		c.Key = key
+		c.Result = token.toModel(ctx)
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditAPITokenCreated,
+			TargetType: "api_token",
+			TargetID:   token.ID,
+			TargetName: token.Name,
+			After:      apiTokenAuditValues(&token, user.ID),
+		})
+	})
+}
+
+func revokeAPIToken(ctx context.Context, c *cmd.RevokeAPIToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := dbAPIToken{}
+		err := trx.Get(&token, sqlSelectAPITokens+" AND t.id = $2", tenant.ID, c.TokenID)
+		if err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get API token with id '%d'", c.TokenID)
+		}
+
+		_, err = trx.Execute(`
+			UPDATE api_tokens SET revoked_at = $1 WHERE id = $2 AND tenant_id = $3 AND revoked_at IS NULL
+		`, time.Now(), c.TokenID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke API token with id '%d'", c.TokenID)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditAPITokenRevoked,
+			TargetType: "api_token",
+			TargetID:   token.ID,
+			TargetName: token.Name,
+			Before:     apiTokenAuditValues(&token, int(token.User.ID.Int64)),
+		})
+	})
+}
+
+func apiTokenAuditValues(token *dbAPIToken, ownerID int) map[string]any {
+	values := map[string]any{
+		"userId": ownerID,
+		"scopes": token.Scopes,
+	}
+	if token.ExpiresAt.Valid {
+		values["expiresAt"] = token.ExpiresAt.Time
+	}
+	return values
+}
+
+func markAPITokenAsUsed(ctx context.Context, c *cmd.MarkAPITokenAsUsed) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Avoid a write on every request by only tracking usage once per minute
+		_, err := trx.Execute(`
+			UPDATE api_tokens SET last_used_at = $1
+			WHERE id = $2 AND tenant_id = $3
+			AND (last_used_at IS NULL OR last_used_at < $1 - INTERVAL '1 minute')
+		`, time.Now(), c.TokenID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark API token with id '%d' as used", c.TokenID)
+		}
+		return nil
+	})
+}
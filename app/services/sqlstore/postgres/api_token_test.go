@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestAPITokenStorage_AddGetAndRevoke(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	addNewToken := &cmd.AddNewAPIToken{Name: "Zapier", Scopes: []string{entity.APIScopePostsRead}, ExpiresAt: &expiresAt}
+	err := bus.Dispatch(jonSnowCtx, addNewToken)
+	Expect(err).IsNil()
+	Expect(strings.HasPrefix(addNewToken.Key, entity.APITokenKeyPrefix)).IsTrue()
+	Expect(strings.HasPrefix(addNewToken.Key, addNewToken.Result.Prefix)).IsTrue()
+
+	getByKey := &query.GetAPITokenByKey{Key: addNewToken.Key}
+	err = bus.Dispatch(jonSnowCtx, getByKey)
+	Expect(err).IsNil()
+	Expect(getByKey.Result.Name).Equals("Zapier")
+	Expect(getByKey.Result.User.ID).Equals(jonSnow.ID)
+	Expect(getByKey.Result.ExpiresAt).IsNotNil()
+	Expect(getByKey.Result.LastUsedAt).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.MarkAPITokenAsUsed{TokenID: getByKey.Result.ID})
+	Expect(err).IsNil()
+
+	getMyTokens := &query.GetCurrentUserAPITokens{}
+	err = bus.Dispatch(jonSnowCtx, getMyTokens)
+	Expect(err).IsNil()
+	Expect(getMyTokens.Result).HasLen(1)
+	Expect(getMyTokens.Result[0].LastUsedAt).IsNotNil()
+
+	getMyTokens = &query.GetCurrentUserAPITokens{}
+	err = bus.Dispatch(aryaStarkCtx, getMyTokens)
+	Expect(err).IsNil()
+	Expect(getMyTokens.Result).HasLen(0)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeAPIToken{TokenID: getByKey.Result.ID})
+	Expect(err).IsNil()
+
+	getByKey = &query.GetAPITokenByKey{Key: addNewToken.Key}
+	err = bus.Dispatch(jonSnowCtx, getByKey)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
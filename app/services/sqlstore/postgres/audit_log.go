@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/web"
+)
+
+type dbAuditLogEntry struct {
+	ID         int            `db:"id"`
+	Action     string         `db:"action"`
+	ActorID    dbx.NullInt    `db:"actor_id"`
+	ActorName  string         `db:"actor_name"`
+	TargetType string         `db:"target_type"`
+	TargetID   dbx.NullInt    `db:"target_id"`
+	TargetName string         `db:"target_name"`
+	Before     dbx.NullString `db:"before"`
+	After      dbx.NullString `db:"after"`
+	IPAddress  string         `db:"ip_address"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (e *dbAuditLogEntry) toModel() *entity.AuditLogEntry {
+	entry := &entity.AuditLogEntry{
+		ID:         e.ID,
+		Action:     e.Action,
+		ActorID:    int(e.ActorID.Int64),
+		ActorName:  e.ActorName,
+		TargetType: e.TargetType,
+		TargetID:   int(e.TargetID.Int64),
+		TargetName: e.TargetName,
+		IPAddress:  e.IPAddress,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.Before.Valid {
+		_ = json.Unmarshal([]byte(e.Before.String), &entry.Before)
+	}
+	if e.After.Valid {
+		_ = json.Unmarshal([]byte(e.After.String), &entry.After)
+	}
+	return entry
+}
+
+// auditEntry describes a change to be recorded by insertAuditLog
+type auditEntry struct {
+	Action     string
+	TargetType string
+	TargetID   int
+	TargetName string
+	Before     map[string]any
+	After      map[string]any
+}
+
+func insertAuditLog(ctx context.Context, trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, entry auditEntry) error {
+	var actorID any
+	actorName := ""
+	if user != nil {
+		actorID = user.ID
+		actorName = user.Name
+	}
+
+	var targetID any
+	if entry.TargetID > 0 {
+		targetID = entry.TargetID
+	}
+
+	before, err := toAuditJSON(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := toAuditJSON(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = trx.Execute(`
+		INSERT INTO audit_logs (tenant_id, action, actor_id, actor_name, target_type, target_id, target_name, before, after, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, tenant.ID, entry.Action, actorID, truncate(actorName, 100), entry.TargetType, targetID,
+		truncate(entry.TargetName, 200), before, after, truncate(web.ClientIP(ctx), 50), time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to add audit log entry '%s'", entry.Action)
+	}
+	return nil
+}
+
+// auditUser is the state of a user right before an administrative change
+type auditUser struct {
+	Name         string          `db:"name"`
+	Role         enum.Role       `db:"role"`
+	Status       enum.UserStatus `db:"status"`
+	CustomRoleID dbx.NullInt     `db:"custom_role_id"`
+}
+
+func getAuditUser(trx *dbx.Trx, tenant *entity.Tenant, userID int) (*auditUser, error) {
+	target := &auditUser{}
+	err := trx.Get(target, "SELECT name, role, status, custom_role_id FROM users WHERE id = $1 AND tenant_id = $2", userID, tenant.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user with id '%d'", userID)
+	}
+	return target, nil
+}
+
+func toAuditJSON(values map[string]any) (any, error) {
+	if values == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal audit log values")
+	}
+	return string(bytes), nil
+}
+
+func searchAuditLogs(ctx context.Context, q *query.SearchAuditLogs) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		conditions := []string{"tenant_id = $1"}
+		params := []any{tenant.ID}
+
+		if q.Action != "" {
+			params = append(params, q.Action)
+			conditions = append(conditions, fmt.Sprintf("action = $%d", len(params)))
+		}
+		if q.ActorID > 0 {
+			params = append(params, q.ActorID)
+			conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(params)))
+		}
+		if q.TargetType != "" {
+			params = append(params, q.TargetType)
+			conditions = append(conditions, fmt.Sprintf("target_type = $%d", len(params)))
+		}
+		if q.Since != nil {
+			params = append(params, *q.Since)
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(params)))
+		}
+		if q.Until != nil {
+			params = append(params, *q.Until)
+			conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(params)))
+		}
+
+		limit := q.Limit
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		entries := []*dbAuditLogEntry{}
+		err := trx.Select(&entries, fmt.Sprintf(`
+			SELECT id, action, actor_id, actor_name, target_type, target_id, target_name, before, after, ip_address, created_at
+			FROM audit_logs
+			WHERE %s
+			ORDER BY id DESC
+			LIMIT %d
+		`, strings.Join(conditions, " AND "), limit), params...)
+		if err != nil {
+			return errors.Wrap(err, "failed to search audit logs")
+		}
+
+		q.Result = make([]*entity.AuditLogEntry, len(entries))
+		for i, entry := range entries {
+			q.Result[i] = entry.toModel()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestAuditLogStorage_RecordsAdministrativeChanges(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.ChangeUserRole{UserID: aryaStark.ID, Role: enum.RoleCollaborator})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.BlockUser{UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	search := &query.SearchAuditLogs{}
+	err = bus.Dispatch(jonSnowCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(2)
+
+	Expect(search.Result[0].Action).Equals(entity.AuditUserBlocked)
+	Expect(search.Result[0].ActorID).Equals(jonSnow.ID)
+	Expect(search.Result[0].ActorName).Equals(jonSnow.Name)
+	Expect(search.Result[0].TargetID).Equals(aryaStark.ID)
+	Expect(search.Result[0].Before["status"]).Equals("active")
+	Expect(search.Result[0].After["status"]).Equals("blocked")
+
+	Expect(search.Result[1].Action).Equals(entity.AuditUserRoleChanged)
+	Expect(search.Result[1].Before["role"]).Equals("visitor")
+	Expect(search.Result[1].After["role"]).Equals("collaborator")
+}
+
+func TestAuditLogStorage_RecordsAccessChanges(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addGroup := &cmd.AddNewUserGroup{Name: "Customers", EmailDomains: []string{}}
+	err := bus.Dispatch(jonSnowCtx, addGroup)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddUserToGroup{GroupID: addGroup.Result.ID, UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	addToken := &cmd.AddNewAPIToken{Name: "CI", Scopes: []string{entity.APIScopePostsRead}}
+	err = bus.Dispatch(jonSnowCtx, addToken)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeAPIToken{TokenID: addToken.Result.ID})
+	Expect(err).IsNil()
+
+	search := &query.SearchAuditLogs{}
+	err = bus.Dispatch(jonSnowCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(4)
+
+	Expect(search.Result[0].Action).Equals(entity.AuditAPITokenRevoked)
+	Expect(search.Result[0].TargetName).Equals("CI")
+	Expect(search.Result[1].Action).Equals(entity.AuditAPITokenCreated)
+	Expect(search.Result[2].Action).Equals(entity.AuditUserGroupMemberAdded)
+	Expect(search.Result[2].TargetID).Equals(aryaStark.ID)
+	Expect(search.Result[2].After["groupName"]).Equals("Customers")
+	Expect(search.Result[3].Action).Equals(entity.AuditUserGroupCreated)
+	Expect(search.Result[3].TargetName).Equals("Customers")
+}
+
+func TestAuditLogStorage_FilterByAction(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.UpdateTenantPrivacySettings{IsPrivate: true})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.BlockUser{UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	search := &query.SearchAuditLogs{Action: entity.AuditTenantPrivacyUpdated}
+	err = bus.Dispatch(jonSnowCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(1)
+	Expect(search.Result[0].Before["isPrivate"]).Equals(false)
+	Expect(search.Result[0].After["isPrivate"]).Equals(true)
+
+	search = &query.SearchAuditLogs{ActorID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(0)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/realtime"
 )
 
 type dbComment struct {
@@ -21,6 +22,7 @@ type dbComment struct {
 	EditedAt       dbx.NullTime   `db:"edited_at"`
 	EditedBy       *dbUser        `db:"edited_by"`
 	ReactionCounts dbx.NullString `db:"reaction_counts"`
+	IsInternal     bool           `db:"is_internal"`
 }
 
 func (c *dbComment) toModel(ctx context.Context) *entity.Comment {
@@ -30,6 +32,7 @@ func (c *dbComment) toModel(ctx context.Context) *entity.Comment {
 		CreatedAt:   c.CreatedAt,
 		User:        c.User.toModel(ctx),
 		Attachments: c.Attachments,
+		IsInternal:  c.IsInternal,
 	}
 	if c.EditedAt.Valid {
 		comment.EditedBy = c.EditedBy.toModel(ctx)
@@ -46,20 +49,32 @@ func addNewComment(ctx context.Context, c *cmd.AddNewComment) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		var id int
 		if err := trx.Get(&id, `
-			INSERT INTO comments (tenant_id, post_id, content, user_id, created_at) 
-			VALUES ($1, $2, $3, $4, $5) 
+			INSERT INTO comments (tenant_id, post_id, content, user_id, created_at, is_internal) 
+			VALUES ($1, $2, $3, $4, $5, $6) 
 			RETURNING id
-		`, tenant.ID, c.Post.ID, c.Content, user.ID, time.Now()); err != nil {
+		`, tenant.ID, c.Post.ID, c.Content, user.ID, time.Now(), c.IsInternal); err != nil {
 			return errors.Wrap(err, "failed add new comment")
 		}
 
+		if err := insertCommentRevision(trx, tenant, user, id, c.Content); err != nil {
+			return err
+		}
+
 		q := &query.GetCommentByID{CommentID: id}
 		if err := getCommentByID(ctx, q); err != nil {
 			return err
 		}
 		c.Result = q.Result
 
-		return nil
+		return notifyRealtime(trx, tenant, &realtime.Event{
+			Type: realtime.CommentCreated,
+			Data: map[string]any{
+				"postNumber": c.Post.Number,
+				"commentId":  id,
+			},
+			StaffOnly: c.IsInternal,
+			Post:      realtime.VisibilityOf(c.Post),
+		})
 	})
 }
 
@@ -103,7 +118,8 @@ func updateComment(ctx context.Context, c *cmd.UpdateComment) error {
 		if err != nil {
 			return errors.Wrap(err, "failed update comment")
 		}
-		return nil
+
+		return insertCommentRevision(trx, tenant, user, c.CommentID, c.Content)
 	})
 }
 
@@ -129,6 +145,7 @@ func getCommentByID(ctx context.Context, q *query.GetCommentByID) error {
 							c.content, 
 							c.created_at, 
 							c.edited_at, 
+							c.is_internal,
 							u.id AS user_id, 
 							u.name AS user_name,
 							u.email AS user_email,
@@ -152,7 +169,8 @@ func getCommentByID(ctx context.Context, q *query.GetCommentByID) error {
 			AND e.tenant_id = c.tenant_id
 			WHERE c.id = $1
 			AND c.tenant_id = $2
-			AND c.deleted_at IS NULL`, q.CommentID, tenant.ID)
+			AND c.deleted_at IS NULL
+			AND (c.is_internal = false OR $3)`, q.CommentID, tenant.ID, canSeeInternalComments(user))
 
 		if err != nil {
 			return err
@@ -212,6 +230,7 @@ func getCommentsByPost(ctx context.Context, q *query.GetCommentsByPost) error {
 					c.content, 
 					c.created_at, 
 					c.edited_at, 
+					c.is_internal,
 					u.id AS user_id, 
 					u.name AS user_name,
 					u.email AS user_email,
@@ -245,7 +264,8 @@ func getCommentsByPost(ctx context.Context, q *query.GetCommentsByPost) error {
 			WHERE p.id = $1
 			AND p.tenant_id = $2
 			AND c.deleted_at IS NULL
-			ORDER BY c.created_at ASC`, q.Post.ID, tenant.ID, userId)
+			AND (c.is_internal = false OR $4)
+			ORDER BY c.created_at ASC`, q.Post.ID, tenant.ID, userId, canSeeInternalComments(user))
 		if err != nil {
 			return errors.Wrap(err, "failed get comments of post with id '%d'", q.Post.ID)
 		}
@@ -257,3 +277,7 @@ func getCommentsByPost(ctx context.Context, q *query.GetCommentsByPost) error {
 		return nil
 	})
 }
+
+func canSeeInternalComments(user *entity.User) bool {
+	return user != nil && user.IsCollaborator()
+}
@@ -49,7 +49,10 @@ func getViewData(query query.SearchPosts) (string, []enum.PostStatus, string) {
 	case "recent":
 		sort = "id"
 	case "most-wanted":
-		sort = "votes_count"
+		sort = "votes_weight"
+	case "most-revenue":
+		// Revenue is only known to staff, everyone else falls back to most wanted
+		sort = "votes_revenue DESC, votes_weight"
 	case "most-discussed":
 		sort = "comments_count"
 	case "my-votes":
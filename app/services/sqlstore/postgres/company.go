@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbCompany struct {
+	ID         int    `db:"id"`
+	Name       string `db:"name"`
+	MRR        int    `db:"mrr"`
+	Plan       string `db:"plan"`
+	UsersCount int    `db:"users_count"`
+}
+
+func (c *dbCompany) toModel() *entity.Company {
+	return &entity.Company{
+		ID:         c.ID,
+		Name:       c.Name,
+		MRR:        c.MRR,
+		Plan:       c.Plan,
+		UsersCount: c.UsersCount,
+	}
+}
+
+const sqlSelectCompanies = `
+	SELECT c.id, c.name, c.mrr, c.plan, 
+	(SELECT COUNT(*) FROM users u WHERE u.company_id = c.id AND u.tenant_id = c.tenant_id) AS users_count
+	FROM companies c`
+
+func getCompanyByID(ctx context.Context, q *query.GetCompanyByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		company := dbCompany{}
+		err := trx.Get(&company, sqlSelectCompanies+" WHERE c.tenant_id = $1 AND c.id = $2", tenant.ID, q.CompanyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get company with id '%d'", q.CompanyID)
+		}
+
+		q.Result = company.toModel()
+		return nil
+	})
+}
+
+func getCompanyByName(ctx context.Context, q *query.GetCompanyByName) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		company := dbCompany{}
+		err := trx.Get(&company, sqlSelectCompanies+" WHERE c.tenant_id = $1 AND LOWER(c.name) = LOWER($2)", tenant.ID, q.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to get company with name '%s'", q.Name)
+		}
+
+		q.Result = company.toModel()
+		return nil
+	})
+}
+
+func getAllCompanies(ctx context.Context, q *query.GetAllCompanies) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		companies := []*dbCompany{}
+		err := trx.Select(&companies, sqlSelectCompanies+" WHERE c.tenant_id = $1 ORDER BY c.name", tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all companies")
+		}
+
+		q.Result = make([]*entity.Company, len(companies))
+		for i, company := range companies {
+			q.Result[i] = company.toModel()
+		}
+		return nil
+	})
+}
+
+func addNewCompany(ctx context.Context, c *cmd.AddNewCompany) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var id int
+		err := trx.Get(&id, `
+			INSERT INTO companies (tenant_id, name, mrr, plan, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			RETURNING id
+		`, tenant.ID, c.Name, c.MRR, c.Plan)
+		if err != nil {
+			return errors.Wrap(err, "failed to add new company")
+		}
+
+		c.Result = &entity.Company{ID: id, Name: c.Name, MRR: c.MRR, Plan: c.Plan}
+		return nil
+	})
+}
+
+func updateCompany(ctx context.Context, c *cmd.UpdateCompany) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE companies SET name = $1, mrr = $2, plan = $3
+			WHERE id = $4 AND tenant_id = $5
+		`, c.Name, c.MRR, c.Plan, c.CompanyID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update company with id '%d'", c.CompanyID)
+		}
+
+		company := dbCompany{}
+		err = trx.Get(&company, sqlSelectCompanies+" WHERE c.tenant_id = $1 AND c.id = $2", tenant.ID, c.CompanyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get company with id '%d'", c.CompanyID)
+		}
+
+		c.Result = company.toModel()
+		return nil
+	})
+}
+
+func deleteCompany(ctx context.Context, c *cmd.DeleteCompany) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`UPDATE users SET company_id = NULL WHERE company_id = $1 AND tenant_id = $2`, c.CompanyID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove users from company with id '%d'", c.CompanyID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM companies WHERE id = $1 AND tenant_id = $2`, c.CompanyID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete company with id '%d'", c.CompanyID)
+		}
+		return nil
+	})
+}
+
+func setUserCompany(ctx context.Context, c *cmd.SetUserCompany) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		companyID := sql.NullInt64{Int64: int64(c.CompanyID), Valid: c.CompanyID > 0}
+		_, err := trx.Execute(`UPDATE users SET company_id = $1 WHERE id = $2 AND tenant_id = $3`, companyID, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to set company of user with id '%d'", c.UserID)
+		}
+		return nil
+	})
+}
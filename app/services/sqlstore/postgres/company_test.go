@@ -0,0 +1,115 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestCompanyStorage_AddUpdateAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewCompany := &cmd.AddNewCompany{Name: "Acme Inc.", MRR: 500, Plan: "Pro"}
+	err := bus.Dispatch(jonSnowCtx, addNewCompany)
+	Expect(err).IsNil()
+	Expect(addNewCompany.Result.ID).IsNotEmpty()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetUserCompany{UserID: aryaStark.ID, CompanyID: addNewCompany.Result.ID})
+	Expect(err).IsNil()
+
+	updateCompany := &cmd.UpdateCompany{CompanyID: addNewCompany.Result.ID, Name: "Acme", MRR: 1500, Plan: "Enterprise"}
+	err = bus.Dispatch(jonSnowCtx, updateCompany)
+	Expect(err).IsNil()
+	Expect(updateCompany.Result.UsersCount).Equals(1)
+
+	getCompany := &query.GetCompanyByName{Name: "ACME"}
+	err = bus.Dispatch(jonSnowCtx, getCompany)
+	Expect(err).IsNil()
+	Expect(getCompany.Result.MRR).Equals(1500)
+	Expect(getCompany.Result.Plan).Equals("Enterprise")
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteCompany{CompanyID: getCompany.Result.ID})
+	Expect(err).IsNil()
+
+	getByID := &query.GetCompanyByID{CompanyID: getCompany.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestCompanyStorage_ProxyVotesAndRevenue(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	acme := &cmd.AddNewCompany{Name: "Acme Inc.", MRR: 500}
+	err = bus.Dispatch(jonSnowCtx, acme)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx,
+		&cmd.SetUserCompany{UserID: aryaStark.ID, CompanyID: acme.Result.ID},
+		&cmd.SetUserCompany{UserID: jonSnow.ID, CompanyID: acme.Result.ID},
+		&cmd.AddVote{Post: newPost.Result, User: aryaStark, ProxiedBy: jonSnow, Note: "Asked on a call"},
+		&cmd.AddVote{Post: newPost.Result, User: jonSnow},
+	)
+	Expect(err).IsNil()
+
+	// A company is counted once even when many of its users voted
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.VotesRevenue).Equals(500)
+
+	getPost = &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.VotesRevenue).Equals(0)
+
+	listVotes := &query.ListPostVotes{PostID: newPost.Result.ID, IncludeEmail: true}
+	err = bus.Dispatch(jonSnowCtx, listVotes)
+	Expect(err).IsNil()
+	Expect(listVotes.Result).HasLen(2)
+	Expect(listVotes.Result[0].User.ID).Equals(aryaStark.ID)
+	Expect(listVotes.Result[0].User.Company.Name).Equals("Acme Inc.")
+	Expect(listVotes.Result[0].ProxiedBy.ID).Equals(jonSnow.ID)
+	Expect(listVotes.Result[0].Note).Equals("Asked on a call")
+	Expect(listVotes.Result[1].ProxiedBy).IsNil()
+
+	listVotes = &query.ListPostVotes{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listVotes)
+	Expect(err).IsNil()
+	Expect(listVotes.Result[0].User.Company).IsNil()
+	Expect(listVotes.Result[0].ProxiedBy).IsNil()
+	Expect(listVotes.Result[0].Note).Equals("")
+}
+
+func TestCompanyStorage_ProxyVoteOnExistingVote(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddVote{Post: newPost.Result, User: aryaStark})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: aryaStark, ProxiedBy: jonSnow, Note: "Asked on a call"})
+	Expect(err).IsNil()
+
+	listVotes := &query.ListPostVotes{PostID: newPost.Result.ID, IncludeEmail: true}
+	err = bus.Dispatch(jonSnowCtx, listVotes)
+	Expect(err).IsNil()
+	Expect(listVotes.Result).HasLen(1)
+	Expect(listVotes.Result[0].Weight).Equals(1)
+	Expect(listVotes.Result[0].ProxiedBy.ID).Equals(jonSnow.ID)
+	Expect(listVotes.Result[0].Note).Equals("Asked on a call")
+}
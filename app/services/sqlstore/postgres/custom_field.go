@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/gosimple/slug"
+	"github.com/lib/pq"
+)
+
+type dbCustomField struct {
+	ID       int      `db:"id"`
+	Key      string   `db:"key"`
+	Name     string   `db:"name"`
+	Type     int      `db:"type"`
+	Options  []string `db:"options"`
+	IsPublic bool     `db:"is_public"`
+	Position int      `db:"position"`
+}
+
+func (f *dbCustomField) toModel() *entity.CustomField {
+	return &entity.CustomField{
+		ID:       f.ID,
+		Key:      f.Key,
+		Name:     f.Name,
+		Type:     enum.CustomFieldType(f.Type),
+		Options:  f.Options,
+		IsPublic: f.IsPublic,
+		Position: f.Position,
+	}
+}
+
+const sqlSelectCustomFields = `SELECT f.id, f.key, f.name, f.type, f.options, f.is_public, f.position FROM custom_fields f`
+
+func getCustomFieldByKey(ctx context.Context, q *query.GetCustomFieldByKey) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		field, err := queryCustomFieldByKey(trx, tenant, q.Key)
+		q.Result = field
+		return err
+	})
+}
+
+func getAllCustomFields(ctx context.Context, q *query.GetAllCustomFields) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		fields, err := queryVisibleCustomFields(trx, tenant, user)
+		if err != nil {
+			return errors.Wrap(err, "failed get all custom fields")
+		}
+
+		q.Result = fields
+		return nil
+	})
+}
+
+func addNewCustomField(ctx context.Context, c *cmd.AddNewCustomField) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		c.Result = nil
+		key := slug.Make(c.Name)
+
+		_, err := trx.Execute(`
+			INSERT INTO custom_fields (tenant_id, key, name, type, options, is_public, position, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(position), 0) + 1 FROM custom_fields WHERE tenant_id = $1), NOW())
+		`, tenant.ID, key, c.Name, c.Type, pq.Array(fieldOptions(c.Type, c.Options)), c.IsPublic)
+		if err != nil {
+			return errors.Wrap(err, "failed to add new custom field")
+		}
+
+		field, err := queryCustomFieldByKey(trx, tenant, key)
+		if err != nil {
+			return err
+		}
+		c.Result = field
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomFieldCreated,
+			TargetType: "custom_field",
+			TargetID:   field.ID,
+			TargetName: field.Name,
+			After:      customFieldAuditValues(field),
+		})
+	})
+}
+
+func updateCustomField(ctx context.Context, c *cmd.UpdateCustomField) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		c.Result = nil
+
+		field := dbCustomField{}
+		err := trx.Get(&field, sqlSelectCustomFields+" WHERE f.tenant_id = $1 AND f.id = $2", tenant.ID, c.FieldID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom field with id '%d'", c.FieldID)
+		}
+
+		previous := field.toModel()
+		options := fieldOptions(enum.CustomFieldType(field.Type), c.Options)
+		_, err = trx.Execute(`
+			UPDATE custom_fields SET name = $1, options = $2, is_public = $3, position = $4
+			WHERE id = $5 AND tenant_id = $6
+		`, c.Name, pq.Array(options), c.IsPublic, c.Position, c.FieldID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update custom field")
+		}
+
+		// Values that refer to options that no longer exist are dropped
+		switch enum.CustomFieldType(field.Type) {
+		case enum.CustomFieldSelect:
+			_, err = trx.Execute(`
+				DELETE FROM post_custom_field_values
+				WHERE tenant_id = $1 AND field_id = $2 AND NOT (value #>> '{}' = ANY($3))
+			`, tenant.ID, c.FieldID, pq.Array(options))
+		case enum.CustomFieldMultiSelect:
+			_, err = trx.Execute(`
+				UPDATE post_custom_field_values
+				SET value = COALESCE((
+					SELECT jsonb_agg(e) FROM jsonb_array_elements_text(value) e WHERE e = ANY($3)
+				), '[]'::jsonb)
+				WHERE tenant_id = $1 AND field_id = $2
+			`, tenant.ID, c.FieldID, pq.Array(options))
+			if err == nil {
+				_, err = trx.Execute(`
+					DELETE FROM post_custom_field_values
+					WHERE tenant_id = $1 AND field_id = $2 AND value = '[]'::jsonb
+				`, tenant.ID, c.FieldID)
+			}
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to remove values of deleted options")
+		}
+
+		field.Name = c.Name
+		field.Options = options
+		field.IsPublic = c.IsPublic
+		field.Position = c.Position
+		c.Result = field.toModel()
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomFieldUpdated,
+			TargetType: "custom_field",
+			TargetID:   field.ID,
+			TargetName: field.Name,
+			Before:     customFieldAuditValues(previous),
+			After:      customFieldAuditValues(c.Result),
+		})
+	})
+}
+
+func deleteCustomField(ctx context.Context, c *cmd.DeleteCustomField) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`DELETE FROM post_custom_field_values WHERE field_id = $1 AND tenant_id = $2`, c.Field.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove custom field with id '%d' from all posts", c.Field.ID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM custom_fields WHERE id = $1 AND tenant_id = $2`, c.Field.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete custom field with id '%d'", c.Field.ID)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomFieldDeleted,
+			TargetType: "custom_field",
+			TargetID:   c.Field.ID,
+			TargetName: c.Field.Name,
+			Before:     customFieldAuditValues(c.Field),
+		})
+	})
+}
+
+func customFieldAuditValues(field *entity.CustomField) map[string]any {
+	return map[string]any{
+		"key":      field.Key,
+		"type":     field.Type,
+		"options":  field.Options,
+		"isPublic": field.IsPublic,
+		"position": field.Position,
+	}
+}
+
+func setPostCustomFieldValues(ctx context.Context, c *cmd.SetPostCustomFieldValues) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if c.Post.CustomFields == nil {
+			c.Post.CustomFields = make(map[string]any)
+		}
+
+		for _, item := range c.Values {
+			if item.Value == nil {
+				_, err := trx.Execute(`
+					DELETE FROM post_custom_field_values WHERE post_id = $1 AND field_id = $2 AND tenant_id = $3
+				`, c.Post.ID, item.Field.ID, tenant.ID)
+				if err != nil {
+					return errors.Wrap(err, "failed to clear custom field '%s' of post", item.Field.Key)
+				}
+				delete(c.Post.CustomFields, item.Field.Key)
+				continue
+			}
+
+			value, err := json.Marshal(item.Value)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal value of custom field '%s'", item.Field.Key)
+			}
+
+			_, err = trx.Execute(`
+				INSERT INTO post_custom_field_values (tenant_id, post_id, field_id, value, updated_at, updated_by_id)
+				VALUES ($1, $2, $3, $4, NOW(), $5)
+				ON CONFLICT (post_id, field_id) DO UPDATE
+				SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at, updated_by_id = EXCLUDED.updated_by_id
+			`, tenant.ID, c.Post.ID, item.Field.ID, string(value), user.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to set custom field '%s' of post", item.Field.Key)
+			}
+			c.Post.CustomFields[item.Field.Key] = item.Value
+		}
+		return nil
+	})
+}
+
+func queryCustomFieldByKey(trx *dbx.Trx, tenant *entity.Tenant, key string) (*entity.CustomField, error) {
+	field := dbCustomField{}
+
+	err := trx.Get(&field, sqlSelectCustomFields+" WHERE f.tenant_id = $1 AND f.key = $2", tenant.ID, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get custom field with key '%s'", key)
+	}
+
+	return field.toModel(), nil
+}
+
+func queryVisibleCustomFields(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) ([]*entity.CustomField, error) {
+	condition := `AND f.is_public = true`
+	if user != nil && user.IsCollaborator() {
+		condition = ``
+	}
+
+	fields := []*dbCustomField{}
+	err := trx.Select(&fields, fmt.Sprintf(`%s WHERE f.tenant_id = $1 %s ORDER BY f.position, f.id`, sqlSelectCustomFields, condition), tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.CustomField, len(fields))
+	for i, field := range fields {
+		result[i] = field.toModel()
+	}
+	return result, nil
+}
+
+// buildCustomFieldsFilter returns a JSON document to be matched against the custom fields of posts
+func buildCustomFieldsFilter(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, filters map[string]string) (string, error) {
+	fields, err := queryVisibleCustomFields(trx, tenant, user)
+	if err != nil {
+		return "", err
+	}
+
+	document := make(map[string]any)
+	for _, field := range fields {
+		text, ok := filters[field.Key]
+		if !ok {
+			continue
+		}
+
+		value, ok := field.ParseString(text)
+		if !ok {
+			// An invalid value can never match, so we keep the raw text
+			value = text
+		}
+		if value != nil {
+			document[field.Key] = value
+		}
+	}
+
+	if len(document) == 0 {
+		return "", nil
+	}
+
+	bytes, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func fieldOptions(fieldType enum.CustomFieldType, options []string) []string {
+	if !fieldType.HasOptions() || options == nil {
+		return []string{}
+	}
+	return options
+}
@@ -0,0 +1,111 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestCustomFieldStorage_AddUpdateAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewField := &cmd.AddNewCustomField{Name: "Customer Segment", Type: enum.CustomFieldSelect, Options: []string{"SMB", "Enterprise"}}
+	err := bus.Dispatch(jonSnowCtx, addNewField)
+	Expect(err).IsNil()
+	Expect(addNewField.Result.Key).Equals("customer-segment")
+	Expect(addNewField.Result.Type).Equals(enum.CustomFieldSelect)
+	Expect(addNewField.Result.IsPublic).IsFalse()
+
+	updateField := &cmd.UpdateCustomField{FieldID: addNewField.Result.ID, Name: "Segment", Options: []string{"Enterprise"}, IsPublic: true}
+	err = bus.Dispatch(jonSnowCtx, updateField)
+	Expect(err).IsNil()
+
+	getField := &query.GetCustomFieldByKey{Key: "customer-segment"}
+	err = bus.Dispatch(jonSnowCtx, getField)
+	Expect(err).IsNil()
+	Expect(getField.Result.Name).Equals("Segment")
+	Expect(getField.Result.Options).Equals([]string{"Enterprise"})
+	Expect(getField.Result.IsPublic).IsTrue()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteCustomField{Field: getField.Result})
+	Expect(err).IsNil()
+
+	getField = &query.GetCustomFieldByKey{Key: "customer-segment"}
+	err = bus.Dispatch(jonSnowCtx, getField)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestCustomFieldStorage_SetValuesAndSearch(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	platform := &cmd.AddNewCustomField{Name: "Platform", Type: enum.CustomFieldMultiSelect, Options: []string{"iOS", "Web"}, IsPublic: true}
+	arrImpact := &cmd.AddNewCustomField{Name: "ARR Impact", Type: enum.CustomFieldNumber}
+	err := bus.Dispatch(jonSnowCtx, platform, arrImpact)
+	Expect(err).IsNil()
+
+	post1 := &cmd.AddNewPost{Title: "Dark mode on mobile", Description: "Please"}
+	post2 := &cmd.AddNewPost{Title: "Dark mode on desktop", Description: "Please"}
+	err = bus.Dispatch(aryaStarkCtx, post1, post2)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostCustomFieldValues{
+		Post: post1.Result,
+		Values: []*entity.CustomFieldValue{
+			{Field: platform.Result, Value: []string{"iOS"}},
+			{Field: arrImpact.Result, Value: float64(50000)},
+		},
+	})
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: post1.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.CustomFields).Equals(map[string]any{
+		"platform":   []any{"iOS"},
+		"arr-impact": float64(50000),
+	})
+
+	// Staff-only fields are hidden from visitors
+	getPost = &query.GetPostByID{PostID: post1.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.CustomFields).Equals(map[string]any{
+		"platform": []any{"iOS"},
+	})
+
+	searchPosts := &query.SearchPosts{View: "recent", CustomFields: map[string]string{"platform": "iOS"}}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+	Expect(searchPosts.Result[0].ID).Equals(post1.Result.ID)
+
+	searchPosts = &query.SearchPosts{View: "recent", CustomFields: map[string]string{"arr-impact": "50000"}}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(2)
+
+	searchPosts = &query.SearchPosts{View: "recent", CustomFields: map[string]string{"arr-impact": "50000"}}
+	err = bus.Dispatch(jonSnowCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(1)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostCustomFieldValues{
+		Post:   post1.Result,
+		Values: []*entity.CustomFieldValue{{Field: platform.Result, Value: nil}},
+	})
+	Expect(err).IsNil()
+
+	searchPosts = &query.SearchPosts{View: "recent", CustomFields: map[string]string{"platform": "iOS"}}
+	err = bus.Dispatch(aryaStarkCtx, searchPosts)
+	Expect(err).IsNil()
+	Expect(searchPosts.Result).HasLen(0)
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbCustomRole struct {
+	ID          int      `db:"id"`
+	Name        string   `db:"name"`
+	Permissions []string `db:"permissions"`
+	UsersCount  int      `db:"users_count"`
+}
+
+func (r *dbCustomRole) toModel() *entity.CustomRole {
+	permissions := r.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &entity.CustomRole{
+		ID:          r.ID,
+		Name:        r.Name,
+		Permissions: permissions,
+		UsersCount:  r.UsersCount,
+	}
+}
+
+const sqlSelectCustomRoles = `
+	SELECT r.id, r.name, r.permissions,
+	(SELECT COUNT(*) FROM users u WHERE u.custom_role_id = r.id AND u.tenant_id = r.tenant_id) AS users_count
+	FROM custom_roles r`
+
+func getCustomRoleByID(ctx context.Context, q *query.GetCustomRoleByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		role := dbCustomRole{}
+		err := trx.Get(&role, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 AND r.id = $2", tenant.ID, q.RoleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom role with id '%d'", q.RoleID)
+		}
+
+		q.Result = role.toModel()
+		return nil
+	})
+}
+
+func getCustomRoleByName(ctx context.Context, q *query.GetCustomRoleByName) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		role := dbCustomRole{}
+		err := trx.Get(&role, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 AND LOWER(r.name) = LOWER($2)", tenant.ID, q.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom role with name '%s'", q.Name)
+		}
+
+		q.Result = role.toModel()
+		return nil
+	})
+}
+
+func getAllCustomRoles(ctx context.Context, q *query.GetAllCustomRoles) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		roles := []*dbCustomRole{}
+		err := trx.Select(&roles, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 ORDER BY r.name", tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all custom roles")
+		}
+
+		q.Result = make([]*entity.CustomRole, len(roles))
+		for i, role := range roles {
+			q.Result[i] = role.toModel()
+		}
+		return nil
+	})
+}
+
+func addNewCustomRole(ctx context.Context, c *cmd.AddNewCustomRole) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var id int
+		err := trx.Get(&id, `
+			INSERT INTO custom_roles (tenant_id, name, permissions, created_at)
+			VALUES ($1, $2, $3, NOW())
+			RETURNING id
+		`, tenant.ID, c.Name, pq.Array(c.Permissions))
+		if err != nil {
+			return errors.Wrap(err, "failed to add new custom role")
+		}
+
+		c.Result = &entity.CustomRole{ID: id, Name: c.Name, Permissions: c.Permissions}
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomRoleCreated,
+			TargetType: "role",
+			TargetID:   id,
+			TargetName: c.Name,
+			After:      map[string]any{"name": c.Name, "permissions": c.Permissions},
+		})
+	})
+}
+
+func updateCustomRole(ctx context.Context, c *cmd.UpdateCustomRole) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous := dbCustomRole{}
+		err := trx.Get(&previous, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 AND r.id = $2", tenant.ID, c.RoleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom role with id '%d'", c.RoleID)
+		}
+
+		_, err = trx.Execute(`
+			UPDATE custom_roles SET name = $1, permissions = $2
+			WHERE id = $3 AND tenant_id = $4
+		`, c.Name, pq.Array(c.Permissions), c.RoleID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update custom role with id '%d'", c.RoleID)
+		}
+
+		role := dbCustomRole{}
+		err = trx.Get(&role, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 AND r.id = $2", tenant.ID, c.RoleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom role with id '%d'", c.RoleID)
+		}
+
+		c.Result = role.toModel()
+		before := previous.toModel()
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomRoleUpdated,
+			TargetType: "role",
+			TargetID:   c.RoleID,
+			TargetName: c.Name,
+			Before:     map[string]any{"name": before.Name, "permissions": before.Permissions},
+			After:      map[string]any{"name": c.Name, "permissions": c.Permissions},
+		})
+	})
+}
+
+func deleteCustomRole(ctx context.Context, c *cmd.DeleteCustomRole) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous := dbCustomRole{}
+		err := trx.Get(&previous, sqlSelectCustomRoles+" WHERE r.tenant_id = $1 AND r.id = $2", tenant.ID, c.RoleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get custom role with id '%d'", c.RoleID)
+		}
+
+		// users of a deleted role lose their permissions instead of falling back to the collaborator ones
+		_, err = trx.Execute(`
+			UPDATE users SET role = $1, custom_role_id = NULL
+			WHERE custom_role_id = $2 AND tenant_id = $3
+		`, enum.RoleVisitor, c.RoleID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove users from custom role with id '%d'", c.RoleID)
+		}
+
+		_, err = trx.Execute(`DELETE FROM custom_roles WHERE id = $1 AND tenant_id = $2`, c.RoleID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete custom role with id '%d'", c.RoleID)
+		}
+
+		before := previous.toModel()
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditCustomRoleDeleted,
+			TargetType: "role",
+			TargetID:   c.RoleID,
+			TargetName: before.Name,
+			Before:     map[string]any{"name": before.Name, "permissions": before.Permissions},
+		})
+	})
+}
+
+func setUserCustomRole(ctx context.Context, c *cmd.SetUserCustomRole) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		// custom roles only apply to collaborators, removing it keeps the user as a regular collaborator
+		roleID := sql.NullInt64{Int64: int64(c.RoleID), Valid: c.RoleID > 0}
+		_, err = trx.Execute(`
+			UPDATE users SET role = $1, custom_role_id = $2
+			WHERE id = $3 AND tenant_id = $4
+		`, enum.RoleCollaborator, roleID, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to set custom role of user with id '%d'", c.UserID)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserCustomRoleChanged,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+			Before:     map[string]any{"role": target.Role, "customRoleId": target.CustomRoleID.Int64},
+			After:      map[string]any{"role": enum.RoleCollaborator, "customRoleId": c.RoleID},
+		})
+	})
+}
+
+// loadCustomRoles fills the custom role of given users
+func loadCustomRoles(trx *dbx.Trx, tenantID int, users ...*dbUser) error {
+	needsRoles := false
+	for _, u := range users {
+		if u.CustomRoleID.Valid {
+			needsRoles = true
+		}
+	}
+	if !needsRoles {
+		return nil
+	}
+
+	roles := []*dbCustomRole{}
+	err := trx.Select(&roles, sqlSelectCustomRoles+" WHERE r.tenant_id = $1", tenantID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get custom roles")
+	}
+
+	for _, u := range users {
+		for _, role := range roles {
+			if u.CustomRoleID.Valid && int64(role.ID) == u.CustomRoleID.Int64 {
+				u.CustomRole = role
+			}
+		}
+	}
+	return nil
+}
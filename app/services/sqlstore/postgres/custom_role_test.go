@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestCustomRoleStorage_AddUpdateAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewRole := &cmd.AddNewCustomRole{Name: "Support Agent", Permissions: []string{entity.PermissionRespond}}
+	err := bus.Dispatch(jonSnowCtx, addNewRole)
+	Expect(err).IsNil()
+	Expect(addNewRole.Result.ID).IsNotEmpty()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetUserCustomRole{UserID: aryaStark.ID, RoleID: addNewRole.Result.ID})
+	Expect(err).IsNil()
+
+	updateRole := &cmd.UpdateCustomRole{
+		RoleID:      addNewRole.Result.ID,
+		Name:        "Support",
+		Permissions: []string{entity.PermissionRespond, entity.PermissionManageTags},
+	}
+	err = bus.Dispatch(jonSnowCtx, updateRole)
+	Expect(err).IsNil()
+	Expect(updateRole.Result.UsersCount).Equals(1)
+
+	getUser := &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.Role).Equals(enum.RoleCollaborator)
+	Expect(getUser.Result.CustomRole.Name).Equals("Support")
+	Expect(getUser.Result.HasPermission(entity.PermissionManageTags)).IsTrue()
+	Expect(getUser.Result.HasPermission(entity.PermissionModerateComments)).IsFalse()
+
+	getRole := &query.GetCustomRoleByName{Name: "SUPPORT"}
+	err = bus.Dispatch(jonSnowCtx, getRole)
+	Expect(err).IsNil()
+	Expect(getRole.Result.Permissions).Equals([]string{entity.PermissionRespond, entity.PermissionManageTags})
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteCustomRole{RoleID: getRole.Result.ID})
+	Expect(err).IsNil()
+
+	getByID := &query.GetCustomRoleByID{RoleID: getRole.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	// users of a deleted role are demoted instead of getting the default collaborator permissions
+	getUser = &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.Role).Equals(enum.RoleVisitor)
+	Expect(getUser.Result.CustomRole).IsNil()
+}
+
+func TestCustomRoleStorage_ChangeUserRoleClearsCustomRole(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewRole := &cmd.AddNewCustomRole{Name: "Support Agent", Permissions: []string{entity.PermissionRespond}}
+	err := bus.Dispatch(jonSnowCtx, addNewRole)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx,
+		&cmd.SetUserCustomRole{UserID: aryaStark.ID, RoleID: addNewRole.Result.ID},
+		&cmd.ChangeUserRole{UserID: aryaStark.ID, Role: enum.RoleAdministrator},
+	)
+	Expect(err).IsNil()
+
+	getUser := &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.Role).Equals(enum.RoleAdministrator)
+	Expect(getUser.Result.CustomRole).IsNil()
+}
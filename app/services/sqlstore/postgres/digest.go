@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbDigestItem struct {
+	ID        int       `db:"id"`
+	TenantID  int       `db:"tenant_id"`
+	UserID    int       `db:"user_id"`
+	UserName  string    `db:"user_name"`
+	UserEmail string    `db:"user_email"`
+	UserRole  int       `db:"user_role"`
+	Title     string    `db:"title"`
+	Link      string    `db:"link"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func addDigestItems(ctx context.Context, c *cmd.AddDigestItems) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var postID any
+		if c.PostID > 0 {
+			postID = c.PostID
+		}
+
+		now := time.Now()
+		for _, u := range c.Users {
+			_, err := trx.Execute(`
+				INSERT INTO digest_items (tenant_id, user_id, post_id, title, link, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, tenant.ID, u.ID, postID, c.Title, c.Link, now)
+			if err != nil {
+				return errors.Wrap(err, "failed to add digest item")
+			}
+		}
+		return nil
+	})
+}
+
+func deleteDigestItems(ctx context.Context, c *cmd.DeleteDigestItems) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		if len(c.IDs) == 0 {
+			return nil
+		}
+
+		_, err := trx.Execute("DELETE FROM digest_items WHERE id = ANY($1)", pq.Array(c.IDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to delete digest items")
+		}
+		return nil
+	})
+}
+
+func getPendingEmailDigests(ctx context.Context, q *query.GetPendingEmailDigests) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		q.Result = make([]*entity.EmailDigest, 0)
+
+		// Users that switched back to instant emails still get their remaining items on the daily run
+		frequencyCondition := "COALESCE(freq.value, $1) <> $2"
+		if q.Frequency == enum.EmailDigestFrequencyWeekly {
+			frequencyCondition = "COALESCE(freq.value, $1) = $2"
+		}
+
+		var items []*dbDigestItem
+		err := trx.Select(&items, `
+			SELECT d.id, d.tenant_id, d.user_id, u.name AS user_name, u.email AS user_email, u.role AS user_role, d.title, d.link, d.created_at
+			FROM digest_items d
+			INNER JOIN users u
+			ON u.id = d.user_id
+			AND u.tenant_id = d.tenant_id
+			INNER JOIN tenants t
+			ON t.id = d.tenant_id
+			LEFT JOIN user_settings freq
+			ON freq.user_id = u.id
+			AND freq.tenant_id = u.tenant_id
+			AND freq.key = $3
+			WHERE `+frequencyCondition+`
+			AND u.status = $4
+			AND u.email <> ''
+			AND u.email_supressed_at IS NULL
+			AND t.status = $5
+			ORDER BY d.tenant_id, d.user_id, d.id
+		`, enum.EmailDigestFrequencyInstant, enum.EmailDigestFrequencyWeekly, enum.EmailDigestFrequencySettingsKey, enum.UserActive, enum.TenantActive)
+		if err != nil {
+			return errors.Wrap(err, "failed to get pending digest items")
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		tenantIDs := make([]int, 0)
+		for _, item := range items {
+			if len(tenantIDs) == 0 || tenantIDs[len(tenantIDs)-1] != item.TenantID {
+				tenantIDs = append(tenantIDs, item.TenantID)
+			}
+		}
+
+		var tenants []*dbTenant
+		err = trx.Select(&tenants, `
+			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing, voting_mode, vote_budget, max_votes_per_post, is_2fa_required
+			FROM tenants
+			WHERE id = ANY($1)
+		`, pq.Array(tenantIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to get tenants of pending digest items")
+		}
+
+		tenantsByID := make(map[int]*entity.Tenant, len(tenants))
+		for _, t := range tenants {
+			tenantsByID[t.ID] = t.toModel()
+		}
+
+		var digest *entity.EmailDigest
+		for _, item := range items {
+			if digest == nil || digest.User.ID != item.UserID {
+				tenant := tenantsByID[item.TenantID]
+				digest = &entity.EmailDigest{
+					Tenant: tenant,
+					User: &entity.User{
+						ID:     item.UserID,
+						Name:   item.UserName,
+						Email:  item.UserEmail,
+						Tenant: tenant,
+						Role:   enum.Role(item.UserRole),
+						Status: enum.UserActive,
+					},
+					Items: make([]*entity.DigestItem, 0),
+				}
+				q.Result = append(q.Result, digest)
+			}
+
+			digest.Items = append(digest.Items, &entity.DigestItem{
+				ID:        item.ID,
+				Title:     item.Title,
+				Link:      item.Link,
+				CreatedAt: item.CreatedAt,
+			})
+		}
+
+		return nil
+	})
+}
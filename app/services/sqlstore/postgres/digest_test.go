@@ -0,0 +1,53 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestDigestStorage_AddGetAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(aryaStarkCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{
+			enum.EmailDigestFrequencySettingsKey: enum.EmailDigestFrequencyWeekly,
+		},
+	})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddDigestItems{
+		Users: []*entity.User{aryaStark},
+		Title: "New post: **My Post**",
+		Link:  "/posts/1/my-post",
+	})
+	Expect(err).IsNil()
+
+	daily := &query.GetPendingEmailDigests{Frequency: enum.EmailDigestFrequencyDaily}
+	err = bus.Dispatch(jonSnowCtx, daily)
+	Expect(err).IsNil()
+	Expect(daily.Result).HasLen(0)
+
+	weekly := &query.GetPendingEmailDigests{Frequency: enum.EmailDigestFrequencyWeekly}
+	err = bus.Dispatch(jonSnowCtx, weekly)
+	Expect(err).IsNil()
+	Expect(weekly.Result).HasLen(1)
+	Expect(weekly.Result[0].Tenant.ID).Equals(demoTenant.ID)
+	Expect(weekly.Result[0].User.ID).Equals(aryaStark.ID)
+	Expect(weekly.Result[0].Items).HasLen(1)
+	Expect(weekly.Result[0].Items[0].Title).Equals("New post: **My Post**")
+	Expect(weekly.Result[0].Items[0].Link).Equals("/posts/1/my-post")
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteDigestItems{IDs: []int{weekly.Result[0].Items[0].ID}})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, weekly)
+	Expect(err).IsNil()
+	Expect(weekly.Result).HasLen(0)
+}
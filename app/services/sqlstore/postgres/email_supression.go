@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbSupressedEmail struct {
+	UserID      int         `db:"id"`
+	UserName    string      `db:"name"`
+	Email       string      `db:"email"`
+	Reason      dbx.NullInt `db:"email_supression_reason"`
+	SupressedAt time.Time   `db:"email_supressed_at"`
+}
+
+func (s *dbSupressedEmail) toModel() *entity.SupressedEmail {
+	reason := enum.EmailSupressionHardBounce
+	if s.Reason.Valid {
+		reason = enum.EmailSupressionReason(s.Reason.Int64)
+	}
+
+	return &entity.SupressedEmail{
+		UserID:      s.UserID,
+		UserName:    s.UserName,
+		Email:       s.Email,
+		Reason:      reason,
+		SupressedAt: s.SupressedAt,
+	}
+}
+
+func supressUserEmail(ctx context.Context, c *cmd.SupressUserEmail) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		rowsCount, err := trx.Execute(`
+			UPDATE users SET email_supressed_at = $3, email_supression_reason = $4
+			WHERE id = $1 AND tenant_id = $2 AND email_supressed_at IS NULL`,
+			c.UserID, tenant.ID, time.Now(), enum.EmailSupressionManual,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to supress user email")
+		}
+		if rowsCount == 0 {
+			return nil
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserEmailSupressed,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+			After:      map[string]any{"reason": enum.EmailSupressionManual.String()},
+		})
+	})
+}
+
+func unsupressUserEmail(ctx context.Context, c *cmd.UnsupressUserEmail) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		supressed := dbSupressedEmail{}
+		err := trx.Get(&supressed, `
+			SELECT id, name, email, email_supression_reason, email_supressed_at
+			FROM users
+			WHERE id = $1 AND tenant_id = $2 AND email_supressed_at IS NOT NULL`,
+			c.UserID, tenant.ID,
+		)
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to get supressed email of user with id '%d'", c.UserID)
+		}
+
+		if _, err := trx.Execute(`
+			UPDATE users SET email_supressed_at = NULL, email_supression_reason = NULL
+			WHERE id = $1 AND tenant_id = $2`,
+			c.UserID, tenant.ID,
+		); err != nil {
+			return errors.Wrap(err, "failed to unsupress user email")
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserEmailUnsupressed,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: supressed.UserName,
+			Before:     map[string]any{"reason": supressed.toModel().Reason.String()},
+		})
+	})
+}
+
+func getSupressedEmails(ctx context.Context, q *query.GetSupressedEmails) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var supressed []*dbSupressedEmail
+		err := trx.Select(&supressed, `
+			SELECT id, name, email, email_supression_reason, email_supressed_at
+			FROM users
+			WHERE tenant_id = $1 AND email_supressed_at IS NOT NULL
+			ORDER BY email_supressed_at DESC`,
+			tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get supressed emails")
+		}
+
+		q.Result = make([]*entity.SupressedEmail, len(supressed))
+		for i, s := range supressed {
+			q.Result[i] = s.toModel()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbEmailTemplate struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Locale    string    `db:"locale"`
+	Subject   string    `db:"subject"`
+	Body      string    `db:"body"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (t *dbEmailTemplate) toModel() *entity.EmailTemplate {
+	return &entity.EmailTemplate{
+		ID:        t.ID,
+		Name:      t.Name,
+		Locale:    t.Locale,
+		Subject:   t.Subject,
+		Body:      t.Body,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func getEmailTemplate(ctx context.Context, q *query.GetEmailTemplate) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		template := dbEmailTemplate{}
+		err := trx.Get(&template, `
+			SELECT id, name, locale, subject, body, updated_at
+			FROM email_templates
+			WHERE tenant_id = $1 AND name = $2 AND locale = $3`,
+			tenant.ID, q.Name, q.Locale,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get email template '%s' for locale '%s'", q.Name, q.Locale)
+		}
+
+		q.Result = template.toModel()
+		return nil
+	})
+}
+
+func listEmailTemplates(ctx context.Context, q *query.ListEmailTemplates) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var templates []*dbEmailTemplate
+		err := trx.Select(&templates, `
+			SELECT id, name, locale, subject, body, updated_at
+			FROM email_templates
+			WHERE tenant_id = $1
+			ORDER BY name, locale`,
+			tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to list email templates")
+		}
+
+		q.Result = make([]*entity.EmailTemplate, len(templates))
+		for i, template := range templates {
+			q.Result[i] = template.toModel()
+		}
+		return nil
+	})
+}
+
+func saveEmailTemplate(ctx context.Context, c *cmd.SaveEmailTemplate) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous, err := getAuditEmailTemplate(trx, tenant, c.Name, c.Locale)
+		if err != nil {
+			return err
+		}
+
+		var id int
+		err = trx.Scalar(&id, `
+			INSERT INTO email_templates (tenant_id, name, locale, subject, body, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (tenant_id, name, locale) DO UPDATE
+			SET subject = EXCLUDED.subject, body = EXCLUDED.body, updated_at = EXCLUDED.updated_at
+			RETURNING id`,
+			tenant.ID, c.Name, c.Locale, c.Subject, c.Body, time.Now(),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to save email template '%s' for locale '%s'", c.Name, c.Locale)
+		}
+
+		entry := auditEntry{
+			Action:     entity.AuditEmailTemplateSaved,
+			TargetType: "email_template",
+			TargetID:   id,
+			TargetName: c.Name,
+			After:      map[string]any{"locale": c.Locale, "subject": c.Subject},
+		}
+		if previous != nil {
+			entry.Before = map[string]any{"locale": previous.Locale, "subject": previous.Subject}
+		}
+		return insertAuditLog(ctx, trx, tenant, user, entry)
+	})
+}
+
+func deleteEmailTemplate(ctx context.Context, c *cmd.DeleteEmailTemplate) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous, err := getAuditEmailTemplate(trx, tenant, c.Name, c.Locale)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			return nil
+		}
+
+		if _, err := trx.Execute(`
+			DELETE FROM email_templates
+			WHERE tenant_id = $1 AND id = $2`,
+			tenant.ID, previous.ID,
+		); err != nil {
+			return errors.Wrap(err, "failed to delete email template '%s' for locale '%s'", c.Name, c.Locale)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditEmailTemplateDeleted,
+			TargetType: "email_template",
+			TargetID:   previous.ID,
+			TargetName: previous.Name,
+			Before:     map[string]any{"locale": previous.Locale, "subject": previous.Subject},
+		})
+	})
+}
+
+// getAuditEmailTemplate returns the current override of an email template, or nil when the default one is used
+func getAuditEmailTemplate(trx *dbx.Trx, tenant *entity.Tenant, name, locale string) (*dbEmailTemplate, error) {
+	template := &dbEmailTemplate{}
+	err := trx.Get(template, `
+		SELECT id, name, locale, subject, body, updated_at
+		FROM email_templates
+		WHERE tenant_id = $1 AND name = $2 AND locale = $3`,
+		tenant.ID, name, locale,
+	)
+	if errors.Cause(err) == app.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get email template '%s' for locale '%s'", name, locale)
+	}
+	return template, nil
+}
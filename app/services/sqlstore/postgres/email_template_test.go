@@ -0,0 +1,47 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestEmailTemplateStorage_SaveAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx,
+		&cmd.SaveEmailTemplate{Name: "new_post", Locale: "en", Subject: "New idea", Body: "{{ .title }}"},
+		&cmd.SaveEmailTemplate{Name: "new_post", Locale: "pt-BR", Subject: "Nova ideia", Body: "{{ .title }}"},
+		&cmd.SaveEmailTemplate{Name: "new_post", Locale: "en", Subject: "A new idea", Body: "<p>{{ .title }}</p>"},
+	)
+	Expect(err).IsNil()
+
+	getTemplate := &query.GetEmailTemplate{Name: "new_post", Locale: "en"}
+	err = bus.Dispatch(jonSnowCtx, getTemplate)
+	Expect(err).IsNil()
+	Expect(getTemplate.Result.Subject).Equals("A new idea")
+	Expect(getTemplate.Result.Body).Equals("<p>{{ .title }}</p>")
+
+	listTemplates := &query.ListEmailTemplates{}
+	err = bus.Dispatch(jonSnowCtx, listTemplates)
+	Expect(err).IsNil()
+	Expect(listTemplates.Result).HasLen(2)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteEmailTemplate{Name: "new_post", Locale: "en"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetEmailTemplate{Name: "new_post", Locale: "en"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	err = bus.Dispatch(aryaStarkCtx, &query.GetEmailTemplate{Name: "new_post", Locale: "pt-BR"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(avengersTenantCtx, &query.GetEmailTemplate{Name: "new_post", Locale: "pt-BR"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/realtime"
 	"github.com/lib/pq"
 )
 
@@ -147,7 +148,13 @@ func addNewNotification(ctx context.Context, c *cmd.AddNewNotification) error {
 		}
 
 		c.Result = notification
-		return nil
+		return notifyRealtime(trx, tenant, &realtime.Event{
+			Type: realtime.NotificationCreated,
+			Data: map[string]any{
+				"id": notification.ID,
+			},
+			UserID: c.User.ID,
+		})
 	})
 }
 
@@ -172,6 +179,43 @@ func removeSubscriber(ctx context.Context, c *cmd.RemoveSubscriber) error {
 	})
 }
 
+// muteComments keeps the user subscribed to the post, but stops the notifications about its new comments
+func muteComments(ctx context.Context, c *cmd.MuteComments) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO post_subscribers (tenant_id, user_id, post_id, created_at, updated_at, status, comments_muted)
+			VALUES ($1, $2, $3, $4, $4, $5, true) ON CONFLICT (user_id, post_id)
+			DO UPDATE SET status = $5, comments_muted = true, updated_at = $4`,
+			tenant.ID, c.User.ID, c.Post.ID, time.Now(), enum.SubscriberActive,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to mute post comments")
+		}
+		return nil
+	})
+}
+
+func unmuteComments(ctx context.Context, c *cmd.UnmuteComments) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE post_subscribers SET comments_muted = false, updated_at = $4
+			WHERE tenant_id = $1 AND user_id = $2 AND post_id = $3`,
+			tenant.ID, c.User.ID, c.Post.ID, time.Now(),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmute post comments")
+		}
+		return nil
+	})
+}
+
+// sqlIsNotSnoozed filters out the users who snoozed all their notifications
+var sqlIsNotSnoozed = fmt.Sprintf(`NOT EXISTS (
+	SELECT 1 FROM user_settings snooze
+	WHERE snooze.user_id = u.id AND snooze.tenant_id = u.tenant_id AND snooze.key = '%s'
+	AND CAST(NULLIF(snooze.value, '') AS timestamptz) > NOW()
+)`, enum.NotificationsSnoozedUntilSettingsKey)
+
 func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		q.Result = make([]*entity.User, 0)
@@ -182,13 +226,69 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 		)
 
 		// When searching for email subscrivers, skip users with email supressed
-		supressionCondition := ""
-		if q.Channel == enum.NotificationChannelEmail {
-			supressionCondition = "AND u.email_supressed_at IS NULL"
+		// Users on a daily or weekly digest are returned for the digest channel instead of the email channel
+		// Push notifications are opt-in, so default roles are ignored, and only users with a subscribed browser get them
+		channel := q.Channel
+		channelCondition := ""
+		defaultEnabledUserRoles := q.Event.DefaultEnabledUserRoles
+		if q.Channel == enum.NotificationChannelPush {
+			defaultEnabledUserRoles = []enum.Role{}
+			channelCondition = `AND EXISTS (
+				SELECT 1 FROM push_subscriptions ps
+				WHERE ps.user_id = u.id AND ps.tenant_id = u.tenant_id
+			)`
+		} else if q.Channel == enum.NotificationChannelEmail || q.Channel == enum.NotificationChannelDigest {
+			channel = enum.NotificationChannelEmail
+			frequencies := fmt.Sprintf("'%s'", enum.EmailDigestFrequencyInstant)
+			if q.Channel == enum.NotificationChannelDigest {
+				frequencies = fmt.Sprintf("'%s', '%s'", enum.EmailDigestFrequencyDaily, enum.EmailDigestFrequencyWeekly)
+			}
+			channelCondition = fmt.Sprintf(`AND u.email_supressed_at IS NULL
+				AND COALESCE((
+					SELECT freq.value FROM user_settings freq
+					WHERE freq.user_id = u.id AND freq.tenant_id = u.tenant_id AND freq.key = '%s'
+				), '%s') IN (%s)`, enum.EmailDigestFrequencySettingsKey, enum.EmailDigestFrequencyInstant, frequencies)
+		}
+
+		// Users following a tag of a new post are notified about it even when they turned off new post notifications,
+		// in which case the web and email channels are used
+		followCondition := ""
+		if q.Event.UserSettingsKeyName == enum.NotificationEventNewPost.UserSettingsKeyName {
+			followCondition = fmt.Sprintf(`OR (
+				COALESCE(CAST(set.value AS integer), 0) = 0
+				AND %d & $4 > 0
+				AND EXISTS (
+					SELECT 1 FROM tag_subscribers ts
+					INNER JOIN post_tags pt ON pt.tag_id = ts.tag_id AND pt.tenant_id = ts.tenant_id
+					INNER JOIN posts p ON p.id = pt.post_id AND p.tenant_id = pt.tenant_id
+					INNER JOIN tags t ON t.id = ts.tag_id AND t.tenant_id = ts.tenant_id
+					WHERE ts.user_id = u.id AND ts.tenant_id = u.tenant_id AND p.number = $6
+					AND (u.role = ANY($7) OR (t.is_public = true AND (
+						t.group_id IS NULL OR EXISTS (SELECT 1 FROM user_groups g WHERE g.id = t.group_id AND g.tenant_id = u.tenant_id AND %s)
+					)))
+				)
+			)`, enum.NotificationChannelWeb|enum.NotificationChannelEmail, sqlIsGroupMember)
+		}
+
+		// Comments of a post can be muted while staying subscribed to its other events
+		muteCondition := ""
+		if q.Event.UserSettingsKeyName == enum.NotificationEventNewComment.UserSettingsKeyName {
+			muteCondition = "AND sub.comments_muted IS NOT TRUE"
 		}
 
 		// If the event doesn't require a subscription, notify everyone
 		if len(q.Event.RequiresSubscriptionUserRoles) == 0 {
+			args := []any{
+				q.Event.UserSettingsKeyName,
+				tenant.ID,
+				pq.Array(defaultEnabledUserRoles),
+				channel,
+				enum.UserActive,
+			}
+			if followCondition != "" {
+				args = append(args, q.Number, pq.Array([]enum.Role{enum.RoleCollaborator, enum.RoleAdministrator}))
+			}
+
 			err = trx.Select(&users, fmt.Sprintf(`
 				SELECT DISTINCT u.id, u.name, u.email, u.tenant_id, u.role, u.status
 				FROM users u
@@ -198,18 +298,14 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 				AND set.key = $1
 				WHERE u.tenant_id = $2
 				AND u.status = $5
+				AND %s
 				%s
 				AND (
 					(set.value IS NULL AND u.role = ANY($3))
 					OR CAST(set.value AS integer) & $4 > 0
+					%s
 				)
-				ORDER by u.id`, supressionCondition),
-				q.Event.UserSettingsKeyName,
-				tenant.ID,
-				pq.Array(q.Event.DefaultEnabledUserRoles),
-				q.Channel,
-				enum.UserActive,
-			)
+				ORDER by u.id`, sqlIsNotSnoozed, channelCondition, followCondition), args...)
 		} else {
 			// If the event requires a subscription, notify only those who subscribed
 			err = trx.Select(&users, fmt.Sprintf(`
@@ -225,19 +321,21 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 				AND set.tenant_id = u.tenant_id
 				WHERE u.tenant_id = $4
 				AND u.status = $8
+				AND %s
 				%s
 				AND ( sub.status = $2 OR (sub.status IS NULL AND NOT u.role = ANY($7)) )
+				%s
 				AND (
 					(set.value IS NULL AND u.role = ANY($5))
 					OR CAST(set.value AS integer) & $6 > 0
 				)
-				ORDER by u.id`, supressionCondition),
+				ORDER by u.id`, sqlIsNotSnoozed, channelCondition, muteCondition),
 				q.Number,
 				enum.SubscriberActive,
 				q.Event.UserSettingsKeyName,
 				tenant.ID,
-				pq.Array(q.Event.DefaultEnabledUserRoles),
-				q.Channel,
+				pq.Array(defaultEnabledUserRoles),
+				channel,
 				pq.Array(q.Event.RequiresSubscriptionUserRoles),
 				enum.UserActive,
 			)
@@ -247,6 +345,11 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 			return errors.Wrap(err, "failed to get post number '%d' subscribers", q.Number)
 		}
 
+		// subscribers who can't see the post are filtered out based on their groups
+		if err := loadUserGroups(trx, tenant.ID, users...); err != nil {
+			return err
+		}
+
 		q.Result = make([]*entity.User, len(users))
 		for i, user := range users {
 			q.Result[i] = user.toModel(ctx)
@@ -258,7 +361,7 @@ func getActiveSubscribers(ctx context.Context, q *query.GetActiveSubscribers) er
 func internalAddSubscriber(trx *dbx.Trx, post *entity.Post, tenant *entity.Tenant, user *entity.User, force bool) error {
 	conflict := " DO NOTHING"
 	if force {
-		conflict = "(user_id, post_id) DO UPDATE SET status = $5, updated_at = $4"
+		conflict = "(user_id, post_id) DO UPDATE SET status = $5, comments_muted = false, updated_at = $4"
 	}
 
 	_, err := trx.Execute(fmt.Sprintf(`
@@ -274,12 +377,21 @@ func internalAddSubscriber(trx *dbx.Trx, post *entity.Post, tenant *entity.Tenan
 
 func supressEmail(ctx context.Context, c *cmd.SupressEmail) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		cmd := "UPDATE users SET email_supressed_at = $1 WHERE email = ANY($2) AND email_supressed_at IS NULL"
-		rowsCount, err := trx.Execute(cmd, time.Now(), pq.Array(c.EmailAddresses))
+		reason := c.Reason
+		if reason == 0 {
+			reason = enum.EmailSupressionHardBounce
+		}
+
+		cmd := "UPDATE users SET email_supressed_at = $1, email_supression_reason = $3 WHERE email = ANY($2) AND email_supressed_at IS NULL"
+		rowsCount, err := trx.Execute(cmd, time.Now(), pq.Array(c.EmailAddresses), reason)
 		if err != nil {
 			return errors.Wrap(err, "failed to update supress email: %s", strings.Join(c.EmailAddresses, ","))
 		}
 		c.NumOfSupressedEmailAddresses = int(rowsCount)
+
+		if reason == enum.EmailSupressionHardBounce {
+			return markEmailsAsBounced(trx, c.EmailAddresses)
+		}
 		return nil
 	})
 }
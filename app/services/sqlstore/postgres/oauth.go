@@ -13,40 +13,56 @@ import (
 )
 
 type dbOAuthConfig struct {
-	ID                int    `db:"id"`
-	Provider          string `db:"provider"`
-	DisplayName       string `db:"display_name"`
-	LogoBlobKey       string `db:"logo_bkey"`
-	Status            int    `db:"status"`
-	IsTrusted         bool   `db:"is_trusted"`
-	ClientID          string `db:"client_id"`
-	ClientSecret      string `db:"client_secret"`
-	AuthorizeURL      string `db:"authorize_url"`
-	TokenURL          string `db:"token_url"`
-	Scope             string `db:"scope"`
-	ProfileURL        string `db:"profile_url"`
-	JSONUserIDPath    string `db:"json_user_id_path"`
-	JSONUserNamePath  string `db:"json_user_name_path"`
-	JSONUserEmailPath string `db:"json_user_email_path"`
+	ID                 int    `db:"id"`
+	Provider           string `db:"provider"`
+	DisplayName        string `db:"display_name"`
+	LogoBlobKey        string `db:"logo_bkey"`
+	Status             int    `db:"status"`
+	IsTrusted          bool   `db:"is_trusted"`
+	ClientID           string `db:"client_id"`
+	ClientSecret       string `db:"client_secret"`
+	AuthorizeURL       string `db:"authorize_url"`
+	TokenURL           string `db:"token_url"`
+	Scope              string `db:"scope"`
+	ProfileURL         string `db:"profile_url"`
+	JSONUserIDPath     string `db:"json_user_id_path"`
+	JSONUserNamePath   string `db:"json_user_name_path"`
+	JSONUserEmailPath  string `db:"json_user_email_path"`
+	JSONUserGroupsPath string `db:"json_user_groups_path"`
 }
 
 func (m *dbOAuthConfig) toModel() *entity.OAuthConfig {
 	return &entity.OAuthConfig{
-		ID:                m.ID,
-		Provider:          m.Provider,
-		DisplayName:       m.DisplayName,
-		Status:            m.Status,
-		IsTrusted:         m.IsTrusted,
-		LogoBlobKey:       m.LogoBlobKey,
-		ClientID:          m.ClientID,
-		ClientSecret:      m.ClientSecret,
-		AuthorizeURL:      m.AuthorizeURL,
-		TokenURL:          m.TokenURL,
-		ProfileURL:        m.ProfileURL,
-		Scope:             m.Scope,
-		JSONUserIDPath:    m.JSONUserIDPath,
-		JSONUserNamePath:  m.JSONUserNamePath,
-		JSONUserEmailPath: m.JSONUserEmailPath,
+		ID:                 m.ID,
+		Provider:           m.Provider,
+		DisplayName:        m.DisplayName,
+		Status:             m.Status,
+		IsTrusted:          m.IsTrusted,
+		LogoBlobKey:        m.LogoBlobKey,
+		ClientID:           m.ClientID,
+		ClientSecret:       m.ClientSecret,
+		AuthorizeURL:       m.AuthorizeURL,
+		TokenURL:           m.TokenURL,
+		ProfileURL:         m.ProfileURL,
+		Scope:              m.Scope,
+		JSONUserIDPath:     m.JSONUserIDPath,
+		JSONUserNamePath:   m.JSONUserNamePath,
+		JSONUserEmailPath:  m.JSONUserEmailPath,
+		JSONUserGroupsPath: m.JSONUserGroupsPath,
+	}
+}
+
+// auditValues returns the settings of an OAuth provider that are recorded on the audit log, secrets are never included
+func (m *dbOAuthConfig) auditValues() map[string]any {
+	return map[string]any{
+		"displayName":  m.DisplayName,
+		"status":       m.Status,
+		"isTrusted":    m.IsTrusted,
+		"clientId":     m.ClientID,
+		"authorizeUrl": m.AuthorizeURL,
+		"tokenUrl":     m.TokenURL,
+		"profileUrl":   m.ProfileURL,
+		"scope":        m.Scope,
 	}
 }
 
@@ -61,7 +77,7 @@ func getCustomOAuthConfigByProvider(ctx context.Context, q *query.GetCustomOAuth
 		SELECT id, provider, display_name, status, is_trusted, logo_bkey,
 					 client_id, client_secret, authorize_url,
 					 profile_url, token_url, scope, json_user_id_path,
-					 json_user_name_path, json_user_email_path
+					 json_user_name_path, json_user_email_path, json_user_groups_path
 		FROM oauth_providers
 		WHERE tenant_id = $1 AND provider = $2
 		`, tenant.ID, q.Provider)
@@ -86,7 +102,7 @@ func listCustomOAuthConfig(ctx context.Context, q *query.ListCustomOAuthConfig)
 			SELECT id, provider, display_name, status, is_trusted, logo_bkey,
 						 client_id, client_secret, authorize_url,
 						 profile_url, token_url, scope, json_user_id_path,
-						 json_user_name_path, json_user_email_path
+						 json_user_name_path, json_user_email_path, json_user_groups_path
 			FROM oauth_providers
 			WHERE tenant_id = $1
 			ORDER BY id`, tenant.ID)
@@ -106,45 +122,83 @@ func listCustomOAuthConfig(ctx context.Context, q *query.ListCustomOAuthConfig)
 func saveCustomOAuthConfig(ctx context.Context, c *cmd.SaveCustomOAuthConfig) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		var err error
+		var previous *dbOAuthConfig
 
 		if c.Logo.Remove {
 			c.Logo.BlobKey = ""
 		}
 
+		if c.ID != 0 {
+			previous = &dbOAuthConfig{}
+			err = trx.Get(previous, `
+			SELECT id, provider, display_name, status, is_trusted, logo_bkey,
+						 client_id, client_secret, authorize_url,
+						 profile_url, token_url, scope, json_user_id_path,
+						 json_user_name_path, json_user_email_path, json_user_groups_path
+			FROM oauth_providers
+			WHERE tenant_id = $1 AND id = $2
+			`, tenant.ID, c.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to get OAuth Provider with id '%d'", c.ID)
+			}
+		}
+
 		if c.ID == 0 {
 			query := `INSERT INTO oauth_providers (
 				tenant_id, provider, display_name, status, is_trusted,
 				client_id, client_secret, authorize_url,
 				profile_url, token_url, scope, json_user_id_path,
-				json_user_name_path, json_user_email_path, logo_bkey
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+				json_user_name_path, json_user_email_path, logo_bkey, json_user_groups_path
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 			RETURNING id`
 
 			err = trx.Get(&c.ID, query, tenant.ID, c.Provider,
 				c.DisplayName, c.Status, c.IsTrusted, c.ClientID, c.ClientSecret,
 				c.AuthorizeURL, c.ProfileURL, c.TokenURL,
 				c.Scope, c.JSONUserIDPath, c.JSONUserNamePath,
-				c.JSONUserEmailPath, c.Logo.BlobKey)
+				c.JSONUserEmailPath, c.Logo.BlobKey, c.JSONUserGroupsPath)
 		} else {
 			query := `
 				UPDATE oauth_providers 
 				SET display_name = $3, status = $4, client_id = $5, client_secret = $6, 
 						authorize_url = $7, profile_url = $8, token_url = $9, scope = $10, 
 						json_user_id_path = $11, json_user_name_path = $12, json_user_email_path = $13,
-						logo_bkey = $14, is_trusted = $15
+						logo_bkey = $14, is_trusted = $15, json_user_groups_path = $16
 			WHERE tenant_id = $1 AND id = $2`
 
 			_, err = trx.Execute(query, tenant.ID, c.ID,
 				c.DisplayName, c.Status, c.ClientID, c.ClientSecret,
 				c.AuthorizeURL, c.ProfileURL, c.TokenURL,
 				c.Scope, c.JSONUserIDPath, c.JSONUserNamePath,
-				c.JSONUserEmailPath, c.Logo.BlobKey, c.IsTrusted)
+				c.JSONUserEmailPath, c.Logo.BlobKey, c.IsTrusted, c.JSONUserGroupsPath)
 		}
 
 		if err != nil {
 			return errors.Wrap(err, "failed to save OAuth Provider")
 		}
 
-		return nil
+		after := (&dbOAuthConfig{
+			DisplayName:  c.DisplayName,
+			Status:       c.Status,
+			IsTrusted:    c.IsTrusted,
+			ClientID:     c.ClientID,
+			AuthorizeURL: c.AuthorizeURL,
+			TokenURL:     c.TokenURL,
+			ProfileURL:   c.ProfileURL,
+			Scope:        c.Scope,
+		}).auditValues()
+		entry := auditEntry{
+			Action:     entity.AuditOAuthConfigSaved,
+			TargetType: "oauth",
+			TargetID:   c.ID,
+			TargetName: c.Provider,
+			After:      after,
+		}
+		if previous != nil {
+			entry.Before = previous.auditValues()
+			after["clientSecretChanged"] = previous.ClientSecret != c.ClientSecret
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, entry)
 	})
 }
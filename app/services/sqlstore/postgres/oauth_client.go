@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+	"github.com/lib/pq"
+)
+
+type dbOAuthClient struct {
+	ID           int       `db:"id"`
+	ClientID     string    `db:"client_id"`
+	SecretHash   string    `db:"client_secret_hash"`
+	Name         string    `db:"name"`
+	RedirectURIs []string  `db:"redirect_uris"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (c *dbOAuthClient) toModel() *entity.OAuthClient {
+	return &entity.OAuthClient{
+		ID:           c.ID,
+		ClientID:     c.ClientID,
+		SecretHash:   c.SecretHash,
+		Name:         c.Name,
+		RedirectURIs: c.RedirectURIs,
+		CreatedAt:    c.CreatedAt,
+	}
+}
+
+type dbOAuthGrant struct {
+	ClientID      int      `db:"client_id"`
+	UserID        int      `db:"user_id"`
+	RedirectURI   string   `db:"redirect_uri"`
+	Scopes        []string `db:"scopes"`
+	CodeChallenge string   `db:"code_challenge"`
+	Nonce         string   `db:"nonce"`
+}
+
+func (g *dbOAuthGrant) toModel() *entity.OAuthGrant {
+	return &entity.OAuthGrant{
+		ClientID:      g.ClientID,
+		UserID:        g.UserID,
+		RedirectURI:   g.RedirectURI,
+		Scopes:        g.Scopes,
+		CodeChallenge: g.CodeChallenge,
+		Nonce:         g.Nonce,
+	}
+}
+
+const sqlSelectOAuthClients = `
+	SELECT id, client_id, COALESCE(client_secret_hash, '') AS client_secret_hash, name, redirect_uris, created_at
+	FROM oauth_clients
+	WHERE tenant_id = $1
+	AND revoked_at IS NULL`
+
+func getOAuthClientByID(ctx context.Context, q *query.GetOAuthClientByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		client := dbOAuthClient{}
+		err := trx.Get(&client, sqlSelectOAuthClients+" AND id = $2", tenant.ID, q.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get OAuth client with id '%d'", q.ID)
+		}
+
+		q.Result = client.toModel()
+		return nil
+	})
+}
+
+func getOAuthClientByClientID(ctx context.Context, q *query.GetOAuthClientByClientID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		client := dbOAuthClient{}
+		err := trx.Get(&client, sqlSelectOAuthClients+" AND client_id = $2", tenant.ID, q.ClientID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get OAuth client with client_id '%s'", q.ClientID)
+		}
+
+		q.Result = client.toModel()
+		return nil
+	})
+}
+
+func getAllOAuthClients(ctx context.Context, q *query.GetAllOAuthClients) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		clients := []*dbOAuthClient{}
+		err := trx.Select(&clients, sqlSelectOAuthClients+" ORDER BY name", tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all OAuth clients")
+		}
+
+		q.Result = make([]*entity.OAuthClient, len(clients))
+		for i, client := range clients {
+			q.Result[i] = client.toModel()
+		}
+		return nil
+	})
+}
+
+func addNewOAuthClient(ctx context.Context, c *cmd.AddNewOAuthClient) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Only a hash of the secret is stored, the secret itself is shown once to the administrator
+		secret := ""
+		var secretHash *string
+		if c.IsConfidential {
+			secret = entity.GenerateOAuthClientSecret()
+			hash := crypto.SHA512(secret)
+			secretHash = &hash
+		}
+
+		client := dbOAuthClient{}
+		err := trx.Get(&client, `
+			INSERT INTO oauth_clients (tenant_id, client_id, client_secret_hash, name, redirect_uris, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, client_id, COALESCE(client_secret_hash, '') AS client_secret_hash, name, redirect_uris, created_at
+		`, tenant.ID, entity.GenerateOAuthClientID(), secretHash, c.Name, pq.Array(c.RedirectURIs), time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to add new OAuth client")
+		}
+
+		c.Secret = secret
+		c.Result = client.toModel()
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditOAuthClientCreated,
+			TargetType: "oauth_client",
+			TargetID:   client.ID,
+			TargetName: client.Name,
+			After:      oauthClientAuditValues(&client),
+		})
+	})
+}
+
+func revokeOAuthClient(ctx context.Context, c *cmd.RevokeOAuthClient) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		client := dbOAuthClient{}
+		err := trx.Get(&client, sqlSelectOAuthClients+" AND id = $2", tenant.ID, c.ID)
+		if err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return nil
+			}
+			return errors.Wrap(err, "failed to get OAuth client with id '%d'", c.ID)
+		}
+
+		now := time.Now()
+		_, err = trx.Execute(`
+			UPDATE oauth_clients SET revoked_at = $1 WHERE id = $2 AND tenant_id = $3 AND revoked_at IS NULL
+		`, now, c.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke OAuth client with id '%d'", c.ID)
+		}
+
+		_, err = trx.Execute(`
+			UPDATE oauth_refresh_tokens SET revoked_at = $1 WHERE client_id = $2 AND tenant_id = $3 AND revoked_at IS NULL
+		`, now, c.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke refresh tokens of OAuth client with id '%d'", c.ID)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditOAuthClientRevoked,
+			TargetType: "oauth_client",
+			TargetID:   client.ID,
+			TargetName: client.Name,
+			Before:     oauthClientAuditValues(&client),
+		})
+	})
+}
+
+func oauthClientAuditValues(client *dbOAuthClient) map[string]any {
+	return map[string]any{
+		"clientId":       client.ClientID,
+		"redirectUris":   client.RedirectURIs,
+		"isConfidential": client.SecretHash != "",
+	}
+}
+
+func addOAuthAuthorizationCode(ctx context.Context, c *cmd.AddOAuthAuthorizationCode) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Only a hash of the code is stored, the code itself is sent to the client through the redirect URI
+		code := rand.String(48)
+		now := time.Now()
+
+		_, err := trx.Execute(`
+			INSERT INTO oauth_authorization_codes (tenant_id, client_id, user_id, code_hash, redirect_uri, scopes, code_challenge, nonce, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, tenant.ID, c.Grant.ClientID, c.Grant.UserID, crypto.SHA512(code), c.Grant.RedirectURI,
+			pq.Array(c.Grant.Scopes), c.Grant.CodeChallenge, c.Grant.Nonce, now.Add(entity.OAuthAuthorizationCodeLifetime), now)
+		if err != nil {
+			return errors.Wrap(err, "failed to add OAuth authorization code")
+		}
+
+		c.Code = code
+		return nil
+	})
+}
+
+func useOAuthAuthorizationCode(ctx context.Context, c *cmd.UseOAuthAuthorizationCode) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Codes can only be exchanged once, so they're marked as used in the same statement
+		grant := dbOAuthGrant{}
+		err := trx.Get(&grant, `
+			UPDATE oauth_authorization_codes SET used_at = $1
+			WHERE code_hash = $2 AND tenant_id = $3
+			AND used_at IS NULL AND expires_at > $1
+			RETURNING client_id, user_id, redirect_uri, scopes, code_challenge, nonce
+		`, time.Now(), crypto.SHA512(c.Code), tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to use OAuth authorization code")
+		}
+
+		c.Result = grant.toModel()
+		return nil
+	})
+}
+
+func addOAuthRefreshToken(ctx context.Context, c *cmd.AddOAuthRefreshToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		token := rand.String(64)
+		now := time.Now()
+
+		_, err := trx.Execute(`
+			INSERT INTO oauth_refresh_tokens (tenant_id, client_id, user_id, token_hash, scopes, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, tenant.ID, c.Grant.ClientID, c.Grant.UserID, crypto.SHA512(token),
+			pq.Array(c.Grant.Scopes), now.Add(entity.OAuthRefreshTokenLifetime), now)
+		if err != nil {
+			return errors.Wrap(err, "failed to add OAuth refresh token")
+		}
+
+		c.Token = token
+		return nil
+	})
+}
+
+func useOAuthRefreshToken(ctx context.Context, c *cmd.UseOAuthRefreshToken) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Refresh tokens are rotated, the used token is revoked and a new one is issued along with the access token
+		grant := dbOAuthGrant{}
+		err := trx.Get(&grant, `
+			UPDATE oauth_refresh_tokens SET revoked_at = $1
+			WHERE token_hash = $2 AND tenant_id = $3
+			AND revoked_at IS NULL AND expires_at > $1
+			RETURNING client_id, user_id, '' AS redirect_uri, scopes, '' AS code_challenge, '' AS nonce
+		`, time.Now(), crypto.SHA512(c.Token), tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to use OAuth refresh token")
+		}
+
+		c.Result = grant.toModel()
+		return nil
+	})
+}
@@ -0,0 +1,107 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestOAuthClientStorage_AddGetAndRevoke(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addConfidential := &cmd.AddNewOAuthClient{Name: "Backend", RedirectURIs: []string{"https://example.com/callback"}, IsConfidential: true}
+	err := bus.Dispatch(jonSnowCtx, addConfidential)
+	Expect(err).IsNil()
+	Expect(addConfidential.Result.IsConfidential()).IsTrue()
+
+	addPublic := &cmd.AddNewOAuthClient{Name: "Mobile", RedirectURIs: []string{"com.example.app:/callback"}}
+	err = bus.Dispatch(jonSnowCtx, addPublic)
+	Expect(err).IsNil()
+	Expect(addPublic.Result.IsConfidential()).IsFalse()
+	Expect(addPublic.Secret).Equals("")
+
+	getByClientID := &query.GetOAuthClientByClientID{ClientID: addConfidential.Result.ClientID}
+	err = bus.Dispatch(jonSnowCtx, getByClientID)
+	Expect(err).IsNil()
+	Expect(getByClientID.Result.Name).Equals("Backend")
+	Expect(addConfidential.Secret).IsNotEmpty()
+	Expect(getByClientID.Result.SecretHash).NotEquals(addConfidential.Secret)
+	Expect(getByClientID.Result.VerifySecret(addConfidential.Secret)).IsTrue()
+
+	getAll := &query.GetAllOAuthClients{}
+	err = bus.Dispatch(jonSnowCtx, getAll)
+	Expect(err).IsNil()
+	Expect(getAll.Result).HasLen(2)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeOAuthClient{ID: addPublic.Result.ID})
+	Expect(err).IsNil()
+
+	getByID := &query.GetOAuthClientByID{ID: addPublic.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestOAuthClientStorage_AuthorizationCodeCanOnlyBeUsedOnce(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addClient := &cmd.AddNewOAuthClient{Name: "Backend", RedirectURIs: []string{"https://example.com/callback"}, IsConfidential: true}
+	err := bus.Dispatch(jonSnowCtx, addClient)
+	Expect(err).IsNil()
+
+	addCode := &cmd.AddOAuthAuthorizationCode{
+		Grant: &entity.OAuthGrant{
+			ClientID:      addClient.Result.ID,
+			UserID:        aryaStark.ID,
+			RedirectURI:   "https://example.com/callback",
+			Scopes:        []string{entity.OAuthScopeOpenID, entity.APIScopePostsRead},
+			CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			Nonce:         "abc",
+		},
+	}
+	err = bus.Dispatch(aryaStarkCtx, addCode)
+	Expect(err).IsNil()
+	Expect(addCode.Code).IsNotEmpty()
+
+	useCode := &cmd.UseOAuthAuthorizationCode{Code: addCode.Code}
+	err = bus.Dispatch(demoTenantCtx, useCode)
+	Expect(err).IsNil()
+	Expect(useCode.Result.UserID).Equals(aryaStark.ID)
+	Expect(useCode.Result.Scopes).Equals([]string{entity.OAuthScopeOpenID, entity.APIScopePostsRead})
+	Expect(useCode.Result.Nonce).Equals("abc")
+
+	useCode = &cmd.UseOAuthAuthorizationCode{Code: addCode.Code}
+	err = bus.Dispatch(demoTenantCtx, useCode)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestOAuthClientStorage_RefreshTokensAreRotated(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addClient := &cmd.AddNewOAuthClient{Name: "Mobile", RedirectURIs: []string{"com.example.app:/callback"}}
+	err := bus.Dispatch(jonSnowCtx, addClient)
+	Expect(err).IsNil()
+
+	addToken := &cmd.AddOAuthRefreshToken{
+		Grant: &entity.OAuthGrant{ClientID: addClient.Result.ID, UserID: aryaStark.ID, Scopes: []string{entity.OAuthScopeProfile}},
+	}
+	err = bus.Dispatch(demoTenantCtx, addToken)
+	Expect(err).IsNil()
+
+	useToken := &cmd.UseOAuthRefreshToken{Token: addToken.Token}
+	err = bus.Dispatch(demoTenantCtx, useToken)
+	Expect(err).IsNil()
+	Expect(useToken.Result.ClientID).Equals(addClient.Result.ID)
+
+	useToken = &cmd.UseOAuthRefreshToken{Token: addToken.Token}
+	err = bus.Dispatch(demoTenantCtx, useToken)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbOutboundEmail struct {
+	ID             int            `db:"id"`
+	TenantID       dbx.NullInt    `db:"tenant_id"`
+	MessageID      string         `db:"message_id"`
+	TemplateName   string         `db:"template_name"`
+	From           string         `db:"from_address"`
+	ReplyTo        string         `db:"reply_to"`
+	ToName         string         `db:"to_name"`
+	ToAddress      string         `db:"to_address"`
+	UnsubscribeURL string         `db:"unsubscribe_url"`
+	Subject        string         `db:"subject"`
+	Body           string         `db:"body"`
+	Status         int            `db:"status"`
+	Attempts       int            `db:"attempts"`
+	LastError      dbx.NullString `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	SentAt         dbx.NullTime   `db:"sent_at"`
+}
+
+func (e *dbOutboundEmail) toModel() *entity.OutboundEmail {
+	email := &entity.OutboundEmail{
+		ID:             e.ID,
+		TenantID:       int(e.TenantID.Int64),
+		MessageID:      e.MessageID,
+		TemplateName:   e.TemplateName,
+		From:           e.From,
+		ReplyTo:        e.ReplyTo,
+		ToName:         e.ToName,
+		ToAddress:      e.ToAddress,
+		UnsubscribeURL: e.UnsubscribeURL,
+		Subject:        e.Subject,
+		Body:           e.Body,
+		Status:         enum.OutboundEmailStatus(e.Status),
+		Attempts:       e.Attempts,
+		LastError:      e.LastError.String,
+		CreatedAt:      e.CreatedAt,
+	}
+	if e.SentAt.Valid {
+		email.SentAt = &e.SentAt.Time
+	}
+	return email
+}
+
+const outboundEmailFields = `id, tenant_id, message_id, template_name, from_address, reply_to, to_name, to_address,
+	unsubscribe_url, subject, body, status, attempts, last_error, created_at, sent_at`
+
+func queueEmail(ctx context.Context, c *cmd.QueueEmail) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// Emails sent before a tenant exists, such as the sign up one, are not scoped to a tenant
+		var tenantID any
+		if tenant != nil {
+			tenantID = tenant.ID
+			c.Email.TenantID = tenant.ID
+		}
+
+		now := time.Now()
+		err := trx.Scalar(&c.Email.ID, `
+			INSERT INTO outbound_emails (tenant_id, message_id, template_name, from_address, reply_to, to_name, to_address, unsubscribe_url, subject, body, status, attempts, created_at, next_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, 0, $12, $12)
+			RETURNING id`,
+			tenantID, c.Email.MessageID, c.Email.TemplateName, c.Email.From, c.Email.ReplyTo, truncate(c.Email.ToName, 200), c.Email.ToAddress,
+			c.Email.UnsubscribeURL, truncate(c.Email.Subject, 1000), c.Email.Body, enum.OutboundEmailQueued, now,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to queue email with template '%s'", c.Email.TemplateName)
+		}
+
+		c.Email.Status = enum.OutboundEmailQueued
+		c.Email.CreatedAt = now
+		return nil
+	})
+}
+
+func getQueuedEmails(ctx context.Context, q *query.GetQueuedEmails) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		var emails []*dbOutboundEmail
+		err := trx.Select(&emails, `
+			SELECT `+outboundEmailFields+`
+			FROM outbound_emails
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at, id
+			LIMIT $3`,
+			enum.OutboundEmailQueued, time.Now(), q.Limit,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get queued emails")
+		}
+
+		q.Result = make([]*entity.OutboundEmail, len(emails))
+		for i, email := range emails {
+			q.Result[i] = email.toModel()
+		}
+		return nil
+	})
+}
+
+func searchOutboundEmails(ctx context.Context, q *query.SearchOutboundEmails) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var emails []*dbOutboundEmail
+		err := trx.Select(&emails, `
+			SELECT `+outboundEmailFields+`
+			FROM outbound_emails
+			WHERE tenant_id = $1 AND LOWER(to_address) = $2
+			ORDER BY created_at DESC, id DESC
+			LIMIT 100`,
+			tenant.ID, strings.ToLower(strings.TrimSpace(q.Address)),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to search emails sent to '%s'", q.Address)
+		}
+
+		q.Result = make([]*entity.OutboundEmail, len(emails))
+		for i, email := range emails {
+			q.Result[i] = email.toModel()
+		}
+		return nil
+	})
+}
+
+// The body is no longer needed once the email leaves the queue, and it might contain sign in links
+// Delivery statuses are committed right away, so that emails aren't sent again when the job fails later on
+func markEmailAsSent(ctx context.Context, c *cmd.MarkEmailAsSent) error {
+	return usingOwnTransaction(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE outbound_emails
+			SET status = $2, attempts = attempts + 1, sent_at = $3, last_error = NULL, body = ''
+			WHERE id = $1`,
+			c.ID, enum.OutboundEmailSent, time.Now(),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark email '%d' as sent", c.ID)
+		}
+		return nil
+	})
+}
+
+func markEmailAsFailed(ctx context.Context, c *cmd.MarkEmailAsFailed) error {
+	return usingOwnTransaction(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		var err error
+		if c.RetryAt != nil {
+			_, err = trx.Execute(`
+				UPDATE outbound_emails
+				SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+				WHERE id = $1`,
+				c.ID, c.Error, *c.RetryAt,
+			)
+		} else {
+			_, err = trx.Execute(`
+				UPDATE outbound_emails
+				SET status = $2, attempts = attempts + 1, last_error = $3, body = ''
+				WHERE id = $1`,
+				c.ID, enum.OutboundEmailFailed, c.Error,
+			)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to mark email '%d' as failed", c.ID)
+		}
+		return nil
+	})
+}
+
+func purgeOutboundEmails(ctx context.Context, c *cmd.PurgeOutboundEmails) error {
+	return usingOwnTransaction(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		count, err := trx.Execute(`
+			DELETE FROM outbound_emails
+			WHERE created_at <= $1 AND status <> $2`,
+			c.Before, enum.OutboundEmailQueued,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete old outbound emails")
+		}
+		c.NumOfDeletedEmails = int(count)
+		return nil
+	})
+}
+
+// markEmailsAsBounced flags the latest emails sent to addresses that hard bounced
+func markEmailsAsBounced(trx *dbx.Trx, addresses []string) error {
+	lowerAddresses := make([]string, len(addresses))
+	for i, address := range addresses {
+		lowerAddresses[i] = strings.ToLower(address)
+	}
+
+	_, err := trx.Execute(`
+		UPDATE outbound_emails
+		SET status = $1
+		WHERE status = $2 AND LOWER(to_address) = ANY($3) AND sent_at >= $4`,
+		enum.OutboundEmailBounced, enum.OutboundEmailSent, pq.Array(lowerAddresses), time.Now().AddDate(0, 0, -7),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark emails as bounced")
+	}
+	return nil
+}
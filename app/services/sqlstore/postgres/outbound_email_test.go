@@ -0,0 +1,132 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/dbx"
+)
+
+func newQueueEmail(toAddress string) *cmd.QueueEmail {
+	return &cmd.QueueEmail{
+		Email: &entity.OutboundEmail{
+			MessageID:    "<" + toAddress + "@test.fider.io>",
+			TemplateName: "new_post",
+			From:         "Fider <noreply@fider.io>",
+			ReplyTo:      "noreply@fider.io",
+			ToName:       "Jon Snow",
+			ToAddress:    toAddress,
+			Subject:      "New idea",
+			Body:         "<p>Add dark mode</p>",
+		},
+	}
+}
+
+// commitAndContinue commits the changes of current test, as delivery statuses are saved in their own transaction,
+// and returns a context bound to a new transaction to check the results
+func commitAndContinue(ctx context.Context) (context.Context, func()) {
+	trx.MustCommit()
+
+	newTrx, _ := dbx.BeginTx(ctx)
+	return context.WithValue(ctx, app.TransactionCtxKey, newTrx), newTrx.MustRollback
+}
+
+func TestOutboundEmailStorage_QueueAndSend(t *testing.T) {
+	ctx := SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+	defer ResetDatabase()
+
+	first := newQueueEmail("jon.snow@got.com")
+	second := newQueueEmail("arya.stark@got.com")
+	err := bus.Dispatch(demoTenantCtx, first, second)
+	Expect(err).IsNil()
+	Expect(first.Email.ID).NotEquals(0)
+	Expect(first.Email.Status).Equals(enum.OutboundEmailQueued)
+
+	ctx, rollback := commitAndContinue(ctx)
+	defer rollback()
+	demoTenantCtx := withTenant(ctx, demoTenant)
+	avengersTenantCtx := withTenant(ctx, avengersTenant)
+
+	getQueued := &query.GetQueuedEmails{Limit: 10}
+	err = bus.Dispatch(ctx, getQueued)
+	Expect(err).IsNil()
+	Expect(getQueued.Result).HasLen(2)
+	Expect(getQueued.Result[0].TenantID).Equals(demoTenant.ID)
+	Expect(getQueued.Result[0].Body).Equals("<p>Add dark mode</p>")
+
+	retryAt := time.Now().Add(10 * time.Minute)
+	err = bus.Dispatch(context.Background(),
+		&cmd.MarkEmailAsSent{ID: first.Email.ID},
+		&cmd.MarkEmailAsFailed{ID: second.Email.ID, Error: "connection refused", RetryAt: &retryAt},
+	)
+	Expect(err).IsNil()
+
+	getQueued = &query.GetQueuedEmails{Limit: 10}
+	err = bus.Dispatch(ctx, getQueued)
+	Expect(err).IsNil()
+	Expect(getQueued.Result).HasLen(0)
+
+	search := &query.SearchOutboundEmails{Address: "JON.SNOW@got.com"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(1)
+	Expect(search.Result[0].Status).Equals(enum.OutboundEmailSent)
+	Expect(search.Result[0].SentAt).IsNotNil()
+	Expect(search.Result[0].Body).Equals("")
+
+	search = &query.SearchOutboundEmails{Address: "arya.stark@got.com"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(1)
+	Expect(search.Result[0].Status).Equals(enum.OutboundEmailQueued)
+	Expect(search.Result[0].Attempts).Equals(1)
+	Expect(search.Result[0].LastError).Equals("connection refused")
+
+	search = &query.SearchOutboundEmails{Address: "arya.stark@got.com"}
+	err = bus.Dispatch(avengersTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result).HasLen(0)
+}
+
+func TestOutboundEmailStorage_GiveUpAndPurge(t *testing.T) {
+	ctx := SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+	defer ResetDatabase()
+
+	failed := newQueueEmail("jon.snow@got.com")
+	queued := newQueueEmail("arya.stark@got.com")
+	err := bus.Dispatch(demoTenantCtx, failed, queued)
+	Expect(err).IsNil()
+
+	ctx, rollback := commitAndContinue(ctx)
+	defer rollback()
+	demoTenantCtx := withTenant(ctx, demoTenant)
+
+	err = bus.Dispatch(context.Background(), &cmd.MarkEmailAsFailed{ID: failed.Email.ID, Error: "invalid address"})
+	Expect(err).IsNil()
+
+	search := &query.SearchOutboundEmails{Address: "jon.snow@got.com"}
+	err = bus.Dispatch(demoTenantCtx, search)
+	Expect(err).IsNil()
+	Expect(search.Result[0].Status).Equals(enum.OutboundEmailFailed)
+
+	purge := &cmd.PurgeOutboundEmails{Before: time.Now().Add(time.Minute)}
+	err = bus.Dispatch(context.Background(), purge)
+	Expect(err).IsNil()
+	Expect(purge.NumOfDeletedEmails).Equals(1)
+
+	getQueued := &query.GetQueuedEmails{Limit: 10}
+	err = bus.Dispatch(ctx, getQueued)
+	Expect(err).IsNil()
+	Expect(getQueued.Result).HasLen(1)
+	Expect(getQueued.Result[0].ID).Equals(queued.Email.ID)
+}
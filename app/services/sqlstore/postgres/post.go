@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -32,6 +33,9 @@ type dbPost struct {
 	User           *dbUser        `db:"user"`
 	HasVoted       bool           `db:"has_voted"`
 	VotesCount     int            `db:"votes_count"`
+	VotesWeight    int            `db:"votes_weight"`
+	MyVoteWeight   int            `db:"my_vote_weight"`
+	VotesRevenue   int            `db:"votes_revenue"`
 	CommentsCount  int            `db:"comments_count"`
 	RecentVotes    int            `db:"recent_votes_count"`
 	RecentComments int            `db:"recent_comments_count"`
@@ -44,6 +48,9 @@ type dbPost struct {
 	OriginalSlug   sql.NullString `db:"original_slug"`
 	OriginalStatus sql.NullInt64  `db:"original_status"`
 	Tags           []string       `db:"tags"`
+	CustomFields   dbx.NullString `db:"custom_fields"`
+	IsPrivate      bool           `db:"is_private"`
+	GroupID        sql.NullInt64  `db:"group_id"`
 }
 
 func (i *dbPost) toModel(ctx context.Context) *entity.Post {
@@ -56,10 +63,20 @@ func (i *dbPost) toModel(ctx context.Context) *entity.Post {
 		CreatedAt:     i.CreatedAt,
 		HasVoted:      i.HasVoted,
 		VotesCount:    i.VotesCount,
+		VotesWeight:   i.VotesWeight,
+		MyVoteWeight:  i.MyVoteWeight,
+		VotesRevenue:  i.VotesRevenue,
 		CommentsCount: i.CommentsCount,
 		Status:        enum.PostStatus(i.Status),
 		User:          i.User.toModel(ctx),
 		Tags:          i.Tags,
+		CustomFields:  make(map[string]any),
+		IsPrivate:     i.IsPrivate,
+		GroupID:       int(i.GroupID.Int64),
+	}
+
+	if i.CustomFields.Valid {
+		_ = json.Unmarshal([]byte(i.CustomFields.String), &post.CustomFields)
 	}
 
 	if i.Response.Valid {
@@ -94,6 +111,18 @@ var (
 														%s
 														GROUP BY post_id 
 													), 
+													agg_fields AS (
+														SELECT 
+																v.post_id, 
+																jsonb_object_agg(f.key, v.value) as fields
+														FROM post_custom_field_values v
+														INNER JOIN custom_fields f
+														ON f.id = v.field_id
+														AND f.tenant_id = v.tenant_id
+														WHERE v.tenant_id = $1
+														%s
+														GROUP BY v.post_id
+													),
 													agg_comments AS (
 															SELECT 
 																	post_id, 
@@ -105,13 +134,15 @@ var (
 															AND posts.tenant_id = comments.tenant_id
 															WHERE posts.tenant_id = $1
 															AND comments.deleted_at IS NULL
+															%s
 															GROUP BY post_id
 													),
 													agg_votes AS (
 															SELECT 
 															post_id, 
 																	COUNT(CASE WHEN post_votes.created_at > CURRENT_DATE - INTERVAL '30 days'  THEN 1 END) as recent,
-																	COUNT(*) as all
+																	COUNT(*) as all,
+																	SUM(post_votes.weight) as weight
 															FROM post_votes 
 															INNER JOIN posts
 															ON posts.id = post_votes.post_id
@@ -126,10 +157,13 @@ var (
 																p.description, 
 																p.created_at,
 																COALESCE(agg_s.all, 0) as votes_count,
+																COALESCE(agg_s.weight, 0) as votes_weight,
 																COALESCE(agg_c.all, 0) as comments_count,
 																COALESCE(agg_s.recent, 0) AS recent_votes_count,
 																COALESCE(agg_c.recent, 0) AS recent_comments_count,																
 																p.status, 
+																p.is_private,
+																p.group_id,
 																u.id AS user_id, 
 																u.name AS user_name, 
 																u.email AS user_email,
@@ -151,7 +185,10 @@ var (
 																d.slug AS original_slug,
 																d.status AS original_status,
 																COALESCE(agg_t.tags, ARRAY[]::text[]) AS tags,
-																COALESCE(%s, false) AS has_voted
+																COALESCE(agg_f.fields, '{}'::jsonb) AS custom_fields,
+																COALESCE(%s, 0) > 0 AS has_voted,
+																COALESCE(%s, 0) AS my_vote_weight,
+																%s AS votes_revenue
 													FROM posts p
 													INNER JOIN users u
 													ON u.id = p.user_id
@@ -168,6 +205,8 @@ var (
 													ON agg_s.post_id = p.id
 													LEFT JOIN agg_tags agg_t 
 													ON agg_t.post_id = p.id
+													LEFT JOIN agg_fields agg_f
+													ON agg_f.post_id = p.id
 													WHERE p.status != ` + strconv.Itoa(int(enum.PostDeleted)) + ` AND %s`
 )
 
@@ -211,13 +250,37 @@ func setPostResponse(ctx context.Context, c *cmd.SetPostResponse) error {
 			return errors.Wrap(err, "failed to update post's response")
 		}
 
+		previousText := ""
+		if c.Post.Response != nil {
+			previousText = c.Post.Response.Text
+		}
+		if previousText != c.Text {
+			if err := insertPostRevision(trx, tenant, user, c.Post.ID, enum.RevisionResponse, "", c.Text); err != nil {
+				return err
+			}
+		}
+
+		if c.Status == enum.PostDeleted {
+			err = insertAuditLog(ctx, trx, tenant, user, auditEntry{
+				Action:     entity.AuditPostDeleted,
+				TargetType: "post",
+				TargetID:   c.Post.ID,
+				TargetName: c.Post.Title,
+				Before:     map[string]any{"number": c.Post.Number, "status": c.Post.Status},
+				After:      map[string]any{"status": c.Status, "reason": c.Text},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		c.Post.Status = c.Status
 		c.Post.Response = &entity.PostResponse{
 			Text:        c.Text,
 			RespondedAt: respondedAt,
 			User:        user,
 		}
-		return nil
+		return notifyPostStatusChanged(trx, tenant, c.Post)
 	})
 }
 
@@ -261,7 +324,7 @@ func markPostAsDuplicate(ctx context.Context, c *cmd.MarkPostAsDuplicate) error
 				Status: c.Original.Status,
 			},
 		}
-		return nil
+		return notifyPostStatusChanged(trx, tenant, c.Post)
 	})
 }
 
@@ -275,7 +338,12 @@ func countPostPerStatus(ctx context.Context, q *query.CountPostPerStatus) error
 
 		q.Result = make(map[enum.PostStatus]int)
 		stats := []*dbStatusCount{}
-		err := trx.Select(&stats, "SELECT status, COUNT(*) AS count FROM posts WHERE tenant_id = $1 GROUP BY status", tenant.ID)
+		condition := ""
+		if visibility := postVisibilityCondition(user); visibility != "" {
+			condition = "AND " + visibility
+		}
+
+		err := trx.Select(&stats, fmt.Sprintf("SELECT p.status, COUNT(*) AS count FROM posts p WHERE p.tenant_id = $1 %s GROUP BY p.status", condition), tenant.ID)
 		if err != nil {
 			return errors.Wrap(err, "failed to count posts per status")
 		}
@@ -291,13 +359,17 @@ func addNewPost(ctx context.Context, c *cmd.AddNewPost) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		var id int
 		err := trx.Get(&id,
-			`INSERT INTO posts (title, slug, number, description, tenant_id, user_id, created_at, status) 
-			 VALUES ($1, $2, (SELECT COALESCE(MAX(number), 0) + 1 FROM posts p WHERE p.tenant_id = $4), $3, $4, $5, $6, 0) 
-			 RETURNING id`, c.Title, slug.Make(c.Title), c.Description, tenant.ID, user.ID, time.Now())
+			`INSERT INTO posts (title, slug, number, description, tenant_id, user_id, created_at, status, is_private) 
+			 VALUES ($1, $2, (SELECT COALESCE(MAX(number), 0) + 1 FROM posts p WHERE p.tenant_id = $4), $3, $4, $5, $6, 0, $7) 
+			 RETURNING id`, c.Title, slug.Make(c.Title), c.Description, tenant.ID, user.ID, time.Now(), c.IsPrivate)
 		if err != nil {
 			return errors.Wrap(err, "failed add new post")
 		}
 
+		if err := insertPostRevision(trx, tenant, user, id, enum.RevisionPost, c.Title, c.Description); err != nil {
+			return err
+		}
+
 		q := &query.GetPostByID{PostID: id}
 		if err := getPostByID(ctx, q); err != nil {
 			return err
@@ -320,6 +392,10 @@ func updatePost(ctx context.Context, c *cmd.UpdatePost) error {
 			return errors.Wrap(err, "failed update post")
 		}
 
+		if err := insertPostRevision(trx, tenant, user, c.Post.ID, enum.RevisionPost, c.Title, c.Description); err != nil {
+			return err
+		}
+
 		q := &query.GetPostByID{PostID: c.Post.ID}
 		if err := getPostByID(ctx, q); err != nil {
 			return err
@@ -329,6 +405,31 @@ func updatePost(ctx context.Context, c *cmd.UpdatePost) error {
 	})
 }
 
+func setPostPrivacy(ctx context.Context, c *cmd.SetPostPrivacy) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`UPDATE posts SET is_private = $1 WHERE id = $2 AND tenant_id = $3`, c.IsPrivate, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update post privacy")
+		}
+
+		c.Post.IsPrivate = c.IsPrivate
+		return nil
+	})
+}
+
+func setPostGroup(ctx context.Context, c *cmd.SetPostGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		groupID := sql.NullInt64{Int64: int64(c.GroupID), Valid: c.GroupID > 0}
+		_, err := trx.Execute(`UPDATE posts SET group_id = $1 WHERE id = $2 AND tenant_id = $3`, groupID, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update post group")
+		}
+
+		c.Post.GroupID = c.GroupID
+		return nil
+	})
+}
+
 func getPostByID(ctx context.Context, q *query.GetPostByID) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		post, err := querySinglePost(ctx, trx, buildPostQuery(user, "p.tenant_id = $1 AND p.id = $2"), tenant.ID, q.PostID)
@@ -472,16 +573,28 @@ func searchPosts(ctx context.Context, q *query.SearchPosts) error {
 
 			}
 
+			params := []interface{}{tenant.ID, pq.Array(statuses)}
+			if len(q.Tags) > 0 {
+				params = append(params, pq.Array(q.Tags))
+			}
+
+			if len(q.CustomFields) > 0 {
+				filter, err := buildCustomFieldsFilter(trx, tenant, user, q.CustomFields)
+				if err != nil {
+					return errors.Wrap(err, "failed to build custom fields filter")
+				}
+				if filter != "" {
+					params = append(params, filter)
+					condition += fmt.Sprintf(" AND custom_fields @> $%d::jsonb", len(params))
+				}
+			}
+
 			sql := fmt.Sprintf(`
 				SELECT * FROM (%s) AS q 
 				WHERE 1 = 1 %s
 				ORDER BY %s DESC
 				LIMIT %s
 			`, innerQuery, condition, sort, q.Limit)
-			params := []interface{}{tenant.ID, pq.Array(statuses)}
-			if len(q.Tags) > 0 {
-				params = append(params, pq.Array(q.Tags))
-			}
 			err = trx.Select(&posts, sql, params...)
 		}
 
@@ -518,14 +631,44 @@ func querySinglePost(ctx context.Context, trx *dbx.Trx, query string, args ...an
 	return post.toModel(ctx), nil
 }
 
+// postVisibilityCondition hides private posts and posts restricted to other groups, except from their authors and staff
+func postVisibilityCondition(user *entity.User) string {
+	if user == nil {
+		return `(p.is_private = false AND p.group_id IS NULL)`
+	}
+	if user.IsCollaborator() {
+		return ``
+	}
+	return fmt.Sprintf(`(p.user_id = %d OR (p.is_private = false AND %s))`, user.ID, sqlGroupCondition("p.group_id", user))
+}
+
 func buildPostQuery(user *entity.User, filter string) string {
-	tagCondition := `AND tags.is_public = true`
+	tagCondition := `AND tags.is_public = true AND ` + sqlGroupCondition("tags.group_id", user)
+	fieldCondition := `AND f.is_public = true`
+	commentCondition := `AND comments.is_internal = false`
+	visibilityCondition := postVisibilityCondition(user) + ` AND `
+	revenueSubQuery := "0"
 	if user != nil && user.IsCollaborator() {
 		tagCondition = ``
+		fieldCondition = ``
+		commentCondition = ``
+		visibilityCondition = ``
+		// Each company is counted once, no matter how many of its users voted
+		revenueSubQuery = `(
+			SELECT COALESCE(SUM(c.mrr), 0) FROM companies c
+			WHERE c.tenant_id = p.tenant_id
+			AND c.id IN (
+				SELECT vu.company_id FROM post_votes pv
+				INNER JOIN users vu
+				ON vu.id = pv.user_id
+				AND vu.tenant_id = pv.tenant_id
+				WHERE pv.post_id = p.id
+			)
+		)`
 	}
-	hasVotedSubQuery := "null"
+	myVoteSubQuery := "null"
 	if user != nil {
-		hasVotedSubQuery = fmt.Sprintf("(SELECT true FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
+		myVoteSubQuery = fmt.Sprintf("(SELECT weight FROM post_votes WHERE post_id = p.id AND user_id = %d)", user.ID)
 	}
-	return fmt.Sprintf(sqlSelectPostsWhere, tagCondition, hasVotedSubQuery, filter)
+	return fmt.Sprintf(sqlSelectPostsWhere, tagCondition, fieldCondition, commentCondition, myVoteSubQuery, myVoteSubQuery, revenueSubQuery, visibilityCondition+filter)
 }
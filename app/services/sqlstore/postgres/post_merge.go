@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbPostMerge struct {
+	ID                 int          `db:"id"`
+	PostID             int          `db:"post_id"`
+	OriginalID         int          `db:"original_id"`
+	MergedAt           time.Time    `db:"merged_at"`
+	MergedBy           *dbUser      `db:"merged_by"`
+	RevertedAt         dbx.NullTime `db:"reverted_at"`
+	PreviousStatus     int          `db:"previous_status"`
+	VoterIDs           []int64      `db:"voter_ids"`
+	AddedVoterIDs      []int64      `db:"added_voter_ids"`
+	AddedSubscriberIDs []int64      `db:"added_subscriber_ids"`
+	AddedTagIDs        []int64      `db:"added_tag_ids"`
+	CopiedCommentIDs   []int64      `db:"copied_comment_ids"`
+}
+
+func (m *dbPostMerge) toModel(ctx context.Context) *entity.PostMerge {
+	merge := &entity.PostMerge{
+		ID:                 m.ID,
+		PostID:             m.PostID,
+		OriginalID:         m.OriginalID,
+		MergedAt:           m.MergedAt,
+		MergedBy:           m.MergedBy.toModel(ctx),
+		PreviousStatus:     enum.PostStatus(m.PreviousStatus),
+		VoterIDs:           toInts(m.VoterIDs),
+		AddedVoterIDs:      toInts(m.AddedVoterIDs),
+		AddedSubscriberIDs: toInts(m.AddedSubscriberIDs),
+		AddedTagIDs:        toInts(m.AddedTagIDs),
+		CopiedCommentIDs:   toInts(m.CopiedCommentIDs),
+	}
+	if m.RevertedAt.Valid {
+		merge.RevertedAt = &m.RevertedAt.Time
+	}
+	return merge
+}
+
+func toInts(values []int64) []int {
+	result := make([]int, len(values))
+	for i, v := range values {
+		result[i] = int(v)
+	}
+	return result
+}
+
+// selectIDs runs a query that returns a single integer column and aggregates it into a slice
+func selectIDs(trx *dbx.Trx, command string, args ...any) ([]int64, error) {
+	ids := make([]int64, 0)
+	err := trx.Scalar(pq.Array(&ids), "WITH q AS ("+command+") SELECT COALESCE(ARRAY_AGG(id), '{}') FROM q", args...)
+	return ids, err
+}
+
+func mergePosts(ctx context.Context, c *cmd.MergePosts) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		now := time.Now()
+
+		voterIDs, err := selectIDs(trx, `
+			SELECT user_id AS id FROM post_votes WHERE post_id = $1 AND tenant_id = $2
+		`, c.Post.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get votes of post with id '%d'", c.Post.ID)
+		}
+
+		// Users who voted on both posts keep a single vote on the original
+		addedVoterIDs, err := selectIDs(trx, `
+			INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight, proxied_by_id, note)
+			SELECT tenant_id, user_id, $3, created_at, weight, proxied_by_id, note FROM post_votes WHERE post_id = $2 AND tenant_id = $1
+			ON CONFLICT DO NOTHING
+			RETURNING user_id AS id
+		`, tenant.ID, c.Post.ID, c.Original.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to move votes to post with id '%d'", c.Original.ID)
+		}
+
+		if _, err := trx.Execute("DELETE FROM post_votes WHERE post_id = $1 AND tenant_id = $2", c.Post.ID, tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to remove votes of post with id '%d'", c.Post.ID)
+		}
+
+		// Voters are subscribed to the original so they keep getting updates about it
+		addedSubscriberIDs, err := selectIDs(trx, `
+			INSERT INTO post_subscribers (tenant_id, user_id, post_id, created_at, updated_at, status)
+			SELECT $1, s.user_id, $3, $4, $4, $5 FROM (
+				SELECT user_id FROM post_subscribers WHERE post_id = $2 AND tenant_id = $1 AND status = $5
+				UNION
+				SELECT UNNEST($6::int[]) AS user_id
+			) s
+			ON CONFLICT (user_id, post_id) DO NOTHING
+			RETURNING user_id AS id
+		`, tenant.ID, c.Post.ID, c.Original.ID, now, enum.SubscriberActive, pq.Array(voterIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to merge subscribers into post with id '%d'", c.Original.ID)
+		}
+
+		addedTagIDs, err := selectIDs(trx, `
+			INSERT INTO post_tags (tag_id, post_id, created_at, created_by_id, tenant_id)
+			SELECT pt.tag_id, $3, $4, $5, $1 FROM post_tags pt
+			WHERE pt.post_id = $2 AND pt.tenant_id = $1
+			AND NOT EXISTS (SELECT 1 FROM post_tags o WHERE o.post_id = $3 AND o.tag_id = pt.tag_id AND o.tenant_id = $1)
+			RETURNING tag_id AS id
+		`, tenant.ID, c.Post.ID, c.Original.ID, now, user.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to merge tags into post with id '%d'", c.Original.ID)
+		}
+
+		copiedCommentIDs := make([]int64, 0)
+		if c.CopyComments {
+			copiedCommentIDs, err = selectIDs(trx, `
+				INSERT INTO comments (tenant_id, post_id, content, user_id, created_at, is_internal)
+				SELECT tenant_id, $3, content, user_id, created_at, is_internal FROM comments
+				WHERE post_id = $2 AND tenant_id = $1 AND deleted_at IS NULL
+				ORDER BY created_at
+				RETURNING id
+			`, tenant.ID, c.Post.ID, c.Original.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to copy comments into post with id '%d'", c.Original.ID)
+			}
+		}
+
+		var (
+			previousResponse       dbx.NullString
+			previousResponseDate   dbx.NullTime
+			previousResponseUserID dbx.NullInt
+			previousOriginalID     dbx.NullInt
+		)
+		if c.Post.Response != nil {
+			previousResponse.String, previousResponse.Valid = c.Post.Response.Text, true
+			previousResponseDate.Time, previousResponseDate.Valid = c.Post.Response.RespondedAt, true
+			if c.Post.Response.User != nil {
+				previousResponseUserID.Int64, previousResponseUserID.Valid = int64(c.Post.Response.User.ID), true
+			}
+		}
+		if c.Post.Status == enum.PostDuplicate {
+			err := trx.Scalar(&previousOriginalID, "SELECT original_id FROM posts WHERE id = $1 AND tenant_id = $2", c.Post.ID, tenant.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to get original of post with id '%d'", c.Post.ID)
+			}
+		}
+
+		_, err = trx.Execute(`
+		UPDATE posts 
+		SET response = '', original_id = $3, response_date = $4, response_user_id = $5, status = $6 
+		WHERE id = $1 and tenant_id = $2
+		`, c.Post.ID, tenant.ID, c.Original.ID, now, user.ID, enum.PostDuplicate)
+		if err != nil {
+			return errors.Wrap(err, "failed to update post's response")
+		}
+
+		merge := &entity.PostMerge{
+			PostID:             c.Post.ID,
+			OriginalID:         c.Original.ID,
+			MergedAt:           now,
+			MergedBy:           user,
+			PreviousStatus:     c.Post.Status,
+			VoterIDs:           toInts(voterIDs),
+			AddedVoterIDs:      toInts(addedVoterIDs),
+			AddedSubscriberIDs: toInts(addedSubscriberIDs),
+			AddedTagIDs:        toInts(addedTagIDs),
+			CopiedCommentIDs:   toInts(copiedCommentIDs),
+		}
+
+		err = trx.Get(&merge.ID, `
+			INSERT INTO post_merges (
+				tenant_id, post_id, original_id, merged_at, merged_by_id, previous_status,
+				previous_response, previous_response_date, previous_response_user_id, previous_original_id,
+				voter_ids, added_voter_ids, added_subscriber_ids, added_tag_ids, copied_comment_ids
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			RETURNING id
+		`, tenant.ID, c.Post.ID, c.Original.ID, now, user.ID, c.Post.Status,
+			previousResponse, previousResponseDate, previousResponseUserID, previousOriginalID,
+			pq.Array(voterIDs), pq.Array(addedVoterIDs), pq.Array(addedSubscriberIDs), pq.Array(addedTagIDs), pq.Array(copiedCommentIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to record merge of post with id '%d'", c.Post.ID)
+		}
+
+		c.Post.Status = enum.PostDuplicate
+		c.Post.VotesCount = 0
+		c.Post.Response = &entity.PostResponse{
+			RespondedAt: now,
+			User:        user,
+			Original: &entity.OriginalPost{
+				Number: c.Original.Number,
+				Title:  c.Original.Title,
+				Slug:   c.Original.Slug,
+				Status: c.Original.Status,
+			},
+		}
+		c.Result = merge
+		return nil
+	})
+}
+
+func revertPostMerge(ctx context.Context, c *cmd.RevertPostMerge) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if c.Merge.IsReverted() {
+			return nil
+		}
+
+		now := time.Now()
+		merge := c.Merge
+
+		// Votes go back to the duplicate, keeping their original date, weight and proxy when they're still known
+		_, err := trx.Execute(`
+			INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight, proxied_by_id, note)
+			SELECT $1, v.user_id, $2, COALESCE(o.created_at, $5), COALESCE(o.weight, 1), o.proxied_by_id, COALESCE(o.note, '')
+			FROM UNNEST($4::int[]) AS v(user_id)
+			LEFT JOIN post_votes o
+			ON o.user_id = v.user_id
+			AND o.post_id = $3
+			AND o.tenant_id = $1
+			ON CONFLICT DO NOTHING
+		`, tenant.ID, merge.PostID, merge.OriginalID, pq.Array(merge.VoterIDs), now)
+		if err != nil {
+			return errors.Wrap(err, "failed to restore votes of post with id '%d'", merge.PostID)
+		}
+
+		var commands = []struct {
+			description string
+			command     string
+			ids         []int
+		}{
+			{"votes", "DELETE FROM post_votes WHERE tenant_id = $1 AND post_id = $2 AND user_id = ANY($3)", merge.AddedVoterIDs},
+			{"subscribers", "DELETE FROM post_subscribers WHERE tenant_id = $1 AND post_id = $2 AND user_id = ANY($3)", merge.AddedSubscriberIDs},
+			{"tags", "DELETE FROM post_tags WHERE tenant_id = $1 AND post_id = $2 AND tag_id = ANY($3)", merge.AddedTagIDs},
+		}
+
+		for _, item := range commands {
+			if _, err := trx.Execute(item.command, tenant.ID, merge.OriginalID, pq.Array(item.ids)); err != nil {
+				return errors.Wrap(err, "failed to remove merged %s from post with id '%d'", item.description, merge.OriginalID)
+			}
+		}
+
+		_, err = trx.Execute(`
+			UPDATE comments SET deleted_at = $1, deleted_by_id = $2 
+			WHERE tenant_id = $3 AND post_id = $4 AND id = ANY($5) AND deleted_at IS NULL
+		`, now, user.ID, tenant.ID, merge.OriginalID, pq.Array(merge.CopiedCommentIDs))
+		if err != nil {
+			return errors.Wrap(err, "failed to remove copied comments from post with id '%d'", merge.OriginalID)
+		}
+
+		_, err = trx.Execute(`
+			UPDATE posts p
+			SET status = m.previous_status, 
+					response = m.previous_response, 
+					response_date = m.previous_response_date, 
+					response_user_id = m.previous_response_user_id, 
+					original_id = m.previous_original_id
+			FROM post_merges m
+			WHERE m.id = $1
+			AND m.tenant_id = $2
+			AND p.id = m.post_id
+			AND p.tenant_id = m.tenant_id
+		`, merge.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to restore status of post with id '%d'", merge.PostID)
+		}
+
+		_, err = trx.Execute(`
+			UPDATE post_merges SET reverted_at = $1, reverted_by_id = $2 
+			WHERE id = $3 AND tenant_id = $4
+		`, now, user.ID, merge.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark merge with id '%d' as reverted", merge.ID)
+		}
+
+		merge.RevertedAt = &now
+		return nil
+	})
+}
+
+func getActivePostMerge(ctx context.Context, q *query.GetActivePostMerge) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		merge := dbPostMerge{}
+		err := trx.Get(&merge, `
+			SELECT m.id, 
+						 m.post_id, 
+						 m.original_id, 
+						 m.merged_at, 
+						 m.reverted_at, 
+						 m.previous_status,
+						 m.voter_ids, 
+						 m.added_voter_ids, 
+						 m.added_subscriber_ids, 
+						 m.added_tag_ids, 
+						 m.copied_comment_ids,
+						 u.id AS merged_by_id,
+						 u.name AS merged_by_name,
+						 u.email AS merged_by_email,
+						 u.role AS merged_by_role,
+						 u.status AS merged_by_status,
+						 u.avatar_type AS merged_by_avatar_type,
+						 u.avatar_bkey AS merged_by_avatar_bkey
+			FROM post_merges m
+			INNER JOIN users u
+			ON u.id = m.merged_by_id
+			AND u.tenant_id = m.tenant_id
+			WHERE m.post_id = $1
+			AND m.tenant_id = $2
+			AND m.reverted_at IS NULL
+			ORDER BY m.merged_at DESC
+			LIMIT 1
+		`, q.PostID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get merge of post with id '%d'", q.PostID)
+		}
+
+		q.Result = merge.toModel(ctx)
+		return nil
+	})
+}
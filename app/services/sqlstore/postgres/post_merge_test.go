@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestPostMergeStorage_KeepsProxiedVotes(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	duplicate := &cmd.AddNewPost{Title: "Add a dark mode", Description: "It's easier on the eyes"}
+	err := bus.Dispatch(jonSnowCtx, duplicate)
+	Expect(err).IsNil()
+
+	original := &cmd.AddNewPost{Title: "Support dark themes", Description: "For the whole site"}
+	err = bus.Dispatch(jonSnowCtx, original)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: duplicate.Result, User: aryaStark, ProxiedBy: jonSnow, Note: "Asked on a call"})
+	Expect(err).IsNil()
+
+	expectProxiedVote := func(postID int) {
+		listVotes := &query.ListPostVotes{PostID: postID, IncludeEmail: true}
+		err := bus.Dispatch(jonSnowCtx, listVotes)
+		Expect(err).IsNil()
+		Expect(listVotes.Result).HasLen(1)
+		Expect(listVotes.Result[0].User.ID).Equals(aryaStark.ID)
+		Expect(listVotes.Result[0].ProxiedBy.ID).Equals(jonSnow.ID)
+		Expect(listVotes.Result[0].Note).Equals("Asked on a call")
+	}
+
+	merge := &cmd.MergePosts{Post: duplicate.Result, Original: original.Result}
+	err = bus.Dispatch(jonSnowCtx, merge)
+	Expect(err).IsNil()
+	expectProxiedVote(original.Result.ID)
+
+	getMerge := &query.GetActivePostMerge{PostID: duplicate.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getMerge)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevertPostMerge{Merge: getMerge.Result})
+	Expect(err).IsNil()
+	expectProxiedVote(duplicate.Result.ID)
+}
@@ -132,6 +132,72 @@ func TestPostStorage_AddAndReturnComments(t *testing.T) {
 	Expect(commentsByPost.Result[1].User.Name).Equals("Arya Stark")
 }
 
+func TestPostStorage_PrivatePost(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My private post", Description: "only for staff", IsPrivate: true}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+	Expect(newPost.Result.IsPrivate).IsTrue()
+
+	byAuthor := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(aryaStarkCtx, byAuthor)
+	Expect(err).IsNil()
+
+	byStaff := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(jonSnowCtx, byStaff)
+	Expect(err).IsNil()
+
+	byOther := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(sansaStarkCtx, byOther)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	byAnonymous := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(demoTenantCtx, byAnonymous)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostPrivacy{Post: byStaff.Result, IsPrivate: false})
+	Expect(err).IsNil()
+
+	byAnonymous = &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(demoTenantCtx, byAnonymous)
+	Expect(err).IsNil()
+	Expect(byAnonymous.Result.IsPrivate).IsFalse()
+}
+
+func TestPostStorage_InternalComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddNewComment{Post: newPost.Result, Content: "Public comment"})
+	Expect(err).IsNil()
+
+	internal := &cmd.AddNewComment{Post: newPost.Result, Content: "Internal note", IsInternal: true}
+	err = bus.Dispatch(jonSnowCtx, internal)
+	Expect(err).IsNil()
+	Expect(internal.Result.IsInternal).IsTrue()
+
+	byStaff := &query.GetCommentsByPost{Post: newPost.Result}
+	err = bus.Dispatch(jonSnowCtx, byStaff)
+	Expect(err).IsNil()
+	Expect(byStaff.Result).HasLen(2)
+
+	byVisitor := &query.GetCommentsByPost{Post: newPost.Result}
+	err = bus.Dispatch(aryaStarkCtx, byVisitor)
+	Expect(err).IsNil()
+	Expect(byVisitor.Result).HasLen(1)
+	Expect(byVisitor.Result[0].Content).Equals("Public comment")
+
+	commentByID := &query.GetCommentByID{CommentID: internal.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, commentByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
 func TestPostStorage_AddGetUpdateComment(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -286,6 +352,87 @@ func TestPostStorage_AddVote_Twice(t *testing.T) {
 	Expect(getPost.Result.VotesCount).Equals(1)
 }
 
+func TestPostStorage_AddVote_Weighted(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(
+		jonSnowCtx,
+		&cmd.AddVote{Post: newPost.Result, User: jonSnow, Weight: 2},
+		&cmd.AddVote{Post: newPost.Result, User: aryaStark, Weight: 3},
+	)
+	Expect(err).IsNil()
+
+	// Without an explicit weight, the existing vote is kept
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: aryaStark})
+	Expect(err).IsNil()
+
+	getPost := &query.GetPostByID{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getPost)
+	Expect(err).IsNil()
+	Expect(getPost.Result.VotesCount).Equals(2)
+	Expect(getPost.Result.VotesWeight).Equals(5)
+	Expect(getPost.Result.MyVoteWeight).Equals(2)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddVote{Post: newPost.Result, User: jonSnow, Weight: 1})
+	Expect(err).IsNil()
+
+	getBudget := &query.GetVoteBudget{}
+	err = bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(1)
+
+	getBudget = &query.GetVoteBudget{ExcludePostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(0)
+
+	getBudget = &query.GetVoteBudget{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getBudget)
+	Expect(err).IsNil()
+	Expect(getBudget.Result.Spent).Equals(3)
+
+	listVotes := &query.ListPostVotes{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, listVotes)
+	Expect(err).IsNil()
+	Expect(listVotes.Result).HasLen(2)
+	Expect(listVotes.Result[0].Weight).Equals(1)
+	Expect(listVotes.Result[1].Weight).Equals(3)
+}
+
+func TestPostStorage_AddVote_BudgetExceeded(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	post1 := &cmd.AddNewPost{Title: "My first post", Description: "with this description"}
+	post2 := &cmd.AddNewPost{Title: "My second post", Description: "with this description"}
+	err := bus.Dispatch(jonSnowCtx, post1, post2)
+	Expect(err).IsNil()
+
+	tenant := *demoTenant
+	tenant.VotingMode = enum.VotingModeBudget
+	tenant.VoteBudget = 5
+	tenant.MaxVotesPerPost = 3
+	ctx := withTenant(jonSnowCtx, &tenant)
+
+	err = bus.Dispatch(ctx, &cmd.AddVote{Post: post1.Result, User: jonSnow, Weight: 3})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(ctx, &cmd.AddVote{Post: post2.Result, User: jonSnow, Weight: 3})
+	Expect(errors.Cause(err)).Equals(app.ErrVoteBudgetExceeded)
+
+	err = bus.Dispatch(ctx, &cmd.AddVote{Post: post2.Result, User: jonSnow, Weight: 2})
+	Expect(err).IsNil()
+
+	// The weight of the vote that is being changed is given back first
+	err = bus.Dispatch(ctx, &cmd.AddVote{Post: post1.Result, User: jonSnow, Weight: 3})
+	Expect(err).IsNil()
+}
+
 func TestPostStorage_RemoveVote(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
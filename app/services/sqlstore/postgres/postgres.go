@@ -8,6 +8,7 @@ import (
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 )
 
 func init() {
@@ -41,9 +42,26 @@ func (s Service) Init() {
 	bus.AddHandler(addNewNotification)
 	bus.AddHandler(addSubscriber)
 	bus.AddHandler(removeSubscriber)
+	bus.AddHandler(muteComments)
+	bus.AddHandler(unmuteComments)
 	bus.AddHandler(supressEmail)
+	bus.AddHandler(supressUserEmail)
+	bus.AddHandler(unsupressUserEmail)
+	bus.AddHandler(getSupressedEmails)
 	bus.AddHandler(getActiveSubscribers)
 
+	bus.AddHandler(getEmailTemplate)
+	bus.AddHandler(listEmailTemplates)
+	bus.AddHandler(saveEmailTemplate)
+	bus.AddHandler(deleteEmailTemplate)
+
+	bus.AddHandler(queueEmail)
+	bus.AddHandler(getQueuedEmails)
+	bus.AddHandler(searchOutboundEmails)
+	bus.AddHandler(markEmailAsSent)
+	bus.AddHandler(markEmailAsFailed)
+	bus.AddHandler(purgeOutboundEmails)
+
 	bus.AddHandler(getTagBySlug)
 	bus.AddHandler(getAssignedTags)
 	bus.AddHandler(getAllTags)
@@ -52,13 +70,80 @@ func (s Service) Init() {
 	bus.AddHandler(deleteTag)
 	bus.AddHandler(assignTag)
 	bus.AddHandler(unassignTag)
+	bus.AddHandler(followTag)
+	bus.AddHandler(unfollowTag)
+	bus.AddHandler(getFollowedTags)
+
+	bus.AddHandler(getCustomFieldByKey)
+	bus.AddHandler(getAllCustomFields)
+	bus.AddHandler(addNewCustomField)
+	bus.AddHandler(updateCustomField)
+	bus.AddHandler(deleteCustomField)
+	bus.AddHandler(setPostCustomFieldValues)
+
+	bus.AddHandler(getCompanyByID)
+	bus.AddHandler(getCompanyByName)
+	bus.AddHandler(getAllCompanies)
+	bus.AddHandler(addNewCompany)
+	bus.AddHandler(updateCompany)
+	bus.AddHandler(deleteCompany)
+	bus.AddHandler(setUserCompany)
+
+	bus.AddHandler(getCustomRoleByID)
+	bus.AddHandler(getCustomRoleByName)
+	bus.AddHandler(getAllCustomRoles)
+	bus.AddHandler(addNewCustomRole)
+	bus.AddHandler(updateCustomRole)
+	bus.AddHandler(deleteCustomRole)
+	bus.AddHandler(setUserCustomRole)
+
+	bus.AddHandler(getUserGroupByID)
+	bus.AddHandler(getUserGroupByName)
+	bus.AddHandler(getAllUserGroups)
+	bus.AddHandler(getUserGroupMembers)
+	bus.AddHandler(addNewUserGroup)
+	bus.AddHandler(updateUserGroup)
+	bus.AddHandler(deleteUserGroup)
+	bus.AddHandler(addUserToGroup)
+	bus.AddHandler(removeUserFromGroup)
+	bus.AddHandler(syncUserOAuthGroups)
+
+	bus.AddHandler(getUserTwoFactor)
+	bus.AddHandler(setupUserTwoFactor)
+	bus.AddHandler(enableUserTwoFactor)
+	bus.AddHandler(disableUserTwoFactor)
+	bus.AddHandler(regenerateRecoveryCodes)
+	bus.AddHandler(markTwoFactorStepUsed)
+	bus.AddHandler(useRecoveryCode)
+	bus.AddHandler(recordTwoFactorFailure)
+
+	bus.AddHandler(createUserSession)
+	bus.AddHandler(getUserSessionByKey)
+	bus.AddHandler(getUserSessions)
+	bus.AddHandler(touchUserSession)
+	bus.AddHandler(revokeUserSession)
+	bus.AddHandler(revokeUserSessions)
+	bus.AddHandler(revokeAllUserSessions)
+
+	bus.AddHandler(savePushSubscription)
+	bus.AddHandler(deletePushSubscription)
+	bus.AddHandler(getPushSubscriptions)
+
+	bus.AddHandler(searchAuditLogs)
+
+	bus.AddHandler(addDigestItems)
+	bus.AddHandler(deleteDigestItems)
+	bus.AddHandler(getPendingEmailDigests)
 
 	bus.AddHandler(addVote)
 	bus.AddHandler(removeVote)
 	bus.AddHandler(listPostVotes)
+	bus.AddHandler(getVoteBudget)
 
 	bus.AddHandler(addNewPost)
 	bus.AddHandler(updatePost)
+	bus.AddHandler(setPostPrivacy)
+	bus.AddHandler(setPostGroup)
 	bus.AddHandler(getPostByID)
 	bus.AddHandler(getPostBySlug)
 	bus.AddHandler(getPostByNumber)
@@ -69,6 +154,13 @@ func (s Service) Init() {
 	bus.AddHandler(markPostAsDuplicate)
 	bus.AddHandler(setPostResponse)
 	bus.AddHandler(postIsReferenced)
+	bus.AddHandler(mergePosts)
+	bus.AddHandler(revertPostMerge)
+	bus.AddHandler(getActivePostMerge)
+
+	bus.AddHandler(getRevisionsByPost)
+	bus.AddHandler(getRevisionByID)
+	bus.AddHandler(getPreviousRevision)
 
 	bus.AddHandler(setAttachments)
 	bus.AddHandler(getAttachments)
@@ -87,7 +179,10 @@ func (s Service) Init() {
 	bus.AddHandler(unblockUser)
 	bus.AddHandler(regenerateAPIKey)
 	bus.AddHandler(userSubscribedTo)
+	bus.AddHandler(userMutedCommentsOn)
 	bus.AddHandler(deleteCurrentUser)
+	bus.AddHandler(deleteUser)
+	bus.AddHandler(changeUserName)
 	bus.AddHandler(changeUserEmail)
 	bus.AddHandler(changeUserRole)
 	bus.AddHandler(updateCurrentUserSettings)
@@ -102,15 +197,36 @@ func (s Service) Init() {
 	bus.AddHandler(getAllUsers)
 	bus.AddHandler(getAllUsersNames)
 
+	bus.AddHandler(getAPITokenByKey)
+	bus.AddHandler(getAPITokenByID)
+	bus.AddHandler(getCurrentUserAPITokens)
+	bus.AddHandler(getAllAPITokens)
+	bus.AddHandler(addNewAPIToken)
+	bus.AddHandler(revokeAPIToken)
+	bus.AddHandler(markAPITokenAsUsed)
+
+	bus.AddHandler(getOAuthClientByID)
+	bus.AddHandler(getOAuthClientByClientID)
+	bus.AddHandler(getAllOAuthClients)
+	bus.AddHandler(addNewOAuthClient)
+	bus.AddHandler(revokeOAuthClient)
+	bus.AddHandler(addOAuthAuthorizationCode)
+	bus.AddHandler(useOAuthAuthorizationCode)
+	bus.AddHandler(addOAuthRefreshToken)
+	bus.AddHandler(useOAuthRefreshToken)
+
 	bus.AddHandler(createTenant)
 	bus.AddHandler(getFirstTenant)
 	bus.AddHandler(getTenantByDomain)
+	bus.AddHandler(getTenantByID)
 	bus.AddHandler(activateTenant)
 	bus.AddHandler(isSubdomainAvailable)
 	bus.AddHandler(isCNAMEAvailable)
 	bus.AddHandler(updateTenantSettings)
 	bus.AddHandler(updateTenantPrivacySettings)
+	bus.AddHandler(updateTenantVotingSettings)
 	bus.AddHandler(updateTenantEmailAuthAllowedSettings)
+	bus.AddHandler(updateTenantTwoFactorSettings)
 	bus.AddHandler(updateTenantAdvancedSettings)
 
 	bus.AddHandler(getVerificationByKey)
@@ -121,6 +237,9 @@ func (s Service) Init() {
 	bus.AddHandler(getCustomOAuthConfigByProvider)
 	bus.AddHandler(saveCustomOAuthConfig)
 
+	bus.AddHandler(getSAMLConfig)
+	bus.AddHandler(saveSAMLConfig)
+
 	bus.AddHandler(getWebhook)
 	bus.AddHandler(listAllWebhooks)
 	bus.AddHandler(listAllWebhooksByType)
@@ -149,3 +268,24 @@ func using(ctx context.Context, handler SqlHandler) error {
 	user, _ := ctx.Value(app.UserCtxKey).(*entity.User)
 	return handler(trx, tenant, user)
 }
+
+// usingOwnTransaction is like using, but runs given handler in a new transaction that is committed right away,
+// so that its changes are kept even if the transaction of current context is rolled back later
+func usingOwnTransaction(ctx context.Context, handler SqlHandler) error {
+	trx, err := dbx.BeginTx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to open transaction")
+	}
+
+	tenant, _ := ctx.Value(app.TenantCtxKey).(*entity.Tenant)
+	user, _ := ctx.Value(app.UserCtxKey).(*entity.User)
+	if err := handler(trx, tenant, user); err != nil {
+		_ = trx.Rollback()
+		return err
+	}
+
+	if err := trx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbPushSubscription struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	Endpoint  string    `db:"endpoint"`
+	P256dh    string    `db:"p256dh"`
+	Auth      string    `db:"auth"`
+	UserAgent string    `db:"user_agent"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (s *dbPushSubscription) toModel() *entity.PushSubscription {
+	return &entity.PushSubscription{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Endpoint:  s.Endpoint,
+		P256dh:    s.P256dh,
+		Auth:      s.Auth,
+		UserAgent: s.UserAgent,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// A browser has a single endpoint, so subscribing again replaces the keys and moves it to the current user
+func savePushSubscription(ctx context.Context, c *cmd.SavePushSubscription) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO push_subscriptions (tenant_id, user_id, endpoint, p256dh, auth, user_agent, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tenant_id, endpoint)
+			DO UPDATE SET user_id = $2, p256dh = $4, auth = $5, user_agent = $6`,
+			tenant.ID, user.ID, c.Endpoint, c.P256dh, c.Auth, truncate(c.UserAgent, 500), time.Now(),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to save push subscription")
+		}
+		return nil
+	})
+}
+
+func deletePushSubscription(ctx context.Context, c *cmd.DeletePushSubscription) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(
+			"DELETE FROM push_subscriptions WHERE tenant_id = $1 AND endpoint = $2",
+			tenant.ID, c.Endpoint,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete push subscription")
+		}
+		return nil
+	})
+}
+
+func getPushSubscriptions(ctx context.Context, q *query.GetPushSubscriptions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		subscriptions := []*dbPushSubscription{}
+		err := trx.Select(&subscriptions, `
+			SELECT id, user_id, endpoint, p256dh, auth, user_agent, created_at
+			FROM push_subscriptions
+			WHERE user_id = $1 AND tenant_id = $2
+			ORDER BY created_at DESC`,
+			q.UserID, tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to get push subscriptions of user '%d'", q.UserID)
+		}
+
+		q.Result = make([]*entity.PushSubscription, len(subscriptions))
+		for i, subscription := range subscriptions {
+			q.Result[i] = subscription.toModel()
+		}
+		return nil
+	})
+}
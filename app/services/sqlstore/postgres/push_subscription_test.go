@@ -0,0 +1,93 @@
+package postgres_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+)
+
+func TestPushSubscriptionStorage_SaveAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx,
+		&cmd.SavePushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/1", P256dh: "key1", Auth: "auth1", UserAgent: "Chrome"},
+		&cmd.SavePushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/2", P256dh: "key2", Auth: "auth2", UserAgent: "Firefox"},
+	)
+	Expect(err).IsNil()
+
+	// The same browser is now used by Arya
+	err = bus.Dispatch(aryaStarkCtx, &cmd.SavePushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/2", P256dh: "key3", Auth: "auth3", UserAgent: "Firefox"})
+	Expect(err).IsNil()
+
+	jonSubscriptions := &query.GetPushSubscriptions{UserID: jonSnow.ID}
+	aryaSubscriptions := &query.GetPushSubscriptions{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, jonSubscriptions, aryaSubscriptions)
+	Expect(err).IsNil()
+
+	Expect(jonSubscriptions.Result).HasLen(1)
+	Expect(jonSubscriptions.Result[0].Endpoint).Equals("https://fcm.googleapis.com/fcm/send/1")
+	Expect(jonSubscriptions.Result[0].P256dh).Equals("key1")
+	Expect(jonSubscriptions.Result[0].UserAgent).Equals("Chrome")
+
+	Expect(aryaSubscriptions.Result).HasLen(1)
+	Expect(aryaSubscriptions.Result[0].P256dh).Equals("key3")
+	Expect(aryaSubscriptions.Result[0].Auth).Equals("auth3")
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeletePushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/1"})
+	Expect(err).IsNil()
+
+	jonSubscriptions = &query.GetPushSubscriptions{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, jonSubscriptions)
+	Expect(err).IsNil()
+	Expect(jonSubscriptions.Result).HasLen(0)
+
+	avengersSubscriptions := &query.GetPushSubscriptions{UserID: aryaStark.ID}
+	err = bus.Dispatch(avengersTenantCtx, avengersSubscriptions)
+	Expect(err).IsNil()
+	Expect(avengersSubscriptions.Result).HasLen(0)
+}
+
+func TestPushSubscriptionStorage_ActiveSubscribers(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	// Push notifications are never enabled by default
+	pushSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelPush, Event: enum.NotificationEventNewPost}
+	err = bus.Dispatch(jonSnowCtx, pushSubscribers)
+	Expect(err).IsNil()
+	Expect(pushSubscribers.Result).HasLen(0)
+
+	pushAndWeb := strconv.Itoa(int(enum.NotificationChannelWeb | enum.NotificationChannelPush))
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{enum.NotificationEventNewPost.UserSettingsKeyName: pushAndWeb},
+	})
+	Expect(err).IsNil()
+	err = bus.Dispatch(aryaStarkCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{enum.NotificationEventNewPost.UserSettingsKeyName: pushAndWeb},
+	})
+	Expect(err).IsNil()
+
+	// Only users with a subscribed browser get push notifications
+	err = bus.Dispatch(jonSnowCtx, &cmd.SavePushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/1", P256dh: "key1", Auth: "auth1"})
+	Expect(err).IsNil()
+
+	pushSubscribers = &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelPush, Event: enum.NotificationEventNewPost}
+	webSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	err = bus.Dispatch(jonSnowCtx, pushSubscribers, webSubscribers)
+	Expect(err).IsNil()
+
+	Expect(pushSubscribers.Result).HasLen(1)
+	Expect(pushSubscribers.Result[0].ID).Equals(jonSnow.ID)
+
+	Expect(webSubscribers.Result).HasLen(2)
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/realtime"
+)
+
+// notifyRealtime sends an event to the browsers connected to any instance
+// Postgres only delivers it once current transaction is committed, so nothing is sent for changes that are rolled back
+func notifyRealtime(trx *dbx.Trx, tenant *entity.Tenant, e *realtime.Event) error {
+	e.TenantID = tenant.ID
+	payload, err := realtime.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	if _, err := trx.Execute("SELECT pg_notify($1, $2)", realtime.Channel, payload); err != nil {
+		return errors.Wrap(err, "failed to notify event '%s'", e.Type)
+	}
+	return nil
+}
+
+func notifyVotesChanged(trx *dbx.Trx, tenant *entity.Tenant, post *entity.Post) error {
+	var votes struct {
+		Count  int `db:"count"`
+		Weight int `db:"weight"`
+	}
+	err := trx.Get(&votes, `
+		SELECT COUNT(*) AS count, COALESCE(SUM(weight), 0) AS weight
+		FROM post_votes
+		WHERE post_id = $1 AND tenant_id = $2
+	`, post.ID, tenant.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to count votes of post with id '%d'", post.ID)
+	}
+
+	return notifyRealtime(trx, tenant, &realtime.Event{
+		Type: realtime.PostVotesChanged,
+		Data: map[string]any{
+			"postNumber":  post.Number,
+			"votesCount":  votes.Count,
+			"votesWeight": votes.Weight,
+		},
+		Post: realtime.VisibilityOf(post),
+	})
+}
+
+func notifyPostStatusChanged(trx *dbx.Trx, tenant *entity.Tenant, post *entity.Post) error {
+	return notifyRealtime(trx, tenant, &realtime.Event{
+		Type: realtime.PostStatusChanged,
+		Data: map[string]any{
+			"postNumber": post.Number,
+			"status":     post.Status.Name(),
+		},
+		Post: realtime.VisibilityOf(post),
+	})
+}
+
+// notifyRoadmapPostMoved sends the new place of a post on the roadmap, a zero column meaning it was removed from it
+func notifyRoadmapPostMoved(trx *dbx.Trx, tenant *entity.Tenant, postID, columnID, position int) error {
+	var post struct {
+		Number    int  `db:"number"`
+		UserID    int  `db:"user_id"`
+		IsPrivate bool `db:"is_private"`
+		GroupID   int  `db:"group_id"`
+	}
+	err := trx.Get(&post, `
+		SELECT number, user_id, is_private, COALESCE(group_id, 0) AS group_id
+		FROM posts
+		WHERE id = $1 AND tenant_id = $2
+	`, postID, tenant.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get post with id '%d'", postID)
+	}
+
+	var column *realtime.ColumnVisibility
+	if columnID > 0 {
+		var dbColumn struct {
+			IsVisibleToPublic bool `db:"is_visible_to_public"`
+			GroupID           int  `db:"group_id"`
+		}
+		err := trx.Get(&dbColumn, `
+			SELECT is_visible_to_public, COALESCE(group_id, 0) AS group_id
+			FROM roadmap_columns
+			WHERE id = $1 AND tenant_id = $2
+		`, columnID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get roadmap column with id '%d'", columnID)
+		}
+		column = &realtime.ColumnVisibility{
+			IsVisibleToPublic: dbColumn.IsVisibleToPublic,
+			GroupID:           dbColumn.GroupID,
+		}
+	}
+
+	return notifyRealtime(trx, tenant, &realtime.Event{
+		Type: realtime.RoadmapPostMoved,
+		Data: map[string]any{
+			"postNumber": post.Number,
+			"columnId":   columnID,
+			"position":   position,
+		},
+		Post: &realtime.PostVisibility{
+			AuthorID:  post.UserID,
+			IsPrivate: post.IsPrivate,
+			GroupID:   post.GroupID,
+		},
+		Column: column,
+	})
+}
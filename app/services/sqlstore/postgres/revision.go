@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbRevision struct {
+	ID        int           `db:"id"`
+	PostID    int           `db:"post_id"`
+	CommentID sql.NullInt64 `db:"comment_id"`
+	Kind      int           `db:"kind"`
+	Title     string        `db:"title"`
+	Content   string        `db:"content"`
+	CreatedAt time.Time     `db:"created_at"`
+	CreatedBy *dbUser       `db:"created_by"`
+}
+
+func (r *dbRevision) toModel(ctx context.Context) *entity.Revision {
+	return &entity.Revision{
+		ID:        r.ID,
+		PostID:    r.PostID,
+		CommentID: int(r.CommentID.Int64),
+		Kind:      enum.RevisionKind(r.Kind),
+		Title:     r.Title,
+		Content:   r.Content,
+		CreatedAt: r.CreatedAt,
+		CreatedBy: r.CreatedBy.toModel(ctx),
+	}
+}
+
+const sqlSelectRevisions = `
+	SELECT r.id, r.post_id, r.comment_id, r.kind, r.title, r.content, r.created_at,
+				 u.id AS created_by_id,
+				 u.name AS created_by_name,
+				 u.email AS created_by_email,
+				 u.role AS created_by_role,
+				 u.status AS created_by_status,
+				 u.avatar_type AS created_by_avatar_type,
+				 u.avatar_bkey AS created_by_avatar_bkey
+	FROM revisions r
+	INNER JOIN users u
+	ON u.id = r.created_by_id
+	AND u.tenant_id = r.tenant_id
+`
+
+func getRevisionsByPost(ctx context.Context, q *query.GetRevisionsByPost) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revisions := []*dbRevision{}
+		err := trx.Select(&revisions, sqlSelectRevisions+`
+			WHERE r.tenant_id = $1 AND r.post_id = $2
+			ORDER BY r.created_at DESC, r.id DESC
+		`, tenant.ID, q.PostID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revisions of post with id '%d'", q.PostID)
+		}
+
+		q.Result = make([]*entity.Revision, len(revisions))
+		for i, revision := range revisions {
+			q.Result[i] = revision.toModel(ctx)
+		}
+		return nil
+	})
+}
+
+func getRevisionByID(ctx context.Context, q *query.GetRevisionByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revision := dbRevision{}
+		err := trx.Get(&revision, sqlSelectRevisions+`
+			WHERE r.tenant_id = $1 AND r.post_id = $2 AND r.id = $3
+		`, tenant.ID, q.PostID, q.RevisionID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revision with id '%d'", q.RevisionID)
+		}
+
+		q.Result = revision.toModel(ctx)
+		return nil
+	})
+}
+
+func getPreviousRevision(ctx context.Context, q *query.GetPreviousRevision) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		revision := dbRevision{}
+		err := trx.Get(&revision, sqlSelectRevisions+`
+			WHERE r.tenant_id = $1 AND r.post_id = $2 AND r.kind = $3
+			AND COALESCE(r.comment_id, 0) = $4
+			AND (r.created_at, r.id) < ($5, $6)
+			ORDER BY r.created_at DESC, r.id DESC
+			LIMIT 1
+		`, tenant.ID, q.Revision.PostID, q.Revision.Kind, q.Revision.CommentID, q.Revision.CreatedAt, q.Revision.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get revision previous to '%d'", q.Revision.ID)
+		}
+
+		q.Result = revision.toModel(ctx)
+		return nil
+	})
+}
+
+func insertPostRevision(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, postID int, kind enum.RevisionKind, title, content string) error {
+	_, err := trx.Execute(`
+		INSERT INTO revisions (tenant_id, post_id, kind, title, content, created_at, created_by_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, tenant.ID, postID, kind, title, content, time.Now(), user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add revision of post with id '%d'", postID)
+	}
+	return nil
+}
+
+func insertCommentRevision(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, commentID int, content string) error {
+	_, err := trx.Execute(`
+		INSERT INTO revisions (tenant_id, post_id, comment_id, kind, content, created_at, created_by_id)
+		SELECT tenant_id, post_id, id, $3, $4, $5, $6 FROM comments WHERE id = $1 AND tenant_id = $2
+	`, commentID, tenant.ID, enum.RevisionComment, content, time.Now(), user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add revision of comment with id '%d'", commentID)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestRevisionStorage_PostEdits(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdatePost{Post: newPost.Result, Title: "My updated post", Description: "with a better description"})
+	Expect(err).IsNil()
+
+	getRevisions := &query.GetRevisionsByPost{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getRevisions)
+	Expect(err).IsNil()
+	Expect(getRevisions.Result).HasLen(2)
+
+	latest := getRevisions.Result[0]
+	Expect(latest.Kind).Equals(enum.RevisionPost)
+	Expect(latest.Title).Equals("My updated post")
+	Expect(latest.Content).Equals("with a better description")
+	Expect(latest.CreatedBy.ID).Equals(jonSnow.ID)
+
+	original := getRevisions.Result[1]
+	Expect(original.Title).Equals("My new post")
+	Expect(original.Content).Equals("with this description")
+	Expect(original.CreatedBy.ID).Equals(aryaStark.ID)
+
+	getPrevious := &query.GetPreviousRevision{Revision: latest}
+	err = bus.Dispatch(jonSnowCtx, getPrevious)
+	Expect(err).IsNil()
+	Expect(getPrevious.Result.ID).Equals(original.ID)
+
+	getPrevious = &query.GetPreviousRevision{Revision: original}
+	err = bus.Dispatch(jonSnowCtx, getPrevious)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestRevisionStorage_ResponseAndComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostResponse{Post: newPost.Result, Text: "We're on it", Status: enum.PostStarted})
+	Expect(err).IsNil()
+
+	// Changing only the status does not create a new revision
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostResponse{Post: newPost.Result, Text: "We're on it", Status: enum.PostCompleted})
+	Expect(err).IsNil()
+
+	newComment := &cmd.AddNewComment{Post: newPost.Result, Content: "First!"}
+	err = bus.Dispatch(aryaStarkCtx, newComment)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.UpdateComment{CommentID: newComment.Result.ID, Content: "Second!"})
+	Expect(err).IsNil()
+
+	getRevisions := &query.GetRevisionsByPost{PostID: newPost.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getRevisions)
+	Expect(err).IsNil()
+	Expect(getRevisions.Result).HasLen(4)
+
+	Expect(getRevisions.Result[0].Kind).Equals(enum.RevisionComment)
+	Expect(getRevisions.Result[0].CommentID).Equals(newComment.Result.ID)
+	Expect(getRevisions.Result[0].Content).Equals("Second!")
+	Expect(getRevisions.Result[1].Kind).Equals(enum.RevisionComment)
+	Expect(getRevisions.Result[1].Content).Equals("First!")
+	Expect(getRevisions.Result[2].Kind).Equals(enum.RevisionResponse)
+	Expect(getRevisions.Result[2].Content).Equals("We're on it")
+	Expect(getRevisions.Result[3].Kind).Equals(enum.RevisionPost)
+
+	getRevision := &query.GetRevisionByID{PostID: newPost.Result.ID, RevisionID: getRevisions.Result[1].ID}
+	err = bus.Dispatch(jonSnowCtx, getRevision)
+	Expect(err).IsNil()
+	Expect(getRevision.Result.Content).Equals("First!")
+}
@@ -5,12 +5,14 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/actions"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 	"github.com/gosimple/slug"
 )
 
@@ -20,8 +22,9 @@ type dbRoadmapColumn struct {
 	Name              string    `db:"name"`
 	Slug              string    `db:"slug"`
 	Position          int       `db:"position"`
-	IsVisibleToPublic bool      `db:"is_visible_to_public"`
-	CreatedAt         time.Time `db:"created_at"`
+	IsVisibleToPublic bool          `db:"is_visible_to_public"`
+	GroupID           sql.NullInt64 `db:"group_id"`
+	CreatedAt         time.Time     `db:"created_at"`
 }
 
 type dbRoadmapAssignment struct {
@@ -42,6 +45,7 @@ func (r *dbRoadmapColumn) toModel() *entity.RoadmapColumn {
 		Slug:              r.Slug,
 		Position:          r.Position,
 		IsVisibleToPublic: r.IsVisibleToPublic,
+		GroupID:           int(r.GroupID.Int64),
 		CreatedAt:         r.CreatedAt,
 		Posts:             make([]*entity.Post, 0),
 	}
@@ -64,12 +68,12 @@ func GetRoadmapColumns(ctx context.Context, q *query.GetRoadmapColumns) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		dbColumns := make([]*dbRoadmapColumn, 0)
 		query := `
-			SELECT id, tenant_id, name, slug, position, is_visible_to_public, created_at
+			SELECT id, tenant_id, name, slug, position, is_visible_to_public, group_id, created_at
 			FROM roadmap_columns
 			WHERE tenant_id = $1
 		`
 		if !q.IncludePrivate {
-			query += " AND is_visible_to_public = true"
+			query += " AND is_visible_to_public = true AND " + sqlGroupCondition("group_id", user)
 		}
 		query += " ORDER BY position ASC"
 		
@@ -93,12 +97,12 @@ func GetRoadmapData(ctx context.Context, q *query.GetRoadmapData) error {
 		// Get all columns
 		dbColumns := make([]*dbRoadmapColumn, 0)
 		columnQuery := `
-			SELECT id, tenant_id, name, slug, position, is_visible_to_public, created_at
+			SELECT id, tenant_id, name, slug, position, is_visible_to_public, group_id, created_at
 			FROM roadmap_columns
 			WHERE tenant_id = $1
 		`
 		if !q.IncludePrivate {
-			columnQuery += " AND is_visible_to_public = true"
+			columnQuery += " AND is_visible_to_public = true AND " + sqlGroupCondition("group_id", user)
 		}
 		columnQuery += " ORDER BY position ASC"
 		
@@ -141,8 +145,8 @@ func GetRoadmapData(ctx context.Context, q *query.GetRoadmapData) error {
 			for _, postID := range postIDs {
 				post, err := querySinglePost(ctx, trx, buildPostQuery(user, "p.tenant_id = $1 AND p.id = $2"), tenant.ID, postID)
 				if err != nil {
-					if err == sql.ErrNoRows {
-						// Post might have been deleted, skip it
+					if errors.Cause(err) == app.ErrNotFound {
+						// Post might have been deleted or is private to the current user, skip it
 						continue
 					}
 					return err
@@ -211,7 +215,7 @@ func AssignPostToColumn(ctx context.Context, c *cmd.AssignPostToColumn) error {
 		}
 
 		c.Result = assignment.toModel()
-		return nil
+		return notifyRoadmapPostMoved(trx, tenant, c.PostID, c.ColumnID, c.Position)
 	})
 }
 
@@ -222,19 +226,30 @@ func RemovePostFromRoadmap(ctx context.Context, c *cmd.RemovePostFromRoadmap) er
 			DELETE FROM roadmap_post_assignments
 			WHERE post_id = $1 AND tenant_id = $2
 		`, c.PostID, tenant.ID)
-		return err
+		if err != nil {
+			return err
+		}
+		return notifyRoadmapPostMoved(trx, tenant, c.PostID, 0, 0)
 	})
 }
 
 // ReorderPostInColumn changes the position of a post within its column
 func ReorderPostInColumn(ctx context.Context, c *cmd.ReorderPostInColumn) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		_, err := trx.Execute(`
+		var columnID int
+		err := trx.Scalar(&columnID, `
 			UPDATE roadmap_post_assignments
 			SET position = $1
-			WHERE post_id = $2
-		`, c.NewPosition, c.PostID)
-		return err
+			WHERE post_id = $2 AND tenant_id = $3
+			RETURNING column_id
+		`, c.NewPosition, c.PostID, tenant.ID)
+		if errors.Cause(err) == app.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return notifyRoadmapPostMoved(trx, tenant, c.PostID, columnID, c.NewPosition)
 	})
 }
 
@@ -247,14 +262,15 @@ func CreateRoadmapColumn(ctx context.Context, c *cmd.CreateRoadmapColumn) error
 			Slug:              c.Slug,
 			Position:          c.Position,
 			IsVisibleToPublic: c.IsVisibleToPublic,
+			GroupID:           sql.NullInt64{Int64: int64(c.GroupID), Valid: c.GroupID > 0},
 			CreatedAt:         time.Now(),
 		}
 
 		err := trx.Get(column, `
-			INSERT INTO roadmap_columns (tenant_id, name, slug, position, is_visible_to_public, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO roadmap_columns (tenant_id, name, slug, position, is_visible_to_public, group_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING id
-		`, column.TenantID, column.Name, column.Slug, column.Position, column.IsVisibleToPublic, column.CreatedAt)
+		`, column.TenantID, column.Name, column.Slug, column.Position, column.IsVisibleToPublic, column.GroupID, column.CreatedAt)
 		if err != nil {
 			return err
 		}
@@ -270,10 +286,10 @@ func UpdateRoadmapColumn(ctx context.Context, c *cmd.UpdateRoadmapColumn) error
 		column := &dbRoadmapColumn{}
 		err := trx.Get(column, `
 			UPDATE roadmap_columns 
-			SET name = $1, is_visible_to_public = $2
-			WHERE id = $3 AND tenant_id = $4
-			RETURNING id, tenant_id, name, slug, position, is_visible_to_public, created_at
-		`, c.Name, c.IsVisibleToPublic, c.ColumnID, tenant.ID)
+			SET name = $1, is_visible_to_public = $2, group_id = $3
+			WHERE id = $4 AND tenant_id = $5
+			RETURNING id, tenant_id, name, slug, position, is_visible_to_public, group_id, created_at
+		`, c.Name, c.IsVisibleToPublic, sql.NullInt64{Int64: int64(c.GroupID), Valid: c.GroupID > 0}, c.ColumnID, tenant.ID)
 		if err != nil {
 			return err
 		}
@@ -361,6 +377,7 @@ func handleCreateRoadmapColumnAction(ctx context.Context, action *actions.Create
 			Slug:              slug,
 			Position:          *getMaxPos.Result + 1,
 			IsVisibleToPublic: action.IsVisibleToPublic,
+			GroupID:           action.GroupID,
 			CreatedByID:       user.ID,
 		}
 
@@ -374,6 +391,7 @@ func handleUpdateRoadmapColumnAction(ctx context.Context, action *actions.Update
 			ColumnID:          action.ColumnID,
 			Name:              action.Name,
 			IsVisibleToPublic: action.IsVisibleToPublic,
+			GroupID:           action.GroupID,
 			UpdatedByID:       user.ID,
 		}
 
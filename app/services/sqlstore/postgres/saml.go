@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbSAMLConfig struct {
+	ID                     int    `db:"id"`
+	Status                 int    `db:"status"`
+	DisplayName            string `db:"display_name"`
+	IdPMetadata            string `db:"idp_metadata"`
+	IdPEntityID            string `db:"idp_entity_id"`
+	IdPSSOURL              string `db:"idp_sso_url"`
+	AttributeName          string `db:"attribute_name"`
+	AttributeEmail         string `db:"attribute_email"`
+	AttributeRole          string `db:"attribute_role"`
+	AdministratorRoleValue string `db:"administrator_role_value"`
+	CollaboratorRoleValue  string `db:"collaborator_role_value"`
+	RequireSSO             bool   `db:"require_sso"`
+	AllowIdPInitiated      bool   `db:"allow_idp_initiated"`
+}
+
+func (m *dbSAMLConfig) toModel() *entity.SAMLConfig {
+	return &entity.SAMLConfig{
+		ID:                     m.ID,
+		Status:                 m.Status,
+		DisplayName:            m.DisplayName,
+		IdPMetadata:            m.IdPMetadata,
+		IdPEntityID:            m.IdPEntityID,
+		IdPSSOURL:              m.IdPSSOURL,
+		AttributeName:          m.AttributeName,
+		AttributeEmail:         m.AttributeEmail,
+		AttributeRole:          m.AttributeRole,
+		AdministratorRoleValue: m.AdministratorRoleValue,
+		CollaboratorRoleValue:  m.CollaboratorRoleValue,
+		RequireSSO:             m.RequireSSO,
+		AllowIdPInitiated:      m.AllowIdPInitiated,
+	}
+}
+
+// auditValues returns the settings of a SAML config that are recorded on the audit log
+func (m *dbSAMLConfig) auditValues() map[string]any {
+	return map[string]any{
+		"status":                 m.Status,
+		"displayName":            m.DisplayName,
+		"idpEntityId":            m.IdPEntityID,
+		"idpSsoUrl":              m.IdPSSOURL,
+		"attributeRole":          m.AttributeRole,
+		"administratorRoleValue": m.AdministratorRoleValue,
+		"collaboratorRoleValue":  m.CollaboratorRoleValue,
+		"requireSSO":             m.RequireSSO,
+		"allowIdPInitiated":      m.AllowIdPInitiated,
+	}
+}
+
+func getSAMLConfig(ctx context.Context, q *query.GetSAMLConfig) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if trx == nil || tenant == nil {
+			return app.ErrNotFound
+		}
+
+		config := &dbSAMLConfig{}
+		err := trx.Get(config, `
+			SELECT id, status, display_name, idp_metadata, idp_entity_id, idp_sso_url,
+						 attribute_name, attribute_email, attribute_role,
+						 administrator_role_value, collaborator_role_value, require_sso, allow_idp_initiated
+			FROM saml_configs
+			WHERE tenant_id = $1`, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get SAML config")
+		}
+
+		q.Result = config.toModel()
+		return nil
+	})
+}
+
+func saveSAMLConfig(ctx context.Context, c *cmd.SaveSAMLConfig) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var previous *dbSAMLConfig
+		config := &dbSAMLConfig{}
+		err := trx.Get(config, `
+			SELECT id, status, display_name, idp_metadata, idp_entity_id, idp_sso_url,
+						 attribute_name, attribute_email, attribute_role,
+						 administrator_role_value, collaborator_role_value, require_sso, allow_idp_initiated
+			FROM saml_configs
+			WHERE tenant_id = $1`, tenant.ID)
+		if err == nil {
+			previous = config
+		} else if errors.Cause(err) != app.ErrNotFound {
+			return errors.Wrap(err, "failed to get SAML config")
+		}
+
+		_, err = trx.Execute(`
+			INSERT INTO saml_configs (
+				tenant_id, status, display_name, idp_metadata, idp_entity_id, idp_sso_url,
+				attribute_name, attribute_email, attribute_role,
+				administrator_role_value, collaborator_role_value, require_sso, allow_idp_initiated
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			ON CONFLICT (tenant_id) DO UPDATE
+			SET status = $2, display_name = $3, idp_metadata = $4, idp_entity_id = $5, idp_sso_url = $6,
+					attribute_name = $7, attribute_email = $8, attribute_role = $9,
+					administrator_role_value = $10, collaborator_role_value = $11, require_sso = $12,
+					allow_idp_initiated = $13,
+					updated_at = NOW()`,
+			tenant.ID, c.Status, c.DisplayName, c.IdPMetadata, c.IdPEntityID, c.IdPSSOURL,
+			c.AttributeName, c.AttributeEmail, c.AttributeRole,
+			c.AdministratorRoleValue, c.CollaboratorRoleValue, c.RequireSSO, c.AllowIdPInitiated)
+		if err != nil {
+			return errors.Wrap(err, "failed to save SAML config")
+		}
+
+		if c.RequireSSO {
+			_, err = trx.Execute("UPDATE tenants SET is_email_auth_allowed = false WHERE id = $1", tenant.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to disable email auth")
+			}
+			tenant.IsEmailAuthAllowed = false
+		}
+
+		entry := auditEntry{
+			Action:     entity.AuditSAMLConfigSaved,
+			TargetType: "saml",
+			TargetName: c.DisplayName,
+			After: (&dbSAMLConfig{
+				Status:                 c.Status,
+				DisplayName:            c.DisplayName,
+				IdPEntityID:            c.IdPEntityID,
+				IdPSSOURL:              c.IdPSSOURL,
+				AttributeRole:          c.AttributeRole,
+				AdministratorRoleValue: c.AdministratorRoleValue,
+				CollaboratorRoleValue:  c.CollaboratorRoleValue,
+				RequireSSO:             c.RequireSSO,
+				AllowIdPInitiated:      c.AllowIdPInitiated,
+			}).auditValues(),
+		}
+		if previous != nil {
+			entry.Before = previous.auditValues()
+			entry.After["idpMetadataChanged"] = previous.IdPMetadata != c.IdPMetadata
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, entry)
+	})
+}
@@ -0,0 +1,64 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestSAMLConfigStorage_SaveAndGet(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getConfig := &query.GetSAMLConfig{}
+	err := bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SaveSAMLConfig{
+		Status:         enum.OAuthConfigDisabled,
+		DisplayName:    "Company SSO",
+		IdPMetadata:    "<EntityDescriptor/>",
+		IdPEntityID:    "https://idp.test/metadata",
+		IdPSSOURL:      "https://idp.test/sso",
+		AttributeEmail: "mail",
+	})
+	Expect(err).IsNil()
+
+	getConfig = &query.GetSAMLConfig{}
+	err = bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.IsEnabled()).IsFalse()
+	Expect(getConfig.Result.DisplayName).Equals("Company SSO")
+	Expect(getConfig.Result.IdPEntityID).Equals("https://idp.test/metadata")
+	Expect(getConfig.Result.AttributeEmail).Equals("mail")
+	Expect(getConfig.Result.RequireSSO).IsFalse()
+	Expect(getConfig.Result.AllowIdPInitiated).IsFalse()
+
+	err = bus.Dispatch(demoTenantCtx, &cmd.SaveSAMLConfig{
+		Status:            enum.OAuthConfigEnabled,
+		DisplayName:       "Okta",
+		IdPMetadata:       "<EntityDescriptor/>",
+		RequireSSO:        true,
+		AllowIdPInitiated: true,
+	})
+	Expect(err).IsNil()
+
+	getConfig = &query.GetSAMLConfig{}
+	err = bus.Dispatch(demoTenantCtx, getConfig)
+	Expect(err).IsNil()
+	Expect(getConfig.Result.IsEnabled()).IsTrue()
+	Expect(getConfig.Result.DisplayName).Equals("Okta")
+	Expect(getConfig.Result.RequireSSO).IsTrue()
+	Expect(getConfig.Result.AllowIdPInitiated).IsTrue()
+
+	getTenant := &query.GetTenantByDomain{Domain: "demo"}
+	err = bus.Dispatch(demoTenantCtx, getTenant)
+	Expect(err).IsNil()
+	Expect(getTenant.Result.IsEmailAuthAllowed).IsFalse()
+}
@@ -3,6 +3,7 @@ package postgres_test
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/enum"
@@ -352,3 +353,160 @@ func TestSubscription_EmailSupressed(t *testing.T) {
 	Expect(q.Result).HasLen(1)
 	Expect(q.Result[0].ID).Equals(jonSnow.ID)
 }
+
+func TestSubscription_MutedComments(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.MuteComments{Post: newPost.Result, User: jonSnow})
+	Expect(err).IsNil()
+	err = bus.Dispatch(aryaStarkCtx, &cmd.MuteComments{Post: newPost.Result, User: aryaStark})
+	Expect(err).IsNil()
+
+	newCommentSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewComment}
+	changeStatusSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventChangeStatus}
+	err = bus.Dispatch(aryaStarkCtx, newCommentSubscribers, changeStatusSubscribers)
+	Expect(err).IsNil()
+
+	Expect(newCommentSubscribers.Result).HasLen(0)
+	Expect(changeStatusSubscribers.Result).HasLen(2)
+
+	muted := &query.UserMutedCommentsOn{PostID: newPost.Result.ID}
+	err = bus.Dispatch(aryaStarkCtx, muted)
+	Expect(err).IsNil()
+	Expect(muted.Result).IsTrue()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UnmuteComments{Post: newPost.Result, User: jonSnow})
+	Expect(err).IsNil()
+
+	// Subscribing again restores the comment notifications
+	err = bus.Dispatch(aryaStarkCtx, &cmd.AddSubscriber{Post: newPost.Result, User: aryaStark})
+	Expect(err).IsNil()
+
+	newCommentSubscribers = &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewComment}
+	err = bus.Dispatch(aryaStarkCtx, newCommentSubscribers)
+	Expect(err).IsNil()
+
+	Expect(newCommentSubscribers.Result).HasLen(2)
+	Expect(newCommentSubscribers.Result[0].ID).Equals(jonSnow.ID)
+	Expect(newCommentSubscribers.Result[1].ID).Equals(aryaStark.ID)
+
+	err = bus.Dispatch(aryaStarkCtx, muted)
+	Expect(err).IsNil()
+	Expect(muted.Result).IsFalse()
+}
+
+func TestSubscription_Snoozed(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	newPost := &cmd.AddNewPost{Title: "My new post", Description: "with this description"}
+	err := bus.Dispatch(aryaStarkCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{
+			enum.NotificationsSnoozedUntilSettingsKey: time.Now().Add(time.Hour).Format(time.RFC3339),
+		},
+	})
+	Expect(err).IsNil()
+
+	// Arya's snooze is already over
+	err = bus.Dispatch(aryaStarkCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{
+			enum.NotificationsSnoozedUntilSettingsKey: time.Now().Add(-time.Hour).Format(time.RFC3339),
+		},
+	})
+	Expect(err).IsNil()
+
+	newPostSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	changeStatusSubscribers := &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelEmail, Event: enum.NotificationEventChangeStatus}
+	err = bus.Dispatch(aryaStarkCtx, newPostSubscribers, changeStatusSubscribers)
+	Expect(err).IsNil()
+
+	Expect(newPostSubscribers.Result).HasLen(0)
+	Expect(changeStatusSubscribers.Result).HasLen(1)
+	Expect(changeStatusSubscribers.Result[0].ID).Equals(aryaStark.ID)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{
+			enum.NotificationsSnoozedUntilSettingsKey: "",
+		},
+	})
+	Expect(err).IsNil()
+
+	newPostSubscribers = &query.GetActiveSubscribers{Number: newPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	err = bus.Dispatch(aryaStarkCtx, newPostSubscribers)
+	Expect(err).IsNil()
+
+	Expect(newPostSubscribers.Result).HasLen(1)
+	Expect(newPostSubscribers.Result[0].ID).Equals(jonSnow.ID)
+}
+
+func TestSubscription_FollowedTag(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	bug := &cmd.AddNewTag{Name: "Bug", Color: "FF0000", IsPublic: true}
+	secret := &cmd.AddNewTag{Name: "Secret", Color: "000000", IsPublic: false}
+	err := bus.Dispatch(jonSnowCtx, bug, secret)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.FollowTag{Tag: bug.Result}, &cmd.FollowTag{Tag: secret.Result})
+	Expect(err).IsNil()
+
+	// Sansa turned off new post notifications, but following a tag turns them on for its posts
+	err = bus.Dispatch(sansaStarkCtx, &cmd.UpdateCurrentUserSettings{
+		Settings: map[string]string{
+			enum.NotificationEventNewPost.UserSettingsKeyName: "0",
+		},
+	}, &cmd.FollowTag{Tag: bug.Result})
+	Expect(err).IsNil()
+
+	followed := &query.GetFollowedTags{}
+	err = bus.Dispatch(aryaStarkCtx, followed)
+	Expect(err).IsNil()
+	Expect(followed.Result).HasLen(2)
+	Expect(followed.Result[0].Name).Equals("Bug")
+
+	bugPost := &cmd.AddNewPost{Title: "Something is broken", Description: "here"}
+	secretPost := &cmd.AddNewPost{Title: "Something is hidden", Description: "there"}
+	err = bus.Dispatch(jonSnowCtx, bugPost, secretPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx,
+		&cmd.AssignTag{Tag: bug.Result, Post: bugPost.Result},
+		&cmd.AssignTag{Tag: secret.Result, Post: secretPost.Result},
+	)
+	Expect(err).IsNil()
+
+	webSubscribers := &query.GetActiveSubscribers{Number: bugPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	pushSubscribers := &query.GetActiveSubscribers{Number: bugPost.Result.Number, Channel: enum.NotificationChannelPush, Event: enum.NotificationEventNewPost}
+	secretSubscribers := &query.GetActiveSubscribers{Number: secretPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	err = bus.Dispatch(jonSnowCtx, webSubscribers, pushSubscribers, secretSubscribers)
+	Expect(err).IsNil()
+
+	Expect(webSubscribers.Result).HasLen(3)
+	Expect(webSubscribers.Result[0].ID).Equals(jonSnow.ID)
+	Expect(webSubscribers.Result[1].ID).Equals(aryaStark.ID)
+	Expect(webSubscribers.Result[2].ID).Equals(sansaStark.ID)
+
+	Expect(pushSubscribers.Result).HasLen(0)
+
+	// Visitors are not notified about tags they can't see
+	Expect(secretSubscribers.Result).HasLen(1)
+	Expect(secretSubscribers.Result[0].ID).Equals(jonSnow.ID)
+
+	err = bus.Dispatch(aryaStarkCtx, &cmd.UnfollowTag{Tag: bug.Result})
+	Expect(err).IsNil()
+
+	webSubscribers = &query.GetActiveSubscribers{Number: bugPost.Result.Number, Channel: enum.NotificationChannelWeb, Event: enum.NotificationEventNewPost}
+	err = bus.Dispatch(jonSnowCtx, webSubscribers)
+	Expect(err).IsNil()
+	Expect(webSubscribers.Result).HasLen(2)
+	Expect(webSubscribers.Result[1].ID).Equals(sansaStark.ID)
+}
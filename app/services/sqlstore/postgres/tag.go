@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -14,11 +15,12 @@ import (
 )
 
 type dbTag struct {
-	ID       int    `db:"id"`
-	Name     string `db:"name"`
-	Slug     string `db:"slug"`
-	Color    string `db:"color"`
-	IsPublic bool   `db:"is_public"`
+	ID       int           `db:"id"`
+	Name     string        `db:"name"`
+	Slug     string        `db:"slug"`
+	Color    string        `db:"color"`
+	IsPublic bool          `db:"is_public"`
+	GroupID  sql.NullInt64 `db:"group_id"`
 }
 
 func (t *dbTag) toModel() *entity.Tag {
@@ -28,6 +30,7 @@ func (t *dbTag) toModel() *entity.Tag {
 		Slug:     t.Slug,
 		Color:    t.Color,
 		IsPublic: t.IsPublic,
+		GroupID:  int(t.GroupID.Int64),
 	}
 }
 
@@ -44,7 +47,7 @@ func getAssignedTags(ctx context.Context, q *query.GetAssignedTags) error {
 		q.Result = make([]*entity.Tag, 0)
 
 		tags, err := queryTags(trx, `
-			SELECT t.id, t.name, t.slug, t.color, t.is_public 
+			SELECT t.id, t.name, t.slug, t.color, t.is_public, t.group_id
 			FROM tags t
 			INNER JOIN post_tags pt
 			ON pt.tag_id = t.id
@@ -66,13 +69,13 @@ func getAllTags(ctx context.Context, q *query.GetAllTags) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		q.Result = make([]*entity.Tag, 0)
 
-		condition := `AND t.is_public = true`
+		condition := `AND t.is_public = true AND ` + sqlGroupCondition("t.group_id", user)
 		if user != nil && user.IsCollaborator() {
 			condition = ``
 		}
 
 		query := fmt.Sprintf(`
-			SELECT t.id, t.name, t.slug, t.color, t.is_public 
+			SELECT t.id, t.name, t.slug, t.color, t.is_public, t.group_id
 			FROM tags t
 			WHERE t.tenant_id = $1 %s
 			ORDER BY t.name
@@ -92,17 +95,28 @@ func addNewTag(ctx context.Context, c *cmd.AddNewTag) error {
 		c.Result = nil
 		newSlug := slug.Make(c.Name)
 
+		groupID := sql.NullInt64{Int64: int64(c.GroupID), Valid: c.GroupID > 0}
 		_, err := trx.Execute(`
-			INSERT INTO tags (name, slug, color, is_public, created_at, tenant_id) 
-			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
-		`, c.Name, newSlug, c.Color, c.IsPublic, time.Now(), tenant.ID)
+			INSERT INTO tags (name, slug, color, is_public, created_at, tenant_id, group_id) 
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+		`, c.Name, newSlug, c.Color, c.IsPublic, time.Now(), tenant.ID, groupID)
 		if err != nil {
 			return errors.Wrap(err, "failed to add new tag")
 		}
 
 		tag, err := queryTagBySlug(trx, tenant, newSlug)
+		if err != nil {
+			return err
+		}
 		c.Result = tag
-		return err
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTagCreated,
+			TargetType: "tag",
+			TargetID:   tag.ID,
+			TargetName: tag.Name,
+			After:      tagAuditValues(tag),
+		})
 	})
 }
 
@@ -111,15 +125,33 @@ func updateTag(ctx context.Context, c *cmd.UpdateTag) error {
 		c.Result = nil
 		newSlug := slug.Make(c.Name)
 
-		_, err := trx.Execute(`UPDATE tags SET name = $1, slug = $2, color = $3, is_public = $4
-													 WHERE id = $5 AND tenant_id = $6`, c.Name, newSlug, c.Color, c.IsPublic, c.TagID, tenant.ID)
+		previous := dbTag{}
+		err := trx.Get(&previous, "SELECT id, name, slug, color, is_public, group_id FROM tags WHERE tenant_id = $1 AND id = $2", tenant.ID, c.TagID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get tag with id '%d'", c.TagID)
+		}
+
+		groupID := sql.NullInt64{Int64: int64(c.GroupID), Valid: c.GroupID > 0}
+		_, err = trx.Execute(`UPDATE tags SET name = $1, slug = $2, color = $3, is_public = $4, group_id = $5
+													 WHERE id = $6 AND tenant_id = $7`, c.Name, newSlug, c.Color, c.IsPublic, groupID, c.TagID, tenant.ID)
 		if err != nil {
 			return errors.Wrap(err, "failed to update tag")
 		}
 
 		tag, err := queryTagBySlug(trx, tenant, newSlug)
+		if err != nil {
+			return err
+		}
 		c.Result = tag
-		return err
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTagUpdated,
+			TargetType: "tag",
+			TargetID:   tag.ID,
+			TargetName: tag.Name,
+			Before:     tagAuditValues(previous.toModel()),
+			After:      tagAuditValues(tag),
+		})
 	})
 }
 
@@ -130,14 +162,35 @@ func deleteTag(ctx context.Context, c *cmd.DeleteTag) error {
 			return errors.Wrap(err, "failed to remove tag with id '%d' from all posts", c.Tag.ID)
 		}
 
+		_, err = trx.Execute(`DELETE FROM tag_subscribers WHERE tag_id = $1 AND tenant_id = $2`, c.Tag.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove followers of tag with id '%d'", c.Tag.ID)
+		}
+
 		_, err = trx.Execute(`DELETE FROM tags WHERE id = $1 AND tenant_id = $2`, c.Tag.ID, tenant.ID)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete tag with id '%d'", c.Tag.ID)
 		}
-		return nil
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTagDeleted,
+			TargetType: "tag",
+			TargetID:   c.Tag.ID,
+			TargetName: c.Tag.Name,
+			Before:     tagAuditValues(c.Tag),
+		})
 	})
 }
 
+func tagAuditValues(tag *entity.Tag) map[string]any {
+	return map[string]any{
+		"name":     tag.Name,
+		"color":    tag.Color,
+		"isPublic": tag.IsPublic,
+		"groupId":  tag.GroupID,
+	}
+}
+
 func assignTag(ctx context.Context, c *cmd.AssignTag) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		alreadyAssigned, err := trx.Exists("SELECT 1 FROM post_tags WHERE post_id = $1 AND tag_id = $2 AND tenant_id = $3", c.Post.ID, c.Tag.ID, tenant.ID)
@@ -175,10 +228,61 @@ func unassignTag(ctx context.Context, c *cmd.UnassignTag) error {
 	})
 }
 
+func followTag(ctx context.Context, c *cmd.FollowTag) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			INSERT INTO tag_subscribers (tenant_id, user_id, tag_id, created_at)
+			VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, tag_id) DO NOTHING
+		`, tenant.ID, user.ID, c.Tag.ID, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to follow tag")
+		}
+		return nil
+	})
+}
+
+func unfollowTag(ctx context.Context, c *cmd.UnfollowTag) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(
+			`DELETE FROM tag_subscribers WHERE tag_id = $1 AND user_id = $2 AND tenant_id = $3`,
+			c.Tag.ID, user.ID, tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to unfollow tag")
+		}
+		return nil
+	})
+}
+
+func getFollowedTags(ctx context.Context, q *query.GetFollowedTags) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = make([]*entity.Tag, 0)
+		if user == nil {
+			return nil
+		}
+
+		tags, err := queryTags(trx, `
+			SELECT t.id, t.name, t.slug, t.color, t.is_public, t.group_id
+			FROM tags t
+			INNER JOIN tag_subscribers ts
+			ON ts.tag_id = t.id
+			AND ts.tenant_id = t.tenant_id
+			WHERE ts.user_id = $1 AND t.tenant_id = $2
+			ORDER BY t.name
+		`, user.ID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed get followed tags")
+		}
+
+		q.Result = tags
+		return nil
+	})
+}
+
 func queryTagBySlug(trx *dbx.Trx, tenant *entity.Tenant, slug string) (*entity.Tag, error) {
 	tag := dbTag{}
 
-	err := trx.Get(&tag, "SELECT id, name, slug, color, is_public FROM tags WHERE tenant_id = $1 AND slug = $2", tenant.ID, slug)
+	err := trx.Get(&tag, "SELECT id, name, slug, color, is_public, group_id FROM tags WHERE tenant_id = $1 AND slug = $2", tenant.ID, slug)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get tag with slug '%s'", slug)
 	}
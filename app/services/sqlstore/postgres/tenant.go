@@ -17,21 +17,25 @@ import (
 )
 
 type dbTenant struct {
-	ID                 int    `db:"id"`
-	Name               string `db:"name"`
-	Subdomain          string `db:"subdomain"`
-	CNAME              string `db:"cname"`
-	Invitation         string `db:"invitation"`
-	WelcomeMessage     string `db:"welcome_message"`
-	Status             int    `db:"status"`
-	Locale             string `db:"locale"`
-	IsPrivate          bool   `db:"is_private"`
-	LogoBlobKey        string `db:"logo_bkey"`
-	CustomCSS          string `db:"custom_css"`
-	AllowedSchemes     string `db:"allowed_schemes"`
-	IsEmailAuthAllowed bool   `db:"is_email_auth_allowed"`
-	IsFeedEnabled      bool   `db:"is_feed_enabled"`
-	PreventIndexing    bool   `db:"prevent_indexing"`
+	ID                  int    `db:"id"`
+	Name                string `db:"name"`
+	Subdomain           string `db:"subdomain"`
+	CNAME               string `db:"cname"`
+	Invitation          string `db:"invitation"`
+	WelcomeMessage      string `db:"welcome_message"`
+	Status              int    `db:"status"`
+	Locale              string `db:"locale"`
+	IsPrivate           bool   `db:"is_private"`
+	LogoBlobKey         string `db:"logo_bkey"`
+	CustomCSS           string `db:"custom_css"`
+	AllowedSchemes      string `db:"allowed_schemes"`
+	IsEmailAuthAllowed  bool   `db:"is_email_auth_allowed"`
+	IsFeedEnabled       bool   `db:"is_feed_enabled"`
+	PreventIndexing     bool   `db:"prevent_indexing"`
+	VotingMode          int    `db:"voting_mode"`
+	VoteBudget          int    `db:"vote_budget"`
+	MaxVotesPerPost     int    `db:"max_votes_per_post"`
+	IsTwoFactorRequired bool   `db:"is_2fa_required"`
 }
 
 func (t *dbTenant) toModel() *entity.Tenant {
@@ -40,21 +44,25 @@ func (t *dbTenant) toModel() *entity.Tenant {
 	}
 
 	tenant := &entity.Tenant{
-		ID:                 t.ID,
-		Name:               t.Name,
-		Subdomain:          t.Subdomain,
-		CNAME:              t.CNAME,
-		Invitation:         t.Invitation,
-		WelcomeMessage:     t.WelcomeMessage,
-		Status:             enum.TenantStatus(t.Status),
-		Locale:             t.Locale,
-		IsPrivate:          t.IsPrivate,
-		LogoBlobKey:        t.LogoBlobKey,
-		CustomCSS:          t.CustomCSS,
-		AllowedSchemes:     t.AllowedSchemes,
-		IsEmailAuthAllowed: t.IsEmailAuthAllowed,
-		IsFeedEnabled:      t.IsFeedEnabled,
-		PreventIndexing:    t.PreventIndexing,
+		ID:                  t.ID,
+		Name:                t.Name,
+		Subdomain:           t.Subdomain,
+		CNAME:               t.CNAME,
+		Invitation:          t.Invitation,
+		WelcomeMessage:      t.WelcomeMessage,
+		Status:              enum.TenantStatus(t.Status),
+		Locale:              t.Locale,
+		IsPrivate:           t.IsPrivate,
+		LogoBlobKey:         t.LogoBlobKey,
+		CustomCSS:           t.CustomCSS,
+		AllowedSchemes:      t.AllowedSchemes,
+		IsEmailAuthAllowed:  t.IsEmailAuthAllowed,
+		IsFeedEnabled:       t.IsFeedEnabled,
+		PreventIndexing:     t.PreventIndexing,
+		VotingMode:          enum.VotingMode(t.VotingMode),
+		VoteBudget:          t.VoteBudget,
+		MaxVotesPerPost:     t.MaxVotesPerPost,
+		IsTwoFactorRequired: t.IsTwoFactorRequired,
 	}
 
 	return tenant
@@ -133,7 +141,14 @@ func updateTenantPrivacySettings(ctx context.Context, c *cmd.UpdateTenantPrivacy
 		if err != nil {
 			return errors.Wrap(err, "failed update tenant feed setting")
 		}
-		return nil
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantPrivacyUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before:     map[string]any{"isPrivate": tenant.IsPrivate, "isFeedEnabled": tenant.IsFeedEnabled},
+			After:      map[string]any{"isPrivate": c.IsPrivate, "isFeedEnabled": c.IsFeedEnabled},
+		})
 	})
 }
 
@@ -143,6 +158,57 @@ func updateTenantEmailAuthAllowedSettings(ctx context.Context, c *cmd.UpdateTena
 		if err != nil {
 			return errors.Wrap(err, "failed update tenant allowing email auth settings")
 		}
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantEmailAuthUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before:     map[string]any{"isEmailAuthAllowed": tenant.IsEmailAuthAllowed},
+			After:      map[string]any{"isEmailAuthAllowed": c.IsEmailAuthAllowed},
+		})
+	})
+}
+
+func updateTenantTwoFactorSettings(ctx context.Context, c *cmd.UpdateTenantTwoFactorSettings) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute("UPDATE tenants SET is_2fa_required = $1 WHERE id = $2", c.IsTwoFactorRequired, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed update tenant two-factor settings")
+		}
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantTwoFactorUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before:     map[string]any{"isTwoFactorRequired": tenant.IsTwoFactorRequired},
+			After:      map[string]any{"isTwoFactorRequired": c.IsTwoFactorRequired},
+		})
+	})
+}
+
+func updateTenantVotingSettings(ctx context.Context, c *cmd.UpdateTenantVotingSettings) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		query := "UPDATE tenants SET voting_mode = $1, vote_budget = $2, max_votes_per_post = $3 WHERE id = $4"
+		_, err := trx.Execute(query, c.VotingMode, c.VoteBudget, c.MaxVotesPerPost, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed update tenant voting settings")
+		}
+
+		err = insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantVotingUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before:     map[string]any{"votingMode": tenant.VotingMode, "voteBudget": tenant.VoteBudget, "maxVotesPerPost": tenant.MaxVotesPerPost},
+			After:      map[string]any{"votingMode": c.VotingMode, "voteBudget": c.VoteBudget, "maxVotesPerPost": c.MaxVotesPerPost},
+		})
+		if err != nil {
+			return err
+		}
+
+		tenant.VotingMode = c.VotingMode
+		tenant.VoteBudget = c.VoteBudget
+		tenant.MaxVotesPerPost = c.MaxVotesPerPost
 		return nil
 	})
 }
@@ -159,6 +225,24 @@ func updateTenantSettings(ctx context.Context, c *cmd.UpdateTenantSettings) erro
 			return errors.Wrap(err, "failed update tenant settings")
 		}
 
+		err = insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantSettingsUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before: map[string]any{
+				"title": tenant.Name, "invitation": tenant.Invitation, "welcomeMessage": tenant.WelcomeMessage,
+				"cname": tenant.CNAME, "logoBlobKey": tenant.LogoBlobKey, "locale": tenant.Locale,
+			},
+			After: map[string]any{
+				"title": c.Title, "invitation": c.Invitation, "welcomeMessage": c.WelcomeMessage,
+				"cname": c.CNAME, "logoBlobKey": c.Logo.BlobKey, "locale": c.Locale,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
 		tenant.Name = c.Title
 		tenant.Invitation = c.Invitation
 		tenant.CNAME = c.CNAME
@@ -181,6 +265,18 @@ func updateTenantAdvancedSettings(ctx context.Context, c *cmd.UpdateTenantAdvanc
 			return errors.Wrap(err, "failed update tenant advanced settings")
 		}
 
+		err = insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditTenantAdvancedUpdated,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+			Before:     map[string]any{"customCSS": tenant.CustomCSS, "allowedSchemes": tenant.AllowedSchemes},
+			After:      map[string]any{"customCSS": c.CustomCSS, "allowedSchemes": AllowedSchemes},
+		})
+		if err != nil {
+			return err
+		}
+
 		tenant.CustomCSS = c.CustomCSS
 		tenant.AllowedSchemes = AllowedSchemes
 		return nil
@@ -275,7 +371,7 @@ func getFirstTenant(ctx context.Context, q *query.GetFirstTenant) error {
 		tenant := dbTenant{}
 
 		err := trx.Get(&tenant, `
-			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing
+			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing, voting_mode, vote_budget, max_votes_per_post, is_2fa_required
 			FROM tenants
 			ORDER BY id LIMIT 1
 		`)
@@ -294,7 +390,7 @@ func getTenantByDomain(ctx context.Context, q *query.GetTenantByDomain) error {
 		tenant := dbTenant{}
 
 		err := trx.Get(&tenant, `
-			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing
+			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing, voting_mode, vote_budget, max_votes_per_post, is_2fa_required
 			FROM tenants t
 			WHERE subdomain = $1 OR subdomain = $2 OR cname = $3
 			ORDER BY cname DESC
@@ -307,3 +403,21 @@ func getTenantByDomain(ctx context.Context, q *query.GetTenantByDomain) error {
 		return nil
 	})
 }
+
+func getTenantByID(ctx context.Context, q *query.GetTenantByID) error {
+	return using(ctx, func(trx *dbx.Trx, _ *entity.Tenant, _ *entity.User) error {
+		tenant := dbTenant{}
+
+		err := trx.Get(&tenant, `
+			SELECT id, name, subdomain, cname, invitation, locale, welcome_message, status, is_private, logo_bkey, custom_css, allowed_schemes, is_email_auth_allowed, is_feed_enabled, prevent_indexing, voting_mode, vote_budget, max_votes_per_post, is_2fa_required
+			FROM tenants
+			WHERE id = $1
+		`, q.TenantID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get tenant with id '%d'", q.TenantID)
+		}
+
+		q.Result = tenant.toModel()
+		return nil
+	})
+}
@@ -118,6 +118,21 @@ func TestTenantStorage_GetByDomain_NotFound(t *testing.T) {
 	Expect(getByDomain.Result).IsNil()
 }
 
+func TestTenantStorage_GetByID(t *testing.T) {
+	ctx := SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	getByID := &query.GetTenantByID{TenantID: demoTenant.ID}
+	err := bus.Dispatch(ctx, getByID)
+	Expect(err).IsNil()
+	Expect(getByID.Result.Subdomain).Equals("demo")
+
+	getByID = &query.GetTenantByID{TenantID: 9999}
+	err = bus.Dispatch(ctx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+	Expect(getByID.Result).IsNil()
+}
+
 func TestTenantStorage_GetByDomain_CNAME(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/rand"
+)
+
+type dbUserTwoFactor struct {
+	UserID       int          `db:"user_id"`
+	Secret       string       `db:"secret"`
+	LastUsedStep int64        `db:"last_used_step"`
+	EnabledAt    dbx.NullTime `db:"enabled_at"`
+
+	FailedAttempts int          `db:"failed_attempts"`
+	LockedUntil    dbx.NullTime `db:"locked_until"`
+}
+
+func (t *dbUserTwoFactor) toModel() *entity.UserTwoFactor {
+	model := &entity.UserTwoFactor{
+		UserID:       t.UserID,
+		Secret:       t.Secret,
+		LastUsedStep: t.LastUsedStep,
+
+		FailedAttempts: t.FailedAttempts,
+	}
+	if t.EnabledAt.Valid {
+		model.EnabledAt = &t.EnabledAt.Time
+	}
+	if t.LockedUntil.Valid {
+		model.LockedUntil = &t.LockedUntil.Time
+	}
+	return model
+}
+
+// normalizeRecoveryCode makes recovery codes case insensitive and ignores the separator
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func getUserTwoFactor(ctx context.Context, q *query.GetUserTwoFactor) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		twoFactor := dbUserTwoFactor{}
+		err := trx.Get(&twoFactor, `
+			SELECT user_id, secret, last_used_step, enabled_at, failed_attempts, locked_until
+			FROM user_totp
+			WHERE user_id = $1 AND tenant_id = $2
+		`, q.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get two-factor settings of user '%d'", q.UserID)
+		}
+
+		q.Result = twoFactor.toModel()
+		return nil
+	})
+}
+
+func setupUserTwoFactor(ctx context.Context, c *cmd.SetupUserTwoFactor) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// A new secret is only enabled once confirmed, so an enabled secret is never replaced
+		_, err := trx.Execute(`
+			INSERT INTO user_totp (user_id, tenant_id, secret, last_used_step, enabled_at, created_at)
+			VALUES ($1, $2, $3, 0, NULL, $4)
+			ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+			WHERE user_totp.enabled_at IS NULL
+		`, c.UserID, tenant.ID, c.Secret, time.Now())
+		if err != nil {
+			return errors.Wrap(err, "failed to setup two-factor for user '%d'", c.UserID)
+		}
+		return nil
+	})
+}
+
+func enableUserTwoFactor(ctx context.Context, c *cmd.EnableUserTwoFactor) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE user_totp SET enabled_at = $1, last_used_step = $2
+			WHERE user_id = $3 AND tenant_id = $4 AND enabled_at IS NULL
+		`, time.Now(), c.Step, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to enable two-factor for user '%d'", c.UserID)
+		}
+
+		codes, err := generateRecoveryCodes(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		c.Result = codes
+		return nil
+	})
+}
+
+func disableUserTwoFactor(ctx context.Context, c *cmd.DisableUserTwoFactor) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		if _, err := trx.Execute("DELETE FROM user_recovery_codes WHERE user_id = $1 AND tenant_id = $2", c.UserID, tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to delete recovery codes of user '%d'", c.UserID)
+		}
+		if _, err := trx.Execute("DELETE FROM user_totp WHERE user_id = $1 AND tenant_id = $2", c.UserID, tenant.ID); err != nil {
+			return errors.Wrap(err, "failed to disable two-factor for user '%d'", c.UserID)
+		}
+		return nil
+	})
+}
+
+func regenerateRecoveryCodes(ctx context.Context, c *cmd.RegenerateRecoveryCodes) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		codes, err := generateRecoveryCodes(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		c.Result = codes
+		return nil
+	})
+}
+
+func markTwoFactorStepUsed(ctx context.Context, c *cmd.MarkTwoFactorStepUsed) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// A code can only be used once, so steps that are not newer than the last used one are rejected
+		affected, err := trx.Execute(`
+			UPDATE user_totp SET last_used_step = $1, failed_attempts = 0
+			WHERE user_id = $2 AND tenant_id = $3 AND last_used_step < $1
+		`, c.Step, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to mark two-factor step as used for user '%d'", c.UserID)
+		}
+
+		c.Result = affected > 0
+		return nil
+	})
+}
+
+func useRecoveryCode(ctx context.Context, c *cmd.UseRecoveryCode) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		affected, err := trx.Execute(`
+			UPDATE user_recovery_codes SET used_at = $1
+			WHERE user_id = $2 AND tenant_id = $3 AND code_hash = $4 AND used_at IS NULL
+		`, time.Now(), c.UserID, tenant.ID, crypto.SHA512(normalizeRecoveryCode(c.Code)))
+		if err != nil {
+			return errors.Wrap(err, "failed to use recovery code for user '%d'", c.UserID)
+		}
+
+		if affected > 0 {
+			_, err = trx.Execute("UPDATE user_totp SET failed_attempts = 0 WHERE user_id = $1 AND tenant_id = $2", c.UserID, tenant.ID)
+			if err != nil {
+				return errors.Wrap(err, "failed to reset failed two-factor attempts of user '%d'", c.UserID)
+			}
+		}
+
+		c.Result = affected > 0
+		return nil
+	})
+}
+
+func recordTwoFactorFailure(ctx context.Context, c *cmd.RecordTwoFactorFailure) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var attempts int
+		err := trx.Scalar(&attempts, `
+			UPDATE user_totp SET failed_attempts = failed_attempts + 1
+			WHERE user_id = $1 AND tenant_id = $2
+			RETURNING failed_attempts
+		`, c.UserID, tenant.ID)
+		if err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return nil
+			}
+			return errors.Wrap(err, "failed to record failed two-factor attempt of user '%d'", c.UserID)
+		}
+
+		if attempts < entity.TwoFactorMaxFailedAttempts {
+			return nil
+		}
+
+		// The counter starts over once the lockout is over
+		_, err = trx.Execute(`
+			UPDATE user_totp SET failed_attempts = 0, locked_until = $1
+			WHERE user_id = $2 AND tenant_id = $3
+		`, time.Now().Add(entity.TwoFactorLockoutDuration), c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to lock out user '%d'", c.UserID)
+		}
+
+		c.Result = true
+		return nil
+	})
+}
+
+// generateRecoveryCodes replaces all recovery codes of given user, only their hashes are stored
+func generateRecoveryCodes(trx *dbx.Trx, tenant *entity.Tenant, userID int) ([]string, error) {
+	if _, err := trx.Execute("DELETE FROM user_recovery_codes WHERE user_id = $1 AND tenant_id = $2", userID, tenant.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to delete recovery codes of user '%d'", userID)
+	}
+
+	now := time.Now()
+	codes := make([]string, entity.RecoveryCodesCount)
+	for i := range codes {
+		code := strings.ToLower(rand.String(10))
+		codes[i] = code[:5] + "-" + code[5:]
+
+		_, err := trx.Execute(`
+			INSERT INTO user_recovery_codes (tenant_id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, tenant.ID, userID, crypto.SHA512(normalizeRecoveryCode(code)), now)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to add recovery code for user '%d'", userID)
+		}
+	}
+	return codes, nil
+}
@@ -0,0 +1,126 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestTwoFactorStorage_SetupEnableAndDisable(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.SetupUserTwoFactor{UserID: jonSnow.ID, Secret: "SECRET1"})
+	Expect(err).IsNil()
+
+	getTwoFactor := &query.GetUserTwoFactor{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, getTwoFactor)
+	Expect(err).IsNil()
+	Expect(getTwoFactor.Result.Secret).Equals("SECRET1")
+	Expect(getTwoFactor.Result.IsEnabled()).IsFalse()
+
+	enable := &cmd.EnableUserTwoFactor{UserID: jonSnow.ID, Step: 100}
+	err = bus.Dispatch(jonSnowCtx, enable)
+	Expect(err).IsNil()
+	Expect(enable.Result).HasLen(entity.RecoveryCodesCount)
+
+	// an enabled secret is never replaced
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetupUserTwoFactor{UserID: jonSnow.ID, Secret: "SECRET2"})
+	Expect(err).IsNil()
+
+	getUser := &query.GetUserByID{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, getTwoFactor, getUser)
+	Expect(err).IsNil()
+	Expect(getTwoFactor.Result.Secret).Equals("SECRET1")
+	Expect(getTwoFactor.Result.IsEnabled()).IsTrue()
+	Expect(getUser.Result.HasTwoFactor).IsTrue()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DisableUserTwoFactor{UserID: jonSnow.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserTwoFactor{UserID: jonSnow.ID})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestTwoFactorStorage_StepsAndRecoveryCodesAreSingleUse(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.SetupUserTwoFactor{UserID: jonSnow.ID, Secret: "SECRET1"})
+	Expect(err).IsNil()
+
+	enable := &cmd.EnableUserTwoFactor{UserID: jonSnow.ID, Step: 100}
+	err = bus.Dispatch(jonSnowCtx, enable)
+	Expect(err).IsNil()
+
+	markUsed := &cmd.MarkTwoFactorStepUsed{UserID: jonSnow.ID, Step: 100}
+	err = bus.Dispatch(jonSnowCtx, markUsed)
+	Expect(err).IsNil()
+	Expect(markUsed.Result).IsFalse()
+
+	markUsed = &cmd.MarkTwoFactorStepUsed{UserID: jonSnow.ID, Step: 101}
+	err = bus.Dispatch(jonSnowCtx, markUsed)
+	Expect(err).IsNil()
+	Expect(markUsed.Result).IsTrue()
+
+	useCode := &cmd.UseRecoveryCode{UserID: jonSnow.ID, Code: strings.ToUpper(enable.Result[0])}
+	err = bus.Dispatch(jonSnowCtx, useCode)
+	Expect(err).IsNil()
+	Expect(useCode.Result).IsTrue()
+
+	useCode = &cmd.UseRecoveryCode{UserID: jonSnow.ID, Code: enable.Result[0]}
+	err = bus.Dispatch(jonSnowCtx, useCode)
+	Expect(err).IsNil()
+	Expect(useCode.Result).IsFalse()
+
+	regenerate := &cmd.RegenerateRecoveryCodes{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, regenerate)
+	Expect(err).IsNil()
+
+	useCode = &cmd.UseRecoveryCode{UserID: jonSnow.ID, Code: enable.Result[1]}
+	err = bus.Dispatch(jonSnowCtx, useCode)
+	Expect(err).IsNil()
+	Expect(useCode.Result).IsFalse()
+}
+
+func TestTwoFactorStorage_LockoutAfterFailedAttempts(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.SetupUserTwoFactor{UserID: jonSnow.ID, Secret: "SECRET1"})
+	Expect(err).IsNil()
+	err = bus.Dispatch(jonSnowCtx, &cmd.EnableUserTwoFactor{UserID: jonSnow.ID, Step: 100})
+	Expect(err).IsNil()
+
+	for i := 1; i < entity.TwoFactorMaxFailedAttempts; i++ {
+		recordFailure := &cmd.RecordTwoFactorFailure{UserID: jonSnow.ID}
+		err = bus.Dispatch(jonSnowCtx, recordFailure)
+		Expect(err).IsNil()
+		Expect(recordFailure.Result).IsFalse()
+	}
+
+	getTwoFactor := &query.GetUserTwoFactor{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, getTwoFactor)
+	Expect(err).IsNil()
+	Expect(getTwoFactor.Result.FailedAttempts).Equals(entity.TwoFactorMaxFailedAttempts - 1)
+	Expect(getTwoFactor.Result.IsLockedOut(time.Now())).IsFalse()
+
+	recordFailure := &cmd.RecordTwoFactorFailure{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, recordFailure)
+	Expect(err).IsNil()
+	Expect(recordFailure.Result).IsTrue()
+
+	err = bus.Dispatch(jonSnowCtx, getTwoFactor)
+	Expect(err).IsNil()
+	Expect(getTwoFactor.Result.FailedAttempts).Equals(0)
+	Expect(getTwoFactor.Result.IsLockedOut(time.Now())).IsTrue()
+	Expect(getTwoFactor.Result.IsLockedOut(time.Now().Add(entity.TwoFactorLockoutDuration + time.Minute))).IsFalse()
+}
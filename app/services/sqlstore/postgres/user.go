@@ -26,6 +26,10 @@ type dbUser struct {
 	Status        sql.NullInt64  `db:"status"`
 	AvatarType    sql.NullInt64  `db:"avatar_type"`
 	AvatarBlobKey sql.NullString `db:"avatar_bkey"`
+	CustomRoleID  sql.NullInt64  `db:"custom_role_id"`
+	HasTwoFactor  bool           `db:"has_two_factor"`
+	CustomRole    *dbCustomRole
+	GroupIDs      []int
 	Providers     []*dbUserProvider
 }
 
@@ -56,6 +60,16 @@ func (u *dbUser) toModel(ctx context.Context) *entity.User {
 		AvatarType:    avatarType,
 		AvatarBlobKey: u.AvatarBlobKey.String,
 		AvatarURL:     avatarURL,
+		GroupIDs:      u.GroupIDs,
+		HasTwoFactor:  u.HasTwoFactor,
+	}
+
+	// a custom role that couldn't be loaded grants no permissions
+	if u.CustomRoleID.Valid {
+		user.CustomRole = &entity.CustomRole{ID: int(u.CustomRoleID.Int64), Permissions: []string{}}
+		if u.CustomRole != nil {
+			user.CustomRole = u.CustomRole.toModel()
+		}
 	}
 
 	for i, p := range u.Providers {
@@ -87,61 +101,135 @@ func countUsers(ctx context.Context, q *query.CountUsers) error {
 
 func blockUser(ctx context.Context, c *cmd.BlockUser) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
 		if _, err := trx.Execute(
 			"UPDATE users SET status = $3 WHERE id = $1 AND tenant_id = $2",
 			c.UserID, tenant.ID, enum.UserBlocked,
 		); err != nil {
 			return errors.Wrap(err, "failed to block user")
 		}
-		return nil
+
+		// blocked users are signed out of all their browsers
+		if _, err := trx.Execute(
+			"DELETE FROM user_sessions WHERE user_id = $1 AND tenant_id = $2",
+			c.UserID, tenant.ID,
+		); err != nil {
+			return errors.Wrap(err, "failed to revoke sessions of blocked user")
+		}
+
+		if _, err := trx.Execute(
+			"DELETE FROM push_subscriptions WHERE user_id = $1 AND tenant_id = $2",
+			c.UserID, tenant.ID,
+		); err != nil {
+			return errors.Wrap(err, "failed to delete push subscriptions of blocked user")
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserBlocked,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+			Before:     map[string]any{"status": target.Status.String()},
+			After:      map[string]any{"status": enum.UserBlocked.String()},
+		})
 	})
 }
 
 func unblockUser(ctx context.Context, c *cmd.UnblockUser) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
 		if _, err := trx.Execute(
 			"UPDATE users SET status = $3 WHERE id = $1 AND tenant_id = $2",
 			c.UserID, tenant.ID, enum.UserActive,
 		); err != nil {
 			return errors.Wrap(err, "failed to unblock user")
 		}
-		return nil
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserUnblocked,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+			Before:     map[string]any{"status": target.Status.String()},
+			After:      map[string]any{"status": enum.UserActive.String()},
+		})
 	})
 }
 
 func deleteCurrentUser(ctx context.Context, c *cmd.DeleteCurrentUser) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		return deleteUserRecords(trx, tenant, user.ID)
+	})
+}
+
+func deleteUser(ctx context.Context, c *cmd.DeleteUser) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := deleteUserRecords(trx, tenant, c.UserID); err != nil {
+			return err
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserDeleted,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+		})
+	})
+}
+
+func deleteUserRecords(trx *dbx.Trx, tenant *entity.Tenant, userID int) error {
+	if _, err := trx.Execute(
+		"UPDATE users SET role = $3, status = $4, name = '', email = '', api_key = null, api_key_date = null, custom_role_id = null WHERE id = $1 AND tenant_id = $2",
+		userID, tenant.ID, enum.RoleVisitor, enum.UserDeleted,
+	); err != nil {
+		return errors.Wrap(err, "failed to delete user")
+	}
+
+	var tables = []struct {
+		name       string
+		userColumn string
+	}{
+		{"user_providers", "user_id"},
+		{"user_settings", "user_id"},
+		{"notifications", "user_id"},
+		{"notifications", "author_id"},
+		{"post_votes", "user_id"},
+		{"post_subscribers", "user_id"},
+		{"email_verifications", "user_id"},
+		{"api_tokens", "user_id"},
+		{"oauth_authorization_codes", "user_id"},
+		{"oauth_refresh_tokens", "user_id"},
+		{"user_group_members", "user_id"},
+		{"user_recovery_codes", "user_id"},
+		{"user_totp", "user_id"},
+		{"user_sessions", "user_id"},
+		{"push_subscriptions", "user_id"},
+		{"tag_subscribers", "user_id"},
+	}
+
+	for _, table := range tables {
 		if _, err := trx.Execute(
-			"UPDATE users SET role = $3, status = $4, name = '', email = '', api_key = null, api_key_date = null WHERE id = $1 AND tenant_id = $2",
-			user.ID, tenant.ID, enum.RoleVisitor, enum.UserDeleted,
+			fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND tenant_id = $2", table.name, table.userColumn),
+			userID, tenant.ID,
 		); err != nil {
-			return errors.Wrap(err, "failed to delete current user")
-		}
-
-		var tables = []struct {
-			name       string
-			userColumn string
-		}{
-			{"user_providers", "user_id"},
-			{"user_settings", "user_id"},
-			{"notifications", "user_id"},
-			{"notifications", "author_id"},
-			{"post_votes", "user_id"},
-			{"post_subscribers", "user_id"},
-			{"email_verifications", "user_id"},
-		}
-
-		for _, table := range tables {
-			if _, err := trx.Execute(
-				fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND tenant_id = $2", table.name, table.userColumn),
-				user.ID, tenant.ID,
-			); err != nil {
-				return errors.Wrap(err, "failed to delete current user's %s records", table)
-			}
+			return errors.Wrap(err, "failed to delete user's %s records", table)
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
 func regenerateAPIKey(ctx context.Context, c *cmd.RegenerateAPIKey) error {
@@ -207,20 +295,63 @@ func userSubscribedTo(ctx context.Context, q *query.UserSubscribedTo) error {
 	})
 }
 
+func userMutedCommentsOn(ctx context.Context, q *query.UserMutedCommentsOn) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		q.Result = false
+		if user == nil {
+			return nil
+		}
+
+		err := trx.Scalar(&q.Result, `
+			SELECT EXISTS(
+				SELECT 1 FROM post_subscribers
+				WHERE user_id = $1 AND post_id = $2 AND tenant_id = $3 AND comments_muted = true
+			)`, user.ID, q.PostID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get comments mute status")
+		}
+		return nil
+	})
+}
+
 func changeUserRole(ctx context.Context, c *cmd.ChangeUserRole) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		cmd := "UPDATE users SET role = $3 WHERE id = $1 AND tenant_id = $2"
-		_, err := trx.Execute(cmd, c.UserID, tenant.ID, c.Role)
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		cmd := "UPDATE users SET role = $3, custom_role_id = NULL WHERE id = $1 AND tenant_id = $2"
+		_, err = trx.Execute(cmd, c.UserID, tenant.ID, c.Role)
 		if err != nil {
 			return errors.Wrap(err, "failed to change user's role")
 		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserRoleChanged,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+			Before:     map[string]any{"role": target.Role},
+			After:      map[string]any{"role": c.Role},
+		})
+	})
+}
+
+func changeUserName(ctx context.Context, c *cmd.ChangeUserName) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		cmd := "UPDATE users SET name = $3 WHERE id = $1 AND tenant_id = $2"
+		_, err := trx.Execute(cmd, c.UserID, tenant.ID, c.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to update user's name")
+		}
 		return nil
 	})
 }
 
 func changeUserEmail(ctx context.Context, c *cmd.ChangeUserEmail) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		cmd := "UPDATE users SET email = $3, email_supressed_at = NULL WHERE id = $1 AND tenant_id = $2"
+		cmd := "UPDATE users SET email = $3, email_supressed_at = NULL, email_supression_reason = NULL WHERE id = $1 AND tenant_id = $2"
 		_, err := trx.Execute(cmd, c.UserID, tenant.ID, strings.ToLower(c.Email))
 		if err != nil {
 			return errors.Wrap(err, "failed to update user's email")
@@ -266,6 +397,7 @@ func getCurrentUserSettings(ctx context.Context, q *query.GetCurrentUserSettings
 				}
 			}
 		}
+		q.Result[enum.EmailDigestFrequencySettingsKey] = enum.EmailDigestFrequencyInstant
 
 		for _, s := range settings {
 			q.Result[s.Key] = s.Value
@@ -371,7 +503,8 @@ func getAllUsers(ctx context.Context, q *query.GetAllUsers) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		var users []*dbUser
 		err := trx.Select(&users, `
-			SELECT id, name, email, tenant_id, role, status, avatar_type, avatar_bkey
+			SELECT id, name, email, tenant_id, role, status, avatar_type, avatar_bkey, custom_role_id,
+			EXISTS(SELECT 1 FROM user_totp WHERE user_totp.user_id = users.id AND user_totp.enabled_at IS NOT NULL) AS has_two_factor
 			FROM users 
 			WHERE tenant_id = $1 
 			AND status != $2
@@ -380,6 +513,14 @@ func getAllUsers(ctx context.Context, q *query.GetAllUsers) error {
 			return errors.Wrap(err, "failed to get all users")
 		}
 
+		if err := loadCustomRoles(trx, tenant.ID, users...); err != nil {
+			return err
+		}
+
+		if err := loadUserGroups(trx, tenant.ID, users...); err != nil {
+			return err
+		}
+
 		q.Result = make([]*entity.User, len(users))
 		for i, user := range users {
 			q.Result[i] = user.toModel(ctx)
@@ -413,7 +554,7 @@ func getAllUsersNames(ctx context.Context, q *query.GetAllUsersNames) error {
 
 func queryUser(ctx context.Context, trx *dbx.Trx, filter string, args ...any) (*entity.User, error) {
 	user := dbUser{}
-	sql := fmt.Sprintf("SELECT id, name, email, tenant_id, role, status, avatar_type, avatar_bkey FROM users WHERE status != %d AND ", enum.UserDeleted)
+	sql := fmt.Sprintf("SELECT id, name, email, tenant_id, role, status, avatar_type, avatar_bkey, custom_role_id, EXISTS(SELECT 1 FROM user_totp WHERE user_totp.user_id = users.id AND user_totp.enabled_at IS NOT NULL) AS has_two_factor FROM users WHERE status != %d AND ", enum.UserDeleted)
 	err := trx.Get(&user, sql+filter, args...)
 	if err != nil {
 		return nil, err
@@ -424,5 +565,15 @@ func queryUser(ctx context.Context, trx *dbx.Trx, filter string, args ...any) (*
 		return nil, err
 	}
 
+	if user.Tenant != nil {
+		if err := loadCustomRoles(trx, user.Tenant.ID, &user); err != nil {
+			return nil, err
+		}
+
+		if err := loadUserGroups(trx, user.Tenant.ID, &user); err != nil {
+			return nil, err
+		}
+	}
+
 	return user.toModel(ctx), nil
 }
@@ -0,0 +1,353 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
+)
+
+type dbUserGroup struct {
+	ID           int      `db:"id"`
+	Name         string   `db:"name"`
+	EmailDomains []string `db:"email_domains"`
+	OAuthClaim   string   `db:"oauth_claim"`
+	MembersCount int      `db:"members_count"`
+}
+
+func (g *dbUserGroup) toModel() *entity.UserGroup {
+	domains := g.EmailDomains
+	if domains == nil {
+		domains = []string{}
+	}
+	return &entity.UserGroup{
+		ID:           g.ID,
+		Name:         g.Name,
+		EmailDomains: domains,
+		OAuthClaim:   g.OAuthClaim,
+		MembersCount: g.MembersCount,
+	}
+}
+
+// sqlIsGroupMember matches the users u that belong to the group g, either by membership or by their email domain
+const sqlIsGroupMember = `(
+	EXISTS (SELECT 1 FROM user_group_members m WHERE m.group_id = g.id AND m.user_id = u.id)
+	OR (u.email != '' AND LOWER(SPLIT_PART(u.email, '@', 2)) = ANY(g.email_domains))
+)`
+
+var sqlSelectUserGroups = `
+	SELECT g.id, g.name, g.email_domains, g.oauth_claim,
+	(SELECT COUNT(*) FROM users u WHERE u.tenant_id = g.tenant_id AND u.status = ` + strconv.Itoa(int(enum.UserActive)) + ` AND ` + sqlIsGroupMember + `) AS members_count
+	FROM user_groups g`
+
+func getUserGroupByID(ctx context.Context, q *query.GetUserGroupByID) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		group := dbUserGroup{}
+		err := trx.Get(&group, sqlSelectUserGroups+" WHERE g.tenant_id = $1 AND g.id = $2", tenant.ID, q.GroupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get user group with id '%d'", q.GroupID)
+		}
+
+		q.Result = group.toModel()
+		return nil
+	})
+}
+
+func getUserGroupByName(ctx context.Context, q *query.GetUserGroupByName) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		group := dbUserGroup{}
+		err := trx.Get(&group, sqlSelectUserGroups+" WHERE g.tenant_id = $1 AND LOWER(g.name) = LOWER($2)", tenant.ID, q.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to get user group with name '%s'", q.Name)
+		}
+
+		q.Result = group.toModel()
+		return nil
+	})
+}
+
+func getAllUserGroups(ctx context.Context, q *query.GetAllUserGroups) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		groups := []*dbUserGroup{}
+		err := trx.Select(&groups, sqlSelectUserGroups+" WHERE g.tenant_id = $1 ORDER BY g.name", tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get all user groups")
+		}
+
+		q.Result = make([]*entity.UserGroup, len(groups))
+		for i, group := range groups {
+			q.Result[i] = group.toModel()
+		}
+		return nil
+	})
+}
+
+func getUserGroupMembers(ctx context.Context, q *query.GetUserGroupMembers) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var users []*dbUser
+		err := trx.Select(&users, `
+			SELECT u.id, u.name, u.email, u.tenant_id, u.role, u.status, u.avatar_type, u.avatar_bkey, u.custom_role_id
+			FROM users u
+			INNER JOIN user_groups g
+			ON g.tenant_id = u.tenant_id
+			WHERE g.tenant_id = $1 AND g.id = $2 AND u.status != $3
+			AND `+sqlIsGroupMember+`
+			ORDER BY u.name`, tenant.ID, q.GroupID, enum.UserDeleted)
+		if err != nil {
+			return errors.Wrap(err, "failed to get members of user group with id '%d'", q.GroupID)
+		}
+
+		q.Result = make([]*entity.User, len(users))
+		for i, member := range users {
+			q.Result[i] = member.toModel(ctx)
+		}
+		return nil
+	})
+}
+
+func addNewUserGroup(ctx context.Context, c *cmd.AddNewUserGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		var id int
+		err := trx.Get(&id, `
+			INSERT INTO user_groups (tenant_id, name, email_domains, oauth_claim, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			RETURNING id
+		`, tenant.ID, c.Name, pq.Array(c.EmailDomains), c.OAuthClaim)
+		if err != nil {
+			return errors.Wrap(err, "failed to add new user group")
+		}
+
+		group := dbUserGroup{}
+		err = trx.Get(&group, sqlSelectUserGroups+" WHERE g.tenant_id = $1 AND g.id = $2", tenant.ID, id)
+		if err != nil {
+			return errors.Wrap(err, "failed to get user group with id '%d'", id)
+		}
+
+		c.Result = group.toModel()
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserGroupCreated,
+			TargetType: "group",
+			TargetID:   group.ID,
+			TargetName: group.Name,
+			After:      userGroupAuditValues(&group),
+		})
+	})
+}
+
+func updateUserGroup(ctx context.Context, c *cmd.UpdateUserGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous, err := queryAuditUserGroup(trx, tenant, c.GroupID)
+		if err != nil {
+			return err
+		}
+
+		_, err = trx.Execute(`
+			UPDATE user_groups SET name = $1, email_domains = $2, oauth_claim = $3
+			WHERE id = $4 AND tenant_id = $5
+		`, c.Name, pq.Array(c.EmailDomains), c.OAuthClaim, c.GroupID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update user group with id '%d'", c.GroupID)
+		}
+
+		group := dbUserGroup{}
+		err = trx.Get(&group, sqlSelectUserGroups+" WHERE g.tenant_id = $1 AND g.id = $2", tenant.ID, c.GroupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get user group with id '%d'", c.GroupID)
+		}
+
+		c.Result = group.toModel()
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserGroupUpdated,
+			TargetType: "group",
+			TargetID:   group.ID,
+			TargetName: group.Name,
+			Before:     userGroupAuditValues(previous),
+			After:      userGroupAuditValues(&group),
+		})
+	})
+}
+
+func deleteUserGroup(ctx context.Context, c *cmd.DeleteUserGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		previous, err := queryAuditUserGroup(trx, tenant, c.GroupID)
+		if err != nil {
+			return err
+		}
+
+		// restricted tags, roadmap columns and posts become visible to everyone again
+		_, err = trx.Execute(`DELETE FROM user_groups WHERE id = $1 AND tenant_id = $2`, c.GroupID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete user group with id '%d'", c.GroupID)
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserGroupDeleted,
+			TargetType: "group",
+			TargetID:   previous.ID,
+			TargetName: previous.Name,
+			Before:     userGroupAuditValues(previous),
+		})
+	})
+}
+
+func addUserToGroup(ctx context.Context, c *cmd.AddUserToGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		// a manually added member stays in the group even if the OAuth claim goes away
+		_, err := trx.Execute(`
+			INSERT INTO user_group_members (tenant_id, group_id, user_id, is_from_oauth, created_at)
+			VALUES ($1, $2, $3, false, NOW())
+			ON CONFLICT (group_id, user_id) DO UPDATE SET is_from_oauth = false
+		`, tenant.ID, c.GroupID, c.UserID)
+		if err != nil {
+			return errors.Wrap(err, "failed to add user '%d' to group '%d'", c.UserID, c.GroupID)
+		}
+
+		return insertMembershipAuditLog(ctx, trx, tenant, user, entity.AuditUserGroupMemberAdded, c.GroupID, c.UserID)
+	})
+}
+
+func removeUserFromGroup(ctx context.Context, c *cmd.RemoveUserFromGroup) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			DELETE FROM user_group_members WHERE group_id = $1 AND user_id = $2 AND tenant_id = $3
+		`, c.GroupID, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove user '%d' from group '%d'", c.UserID, c.GroupID)
+		}
+
+		return insertMembershipAuditLog(ctx, trx, tenant, user, entity.AuditUserGroupMemberRemoved, c.GroupID, c.UserID)
+	})
+}
+
+func queryAuditUserGroup(trx *dbx.Trx, tenant *entity.Tenant, groupID int) (*dbUserGroup, error) {
+	group := &dbUserGroup{}
+	err := trx.Get(group, "SELECT id, name, email_domains, oauth_claim FROM user_groups WHERE tenant_id = $1 AND id = $2", tenant.ID, groupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user group with id '%d'", groupID)
+	}
+	return group, nil
+}
+
+func userGroupAuditValues(group *dbUserGroup) map[string]any {
+	return map[string]any{
+		"name":         group.Name,
+		"emailDomains": group.EmailDomains,
+		"oauthClaim":   group.OAuthClaim,
+	}
+}
+
+// insertMembershipAuditLog records a manual membership change on the member, along with the group it concerns
+func insertMembershipAuditLog(ctx context.Context, trx *dbx.Trx, tenant *entity.Tenant, user *entity.User, action string, groupID, userID int) error {
+	group, err := queryAuditUserGroup(trx, tenant, groupID)
+	if err != nil {
+		return err
+	}
+
+	member, err := getAuditUser(trx, tenant, userID)
+	if err != nil {
+		return err
+	}
+
+	return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+		Action:     action,
+		TargetType: "user",
+		TargetID:   userID,
+		TargetName: member.Name,
+		After: map[string]any{
+			"groupId":   group.ID,
+			"groupName": group.Name,
+		},
+	})
+}
+
+func syncUserOAuthGroups(ctx context.Context, c *cmd.SyncUserOAuthGroups) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			DELETE FROM user_group_members WHERE user_id = $1 AND tenant_id = $2 AND is_from_oauth = true
+		`, c.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to remove OAuth groups of user '%d'", c.UserID)
+		}
+
+		claims := make([]string, len(c.Claims))
+		for i, claim := range c.Claims {
+			claims[i] = strings.ToLower(claim)
+		}
+
+		_, err = trx.Execute(`
+			INSERT INTO user_group_members (tenant_id, group_id, user_id, is_from_oauth, created_at)
+			SELECT g.tenant_id, g.id, $2, true, NOW()
+			FROM user_groups g
+			WHERE g.tenant_id = $1 AND g.oauth_claim != '' AND LOWER(g.oauth_claim) = ANY($3)
+			ON CONFLICT (group_id, user_id) DO NOTHING
+		`, tenant.ID, c.UserID, pq.Array(claims))
+		if err != nil {
+			return errors.Wrap(err, "failed to add OAuth groups of user '%d'", c.UserID)
+		}
+		return nil
+	})
+}
+
+// sqlGroupCondition matches the rows of given column that aren't restricted to a group or are restricted to one of the groups of given user
+func sqlGroupCondition(column string, user *entity.User) string {
+	if user == nil || len(user.GroupIDs) == 0 {
+		return column + " IS NULL"
+	}
+
+	ids := make([]string, len(user.GroupIDs))
+	for i, id := range user.GroupIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s IN (%s))", column, column, strings.Join(ids, ", "))
+}
+
+// loadUserGroups fills the groups given users are members of
+func loadUserGroups(trx *dbx.Trx, tenantID int, users ...*dbUser) error {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		if u.ID.Valid {
+			ids = append(ids, u.ID.Int64)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	type dbMembership struct {
+		UserID  int64 `db:"user_id"`
+		GroupID int   `db:"group_id"`
+	}
+
+	memberships := []*dbMembership{}
+	err := trx.Select(&memberships, `
+		SELECT u.id AS user_id, g.id AS group_id
+		FROM users u
+		INNER JOIN user_groups g
+		ON g.tenant_id = u.tenant_id
+		WHERE u.tenant_id = $1 AND u.id = ANY($2)
+		AND `+sqlIsGroupMember+`
+		ORDER BY g.id`, tenantID, pq.Array(ids))
+	if err != nil {
+		return errors.Wrap(err, "failed to get user groups")
+	}
+
+	for _, u := range users {
+		u.GroupIDs = []int{}
+		for _, m := range memberships {
+			if u.ID.Int64 == m.UserID {
+				u.GroupIDs = append(u.GroupIDs, m.GroupID)
+			}
+		}
+	}
+	return nil
+}
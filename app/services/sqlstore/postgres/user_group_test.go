@@ -0,0 +1,116 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestUserGroupStorage_AddUpdateAndDelete(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewGroup := &cmd.AddNewUserGroup{Name: "Beta Testers"}
+	err := bus.Dispatch(jonSnowCtx, addNewGroup)
+	Expect(err).IsNil()
+	Expect(addNewGroup.Result.ID).IsNotEmpty()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddUserToGroup{GroupID: addNewGroup.Result.ID, UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	updateGroup := &cmd.UpdateUserGroup{GroupID: addNewGroup.Result.ID, Name: "Beta", OAuthClaim: "beta"}
+	err = bus.Dispatch(jonSnowCtx, updateGroup)
+	Expect(err).IsNil()
+	Expect(updateGroup.Result.MembersCount).Equals(1)
+
+	getGroup := &query.GetUserGroupByName{Name: "BETA"}
+	err = bus.Dispatch(jonSnowCtx, getGroup)
+	Expect(err).IsNil()
+	Expect(getGroup.Result.OAuthClaim).Equals("beta")
+
+	getUser := &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.IsMemberOf(getGroup.Result.ID)).IsTrue()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.DeleteUserGroup{GroupID: getGroup.Result.ID})
+	Expect(err).IsNil()
+
+	getByID := &query.GetUserGroupByID{GroupID: getGroup.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestUserGroupStorage_EmailDomainAndOAuthClaims(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	byDomain := &cmd.AddNewUserGroup{Name: "Westeros", EmailDomains: []string{"got.com"}}
+	byClaim := &cmd.AddNewUserGroup{Name: "Nights Watch", OAuthClaim: "nights-watch"}
+	err := bus.Dispatch(jonSnowCtx, byDomain, byClaim)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SyncUserOAuthGroups{UserID: aryaStark.ID, Claims: []string{"Nights-Watch"}})
+	Expect(err).IsNil()
+
+	members := &query.GetUserGroupMembers{GroupID: byClaim.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, members)
+	Expect(err).IsNil()
+	Expect(members.Result).HasLen(1)
+	Expect(members.Result[0].ID).Equals(aryaStark.ID)
+
+	getUser := &query.GetUserByID{UserID: sansaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getUser)
+	Expect(err).IsNil()
+	Expect(getUser.Result.IsMemberOf(byDomain.Result.ID)).IsTrue()
+	Expect(getUser.Result.IsMemberOf(byClaim.Result.ID)).IsFalse()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SyncUserOAuthGroups{UserID: aryaStark.ID, Claims: []string{}})
+	Expect(err).IsNil()
+
+	members = &query.GetUserGroupMembers{GroupID: byClaim.Result.ID}
+	err = bus.Dispatch(jonSnowCtx, members)
+	Expect(err).IsNil()
+	Expect(members.Result).HasLen(0)
+}
+
+func TestPostStorage_GroupRestrictedPost(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	addNewGroup := &cmd.AddNewUserGroup{Name: "Beta Testers"}
+	err := bus.Dispatch(jonSnowCtx, addNewGroup)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.AddUserToGroup{GroupID: addNewGroup.Result.ID, UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	newPost := &cmd.AddNewPost{Title: "My beta feature post", Description: "only for beta testers"}
+	err = bus.Dispatch(jonSnowCtx, newPost)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.SetPostGroup{Post: newPost.Result, GroupID: addNewGroup.Result.ID})
+	Expect(err).IsNil()
+
+	getArya := &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getArya)
+	Expect(err).IsNil()
+
+	byMember := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(withUser(jonSnowCtx, getArya.Result), byMember)
+	Expect(err).IsNil()
+	Expect(byMember.Result.GroupID).Equals(addNewGroup.Result.ID)
+
+	byOther := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(sansaStarkCtx, byOther)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	byAnonymous := &query.GetPostByNumber{Number: newPost.Result.Number}
+	err = bus.Dispatch(demoTenantCtx, byAnonymous)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
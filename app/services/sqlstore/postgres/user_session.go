@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/crypto"
+	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+type dbUserSession struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	UserAgent  string    `db:"user_agent"`
+	IPAddress  string    `db:"ip_address"`
+	CreatedAt  time.Time `db:"created_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+}
+
+func (s *dbUserSession) toModel() *entity.UserSession {
+	return &entity.UserSession{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+	}
+}
+
+// truncate keeps client provided values within the column limits
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
+
+func createUserSession(ctx context.Context, c *cmd.CreateUserSession) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		now := time.Now()
+		session := dbUserSession{}
+		err := trx.Get(&session, `
+			INSERT INTO user_sessions (tenant_id, user_id, key_hash, user_agent, ip_address, created_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6)
+			RETURNING id, user_id, user_agent, ip_address, created_at, last_seen_at
+		`, tenant.ID, c.UserID, crypto.SHA512(c.Key), truncate(c.UserAgent, 500), truncate(c.IPAddress, 50), now)
+		if err != nil {
+			return errors.Wrap(err, "failed to create session for user '%d'", c.UserID)
+		}
+
+		c.Result = session.toModel()
+		return nil
+	})
+}
+
+func getUserSessionByKey(ctx context.Context, q *query.GetUserSessionByKey) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		session := dbUserSession{}
+		err := trx.Get(&session, `
+			SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at
+			FROM user_sessions
+			WHERE key_hash = $1
+		`, crypto.SHA512(q.Key))
+		if err != nil {
+			return errors.Wrap(err, "failed to get session by key")
+		}
+
+		q.Result = session.toModel()
+		return nil
+	})
+}
+
+func getUserSessions(ctx context.Context, q *query.GetUserSessions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		sessions := []*dbUserSession{}
+		err := trx.Select(&sessions, `
+			SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at
+			FROM user_sessions
+			WHERE user_id = $1 AND tenant_id = $2
+			ORDER BY last_seen_at DESC
+		`, q.UserID, tenant.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get sessions of user '%d'", q.UserID)
+		}
+
+		q.Result = make([]*entity.UserSession, len(sessions))
+		for i, session := range sessions {
+			q.Result[i] = session.toModel()
+		}
+		return nil
+	})
+}
+
+func touchUserSession(ctx context.Context, c *cmd.TouchUserSession) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(`
+			UPDATE user_sessions SET last_seen_at = $1, user_agent = $2, ip_address = $3
+			WHERE id = $4
+		`, time.Now(), truncate(c.UserAgent, 500), truncate(c.IPAddress, 50), c.SessionID)
+		if err != nil {
+			return errors.Wrap(err, "failed to touch session '%d'", c.SessionID)
+		}
+		return nil
+	})
+}
+
+func revokeUserSession(ctx context.Context, c *cmd.RevokeUserSession) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(
+			"DELETE FROM user_sessions WHERE id = $1 AND user_id = $2 AND tenant_id = $3",
+			c.SessionID, c.UserID, tenant.ID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke session '%d'", c.SessionID)
+		}
+		return nil
+	})
+}
+
+func revokeUserSessions(ctx context.Context, c *cmd.RevokeUserSessions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(
+			"DELETE FROM user_sessions WHERE user_id = $1 AND tenant_id = $2 AND id != $3",
+			c.UserID, tenant.ID, c.ExceptSessionID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke sessions of user '%d'", c.UserID)
+		}
+
+		// users signing themselves out is not an administrative action
+		if user != nil && user.ID == c.UserID {
+			return nil
+		}
+
+		target, err := getAuditUser(trx, tenant, c.UserID)
+		if err != nil {
+			return err
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditUserSessionsRevoked,
+			TargetType: "user",
+			TargetID:   c.UserID,
+			TargetName: target.Name,
+		})
+	})
+}
+
+func revokeAllUserSessions(ctx context.Context, c *cmd.RevokeAllUserSessions) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		_, err := trx.Execute(
+			"DELETE FROM user_sessions WHERE tenant_id = $1 AND id != $2",
+			tenant.ID, c.ExceptSessionID,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke all sessions")
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditAllSessionsRevoked,
+			TargetType: "tenant",
+			TargetID:   tenant.ID,
+			TargetName: tenant.Name,
+		})
+	})
+}
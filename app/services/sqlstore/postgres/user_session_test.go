@@ -0,0 +1,79 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+)
+
+func TestUserSessionStorage_CreateAndRevoke(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	first := &cmd.CreateUserSession{UserID: jonSnow.ID, Key: "KEY1", UserAgent: "Firefox", IPAddress: "10.0.0.1"}
+	second := &cmd.CreateUserSession{UserID: jonSnow.ID, Key: "KEY2", UserAgent: "Chrome", IPAddress: "10.0.0.2"}
+	err := bus.Dispatch(jonSnowCtx, first, second)
+	Expect(err).IsNil()
+
+	getByKey := &query.GetUserSessionByKey{Key: "KEY1"}
+	err = bus.Dispatch(jonSnowCtx, getByKey)
+	Expect(err).IsNil()
+	Expect(getByKey.Result.ID).Equals(first.Result.ID)
+	Expect(getByKey.Result.UserID).Equals(jonSnow.ID)
+	Expect(getByKey.Result.UserAgent).Equals("Firefox")
+
+	getSessions := &query.GetUserSessions{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, getSessions)
+	Expect(err).IsNil()
+	Expect(getSessions.Result).HasLen(2)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeUserSessions{UserID: jonSnow.ID, ExceptSessionID: first.Result.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserSessionByKey{Key: "KEY2"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeUserSession{UserID: jonSnow.ID, SessionID: first.Result.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserSessionByKey{Key: "KEY1"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestUserSessionStorage_RevokeAll(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	jon := &cmd.CreateUserSession{UserID: jonSnow.ID, Key: "KEY1"}
+	arya := &cmd.CreateUserSession{UserID: aryaStark.ID, Key: "KEY2"}
+	err := bus.Dispatch(jonSnowCtx, jon, arya)
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.RevokeAllUserSessions{ExceptSessionID: jon.Result.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserSessionByKey{Key: "KEY1"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserSessionByKey{Key: "KEY2"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
+
+func TestUserSessionStorage_BlockUserRevokesSessions(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.CreateUserSession{UserID: aryaStark.ID, Key: "KEY1"})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &cmd.BlockUser{UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	err = bus.Dispatch(jonSnowCtx, &query.GetUserSessionByKey{Key: "KEY1"})
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+}
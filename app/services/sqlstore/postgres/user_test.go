@@ -364,6 +364,35 @@ func TestUserStorage_Delete(t *testing.T) {
 	Expect(getByID.Result).IsNil()
 }
 
+func TestUserStorage_DeleteUser(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(jonSnowCtx, &cmd.DeleteUser{UserID: aryaStark.ID})
+	Expect(err).IsNil()
+
+	getByID := &query.GetUserByID{UserID: aryaStark.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(errors.Cause(err)).Equals(app.ErrNotFound)
+
+	getByID = &query.GetUserByID{UserID: jonSnow.ID}
+	err = bus.Dispatch(jonSnowCtx, getByID)
+	Expect(err).IsNil()
+}
+
+func TestUserStorage_ChangeName(t *testing.T) {
+	SetupDatabaseTest(t)
+	defer TeardownDatabaseTest()
+
+	err := bus.Dispatch(demoTenantCtx, &cmd.ChangeUserName{UserID: jonSnow.ID, Name: "Aegon Targaryen"})
+	Expect(err).IsNil()
+
+	getByID := &query.GetUserByID{UserID: jonSnow.ID}
+	err = bus.Dispatch(demoTenantCtx, getByID)
+	Expect(err).IsNil()
+	Expect(getByID.Result.Name).Equals("Aegon Targaryen")
+}
+
 func TestUserStorage_APIKey(t *testing.T) {
 	SetupDatabaseTest(t)
 	defer TeardownDatabaseTest()
@@ -2,37 +2,72 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/getfider/fider/app"
 	"github.com/getfider/fider/app/models/cmd"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
 	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/lib/pq"
 )
 
+type dbVoteUser struct {
+	ID            sql.NullInt64  `db:"id"`
+	Name          sql.NullString `db:"name"`
+	Email         sql.NullString `db:"email"`
+	AvatarType    sql.NullInt64  `db:"avatar_type"`
+	AvatarBlobKey sql.NullString `db:"avatar_bkey"`
+	CompanyID     sql.NullInt64  `db:"company_id"`
+	CompanyName   sql.NullString `db:"company_name"`
+	CompanyMRR    sql.NullInt64  `db:"company_mrr"`
+	CompanyPlan   sql.NullString `db:"company_plan"`
+}
+
+func (u *dbVoteUser) toModel(ctx context.Context) *entity.VoteUser {
+	if u == nil || !u.ID.Valid {
+		return nil
+	}
+
+	id := int(u.ID.Int64)
+	user := &entity.VoteUser{
+		ID:        id,
+		Name:      u.Name.String,
+		Email:     u.Email.String,
+		AvatarURL: buildAvatarURL(ctx, enum.AvatarType(u.AvatarType.Int64), id, u.Name.String, u.AvatarBlobKey.String),
+	}
+
+	if u.CompanyID.Valid {
+		user.Company = &entity.Company{
+			ID:   int(u.CompanyID.Int64),
+			Name: u.CompanyName.String,
+			MRR:  int(u.CompanyMRR.Int64),
+			Plan: u.CompanyPlan.String,
+		}
+	}
+	return user
+}
+
 type dbVote struct {
-	User *struct {
-		ID            int    `db:"id"`
-		Name          string `db:"name"`
-		Email         string `db:"email"`
-		AvatarType    int64  `db:"avatar_type"`
-		AvatarBlobKey string `db:"avatar_bkey"`
-	} `db:"user"`
-	CreatedAt time.Time `db:"created_at"`
+	User      *dbVoteUser `db:"user"`
+	ProxiedBy *dbVoteUser `db:"proxied_by"`
+	Weight    int         `db:"weight"`
+	Note      string      `db:"note"`
+	CreatedAt time.Time   `db:"created_at"`
 }
 
 func (v *dbVote) toModel(ctx context.Context) *entity.Vote {
 	vote := &entity.Vote{
+		Weight:    v.Weight,
+		Note:      v.Note,
 		CreatedAt: v.CreatedAt,
-		User: &entity.VoteUser{
-			ID:        v.User.ID,
-			Name:      v.User.Name,
-			Email:     v.User.Email,
-			AvatarURL: buildAvatarURL(ctx, enum.AvatarType(v.User.AvatarType), v.User.ID, v.User.Name, v.User.AvatarBlobKey),
-		},
+		User:      v.User.toModel(ctx),
+		ProxiedBy: v.ProxiedBy.toModel(ctx),
 	}
 	return vote
 }
@@ -43,16 +78,57 @@ func addVote(ctx context.Context, c *cmd.AddVote) error {
 			return nil
 		}
 
+		// Without an explicit weight an existing vote keeps its weight,
+		// but a proxy vote still records who added it and why
+		updates := make([]string, 0)
+		weight := c.Weight
+		if weight > 0 {
+			updates = append(updates, "weight = EXCLUDED.weight")
+		} else {
+			weight = 1
+		}
+		if c.ProxiedBy != nil {
+			updates = append(updates, "proxied_by_id = EXCLUDED.proxied_by_id", "note = EXCLUDED.note")
+		}
+
+		onConflict := "DO NOTHING"
+		if len(updates) > 0 {
+			onConflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+		}
+
+		// The budget was checked before the transaction started, so it's checked again once the votes of the user are locked
+		// Votes without an explicit weight are added by Fider itself, such as when a post is marked as duplicate
+		if c.Weight > 0 && tenant.VotingMode == enum.VotingModeBudget {
+			// A user without votes has no rows to lock, so the user is locked as well
+			if _, err := trx.Execute("SELECT 1 FROM users WHERE id = $1 AND tenant_id = $2 FOR UPDATE", c.User.ID, tenant.ID); err != nil {
+				return errors.Wrap(err, "failed to lock user with id '%d'", c.User.ID)
+			}
+
+			spent, err := querySpentVotes(trx, tenant, c.User.ID, c.Post.ID, true)
+			if err != nil {
+				return err
+			}
+			if c.Weight > tenant.VoteBudget-spent {
+				return app.ErrVoteBudgetExceeded
+			}
+		}
+
+		var proxiedByID sql.NullInt64
+		if c.ProxiedBy != nil {
+			proxiedByID = sql.NullInt64{Int64: int64(c.ProxiedBy.ID), Valid: true}
+		}
+
 		_, err := trx.Execute(
-			`INSERT INTO post_votes (tenant_id, user_id, post_id, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
-			tenant.ID, c.User.ID, c.Post.ID, time.Now(),
+			`INSERT INTO post_votes (tenant_id, user_id, post_id, created_at, weight, proxied_by_id, note) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id, post_id) `+onConflict,
+			tenant.ID, c.User.ID, c.Post.ID, time.Now(), weight, proxiedByID, c.Note,
 		)
 
 		if err != nil {
 			return errors.Wrap(err, "failed add vote to post")
 		}
 
-		return nil
+		return notifyVotesChanged(trx, tenant, c.Post)
 	})
 }
 
@@ -67,7 +143,7 @@ func removeVote(ctx context.Context, c *cmd.RemoveVote) error {
 			return errors.Wrap(err, "failed to remove vote from post")
 		}
 
-		return nil
+		return notifyVotesChanged(trx, tenant, c.Post)
 	})
 }
 
@@ -79,24 +155,50 @@ func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 			sqlLimit = strconv.Itoa(q.Limit)
 		}
 
+		// Emails, companies, proxies and notes are only meant for staff
 		emailColumn := "''"
+		companyCondition := "AND 1 = 0"
+		proxyCondition := "AND 1 = 0"
+		noteColumn := "''"
 		if q.IncludeEmail {
 			emailColumn = "u.email"
+			companyCondition = ""
+			proxyCondition = ""
+			noteColumn = "pv.note"
 		}
 
 		votes := []*dbVote{}
 		err := trx.Select(&votes, `
 		SELECT 
 			pv.created_at, 
+			pv.weight,
+			`+noteColumn+` AS note,
 			u.id AS user_id,
 			u.name AS user_name,
 			`+emailColumn+` AS user_email,
 			u.avatar_type AS user_avatar_type,
-			u.avatar_bkey AS user_avatar_bkey
+			u.avatar_bkey AS user_avatar_bkey,
+			c.id AS user_company_id,
+			c.name AS user_company_name,
+			c.mrr AS user_company_mrr,
+			c.plan AS user_company_plan,
+			pb.id AS proxied_by_id,
+			pb.name AS proxied_by_name,
+			pb.email AS proxied_by_email,
+			pb.avatar_type AS proxied_by_avatar_type,
+			pb.avatar_bkey AS proxied_by_avatar_bkey
 		FROM post_votes pv
 		INNER JOIN users u
 		ON u.id = pv.user_id
 		AND u.tenant_id = pv.tenant_id 
+		LEFT JOIN companies c
+		ON c.id = u.company_id
+		AND c.tenant_id = u.tenant_id
+		`+companyCondition+`
+		LEFT JOIN users pb
+		ON pb.id = pv.proxied_by_id
+		AND pb.tenant_id = pv.tenant_id
+		`+proxyCondition+`
 		WHERE pv.post_id = $1  
 		AND pv.tenant_id = $2
 		ORDER BY pv.created_at
@@ -113,3 +215,58 @@ func listPostVotes(ctx context.Context, q *query.ListPostVotes) error {
 		return nil
 	})
 }
+
+func getVoteBudget(ctx context.Context, q *query.GetVoteBudget) error {
+	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
+		userID := user.ID
+		if q.UserID > 0 {
+			userID = q.UserID
+		}
+
+		spent, err := querySpentVotes(trx, tenant, userID, q.ExcludePostID, false)
+		if err != nil {
+			return err
+		}
+
+		q.Result = &entity.VoteBudget{
+			Total:      tenant.VoteBudget,
+			Spent:      spent,
+			Remaining:  max(tenant.VoteBudget-spent, 0),
+			MaxPerPost: tenant.MaxVotesPerPost,
+		}
+		return nil
+	})
+}
+
+// querySpentVotes returns the weight of the votes given by a user, except on given post
+// Votes on closed posts are given back to the user
+func querySpentVotes(trx *dbx.Trx, tenant *entity.Tenant, userID, excludePostID int, forUpdate bool) (int, error) {
+	lock := ""
+	if forUpdate {
+		lock = "FOR UPDATE OF pv"
+	}
+
+	var spent int
+	err := trx.Scalar(&spent, `
+		SELECT COALESCE(SUM(v.weight), 0) FROM (
+			SELECT pv.weight
+			FROM post_votes pv
+			INNER JOIN posts p
+			ON p.id = pv.post_id
+			AND p.tenant_id = pv.tenant_id
+			WHERE pv.user_id = $1
+			AND pv.tenant_id = $2
+			AND pv.post_id <> $3
+			AND p.status = ANY($4)
+			`+lock+`
+		) v
+	`, userID, tenant.ID, excludePostID, pq.Array([]enum.PostStatus{
+		enum.PostOpen,
+		enum.PostStarted,
+		enum.PostPlanned,
+	}))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get vote budget of user with id '%d'", userID)
+	}
+	return spent, nil
+}
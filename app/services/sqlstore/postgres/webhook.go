@@ -6,13 +6,14 @@ import (
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/dbx"
+	"github.com/getfider/fider/app/pkg/errors"
 )
 
 func getWebhook(ctx context.Context, q *query.GetWebhook) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhook := &entity.Webhook{}
 		err := trx.Get(webhook, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, is_internal 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND id = $2`, tenant.ID, q.ID)
 		if err != nil {
@@ -28,7 +29,7 @@ func listAllWebhooks(ctx context.Context, q *query.ListAllWebhooks) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, is_internal 
 			FROM webhooks 
 			WHERE tenant_id = $1 
 			ORDER BY id`, tenant.ID)
@@ -45,7 +46,7 @@ func listAllWebhooksByType(ctx context.Context, q *query.ListAllWebhooksByType)
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, is_internal 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND type = $2 
 			ORDER BY id`, tenant.ID, q.Type)
@@ -62,7 +63,7 @@ func listActiveWebhooksByType(ctx context.Context, q *query.ListActiveWebhooksBy
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		webhooks := []*entity.Webhook{}
 		err := trx.Select(&webhooks, `
-			SELECT id, name, type, status, url, content, http_method, http_headers 
+			SELECT id, name, type, status, url, content, http_method, http_headers, is_internal 
 			FROM webhooks 
 			WHERE tenant_id = $1 AND type = $2 AND status = $3 
 			ORDER BY id`, tenant.ID, q.Type, enum.WebhookEnabled)
@@ -78,37 +79,100 @@ func listActiveWebhooksByType(ctx context.Context, q *query.ListActiveWebhooksBy
 func createEditWebhook(ctx context.Context, q *query.CreateEditWebhook) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		var err error
+		var before map[string]any
 		id := q.ID
 
+		if q.ID != 0 {
+			previous, err := getAuditWebhook(trx, tenant, q.ID)
+			if err != nil {
+				return err
+			}
+			before = webhookAuditValues(previous)
+		}
+
 		if q.ID == 0 {
 			err = trx.Get(&id, `
-				INSERT INTO webhooks (name, type, status, url, content, http_method, http_headers, tenant_id) 
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-				RETURNING id`, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders, tenant.ID)
+				INSERT INTO webhooks (name, type, status, url, content, http_method, http_headers, is_internal, tenant_id) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+				RETURNING id`, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders, q.IsInternal, tenant.ID)
 		} else {
 			_, err = trx.Execute(`
 				UPDATE webhooks 
-				SET name = $3, type = $4, status = $5, url = $6, content = $7, http_method = $8, http_headers = $9 
-				WHERE tenant_id = $1 AND id = $2`, tenant.ID, q.ID, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders)
+				SET name = $3, type = $4, status = $5, url = $6, content = $7, http_method = $8, http_headers = $9, is_internal = $10 
+				WHERE tenant_id = $1 AND id = $2`, tenant.ID, q.ID, q.Name, q.Type, q.Status, q.Url, q.Content, q.HttpMethod, q.HttpHeaders, q.IsInternal)
 		}
 
 		if err != nil {
 			return err
 		}
 		q.Result = id
-		return nil
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditWebhookSaved,
+			TargetType: "webhook",
+			TargetID:   id,
+			TargetName: q.Name,
+			Before:     before,
+			After: webhookAuditValues(&entity.Webhook{
+				Name:       q.Name,
+				Type:       q.Type,
+				Status:     q.Status,
+				Url:        q.Url,
+				HttpMethod: q.HttpMethod,
+				IsInternal: q.IsInternal,
+			}),
+		})
 	})
 }
 
 func deleteWebhook(ctx context.Context, q *query.DeleteWebhook) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
-		_, err := trx.Execute(`
+		previous, err := getAuditWebhook(trx, tenant, q.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = trx.Execute(`
 			DELETE FROM webhooks 
 			WHERE tenant_id = $1 AND id = $2`, tenant.ID, q.ID)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return insertAuditLog(ctx, trx, tenant, user, auditEntry{
+			Action:     entity.AuditWebhookDeleted,
+			TargetType: "webhook",
+			TargetID:   q.ID,
+			TargetName: previous.Name,
+			Before:     webhookAuditValues(previous),
+		})
 	})
 }
 
+func getAuditWebhook(trx *dbx.Trx, tenant *entity.Tenant, id int) (*entity.Webhook, error) {
+	webhook := &entity.Webhook{}
+	err := trx.Get(webhook, `
+		SELECT id, name, type, status, url, content, http_method, http_headers, is_internal 
+		FROM webhooks 
+		WHERE tenant_id = $1 AND id = $2`, tenant.ID, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook with id '%d'", id)
+	}
+	return webhook, nil
+}
+
+// webhookAuditValues returns the settings of a webhook recorded on the audit log, headers and content may hold secrets and are left out
+func webhookAuditValues(webhook *entity.Webhook) map[string]any {
+	return map[string]any{
+		"name":       webhook.Name,
+		"type":       webhook.Type,
+		"status":     webhook.Status,
+		"url":        webhook.Url,
+		"httpMethod": webhook.HttpMethod,
+		"isInternal": webhook.IsInternal,
+	}
+}
+
 func markWebhookAsFailed(ctx context.Context, q *query.MarkWebhookAsFailed) error {
 	return using(ctx, func(trx *dbx.Trx, tenant *entity.Tenant, user *entity.User) error {
 		_, err := trx.Execute(`
@@ -31,6 +31,7 @@ You can use ` + "`" + `{{ markdown .post_description }}` + "`" + ` to parse __Ma
 	},
 	HasVoted:      true,
 	VotesCount:    7,
+	VotesWeight:   12,
 	CommentsCount: 3,
 	Status:        enum.PostStarted,
 	Response: &entity.PostResponse{
@@ -39,6 +40,10 @@ You can use ` + "`" + `{{ markdown .post_description }}` + "`" + ` to parse __Ma
 		User:        nil,
 	},
 	Tags: []string{"tag1", "tag2"},
+	CustomFields: map[string]any{
+		"platform": []string{"iOS", "Android"},
+		"priority": "High",
+	},
 }
 
 func dummyTriggerProps(c context.Context, webhookType enum.WebhookType) webhook.Props {
@@ -62,8 +62,27 @@ func triggerWebhooks(ctx context.Context, c *cmd.TriggerWebhooks) error {
 		return err
 	}
 
+	var publicProps webhook.Props
 	for _, webhook_ := range webhooks.Result {
-		_, err = triggerWebhook(ctx, webhook_, c.Props)
+		// private posts and internal comments are only sent to webhooks that target the staff
+		if c.Internal && !webhook_.IsInternal {
+			continue
+		}
+
+		// and so are custom fields that are not public
+		props := c.Props
+		if !webhook_.IsInternal {
+			if publicProps == nil {
+				getFields := &query.GetAllCustomFields{}
+				if err := bus.Dispatch(ctx, getFields); err != nil {
+					return err
+				}
+				publicProps = c.Props.WithPublicCustomFields(getFields.Result)
+			}
+			props = publicProps
+		}
+
+		_, err = triggerWebhook(ctx, webhook_, props)
 		if err != nil {
 			return err
 		}
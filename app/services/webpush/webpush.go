@@ -0,0 +1,139 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/markdown"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/webpush"
+)
+
+func init() {
+	bus.Register(Service{})
+}
+
+type Service struct{}
+
+func (s Service) Name() string {
+	return "Web Push"
+}
+
+func (s Service) Category() string {
+	return "webpush"
+}
+
+func (s Service) Enabled() bool {
+	return env.IsWebPushEnabled()
+}
+
+func (s Service) Init() {
+	bus.AddListener(sendPushNotification)
+}
+
+// message is what the service worker receives and shows to the user
+type message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	Icon  string `json:"icon"`
+}
+
+func sendPushNotification(ctx context.Context, c *cmd.SendPushNotification) error {
+	title := ""
+	if tenant, ok := ctx.Value(app.TenantCtxKey).(*entity.Tenant); ok {
+		title = tenant.Name
+	}
+
+	payload, err := json.Marshal(message{
+		Title: title,
+		Body:  markdown.PlainText(c.Title),
+		URL:   web.BaseURL(ctx) + c.Link,
+		Icon:  web.LogoURL(ctx),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal push notification")
+	}
+
+	for _, user := range c.To {
+		getSubscriptions := &query.GetPushSubscriptions{UserID: user.ID}
+		if err := bus.Dispatch(ctx, getSubscriptions); err != nil {
+			return err
+		}
+
+		// A browser that fails to receive it shouldn't stop the other browsers from getting it
+		for _, subscription := range getSubscriptions.Result {
+			if err := send(ctx, subscription, payload); err != nil {
+				log.Warnf(ctx, "Failed to send push notification to user @{UserID}: @{Error}", dto.Props{
+					"UserID": user.ID,
+					"Error":  err.Error(),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func send(ctx context.Context, subscription *entity.PushSubscription, payload []byte) error {
+	body, err := webpush.Encrypt(payload, webpush.Keys{
+		P256dh: subscription.P256dh,
+		Auth:   subscription.Auth,
+	})
+	if err != nil {
+		return err
+	}
+
+	authorization, err := webpush.VAPIDAuthorization(subscription.Endpoint, subject(), webpush.VAPIDKeys{
+		PublicKey:  env.Config.WebPush.VAPIDPublicKey,
+		PrivateKey: env.Config.WebPush.VAPIDPrivateKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	req := &cmd.HTTPRequest{
+		URL:    subscription.Endpoint,
+		Body:   bytes.NewReader(body),
+		Method: http.MethodPost,
+		Headers: map[string]string{
+			"Authorization":    authorization,
+			"Content-Encoding": "aes128gcm",
+			"Content-Type":     "application/octet-stream",
+			"TTL":              "86400",
+		},
+	}
+	if err := bus.Dispatch(ctx, req); err != nil {
+		return errors.Wrap(err, "failed to send push notification")
+	}
+
+	// Push services reply with 404 or 410 once a browser unsubscribes or its subscription expires
+	if req.ResponseStatusCode == http.StatusNotFound || req.ResponseStatusCode == http.StatusGone {
+		return bus.Dispatch(ctx, &cmd.DeletePushSubscription{Endpoint: subscription.Endpoint})
+	}
+
+	if req.ResponseStatusCode >= 300 {
+		return errors.New("push service replied with status code %d: %s", req.ResponseStatusCode, string(req.ResponseBody))
+	}
+
+	return nil
+}
+
+// subject is how push services can contact the operator of this instance, which falls back to the noreply address
+func subject() string {
+	if env.Config.WebPush.Subject != "" {
+		return env.Config.WebPush.Subject
+	}
+	return "mailto:" + env.Config.Email.NoReply
+}
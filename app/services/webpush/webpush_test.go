@@ -0,0 +1,79 @@
+package webpush_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/pkg/webpush"
+	webpushService "github.com/getfider/fider/app/services/webpush"
+)
+
+// Keys of a browser subscription, from RFC 8291 Section 5
+var browserKeys = webpush.Keys{
+	P256dh: "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+	Auth:   "BTBZMqHH6r4Tts7J_aSIgg",
+}
+
+func TestSendPushNotification(t *testing.T) {
+	RegisterT(t)
+
+	keys, err := webpush.GenerateVAPIDKeys()
+	Expect(err).IsNil()
+	env.Config.WebPush.VAPIDPublicKey = keys.PublicKey
+	env.Config.WebPush.VAPIDPrivateKey = keys.PrivateKey
+	env.Config.WebPush.Subject = "mailto:admin@fider.io"
+	bus.Init(webpushService.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPushSubscriptions) error {
+		if q.UserID == mock.JonSnow.ID {
+			q.Result = []*entity.PushSubscription{
+				{ID: 1, UserID: q.UserID, Endpoint: "https://fcm.googleapis.com/fcm/send/jon", P256dh: browserKeys.P256dh, Auth: browserKeys.Auth},
+				{ID: 2, UserID: q.UserID, Endpoint: "https://updates.push.services.mozilla.com/wpush/v2/expired", P256dh: browserKeys.P256dh, Auth: browserKeys.Auth},
+			}
+		}
+		return nil
+	})
+
+	requests := make([]*cmd.HTTPRequest, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.HTTPRequest) error {
+		requests = append(requests, c)
+		c.ResponseStatusCode = http.StatusCreated
+		if strings.HasSuffix(c.URL, "/expired") {
+			c.ResponseStatusCode = http.StatusGone
+		}
+		return nil
+	})
+
+	deleted := make([]string, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.DeletePushSubscription) error {
+		deleted = append(deleted, c.Endpoint)
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), app.TenantCtxKey, mock.DemoTenant)
+	bus.Publish(ctx, &cmd.SendPushNotification{
+		To:    []*entity.User{mock.JonSnow, mock.AryaStark},
+		Title: "New post: **Add dark mode**",
+		Link:  "/posts/1/add-dark-mode",
+	})
+
+	Expect(requests).HasLen(2)
+	Expect(requests[0].URL).Equals("https://fcm.googleapis.com/fcm/send/jon")
+	Expect(requests[0].Method).Equals("POST")
+	Expect(requests[0].Headers["Content-Encoding"]).Equals("aes128gcm")
+	Expect(requests[0].Headers["TTL"]).Equals("86400")
+	Expect(requests[0].Headers["Authorization"]).ContainsSubstring("vapid t=")
+	Expect(requests[0].Headers["Authorization"]).ContainsSubstring(", k=" + keys.PublicKey)
+
+	Expect(deleted).Equals([]string{"https://updates.push.services.mozilla.com/wpush/v2/expired"})
+}
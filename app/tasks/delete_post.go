@@ -9,6 +9,7 @@ import (
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/pkg/bus"
 	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/markdown"
 	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/pkg/webhook"
@@ -31,8 +32,9 @@ func NotifyAboutDeletedPost(post *entity.Post, deleteCommentAdded bool) worker.T
 		webhookProps.SetTenant(tenant, "tenant", baseURL, logoURL)
 
 		err := bus.Dispatch(c, &cmd.TriggerWebhooks{
-			Type:  enum.WebhookDeletePost,
-			Props: webhookProps,
+			Type:     enum.WebhookDeletePost,
+			Props:    webhookProps,
+			Internal: post.IsPrivate,
 		})
 		if err != nil {
 			return c.Failure(err)
@@ -72,7 +74,11 @@ func NotifyAboutDeletedPost(post *entity.Post, deleteCommentAdded bool) worker.T
 		to := make([]dto.Recipient, 0)
 		for _, user := range users {
 			if user.ID != author.ID {
-				to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+				recipient := dto.NewRecipient(user.Name, user.Email, dto.Props{})
+				to = append(to, withUnsubscribe(c, recipient, &jwt.UnsubscribeClaims{
+					UserID: user.ID,
+					Event:  enum.NotificationEventChangeStatus.UserSettingsKeyName,
+				}))
 			}
 		}
 
@@ -91,6 +97,24 @@ func NotifyAboutDeletedPost(post *entity.Post, deleteCommentAdded bool) worker.T
 			Props:        props,
 		})
 
+		// Digest notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelDigest, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if err = queueDigestItems(c, users, post, title, ""); err != nil {
+			return c.Failure(err)
+		}
+
+		// Push notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelPush, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		sendPushNotifications(c, users, title, "")
+
 		return nil
 	})
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/getfider/fider/app/models/dto"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/services/email/emailmock"
 	"github.com/getfider/fider/app/tasks"
@@ -32,6 +33,10 @@ func TestNotifyAboutDeletePostTask(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.AryaStark,
 		}
@@ -83,10 +88,15 @@ func TestNotifyAboutDeletePostTask(t *testing.T) {
 		Name: "Jon Snow",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, Event: enum.NotificationEventChangeStatus.UserSettingsKeyName})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Arya Stark",
-		Address: "arya.stark@got.com",
-		Props:   dto.Props{},
+		Name:           "Arya Stark",
+		Address:        "arya.stark@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -139,6 +149,10 @@ func TestNotifyAboutDeletePostTask_NoComment(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.AryaStark,
 		}
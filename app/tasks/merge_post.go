@@ -0,0 +1,118 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+// NotifyAboutMergedPost lets voters of a merged post know that their vote now counts toward the original
+func NotifyAboutMergedPost(post, original *entity.Post, merge *entity.PostMerge) worker.Task {
+	return describe("Notify about merged post", func(c *worker.Context) error {
+		if len(merge.VoterIDs) == 0 {
+			return nil
+		}
+
+		author := c.User()
+		isVoter := func(user *entity.User) bool {
+			return user.ID != author.ID && slices.Contains(merge.VoterIDs, user.ID)
+		}
+
+		// Web notification
+		users, err := getActiveSubscribers(c, original, enum.NotificationChannelWeb, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		title := fmt.Sprintf("Your vote on **%s** now counts toward **%s**", post.Title, original.Title)
+		link := fmt.Sprintf("/posts/%d/%s", original.Number, original.Slug)
+		for _, user := range users {
+			if isVoter(user) {
+				err = bus.Dispatch(c, &cmd.AddNewNotification{
+					User:   user,
+					Title:  title,
+					Link:   link,
+					PostID: original.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
+		// Email notification
+		users, err = getActiveSubscribers(c, original, enum.NotificationChannelEmail, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		to := make([]dto.Recipient, 0)
+		for _, user := range users {
+			if isVoter(user) {
+				to = append(to, newPostRecipient(c, user, original))
+			}
+		}
+
+		tenant := c.Tenant()
+		baseURL, logoURL := web.BaseURL(c), web.LogoURL(c)
+
+		props := dto.Props{
+			"title":         post.Title,
+			"originalTitle": original.Title,
+			"siteName":      tenant.Name,
+			"postLink":      linkWithText(fmt.Sprintf("#%d", post.Number), baseURL, "/posts/%d/%s", post.Number, post.Slug),
+			"original":      linkWithText(original.Title, baseURL, "/posts/%d/%s", original.Number, original.Slug),
+			"view":          linkWithText(i18n.T(c, "email.subscription.view"), baseURL, "/posts/%d/%s", original.Number, original.Slug),
+			"change":        linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
+			"logo":          logoURL,
+		}
+
+		bus.Publish(c, &cmd.SendMail{
+			From:         dto.Recipient{Name: author.Name},
+			To:           to,
+			TemplateName: "merge_post",
+			Props:        props,
+		})
+
+		// Digest notification
+		users, err = getActiveSubscribers(c, original, enum.NotificationChannelDigest, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		voters := make([]*entity.User, 0)
+		for _, user := range users {
+			if isVoter(user) {
+				voters = append(voters, user)
+			}
+		}
+
+		if err = queueDigestItems(c, voters, original, title, link); err != nil {
+			return c.Failure(err)
+		}
+
+		// Push notification
+		users, err = getActiveSubscribers(c, original, enum.NotificationChannelPush, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		voters = make([]*entity.User, 0)
+		for _, user := range users {
+			if isVoter(user) {
+				voters = append(voters, user)
+			}
+		}
+		sendPushNotifications(c, voters, title, link)
+
+		return nil
+	})
+}
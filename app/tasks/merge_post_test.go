@@ -0,0 +1,105 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+func TestNotifyAboutMergedPostTask(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	addNewNotifications := make([]*cmd.AddNewNotification, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		addNewNotifications = append(addNewNotifications, c)
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
+		q.Result = []*entity.User{
+			mock.JonSnow,
+			mock.AryaStark,
+		}
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{ID: 2, Number: 2, Title: "I need TypeScript", Slug: "i-need-typescript", Status: enum.PostDuplicate}
+	original := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript", Status: enum.PostOpen}
+	merge := &entity.PostMerge{ID: 1, PostID: post.ID, OriginalID: original.ID, VoterIDs: []int{mock.AryaStark.ID}}
+
+	task := tasks.NotifyAboutMergedPost(post, original, merge)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("merge_post")
+	Expect(emailmock.MessageHistory[0].Tenant).Equals(mock.DemoTenant)
+	Expect(emailmock.MessageHistory[0].Props).Equals(dto.Props{
+		"title":         "I need TypeScript",
+		"originalTitle": "Add support for TypeScript",
+		"siteName":      "Demonstration",
+		"postLink":      "<a href='http://domain.com/posts/2/i-need-typescript'>#2</a>",
+		"original":      "<a href='http://domain.com/posts/1/add-support-for-typescript'>Add support for TypeScript</a>",
+		"view":          "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
+		"change":        "<a href='http://domain.com/settings'>change your notification preferences</a>",
+		"logo":          "https://fider.io/images/logo-100x100.png",
+	})
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, PostID: original.ID})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
+	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
+		Name:           mock.AryaStark.Name,
+		Address:        mock.AryaStark.Email,
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
+	})
+
+	Expect(addNewNotifications).HasLen(1)
+	Expect(addNewNotifications[0].PostID).Equals(original.ID)
+	Expect(addNewNotifications[0].Link).Equals("/posts/1/add-support-for-typescript")
+	Expect(addNewNotifications[0].User).Equals(mock.AryaStark)
+	Expect(addNewNotifications[0].Title).Equals("Your vote on **I need TypeScript** now counts toward **Add support for TypeScript**")
+}
+
+func TestNotifyAboutMergedPostTask_WithoutVoters(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{ID: 2, Number: 2, Title: "I need TypeScript", Slug: "i-need-typescript"}
+	original := &entity.Post{ID: 1, Number: 1, Title: "Add support for TypeScript", Slug: "add-support-for-typescript"}
+
+	task := tasks.NotifyAboutMergedPost(post, original, &entity.PostMerge{})
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
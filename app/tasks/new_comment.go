@@ -28,7 +28,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		var mentionNotifications []*entity.MentionNotification
 
 		// Web notification
-		users, err := getActiveSubscribers(c, post, enum.NotificationChannelWeb, enum.NotificationEventNewComment)
+		users, err := getCommentSubscribers(c, post, comment, enum.NotificationChannelWeb, enum.NotificationEventNewComment)
 		if err != nil {
 			return c.Failure(err)
 		}
@@ -55,7 +55,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 
 		if mentions != nil {
 
-			users, err = getActiveSubscribers(c, post, enum.NotificationChannelWeb, enum.NotificationEventMention)
+			users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelWeb, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
@@ -106,7 +106,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		}
 
 		// Standard email notitifications
-		users, err = getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventNewComment)
+		users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelEmail, enum.NotificationEventNewComment)
 		if err != nil {
 			return c.Failure(err)
 		}
@@ -114,7 +114,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		to := make([]dto.Recipient, 0)
 		for _, user := range users {
 			if user.ID != author.ID {
-				to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+				to = append(to, newPostRecipient(c, user, post))
 			}
 		}
 
@@ -124,7 +124,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		to = make([]dto.Recipient, 0)
 		if mentions != nil {
 
-			users, err = getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventMention)
+			users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelEmail, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
@@ -136,7 +136,7 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						to = append(to, newEventRecipient(c, u, post, enum.NotificationEventMention))
 
 						// Also send the notification log
 						err = bus.Dispatch(c, &cmd.AddMentionNotification{
@@ -154,6 +154,68 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		// Digest notification
+		users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelDigest, enum.NotificationEventNewComment)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if err = queueDigestItems(c, users, post, fmt.Sprintf("**%s** left a comment on **%s**", author.Name, post.Title), link); err != nil {
+			return c.Failure(err)
+		}
+
+		// Digest notification - mentions
+		if mentions != nil {
+			users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelDigest, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			if err = queueDigestItems(c, users, post, fmt.Sprintf("**%s** mentioned you in **%s**", author.Name, post.Title), link); err != nil {
+				return c.Failure(err)
+			}
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID:    u.ID,
+					CommentID: comment.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
+		// Push notification
+		users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelPush, enum.NotificationEventNewComment)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		sendPushNotifications(c, users, fmt.Sprintf("**%s** left a comment on **%s**", author.Name, post.Title), link)
+
+		// Push notification - mentions
+		if mentions != nil {
+			users, err = getCommentSubscribers(c, post, comment, enum.NotificationChannelPush, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			sendPushNotifications(c, users, fmt.Sprintf("**%s** mentioned you in **%s**", author.Name, post.Title), link)
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID:    u.ID,
+					CommentID: comment.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
 		tenant := c.Tenant()
 		baseURL, logoURL := web.BaseURL(c), web.LogoURL(c)
 
@@ -164,8 +226,9 @@ func NotifyAboutNewComment(comment *entity.Comment, post *entity.Post) worker.Ta
 		webhookProps.SetTenant(tenant, "tenant", baseURL, logoURL)
 
 		err = bus.Dispatch(c, &cmd.TriggerWebhooks{
-			Type:  enum.WebhookNewComment,
-			Props: webhookProps,
+			Type:     enum.WebhookNewComment,
+			Props:    webhookProps,
+			Internal: post.IsPrivate || comment.IsInternal,
 		})
 		if err != nil {
 			return c.Failure(err)
@@ -193,7 +256,7 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 		mentionNotificationSent := false
 		if mentions != nil {
 
-			users, err := getActiveSubscribers(c, post, enum.NotificationChannelWeb, enum.NotificationEventMention)
+			users, err := getCommentSubscribers(c, post, comment, enum.NotificationChannelWeb, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
@@ -244,7 +307,7 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 		to := make([]dto.Recipient, 0)
 		if mentions != nil {
 
-			users, err := getActiveSubscribers(c, post, enum.NotificationChannelEmail, enum.NotificationEventMention)
+			users, err := getCommentSubscribers(c, post, comment, enum.NotificationChannelEmail, enum.NotificationEventMention)
 			if err != nil {
 				return c.Failure(err)
 			}
@@ -256,7 +319,7 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						to = append(to, newEventRecipient(c, u, post, enum.NotificationEventMention))
 
 						// Also send the notification log
 						if !mentionNotificationSent {
@@ -275,6 +338,50 @@ func NotifyAboutUpdatedComment(post *entity.Post, comment *entity.Comment) worke
 
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		// Digest notification - mentions
+		if mentions != nil {
+			users, err := getCommentSubscribers(c, post, comment, enum.NotificationChannelDigest, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			if err = queueDigestItems(c, users, post, title, link); err != nil {
+				return c.Failure(err)
+			}
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID:    u.ID,
+					CommentID: comment.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
+		// Push notification - mentions
+		if mentions != nil {
+			users, err := getCommentSubscribers(c, post, comment, enum.NotificationChannelPush, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			sendPushNotifications(c, users, title, link)
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID:    u.ID,
+					CommentID: comment.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
 		return nil
 	})
 }
@@ -301,7 +408,6 @@ func sendEmailNotifications(c *worker.Context, post *entity.Post, to []dto.Recip
 		"content":             markdown.Full(comment, false),
 		"postLink":            linkWithText(fmt.Sprintf("#%d", post.Number), baseURL, "/posts/%d/%s", post.Number, post.Slug),
 		"view":                linkWithText(i18n.T(c, "email.subscription.view"), baseURL, "/posts/%d/%s", post.Number, post.Slug),
-		"unsubscribe":         linkWithText(i18n.T(c, "email.subscription.unsubscribe"), baseURL, "/posts/%d/%s", post.Number, post.Slug),
 		"change":              linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
 		"logo":                logoURL,
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/getfider/fider/app/models/dto"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/services/email/emailmock"
 	"github.com/getfider/fider/app/tasks"
@@ -43,6 +44,10 @@ func TestNotifyAboutNewCommentTask(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		if q.Event.UserSettingsKeyName == "event_notification_new_comment" {
 			q.Result = []*entity.User{
 				mock.JonSnow,
@@ -89,17 +94,21 @@ func TestNotifyAboutNewCommentTask(t *testing.T) {
 		"content":             template.HTML("<p>I agree</p>"),
 		"view":                "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
 		"change":              "<a href='http://domain.com/settings'>change your notification preferences</a>",
-		"unsubscribe":         "<a href='http://domain.com/posts/1/add-support-for-typescript'>unsubscribe from it</a>",
 		"logo":                "https://fider.io/images/logo-100x100.png",
 	})
 	Expect(emailmock.MessageHistory[0].From).Equals(dto.Recipient{
 		Name: "Arya Stark",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.JonSnow.ID, PostID: post.ID})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Jon Snow",
-		Address: "jon.snow@got.com",
-		Props:   dto.Props{},
+		Name:           "Jon Snow",
+		Address:        "jon.snow@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -136,6 +145,62 @@ func TestNotifyAboutNewCommentTask(t *testing.T) {
 	Expect(addNotificationLogs).HasLen(0)
 }
 
+func TestNotifyAboutNewCommentTask_InternalComment(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	var addNewNotification *cmd.AddNewNotification
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		addNewNotification = c
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetMentionNotifications) error {
+		q.Result = []*entity.MentionNotification{}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
+		q.Result = []*entity.User{
+			mock.AryaStark,
+		}
+		return nil
+	})
+
+	var triggerWebhooks *cmd.TriggerWebhooks
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		triggerWebhooks = c
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.AryaStark,
+	}
+	task := tasks.NotifyAboutNewComment(&entity.Comment{Content: "We already have this on our backlog", IsInternal: true}, post)
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(task)
+
+	Expect(err).IsNil()
+	Expect(emailmock.MessageHistory).HasLen(0)
+	Expect(addNewNotification).IsNil()
+
+	Expect(triggerWebhooks).IsNotNil()
+	Expect(triggerWebhooks.Internal).IsTrue()
+}
+
 func TestNotifyAboutNewCommentTask_WithMention(t *testing.T) {
 	RegisterT(t)
 	bus.Init(emailmock.Service{})
@@ -153,6 +218,10 @@ func TestNotifyAboutNewCommentTask_WithMention(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		if q.Event.UserSettingsKeyName == "event_notification_mention" {
 			q.Result = []*entity.User{
 				mock.JonSnow,
@@ -204,17 +273,21 @@ func TestNotifyAboutNewCommentTask_WithMention(t *testing.T) {
 		"content":             template.HTML("<p>I agree with @Jon Snow</p>"),
 		"view":                "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
 		"change":              "<a href='http://domain.com/settings'>change your notification preferences</a>",
-		"unsubscribe":         "<a href='http://domain.com/posts/1/add-support-for-typescript'>unsubscribe from it</a>",
 		"logo":                "https://fider.io/images/logo-100x100.png",
 	})
 	Expect(emailmock.MessageHistory[0].From).Equals(dto.Recipient{
 		Name: "Arya Stark",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.JonSnow.ID, Event: enum.NotificationEventMention.UserSettingsKeyName})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Jon Snow",
-		Address: "jon.snow@got.com",
-		Props:   dto.Props{},
+		Name:           "Jon Snow",
+		Address:        "jon.snow@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -279,6 +352,10 @@ func TestNotifyAboutUpdatedComment(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.JonSnow,
 		}
@@ -323,17 +400,21 @@ func TestNotifyAboutUpdatedComment(t *testing.T) {
 		"content":             template.HTML("<p>I agree with @Jon Snow but not @Arya Stark</p>"),
 		"view":                "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
 		"change":              "<a href='http://domain.com/settings'>change your notification preferences</a>",
-		"unsubscribe":         "<a href='http://domain.com/posts/1/add-support-for-typescript'>unsubscribe from it</a>",
 		"logo":                "https://fider.io/images/logo-100x100.png",
 	})
 	Expect(emailmock.MessageHistory[0].From).Equals(dto.Recipient{
 		Name: "Arya Stark",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.JonSnow.ID, Event: enum.NotificationEventMention.UserSettingsKeyName})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Jon Snow",
-		Address: "jon.snow@got.com",
-		Props:   dto.Props{},
+		Name:           "Jon Snow",
+		Address:        "jon.snow@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -370,6 +451,10 @@ func TestNotifyAboutUpdatedComment_UserAlreadyMentioned(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.JonSnow,
 		}
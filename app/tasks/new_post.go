@@ -108,7 +108,7 @@ func NotifyAboutNewPost(post *entity.Post) worker.Task {
 		to := make([]dto.Recipient, 0)
 		for _, user := range users {
 			if user.ID != author.ID {
-				to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+				to = append(to, newEventRecipient(c, user, post, enum.NotificationEventNewPost))
 			}
 		}
 
@@ -147,7 +147,7 @@ func NotifyAboutNewPost(post *entity.Post) worker.Task {
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						to = append(to, newEventRecipient(c, u, post, enum.NotificationEventMention))
 
 						// Also send the notification log
 						err = bus.Dispatch(c, &cmd.AddMentionNotification{
@@ -165,14 +165,77 @@ func NotifyAboutNewPost(post *entity.Post) worker.Task {
 		// Send mention email notifications
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		// Digest notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelDigest, enum.NotificationEventNewPost)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if err = queueDigestItems(c, users, post, fmt.Sprintf("New post: **%s**", post.Title), link); err != nil {
+			return c.Failure(err)
+		}
+
+		// Digest notification - mentions
+		if len(mentions) > 0 {
+			users, err = getActiveSubscribers(c, post, enum.NotificationChannelDigest, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			if err = queueDigestItems(c, users, post, fmt.Sprintf("**%s** mentioned you in **%s**", author.Name, post.Title), link); err != nil {
+				return c.Failure(err)
+			}
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID: u.ID,
+					PostID: post.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
+		// Push notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelPush, enum.NotificationEventNewPost)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		sendPushNotifications(c, users, fmt.Sprintf("New post: **%s**", post.Title), link)
+
+		// Push notification - mentions
+		if len(mentions) > 0 {
+			users, err = getActiveSubscribers(c, post, enum.NotificationChannelPush, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			sendPushNotifications(c, users, fmt.Sprintf("**%s** mentioned you in **%s**", author.Name, post.Title), link)
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID: u.ID,
+					PostID: post.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
 		webhookProps := webhook.Props{}
 		webhookProps.SetPost(post, "post", baseURL, false, false)
 		webhookProps.SetUser(author, "author")
 		webhookProps.SetTenant(tenant, "tenant", baseURL, logoURL)
 
 		err = bus.Dispatch(c, &cmd.TriggerWebhooks{
-			Type:  enum.WebhookNewPost,
-			Props: webhookProps,
+			Type:     enum.WebhookNewPost,
+			Props:    webhookProps,
+			Internal: post.IsPrivate,
 		})
 		if err != nil {
 			return c.Failure(err)
@@ -260,7 +323,7 @@ func NotifyAboutUpdatedPost(post *entity.Post) worker.Task {
 						func(n *entity.MentionNotification) bool {
 							return n.UserID == u.ID
 						}) {
-						to = append(to, dto.NewRecipient(u.Name, u.Email, dto.Props{}))
+						to = append(to, newEventRecipient(c, u, post, enum.NotificationEventMention))
 
 						// Also send the notification log
 						if !mentionNotificationSent {
@@ -280,6 +343,50 @@ func NotifyAboutUpdatedPost(post *entity.Post) worker.Task {
 		// Send email notifications for mentions
 		sendEmailNotifications(c, post, to, contentString.SanitizeMentions(), enum.NotificationEventMention, "new_comment")
 
+		// Digest notification - mentions
+		if len(mentions) > 0 {
+			users, err := getActiveSubscribers(c, post, enum.NotificationChannelDigest, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			if err = queueDigestItems(c, users, post, title, link); err != nil {
+				return c.Failure(err)
+			}
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID: u.ID,
+					PostID: post.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
+		// Push notification - mentions
+		if len(mentions) > 0 {
+			users, err := getActiveSubscribers(c, post, enum.NotificationChannelPush, enum.NotificationEventMention)
+			if err != nil {
+				return c.Failure(err)
+			}
+
+			users = mentionedUsers(users, mentions, mentionNotifications)
+			sendPushNotifications(c, users, title, link)
+
+			for _, u := range users {
+				err = bus.Dispatch(c, &cmd.AddMentionNotification{
+					UserID: u.ID,
+					PostID: post.ID,
+				})
+				if err != nil {
+					return c.Failure(err)
+				}
+			}
+		}
+
 		return nil
 	})
 }
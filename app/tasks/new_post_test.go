@@ -15,6 +15,7 @@ import (
 	"github.com/getfider/fider/app/models/dto"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/services/email/emailmock"
 	"github.com/getfider/fider/app/tasks"
@@ -31,6 +32,10 @@ func TestNotifyAboutNewPostTask(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.AryaStark,
 		}
@@ -77,10 +82,15 @@ func TestNotifyAboutNewPostTask(t *testing.T) {
 		Name: "Jon Snow",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, Event: enum.NotificationEventNewPost.UserSettingsKeyName})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Arya Stark",
-		Address: "arya.stark@got.com",
-		Props:   dto.Props{},
+		Name:           "Arya Stark",
+		Address:        "arya.stark@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -127,6 +137,10 @@ func TestNotifyAboutNewPostTask_WithMention(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		if q.Event.UserSettingsKeyName == "event_notification_mention" {
 			q.Result = []*entity.User{
 				mock.JonSnow,
@@ -192,3 +206,49 @@ func TestNotifyAboutNewPostTask_WithMention(t *testing.T) {
 	Expect(triggerWebhooks).IsNotNil()
 	Expect(triggerWebhooks.Type).Equals(enum.WebhookNewPost)
 }
+
+func TestNotifyAboutNewPostTask_Push(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewNotification) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		q.Result = []*entity.User{}
+		if q.Channel == enum.NotificationChannelPush && q.Event.UserSettingsKeyName == enum.NotificationEventNewPost.UserSettingsKeyName {
+			q.Result = []*entity.User{mock.JonSnow, mock.AryaStark}
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	pushNotifications := make([]*cmd.SendPushNotification, 0)
+	bus.AddListener(func(ctx context.Context, c *cmd.SendPushNotification) {
+		pushNotifications = append(pushNotifications, c)
+	})
+
+	post := &entity.Post{
+		ID:          1,
+		Number:      1,
+		Title:       "Add support for TypeScript",
+		Slug:        "add-support-for-typescript",
+		Description: "TypeScript is great, please add support for it",
+	}
+
+	err := mock.NewWorker().
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutNewPost(post))
+
+	Expect(err).IsNil()
+	Expect(pushNotifications).HasLen(1)
+	Expect(pushNotifications[0].To).Equals([]*entity.User{mock.AryaStark})
+	Expect(pushNotifications[0].Title).Equals("New post: **Add support for TypeScript**")
+	Expect(pushNotifications[0].Link).Equals("/posts/1/add-support-for-typescript")
+}
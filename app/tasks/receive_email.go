@@ -0,0 +1,397 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/actions"
+	"github.com/getfider/fider/app/metrics"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/errors"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
+	"github.com/getfider/fider/app/pkg/worker"
+)
+
+const (
+	maxEmailPostTitleLength  = 100
+	maxEmailPostImages       = 3
+	pendingEmailPostDuration = 48 * time.Hour
+)
+
+var subjectPrefixRegex = regexp.MustCompile(`(?i)^((re|fw|fwd|aw|wg|tr)\s*:\s*)+`)
+
+// ReceiveEmail processes an email sent to the inbound domain, which is either a bounce report, a reply to a notification or a new post
+func ReceiveEmail(msg *inbound.Message) worker.Task {
+	if len(msg.Bounces) > 0 || len(msg.Complaints) > 0 {
+		return receiveEmailReport(msg)
+	}
+
+	for _, to := range msg.To {
+		if reply := inbound.ParseReplyAddress(to); reply != nil {
+			return receiveEmailReply(msg, reply)
+		}
+		if subdomain := inbound.ParsePostAddress(to); subdomain != "" {
+			return receiveEmailPost(msg, subdomain)
+		}
+	}
+
+	return describe("Receive email", func(c *worker.Context) error {
+		return ignoreEmail(c, msg, "no valid recipient address")
+	})
+}
+
+func ignoreEmail(c *worker.Context, msg *inbound.Message, reason string) error {
+	log.Warnf(c, "Ignoring email from '@{From}': @{Reason}", dto.Props{
+		"From":   msg.From,
+		"Reason": reason,
+	})
+	return nil
+}
+
+// receiveEmailReport supresses the addresses that bounced or complained about emails sent by Fider
+func receiveEmailReport(msg *inbound.Message) worker.Task {
+	return describe("Receive email report", func(c *worker.Context) error {
+		// Reports are only trusted when sent to the bounce address of the reported recipient
+		verified := func(recipients []string) []string {
+			result := make([]string, 0, len(recipients))
+			for _, recipient := range recipients {
+				for _, to := range msg.To {
+					if inbound.IsBounceAddressFor(to, recipient) {
+						result = append(result, recipient)
+						break
+					}
+				}
+			}
+			return result
+		}
+
+		supressions := []*cmd.SupressEmail{
+			{EmailAddresses: verified(msg.Bounces), Reason: enum.EmailSupressionHardBounce},
+			{EmailAddresses: verified(msg.Complaints), Reason: enum.EmailSupressionComplaint},
+		}
+
+		count := 0
+		for _, supression := range supressions {
+			if len(supression.EmailAddresses) == 0 {
+				continue
+			}
+			if err := bus.Dispatch(c, supression); err != nil {
+				return c.Failure(err)
+			}
+			count += len(supression.EmailAddresses)
+		}
+
+		if count == 0 {
+			return ignoreEmail(c, msg, "report doesn't match the bounce address")
+		}
+		return nil
+	})
+}
+
+// receiveEmailReply adds a reply to a notification email as a comment on the post it was sent for
+func receiveEmailReply(msg *inbound.Message, reply *inbound.Reply) worker.Task {
+	return describe("Receive email reply", func(c *worker.Context) error {
+		getTenant := &query.GetTenantByID{TenantID: reply.TenantID}
+		if err := bus.Dispatch(c, getTenant); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return ignoreEmail(c, msg, "tenant not found")
+			}
+			return c.Failure(err)
+		}
+		if getTenant.Result.Status != enum.TenantActive {
+			return ignoreEmail(c, msg, "tenant is not active")
+		}
+
+		ctx, err := web.WithTenantRequest(c.Context, getTenant.Result)
+		if err != nil {
+			return c.Failure(err)
+		}
+		c.Context = ctx
+
+		getUser := &query.GetUserByID{UserID: reply.UserID}
+		if err := bus.Dispatch(c, getUser); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return ignoreEmail(c, msg, "user not found")
+			}
+			return c.Failure(err)
+		}
+
+		// The reply address might have been forwarded, so it's only valid when replying from the user's own mailbox
+		// The From header is easily forged, so the mail server must also have authenticated it
+		user := getUser.Result
+		if user.Status != enum.UserActive || !strings.EqualFold(user.Email, msg.From) {
+			return ignoreEmail(c, msg, "sender doesn't match the recipient of the notification")
+		}
+		if !msg.SenderAuthenticated {
+			return ignoreEmail(c, msg, "sender is not authenticated")
+		}
+		c.Set(app.UserCtxKey, user)
+
+		getPost := &query.GetPostByID{PostID: reply.PostID}
+		if err := bus.Dispatch(c, getPost); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return ignoreEmail(c, msg, "post not found")
+			}
+			return c.Failure(err)
+		}
+
+		post := getPost.Result
+		if post.Status == enum.PostDeleted || !post.IsVisibleTo(user) {
+			return ignoreEmail(c, msg, "post is not available to the user")
+		}
+
+		content := inbound.StripReply(msg.Text)
+		if content == "" {
+			return ignoreEmail(c, msg, "reply is empty")
+		}
+
+		addNewComment := &cmd.AddNewComment{
+			Post:    post,
+			Content: content,
+		}
+		if err := bus.Dispatch(c, addNewComment); err != nil {
+			return c.Failure(err)
+		}
+
+		return NotifyAboutNewComment(addNewComment.Result, post).Job(c)
+	})
+}
+
+// receiveEmailPost creates a new post from an email sent to the inbox address of a tenant
+func receiveEmailPost(msg *inbound.Message, subdomain string) worker.Task {
+	return describe("Receive email post", func(c *worker.Context) error {
+		getTenant := &query.GetTenantByDomain{Domain: subdomain}
+		if err := bus.Dispatch(c, getTenant); err != nil {
+			if errors.Cause(err) == app.ErrNotFound {
+				return ignoreEmail(c, msg, "tenant not found")
+			}
+			return c.Failure(err)
+		}
+
+		tenant := getTenant.Result
+		if tenant.Status != enum.TenantActive {
+			return ignoreEmail(c, msg, "tenant is not active")
+		}
+
+		ctx, err := web.WithTenantRequest(c.Context, tenant)
+		if err != nil {
+			return c.Failure(err)
+		}
+		c.Context = ctx
+
+		if !msg.SenderAuthenticated {
+			return holdEmailPost(c, tenant, msg)
+		}
+
+		user, err := getOrRegisterEmailSender(c, tenant, msg)
+		if err != nil {
+			return c.Failure(err)
+		}
+		if user == nil {
+			return ignoreEmail(c, msg, "sender is not allowed to submit posts")
+		}
+		c.Set(app.UserCtxKey, user)
+
+		images := msg.Images()
+		if len(images) > maxEmailPostImages {
+			images = images[:maxEmailPostImages]
+		}
+
+		attachments := make([]*dto.ImageUpload, 0, len(images))
+		for _, image := range images {
+			attachments = append(attachments, &dto.ImageUpload{
+				Upload: &dto.ImageUploadData{
+					FileName:    image.FileName,
+					ContentType: image.ContentType,
+					Content:     image.Content,
+				},
+			})
+		}
+
+		action := &actions.CreateNewPost{
+			Title:       emailPostTitle(msg.Subject),
+			Description: strings.TrimSpace(msg.Text),
+			Attachments: attachments,
+		}
+
+		result := action.Validate(c, user)
+		if result.Err != nil {
+			return c.Failure(result.Err)
+		}
+		if !result.Ok {
+			reasons := make([]string, 0, len(result.Errors))
+			for _, item := range result.Errors {
+				reasons = append(reasons, item.Message)
+			}
+
+			bus.Publish(c, &cmd.SendMail{
+				From:         dto.Recipient{Name: tenant.Name},
+				To:           []dto.Recipient{dto.NewRecipient(user.Name, user.Email, dto.Props{})},
+				TemplateName: "post_rejected",
+				Props: dto.Props{
+					"title":    msg.Subject,
+					"siteName": tenant.Name,
+					"reasons":  reasons,
+					"logo":     web.LogoURL(c),
+				},
+			})
+			return nil
+		}
+
+		if err := bus.Dispatch(c, &cmd.UploadImages{Images: action.Attachments, Folder: "attachments"}); err != nil {
+			return c.Failure(err)
+		}
+
+		newPost := &cmd.AddNewPost{
+			Title:       action.Title,
+			Description: action.Description,
+		}
+		if err := bus.Dispatch(c, newPost); err != nil {
+			return c.Failure(err)
+		}
+
+		post := newPost.Result
+		commands := []bus.Msg{
+			&cmd.SetAttachments{Post: post, Attachments: action.Attachments},
+		}
+		if tenant.VotingMode != enum.VotingModeBudget {
+			commands = append(commands, &cmd.AddVote{Post: post, User: user})
+		}
+		if err := bus.Dispatch(c, commands...); err != nil {
+			return c.Failure(err)
+		}
+
+		metrics.TotalPosts.Inc()
+
+		baseURL := web.BaseURL(c)
+		bus.Publish(c, &cmd.SendMail{
+			From:         dto.Recipient{Name: tenant.Name},
+			To:           []dto.Recipient{newPostRecipient(c, user, post)},
+			TemplateName: "post_received",
+			Props: dto.Props{
+				"title":    post.Title,
+				"siteName": tenant.Name,
+				"postLink": linkWithText(fmt.Sprintf("#%d", post.Number), baseURL, "/posts/%d/%s", post.Number, post.Slug),
+				"change":   linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
+				"logo":     web.LogoURL(c),
+			},
+		})
+
+		return NotifyAboutNewPost(post).Job(c)
+	})
+}
+
+// holdEmailPost keeps a post sent from an address that the provider couldn't authenticate until the sender confirms it,
+// as anyone can put someone else's address in the From header
+func holdEmailPost(c *worker.Context, tenant *entity.Tenant, msg *inbound.Message) error {
+	user, allowed, err := findEmailSender(c, tenant, msg)
+	if err != nil {
+		return c.Failure(err)
+	}
+	if !allowed {
+		return ignoreEmail(c, msg, "sender is not allowed to submit posts")
+	}
+
+	content, err := json.Marshal(msg)
+	if err != nil {
+		return c.Failure(errors.Wrap(err, "failed to encode email post"))
+	}
+
+	key := entity.GenerateEmailVerificationKey()
+	confirmation := &actions.ConfirmEmailPost{Email: msg.From, Name: msg.FromName}
+	if user != nil {
+		confirmation.Name = user.Name
+	}
+
+	err = bus.Dispatch(c,
+		&cmd.StoreBlob{Key: inbound.PendingPostBlobKey(key), Content: content, ContentType: "application/json"},
+		&cmd.SaveVerificationKey{Key: key, Duration: pendingEmailPostDuration, Request: confirmation},
+	)
+	if err != nil {
+		return c.Failure(err)
+	}
+
+	bus.Publish(c, &cmd.SendMail{
+		From:         dto.Recipient{Name: tenant.Name},
+		To:           []dto.Recipient{dto.NewRecipient(confirmation.Name, msg.From, dto.Props{})},
+		TemplateName: "post_confirm",
+		Props: dto.Props{
+			"title":    msg.Subject,
+			"siteName": tenant.Name,
+			"link":     linkWithText(i18n.T(c, "email.post_confirm.link"), web.BaseURL(c), "/inbound/confirm?k=%s", key),
+			"logo":     web.LogoURL(c),
+		},
+	})
+	return nil
+}
+
+// findEmailSender returns the existing user that sent given email, if any, and whether the sender is allowed to submit posts
+func findEmailSender(c *worker.Context, tenant *entity.Tenant, msg *inbound.Message) (*entity.User, bool, error) {
+	if msg.From == "" {
+		return nil, false, nil
+	}
+
+	getUser := &query.GetUserByEmail{Email: msg.From}
+	err := bus.Dispatch(c, getUser)
+	if err == nil {
+		return getUser.Result, getUser.Result.Status == enum.UserActive, nil
+	}
+	if errors.Cause(err) != app.ErrNotFound {
+		return nil, false, err
+	}
+
+	// Private sites only accept posts from their existing members
+	return nil, !tenant.IsPrivate, nil
+}
+
+// getOrRegisterEmailSender returns the user that sent given email, registering a new visitor when needed
+// A nil user is returned when the sender isn't allowed to submit posts
+func getOrRegisterEmailSender(c *worker.Context, tenant *entity.Tenant, msg *inbound.Message) (*entity.User, error) {
+	user, allowed, err := findEmailSender(c, tenant, msg)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	name := strings.TrimSpace(msg.FromName)
+	if name == "" {
+		name, _, _ = strings.Cut(msg.From, "@")
+	}
+
+	user = &entity.User{
+		Name:   name,
+		Email:  msg.From,
+		Tenant: tenant,
+		Role:   enum.RoleVisitor,
+	}
+	if err := bus.Dispatch(c, &cmd.RegisterUser{User: user}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// emailPostTitle removes reply and forward prefixes from given subject and makes it fit in a post title
+func emailPostTitle(subject string) string {
+	title := strings.Join(strings.Fields(subject), " ")
+	title = subjectPrefixRegex.ReplaceAllString(title, "")
+	for len(title) > maxEmailPostTitleLength {
+		_, size := utf8.DecodeLastRuneInString(title)
+		title = title[:len(title)-size]
+	}
+	return strings.TrimSpace(title)
+}
@@ -0,0 +1,383 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getfider/fider/app"
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/entity"
+	"github.com/getfider/fider/app/models/enum"
+	"github.com/getfider/fider/app/models/query"
+	. "github.com/getfider/fider/app/pkg/assert"
+	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/env"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/mock"
+	"github.com/getfider/fider/app/services/email/emailmock"
+	"github.com/getfider/fider/app/tasks"
+)
+
+var replyPost = &entity.Post{
+	ID:     1,
+	Number: 1,
+	Title:  "Add support for TypeScript",
+	Slug:   "add-support-for-typescript",
+	User:   mock.AryaStark,
+	Status: enum.PostOpen,
+}
+
+func setupEmailReply(t *testing.T) *[]*cmd.AddNewComment {
+	env.Config.Email.Inbound.Domain = "reply.test.fider.io"
+	t.Cleanup(func() {
+		env.Config.Email.Inbound.Domain = ""
+	})
+
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByID) error {
+		if q.TenantID == mock.DemoTenant.ID {
+			q.Result = mock.DemoTenant
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByID) error {
+		if q.UserID == mock.JonSnow.ID {
+			q.Result = mock.JonSnow
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostByID) error {
+		if q.PostID == replyPost.ID {
+			q.Result = replyPost
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	comments := make([]*cmd.AddNewComment, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewComment) error {
+		comments = append(comments, c)
+		c.Result = &entity.Comment{
+			ID:        1,
+			Content:   c.Content,
+			User:      ctx.Value(app.UserCtxKey).(*entity.User),
+			CreatedAt: time.Now(),
+		}
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		q.Result = []*entity.User{}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetMentionNotifications) error {
+		q.Result = []*entity.MentionNotification{}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	return &comments
+}
+
+func TestReceiveEmailReplyTask(t *testing.T) {
+	RegisterT(t)
+	comments := setupEmailReply(t)
+
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:                "Jon.Snow@got.com",
+		To:                  []string{"someone@got.com", inbound.ReplyAddress(mock.DemoTenant.ID, mock.JonSnow.ID, replyPost.ID)},
+		Text:                "Sounds good to me!\n\nOn Mon, Jan 1, 2024 at 10:00 AM Fider <noreply@fider.io> wrote:\n> Arya Stark left a comment",
+		SenderAuthenticated: true,
+	})
+
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(*comments).HasLen(1)
+	Expect((*comments)[0].Post).Equals(replyPost)
+	Expect((*comments)[0].Content).Equals("Sounds good to me!")
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
+
+func TestReceiveEmailReplyTask_Ignored(t *testing.T) {
+	RegisterT(t)
+	comments := setupEmailReply(t)
+
+	replyAddress := inbound.ReplyAddress(mock.DemoTenant.ID, mock.JonSnow.ID, replyPost.ID)
+	messages := []*inbound.Message{
+		// forged reply address
+		{From: "jon.snow@got.com", To: []string{"reply+1.1.1.0000000000000000@reply.test.fider.io"}, Text: "Hello", SenderAuthenticated: true},
+		// sent by someone else
+		{From: "arya.stark@got.com", To: []string{replyAddress}, Text: "Hello", SenderAuthenticated: true},
+		// spoofed sender, which the mail server didn't authenticate
+		{From: "jon.snow@got.com", To: []string{replyAddress}, Text: "Hello"},
+		// unknown post
+		{From: "jon.snow@got.com", To: []string{inbound.ReplyAddress(mock.DemoTenant.ID, mock.JonSnow.ID, 999)}, Text: "Hello", SenderAuthenticated: true},
+		// nothing but the quoted message
+		{From: "jon.snow@got.com", To: []string{replyAddress}, Text: "> Arya Stark left a comment", SenderAuthenticated: true},
+	}
+
+	for _, msg := range messages {
+		err := mock.NewWorker().Execute(tasks.ReceiveEmail(msg))
+		Expect(err).IsNil()
+		Expect(*comments).HasLen(0)
+	}
+}
+
+func setupEmailPost(t *testing.T) (*[]*cmd.AddNewPost, *[]*entity.User) {
+	env.Config.Email.Inbound.Domain = "reply.test.fider.io"
+	t.Cleanup(func() {
+		env.Config.Email.Inbound.Domain = ""
+	})
+
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetTenantByDomain) error {
+		if q.Domain == mock.DemoTenant.Subdomain {
+			q.Result = mock.DemoTenant
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetUserByEmail) error {
+		if q.Email == mock.JonSnow.Email {
+			q.Result = mock.JonSnow
+			return nil
+		}
+		return app.ErrNotFound
+	})
+
+	registered := make([]*entity.User, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.RegisterUser) error {
+		c.User.ID = 3
+		c.User.Status = enum.UserActive
+		registered = append(registered, c.User)
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetPostBySlug) error {
+		return app.ErrNotFound
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.UploadImages) error {
+		return nil
+	})
+
+	posts := make([]*cmd.AddNewPost, 0)
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddNewPost) error {
+		posts = append(posts, c)
+		c.Result = &entity.Post{
+			ID:          10,
+			Number:      10,
+			Title:       c.Title,
+			Slug:        "dark-mode-for-the-mobile-app",
+			Description: c.Description,
+			User:        ctx.Value(app.UserCtxKey).(*entity.User),
+		}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.SetAttachments) error {
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddVote) error {
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		q.Result = []*entity.User{}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, q *query.GetMentionNotifications) error {
+		q.Result = []*entity.MentionNotification{}
+		return nil
+	})
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	return &posts, &registered
+}
+
+func TestReceiveEmailPostTask_ExistingUser(t *testing.T) {
+	RegisterT(t)
+	posts, registered := setupEmailPost(t)
+
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:                "jon.snow@got.com",
+		To:                  []string{"feedback+demo@reply.test.fider.io"},
+		Subject:             "Fwd: Dark mode for the mobile app",
+		Text:                "Our customers keep asking for it.",
+		SenderAuthenticated: true,
+	})
+
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(*registered).HasLen(0)
+	Expect(*posts).HasLen(1)
+	Expect((*posts)[0].Title).Equals("Dark mode for the mobile app")
+	Expect((*posts)[0].Description).Equals("Our customers keep asking for it.")
+
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("post_received")
+	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("jon.snow@got.com")
+	Expect(emailmock.MessageHistory[0].To[0].ReplyTo).Equals(inbound.ReplyAddress(mock.DemoTenant.ID, mock.JonSnow.ID, 10))
+	Expect(emailmock.MessageHistory[0].Props["postLink"]).Equals("<a href='https://demo.test.fider.io/posts/10/dark-mode-for-the-mobile-app'>#10</a>")
+}
+
+func TestReceiveEmailPostTask_NewUser(t *testing.T) {
+	RegisterT(t)
+	posts, registered := setupEmailPost(t)
+
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:                "sansa.stark@got.com",
+		FromName:            "Sansa Stark",
+		To:                  []string{"feedback+demo@reply.test.fider.io"},
+		Subject:             "Dark mode for the mobile app",
+		Text:                "Please!",
+		SenderAuthenticated: true,
+	})
+
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(*registered).HasLen(1)
+	Expect((*registered)[0].Name).Equals("Sansa Stark")
+	Expect((*registered)[0].Email).Equals("sansa.stark@got.com")
+	Expect((*registered)[0].Role).Equals(enum.RoleVisitor)
+	Expect(*posts).HasLen(1)
+	Expect((*posts)[0].Title).Equals("Dark mode for the mobile app")
+}
+
+func TestReceiveEmailPostTask_InvalidTitle(t *testing.T) {
+	RegisterT(t)
+	posts, _ := setupEmailPost(t)
+
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:                "jon.snow@got.com",
+		To:                  []string{"feedback+demo@reply.test.fider.io"},
+		Subject:             "Re: Hi",
+		Text:                "Dark mode please",
+		SenderAuthenticated: true,
+	})
+
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(*posts).HasLen(0)
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("post_rejected")
+	Expect(emailmock.MessageHistory[0].Props["reasons"]).HasLen(1)
+}
+
+func TestReceiveEmailPostTask_UnknownTenant(t *testing.T) {
+	RegisterT(t)
+	posts, registered := setupEmailPost(t)
+
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:                "jon.snow@got.com",
+		To:                  []string{"feedback+unknown@reply.test.fider.io"},
+		Subject:             "Dark mode for the mobile app",
+		Text:                "Please!",
+		SenderAuthenticated: true,
+	})
+
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(*registered).HasLen(0)
+	Expect(*posts).HasLen(0)
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
+
+func TestReceiveEmailPostTask_UnauthenticatedSender(t *testing.T) {
+	RegisterT(t)
+	posts, registered := setupEmailPost(t)
+
+	var blob *cmd.StoreBlob
+	bus.AddHandler(func(ctx context.Context, c *cmd.StoreBlob) error {
+		blob = c
+		return nil
+	})
+	var verification *cmd.SaveVerificationKey
+	bus.AddHandler(func(ctx context.Context, c *cmd.SaveVerificationKey) error {
+		verification = c
+		return nil
+	})
+
+	msg := &inbound.Message{
+		From:    "jon.snow@got.com",
+		To:      []string{"feedback+demo@reply.test.fider.io"},
+		Subject: "Dark mode for the mobile app",
+		Text:    "Please!",
+	}
+	err := mock.NewWorker().Execute(tasks.ReceiveEmail(msg))
+	Expect(err).IsNil()
+	Expect(*registered).HasLen(0)
+	Expect(*posts).HasLen(0)
+
+	Expect(verification.Request.GetKind()).Equals(enum.EmailVerificationKindEmailPost)
+	Expect(verification.Request.GetEmail()).Equals("jon.snow@got.com")
+	Expect(verification.Request.GetName()).Equals("Jon Snow")
+	Expect(blob.Key).Equals(inbound.PendingPostBlobKey(verification.Key))
+	Expect(string(blob.Content)).ContainsSubstring(`"Subject":"Dark mode for the mobile app"`)
+
+	Expect(emailmock.MessageHistory).HasLen(1)
+	Expect(emailmock.MessageHistory[0].TemplateName).Equals("post_confirm")
+	Expect(emailmock.MessageHistory[0].To[0].Address).Equals("jon.snow@got.com")
+	Expect(emailmock.MessageHistory[0].Props["link"]).ContainsSubstring("https://demo.test.fider.io/inbound/confirm?k=" + verification.Key)
+}
+
+func TestReceiveEmailPostTask_UnauthenticatedSender_PrivateTenant(t *testing.T) {
+	RegisterT(t)
+	posts, registered := setupEmailPost(t)
+	worker := mock.NewWorker()
+	mock.DemoTenant.IsPrivate = true
+
+	err := worker.Execute(tasks.ReceiveEmail(&inbound.Message{
+		From:    "sansa.stark@got.com",
+		To:      []string{"feedback+demo@reply.test.fider.io"},
+		Subject: "Dark mode for the mobile app",
+		Text:    "Please!",
+	}))
+	Expect(err).IsNil()
+	Expect(*registered).HasLen(0)
+	Expect(*posts).HasLen(0)
+	Expect(emailmock.MessageHistory).HasLen(0)
+}
+
+func TestReceiveEmailReportTask(t *testing.T) {
+	RegisterT(t)
+	env.Config.Email.Inbound.Domain = "reply.test.fider.io"
+	env.Config.Email.Inbound.SMTPAddress = ":2525"
+
+	supressed := make(map[enum.EmailSupressionReason][]string)
+	bus.AddHandler(func(ctx context.Context, c *cmd.SupressEmail) error {
+		supressed[c.Reason] = c.EmailAddresses
+		return nil
+	})
+
+	// Reports sent to the bounce address of another recipient are ignored
+	task := tasks.ReceiveEmail(&inbound.Message{
+		From:    "mailer-daemon@got.com",
+		To:      []string{inbound.BounceAddress("jon.snow@got.com")},
+		Bounces: []string{"jon.snow@got.com", "arya.stark@got.com"},
+	})
+	err := mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(supressed).Equals(map[enum.EmailSupressionReason][]string{
+		enum.EmailSupressionHardBounce: {"jon.snow@got.com"},
+	})
+
+	task = tasks.ReceiveEmail(&inbound.Message{
+		From:       "feedback@got.com",
+		To:         []string{inbound.BounceAddress("arya.stark@got.com")},
+		Complaints: []string{"arya.stark@got.com"},
+	})
+	err = mock.NewWorker().Execute(task)
+	Expect(err).IsNil()
+	Expect(supressed[enum.EmailSupressionComplaint]).Equals([]string{"arya.stark@got.com"})
+}
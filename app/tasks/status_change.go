@@ -61,7 +61,7 @@ func NotifyAboutStatusChange(post *entity.Post, prevStatus enum.PostStatus) work
 		to := make([]dto.Recipient, 0)
 		for _, user := range users {
 			if user.ID != author.ID {
-				to = append(to, dto.NewRecipient(user.Name, user.Email, dto.Props{}))
+				to = append(to, newPostRecipient(c, user, post))
 			}
 		}
 
@@ -69,16 +69,15 @@ func NotifyAboutStatusChange(post *entity.Post, prevStatus enum.PostStatus) work
 		logoURL := web.LogoURL(c)
 
 		props := dto.Props{
-			"title":       post.Title,
-			"postLink":    linkWithText(fmt.Sprintf("#%d", post.Number), baseURL, "/posts/%d/%s", post.Number, post.Slug),
-			"siteName":    tenant.Name,
-			"content":     markdown.Full(post.Response.Text, true),
-			"status":      i18n.T(c, fmt.Sprintf("enum.poststatus.%s", post.Status.Name())),
-			"duplicate":   duplicate,
-			"view":        linkWithText(i18n.T(c, "email.subscription.view"), baseURL, "/posts/%d/%s", post.Number, post.Slug),
-			"unsubscribe": linkWithText(i18n.T(c, "email.subscription.unsubscribe"), baseURL, "/posts/%d/%s", post.Number, post.Slug),
-			"change":      linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
-			"logo":        logoURL,
+			"title":     post.Title,
+			"postLink":  linkWithText(fmt.Sprintf("#%d", post.Number), baseURL, "/posts/%d/%s", post.Number, post.Slug),
+			"siteName":  tenant.Name,
+			"content":   markdown.Full(post.Response.Text, true),
+			"status":    i18n.T(c, fmt.Sprintf("enum.poststatus.%s", post.Status.Name())),
+			"duplicate": duplicate,
+			"view":      linkWithText(i18n.T(c, "email.subscription.view"), baseURL, "/posts/%d/%s", post.Number, post.Slug),
+			"change":    linkWithText(i18n.T(c, "email.subscription.change"), baseURL, "/settings"),
+			"logo":      logoURL,
 		}
 
 		bus.Publish(c, &cmd.SendMail{
@@ -88,14 +87,33 @@ func NotifyAboutStatusChange(post *entity.Post, prevStatus enum.PostStatus) work
 			Props:        props,
 		})
 
+		// Digest notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelDigest, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		if err = queueDigestItems(c, users, post, title, link); err != nil {
+			return c.Failure(err)
+		}
+
+		// Push notification
+		users, err = getActiveSubscribers(c, post, enum.NotificationChannelPush, enum.NotificationEventChangeStatus)
+		if err != nil {
+			return c.Failure(err)
+		}
+
+		sendPushNotifications(c, users, title, link)
+
 		webhookProps := webhook.Props{"post_old_status": prevStatus.Name()}
 		webhookProps.SetPost(post, "post", baseURL, true, true)
 		webhookProps.SetUser(author, "author")
 		webhookProps.SetTenant(tenant, "tenant", baseURL, logoURL)
 
 		err = bus.Dispatch(c, &cmd.TriggerWebhooks{
-			Type:  enum.WebhookChangeStatus,
-			Props: webhookProps,
+			Type:     enum.WebhookChangeStatus,
+			Props:    webhookProps,
+			Internal: post.IsPrivate,
 		})
 		if err != nil {
 			return c.Failure(err)
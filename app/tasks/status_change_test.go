@@ -16,6 +16,7 @@ import (
 	"github.com/getfider/fider/app/models/dto"
 	. "github.com/getfider/fider/app/pkg/assert"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/jwt"
 	"github.com/getfider/fider/app/pkg/mock"
 	"github.com/getfider/fider/app/services/email/emailmock"
 	"github.com/getfider/fider/app/tasks"
@@ -32,6 +33,10 @@ func TestNotifyAboutStatusChangeTask(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.AryaStark,
 		}
@@ -73,25 +78,29 @@ func TestNotifyAboutStatusChangeTask(t *testing.T) {
 	Expect(emailmock.MessageHistory[0].TemplateName).Equals("change_status")
 	Expect(emailmock.MessageHistory[0].Tenant).Equals(mock.DemoTenant)
 	Expect(emailmock.MessageHistory[0].Props).Equals(dto.Props{
-		"title":       "Add support for TypeScript",
-		"postLink":    "<a href='http://domain.com/posts/1/add-support-for-typescript'>#1</a>",
-		"siteName":    "Demonstration",
-		"content":     template.HTML("<p>Planned for next release.</p>"),
-		"duplicate":   "",
-		"status":      "Planned",
-		"view":        "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
-		"change":      "<a href='http://domain.com/settings'>change your notification preferences</a>",
-		"unsubscribe": "<a href='http://domain.com/posts/1/add-support-for-typescript'>unsubscribe from it</a>",
-		"logo":        "https://fider.io/images/logo-100x100.png",
+		"title":     "Add support for TypeScript",
+		"postLink":  "<a href='http://domain.com/posts/1/add-support-for-typescript'>#1</a>",
+		"siteName":  "Demonstration",
+		"content":   template.HTML("<p>Planned for next release.</p>"),
+		"duplicate": "",
+		"status":    "Planned",
+		"view":      "<a href='http://domain.com/posts/1/add-support-for-typescript'>view it on your browser</a>",
+		"change":    "<a href='http://domain.com/settings'>change your notification preferences</a>",
+		"logo":      "https://fider.io/images/logo-100x100.png",
 	})
 	Expect(emailmock.MessageHistory[0].From).Equals(dto.Recipient{
 		Name: "Jon Snow",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, PostID: post.ID})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Arya Stark",
-		Address: "arya.stark@got.com",
-		Props:   dto.Props{},
+		Name:           "Arya Stark",
+		Address:        "arya.stark@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -145,6 +154,10 @@ func TestNotifyAboutStatusChangeTask_Duplicate(t *testing.T) {
 	})
 
 	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{}
+			return nil
+		}
 		q.Result = []*entity.User{
 			mock.AryaStark,
 		}
@@ -190,25 +203,29 @@ func TestNotifyAboutStatusChangeTask_Duplicate(t *testing.T) {
 	Expect(emailmock.MessageHistory[0].TemplateName).Equals("change_status")
 	Expect(emailmock.MessageHistory[0].Tenant).Equals(mock.DemoTenant)
 	Expect(emailmock.MessageHistory[0].Props).Equals(dto.Props{
-		"title":       "I need TypeScript",
-		"postLink":    "<a href='http://domain.com/posts/2/i-need-typescript'>#2</a>",
-		"siteName":    "Demonstration",
-		"content":     template.HTML(""),
-		"duplicate":   "<a href='http://domain.com/posts/1/add-support-for-typescript'>Add support for TypeScript</a>",
-		"status":      "Duplicate",
-		"view":        "<a href='http://domain.com/posts/2/i-need-typescript'>view it on your browser</a>",
-		"change":      "<a href='http://domain.com/settings'>change your notification preferences</a>",
-		"unsubscribe": "<a href='http://domain.com/posts/2/i-need-typescript'>unsubscribe from it</a>",
-		"logo":        "https://fider.io/images/logo-100x100.png",
+		"title":     "I need TypeScript",
+		"postLink":  "<a href='http://domain.com/posts/2/i-need-typescript'>#2</a>",
+		"siteName":  "Demonstration",
+		"content":   template.HTML(""),
+		"duplicate": "<a href='http://domain.com/posts/1/add-support-for-typescript'>Add support for TypeScript</a>",
+		"status":    "Duplicate",
+		"view":      "<a href='http://domain.com/posts/2/i-need-typescript'>view it on your browser</a>",
+		"change":    "<a href='http://domain.com/settings'>change your notification preferences</a>",
+		"logo":      "https://fider.io/images/logo-100x100.png",
 	})
 	Expect(emailmock.MessageHistory[0].From).Equals(dto.Recipient{
 		Name: "Jon Snow",
 	})
 	Expect(emailmock.MessageHistory[0].To).HasLen(1)
+	unsubscribeToken, _ := jwt.Encode(&jwt.UnsubscribeClaims{UserID: mock.AryaStark.ID, PostID: post.ID})
+	unsubscribeURL := "http://domain.com/unsubscribe/" + unsubscribeToken
 	Expect(emailmock.MessageHistory[0].To[0]).Equals(dto.Recipient{
-		Name:    "Arya Stark",
-		Address: "arya.stark@got.com",
-		Props:   dto.Props{},
+		Name:           "Arya Stark",
+		Address:        "arya.stark@got.com",
+		UnsubscribeURL: unsubscribeURL,
+		Props: dto.Props{
+			"unsubscribe": "<a href='" + unsubscribeURL + "'>unsubscribe from it</a>",
+		},
 	})
 
 	Expect(addNewNotification).IsNotNil()
@@ -255,3 +272,58 @@ func TestNotifyAboutStatusChangeTask_Duplicate(t *testing.T) {
 		"tenant_url":                    "http://domain.com",
 	})
 }
+
+func TestNotifyAboutStatusChangeTask_QueuesDigestItems(t *testing.T) {
+	RegisterT(t)
+	bus.Init(emailmock.Service{})
+
+	bus.AddHandler(func(ctx context.Context, q *query.GetActiveSubscribers) error {
+		if q.Channel == enum.NotificationChannelDigest || q.Channel == enum.NotificationChannelPush {
+			q.Result = []*entity.User{
+				mock.JonSnow,
+				mock.AryaStark,
+			}
+		} else {
+			q.Result = []*entity.User{}
+		}
+		return nil
+	})
+
+	var addDigestItems *cmd.AddDigestItems
+	bus.AddHandler(func(ctx context.Context, c *cmd.AddDigestItems) error {
+		addDigestItems = c
+		return nil
+	})
+
+	bus.AddHandler(func(ctx context.Context, c *cmd.TriggerWebhooks) error {
+		return nil
+	})
+
+	worker := mock.NewWorker()
+	post := &entity.Post{
+		ID:     1,
+		Number: 1,
+		Title:  "Add support for TypeScript",
+		Slug:   "add-support-for-typescript",
+		User:   mock.AryaStark,
+		Status: enum.PostPlanned,
+		Response: &entity.PostResponse{
+			RespondedAt: time.Now(),
+			Text:        "Planned for next release.",
+			User:        mock.JonSnow,
+		},
+	}
+
+	err := worker.
+		OnTenant(mock.DemoTenant).
+		AsUser(mock.JonSnow).
+		WithBaseURL("http://domain.com").
+		Execute(tasks.NotifyAboutStatusChange(post, enum.PostOpen))
+
+	Expect(err).IsNil()
+	Expect(addDigestItems).IsNotNil()
+	Expect(addDigestItems.Users).Equals([]*entity.User{mock.AryaStark})
+	Expect(addDigestItems.PostID).Equals(post.ID)
+	Expect(addDigestItems.Title).Equals("**Jon Snow** changed status of **Add support for TypeScript** to **planned**")
+	Expect(addDigestItems.Link).Equals("/posts/1/add-support-for-typescript")
+}
@@ -3,11 +3,19 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"slices"
 
+	"github.com/getfider/fider/app/models/cmd"
+	"github.com/getfider/fider/app/models/dto"
 	"github.com/getfider/fider/app/models/entity"
 	"github.com/getfider/fider/app/models/enum"
 	"github.com/getfider/fider/app/models/query"
 	"github.com/getfider/fider/app/pkg/bus"
+	"github.com/getfider/fider/app/pkg/i18n"
+	"github.com/getfider/fider/app/pkg/inbound"
+	"github.com/getfider/fider/app/pkg/jwt"
+	"github.com/getfider/fider/app/pkg/log"
+	"github.com/getfider/fider/app/pkg/web"
 	"github.com/getfider/fider/app/pkg/worker"
 )
 
@@ -23,12 +31,123 @@ func linkWithText(text, baseURL, path string, args ...any) string {
 	return fmt.Sprintf("<a href='%s%s'>%s</a>", baseURL, fmt.Sprintf(path, args...), text)
 }
 
+// newPostRecipient returns the recipient of a post notification, who can comment by replying to it when inbound email is enabled
+// and unsubscribe from the post with a single click
+func newPostRecipient(c *worker.Context, user *entity.User, post *entity.Post) dto.Recipient {
+	return newNotificationRecipient(c, user, post, &jwt.UnsubscribeClaims{UserID: user.ID, PostID: post.ID})
+}
+
+// newEventRecipient is like newPostRecipient, but unsubscribing stops the emails of given event instead
+func newEventRecipient(c *worker.Context, user *entity.User, post *entity.Post, event enum.NotificationEvent) dto.Recipient {
+	return newNotificationRecipient(c, user, post, &jwt.UnsubscribeClaims{UserID: user.ID, Event: event.UserSettingsKeyName})
+}
+
+func newNotificationRecipient(c *worker.Context, user *entity.User, post *entity.Post, unsubscribe *jwt.UnsubscribeClaims) dto.Recipient {
+	recipient := dto.NewRecipient(user.Name, user.Email, dto.Props{})
+	recipient.ReplyTo = inbound.ReplyAddress(c.Tenant().ID, user.ID, post.ID)
+	return withUnsubscribe(c, recipient, unsubscribe)
+}
+
+// withUnsubscribe adds a signed link to given recipient that unsubscribes from notifications without signing in
+// It's sent on the List-Unsubscribe header and can be used on templates as the unsubscribe prop
+func withUnsubscribe(c *worker.Context, recipient dto.Recipient, claims *jwt.UnsubscribeClaims) dto.Recipient {
+	token, err := jwt.Encode(claims)
+	if err != nil {
+		log.Error(c, err)
+		return recipient
+	}
+
+	recipient.UnsubscribeURL = fmt.Sprintf("%s/unsubscribe/%s", web.BaseURL(c), token)
+	recipient.Props["unsubscribe"] = fmt.Sprintf("<a href='%s'>%s</a>", recipient.UnsubscribeURL, i18n.T(c, "email.subscription.unsubscribe"))
+	return recipient
+}
+
 func getActiveSubscribers(ctx context.Context, post *entity.Post, channel enum.NotificationChannel, event enum.NotificationEvent) ([]*entity.User, error) {
 	q := &query.GetActiveSubscribers{
 		Number:  post.Number,
 		Channel: channel,
 		Event:   event,
 	}
-	err := bus.Dispatch(ctx, q)
-	return q.Result, err
+	if err := bus.Dispatch(ctx, q); err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, 0, len(q.Result))
+	for _, user := range q.Result {
+		if post.IsVisibleTo(user) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func getCommentSubscribers(ctx context.Context, post *entity.Post, comment *entity.Comment, channel enum.NotificationChannel, event enum.NotificationEvent) ([]*entity.User, error) {
+	users, err := getActiveSubscribers(ctx, post, channel, event)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		if comment.IsVisibleTo(user) {
+			visible = append(visible, user)
+		}
+	}
+	return visible, nil
+}
+
+// queueDigestItems adds an event to the pending email digest of given users, skipping the current user
+func queueDigestItems(c *worker.Context, users []*entity.User, post *entity.Post, title, link string) error {
+	author := c.User()
+	recipients := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		if author == nil || user.ID != author.ID {
+			recipients = append(recipients, user)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return bus.Dispatch(c, &cmd.AddDigestItems{
+		Users:  recipients,
+		PostID: post.ID,
+		Title:  title,
+		Link:   link,
+	})
+}
+
+// sendPushNotifications sends a push notification to the browsers of given users, skipping the current user
+func sendPushNotifications(c *worker.Context, users []*entity.User, title, link string) {
+	author := c.User()
+	to := make([]*entity.User, 0, len(users))
+	for _, user := range users {
+		if author == nil || user.ID != author.ID {
+			to = append(to, user)
+		}
+	}
+
+	if len(to) == 0 {
+		return
+	}
+
+	bus.Publish(c, &cmd.SendPushNotification{
+		To:    to,
+		Title: title,
+		Link:  link,
+	})
+}
+
+// mentionedUsers returns the users mentioned on a content that haven't been notified about it yet
+func mentionedUsers(users []*entity.User, mentions []string, notified []*entity.MentionNotification) []*entity.User {
+	result := make([]*entity.User, 0)
+	for _, u := range users {
+		if slices.Contains(mentions, u.Name) && !slices.ContainsFunc(notified, func(n *entity.MentionNotification) bool {
+			return n.UserID == u.ID
+		}) {
+			result = append(result, u)
+		}
+	}
+	return result
 }
@@ -13,6 +13,10 @@ func main() {
 		os.Exit(cmd.RunPing())
 	} else if len(args) > 0 && args[0] == "migrate" {
 		os.Exit(cmd.RunMigrate())
+	} else if len(args) > 0 && args[0] == "vapidkeys" {
+		os.Exit(cmd.RunVAPIDKeys())
+	} else if len(args) > 0 && args[0] == "signingkey" {
+		os.Exit(cmd.RunSigningKey())
 	} else {
 		os.Exit(cmd.RunServer())
 	}
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS post_merges (
+  id SERIAL PRIMARY KEY,
+  tenant_id INT NOT NULL,
+  post_id INT NOT NULL,
+  original_id INT NOT NULL,
+  merged_at TIMESTAMPTZ NOT NULL,
+  merged_by_id INT NOT NULL,
+  reverted_at TIMESTAMPTZ NULL,
+  reverted_by_id INT NULL,
+  previous_status SMALLINT NOT NULL,
+  previous_response TEXT NULL,
+  previous_response_date TIMESTAMPTZ NULL,
+  previous_response_user_id INT NULL,
+  previous_original_id INT NULL,
+  voter_ids INT[] NOT NULL,
+  added_voter_ids INT[] NOT NULL,
+  added_subscriber_ids INT[] NOT NULL,
+  added_tag_ids INT[] NOT NULL,
+  copied_comment_ids INT[] NOT NULL,
+  FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+  FOREIGN KEY (post_id) REFERENCES posts(id),
+  FOREIGN KEY (original_id) REFERENCES posts(id),
+  FOREIGN KEY (merged_by_id) REFERENCES users(id),
+  FOREIGN KEY (reverted_by_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_post_merges_tenant_post ON post_merges (tenant_id, post_id);
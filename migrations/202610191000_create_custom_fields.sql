@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    key VARCHAR(50) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    type SMALLINT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    UNIQUE(tenant_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS post_custom_field_values (
+    tenant_id INT NOT NULL,
+    post_id INT NOT NULL,
+    field_id INT NOT NULL,
+    value JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_by_id INT NOT NULL,
+    PRIMARY KEY (post_id, field_id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields(id) ON DELETE CASCADE,
+    FOREIGN KEY (updated_by_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_custom_field_values_tenant ON post_custom_field_values(tenant_id, field_id);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    post_id INT NOT NULL,
+    comment_id INT NULL,
+    kind SMALLINT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by_id INT NOT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_revisions_tenant_post ON revisions(tenant_id, post_id);
+
+-- Current content becomes the first revision of everything that already exists
+INSERT INTO revisions (tenant_id, post_id, kind, title, content, created_at, created_by_id)
+SELECT tenant_id, id, 1, title, description, created_at, user_id
+FROM posts;
+
+INSERT INTO revisions (tenant_id, post_id, kind, content, created_at, created_by_id)
+SELECT tenant_id, id, 2, response, response_date, response_user_id
+FROM posts
+WHERE response IS NOT NULL AND response != '' AND response_date IS NOT NULL AND response_user_id IS NOT NULL;
+
+INSERT INTO revisions (tenant_id, post_id, comment_id, kind, content, created_at, created_by_id)
+SELECT tenant_id, post_id, id, 3, content, COALESCE(edited_at, created_at), COALESCE(edited_by_id, user_id)
+FROM comments;
@@ -0,0 +1,5 @@
+ALTER TABLE tenants ADD voting_mode SMALLINT NOT NULL DEFAULT 1;
+ALTER TABLE tenants ADD vote_budget INT NOT NULL DEFAULT 10;
+ALTER TABLE tenants ADD max_votes_per_post INT NOT NULL DEFAULT 3;
+
+ALTER TABLE post_votes ADD weight SMALLINT NOT NULL DEFAULT 1;
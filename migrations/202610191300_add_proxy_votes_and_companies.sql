@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS companies (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    mrr INT NOT NULL DEFAULT 0,
+    plan VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    UNIQUE(tenant_id, name)
+);
+
+ALTER TABLE users ADD company_id INT NULL;
+ALTER TABLE users ADD FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE SET NULL;
+
+ALTER TABLE post_votes ADD proxied_by_id INT NULL;
+ALTER TABLE post_votes ADD note TEXT NOT NULL DEFAULT '';
+ALTER TABLE post_votes ADD FOREIGN KEY (proxied_by_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS saml_configs (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    status INT NOT NULL,
+    display_name VARCHAR(50) NOT NULL,
+    idp_metadata TEXT NOT NULL,
+    idp_entity_id VARCHAR(500) NOT NULL,
+    idp_sso_url VARCHAR(500) NOT NULL,
+    attribute_name VARCHAR(200) NOT NULL DEFAULT '',
+    attribute_email VARCHAR(200) NOT NULL DEFAULT '',
+    attribute_role VARCHAR(200) NOT NULL DEFAULT '',
+    administrator_role_value VARCHAR(200) NOT NULL DEFAULT '',
+    collaborator_role_value VARCHAR(200) NOT NULL DEFAULT '',
+    require_sso BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_saml_configs_tenant_id ON saml_configs(tenant_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS custom_roles (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_custom_roles_tenant_name ON custom_roles(tenant_id, LOWER(name));
+
+ALTER TABLE users ADD custom_role_id INT NULL;
+ALTER TABLE users ADD FOREIGN KEY (custom_role_id) REFERENCES custom_roles(id);
@@ -0,0 +1,3 @@
+ALTER TABLE posts ADD is_private BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD is_internal BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE webhooks ADD is_internal BOOLEAN NOT NULL DEFAULT FALSE;
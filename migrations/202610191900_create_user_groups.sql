@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS user_groups (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    email_domains TEXT[] NOT NULL DEFAULT '{}',
+    oauth_claim VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_groups_tenant_name ON user_groups(tenant_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS user_group_members (
+    tenant_id INT NOT NULL,
+    group_id INT NOT NULL,
+    user_id INT NOT NULL,
+    is_from_oauth BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_group_members_user ON user_group_members(tenant_id, user_id);
+
+ALTER TABLE tags ADD group_id INT NULL;
+ALTER TABLE tags ADD FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE SET NULL;
+
+ALTER TABLE roadmap_columns ADD group_id INT NULL;
+ALTER TABLE roadmap_columns ADD FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE SET NULL;
+
+ALTER TABLE posts ADD group_id INT NULL;
+ALTER TABLE posts ADD FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE SET NULL;
+
+ALTER TABLE oauth_providers ADD json_user_groups_path VARCHAR(100) NOT NULL DEFAULT '';
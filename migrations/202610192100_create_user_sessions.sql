@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    user_id INT NOT NULL,
+    key_hash VARCHAR(128) NOT NULL,
+    user_agent VARCHAR(500) NOT NULL,
+    ip_address VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_sessions_key_hash ON user_sessions(key_hash);
+CREATE INDEX idx_user_sessions_user ON user_sessions(tenant_id, user_id);
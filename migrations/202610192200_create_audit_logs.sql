@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    actor_id INT NULL,
+    actor_name VARCHAR(100) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id INT NULL,
+    target_name VARCHAR(200) NOT NULL,
+    before JSONB NULL,
+    after JSONB NULL,
+    ip_address VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_audit_logs_tenant_created_at ON audit_logs(tenant_id, created_at DESC);
+CREATE INDEX idx_audit_logs_tenant_action ON audit_logs(tenant_id, action);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS digest_items (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    user_id INT NOT NULL,
+    post_id INT NULL,
+    title VARCHAR(400) NOT NULL,
+    link VARCHAR(2048) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_digest_items_user_id ON digest_items(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD email_supression_reason INT NULL;
+
+UPDATE users SET email_supression_reason = 1 WHERE email_supressed_at IS NOT NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS email_templates (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    subject VARCHAR(300) NOT NULL,
+    body TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX email_templates_tenant_name_locale_key ON email_templates(tenant_id, name, locale);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS outbound_emails (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NULL,
+    message_id VARCHAR(200) NOT NULL,
+    template_name VARCHAR(100) NOT NULL,
+    from_address VARCHAR(400) NOT NULL,
+    reply_to VARCHAR(400) NOT NULL,
+    to_name VARCHAR(200) NOT NULL,
+    to_address VARCHAR(200) NOT NULL,
+    unsubscribe_url VARCHAR(2048) NOT NULL,
+    subject VARCHAR(1000) NOT NULL,
+    body TEXT NOT NULL,
+    status INT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ NULL,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_outbound_emails_queued ON outbound_emails(next_attempt_at) WHERE status = 1;
+CREATE INDEX idx_outbound_emails_to_address ON outbound_emails(LOWER(to_address));
+CREATE INDEX idx_outbound_emails_created_at ON outbound_emails(created_at);
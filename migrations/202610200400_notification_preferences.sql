@@ -0,0 +1,14 @@
+ALTER TABLE post_subscribers ADD COLUMN comments_muted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS tag_subscribers (
+    tenant_id INT NOT NULL,
+    user_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tag_id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tag_subscribers_tag ON tag_subscribers(tenant_id, tag_id);
@@ -0,0 +1 @@
+ALTER TABLE saml_configs ADD allow_idp_initiated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE post_merges DROP CONSTRAINT post_merges_tenant_id_fkey;
+ALTER TABLE post_merges ADD CONSTRAINT post_merges_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+ALTER TABLE oauth_clients ADD COLUMN client_secret_hash VARCHAR(128) NULL;
+
+UPDATE oauth_clients SET client_secret_hash = encode(sha512(convert_to(client_secret, 'UTF8')), 'hex') WHERE client_secret IS NOT NULL;
+
+ALTER TABLE oauth_clients DROP COLUMN client_secret;
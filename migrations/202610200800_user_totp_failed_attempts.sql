@@ -0,0 +1,2 @@
+ALTER TABLE user_totp ADD COLUMN failed_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN locked_until TIMESTAMPTZ NULL;
@@ -3,7 +3,7 @@ import NoDataIllustration from "@fider/assets/images/undraw-empty.svg"
 
 import React, { useEffect, useState } from "react"
 import IconBell from "@fider/assets/images/heroicons-bell.svg"
-import { useFider } from "@fider/hooks"
+import { useFider, useRealtime } from "@fider/hooks"
 import { actions, Fider } from "@fider/services"
 import { Avatar, Icon, Markdown, Moment } from "./common"
 import { Dropdown } from "./common/Dropdown"
@@ -44,16 +44,22 @@ export const NotificationIndicator = () => {
   const [recent, setRecent] = useState<Notification[] | undefined>()
   const [unread, setUnread] = useState<Notification[] | undefined>()
 
+  const loadUnreadCount = () => {
+    actions.getTotalUnreadNotifications().then((result) => {
+      if (result.ok && result.data > 0) {
+        setUnreadNotifications(result.data)
+      }
+    })
+  }
+
   useEffect(() => {
     if (fider.session.isAuthenticated) {
-      actions.getTotalUnreadNotifications().then((result) => {
-        if (result.ok && result.data > 0) {
-          setUnreadNotifications(result.data)
-        }
-      })
+      loadUnreadCount()
     }
   }, [fider.session.isAuthenticated])
 
+  useRealtime("notification.created", loadUnreadCount, fider.session.isAuthenticated)
+
   useEffect(() => {
     if (showingNotifications) {
       actions.getAllNotifications().then((result) => {
@@ -29,8 +29,11 @@ beforeEach(() => {
     hasVoted: false,
     response: null,
     votesCount: 5,
+    votesWeight: 5,
+    myVoteWeight: 0,
     commentsCount: 2,
     tags: [],
+    customFields: {},
   }
 })
 
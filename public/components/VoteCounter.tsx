@@ -13,8 +13,10 @@ export interface VoteCounterProps {
 
 export const VoteCounter = (props: VoteCounterProps) => {
   const fider = useFider()
+  const isWeighted = fider.session.tenant.votingMode === "budget" || fider.session.tenant.votingMode === "importance"
   const [hasVoted, setHasVoted] = useState(props.post.hasVoted)
-  const [votesCount, setVotesCount] = useState(props.post.votesCount)
+  const [myVoteWeight, setMyVoteWeight] = useState(props.post.myVoteWeight || (props.post.hasVoted ? 1 : 0))
+  const [votesCount, setVotesCount] = useState(isWeighted ? props.post.votesWeight : props.post.votesCount)
   const [isSignInModalOpen, setIsSignInModalOpen] = useState(false)
 
   const voteOrUndo = async () => {
@@ -27,7 +29,10 @@ export const VoteCounter = (props: VoteCounterProps) => {
 
     const response = await action(props.post.number)
     if (response.ok) {
-      setVotesCount(votesCount + (hasVoted ? -1 : 1))
+      // Weighted sites show the score, which changes by the weight of the vote
+      const change = isWeighted ? myVoteWeight : 1
+      setVotesCount(votesCount + (hasVoted ? -change : 1))
+      setMyVoteWeight(hasVoted ? 0 : 1)
       setHasVoted(!hasVoted)
     }
   }
@@ -2,3 +2,4 @@ export * from "./use-timeout"
 export * from "./use-fider"
 export * from "./use-script"
 export * from "./use-cache"
+export * from "./use-realtime"
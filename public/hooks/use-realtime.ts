@@ -0,0 +1,24 @@
+import { useRef, useEffect } from "react"
+import { realtime } from "@fider/services"
+
+type EventCallback<T> = (data: T) => void
+
+// Calls back on each event of given type while enabled, sharing one connection to the server across components
+export function useRealtime<T>(type: string, callback: EventCallback<T>, enabled = true) {
+  const savedCallback = useRef<EventCallback<T>>()
+
+  useEffect(() => {
+    savedCallback.current = callback
+  })
+
+  useEffect(() => {
+    if (!enabled) {
+      return
+    }
+    return realtime.subscribe<T>(type, (data) => {
+      if (savedCallback.current) {
+        savedCallback.current(data)
+      }
+    })
+  }, [type, enabled])
+}
@@ -0,0 +1,13 @@
+export interface AuditLogEntry {
+  id: number
+  action: string
+  actorId?: number
+  actorName: string
+  targetType: string
+  targetId?: number
+  targetName: string
+  before?: { [key: string]: any }
+  after?: { [key: string]: any }
+  ipAddress: string
+  createdAt: string
+}
@@ -0,0 +1,41 @@
+export type EmailSupressionReason = "hard_bounce" | "complaint" | "manual"
+
+export interface SupressedEmail {
+  userId: number
+  userName: string
+  email: string
+  reason: EmailSupressionReason
+  supressedAt: string
+}
+
+export interface EmailTemplate {
+  id?: number
+  name: string
+  locale: string
+  subject: string
+  body: string
+  updatedAt?: string
+}
+
+export interface EmailTemplatePreview {
+  subject: string
+  body: string
+  error?: string
+}
+
+export type OutboundEmailStatus = "queued" | "sent" | "failed" | "bounced"
+
+export interface OutboundEmail {
+  id: number
+  messageId: string
+  templateName: string
+  from: string
+  toName: string
+  toAddress: string
+  subject: string
+  status: OutboundEmailStatus
+  attempts: number
+  lastError?: string
+  createdAt: string
+  sentAt?: string
+}
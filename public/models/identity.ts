@@ -11,9 +11,15 @@ export interface Tenant {
   logoBlobKey: string
   allowedSchemes: string
   isEmailAuthAllowed: boolean
+  isTwoFactorRequired: boolean
   isFeedEnabled: boolean
+  votingMode: VotingMode
+  voteBudget: number
+  maxVotesPerPost: number
 }
 
+export type VotingMode = "simple" | "budget" | "importance"
+
 export enum TenantStatus {
   Active = 1,
   Pending = 2,
@@ -28,6 +34,81 @@ export interface User {
   role: UserRole
   status: UserStatus
   avatarURL: string
+  customRole?: CustomRole
+  hasTwoFactor?: boolean
+}
+
+export type Permission =
+  | "posts:respond"
+  | "tags:manage"
+  | "roadmap:manage"
+  | "comments:moderate"
+  | "voters:emails"
+  | "webhooks:manage"
+  | "data:export"
+
+export interface CustomRole {
+  id: number
+  name: string
+  permissions: Permission[]
+  usersCount: number
+}
+
+export interface UserGroup {
+  id: number
+  name: string
+  emailDomains: string[]
+  oauthClaim: string
+  membersCount: number
+}
+
+export interface Company {
+  id: number
+  name: string
+  mrr: number
+  plan: string
+  usersCount: number
+}
+
+export type APIScope = "posts:read" | "posts:write" | "roadmap:write" | "users:read" | "scim" | "admin"
+
+export interface APIToken {
+  id: number
+  name: string
+  prefix: string
+  scopes: APIScope[]
+  expiresAt?: string
+  lastUsedAt?: string
+  createdAt: string
+  user?: User
+}
+
+export interface UserSession {
+  id: number
+  userAgent: string
+  ipAddress: string
+  createdAt: string
+  lastSeenAt: string
+}
+
+export interface OAuthClient {
+  id: number
+  clientId: string
+  name: string
+  redirectUris: string[]
+  isConfidential: boolean
+  createdAt: string
+}
+
+export interface OAuthAuthorizationRequest {
+  clientId: string
+  redirectUri: string
+  responseType: string
+  scope: string
+  state: string
+  nonce: string
+  codeChallenge: string
+  codeChallengeMethod: string
 }
 
 export interface UserNames {
@@ -68,4 +149,9 @@ export interface CurrentUser {
   status: UserStatus
   isAdministrator: boolean
   isCollaborator: boolean
+  permissions: Permission[]
+}
+
+export const hasPermission = (user: CurrentUser, permission: Permission): boolean => {
+  return user.permissions.includes(permission)
 }
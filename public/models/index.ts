@@ -5,3 +5,5 @@ export * from "./billing"
 export * from "./notification"
 export * from "./webhook"
 export * from "./roadmap"
+export * from "./audit"
+export * from "./email"
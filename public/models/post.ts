@@ -1,4 +1,4 @@
-import { User } from "./identity"
+import { Company, User } from "./identity"
 
 export interface Post {
   id: number
@@ -12,8 +12,14 @@ export interface Post {
   hasVoted: boolean
   response: PostResponse | null
   votesCount: number
+  votesWeight: number
+  myVoteWeight: number
+  votesRevenue?: number
   commentsCount: number
   tags: string[]
+  customFields: { [key: string]: CustomFieldValue }
+  isPrivate: boolean
+  groupId?: number
 }
 
 export class PostStatus {
@@ -66,6 +72,7 @@ export interface Comment {
   reactionCounts?: ReactionCount[]
   editedAt?: string
   editedBy?: User
+  isInternal: boolean
 }
 
 export interface Tag {
@@ -74,16 +81,69 @@ export interface Tag {
   name: string
   color: string
   isPublic: boolean
+  groupId?: number
+}
+
+export type CustomFieldType = "text" | "number" | "date" | "select" | "multi-select" | "user"
+
+export type CustomFieldValue = string | number | string[]
+
+export interface CustomField {
+  id: number
+  key: string
+  name: string
+  type: CustomFieldType
+  options: string[]
+  isPublic: boolean
+  position: number
+}
+
+export type RevisionKind = "post" | "response" | "comment"
+
+export interface Revision {
+  id: number
+  postId: number
+  commentId?: number
+  kind: RevisionKind
+  title?: string
+  content: string
+  createdAt: string
+  createdBy: User
+}
+
+export interface RevisionChange {
+  op: "equal" | "insert" | "delete"
+  text: string
+}
+
+export interface RevisionDiff {
+  from?: Revision
+  to: Revision
+  title: RevisionChange[]
+  content: RevisionChange[]
+}
+
+export interface VoteUser {
+  id: number
+  name: string
+  email: string
+  avatarURL: string
+  company?: Company
 }
 
 export interface Vote {
   createdAt: Date
-  user: {
-    id: number
-    name: string
-    email: string
-    avatarURL: string
-  }
+  weight: number
+  note?: string
+  user: VoteUser
+  proxiedBy?: VoteUser
+}
+
+export interface VoteBudget {
+  total: number
+  spent: number
+  remaining: number
+  maxPerPost: number
 }
 
 export interface InlineImage {
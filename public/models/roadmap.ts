@@ -6,6 +6,7 @@ export interface RoadmapColumn {
   slug: string
   position: number
   isVisibleToPublic: boolean
+  groupId?: number
   posts: Post[]
 }
 
@@ -22,6 +22,7 @@ export interface SystemSettings {
   oauth: OAuthProviderOption[]
   postWithTags: boolean
   allowAllowedSchemes: boolean
+  vapidPublicKey: string
 }
 
 export interface UserSettings {
@@ -47,9 +48,25 @@ export interface OAuthConfig {
   jsonUserIDPath: string
   jsonUserNamePath: string
   jsonUserEmailPath: string
+  jsonUserGroupsPath: string
   isTrusted: boolean
 }
 
+export interface SAMLConfig {
+  status: number
+  displayName: string
+  idpMetadata: string
+  idpEntityID: string
+  idpSSOURL: string
+  attributeName: string
+  attributeEmail: string
+  attributeRole: string
+  administratorRoleValue: string
+  collaboratorRoleValue: string
+  requireSSO: boolean
+  allowIdPInitiated: boolean
+}
+
 export interface ImageUpload {
   bkey?: string
   upload?: {
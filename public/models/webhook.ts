@@ -8,6 +8,7 @@ export interface WebhookData {
   content: string
   http_method: string
   http_headers: HttpHeaders
+  is_internal: boolean
 }
 
 export interface Webhook extends WebhookData {
@@ -0,0 +1,73 @@
+import React from "react"
+import { Button, Input, Form } from "@fider/components"
+import { Failure } from "@fider/services"
+import { HStack } from "@fider/components/layout"
+
+interface CompanyFormProps {
+  name?: string
+  mrr?: number
+  plan?: string
+  onSave: (data: CompanyFormState) => Promise<Failure | undefined>
+  onCancel: () => void
+}
+
+export interface CompanyFormState {
+  name: string
+  mrr: number
+  plan: string
+  error?: Failure
+}
+
+export class CompanyForm extends React.Component<CompanyFormProps, CompanyFormState> {
+  constructor(props: CompanyFormProps) {
+    super(props)
+    this.state = {
+      name: props.name || "",
+      mrr: props.mrr || 0,
+      plan: props.plan || "",
+    }
+  }
+
+  private handleSave = async () => {
+    const error = await this.props.onSave(this.state)
+    if (error) {
+      this.setState({ error })
+    }
+  }
+
+  private handleCancel = async () => {
+    this.props.onCancel()
+  }
+
+  private setName = (name: string) => {
+    this.setState({ name })
+  }
+
+  private setMRR = (value: string) => {
+    this.setState({ mrr: parseInt(value, 10) || 0 })
+  }
+
+  private setPlan = (plan: string) => {
+    this.setState({ plan })
+  }
+
+  public render() {
+    return (
+      <Form error={this.state.error}>
+        <div className="grid gap-2 lg:grid-cols-3">
+          <Input field="name" label="Name" value={this.state.name} onChange={this.setName} />
+          <Input field="mrr" label="MRR" value={this.state.mrr.toString()} onChange={this.setMRR} />
+          <Input field="plan" label="Plan" placeholder="e.g. Enterprise" value={this.state.plan} onChange={this.setPlan} />
+        </div>
+        <HStack>
+          <Button variant="primary" onClick={this.handleSave}>
+            Save
+          </Button>
+          <Button onClick={this.handleCancel} variant="tertiary">
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+}
@@ -0,0 +1,102 @@
+import React, { useState } from "react"
+import { Company } from "@fider/models"
+import { Button, Icon } from "@fider/components"
+import { CompanyFormState, CompanyForm } from "./CompanyForm"
+import { actions, Failure } from "@fider/services"
+import { useFider } from "@fider/hooks"
+
+import IconX from "@fider/assets/images/heroicons-x.svg"
+import IconPencilAlt from "@fider/assets/images/heroicons-pencil-alt.svg"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface CompanyListItemProps {
+  company: Company
+  onCompanyEdited: (company: Company) => void
+  onCompanyDeleted: (company: Company) => void
+}
+
+export const CompanyListItem = (props: CompanyListItemProps) => {
+  const fider = useFider()
+  const [company] = useState(props.company)
+  const [state, setState] = useState<"view" | "edit" | "delete">("view")
+
+  const startDelete = async () => setState("delete")
+  const startEdit = async () => setState("edit")
+  const resetState = async () => setState("view")
+
+  const deleteCompany = async () => {
+    const result = await actions.deleteCompany(company.id)
+    if (result.ok) {
+      resetState()
+      props.onCompanyDeleted(company)
+    }
+  }
+
+  const updateCompany = async (data: CompanyFormState): Promise<Failure | undefined> => {
+    const result = await actions.updateCompany(company.id, data)
+    if (result.ok) {
+      company.name = result.data.name
+      company.mrr = result.data.mrr
+      company.plan = result.data.plan
+
+      resetState()
+      props.onCompanyEdited(company)
+    } else {
+      return result.error
+    }
+  }
+
+  const renderDeleteMode = () => {
+    return (
+      <VStack spacing={2}>
+        <div>
+          <b>Are you sure?</b>{" "}
+          <span>
+            The company <strong>{company.name}</strong> will be deleted and its users will no longer belong to any company.
+          </span>
+        </div>
+        <div>
+          <Button variant="danger" onClick={deleteCompany}>
+            Delete company
+          </Button>
+          <Button onClick={resetState} variant="tertiary">
+            Cancel
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  const renderViewMode = () => {
+    const buttons = fider.session.user.isAdministrator && [
+      <Button size="small" key={0} onClick={startEdit}>
+        <Icon sprite={IconPencilAlt} />
+        <span>Edit</span>
+      </Button>,
+      <Button size="small" key={1} onClick={startDelete}>
+        <Icon sprite={IconX} />
+        <span>Delete</span>
+      </Button>,
+    ]
+
+    return (
+      <HStack justify="between">
+        <VStack spacing={1}>
+          <span>
+            <strong>{company.name}</strong> {company.plan && <span className="text-muted text-xs">{company.plan}</span>}
+          </span>
+          <span className="text-muted text-sm">
+            MRR: {company.mrr.toLocaleString()} · {company.usersCount} {company.usersCount === 1 ? "user" : "users"}
+          </span>
+        </VStack>
+        <HStack>{buttons}</HStack>
+      </HStack>
+    )
+  }
+
+  const renderEditMode = () => {
+    return <CompanyForm name={company.name} mrr={company.mrr} plan={company.plan} onSave={updateCompany} onCancel={resetState} />
+  }
+
+  return state === "delete" ? renderDeleteMode() : state === "edit" ? renderEditMode() : renderViewMode()
+}
@@ -0,0 +1,117 @@
+import React from "react"
+import { Button, Input, Form, RadioButton, Select, SelectOption } from "@fider/components"
+import { CustomFieldType } from "@fider/models"
+import { Failure } from "@fider/services"
+import { HStack } from "@fider/components/layout"
+
+interface CustomFieldFormProps {
+  name?: string
+  type?: CustomFieldType
+  options?: string[]
+  isPublic?: boolean
+  isEditing?: boolean
+  onSave: (data: CustomFieldFormState) => Promise<Failure | undefined>
+  onCancel: () => void
+}
+
+export interface CustomFieldFormState {
+  name: string
+  type: CustomFieldType
+  options: string[]
+  isPublic: boolean
+  error?: Failure
+}
+
+const typeOptions: SelectOption[] = [
+  { value: "text", label: "Text" },
+  { value: "number", label: "Number" },
+  { value: "date", label: "Date" },
+  { value: "select", label: "Select" },
+  { value: "multi-select", label: "Multi-select" },
+  { value: "user", label: "User" },
+]
+
+export class CustomFieldForm extends React.Component<CustomFieldFormProps, CustomFieldFormState> {
+  private visibilityPublic = { label: "Public", value: "public" }
+  private visibilityPrivate = { label: "Staff only", value: "private" }
+
+  constructor(props: CustomFieldFormProps) {
+    super(props)
+    this.state = {
+      name: props.name || "",
+      type: props.type || "text",
+      options: props.options || [],
+      isPublic: props.isPublic || false,
+    }
+  }
+
+  private handleSave = async () => {
+    const error = await this.props.onSave(this.state)
+    if (error) {
+      this.setState({ error })
+    }
+  }
+
+  private handleCancel = async () => {
+    this.props.onCancel()
+  }
+
+  private setName = (name: string) => {
+    this.setState({ name })
+  }
+
+  private setType = (option?: SelectOption) => {
+    if (option) {
+      this.setState({ type: option.value as CustomFieldType })
+    }
+  }
+
+  private setOptions = (value: string) => {
+    this.setState({ options: value.split(",").map((o) => o.trim()) })
+  }
+
+  private setVisibility = (option: SelectOption) => {
+    this.setState({ isPublic: option === this.visibilityPublic })
+  }
+
+  public render() {
+    const hasOptions = this.state.type === "select" || this.state.type === "multi-select"
+
+    return (
+      <Form error={this.state.error}>
+        <div className="grid gap-2 lg:grid-cols-4">
+          <Input field="name" label="Name" value={this.state.name} onChange={this.setName} />
+          {this.props.isEditing ? (
+            <Input field="type" label="Type" value={typeOptions.find((o) => o.value === this.state.type)?.label} disabled={true} />
+          ) : (
+            <Select field="type" label="Type" defaultValue={this.state.type} options={typeOptions} onChange={this.setType} />
+          )}
+          <RadioButton
+            label="Visibility"
+            field="visibility"
+            defaultOption={this.state.isPublic ? this.visibilityPublic : this.visibilityPrivate}
+            options={[this.visibilityPublic, this.visibilityPrivate]}
+            onSelect={this.setVisibility}
+          />
+        </div>
+        {hasOptions && (
+          <Input
+            field="options"
+            label="Options"
+            placeholder="Comma separated, e.g. iOS, Android, Web"
+            value={this.state.options.join(", ")}
+            onChange={this.setOptions}
+          />
+        )}
+        <HStack>
+          <Button variant="primary" onClick={this.handleSave}>
+            Save
+          </Button>
+          <Button onClick={this.handleCancel} variant="tertiary">
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+}
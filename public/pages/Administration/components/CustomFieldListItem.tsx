@@ -0,0 +1,113 @@
+import React, { useState } from "react"
+import { CustomField } from "@fider/models"
+import { Button, Icon } from "@fider/components"
+import { CustomFieldFormState, CustomFieldForm } from "./CustomFieldForm"
+import { actions, Failure } from "@fider/services"
+import { useFider } from "@fider/hooks"
+
+import IconX from "@fider/assets/images/heroicons-x.svg"
+import IconPencilAlt from "@fider/assets/images/heroicons-pencil-alt.svg"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface CustomFieldListItemProps {
+  field: CustomField
+  onFieldEdited: (field: CustomField) => void
+  onFieldDeleted: (field: CustomField) => void
+}
+
+export const CustomFieldListItem = (props: CustomFieldListItemProps) => {
+  const fider = useFider()
+  const [field] = useState(props.field)
+  const [state, setState] = useState<"view" | "edit" | "delete">("view")
+
+  const startDelete = async () => setState("delete")
+  const startEdit = async () => setState("edit")
+  const resetState = async () => setState("view")
+
+  const deleteField = async () => {
+    const result = await actions.deleteCustomField(field.key)
+    if (result.ok) {
+      resetState()
+      props.onFieldDeleted(field)
+    }
+  }
+
+  const updateField = async (data: CustomFieldFormState): Promise<Failure | undefined> => {
+    const result = await actions.updateCustomField(field.key, { ...data, position: field.position })
+    if (result.ok) {
+      field.name = result.data.name
+      field.options = result.data.options
+      field.isPublic = result.data.isPublic
+
+      resetState()
+      props.onFieldEdited(field)
+    } else {
+      return result.error
+    }
+  }
+
+  const renderDeleteMode = () => {
+    return (
+      <VStack spacing={2}>
+        <div>
+          <b>Are you sure?</b>{" "}
+          <span>
+            The field <strong>{field.name}</strong> and its values will be removed from all posts.
+          </span>
+        </div>
+        <div>
+          <Button variant="danger" onClick={deleteField}>
+            Delete field
+          </Button>
+          <Button onClick={resetState} variant="tertiary">
+            Cancel
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  const renderViewMode = () => {
+    const buttons = fider.session.user.isAdministrator && [
+      <Button size="small" key={0} onClick={startEdit}>
+        <Icon sprite={IconPencilAlt} />
+        <span>Edit</span>
+      </Button>,
+      <Button size="small" key={1} onClick={startDelete}>
+        <Icon sprite={IconX} />
+        <span>Delete</span>
+      </Button>,
+    ]
+
+    return (
+      <HStack justify="between">
+        <VStack spacing={1}>
+          <span>
+            <strong>{field.name}</strong> <span className="text-muted text-xs">{field.key}</span>
+          </span>
+          <span className="text-muted text-sm">
+            {field.type}
+            {field.options.length > 0 && `: ${field.options.join(", ")}`}
+          </span>
+        </VStack>
+        <HStack>{buttons}</HStack>
+      </HStack>
+    )
+  }
+
+  const renderEditMode = () => {
+    return (
+      <CustomFieldForm
+        name={field.name}
+        type={field.type}
+        options={field.options}
+        isPublic={field.isPublic}
+        isEditing={true}
+        onSave={updateField}
+        onCancel={resetState}
+      />
+    )
+  }
+
+  return state === "delete" ? renderDeleteMode() : state === "edit" ? renderEditMode() : renderViewMode()
+}
@@ -0,0 +1,81 @@
+import React from "react"
+import { Button, Input, Form, Checkbox } from "@fider/components"
+import { Permission } from "@fider/models"
+import { Failure } from "@fider/services"
+import { HStack, VStack } from "@fider/components/layout"
+
+export const permissionDescriptions: { [key in Permission]: string } = {
+  "posts:respond": "Respond to posts, change their status, merge and tag them",
+  "tags:manage": "Create, edit and delete tags",
+  "roadmap:manage": "Move posts on the roadmap",
+  "comments:moderate": "Edit and delete comments of other users",
+  "voters:emails": "See the email of voters",
+  "webhooks:manage": "Manage webhooks",
+  "data:export": "Export posts to CSV",
+}
+
+interface CustomRoleFormProps {
+  name?: string
+  permissions?: Permission[]
+  onSave: (data: CustomRoleFormState) => Promise<Failure | undefined>
+  onCancel: () => void
+}
+
+export interface CustomRoleFormState {
+  name: string
+  permissions: Permission[]
+  error?: Failure
+}
+
+export class CustomRoleForm extends React.Component<CustomRoleFormProps, CustomRoleFormState> {
+  constructor(props: CustomRoleFormProps) {
+    super(props)
+    this.state = {
+      name: props.name || "",
+      permissions: props.permissions || [],
+    }
+  }
+
+  private handleSave = async () => {
+    const error = await this.props.onSave(this.state)
+    if (error) {
+      this.setState({ error })
+    }
+  }
+
+  private handleCancel = async () => {
+    this.props.onCancel()
+  }
+
+  private setName = (name: string) => {
+    this.setState({ name })
+  }
+
+  private togglePermission = (permission: Permission) => (checked: boolean) => {
+    const permissions = this.state.permissions.filter((p) => p !== permission)
+    this.setState({ permissions: checked ? permissions.concat(permission) : permissions })
+  }
+
+  public render() {
+    return (
+      <Form error={this.state.error}>
+        <Input field="name" label="Name" placeholder="e.g. Support Agent" maxLength={50} value={this.state.name} onChange={this.setName} />
+        <VStack spacing={1}>
+          {(Object.keys(permissionDescriptions) as Permission[]).map((permission) => (
+            <Checkbox key={permission} field={`permission-${permission}`} checked={this.state.permissions.includes(permission)} onChange={this.togglePermission(permission)}>
+              {permissionDescriptions[permission]}
+            </Checkbox>
+          ))}
+        </VStack>
+        <HStack>
+          <Button variant="primary" onClick={this.handleSave}>
+            Save
+          </Button>
+          <Button onClick={this.handleCancel} variant="tertiary">
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+}
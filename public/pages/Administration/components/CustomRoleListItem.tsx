@@ -0,0 +1,100 @@
+import React, { useState } from "react"
+import { CustomRole } from "@fider/models"
+import { Button, Icon } from "@fider/components"
+import { CustomRoleFormState, CustomRoleForm, permissionDescriptions } from "./CustomRoleForm"
+import { actions, Failure } from "@fider/services"
+
+import IconX from "@fider/assets/images/heroicons-x.svg"
+import IconPencilAlt from "@fider/assets/images/heroicons-pencil-alt.svg"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface CustomRoleListItemProps {
+  role: CustomRole
+  onRoleEdited: (role: CustomRole) => void
+  onRoleDeleted: (role: CustomRole) => void
+}
+
+export const CustomRoleListItem = (props: CustomRoleListItemProps) => {
+  const [role] = useState(props.role)
+  const [state, setState] = useState<"view" | "edit" | "delete">("view")
+
+  const startDelete = async () => setState("delete")
+  const startEdit = async () => setState("edit")
+  const resetState = async () => setState("view")
+
+  const deleteRole = async () => {
+    const result = await actions.deleteCustomRole(role.id)
+    if (result.ok) {
+      resetState()
+      props.onRoleDeleted(role)
+    }
+  }
+
+  const updateRole = async (data: CustomRoleFormState): Promise<Failure | undefined> => {
+    const result = await actions.updateCustomRole(role.id, data)
+    if (result.ok) {
+      role.name = result.data.name
+      role.permissions = result.data.permissions
+
+      resetState()
+      props.onRoleEdited(role)
+    } else {
+      return result.error
+    }
+  }
+
+  const renderDeleteMode = () => {
+    return (
+      <VStack spacing={2}>
+        <div>
+          <b>Are you sure?</b>{" "}
+          <span>
+            The role <strong>{role.name}</strong> will be deleted and its users will be demoted to visitors.
+          </span>
+        </div>
+        <div>
+          <Button variant="danger" onClick={deleteRole}>
+            Delete role
+          </Button>
+          <Button onClick={resetState} variant="tertiary">
+            Cancel
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  const renderViewMode = () => {
+    return (
+      <HStack justify="between">
+        <VStack spacing={1}>
+          <span>
+            <strong>{role.name}</strong>{" "}
+            <span className="text-muted text-xs">
+              {role.usersCount} {role.usersCount === 1 ? "user" : "users"}
+            </span>
+          </span>
+          <span className="text-muted text-sm">
+            {role.permissions.length === 0 ? "No permissions" : role.permissions.map((p) => permissionDescriptions[p]).join(" · ")}
+          </span>
+        </VStack>
+        <HStack>
+          <Button size="small" onClick={startEdit}>
+            <Icon sprite={IconPencilAlt} />
+            <span>Edit</span>
+          </Button>
+          <Button size="small" onClick={startDelete}>
+            <Icon sprite={IconX} />
+            <span>Delete</span>
+          </Button>
+        </HStack>
+      </HStack>
+    )
+  }
+
+  const renderEditMode = () => {
+    return <CustomRoleForm name={role.name} permissions={role.permissions} onSave={updateRole} onCancel={resetState} />
+  }
+
+  return state === "delete" ? renderDeleteMode() : state === "edit" ? renderEditMode() : renderViewMode()
+}
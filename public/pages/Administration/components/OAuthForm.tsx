@@ -27,6 +27,7 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
   const [jsonUserIDPath, setJSONUserIDPath] = useState((props.config && props.config.jsonUserIDPath) || "")
   const [jsonUserNamePath, setJSONUserNamePath] = useState((props.config && props.config.jsonUserNamePath) || "")
   const [jsonUserEmailPath, setJSONUserEmailPath] = useState((props.config && props.config.jsonUserEmailPath) || "")
+  const [jsonUserGroupsPath, setJSONUserGroupsPath] = useState((props.config && props.config.jsonUserGroupsPath) || "")
   const [logo, setLogo] = useState<ImageUpload | undefined>()
   const [logoURL, setLogoURL] = useState<string | undefined>()
   const [logoBlobKey, setLogoBlobKey] = useState((props.config && props.config.logoBlobKey) || "")
@@ -47,6 +48,7 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
       jsonUserIDPath,
       jsonUserNamePath,
       jsonUserEmailPath,
+      jsonUserGroupsPath,
       logo,
     })
     if (result.ok) {
@@ -157,7 +159,7 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
           .
         </p>
 
-        <div className="grid grid-cols-3 gap-4">
+        <div className="grid grid-cols-4 gap-4">
           <Input
             field="jsonUserIDPath"
             label="ID"
@@ -192,6 +194,16 @@ export const OAuthForm: React.FC<OAuthFormProps> = (props) => {
               Optional, but <strong>highly</strong> recommended.
             </p>
           </Input>
+          <Input
+            field="jsonUserGroupsPath"
+            label="Groups"
+            maxLength={100}
+            value={jsonUserGroupsPath}
+            disabled={!fider.session.user.isAdministrator}
+            onChange={setJSONUserGroupsPath}
+          >
+            <p className="text-muted">Optional, used to assign users to groups.</p>
+          </Input>
         </div>
 
         <Field label="Trusted Source">
@@ -4,6 +4,7 @@ import React, { useState } from "react"
 import { classSet } from "@fider/services"
 import { Icon } from "@fider/components"
 import { useFider } from "@fider/hooks"
+import { hasPermission } from "@fider/models"
 import IconX from "@fider/assets/images/heroicons-x.svg"
 import IconMenu from "@fider/assets/images/heroicons-menu.svg"
 import { VStack } from "@fider/components/layout"
@@ -42,8 +43,11 @@ export const SideMenu = (props: SiteMenuProps) => {
       <VStack spacing={0} className="c-side-menu rounded-md shadow bg-white">
         <SideMenuItem name="general" title="General" href="/admin" isActive={activeItem === "general"} />
         <SideMenuItem name="privacy" title="Privacy" href="/admin/privacy" isActive={activeItem === "privacy"} />
+        <SideMenuItem name="voting" title="Voting" href="/admin/voting" isActive={activeItem === "voting"} />
         <SideMenuItem name="members" title="Members" href="/admin/members" isActive={activeItem === "members"} />
         <SideMenuItem name="tags" title="Tags" href="/admin/tags" isActive={activeItem === "tags"} />
+        <SideMenuItem name="custom-fields" title="Custom Fields" href="/admin/custom-fields" isActive={activeItem === "custom-fields"} />
+        <SideMenuItem name="companies" title="Companies" href="/admin/companies" isActive={activeItem === "companies"} />
         <SideMenuItem name="roadmap" title="Roadmap" href="/admin/roadmap" isActive={activeItem === "roadmap"} />
         <SideMenuItem name="invitations" title="Invitations" href="/admin/invitations" isActive={activeItem === "invitations"} />
         <SideMenuItem name="authentication" title="Authentication" href="/admin/authentication" isActive={activeItem === "authentication"} />
@@ -51,10 +55,25 @@ export const SideMenu = (props: SiteMenuProps) => {
         {fider.session.user.isAdministrator && (
           <>
             {fider.settings.isBillingEnabled && <SideMenuItem name="billing" title="Billing" href="/admin/billing" isActive={activeItem === "billing"} />}
-            <SideMenuItem name="webhooks" title="Webhooks" href="/admin/webhooks" isActive={activeItem === "webhooks"} />
-            <SideMenuItem name="export" title="Export" href="/admin/export" isActive={activeItem === "export"} />
+            <SideMenuItem name="roles" title="Roles" href="/admin/roles" isActive={activeItem === "roles"} />
+            <SideMenuItem name="groups" title="Groups" href="/admin/groups" isActive={activeItem === "groups"} />
           </>
         )}
+        {hasPermission(fider.session.user, "webhooks:manage") && (
+          <SideMenuItem name="webhooks" title="Webhooks" href="/admin/webhooks" isActive={activeItem === "webhooks"} />
+        )}
+        {fider.session.user.isAdministrator && (
+          <>
+            <SideMenuItem name="api-tokens" title="API Tokens" href="/admin/api-tokens" isActive={activeItem === "api-tokens"} />
+            <SideMenuItem name="oauth-apps" title="OAuth Apps" href="/admin/oauth-apps" isActive={activeItem === "oauth-apps"} />
+            <SideMenuItem name="saml" title="SAML SSO" href="/admin/saml" isActive={activeItem === "saml"} />
+            <SideMenuItem name="audit-log" title="Audit Log" href="/admin/audit-log" isActive={activeItem === "audit-log"} />
+            <SideMenuItem name="email-suppressions" title="Email Suppressions" href="/admin/email-suppressions" isActive={activeItem === "email-suppressions"} />
+            <SideMenuItem name="email-templates" title="Email Templates" href="/admin/email-templates" isActive={activeItem === "email-templates"} />
+            <SideMenuItem name="email-deliveries" title="Email Deliveries" href="/admin/email-deliveries" isActive={activeItem === "email-deliveries"} />
+          </>
+        )}
+        {hasPermission(fider.session.user, "data:export") && <SideMenuItem name="export" title="Export" href="/admin/export" isActive={activeItem === "export"} />}
       </VStack>
     </div>
   )
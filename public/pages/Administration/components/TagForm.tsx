@@ -1,5 +1,6 @@
 import React from "react"
-import { Button, Input, ShowTag, Form, RadioButton, Field, SelectOption } from "@fider/components"
+import { Button, Input, ShowTag, Form, RadioButton, Field, Select, SelectOption } from "@fider/components"
+import { UserGroup } from "@fider/models"
 import { Failure } from "@fider/services"
 import { HStack } from "@fider/components/layout"
 
@@ -7,6 +8,8 @@ interface TagFormProps {
   name?: string
   color?: string
   isPublic?: boolean
+  groupId?: number
+  groups: UserGroup[]
   onSave: (data: TagFormState) => Promise<Failure | undefined>
   onCancel: () => void
 }
@@ -15,6 +18,7 @@ export interface TagFormState {
   name: string
   color: string
   isPublic: boolean
+  groupId?: number
   error?: Failure
 }
 
@@ -28,6 +32,7 @@ export class TagForm extends React.Component<TagFormProps, TagFormState> {
       color: props.color || this.getRandomColor(),
       name: props.name || "",
       isPublic: props.isPublic || false,
+      groupId: props.groupId,
     }
   }
 
@@ -63,6 +68,10 @@ export class TagForm extends React.Component<TagFormProps, TagFormState> {
     this.setState({ isPublic: option === this.visibilityPublic })
   }
 
+  private setGroup = (option?: SelectOption) => {
+    this.setState({ groupId: option && option.value ? parseInt(option.value, 10) : undefined })
+  }
+
   private randomize = () => {
     this.setColor(this.getRandomColor())
   }
@@ -76,7 +85,7 @@ export class TagForm extends React.Component<TagFormProps, TagFormState> {
 
     return (
       <Form error={this.state.error}>
-        <div className="grid gap-2 lg:grid-cols-5">
+        <div className="grid gap-2 lg:grid-cols-6">
           <Input field="name" label="Name" value={this.state.name} onChange={this.setName} />
           <Input field="color" label="Color" afterLabel={randomizer} value={this.state.color} onChange={this.setColor} />
           <RadioButton
@@ -86,6 +95,15 @@ export class TagForm extends React.Component<TagFormProps, TagFormState> {
             options={[this.visibilityPublic, this.visibilityPrivate]}
             onSelect={this.setVisibility}
           />
+          {this.props.groups.length > 0 && (
+            <Select
+              field="groupId"
+              label="Group"
+              defaultValue={this.state.groupId ? this.state.groupId.toString() : ""}
+              options={[{ value: "", label: "Everyone" }].concat(this.props.groups.map((g) => ({ value: g.id.toString(), label: g.name })))}
+              onChange={this.setGroup}
+            />
+          )}
           <Field label="Preview">
             <ShowTag
               tag={{
@@ -0,0 +1,75 @@
+import React from "react"
+import { Button, Input, Form } from "@fider/components"
+import { Failure } from "@fider/services"
+import { HStack } from "@fider/components/layout"
+
+interface UserGroupFormProps {
+  name?: string
+  emailDomains?: string[]
+  oauthClaim?: string
+  onSave: (data: UserGroupFormState) => Promise<Failure | undefined>
+  onCancel: () => void
+}
+
+export interface UserGroupFormState {
+  name: string
+  emailDomains: string[]
+  oauthClaim: string
+  error?: Failure
+}
+
+export class UserGroupForm extends React.Component<UserGroupFormProps, UserGroupFormState> {
+  constructor(props: UserGroupFormProps) {
+    super(props)
+    this.state = {
+      name: props.name || "",
+      emailDomains: props.emailDomains || [],
+      oauthClaim: props.oauthClaim || "",
+    }
+  }
+
+  private handleSave = async () => {
+    const error = await this.props.onSave(this.state)
+    if (error) {
+      this.setState({ error })
+    }
+  }
+
+  private handleCancel = async () => {
+    this.props.onCancel()
+  }
+
+  private setName = (name: string) => {
+    this.setState({ name })
+  }
+
+  private setEmailDomains = (value: string) => {
+    this.setState({ emailDomains: value.split(",").map((d) => d.trim()) })
+  }
+
+  private setOAuthClaim = (oauthClaim: string) => {
+    this.setState({ oauthClaim })
+  }
+
+  public render() {
+    return (
+      <Form error={this.state.error}>
+        <Input field="name" label="Name" placeholder="e.g. Beta Testers" maxLength={50} value={this.state.name} onChange={this.setName} />
+        <Input field="emailDomains" label="Email domains" placeholder="e.g. acme.com, globex.com" value={this.state.emailDomains.join(", ")} onChange={this.setEmailDomains}>
+          <p className="text-muted">Users with an email address on one of these domains are automatically members of this group.</p>
+        </Input>
+        <Input field="oauthClaim" label="OAuth claim" placeholder="e.g. beta-testers" maxLength={100} value={this.state.oauthClaim} onChange={this.setOAuthClaim}>
+          <p className="text-muted">Users signing in with an OAuth provider that reports this value in its groups path become members of this group.</p>
+        </Input>
+        <HStack>
+          <Button variant="primary" onClick={this.handleSave}>
+            Save
+          </Button>
+          <Button onClick={this.handleCancel} variant="tertiary">
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+}
@@ -0,0 +1,167 @@
+import React, { useState } from "react"
+import { User, UserGroup } from "@fider/models"
+import { Avatar, Button, Icon, Select, SelectOption, UserName } from "@fider/components"
+import { UserGroupFormState, UserGroupForm } from "./UserGroupForm"
+import { actions, Failure } from "@fider/services"
+
+import IconX from "@fider/assets/images/heroicons-x.svg"
+import IconPencilAlt from "@fider/assets/images/heroicons-pencil-alt.svg"
+import { HStack, VStack } from "@fider/components/layout"
+
+interface UserGroupListItemProps {
+  group: UserGroup
+  users: User[]
+  onGroupEdited: (group: UserGroup) => void
+  onGroupDeleted: (group: UserGroup) => void
+}
+
+export const UserGroupListItem = (props: UserGroupListItemProps) => {
+  const [group] = useState(props.group)
+  const [members, setMembers] = useState<User[]>([])
+  const [state, setState] = useState<"view" | "edit" | "delete" | "members">("view")
+
+  const startDelete = async () => setState("delete")
+  const startEdit = async () => setState("edit")
+  const resetState = async () => setState("view")
+
+  const loadMembers = async () => {
+    const result = await actions.listUserGroupMembers(group.id)
+    if (result.ok) {
+      setMembers(result.data)
+      group.membersCount = result.data.length
+      setState("members")
+    }
+  }
+
+  const deleteGroup = async () => {
+    const result = await actions.deleteUserGroup(group.id)
+    if (result.ok) {
+      resetState()
+      props.onGroupDeleted(group)
+    }
+  }
+
+  const updateGroup = async (data: UserGroupFormState): Promise<Failure | undefined> => {
+    const result = await actions.updateUserGroup(group.id, data)
+    if (result.ok) {
+      group.name = result.data.name
+      group.emailDomains = result.data.emailDomains
+      group.oauthClaim = result.data.oauthClaim
+
+      resetState()
+      props.onGroupEdited(group)
+    } else {
+      return result.error
+    }
+  }
+
+  const addMember = async (option?: SelectOption) => {
+    if (option) {
+      const result = await actions.addUserGroupMember(group.id, parseInt(option.value, 10))
+      if (result.ok) {
+        await loadMembers()
+      }
+    }
+  }
+
+  const removeMember = (user: User) => async () => {
+    const result = await actions.removeUserGroupMember(group.id, user.id)
+    if (result.ok) {
+      await loadMembers()
+    }
+  }
+
+  const renderDeleteMode = () => {
+    return (
+      <VStack spacing={2}>
+        <div>
+          <b>Are you sure?</b>{" "}
+          <span>
+            The group <strong>{group.name}</strong> will be deleted and everything restricted to it will become visible to everyone.
+          </span>
+        </div>
+        <div>
+          <Button variant="danger" onClick={deleteGroup}>
+            Delete group
+          </Button>
+          <Button onClick={resetState} variant="tertiary">
+            Cancel
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  const renderMembersMode = () => {
+    const options = [{ value: "", label: "Add a member..." }].concat(
+      props.users.filter((u) => !members.some((m) => m.id === u.id)).map((u) => ({ value: u.id.toString(), label: u.name }))
+    )
+
+    return (
+      <VStack spacing={2}>
+        <strong>{group.name}</strong>
+        {members.length === 0 && <p className="text-muted">This group doesn’t have any members yet.</p>}
+        {members.map((m) => (
+          <HStack key={m.id} justify="between">
+            <HStack>
+              <Avatar user={m} />
+              <UserName user={m} />
+            </HStack>
+            <Button size="small" onClick={removeMember(m)}>
+              <Icon sprite={IconX} />
+              <span>Remove</span>
+            </Button>
+          </HStack>
+        ))}
+        <Select key={members.length} field="member" options={options} onChange={addMember}>
+          <p className="text-muted">Members matched by email domain can’t be removed individually.</p>
+        </Select>
+        <div>
+          <Button onClick={resetState} variant="tertiary">
+            Close
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  const renderViewMode = () => {
+    const rules = [
+      ...group.emailDomains.map((d) => `@${d}`),
+      ...(group.oauthClaim ? [`OAuth: ${group.oauthClaim}`] : []),
+    ]
+
+    return (
+      <HStack justify="between">
+        <VStack spacing={1}>
+          <span>
+            <strong>{group.name}</strong>{" "}
+            <span className="text-muted text-xs">
+              {group.membersCount} {group.membersCount === 1 ? "member" : "members"}
+            </span>
+          </span>
+          <span className="text-muted text-sm">{rules.length === 0 ? "Manually assigned members only" : rules.join(" · ")}</span>
+        </VStack>
+        <HStack>
+          <Button size="small" onClick={loadMembers}>
+            <span>Members</span>
+          </Button>
+          <Button size="small" onClick={startEdit}>
+            <Icon sprite={IconPencilAlt} />
+            <span>Edit</span>
+          </Button>
+          <Button size="small" onClick={startDelete}>
+            <Icon sprite={IconX} />
+            <span>Delete</span>
+          </Button>
+        </HStack>
+      </HStack>
+    )
+  }
+
+  const renderEditMode = () => {
+    return <UserGroupForm name={group.name} emailDomains={group.emailDomains} oauthClaim={group.oauthClaim} onSave={updateGroup} onCancel={resetState} />
+  }
+
+  return state === "delete" ? renderDeleteMode() : state === "edit" ? renderEditMode() : state === "members" ? renderMembersMode() : renderViewMode()
+}
@@ -88,6 +88,7 @@ export const WebhookForm = (props: WebhookFormProps) => {
   const [content, setContent] = useState(props.webhook?.content || "")
   const [httpMethod, setHttpMethod] = useState(props.webhook?.http_method || "POST")
   const [httpHeaders, _setHttpHeaders] = useState(props.webhook?.http_headers || {})
+  const [isInternal, setIsInternal] = useState(props.webhook?.is_internal || false)
   const [typing, setTyping] = useState<NodeJS.Timeout | undefined>()
   const [preview, setPreview] = useState<WebhookPreviewResult | null | undefined>()
   const [isModalOpen, setIsModalOpen] = useState(false)
@@ -116,7 +117,7 @@ export const WebhookForm = (props: WebhookFormProps) => {
   }, [url, content])
 
   const handleSave = async () => {
-    const error = await props.onSave({ name, type, status, url, content, http_method: httpMethod, http_headers: httpHeaders })
+    const error = await props.onSave({ name, type, status, url, content, http_method: httpMethod, http_headers: httpHeaders, is_internal: isInternal })
     if (error) {
       setError(error)
     }
@@ -171,6 +172,9 @@ export const WebhookForm = (props: WebhookFormProps) => {
           <Toggle active={status === WebhookStatus.ENABLED} onToggle={setStatus} />
           {status === WebhookStatus.FAILED && <p className="text-muted mt-1">This webhook was disabled due to a trigger failure</p>}
         </Field>
+        <Field label="Internal" afterLabel={<HoverInfo text="Internal webhooks also receive private posts and internal comments" />}>
+          <Toggle active={isInternal} onToggle={setIsInternal} />
+        </Field>
         <Input
           field="url"
           label="URL"
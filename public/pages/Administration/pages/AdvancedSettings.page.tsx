@@ -7,6 +7,7 @@ import { AdminBasePage } from "../components/AdminBasePage"
 interface AdvancedSettingsPageProps {
   customCSS: string
   allowedSchemes: string
+  postByEmailAddress: string
 }
 
 interface AdvancedSettingsPageState {
@@ -97,6 +98,19 @@ export default class AdvancedSettingsPage extends AdminBasePage<AdvancedSettings
           </TextArea>
         )}
 
+        {this.props.postByEmailAddress && (
+          <div className="field">
+            <label>Post by Email</label>
+            <p>
+              <code>{this.props.postByEmailAddress}</code>
+            </p>
+            <p className="text-muted">
+              Emails sent to this address are submitted as new posts, using the subject as title and the body as description. Image attachments are added to
+              the post and the sender receives a confirmation email.
+            </p>
+          </div>
+        )}
+
         {Fider.session.user.isAdministrator && (
           <div className="field">
             <Button variant="primary" onClick={this.handleSave}>
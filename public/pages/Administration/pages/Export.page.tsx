@@ -1,6 +1,7 @@
 import React from "react"
 
 import { Button, Icon } from "@fider/components"
+import { Fider } from "@fider/services"
 import { AdminBasePage } from "../components/AdminBasePage"
 import IconDownload from "@fider/assets/images/heroicons-download.svg"
 
@@ -23,16 +24,18 @@ export default class ExportPage extends AdminBasePage<any, any> {
           <span>posts.csv</span>
         </Button>
 
-        <div className="mt-8">
-          <h2 className="text-display">Backup your data</h2>
-          <p className="text-muted">
-            Use this button to download a ZIP file with your data in JSON format. This is a full backup and contains all of your data.
-          </p>
-          <Button variant="secondary" href="/admin/export/backup.zip">
-            <Icon sprite={IconDownload} />
-            <span>backup.zip</span>
-          </Button>
-        </div>
+        {Fider.session.user.isAdministrator && (
+          <div className="mt-8">
+            <h2 className="text-display">Backup your data</h2>
+            <p className="text-muted">
+              Use this button to download a ZIP file with your data in JSON format. This is a full backup and contains all of your data.
+            </p>
+            <Button variant="secondary" href="/admin/export/backup.zip">
+              <Icon sprite={IconDownload} />
+              <span>backup.zip</span>
+            </Button>
+          </div>
+        )}
       </>
     )
   }
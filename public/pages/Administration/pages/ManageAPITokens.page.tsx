@@ -0,0 +1,80 @@
+import React from "react"
+import { Avatar, Button, Moment, UserName } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { APIToken } from "@fider/models"
+import { actions, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageAPITokensPageProps {
+  tokens: APIToken[]
+}
+
+interface ManageAPITokensPageState {
+  tokens: APIToken[]
+}
+
+export default class ManageAPITokensPage extends AdminBasePage<ManageAPITokensPageProps, ManageAPITokensPageState> {
+  public id = "p-admin-api-tokens"
+  public name = "api-tokens"
+  public title = "API Tokens"
+  public subtitle = "Review and revoke the API tokens of your members"
+
+  constructor(props: ManageAPITokensPageProps) {
+    super(props)
+    this.state = {
+      tokens: this.props.tokens,
+    }
+  }
+
+  private revoke = async (token: APIToken) => {
+    const result = await actions.revokeAPIToken(token.id)
+    if (result.ok) {
+      this.setState({ tokens: this.state.tokens.filter((t) => t.id !== token.id) })
+    }
+  }
+
+  public content() {
+    if (this.state.tokens.length === 0) {
+      return <p className="text-muted">There aren’t any API tokens yet. Members can create them on their settings page.</p>
+    }
+
+    return (
+      <VStack spacing={4} divide={true}>
+        {this.state.tokens.map((t) => (
+          <HStack key={t.id} justify="between">
+            <HStack>
+              {t.user && <Avatar user={t.user} />}
+              <VStack spacing={0}>
+                <span>
+                  <strong>{t.name}</strong> <code className="text-xs">{t.prefix}…</code> {t.user && <UserName user={t.user} />}
+                </span>
+                <span className="text-muted text-sm">
+                  {t.scopes.join(", ")}
+                  {" · "}
+                  {t.expiresAt ? (
+                    <>
+                      expires <Moment locale={Fider.currentLocale} date={t.expiresAt} />
+                    </>
+                  ) : (
+                    "never expires"
+                  )}
+                  {" · "}
+                  {t.lastUsedAt ? (
+                    <>
+                      last used <Moment locale={Fider.currentLocale} date={t.lastUsedAt} />
+                    </>
+                  ) : (
+                    "never used"
+                  )}
+                </span>
+              </VStack>
+            </HStack>
+            <Button size="small" onClick={() => this.revoke(t)}>
+              Revoke
+            </Button>
+          </HStack>
+        ))}
+      </VStack>
+    )
+  }
+}
@@ -0,0 +1,90 @@
+import React from "react"
+import { Moment, Select, SelectOption } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { AuditLogEntry, User } from "@fider/models"
+import { actions, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageAuditLogPageProps {
+  entries: AuditLogEntry[]
+  actions: string[]
+  actors: User[]
+}
+
+interface ManageAuditLogPageState {
+  entries: AuditLogEntry[]
+  action?: string
+  actorId?: number
+}
+
+const formatValues = (values?: { [key: string]: any }) => {
+  if (!values) {
+    return ""
+  }
+  return Object.keys(values)
+    .map((key) => `${key}: ${JSON.stringify(values[key])}`)
+    .join(", ")
+}
+
+export default class ManageAuditLogPage extends AdminBasePage<ManageAuditLogPageProps, ManageAuditLogPageState> {
+  public id = "p-admin-audit-log"
+  public name = "audit-log"
+  public title = "Audit Log"
+  public subtitle = "Review administrative changes made on this site"
+
+  constructor(props: ManageAuditLogPageProps) {
+    super(props)
+    this.state = {
+      entries: this.props.entries,
+    }
+  }
+
+  private search = async (action?: string, actorId?: number) => {
+    this.setState({ action, actorId })
+    const result = await actions.searchAuditLogs({ action, actorId })
+    if (result.ok) {
+      this.setState({ entries: result.data })
+    }
+  }
+
+  private actionChanged = (option?: SelectOption) => {
+    this.search(option && option.value ? option.value : undefined, this.state.actorId)
+  }
+
+  private actorChanged = (option?: SelectOption) => {
+    this.search(this.state.action, option && option.value ? parseInt(option.value, 10) : undefined)
+  }
+
+  public content() {
+    const actionOptions: SelectOption[] = [{ value: "", label: "All actions" }].concat(this.props.actions.map((a) => ({ value: a, label: a })))
+    const actorOptions: SelectOption[] = [{ value: "", label: "Everyone" }].concat(this.props.actors.map((u) => ({ value: u.id.toString(), label: u.name })))
+
+    return (
+      <>
+        <HStack spacing={4}>
+          <Select field="action" label="Action" defaultValue="" options={actionOptions} onChange={this.actionChanged} />
+          <Select field="actorId" label="Changed by" defaultValue="" options={actorOptions} onChange={this.actorChanged} />
+        </HStack>
+        {this.state.entries.length === 0 ? (
+          <p className="text-muted">No changes have been recorded yet.</p>
+        ) : (
+          <VStack spacing={4} divide={true}>
+            {this.state.entries.map((e) => (
+              <VStack key={e.id} spacing={0}>
+                <span>
+                  <strong>{e.actorName || "System"}</strong> <code className="text-xs">{e.action}</code> {e.targetName}
+                </span>
+                {e.before && <span className="text-muted text-sm">Before: {formatValues(e.before)}</span>}
+                {e.after && <span className="text-muted text-sm">After: {formatValues(e.after)}</span>}
+                <span className="text-muted text-sm">
+                  <Moment locale={Fider.currentLocale} date={e.createdAt} />
+                  {e.ipAddress && ` · ${e.ipAddress}`}
+                </span>
+              </VStack>
+            ))}
+          </VStack>
+        )}
+      </>
+    )
+  }
+}
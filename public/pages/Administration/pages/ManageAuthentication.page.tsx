@@ -18,6 +18,7 @@ interface ManageAuthenticationPageProps {
 interface ManageAuthenticationPageState {
   isAdding: boolean
   isEmailAuthAllowed: boolean
+  isTwoFactorRequired: boolean
   canDisableEmailAuth: boolean
   editing?: OAuthConfig
   error?: Failure
@@ -34,6 +35,7 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
     this.state = {
       isAdding: false,
       isEmailAuthAllowed: Fider.session.tenant.isEmailAuthAllowed,
+      isTwoFactorRequired: Fider.session.tenant.isTwoFactorRequired,
       canDisableEmailAuth: props.providers.map((o) => o.isEnabled).reduce((a, b) => a || b, false),
     }
   }
@@ -82,6 +84,26 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
     )
   }
 
+  private toggleTwoFactorRequired = async (active: boolean) => {
+    const response = await actions.updateTenantTwoFactorRequired(active)
+    if (response.ok) {
+      this.setState({ isTwoFactorRequired: active, error: undefined })
+      notify.success(`You successfully changed two-factor authentication setting.`)
+    } else {
+      this.setState({ error: response.error })
+      notify.error("Unable to save this setting.")
+    }
+  }
+
+  private revokeAllSessions = async () => {
+    const response = await actions.revokeAllSessions()
+    if (response.ok) {
+      notify.success("All other users have been signed out.")
+    } else {
+      notify.error("Unable to sign out users.")
+    }
+  }
+
   public content() {
     let enabledProvidersCount = 0
     for (const o of this.props.providers) {
@@ -124,6 +146,27 @@ export default class ManageAuthenticationPage extends AdminBasePage<ManageAuthen
               </p>
               <p className="text-muted mt-1">Note: Administrator accounts will still be allowed to sign in using their email.</p>
             </Field>
+            <Field label="Require Two-Factor Authentication for Staff" className="mt-2">
+              <Toggle
+                field="isTwoFactorRequired"
+                label={this.state.isTwoFactorRequired ? "Yes" : "No"}
+                disabled={!Fider.session.user.isAdministrator}
+                active={this.state.isTwoFactorRequired}
+                onToggle={this.toggleTwoFactorRequired}
+              />
+              <p className="text-muted my-1">
+                When required, collaborators and administrators must enable two-factor authentication on their account before they can continue using this site.
+                You can see who has enabled it on the Members page.
+              </p>
+            </Field>
+            {Fider.session.user.isAdministrator && (
+              <Field label="Sessions" className="mt-2">
+                <p className="text-muted my-1">Sign out every user of this site from all their browsers. You will remain signed in.</p>
+                <Button variant="danger" size="small" onClick={this.revokeAllSessions}>
+                  Sign out all users
+                </Button>
+              </Field>
+            )}
           </Form>
         </div>
         <div>
@@ -0,0 +1,95 @@
+import React from "react"
+import { Button } from "@fider/components"
+
+import { Company } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+import { CompanyFormState, CompanyForm } from "../components/CompanyForm"
+import { CompanyListItem } from "../components/CompanyListItem"
+import { VStack } from "@fider/components/layout"
+
+interface ManageCompaniesPageProps {
+  companies: Company[]
+}
+
+interface ManageCompaniesPageState {
+  isAdding: boolean
+  allCompanies: Company[]
+}
+
+export default class ManageCompaniesPage extends AdminBasePage<ManageCompaniesPageProps, ManageCompaniesPageState> {
+  public id = "p-admin-companies"
+  public name = "companies"
+  public title = "Companies"
+  public subtitle = "Manage the companies your users belong to"
+
+  constructor(props: ManageCompaniesPageProps) {
+    super(props)
+    this.state = {
+      isAdding: false,
+      allCompanies: this.props.companies,
+    }
+  }
+
+  private addNew = async () => {
+    this.setState({ isAdding: true })
+  }
+
+  private cancelAdd = () => {
+    this.setState({ isAdding: false })
+  }
+
+  private saveNewCompany = async (data: CompanyFormState): Promise<Failure | undefined> => {
+    const result = await actions.createCompany(data)
+    if (result.ok) {
+      this.setState({
+        isAdding: false,
+        allCompanies: this.state.allCompanies.concat({ ...result.data, usersCount: 0 }),
+      })
+    } else {
+      return result.error
+    }
+  }
+
+  private handleCompanyDeleted = (company: Company) => {
+    this.setState({
+      allCompanies: this.state.allCompanies.filter((c) => c.id !== company.id),
+    })
+  }
+
+  private handleCompanyEdited = () => {
+    this.setState({
+      allCompanies: [...this.state.allCompanies],
+    })
+  }
+
+  public content() {
+    const list = this.state.allCompanies.map((c) => (
+      <CompanyListItem key={c.id} company={c} onCompanyDeleted={this.handleCompanyDeleted} onCompanyEdited={this.handleCompanyEdited} />
+    ))
+
+    const form =
+      Fider.session.user.isAdministrator &&
+      (this.state.isAdding ? (
+        <CompanyForm onSave={this.saveNewCompany} onCancel={this.cancelAdd} />
+      ) : (
+        <Button variant="secondary" onClick={this.addNew}>
+          Add new
+        </Button>
+      ))
+
+    return (
+      <VStack spacing={8}>
+        <div>
+          <p className="text-muted">
+            Companies are only visible to members of this site. The MRR of the companies that voted on a post can be used to sort posts by revenue.
+          </p>
+          <VStack spacing={4} divide={true}>
+            {list.length === 0 ? <p className="text-muted">There aren’t any companies yet.</p> : list}
+          </VStack>
+        </div>
+        <div>{form}</div>
+      </VStack>
+    )
+  }
+}
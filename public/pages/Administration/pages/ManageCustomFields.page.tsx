@@ -0,0 +1,106 @@
+import React from "react"
+import { Button } from "@fider/components"
+
+import { CustomField } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+import { CustomFieldFormState, CustomFieldForm } from "../components/CustomFieldForm"
+import { CustomFieldListItem } from "../components/CustomFieldListItem"
+import { VStack } from "@fider/components/layout"
+
+interface ManageCustomFieldsPageProps {
+  customFields: CustomField[]
+}
+
+interface ManageCustomFieldsPageState {
+  isAdding: boolean
+  allFields: CustomField[]
+}
+
+export default class ManageCustomFieldsPage extends AdminBasePage<ManageCustomFieldsPageProps, ManageCustomFieldsPageState> {
+  public id = "p-admin-custom-fields"
+  public name = "custom-fields"
+  public title = "Custom Fields"
+  public subtitle = "Manage the custom fields of your posts"
+
+  constructor(props: ManageCustomFieldsPageProps) {
+    super(props)
+    this.state = {
+      isAdding: false,
+      allFields: this.props.customFields,
+    }
+  }
+
+  private addNew = async () => {
+    this.setState({ isAdding: true })
+  }
+
+  private cancelAdd = () => {
+    this.setState({ isAdding: false })
+  }
+
+  private saveNewField = async (data: CustomFieldFormState): Promise<Failure | undefined> => {
+    const result = await actions.createCustomField(data)
+    if (result.ok) {
+      this.setState({
+        isAdding: false,
+        allFields: this.state.allFields.concat(result.data),
+      })
+    } else {
+      return result.error
+    }
+  }
+
+  private handleFieldDeleted = (field: CustomField) => {
+    this.setState({
+      allFields: this.state.allFields.filter((f) => f.id !== field.id),
+    })
+  }
+
+  private handleFieldEdited = () => {
+    this.setState({
+      allFields: [...this.state.allFields],
+    })
+  }
+
+  private getFieldList(filter: (field: CustomField) => boolean) {
+    return this.state.allFields.filter(filter).map((f) => {
+      return <CustomFieldListItem key={f.id} field={f} onFieldDeleted={this.handleFieldDeleted} onFieldEdited={this.handleFieldEdited} />
+    })
+  }
+
+  public content() {
+    const publicFieldList = this.getFieldList((f) => f.isPublic)
+    const privateFieldList = this.getFieldList((f) => !f.isPublic)
+
+    const form =
+      Fider.session.user.isAdministrator &&
+      (this.state.isAdding ? (
+        <CustomFieldForm onSave={this.saveNewField} onCancel={this.cancelAdd} />
+      ) : (
+        <Button variant="secondary" onClick={this.addNew}>
+          Add new
+        </Button>
+      ))
+
+    return (
+      <VStack spacing={8}>
+        <div>
+          <h2 className="text-display">Public Fields</h2>
+          <p className="text-muted">These fields and their values are visible to all visitors.</p>
+          <VStack spacing={4} divide={true}>
+            {publicFieldList.length === 0 ? <p className="text-muted">There aren’t any public fields yet.</p> : publicFieldList}
+          </VStack>
+        </div>
+        <div>
+          <h2 className="text-display">Staff-only Fields</h2>
+          <p className="text-muted">These fields and their values are only visible for members of this site.</p>
+          <VStack spacing={4} divide={true}>
+            {privateFieldList.length === 0 ? <p className="text-muted">There aren’t any staff-only fields yet.</p> : privateFieldList}
+          </VStack>
+        </div>
+        <div>{form}</div>
+      </VStack>
+    )
+  }
+}
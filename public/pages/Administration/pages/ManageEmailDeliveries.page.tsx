@@ -0,0 +1,95 @@
+import React from "react"
+import { Button, Form, Input, Moment } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { OutboundEmail, OutboundEmailStatus } from "@fider/models"
+import { actions, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageEmailDeliveriesPageProps {
+  address: string
+  deliveries: OutboundEmail[]
+}
+
+interface ManageEmailDeliveriesPageState {
+  address: string
+  searchedAddress: string
+  deliveries: OutboundEmail[]
+}
+
+const statuses: { [key in OutboundEmailStatus]: string } = {
+  queued: "Queued",
+  sent: "Sent",
+  failed: "Failed",
+  bounced: "Bounced",
+}
+
+export default class ManageEmailDeliveriesPage extends AdminBasePage<ManageEmailDeliveriesPageProps, ManageEmailDeliveriesPageState> {
+  public id = "p-admin-email-deliveries"
+  public name = "email-deliveries"
+  public title = "Email Deliveries"
+  public subtitle = "Find out what happened to the emails sent to an address"
+
+  constructor(props: ManageEmailDeliveriesPageProps) {
+    super(props)
+    this.state = {
+      address: this.props.address,
+      searchedAddress: this.props.address,
+      deliveries: this.props.deliveries,
+    }
+  }
+
+  private setAddress = (address: string) => {
+    this.setState({ address })
+  }
+
+  private search = async () => {
+    const address = this.state.address.trim()
+    const result = await actions.searchEmailDeliveries(address)
+    if (result.ok) {
+      this.setState({ searchedAddress: address, deliveries: result.data })
+    }
+  }
+
+  public content() {
+    return (
+      <>
+        <p className="text-muted">
+          Emails are queued and sent in the background within the rate limit of your email provider. Failed emails are retried a few times before giving up.
+          Emails are kept for 30 days.
+        </p>
+        <Form>
+          <Input field="address" label="Email address" placeholder="name@example.com" value={this.state.address} onChange={this.setAddress} />
+          <Button variant="primary" onClick={this.search}>
+            Search
+          </Button>
+        </Form>
+        {this.state.searchedAddress &&
+          (this.state.deliveries.length === 0 ? (
+            <p className="text-muted">No emails have been sent to {this.state.searchedAddress} in the last 30 days.</p>
+          ) : (
+            <VStack spacing={4} divide={true}>
+              {this.state.deliveries.map((d) => (
+                <HStack key={d.id} justify="between">
+                  <VStack spacing={0}>
+                    <strong>{d.subject}</strong>
+                    <span className="text-muted text-sm">
+                      <code className="text-xs">{d.templateName}</code> · Queued <Moment locale={Fider.currentLocale} date={d.createdAt} />
+                      {d.sentAt && (
+                        <>
+                          {" "}
+                          · Sent <Moment locale={Fider.currentLocale} date={d.sentAt} />
+                        </>
+                      )}
+                      {d.attempts > 0 && ` · Attempts: ${d.attempts}`}
+                    </span>
+                    {d.lastError && <span className="text-muted text-sm">Last error: {d.lastError}</span>}
+                  </VStack>
+                  <span className="text-sm">{statuses[d.status]}</span>
+                </HStack>
+              ))}
+            </VStack>
+          ))}
+      </>
+    )
+  }
+}
@@ -0,0 +1,97 @@
+import React from "react"
+import { Button, Form, Input, Moment } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { EmailSupressionReason, SupressedEmail } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageEmailSupressionsPageProps {
+  supressions: SupressedEmail[]
+}
+
+interface ManageEmailSupressionsPageState {
+  supressions: SupressedEmail[]
+  email: string
+  error?: Failure
+}
+
+const reasons: { [key in EmailSupressionReason]: string } = {
+  hard_bounce: "Hard bounce",
+  complaint: "Spam complaint",
+  manual: "Suppressed manually",
+}
+
+export default class ManageEmailSupressionsPage extends AdminBasePage<ManageEmailSupressionsPageProps, ManageEmailSupressionsPageState> {
+  public id = "p-admin-email-suppressions"
+  public name = "email-suppressions"
+  public title = "Email Suppressions"
+  public subtitle = "Review the members that no longer receive emails"
+
+  constructor(props: ManageEmailSupressionsPageProps) {
+    super(props)
+    this.state = {
+      supressions: this.props.supressions,
+      email: "",
+    }
+  }
+
+  private setEmail = (email: string) => {
+    this.setState({ email })
+  }
+
+  private supress = async () => {
+    const result = await actions.supressUserEmail(this.state.email)
+    if (result.ok) {
+      location.reload()
+    } else {
+      this.setState({ error: result.error })
+    }
+  }
+
+  private unsupress = async (supression: SupressedEmail) => {
+    const result = await actions.unsupressUserEmail(supression.userId)
+    if (result.ok) {
+      this.setState({ supressions: this.state.supressions.filter((s) => s.userId !== supression.userId) })
+    }
+  }
+
+  public content() {
+    return (
+      <>
+        <p className="text-muted">
+          Fider stops sending emails to addresses that bounced or marked an email as spam, so that your site keeps a good sending reputation. Only unsuppress
+          an address once you are sure it can receive emails again.
+        </p>
+        <Form error={this.state.error}>
+          <Input field="email" label="Suppress an email address" placeholder="name@example.com" value={this.state.email} onChange={this.setEmail}>
+            <p className="text-muted">The member with this email address will no longer receive any email from this site.</p>
+          </Input>
+          <Button variant="primary" onClick={this.supress}>
+            Suppress
+          </Button>
+        </Form>
+        {this.state.supressions.length === 0 ? (
+          <p className="text-muted">There aren’t any suppressed email addresses.</p>
+        ) : (
+          <VStack spacing={4} divide={true}>
+            {this.state.supressions.map((s) => (
+              <HStack key={s.userId} justify="between">
+                <VStack spacing={0}>
+                  <span>
+                    <strong>{s.userName}</strong> {s.email}
+                  </span>
+                  <span className="text-muted text-sm">
+                    {reasons[s.reason]} · <Moment locale={Fider.currentLocale} date={s.supressedAt} />
+                  </span>
+                </VStack>
+                <Button size="small" onClick={() => this.unsupress(s)}>
+                  Unsuppress
+                </Button>
+              </HStack>
+            ))}
+          </VStack>
+        )}
+      </>
+    )
+  }
+}
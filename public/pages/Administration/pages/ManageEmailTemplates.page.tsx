@@ -0,0 +1,183 @@
+import React from "react"
+import { Button, Field, Form, Input, Select, SelectOption, TextArea } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { EmailTemplate, EmailTemplatePreview } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+import locales from "@locale/locales"
+
+interface ManageEmailTemplatesPageProps {
+  defaults: EmailTemplate[]
+  templates: EmailTemplate[]
+}
+
+interface ManageEmailTemplatesPageState {
+  templates: EmailTemplate[]
+  name: string
+  locale: string
+  subject: string
+  body: string
+  preview?: EmailTemplatePreview
+  error?: Failure
+}
+
+const titles: { [key: string]: string } = {
+  new_post: "New post",
+  new_comment: "New comment",
+  change_status: "Status change",
+  delete_post: "Deleted post",
+  merge_post: "Merged post",
+  post_received: "Post received by email",
+  post_rejected: "Post rejected by email",
+  digest: "Email digest",
+  invite_email: "Invitation",
+  signin_email: "Sign in",
+  signup_email: "Sign up",
+  change_emailaddress_email: "Email address change",
+}
+
+export default class ManageEmailTemplatesPage extends AdminBasePage<ManageEmailTemplatesPageProps, ManageEmailTemplatesPageState> {
+  public id = "p-admin-email-templates"
+  public name = "email-templates"
+  public title = "Email Templates"
+  public subtitle = "Customize the emails sent by this site"
+
+  private typing?: number
+
+  constructor(props: ManageEmailTemplatesPageProps) {
+    super(props)
+    this.state = this.select(this.props.templates, this.props.defaults[0].name, Fider.session.tenant.locale)
+  }
+
+  public componentDidMount() {
+    this.preview()
+  }
+
+  private select(templates: EmailTemplate[], name: string, locale: string): ManageEmailTemplatesPageState {
+    const template = this.findOverride(templates, name, locale) || this.props.defaults.find((t) => t.name === name)
+    return {
+      templates,
+      name,
+      locale,
+      subject: template?.subject || "",
+      body: template?.body || "",
+      preview: undefined,
+      error: undefined,
+    }
+  }
+
+  private findOverride(templates: EmailTemplate[], name: string, locale: string): EmailTemplate | undefined {
+    return templates.find((t) => t.name === name && t.locale === locale)
+  }
+
+  private change = (name: string, locale: string) => {
+    this.setState(this.select(this.state.templates, name, locale), this.preview)
+  }
+
+  private setName = (option?: SelectOption) => this.change(option?.value || this.state.name, this.state.locale)
+  private setLocale = (option?: SelectOption) => this.change(this.state.name, option?.value || this.state.locale)
+
+  private setSubject = (subject: string) => {
+    this.setState({ subject }, this.schedulePreview)
+  }
+
+  private setBody = (body: string) => {
+    this.setState({ body }, this.schedulePreview)
+  }
+
+  private schedulePreview = () => {
+    window.clearTimeout(this.typing)
+    this.typing = window.setTimeout(this.preview, 1_000)
+  }
+
+  private preview = async () => {
+    const result = await actions.previewEmailTemplate(this.state.name, this.state.subject, this.state.body)
+    if (result.ok) {
+      this.setState({ preview: result.data })
+    }
+  }
+
+  private save = async () => {
+    const { name, locale, subject, body } = this.state
+    const result = await actions.saveEmailTemplate({ name, locale, subject, body })
+    if (result.ok) {
+      const templates = this.state.templates.filter((t) => t.name !== name || t.locale !== locale).concat({ name, locale, subject, body })
+      this.setState({ templates, error: undefined })
+    } else {
+      this.setState({ error: result.error })
+    }
+  }
+
+  private restoreDefault = async () => {
+    const { name, locale } = this.state
+    const result = await actions.deleteEmailTemplate(name, locale)
+    if (result.ok) {
+      const templates = this.state.templates.filter((t) => t.name !== name || t.locale !== locale)
+      this.setState(this.select(templates, name, locale), this.preview)
+    }
+  }
+
+  public content() {
+    const isCustomized = !!this.findOverride(this.state.templates, this.state.name, this.state.locale)
+
+    return (
+      <>
+        <p className="text-muted">
+          Emails are written with the same template language used by Fider. Each locale can have its own version of an email, otherwise the default one is
+          sent.
+        </p>
+        <Form error={this.state.error}>
+          <HStack spacing={4}>
+            <Select
+              key={`name-${this.state.name}`}
+              field="name"
+              label="Email"
+              defaultValue={this.state.name}
+              options={this.props.defaults.map((t) => ({
+                value: t.name,
+                label: (titles[t.name] || t.name) + (this.state.templates.some((o) => o.name === t.name) ? " (customized)" : ""),
+              }))}
+              onChange={this.setName}
+            />
+            <Select
+              key={`locale-${this.state.locale}`}
+              field="locale"
+              label="Locale"
+              defaultValue={this.state.locale}
+              options={Object.entries(locales).map(([k, v]) => ({
+                value: k,
+                label: v.text + (this.findOverride(this.state.templates, this.state.name, k) ? " (customized)" : ""),
+              }))}
+              onChange={this.setLocale}
+            />
+          </HStack>
+          <Input field="subject" label="Subject" value={this.state.subject} onChange={this.setSubject} />
+          <TextArea field="body" label="Body" minRows={12} value={this.state.body} onChange={this.setBody}>
+            <p className="text-muted">
+              The body is placed inside the site logo and footer. Props such as <code>{"{{ .siteName }}"}</code> and <code>{"{{ .title }}"}</code> are
+              available, and <code>{'{{ translate "key" }}'}</code> uses the texts of the selected locale.
+            </p>
+          </TextArea>
+          <HStack>
+            <Button variant="primary" onClick={this.save}>
+              Save
+            </Button>
+            {isCustomized && <Button onClick={this.restoreDefault}>Restore default</Button>}
+          </HStack>
+        </Form>
+        <Field label="Preview">
+          {this.state.preview === undefined ? (
+            <p className="text-muted">Loading preview...</p>
+          ) : this.state.preview.error ? (
+            <pre>{this.state.preview.error}</pre>
+          ) : (
+            <VStack>
+              <strong>{this.state.preview.subject}</strong>
+              <iframe title="Preview" sandbox="" srcDoc={this.state.preview.body} style={{ width: "100%", height: "480px", border: 0 }} />
+            </VStack>
+          )}
+        </Field>
+      </>
+    )
+  }
+}
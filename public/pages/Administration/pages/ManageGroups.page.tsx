@@ -0,0 +1,94 @@
+import React from "react"
+import { Button } from "@fider/components"
+
+import { User, UserGroup } from "@fider/models"
+import { actions, Failure } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+import { UserGroupFormState, UserGroupForm } from "../components/UserGroupForm"
+import { UserGroupListItem } from "../components/UserGroupListItem"
+import { VStack } from "@fider/components/layout"
+
+interface ManageGroupsPageProps {
+  groups: UserGroup[]
+  users: User[]
+}
+
+interface ManageGroupsPageState {
+  isAdding: boolean
+  allGroups: UserGroup[]
+}
+
+export default class ManageGroupsPage extends AdminBasePage<ManageGroupsPageProps, ManageGroupsPageState> {
+  public id = "p-admin-groups"
+  public name = "groups"
+  public title = "Groups"
+  public subtitle = "Manage segments of users and what they can see"
+
+  constructor(props: ManageGroupsPageProps) {
+    super(props)
+    this.state = {
+      isAdding: false,
+      allGroups: this.props.groups,
+    }
+  }
+
+  private addNew = async () => {
+    this.setState({ isAdding: true })
+  }
+
+  private cancelAdd = () => {
+    this.setState({ isAdding: false })
+  }
+
+  private saveNewGroup = async (data: UserGroupFormState): Promise<Failure | undefined> => {
+    const result = await actions.createUserGroup(data)
+    if (result.ok) {
+      this.setState({
+        isAdding: false,
+        allGroups: this.state.allGroups.concat(result.data),
+      })
+    } else {
+      return result.error
+    }
+  }
+
+  private handleGroupDeleted = (group: UserGroup) => {
+    this.setState({
+      allGroups: this.state.allGroups.filter((g) => g.id !== group.id),
+    })
+  }
+
+  private handleGroupEdited = () => {
+    this.setState({
+      allGroups: [...this.state.allGroups],
+    })
+  }
+
+  public content() {
+    const list = this.state.allGroups.map((g) => (
+      <UserGroupListItem key={g.id} group={g} users={this.props.users} onGroupDeleted={this.handleGroupDeleted} onGroupEdited={this.handleGroupEdited} />
+    ))
+
+    const form = this.state.isAdding ? (
+      <UserGroupForm onSave={this.saveNewGroup} onCancel={this.cancelAdd} />
+    ) : (
+      <Button variant="secondary" onClick={this.addNew}>
+        Add new
+      </Button>
+    )
+
+    return (
+      <VStack spacing={8}>
+        <div>
+          <p className="text-muted">
+            Tags, roadmap columns and posts can be restricted to a group. Restricted content is only visible to members of the group and to your team.
+          </p>
+          <VStack spacing={4} divide={true}>
+            {list.length === 0 ? <p className="text-muted">There aren’t any groups yet.</p> : list}
+          </VStack>
+        </div>
+        <div>{form}</div>
+      </VStack>
+    )
+  }
+}
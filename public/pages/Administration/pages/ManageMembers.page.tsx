@@ -1,11 +1,11 @@
 import React from "react"
 import { Input, Avatar, UserName, Icon, Dropdown, Button } from "@fider/components"
-import { User, UserRole, UserStatus } from "@fider/models"
+import { CustomRole, User, UserRole, UserStatus } from "@fider/models"
 import { AdminBasePage } from "../components/AdminBasePage"
 import IconSearch from "@fider/assets/images/heroicons-search.svg"
 import IconX from "@fider/assets/images/heroicons-x.svg"
 import IconDotsHorizontal from "@fider/assets/images/heroicons-dots-horizontal.svg"
-import { actions, Fider } from "@fider/services"
+import { actions, Fider, notify } from "@fider/services"
 import { HStack, VStack } from "@fider/components/layout"
 
 interface ManageMembersPageState {
@@ -16,17 +16,20 @@ interface ManageMembersPageState {
 
 interface ManageMembersPageProps {
   users: User[]
+  roles: CustomRole[]
 }
 
 interface UserListItemProps {
   user: User
+  roles: CustomRole[]
   onAction: (actionName: string, user: User) => Promise<void>
 }
 
 const UserListItem = (props: UserListItemProps) => {
   const admin = props.user.role === UserRole.Administrator && <span>administrator</span>
-  const collaborator = props.user.role === UserRole.Collaborator && <span>collaborator</span>
+  const collaborator = props.user.role === UserRole.Collaborator && <span>{props.user.customRole ? props.user.customRole.name : "collaborator"}</span>
   const blocked = props.user.status === UserStatus.Blocked && <span className="text-red-700">blocked</span>
+  const twoFactor = props.user.hasTwoFactor && <span className="text-green-700">2FA</span>
   const isVisitor = props.user.role === UserRole.Visitor
 
   const actionSelected = (actionName: string) => () => {
@@ -40,7 +43,7 @@ const UserListItem = (props: UserListItemProps) => {
         <VStack spacing={0}>
           <UserName user={props.user} showEmail={true} />
           <span className="text-muted">
-            {admin} {collaborator} {blocked}
+            {admin} {collaborator} {blocked} {twoFactor}
           </span>
         </VStack>
       </HStack>
@@ -51,6 +54,16 @@ const UserListItem = (props: UserListItemProps) => {
           )}
           {!blocked && (!!admin || isVisitor) && <Dropdown.ListItem onClick={actionSelected("to-collaborator")}>Promote to Collaborator</Dropdown.ListItem>}
           {!blocked && (!!collaborator || !!admin) && <Dropdown.ListItem onClick={actionSelected("to-visitor")}>Demote to Visitor</Dropdown.ListItem>}
+          {!blocked &&
+            props.roles
+              .filter((r) => !props.user.customRole || props.user.customRole.id !== r.id)
+              .map((r) => (
+                <Dropdown.ListItem key={r.id} onClick={actionSelected(`to-role-${r.id}`)}>
+                  Set role to {r.name}
+                </Dropdown.ListItem>
+              ))}
+          {!blocked && props.user.customRole && <Dropdown.ListItem onClick={actionSelected("remove-role")}>Remove role</Dropdown.ListItem>}
+          {!blocked && <Dropdown.ListItem onClick={actionSelected("sign-out")}>Sign out everywhere</Dropdown.ListItem>}
           {isVisitor && !blocked && <Dropdown.ListItem onClick={actionSelected("block")}>Block User</Dropdown.ListItem>}
           {isVisitor && !!blocked && <Dropdown.ListItem onClick={actionSelected("unblock")}>Unblock User</Dropdown.ListItem>}
         </Dropdown>
@@ -101,6 +114,16 @@ export default class ManageMembersPage extends AdminBasePage<ManageMembersPagePr
       const result = await actions.changeUserRole(user.id, role)
       if (result.ok) {
         user.role = role
+        user.customRole = undefined
+      }
+      this.handleSearchFilterChanged(this.state.query)
+    }
+
+    const changeCustomRole = async (customRole?: CustomRole) => {
+      const result = await actions.setUserCustomRole(user.id, customRole ? customRole.id : 0)
+      if (result.ok) {
+        user.role = UserRole.Collaborator
+        user.customRole = customRole
       }
       this.handleSearchFilterChanged(this.state.query)
     }
@@ -124,6 +147,15 @@ export default class ManageMembersPage extends AdminBasePage<ManageMembersPagePr
       await changeStatus(UserStatus.Blocked)
     } else if (actionName === "unblock") {
       await changeStatus(UserStatus.Active)
+    } else if (actionName === "sign-out") {
+      const result = await actions.revokeSessionsOfUser(user.id)
+      if (result.ok) {
+        notify.success(`${user.name} has been signed out of all browsers.`)
+      }
+    } else if (actionName === "remove-role") {
+      await changeCustomRole(undefined)
+    } else if (actionName.startsWith("to-role-")) {
+      await changeCustomRole(this.props.roles.find((r) => `to-role-${r.id}` === actionName))
     }
   }
 
@@ -157,7 +189,7 @@ export default class ManageMembersPage extends AdminBasePage<ManageMembersPagePr
         <div className="p-2">
           <VStack spacing={2} divide={true}>
             {this.state.visibleUsers.map((user) => (
-              <UserListItem key={user.id} user={user} onAction={this.handleAction} />
+              <UserListItem key={user.id} user={user} roles={this.props.roles || []} onAction={this.handleAction} />
             ))}
           </VStack>
         </div>
@@ -185,9 +217,15 @@ export default class ManageMembersPage extends AdminBasePage<ManageMembersPagePr
           <li>
             <strong>Collaborators</strong> can edit and manage content, but not permissions and settings.
           </li>
+          <li>
+            <strong>Roles</strong> limit what a collaborator is allowed to do. They are managed on the Roles page.
+          </li>
           <li>
             <strong>Blocked</strong> users are unable to sign into this site.
           </li>
+          <li>
+            <strong>2FA</strong> users have enabled two-factor authentication on their account.
+          </li>
         </ul>
       </>
     )
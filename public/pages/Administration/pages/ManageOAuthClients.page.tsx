@@ -0,0 +1,159 @@
+import React from "react"
+import { Button, Checkbox, Form, Input, Moment, TextArea } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { OAuthClient } from "@fider/models"
+import { actions, Failure, Fider } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageOAuthClientsPageProps {
+  clients: OAuthClient[]
+}
+
+interface ManageOAuthClientsPageState {
+  clients: OAuthClient[]
+  isAdding: boolean
+  name: string
+  redirectUris: string
+  isConfidential: boolean
+  newClient?: OAuthClient
+  newClientSecret: string
+  error?: Failure
+}
+
+export default class ManageOAuthClientsPage extends AdminBasePage<ManageOAuthClientsPageProps, ManageOAuthClientsPageState> {
+  public id = "p-admin-oauth-apps"
+  public name = "oauth-apps"
+  public title = "OAuth Apps"
+  public subtitle = "Register applications that sign your users in and use the API on their behalf"
+
+  constructor(props: ManageOAuthClientsPageProps) {
+    super(props)
+    this.state = {
+      clients: this.props.clients,
+      isAdding: false,
+      name: "",
+      redirectUris: "",
+      isConfidential: true,
+      newClientSecret: "",
+    }
+  }
+
+  private create = async () => {
+    const redirectUris = this.state.redirectUris
+      .split("\n")
+      .map((uri) => uri.trim())
+      .filter((uri) => uri !== "")
+
+    const result = await actions.createOAuthClient({ name: this.state.name, redirectUris, isConfidential: this.state.isConfidential })
+    if (result.ok) {
+      this.setState({
+        clients: this.state.clients.concat(result.data.client),
+        isAdding: false,
+        name: "",
+        redirectUris: "",
+        newClient: result.data.client,
+        newClientSecret: result.data.clientSecret,
+        error: undefined,
+      })
+    } else {
+      this.setState({ error: result.error })
+    }
+  }
+
+  private revoke = async (client: OAuthClient) => {
+    const result = await actions.revokeOAuthClient(client.id)
+    if (result.ok) {
+      this.setState({ clients: this.state.clients.filter((c) => c.id !== client.id) })
+    }
+  }
+
+  private renderNewClient(client: OAuthClient) {
+    return (
+      <div className="mb-4">
+        <p>
+          <strong>{client.name}</strong> has been registered. Use the following credentials in your application.
+        </p>
+        <p>
+          Client ID: <code>{client.clientId}</code>
+        </p>
+        {this.state.newClientSecret && (
+          <p>
+            Client Secret: <code>{this.state.newClientSecret}</code>
+          </p>
+        )}
+        <p className="text-muted">
+          Endpoints are listed in <code>{Fider.settings.baseURL}/.well-known/openid-configuration</code>. Authorization requests must use PKCE with the
+          S256 method.
+        </p>
+      </div>
+    )
+  }
+
+  private renderForm() {
+    return (
+      <Form error={this.state.error}>
+        <Input field="name" label="Name" value={this.state.name} onChange={(name) => this.setState({ name })} />
+        <TextArea
+          field="redirectUris"
+          label="Redirect URIs"
+          placeholder="https://example.com/oauth/callback"
+          value={this.state.redirectUris}
+          onChange={(redirectUris) => this.setState({ redirectUris })}
+        >
+          <p className="text-muted">One per line. Users are only sent back to an exact match of one of these URIs.</p>
+        </TextArea>
+        <Checkbox field="isConfidential" checked={this.state.isConfidential} onChange={(isConfidential) => this.setState({ isConfidential })}>
+          Confidential client. Uncheck for mobile and single-page apps that can&apos;t keep a secret.
+        </Checkbox>
+        <HStack className="mt-4">
+          <Button variant="primary" onClick={this.create}>
+            Register
+          </Button>
+          <Button variant="tertiary" onClick={() => this.setState({ isAdding: false, error: undefined })}>
+            Cancel
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+
+  public content() {
+    return (
+      <>
+        {this.state.newClient && this.renderNewClient(this.state.newClient)}
+        {this.state.clients.length === 0 ? (
+          <p className="text-muted">There aren’t any OAuth apps yet.</p>
+        ) : (
+          <VStack spacing={4} divide={true} className="mb-4">
+            {this.state.clients.map((c) => (
+              <HStack key={c.id} justify="between">
+                <VStack spacing={0}>
+                  <span>
+                    <strong>{c.name}</strong> <code className="text-xs">{c.clientId}</code>
+                  </span>
+                  <span className="text-muted text-sm">
+                    {c.isConfidential ? "confidential" : "public"}
+                    {" · "}
+                    {c.redirectUris.join(", ")}
+                    {" · "}
+                    registered <Moment locale={Fider.currentLocale} date={c.createdAt} />
+                  </span>
+                </VStack>
+                <Button size="small" onClick={() => this.revoke(c)}>
+                  Revoke
+                </Button>
+              </HStack>
+            ))}
+          </VStack>
+        )}
+        {this.state.isAdding ? (
+          this.renderForm()
+        ) : (
+          <Button variant="secondary" onClick={() => this.setState({ isAdding: true, newClient: undefined })}>
+            Register new app
+          </Button>
+        )}
+      </>
+    )
+  }
+}
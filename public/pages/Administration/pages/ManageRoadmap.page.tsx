@@ -1,9 +1,9 @@
 import "./ManageRoadmap.page.scss"
 
 import React, { useState, useEffect } from "react"
-import { RoadmapColumn } from "@fider/models"
-import { Button, Input, Message, Modal, Toggle } from "@fider/components"
-import { roadmap } from "@fider/services"
+import { RoadmapColumn, UserGroup } from "@fider/models"
+import { Button, Input, Message, Modal, Select, SelectOption, Toggle } from "@fider/components"
+import { actions, roadmap } from "@fider/services"
 import { i18n } from "@lingui/core"
 import { Trans } from "@lingui/react/macro"
 import { AdminPageContainer } from "../components/AdminBasePage"
@@ -16,6 +16,18 @@ export interface ManageRoadmapPageState {
   editingColumn?: RoadmapColumn
   newColumnName: string
   newColumnPublic: boolean
+  newColumnGroupId?: number
+  groups: UserGroup[]
+}
+
+const groupOptions = (groups: UserGroup[]): SelectOption[] => {
+  return [{ value: "", label: i18n._({ id: "admin.roadmap.group.everyone", message: "Everyone" }) }].concat(
+    groups.map((g) => ({ value: g.id.toString(), label: g.name }))
+  )
+}
+
+const toGroupId = (option?: SelectOption): number | undefined => {
+  return option && option.value ? parseInt(option.value, 10) : undefined
 }
 
 const ManageRoadmapPage = () => {
@@ -25,10 +37,16 @@ const ManageRoadmapPage = () => {
     showCreateModal: false,
     newColumnName: "",
     newColumnPublic: true,
+    groups: [],
   })
 
   useEffect(() => {
     loadColumns()
+    actions.listUserGroups().then((result) => {
+      if (result.ok) {
+        setState((prev) => ({ ...prev, groups: result.data }))
+      }
+    })
   }, [])
 
   const loadColumns = async () => {
@@ -49,12 +67,13 @@ const ManageRoadmapPage = () => {
     if (!state.newColumnName.trim()) return
 
     try {
-      await roadmap.createColumn(state.newColumnName.trim(), state.newColumnPublic)
+      await roadmap.createColumn(state.newColumnName.trim(), state.newColumnPublic, state.newColumnGroupId)
       setState((prev) => ({
         ...prev,
         showCreateModal: false,
         newColumnName: "",
         newColumnPublic: true,
+        newColumnGroupId: undefined,
       }))
       await loadColumns()
     } catch (error) {
@@ -65,9 +84,9 @@ const ManageRoadmapPage = () => {
     }
   }
 
-  const handleUpdateColumn = async (column: RoadmapColumn, name: string, isPublic: boolean) => {
+  const handleUpdateColumn = async (column: RoadmapColumn, name: string, isPublic: boolean, groupId?: number) => {
     try {
-      await roadmap.updateColumn(column.id, name, isPublic)
+      await roadmap.updateColumn(column.id, name, isPublic, groupId)
       await loadColumns()
     } catch (error) {
       setState((prev) => ({
@@ -161,6 +180,9 @@ const ManageRoadmapPage = () => {
                         <span className={`c-roadmap-column-admin__visibility ${column.isVisibleToPublic ? "public" : "private"}`}>
                           {column.isVisibleToPublic ? "Public" : "Private"}
                         </span>
+                        {column.groupId && (
+                          <span className="c-roadmap-column-admin__group">{state.groups.find((g) => g.id === column.groupId)?.name}</span>
+                        )}
                       </div>
                     </div>
                     <div className="c-roadmap-column-admin__actions">
@@ -213,6 +235,17 @@ const ManageRoadmapPage = () => {
                 <Trans id="admin.roadmap.create.public.help">Public columns are visible to all users. Private columns are only visible to staff members.</Trans>
               </p>
             </div>
+            {state.groups.length > 0 && (
+              <div className="mb-4">
+                <Select
+                  field="groupId"
+                  label={i18n._({ id: "admin.roadmap.group.label", message: "Restrict to group" })}
+                  defaultValue=""
+                  options={groupOptions(state.groups)}
+                  onChange={(option) => setState((prev) => ({ ...prev, newColumnGroupId: toGroupId(option) }))}
+                />
+              </div>
+            )}
           </Modal.Content>
           <Modal.Footer>
             <Button variant="tertiary" onClick={() => setState((prev) => ({ ...prev, showCreateModal: false }))}>
@@ -228,6 +261,7 @@ const ManageRoadmapPage = () => {
         {state.editingColumn && (
           <EditColumnModal
             column={state.editingColumn}
+            groups={state.groups}
             onClose={() => setState((prev) => ({ ...prev, editingColumn: undefined }))}
             onSave={handleUpdateColumn}
           />
@@ -239,16 +273,18 @@ const ManageRoadmapPage = () => {
 
 interface EditColumnModalProps {
   column: RoadmapColumn
+  groups: UserGroup[]
   onClose: () => void
-  onSave: (column: RoadmapColumn, name: string, isPublic: boolean) => void
+  onSave: (column: RoadmapColumn, name: string, isPublic: boolean, groupId?: number) => void
 }
 
 const EditColumnModal = (props: EditColumnModalProps) => {
   const [name, setName] = useState(props.column.name)
   const [isPublic, setIsPublic] = useState(props.column.isVisibleToPublic)
+  const [groupId, setGroupId] = useState(props.column.groupId)
 
   const handleSave = () => {
-    props.onSave(props.column, name, isPublic)
+    props.onSave(props.column, name, isPublic, groupId)
     props.onClose()
   }
 
@@ -275,6 +311,17 @@ const EditColumnModal = (props: EditColumnModalProps) => {
             onToggle={(checked: boolean) => setIsPublic(checked)}
           />
         </div>
+        {props.groups.length > 0 && (
+          <div className="mb-4">
+            <Select
+              field="groupId"
+              label={i18n._({ id: "admin.roadmap.group.label", message: "Restrict to group" })}
+              defaultValue={groupId ? groupId.toString() : ""}
+              options={groupOptions(props.groups)}
+              onChange={(option) => setGroupId(toGroupId(option))}
+            />
+          </div>
+        )}
       </Modal.Content>
       <Modal.Footer>
         <Button variant="tertiary" onClick={props.onClose}>
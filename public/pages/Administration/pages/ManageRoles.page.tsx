@@ -0,0 +1,94 @@
+import React from "react"
+import { Button } from "@fider/components"
+
+import { CustomRole } from "@fider/models"
+import { actions, Failure } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+import { CustomRoleFormState, CustomRoleForm } from "../components/CustomRoleForm"
+import { CustomRoleListItem } from "../components/CustomRoleListItem"
+import { VStack } from "@fider/components/layout"
+
+interface ManageRolesPageProps {
+  roles: CustomRole[]
+}
+
+interface ManageRolesPageState {
+  isAdding: boolean
+  allRoles: CustomRole[]
+}
+
+export default class ManageRolesPage extends AdminBasePage<ManageRolesPageProps, ManageRolesPageState> {
+  public id = "p-admin-roles"
+  public name = "roles"
+  public title = "Roles"
+  public subtitle = "Manage what your collaborators are allowed to do"
+
+  constructor(props: ManageRolesPageProps) {
+    super(props)
+    this.state = {
+      isAdding: false,
+      allRoles: this.props.roles,
+    }
+  }
+
+  private addNew = async () => {
+    this.setState({ isAdding: true })
+  }
+
+  private cancelAdd = () => {
+    this.setState({ isAdding: false })
+  }
+
+  private saveNewRole = async (data: CustomRoleFormState): Promise<Failure | undefined> => {
+    const result = await actions.createCustomRole(data)
+    if (result.ok) {
+      this.setState({
+        isAdding: false,
+        allRoles: this.state.allRoles.concat({ ...result.data, usersCount: 0 }),
+      })
+    } else {
+      return result.error
+    }
+  }
+
+  private handleRoleDeleted = (role: CustomRole) => {
+    this.setState({
+      allRoles: this.state.allRoles.filter((r) => r.id !== role.id),
+    })
+  }
+
+  private handleRoleEdited = () => {
+    this.setState({
+      allRoles: [...this.state.allRoles],
+    })
+  }
+
+  public content() {
+    const list = this.state.allRoles.map((r) => (
+      <CustomRoleListItem key={r.id} role={r} onRoleDeleted={this.handleRoleDeleted} onRoleEdited={this.handleRoleEdited} />
+    ))
+
+    const form = this.state.isAdding ? (
+      <CustomRoleForm onSave={this.saveNewRole} onCancel={this.cancelAdd} />
+    ) : (
+      <Button variant="secondary" onClick={this.addNew}>
+        Add new
+      </Button>
+    )
+
+    return (
+      <VStack spacing={8}>
+        <div>
+          <p className="text-muted">
+            Roles are assigned to collaborators from the Members page. A collaborator with a role can only do what the role allows, while collaborators without
+            a role keep the default collaborator permissions.
+          </p>
+          <VStack spacing={4} divide={true}>
+            {list.length === 0 ? <p className="text-muted">There aren’t any roles yet.</p> : list}
+          </VStack>
+        </div>
+        <div>{form}</div>
+      </VStack>
+    )
+  }
+}
@@ -0,0 +1,184 @@
+import React from "react"
+import { Button, Field, Form, Input, TextArea, Toggle } from "@fider/components"
+import { HStack } from "@fider/components/layout"
+import { OAuthConfigStatus, SAMLConfig } from "@fider/models"
+import { actions, Failure, notify } from "@fider/services"
+import { AdminBasePage } from "../components/AdminBasePage"
+
+interface ManageSAMLPageProps {
+  config?: SAMLConfig
+  entityID: string
+  metadataURL: string
+  acsURL: string
+}
+
+interface ManageSAMLPageState {
+  enabled: boolean
+  displayName: string
+  idpMetadata: string
+  attributeName: string
+  attributeEmail: string
+  attributeRole: string
+  administratorRoleValue: string
+  collaboratorRoleValue: string
+  requireSSO: boolean
+  allowIdPInitiated: boolean
+  error?: Failure
+}
+
+export default class ManageSAMLPage extends AdminBasePage<ManageSAMLPageProps, ManageSAMLPageState> {
+  public id = "p-admin-saml"
+  public name = "saml"
+  public title = "SAML Single Sign-On"
+  public subtitle = "Let your users sign in with your identity provider"
+
+  constructor(props: ManageSAMLPageProps) {
+    super(props)
+    const config = this.props.config
+    this.state = {
+      enabled: (config && config.status === OAuthConfigStatus.Enabled) || false,
+      displayName: (config && config.displayName) || "",
+      idpMetadata: (config && config.idpMetadata) || "",
+      attributeName: (config && config.attributeName) || "",
+      attributeEmail: (config && config.attributeEmail) || "",
+      attributeRole: (config && config.attributeRole) || "",
+      administratorRoleValue: (config && config.administratorRoleValue) || "",
+      collaboratorRoleValue: (config && config.collaboratorRoleValue) || "",
+      requireSSO: (config && config.requireSSO) || false,
+      allowIdPInitiated: (config && config.allowIdPInitiated) || false,
+    }
+  }
+
+  private save = async () => {
+    const result = await actions.saveSAMLConfig({
+      status: this.state.enabled ? OAuthConfigStatus.Enabled : OAuthConfigStatus.Disabled,
+      displayName: this.state.displayName,
+      idpMetadata: this.state.idpMetadata,
+      attributeName: this.state.attributeName,
+      attributeEmail: this.state.attributeEmail,
+      attributeRole: this.state.attributeRole,
+      administratorRoleValue: this.state.administratorRoleValue,
+      collaboratorRoleValue: this.state.collaboratorRoleValue,
+      requireSSO: this.state.enabled && this.state.requireSSO,
+      allowIdPInitiated: this.state.enabled && this.state.allowIdPInitiated,
+    })
+    if (result.ok) {
+      this.setState({ error: undefined })
+      notify.success("Your SAML configuration has been saved.")
+    } else {
+      this.setState({ error: result.error })
+    }
+  }
+
+  private loadMetadataFile = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files && e.target.files[0]
+    if (file) {
+      const reader = new FileReader()
+      reader.onload = () => this.setState({ idpMetadata: reader.result as string })
+      reader.readAsText(file)
+    }
+  }
+
+  public content() {
+    return (
+      <>
+        <div className="mb-4">
+          <p>Register this site in your identity provider with the following details.</p>
+          <p>
+            Entity ID: <code>{this.props.entityID}</code>
+          </p>
+          <p>
+            Assertion Consumer Service URL (HTTP-POST): <code>{this.props.acsURL}</code>
+          </p>
+          <p>
+            Metadata: <code>{this.props.metadataURL}</code>
+          </p>
+          <p className="text-muted">Responses or assertions must be signed. Encrypted assertions are not supported.</p>
+        </div>
+        <Form error={this.state.error}>
+          <Input field="displayName" label="Display Name" maxLength={50} value={this.state.displayName} onChange={(displayName) => this.setState({ displayName })}>
+            <p className="text-muted">Shown on the sign in button, e.g. &quot;Okta&quot; or &quot;Company SSO&quot;.</p>
+          </Input>
+          <TextArea
+            field="idpMetadata"
+            label="IdP Metadata"
+            minRows={6}
+            placeholder="<EntityDescriptor ...>"
+            value={this.state.idpMetadata}
+            onChange={(idpMetadata) => this.setState({ idpMetadata })}
+          >
+            <p className="text-muted">
+              Paste the metadata XML of your identity provider or <input type="file" accept=".xml,text/xml,application/xml" onChange={this.loadMetadataFile} />
+            </p>
+          </TextArea>
+          {this.props.config && this.props.config.idpEntityID && (
+            <p className="text-muted text-sm">
+              Current IdP: <code>{this.props.config.idpEntityID}</code> signing in at <code>{this.props.config.idpSSOURL}</code>
+            </p>
+          )}
+          <Input field="attributeName" label="Name Attribute" value={this.state.attributeName} onChange={(attributeName) => this.setState({ attributeName })}>
+            <p className="text-muted">Attribute holding the display name. When empty, the name is taken from the email address.</p>
+          </Input>
+          <Input field="attributeEmail" label="Email Attribute" value={this.state.attributeEmail} onChange={(attributeEmail) => this.setState({ attributeEmail })}>
+            <p className="text-muted">Attribute holding the email address. When empty, the NameID is used if it is an email address.</p>
+          </Input>
+          <Input field="attributeRole" label="Role Attribute" value={this.state.attributeRole} onChange={(attributeRole) => this.setState({ attributeRole })}>
+            <p className="text-muted">
+              Optional. When the assertion carries this attribute, the user role is updated on every sign in. Users without a matching value become visitors.
+            </p>
+          </Input>
+          {this.state.attributeRole && (
+            <HStack>
+              <Input
+                field="administratorRoleValue"
+                label="Administrator Value"
+                value={this.state.administratorRoleValue}
+                onChange={(administratorRoleValue) => this.setState({ administratorRoleValue })}
+              />
+              <Input
+                field="collaboratorRoleValue"
+                label="Collaborator Value"
+                value={this.state.collaboratorRoleValue}
+                onChange={(collaboratorRoleValue) => this.setState({ collaboratorRoleValue })}
+              />
+            </HStack>
+          )}
+          <Field label="Status">
+            <Toggle field="status" active={this.state.enabled} onToggle={(enabled) => this.setState({ enabled })} label={this.state.enabled ? "Enabled" : "Disabled"} />
+          </Field>
+          {this.state.enabled && (
+            <Field label="Require SSO">
+              <Toggle
+                field="requireSSO"
+                active={this.state.requireSSO}
+                onToggle={(requireSSO) => this.setState({ requireSSO })}
+                label={this.state.requireSSO ? "Yes" : "No"}
+              />
+              <p className="text-muted mt-1">
+                When enabled, SAML is the only way to sign in. Email authentication and other providers are disabled. Administrators can still sign in by
+                email in case the identity provider is unavailable.
+              </p>
+            </Field>
+          )}
+          {this.state.enabled && (
+            <Field label="Allow IdP-initiated sign in">
+              <Toggle
+                field="allowIdPInitiated"
+                active={this.state.allowIdPInitiated}
+                onToggle={(allowIdPInitiated) => this.setState({ allowIdPInitiated })}
+                label={this.state.allowIdPInitiated ? "Yes" : "No"}
+              />
+              <p className="text-muted mt-1">
+                When enabled, users can also sign in from the dashboard of your identity provider. Responses that weren't requested by Fider can&apos;t be tied
+                to a sign in attempt, so only enable it if your identity provider requires it.
+              </p>
+            </Field>
+          )}
+          <Button variant="primary" onClick={this.save}>
+            Save
+          </Button>
+        </Form>
+      </>
+    )
+  }
+}
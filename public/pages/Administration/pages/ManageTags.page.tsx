@@ -1,7 +1,7 @@
 import React from "react"
 import { Button } from "@fider/components"
 
-import { Tag } from "@fider/models"
+import { Tag, UserGroup, hasPermission } from "@fider/models"
 import { actions, Failure, Fider } from "@fider/services"
 import { AdminBasePage } from "../components/AdminBasePage"
 import { TagFormState, TagForm } from "../components/TagForm"
@@ -10,6 +10,7 @@ import { VStack } from "@fider/components/layout"
 
 interface ManageTagsPageProps {
   tags: Tag[]
+  groups: UserGroup[]
 }
 
 interface ManageTagsPageState {
@@ -55,7 +56,7 @@ export default class ManageTagsPage extends AdminBasePage<ManageTagsPageProps, M
   }
 
   private saveNewTag = async (data: TagFormState): Promise<Failure | undefined> => {
-    const result = await actions.createTag(data.name, data.color, data.isPublic)
+    const result = await actions.createTag(data.name, data.color, data.isPublic, data.groupId)
     if (result.ok) {
       this.setState({
         isAdding: false,
@@ -81,7 +82,7 @@ export default class ManageTagsPage extends AdminBasePage<ManageTagsPageProps, M
 
   private getTagList(filter: (tag: Tag) => boolean) {
     return this.state.allTags.filter(filter).map((t) => {
-      return <TagListItem key={t.id} tag={t} onTagDeleted={this.handleTagDeleted} onTagEdited={this.handleTagEdited} />
+      return <TagListItem key={t.id} tag={t} groups={this.props.groups} onTagDeleted={this.handleTagDeleted} onTagEdited={this.handleTagEdited} />
     })
   }
 
@@ -90,9 +91,9 @@ export default class ManageTagsPage extends AdminBasePage<ManageTagsPageProps, M
     const privateTagList = this.getTagList((t) => !t.isPublic)
 
     const form =
-      Fider.session.user.isAdministrator &&
+      hasPermission(Fider.session.user, "tags:manage") &&
       (this.state.isAdding ? (
-        <TagForm onSave={this.saveNewTag} onCancel={this.cancelAdd} />
+        <TagForm groups={this.props.groups} onSave={this.saveNewTag} onCancel={this.cancelAdd} />
       ) : (
         <Button variant="secondary" onClick={this.addNew}>
           Add new
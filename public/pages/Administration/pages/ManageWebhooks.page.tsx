@@ -84,6 +84,7 @@ const ManageWebhooksPage = (props: ManageWebhooksPageProps) => {
       webhook.content = data.content
       webhook.http_method = data.http_method
       webhook.http_headers = data.http_headers
+      webhook.is_internal = data.is_internal
 
       setEditing(undefined)
       sortWebhooks()
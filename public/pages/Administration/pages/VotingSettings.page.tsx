@@ -0,0 +1,103 @@
+import React from "react"
+import { Form, Button, Input, RadioButton } from "@fider/components"
+import { actions, notify, Failure, Fider } from "@fider/services"
+import { VotingMode } from "@fider/models"
+import { AdminBasePage } from "@fider/pages/Administration/components/AdminBasePage"
+
+interface VotingSettingsPageState {
+  votingMode: VotingMode
+  voteBudget: string
+  maxVotesPerPost: string
+  error?: Failure
+}
+
+const votingModes = [
+  { value: "simple", label: "One vote per user" },
+  { value: "budget", label: "Vote budget" },
+  { value: "importance", label: "Importance levels" },
+]
+
+export default class VotingSettingsPage extends AdminBasePage<any, VotingSettingsPageState> {
+  public id = "p-admin-voting"
+  public name = "voting"
+  public title = "Voting"
+  public subtitle = "Manage how users vote on posts"
+
+  constructor(props: any) {
+    super(props)
+
+    this.state = {
+      votingMode: Fider.session.tenant.votingMode,
+      voteBudget: Fider.session.tenant.voteBudget.toString(),
+      maxVotesPerPost: Fider.session.tenant.maxVotesPerPost.toString(),
+    }
+  }
+
+  private setVotingMode = (option: { value: string }) => {
+    this.setState({ votingMode: option.value as VotingMode })
+  }
+
+  private setVoteBudget = (voteBudget: string) => {
+    this.setState({ voteBudget })
+  }
+
+  private setMaxVotesPerPost = (maxVotesPerPost: string) => {
+    this.setState({ maxVotesPerPost })
+  }
+
+  private handleSave = async (): Promise<void> => {
+    const result = await actions.updateTenantVoting({
+      votingMode: this.state.votingMode,
+      voteBudget: parseInt(this.state.voteBudget, 10) || 0,
+      maxVotesPerPost: parseInt(this.state.maxVotesPerPost, 10) || 0,
+    })
+    if (result.ok) {
+      notify.success("Your voting settings have been saved.")
+      this.setState({ error: undefined })
+    } else {
+      this.setState({ error: result.error })
+    }
+  }
+
+  public content() {
+    const defaultOption = votingModes.find((x) => x.value === this.state.votingMode) || votingModes[0]
+    const isAdministrator = Fider.session.user.isAdministrator
+
+    return (
+      <Form error={this.state.error}>
+        <RadioButton label="Voting Mode" field="votingMode" defaultOption={defaultOption} options={votingModes} onSelect={this.setVotingMode} />
+        <p className="text-muted">
+          With <strong>one vote per user</strong>, every post gets at most one vote from each user.
+          <br />
+          With a <strong>vote budget</strong>, every user gets a number of votes to distribute between posts, and can put more than one vote on the posts
+          that matter most to them. Votes on completed, declined and duplicate posts are given back.
+          <br />
+          With <strong>importance levels</strong>, users say whether a post is nice to have (1 point), important (2 points) or critical (3 points).
+          <br />
+          Posts always show how many users voted, and are sorted by the weighted score on the <em>Most Wanted</em> view.
+        </p>
+
+        {this.state.votingMode === "budget" && (
+          <>
+            <Input field="voteBudget" label="Votes per user" disabled={!isAdministrator} value={this.state.voteBudget} onChange={this.setVoteBudget} />
+            <Input
+              field="maxVotesPerPost"
+              label="Maximum votes per post"
+              disabled={!isAdministrator}
+              value={this.state.maxVotesPerPost}
+              onChange={this.setMaxVotesPerPost}
+            />
+          </>
+        )}
+
+        {isAdministrator && (
+          <div className="field">
+            <Button variant="primary" onClick={this.handleSave}>
+              Save
+            </Button>
+          </div>
+        )}
+      </Form>
+    )
+  }
+}
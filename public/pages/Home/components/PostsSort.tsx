@@ -2,6 +2,7 @@ import React from "react"
 import { Dropdown } from "@fider/components"
 import { HStack } from "@fider/components/layout"
 import { i18n } from "@lingui/core"
+import { useFider } from "@fider/hooks"
 import IconSparkles from "@fider/assets/images/heroicons-sparkles-outline.svg"
 import IconThumbsUp from "@fider/assets/images/heroicons-thumbsup.svg"
 import IconChat from "@fider/assets/images/heroicons-chat-alt-2.svg"
@@ -13,6 +14,7 @@ interface PostsSortProps {
 }
 
 export const PostsSort: React.FC<PostsSortProps> = ({ value = "trending", onChange }) => {
+  const fider = useFider()
   const options = [
     { value: "trending", label: i18n._({ id: "home.postfilter.option.trending", message: "Trending" }), icon: IconSparkles },
     { value: "most-wanted", label: i18n._({ id: "home.postfilter.option.mostwanted", message: "Most Wanted" }), icon: IconThumbsUp },
@@ -20,6 +22,10 @@ export const PostsSort: React.FC<PostsSortProps> = ({ value = "trending", onChan
     { value: "recent", label: i18n._({ id: "home.postfilter.option.recent", message: "Recent" }), icon: IconClock },
   ]
 
+  if (fider.session.isAuthenticated && fider.session.user.isCollaborator) {
+    options.push({ value: "most-revenue", label: i18n._({ id: "home.postfilter.option.mostrevenue", message: "Most Revenue" }), icon: IconThumbsUp })
+  }
+
   const selectedItem = options.find((x) => x.value === value) || options[0]
 
   return (
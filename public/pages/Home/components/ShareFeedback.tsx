@@ -2,7 +2,7 @@ import "./ShareFeedback.scss"
 
 import React, { useEffect, useRef, useState } from "react"
 import { SignInControl } from "@fider/components/common/SignInControl"
-import { Modal, CloseIcon, Form, Button, Input, LegalFooter } from "@fider/components/common"
+import { Modal, CloseIcon, Form, Button, Input, LegalFooter, Checkbox } from "@fider/components/common"
 import { useFider } from "@fider/hooks"
 import { Trans } from "@lingui/react/macro"
 import { actions, Failure, querystring, classSet } from "@fider/services"
@@ -66,6 +66,7 @@ export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
   })
   const [tags, setTags] = useState(getTagsCachedValue())
   const [error, setError] = useState<Failure | undefined>(undefined)
+  const [isPrivate, setIsPrivate] = useState(false)
   const titleRef = useRef<HTMLInputElement>()
   const editorRef = useRef<HTMLDivElement>(null)
   const [titleManuallyEdited, setTitleManuallyEdited] = useState(getTitleManuallyEditedValue())
@@ -187,7 +188,8 @@ export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
           title,
           description,
           attachments,
-          tags.map((tag) => tag.slug)
+          tags.map((tag) => tag.slug),
+          isPrivate
         ),
         minDelay,
       ])
@@ -266,6 +268,11 @@ export const ShareFeedback: React.FC<ShareFeedbackProps> = (props) => {
                   </div>
                 </div>
               )}
+              {fider.session.isAuthenticated && (
+                <Checkbox field="isPrivate" checked={isPrivate} onChange={setIsPrivate}>
+                  <Trans id="newpost.modal.private">Private, only visible to me and the staff</Trans>
+                </Checkbox>
+              )}
             </Form>
           </div>
         </div>
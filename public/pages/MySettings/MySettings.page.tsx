@@ -2,11 +2,14 @@ import React from "react"
 
 import { Modal, Form, Button, PageTitle, Input, Select, SelectOption, ImageUploader, Header } from "@fider/components"
 
-import { UserSettings, UserAvatarType, ImageUpload } from "@fider/models"
+import { UserSettings, UserAvatarType, ImageUpload, Tag } from "@fider/models"
 import { Failure, actions, Fider } from "@fider/services"
-import { NotificationSettings } from "./components/NotificationSettings"
+import { NotificationSettings, SnoozedUntilKey } from "./components/NotificationSettings"
 import { APIKeyForm } from "./components/APIKeyForm"
+import { APITokensForm } from "./components/APITokensForm"
 import { DangerZone } from "./components/DangerZone"
+import { TwoFactorForm } from "./components/TwoFactorForm"
+import { SessionsForm } from "./components/SessionsForm"
 import { i18n } from "@lingui/core"
 import { Trans } from "@lingui/react/macro"
 
@@ -23,6 +26,12 @@ interface MySettingsPageState {
 
 interface MySettingsPageProps {
   userSettings: UserSettings
+  tags: Tag[]
+  followedTags: Tag[]
+  twoFactor: {
+    enabled: boolean
+    required: boolean
+  }
 }
 
 export default class MySettingsPage extends React.Component<MySettingsPageProps, MySettingsPageState> {
@@ -39,11 +48,15 @@ export default class MySettingsPage extends React.Component<MySettingsPageProps,
   }
 
   private confirm = async () => {
+    // Snoozing has its own endpoint, the settings endpoint rejects it
+    const settings = { ...this.state.userSettings }
+    delete settings[SnoozedUntilKey]
+
     const result = await actions.updateUserSettings({
       name: this.state.name,
       avatarType: this.state.avatarType,
       avatar: this.state.avatar,
-      settings: this.state.userSettings,
+      settings,
     })
     if (result.ok) {
       location.reload()
@@ -209,13 +222,27 @@ export default class MySettingsPage extends React.Component<MySettingsPageProps,
                 )}
               </Select>
 
-              <NotificationSettings userSettings={this.props.userSettings} settingsChanged={this.setNotificationSettings} />
+              <NotificationSettings
+                userSettings={this.props.userSettings}
+                tags={this.props.tags}
+                followedTags={this.props.followedTags}
+                settingsChanged={this.setNotificationSettings}
+              />
 
               <Button variant="primary" onClick={this.confirm}>
                 <Trans id="action.save">Save</Trans>
               </Button>
             </Form>
 
+            <div className="mt-8">
+              <TwoFactorForm enabled={this.props.twoFactor.enabled} required={this.props.twoFactor.required} />
+            </div>
+            <div className="mt-8">
+              <SessionsForm />
+            </div>
+            <div className="mt-8">
+              <APITokensForm />
+            </div>
             <div className="mt-8">{Fider.session.user.isCollaborator && <APIKeyForm />}</div>
             <div className="mt-8">
               <DangerZone />
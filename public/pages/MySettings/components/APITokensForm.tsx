@@ -0,0 +1,156 @@
+import React, { useEffect, useState } from "react"
+import { Button, Checkbox, Form, Input, Moment, Select, SelectOption } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { APIScope, APIToken } from "@fider/models"
+import { actions, Failure } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+export const APITokensForm = () => {
+  const fider = useFider()
+  const [tokens, setTokens] = useState<APIToken[]>([])
+  const [isAdding, setIsAdding] = useState(false)
+  const [name, setName] = useState("")
+  const [scopes, setScopes] = useState<APIScope[]>(["posts:read"])
+  const [expiresInDays, setExpiresInDays] = useState(90)
+  const [newKey, setNewKey] = useState("")
+  const [error, setError] = useState<Failure>()
+
+  const expiryOptions: SelectOption[] = [
+    { value: "30", label: i18n._({ id: "mysettings.apitokens.expiry.30days", message: "30 days" }) },
+    { value: "90", label: i18n._({ id: "mysettings.apitokens.expiry.90days", message: "90 days" }) },
+    { value: "365", label: i18n._({ id: "mysettings.apitokens.expiry.1year", message: "1 year" }) },
+    { value: "0", label: i18n._({ id: "mysettings.apitokens.expiry.never", message: "Never" }) },
+  ]
+
+  const user = fider.session.user
+  const availableScopes: APIScope[] = user.isAdministrator
+    ? ["posts:read", "posts:write", "roadmap:write", "users:read", "scim", "admin"]
+    : user.isCollaborator
+    ? ["posts:read", "posts:write", "roadmap:write", "users:read"]
+    : ["posts:read"]
+
+  useEffect(() => {
+    actions.listAPITokens().then((result) => {
+      if (result.ok) {
+        setTokens(result.data)
+      }
+    })
+  }, [])
+
+  const toggleScope = (scope: APIScope) => (checked: boolean) => {
+    setScopes(checked ? scopes.concat(scope) : scopes.filter((s) => s !== scope))
+  }
+
+  const changeExpiry = (option?: SelectOption) => {
+    if (option) {
+      setExpiresInDays(parseInt(option.value, 10))
+    }
+  }
+
+  const create = async () => {
+    const result = await actions.createAPIToken({ name, scopes, expiresInDays })
+    if (result.ok) {
+      setTokens([result.data.token].concat(tokens))
+      setNewKey(result.data.key)
+      setIsAdding(false)
+      setName("")
+      setError(undefined)
+    } else {
+      setError(result.error)
+    }
+  }
+
+  const revoke = async (token: APIToken) => {
+    const result = await actions.revokeAPIToken(token.id)
+    if (result.ok) {
+      setTokens(tokens.filter((t) => t.id !== token.id))
+    }
+  }
+
+  return (
+    <div>
+      <h4 className="text-title mb-1">
+        <Trans id="mysettings.apitokens.title">API Tokens</Trans>
+      </h4>
+      <p className="text-muted">
+        <Trans id="mysettings.apitokens.notice">Tokens only grant the scopes you select, and never more than your own permissions.</Trans>
+      </p>
+      {newKey && (
+        <p className="text-muted">
+          <Trans id="mysettings.apitokens.newkey">
+            Your new token is: <code>{newKey}</code>. It will not be shown again.
+          </Trans>
+        </p>
+      )}
+      <VStack spacing={2} divide={true} className="mb-2">
+        {tokens.map((t) => (
+          <HStack key={t.id} justify="between">
+            <VStack spacing={0}>
+              <span>
+                <strong>{t.name}</strong> <code className="text-xs">{t.prefix}…</code>
+              </span>
+              <span className="text-muted text-sm">
+                {t.scopes.join(", ")}
+                {" · "}
+                {t.expiresAt ? (
+                  <Trans id="mysettings.apitokens.expires">
+                    expires <Moment locale={fider.currentLocale} date={t.expiresAt} />
+                  </Trans>
+                ) : (
+                  <Trans id="mysettings.apitokens.noexpiry">never expires</Trans>
+                )}
+                {t.lastUsedAt && (
+                  <>
+                    {" · "}
+                    <Trans id="mysettings.apitokens.lastused">
+                      last used <Moment locale={fider.currentLocale} date={t.lastUsedAt} />
+                    </Trans>
+                  </>
+                )}
+              </span>
+            </VStack>
+            <Button size="small" variant="tertiary" onClick={() => revoke(t)}>
+              <Trans id="mysettings.apitokens.revoke">Revoke</Trans>
+            </Button>
+          </HStack>
+        ))}
+      </VStack>
+      {isAdding ? (
+        <Form error={error}>
+          <Input field="name" label={i18n._({ id: "mysettings.apitokens.name.label", message: "Name" })} value={name} onChange={setName} />
+          <div className="mb-4">
+            <label>
+              <Trans id="mysettings.apitokens.scopes.label">Scopes</Trans>
+            </label>
+            {availableScopes.map((scope) => (
+              <Checkbox key={scope} field={`scope-${scope}`} checked={scopes.includes(scope)} onChange={toggleScope(scope)}>
+                <code>{scope}</code>
+              </Checkbox>
+            ))}
+          </div>
+          <Select
+            field="expiresInDays"
+            label={i18n._({ id: "mysettings.apitokens.expiry.label", message: "Expiration" })}
+            defaultValue={expiresInDays.toString()}
+            options={expiryOptions}
+            onChange={changeExpiry}
+          />
+          <HStack>
+            <Button variant="primary" size="small" onClick={create}>
+              <Trans id="mysettings.apitokens.create">Create token</Trans>
+            </Button>
+            <Button variant="tertiary" size="small" onClick={() => setIsAdding(false)}>
+              <Trans id="action.cancel">Cancel</Trans>
+            </Button>
+          </HStack>
+        </Form>
+      ) : (
+        <Button size="small" onClick={() => setIsAdding(true)}>
+          <Trans id="mysettings.apitokens.new">New token</Trans>
+        </Button>
+      )}
+    </div>
+  )
+}
@@ -1,22 +1,36 @@
-import React, { useState } from "react"
+import React, { useEffect, useState } from "react"
 
-import { UserSettings } from "@fider/models"
-import { Toggle, Field } from "@fider/components"
+import { Tag, UserSettings } from "@fider/models"
+import { Toggle, Field, Select, SelectOption, Button, Moment, ShowTag } from "@fider/components"
+import { actions, Fider, push } from "@fider/services"
 import { HStack, VStack } from "@fider/components/layout"
 import { i18n } from "@lingui/core"
 import { Trans } from "@lingui/react/macro"
 
 interface NotificationSettingsProps {
   userSettings: UserSettings
+  tags: Tag[]
+  followedTags: Tag[]
   settingsChanged: (settings: UserSettings) => void
 }
 
 type Channel = number
 const WebChannel: Channel = 1
 const EmailChannel: Channel = 2
+const PushChannel: Channel = 8
+const DigestFrequencyKey = "email_digest_frequency"
+export const SnoozedUntilKey = "notifications_snoozed_until"
 
 export const NotificationSettings = (props: NotificationSettingsProps) => {
   const [userSettings, setUserSettings] = useState(props.userSettings)
+  const [isPushSubscribed, setIsPushSubscribed] = useState(false)
+  const [followedTagIDs, setFollowedTagIDs] = useState(props.followedTags.map((t) => t.id))
+  const [snoozeDays, setSnoozeDays] = useState("1")
+  const isPushSupported = push.isSupported()
+
+  useEffect(() => {
+    push.isSubscribed().then(setIsPushSubscribed)
+  }, [])
 
   const isEnabled = (settingsKey: string, channel: Channel): boolean => {
     if (settingsKey in userSettings) {
@@ -26,6 +40,15 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
   }
 
   const toggle = async (settingsKey: string, channel: Channel) => {
+    // Push notifications can only be enabled once this browser is subscribed to them
+    if (channel === PushChannel && !isEnabled(settingsKey, channel) && !isPushSubscribed) {
+      const subscribed = await push.subscribe()
+      setIsPushSubscribed(subscribed)
+      if (!subscribed) {
+        return
+      }
+    }
+
     const nextSettings = {
       ...userSettings,
       [settingsKey]: (parseInt(userSettings[settingsKey], 10) ^ channel).toString(),
@@ -34,12 +57,69 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
     props.settingsChanged(nextSettings)
   }
 
+  const changeDigestFrequency = (option?: SelectOption) => {
+    if (option) {
+      const nextSettings = { ...userSettings, [DigestFrequencyKey]: option.value }
+      setUserSettings(nextSettings)
+      props.settingsChanged(nextSettings)
+    }
+  }
+
+  const toggleFollowTag = async (tag: Tag) => {
+    const isFollowing = followedTagIDs.includes(tag.id)
+    const result = isFollowing ? await actions.unfollowTag(tag.slug) : await actions.followTag(tag.slug)
+    if (result.ok) {
+      setFollowedTagIDs(isFollowing ? followedTagIDs.filter((id) => id !== tag.id) : [...followedTagIDs, tag.id])
+    }
+  }
+
+  const snooze = async (days: number) => {
+    const result = await actions.snoozeNotifications(days)
+    if (result.ok) {
+      const nextSettings = { ...userSettings, [SnoozedUntilKey]: result.data.snoozedUntil }
+      setUserSettings(nextSettings)
+      props.settingsChanged(nextSettings)
+    }
+  }
+
+  const changeSnoozeDays = (option?: SelectOption) => {
+    if (option) {
+      setSnoozeDays(option.value)
+    }
+  }
+
+  const snoozedUntil = userSettings[SnoozedUntilKey] && new Date(userSettings[SnoozedUntilKey]) > new Date() ? userSettings[SnoozedUntilKey] : undefined
+
+  const snoozeOptions: SelectOption[] = [
+    { value: "1", label: i18n._({ id: "mysettings.notification.snooze.day", message: "1 day" }) },
+    { value: "3", label: i18n._({ id: "mysettings.notification.snooze.days3", message: "3 days" }) },
+    { value: "7", label: i18n._({ id: "mysettings.notification.snooze.week", message: "1 week" }) },
+    { value: "14", label: i18n._({ id: "mysettings.notification.snooze.weeks2", message: "2 weeks" }) },
+    { value: "30", label: i18n._({ id: "mysettings.notification.snooze.days30", message: "30 days" }) },
+  ]
+
+  const digestFrequencyOptions: SelectOption[] = [
+    { value: "instant", label: i18n._({ id: "mysettings.notification.frequency.instant", message: "Send an email for each event" }) },
+    { value: "daily", label: i18n._({ id: "mysettings.notification.frequency.daily", message: "Daily digest" }) },
+    { value: "weekly", label: i18n._({ id: "mysettings.notification.frequency.weekly", message: "Weekly digest" }) },
+  ]
+
   const labelWeb = i18n._({ id: "mysettings.notification.channelweb", message: "Web" })
   const labelEmail = i18n._({ id: "mysettings.notification.channelemail", message: "Email" })
+  const labelPush = i18n._({ id: "mysettings.notification.channelpush", message: "Push" })
+
+  const togglePushSubscription = async () => {
+    if (isPushSubscribed) {
+      await push.unsubscribe()
+      setIsPushSubscribed(false)
+    } else {
+      setIsPushSubscribed(await push.subscribe())
+    }
+  }
 
   const icon = (settingsKey: string, channel: Channel) => {
     const active = isEnabled(settingsKey, channel)
-    const label = channel === WebChannel ? labelWeb : labelEmail
+    const label = channel === WebChannel ? labelWeb : channel === EmailChannel ? labelEmail : labelPush
     const onToggle = () => toggle(settingsKey, channel)
     return <Toggle key={`${settingsKey}_${channel}`} active={active} label={label} onToggle={onToggle} />
   }
@@ -61,6 +141,7 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
                 <HStack spacing={6}>
                   {icon("event_notification_new_post", WebChannel)}
                   {icon("event_notification_new_post", EmailChannel)}
+                  {isPushSupported && icon("event_notification_new_post", PushChannel)}
                 </HStack>
               </HStack>
             </div>
@@ -72,6 +153,7 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
                 <HStack spacing={6}>
                   {icon("event_notification_new_comment", WebChannel)}
                   {icon("event_notification_new_comment", EmailChannel)}
+                  {isPushSupported && icon("event_notification_new_comment", PushChannel)}
                 </HStack>
               </HStack>
             </div>
@@ -83,6 +165,7 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
                 <HStack spacing={6}>
                   {icon("event_notification_mention", WebChannel)}
                   {icon("event_notification_mention", EmailChannel)}
+                  {isPushSupported && icon("event_notification_mention", PushChannel)}
                 </HStack>
               </HStack>
             </div>
@@ -94,11 +177,87 @@ export const NotificationSettings = (props: NotificationSettingsProps) => {
                 <HStack spacing={6}>
                   {icon("event_notification_change_status", WebChannel)}
                   {icon("event_notification_change_status", EmailChannel)}
+                  {isPushSupported && icon("event_notification_change_status", PushChannel)}
                 </HStack>
               </HStack>
             </div>
           </VStack>
         </div>
+
+        {isPushSupported && (
+          <div className="mt-4">
+            <HStack spacing={4} justify="between">
+              <p className="text-muted">
+                {isPushSubscribed ? (
+                  <Trans id="mysettings.notification.push.enabled">Push notifications are enabled on this browser.</Trans>
+                ) : (
+                  <Trans id="mysettings.notification.push.disabled">Push notifications are disabled on this browser.</Trans>
+                )}
+              </p>
+              <Button size="small" onClick={togglePushSubscription}>
+                {isPushSubscribed ? (
+                  <Trans id="mysettings.notification.push.disable">Disable on this browser</Trans>
+                ) : (
+                  <Trans id="mysettings.notification.push.enable">Enable on this browser</Trans>
+                )}
+              </Button>
+            </HStack>
+          </div>
+        )}
+
+        <div className="mt-4">
+          <Select
+            field="emailDigestFrequency"
+            label={i18n._({ id: "mysettings.notification.frequency.label", message: "Email frequency" })}
+            defaultValue={userSettings[DigestFrequencyKey] || "instant"}
+            options={digestFrequencyOptions}
+            onChange={changeDigestFrequency}
+          />
+        </div>
+
+        {props.tags.length > 0 && (
+          <div className="mt-4">
+            <p className="text-muted mb-2">
+              <Trans id="mysettings.notification.tags.title">Follow tags to be notified about new posts with them, even when new post notifications are off.</Trans>
+            </p>
+            <VStack spacing={2}>
+              {props.tags.map((tag) => (
+                <HStack key={tag.id} justify="between">
+                  <ShowTag tag={tag} />
+                  <Toggle active={followedTagIDs.includes(tag.id)} onToggle={() => toggleFollowTag(tag)} />
+                </HStack>
+              ))}
+            </VStack>
+          </div>
+        )}
+
+        <div className="mt-4">
+          {snoozedUntil ? (
+            <HStack spacing={4} justify="between">
+              <p className="text-muted">
+                <Trans id="mysettings.notification.snooze.active">
+                  All notifications are snoozed until <Moment locale={Fider.currentLocale} date={snoozedUntil} format="full" />.
+                </Trans>
+              </p>
+              <Button size="small" onClick={() => snooze(0)}>
+                <Trans id="mysettings.notification.snooze.resume">Resume now</Trans>
+              </Button>
+            </HStack>
+          ) : (
+            <HStack spacing={4} align="end">
+              <Select
+                field="snoozeDays"
+                label={i18n._({ id: "mysettings.notification.snooze.label", message: "Snooze all notifications for" })}
+                defaultValue={snoozeDays}
+                options={snoozeOptions}
+                onChange={changeSnoozeDays}
+              />
+              <Button size="small" onClick={() => snooze(parseInt(snoozeDays, 10))}>
+                <Trans id="mysettings.notification.snooze.action">Snooze</Trans>
+              </Button>
+            </HStack>
+          )}
+        </div>
       </Field>
     </>
   )
@@ -0,0 +1,79 @@
+import React, { useEffect, useState } from "react"
+import { Button, Moment } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { UserSession } from "@fider/models"
+import { actions } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { Trans } from "@lingui/react/macro"
+
+export const SessionsForm = () => {
+  const fider = useFider()
+  const [sessions, setSessions] = useState<UserSession[]>([])
+  const [currentSessionId, setCurrentSessionId] = useState(0)
+
+  useEffect(() => {
+    actions.listUserSessions().then((result) => {
+      if (result.ok) {
+        setSessions(result.data.sessions)
+        setCurrentSessionId(result.data.currentSessionId)
+      }
+    })
+  }, [])
+
+  const revoke = async (session: UserSession) => {
+    const result = await actions.revokeUserSession(session.id)
+    if (result.ok) {
+      setSessions(sessions.filter((s) => s.id !== session.id))
+    }
+  }
+
+  const signOutEverywhere = async () => {
+    const result = await actions.signOutEverywhere()
+    if (result.ok) {
+      setSessions(sessions.filter((s) => s.id === currentSessionId))
+    }
+  }
+
+  return (
+    <div>
+      <h4 className="text-title mb-1">
+        <Trans id="mysettings.sessions.title">Sessions</Trans>
+      </h4>
+      <p className="text-muted">
+        <Trans id="mysettings.sessions.notice">These are the browsers and devices where you are currently signed in.</Trans>
+      </p>
+      <VStack spacing={2} divide={true} className="mb-2">
+        {sessions.map((s) => (
+          <HStack key={s.id} justify="between">
+            <VStack spacing={0}>
+              <span>
+                <strong>{s.userAgent || "—"}</strong>
+              </span>
+              <span className="text-muted text-sm">
+                {s.ipAddress}
+                {" · "}
+                {s.id === currentSessionId ? (
+                  <Trans id="mysettings.sessions.current">this browser</Trans>
+                ) : (
+                  <Trans id="mysettings.sessions.lastseen">
+                    last seen <Moment locale={fider.currentLocale} date={s.lastSeenAt} />
+                  </Trans>
+                )}
+              </span>
+            </VStack>
+            {s.id !== currentSessionId && (
+              <Button size="small" variant="tertiary" onClick={() => revoke(s)}>
+                <Trans id="mysettings.sessions.revoke">Sign out</Trans>
+              </Button>
+            )}
+          </HStack>
+        ))}
+      </VStack>
+      {sessions.length > 1 && (
+        <Button size="small" variant="danger" onClick={signOutEverywhere}>
+          <Trans id="mysettings.sessions.signouteverywhere">Sign out everywhere else</Trans>
+        </Button>
+      )}
+    </div>
+  )
+}
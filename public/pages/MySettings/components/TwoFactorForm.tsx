@@ -0,0 +1,141 @@
+import React, { useState } from "react"
+import { Button, Form, Input } from "@fider/components"
+import { actions, Failure, Result } from "@fider/services"
+import { TwoFactorSetup } from "@fider/services/actions"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+interface TwoFactorFormProps {
+  enabled: boolean
+  required: boolean
+}
+
+export const TwoFactorForm = (props: TwoFactorFormProps) => {
+  const [enabled, setEnabled] = useState(props.enabled)
+  const [setup, setSetup] = useState<TwoFactorSetup>()
+  const [code, setCode] = useState("")
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([])
+  const [error, setError] = useState<Failure>()
+
+  const startSetup = async () => {
+    const result = await actions.setupTwoFactor()
+    if (result.ok) {
+      setSetup(result.data)
+      setRecoveryCodes([])
+    }
+  }
+
+  const handle = async <T,>(promise: Promise<Result<T>>): Promise<T | undefined> => {
+    const result = await promise
+    if (result.ok) {
+      setCode("")
+      setError(undefined)
+      return result.data
+    }
+    setError(result.error)
+    return undefined
+  }
+
+  const enable = async () => {
+    const data = await handle(actions.enableTwoFactor(code))
+    if (data) {
+      setEnabled(true)
+      setSetup(undefined)
+      setRecoveryCodes(data.recoveryCodes)
+    }
+  }
+
+  const disable = async () => {
+    const data = await handle(actions.disableTwoFactor(code))
+    if (data !== undefined) {
+      setEnabled(false)
+      setRecoveryCodes([])
+    }
+  }
+
+  const regenerate = async () => {
+    const data = await handle(actions.regenerateRecoveryCodes(code))
+    if (data) {
+      setRecoveryCodes(data.recoveryCodes)
+    }
+  }
+
+  const codeInput = (
+    <Input
+      field="code"
+      label={i18n._({ id: "mysettings.twofactor.code", message: "Authentication code" })}
+      placeholder="123456"
+      maxLength={20}
+      value={code}
+      onChange={setCode}
+    />
+  )
+
+  return (
+    <div>
+      <h4 className="text-title mb-1">
+        <Trans id="mysettings.twofactor.title">Two-factor authentication</Trans>
+      </h4>
+      <p className="text-muted">
+        <Trans id="mysettings.twofactor.notice">Protect your account with a code from an authenticator app each time you sign in.</Trans>
+      </p>
+      {props.required && !enabled && (
+        <p className="text-red-700">
+          <Trans id="mysettings.twofactor.required">Staff members of this site are required to enable two-factor authentication before continuing.</Trans>
+        </p>
+      )}
+
+      {recoveryCodes.length > 0 && (
+        <div className="mb-4">
+          <p className="text-muted">
+            <Trans id="mysettings.twofactor.recoverycodes">
+              Store these recovery codes somewhere safe. Each one can be used once to sign in if you lose access to your authenticator app.
+            </Trans>
+          </p>
+          <pre>{recoveryCodes.join("\n")}</pre>
+        </div>
+      )}
+
+      {!enabled && !setup && (
+        <Button size="small" onClick={startSetup}>
+          <Trans id="mysettings.twofactor.enable">Enable two-factor authentication</Trans>
+        </Button>
+      )}
+
+      {!enabled && setup && (
+        <Form error={error}>
+          <p className="text-muted">
+            <Trans id="mysettings.twofactor.setup">
+              Open{" "}
+              <a className="text-link" href={setup.url}>
+                this link
+              </a>{" "}
+              on your phone or enter the key <code>{setup.secret}</code> in your authenticator app, then type the code it shows.
+            </Trans>
+          </p>
+          {codeInput}
+          <Button variant="primary" size="small" onClick={enable}>
+            <Trans id="action.confirm">Confirm</Trans>
+          </Button>
+        </Form>
+      )}
+
+      {enabled && (
+        <Form error={error}>
+          <p className="text-muted">
+            <Trans id="mysettings.twofactor.enabled">Two-factor authentication is enabled. Type a current code to manage it.</Trans>
+          </p>
+          {codeInput}
+          <Button size="small" onClick={regenerate}>
+            <Trans id="mysettings.twofactor.regenerate">Regenerate recovery codes</Trans>
+          </Button>
+          {!props.required && (
+            <Button variant="danger" size="small" onClick={disable}>
+              <Trans id="mysettings.twofactor.disable">Disable</Trans>
+            </Button>
+          )}
+        </Form>
+      )}
+    </div>
+  )
+}
@@ -0,0 +1,118 @@
+import React, { useState } from "react"
+import { Button, SignInControl, TenantLogo } from "@fider/components"
+import { VStack } from "@fider/components/layout"
+import { OAuthAuthorizationRequest } from "@fider/models"
+import { actions } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { Trans } from "@lingui/react/macro"
+import { i18n } from "@lingui/core"
+
+interface OAuthAuthorizePageProps {
+  error?: string
+  clientName: string
+  scopes: string[]
+  request: OAuthAuthorizationRequest
+}
+
+const scopeDescription = (scope: string): string => {
+  switch (scope) {
+    case "openid":
+      return i18n._({ id: "oauthauthorize.scope.openid", message: "Know who you are on this site" })
+    case "profile":
+      return i18n._({ id: "oauthauthorize.scope.profile", message: "See your name and avatar" })
+    case "email":
+      return i18n._({ id: "oauthauthorize.scope.email", message: "See your email address" })
+    case "posts:read":
+      return i18n._({ id: "oauthauthorize.scope.postsread", message: "Read posts and comments" })
+    case "posts:write":
+      return i18n._({ id: "oauthauthorize.scope.postswrite", message: "Create posts, comments and votes on your behalf" })
+    case "roadmap:write":
+      return i18n._({ id: "oauthauthorize.scope.roadmapwrite", message: "Manage the roadmap on your behalf" })
+    case "users:read":
+      return i18n._({ id: "oauthauthorize.scope.usersread", message: "See the list of users" })
+    case "scim":
+      return i18n._({ id: "oauthauthorize.scope.scim", message: "Provision and deprovision users of this site" })
+    case "admin":
+      return i18n._({ id: "oauthauthorize.scope.admin", message: "Administer this site on your behalf" })
+  }
+  return scope
+}
+
+const OAuthAuthorizePage = (props: OAuthAuthorizePageProps) => {
+  const fider = useFider()
+  const [isSubmitting, setIsSubmitting] = useState(false)
+
+  const decide = (approved: boolean) => async () => {
+    setIsSubmitting(true)
+    const result = await actions.authorizeOAuthClient(props.request, approved)
+    if (result.ok) {
+      location.href = result.data.redirectURL
+    } else {
+      setIsSubmitting(false)
+    }
+  }
+
+  const content = () => {
+    if (props.error) {
+      return <p className="text-red-700">{props.error}</p>
+    }
+
+    const clientName = props.clientName
+    const tenantName = fider.session.tenant.name
+
+    if (!fider.session.isAuthenticated) {
+      return (
+        <>
+          <p className="text-title">
+            <Trans id="oauthauthorize.signin">
+              Sign in to <strong>{tenantName}</strong> to continue to <strong>{clientName}</strong>.
+            </Trans>
+          </p>
+          <SignInControl useEmail={true} redirectTo={location.href} />
+        </>
+      )
+    }
+
+    const userName = fider.session.user.name
+    return (
+      <VStack spacing={4}>
+        <p className="text-title">
+          <Trans id="oauthauthorize.title">
+            <strong>{clientName}</strong> wants to access your <strong>{tenantName}</strong> account.
+          </Trans>
+        </p>
+        <div>
+          <p className="text-muted">
+            <Trans id="oauthauthorize.signedinas">
+              Signed in as <strong>{userName}</strong>. This will allow {clientName} to:
+            </Trans>
+          </p>
+          <ul className="list-disc ml-4">
+            {props.scopes.map((scope) => (
+              <li key={scope}>{scopeDescription(scope)}</li>
+            ))}
+          </ul>
+        </div>
+        <div>
+          <Button variant="primary" disabled={isSubmitting} onClick={decide(true)}>
+            <Trans id="oauthauthorize.action.allow">Allow</Trans>
+          </Button>
+          <Button variant="tertiary" disabled={isSubmitting} onClick={decide(false)}>
+            <Trans id="oauthauthorize.action.deny">Deny</Trans>
+          </Button>
+        </div>
+      </VStack>
+    )
+  }
+
+  return (
+    <div id="p-oauth-authorize" className="page container w-max-4xl">
+      <div className="h-20 text-center mb-4">
+        <TenantLogo size={100} />
+      </div>
+      {content()}
+    </div>
+  )
+}
+
+export default OAuthAuthorizePage
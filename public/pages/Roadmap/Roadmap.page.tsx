@@ -1,10 +1,10 @@
 import "./Roadmap.page.scss"
 
 import React, { useEffect, useState } from "react"
-import { RoadmapData } from "@fider/models"
+import { RoadmapData, hasPermission } from "@fider/models"
 import { Loader, Message, Header } from "@fider/components"
-import { roadmap } from "@fider/services"
-import { useFider } from "@fider/hooks"
+import { realtime, roadmap } from "@fider/services"
+import { useFider, useRealtime } from "@fider/hooks"
 import { RoadmapColumn as RoadmapColumnComponent } from "./components/RoadmapColumn"
 import { i18n } from "@lingui/core"
 import { Trans } from "@lingui/react/macro"
@@ -38,6 +38,16 @@ const RoadmapPage = () => {
     }
   }
 
+  // Keep the board in sync with moves made by others, without showing the loader
+  useRealtime<realtime.RoadmapMovedEvent>("roadmap.moved", async () => {
+    try {
+      const roadmapData = await roadmap.getRoadmap()
+      setState({ loading: false, roadmapData })
+    } catch (error) {
+      console.error("Failed to refresh roadmap:", error)
+    }
+  })
+
   const handlePostMoved = async (postNumber: number, fromColumnId: number, toColumnId: number, newPosition: number) => {
     try {
       await roadmap.assignPostToColumn(postNumber, toColumnId, newPosition)
@@ -78,7 +88,7 @@ const RoadmapPage = () => {
     )
   }
 
-  const isStaff = fider.session.isAuthenticated && fider.session.user.isCollaborator
+  const isStaff = fider.session.isAuthenticated && hasPermission(fider.session.user, "roadmap:manage")
 
   return (
     <>
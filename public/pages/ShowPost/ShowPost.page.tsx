@@ -2,14 +2,15 @@ import "./ShowPost.page.scss"
 
 import React, { useState, useEffect, useCallback } from "react"
 
-import { Comment, Post, Tag, Vote, CurrentUser, PostStatus } from "@fider/models"
-import { actions, cache, clearUrlHash, Failure, Fider, notify, timeAgo } from "@fider/services"
+import { Comment, Post, Tag, Vote, CurrentUser, PostStatus, CustomField, UserGroup, hasPermission } from "@fider/models"
+import { actions, cache, clearUrlHash, Failure, Fider, notify, realtime, timeAgo } from "@fider/services"
 import IconDotsHorizontal from "@fider/assets/images/heroicons-dots-horizontal.svg"
 import IconDuplicate from "@fider/assets/images/heroicons-duplicate.svg"
 import { i18n } from "@lingui/core"
 import IconRSS from "@fider/assets/images/heroicons-rss.svg"
 import IconPencil from "@fider/assets/images/heroicons-pencil-alt.svg"
 import IconChat from "@fider/assets/images/heroicons-chat-alt-2.svg"
+import IconClock from "@fider/assets/images/heroicons-clock.svg"
 
 import { ResponseDetails, Button, UserName, Moment, Markdown, Input, Form, Icon, Header, PoweredByFider, Avatar, Dropdown, RSSModal } from "@fider/components"
 import { DiscussionPanel } from "./components/DiscussionPanel"
@@ -24,19 +25,25 @@ import { FollowButton } from "./components/FollowButton"
 import { VoteSection } from "./components/VoteSection"
 import { DeletePostModal } from "./components/DeletePostModal"
 import { ResponseModal } from "./components/ResponseModal"
+import { RevisionsModal } from "./components/RevisionsModal"
 import { VotesPanel } from "./components/VotesPanel"
 import { TagsPanel } from "@fider/pages/ShowPost/components/TagsPanel"
+import { CustomFieldsPanel } from "./components/CustomFieldsPanel"
+import { PostGroupModal } from "./components/PostGroupModal"
 import { t } from "@lingui/macro"
-import { useFider } from "@fider/hooks"
+import { useFider, useRealtime } from "@fider/hooks"
 import { useAttachments } from "@fider/hooks/useAttachments"
 
 interface ShowPostPageProps {
   post: Post
   subscribed: boolean
+  commentsMuted: boolean
   comments: Comment[]
   tags: Tag[]
   votes: Vote[]
   attachments: string[]
+  customFields: CustomField[]
+  groups: UserGroup[]
 }
 
 const oneHour = 3600
@@ -54,15 +61,38 @@ export default function ShowPostPage(props: ShowPostPageProps) {
   const [isRSSModalOpen, setIsRSSModalOpen] = useState(false)
   const [showResponseModal, setShowResponseModal] = useState(false)
   const [showRoadmapModal, setShowRoadmapModal] = useState(false)
+  const [showRevisionsModal, setShowRevisionsModal] = useState(false)
+  const [showGroupModal, setShowGroupModal] = useState(false)
   const [newTitle, setNewTitle] = useState(props.post.title)
   const [newDescription, setNewDescription] = useState(props.post.description)
   const { attachments, handleImageUploaded, getImageSrc } = useAttachments({
     maxAttachments: 3,
   })
   const [highlightedComment, setHighlightedComment] = useState<number | undefined>(undefined)
+  const [comments, setComments] = useState(props.comments)
   const [error, setError] = useState<Failure | undefined>(undefined)
   const fider = useFider()
 
+  useRealtime<realtime.CommentCreatedEvent>("comment.created", async (data) => {
+    if (data.postNumber !== props.post.number || comments.some((c) => c.id === data.commentId)) {
+      return
+    }
+    const result = await actions.listComments(props.post.number)
+    if (result.ok) {
+      // Comments already on the page are kept as they are, only new ones are added
+      setComments((current) => {
+        const known = new Set(current.map((c) => c.id))
+        return [...current, ...result.data.filter((c) => !known.has(c.id))]
+      })
+    }
+  })
+
+  useRealtime<realtime.PostStatusEvent>("post.status", (data) => {
+    if (data.postNumber === props.post.number && data.status !== props.post.status) {
+      notify.success(<Trans id="showpost.realtime.statuschanged">The status of this post has changed, reload the page to see it.</Trans>)
+    }
+  })
+
   const handleRoadmapAssigned = () => {
     notify.success(<Trans id="showpost.roadmap.assigned">Post assigned to roadmap successfully</Trans>)
     // Optionally reload the page or update state
@@ -80,7 +110,7 @@ export default function ShowPostPage(props: ShowPostPageProps) {
       } else {
         // Match, extract numeric ID
         const id = parseInt(result[1])
-        if (props.comments.map((comment) => comment.id).includes(id)) {
+        if (comments.map((comment) => comment.id).includes(id)) {
           newHighlightedComment = id
         } else {
           // Unknown comment
@@ -95,7 +125,7 @@ export default function ShowPostPage(props: ShowPostPageProps) {
       }
       setHighlightedComment(newHighlightedComment)
     },
-    [props.comments]
+    [comments]
   )
 
   useEffect(() => {
@@ -124,6 +154,13 @@ export default function ShowPostPage(props: ShowPostPageProps) {
     }
   }
 
+  const togglePrivacy = async () => {
+    const result = await actions.setPostPrivacy(props.post.number, !props.post.isPrivate)
+    if (result.ok) {
+      location.reload()
+    }
+  }
+
   const canDeletePost = () => {
     const status = PostStatus.Get(props.post.status)
     if (!Fider.session.isAuthenticated || !Fider.session.user.isAdministrator || status.closed) {
@@ -200,16 +237,33 @@ export default function ShowPostPage(props: ShowPostPageProps) {
                             <Dropdown.ListItem onClick={onActionSelected("edit")} icon={IconPencil}>
                               <Trans id="action.edit">Edit</Trans>
                             </Dropdown.ListItem>
-                            {Fider.session.user.isCollaborator && (
+                            {hasPermission(Fider.session.user, "posts:respond") && (
                               <>
                                 <Dropdown.ListItem onClick={onActionSelected("status")} icon={IconChat}>
                                   <Trans id="action.respond">Respond</Trans>
                                 </Dropdown.ListItem>
-                                <Dropdown.ListItem onClick={() => setShowRoadmapModal(true)}>
-                                  <Trans id="action.assignroadmap">Assign to Roadmap</Trans>
+                                <Dropdown.ListItem onClick={() => setShowRevisionsModal(true)} icon={IconClock}>
+                                  <Trans id="action.history">History</Trans>
                                 </Dropdown.ListItem>
+                                <Dropdown.ListItem onClick={togglePrivacy}>
+                                  {props.post.isPrivate ? (
+                                    <Trans id="action.makepublic">Make public</Trans>
+                                  ) : (
+                                    <Trans id="action.makeprivate">Make private</Trans>
+                                  )}
+                                </Dropdown.ListItem>
+                                {props.groups.length > 0 && (
+                                  <Dropdown.ListItem onClick={() => setShowGroupModal(true)}>
+                                    <Trans id="action.restricttogroup">Restrict to group</Trans>
+                                  </Dropdown.ListItem>
+                                )}
                               </>
                             )}
+                            {hasPermission(Fider.session.user, "roadmap:manage") && (
+                              <Dropdown.ListItem onClick={() => setShowRoadmapModal(true)}>
+                                <Trans id="action.assignroadmap">Assign to Roadmap</Trans>
+                              </Dropdown.ListItem>
+                            )}
                           </>
                         )}
                         {canDeletePost() && (
@@ -230,13 +284,27 @@ export default function ShowPostPage(props: ShowPostPageProps) {
                   ) : (
                     <>
                       <h1 className="text-large text-break">{props.post.title}</h1>
+                      {props.post.isPrivate && (
+                        <span className="text-sm text-muted">
+                          <Trans id="showpost.private">Private, only visible to the author and the staff</Trans>
+                        </span>
+                      )}
+                      {!props.post.isPrivate && !!props.post.groupId && (
+                        <span className="text-sm text-muted">
+                          <Trans id="showpost.restricted">Restricted, only visible to members of a group, the author and the staff</Trans>
+                        </span>
+                      )}
                     </>
                   )}
                 </div>
 
                 <DeletePostModal onModalClose={() => setShowDeleteModal(false)} showModal={showDeleteModal} post={props.post} />
                 {Fider.session.isAuthenticated && Fider.session.user.isCollaborator && (
-                  <ResponseModal onCloseModal={() => setShowResponseModal(false)} showModal={showResponseModal} post={props.post} />
+                  <>
+                    <ResponseModal onCloseModal={() => setShowResponseModal(false)} showModal={showResponseModal} post={props.post} />
+                    <RevisionsModal isOpen={showRevisionsModal} onClose={() => setShowRevisionsModal(false)} post={props.post} />
+                    <PostGroupModal isOpen={showGroupModal} onClose={() => setShowGroupModal(false)} post={props.post} groups={props.groups} />
+                  </>
                 )}
                 <AssignToRoadmapModal
                   post={props.post}
@@ -276,12 +344,13 @@ export default function ShowPostPage(props: ShowPostPageProps) {
                 <div className="mt-2">
                   <TagsPanel post={props.post} tags={props.tags} />
                 </div>
+                <CustomFieldsPanel post={props.post} fields={props.customFields || []} />
 
                 <VStack spacing={4}>
                   {!editMode ? (
                     <HStack justify="between" align="start">
                       <VoteSection post={props.post} votes={props.post.votesCount} />
-                      <FollowButton post={props.post} subscribed={props.subscribed} />
+                      <FollowButton post={props.post} subscribed={props.subscribed} commentsMuted={props.commentsMuted} />
                     </HStack>
                   ) : (
                     <HStack>
@@ -307,7 +376,7 @@ export default function ShowPostPage(props: ShowPostPageProps) {
             </div>
 
             <div className="p-show-post__discussion_col">
-              <DiscussionPanel post={props.post} comments={props.comments} highlightedComment={highlightedComment} />
+              <DiscussionPanel post={props.post} comments={comments} highlightedComment={highlightedComment} />
             </div>
           </div>
           <div className="p-show-post__action-col">
@@ -1,7 +1,7 @@
 import React, { useCallback, useState, useEffect } from "react"
 
 import { Post } from "@fider/models"
-import { Avatar, UserName, Button, Form } from "@fider/components"
+import { Avatar, UserName, Button, Form, Checkbox } from "@fider/components"
 import { SignInModal } from "@fider/components"
 
 import { cache, actions, Failure, Fider } from "@fider/services"
@@ -31,6 +31,7 @@ export const CommentInput = (props: CommentInputProps) => {
   const [isSignInModalOpen, setIsSignInModalOpen] = useState(false)
   const [error, setError] = useState<Failure | undefined>(undefined)
   const [isClient, setIsClient] = useState(false)
+  const [isInternal, setIsInternal] = useState(false)
 
   // Use the attachments hook
   const { attachments, handleImageUploaded, getImageSrc, clearAttachments } = useAttachments({
@@ -51,7 +52,7 @@ export const CommentInput = (props: CommentInputProps) => {
 
     const content = getContentFromCache()
 
-    const result = await actions.createComment(props.post.number, content || "", attachments)
+    const result = await actions.createComment(props.post.number, content || "", attachments, isInternal)
     if (result.ok) {
       clearAttachments()
       cache.session.remove(getCacheKey(CACHE_TITLE_KEY))
@@ -101,6 +102,12 @@ export const CommentInput = (props: CommentInputProps) => {
                   onImageUploaded={handleImageUploaded}
                 />
 
+                {Fider.session.isAuthenticated && Fider.session.user.isCollaborator && (
+                  <Checkbox field="isInternal" checked={isInternal} onChange={setIsInternal}>
+                    <Trans id="showpost.commentinput.internal">Internal note, only visible to staff</Trans>
+                  </Checkbox>
+                )}
+
                 {hasContent && (
                   <>
                     <Button variant="primary" onClick={submit} className="mt-2">
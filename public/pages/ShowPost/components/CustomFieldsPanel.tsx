@@ -0,0 +1,153 @@
+import React, { useState } from "react"
+import { CustomField, CustomFieldValue, Post } from "@fider/models"
+import { actions, Failure } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { Button, Checkbox, Field, Form, Input, Select, SelectOption } from "@fider/components"
+import { HStack, VStack } from "@fider/components/layout"
+import { Trans } from "@lingui/react/macro"
+
+export interface CustomFieldsPanelProps {
+  post: Post
+  fields: CustomField[]
+}
+
+const formatValue = (value: CustomFieldValue | undefined) => {
+  if (value === undefined || value === null) {
+    return ""
+  }
+  return Array.isArray(value) ? value.join(", ") : value.toString()
+}
+
+const parseValue = (field: CustomField, value: CustomFieldValue | undefined): CustomFieldValue | null => {
+  if (value === undefined || value === "" || (Array.isArray(value) && value.length === 0)) {
+    return null
+  }
+  if (field.type === "number" || field.type === "user") {
+    return Number(value)
+  }
+  return value
+}
+
+export const CustomFieldsPanel = (props: CustomFieldsPanelProps) => {
+  const fider = useFider()
+  const canEdit = fider.session.isAuthenticated && fider.session.user.isCollaborator
+  const [values, setValues] = useState(props.post.customFields || {})
+  const [draft, setDraft] = useState<{ [key: string]: CustomFieldValue }>({})
+  const [isEditing, setIsEditing] = useState(false)
+  const [error, setError] = useState<Failure | undefined>(undefined)
+
+  if (props.fields.length === 0) {
+    return null
+  }
+
+  const startEdit = () => {
+    setDraft({ ...values })
+    setIsEditing(true)
+  }
+
+  const cancelEdit = () => {
+    setError(undefined)
+    setIsEditing(false)
+  }
+
+  const setDraftValue = (key: string, value: CustomFieldValue) => {
+    setDraft({ ...draft, [key]: value })
+  }
+
+  const toggleOption = (field: CustomField, option: string) => (checked: boolean) => {
+    const current = (draft[field.key] as string[]) || []
+    setDraftValue(field.key, checked ? current.concat(option) : current.filter((o) => o !== option))
+  }
+
+  const save = async () => {
+    const fields: { [key: string]: CustomFieldValue | null } = {}
+    props.fields.forEach((f) => (fields[f.key] = parseValue(f, draft[f.key])))
+
+    const result = await actions.setPostCustomFields(props.post.number, fields)
+    if (result.ok) {
+      setValues(result.data)
+      setError(undefined)
+      setIsEditing(false)
+    } else {
+      setError(result.error)
+    }
+  }
+
+  const renderInput = (field: CustomField) => {
+    const name = `fields.${field.key}`
+    if (field.type === "select") {
+      const options: SelectOption[] = [{ value: "", label: "" }, ...field.options.map((o) => ({ value: o, label: o }))]
+      return (
+        <Select
+          key={field.key}
+          field={name}
+          label={field.name}
+          defaultValue={formatValue(draft[field.key])}
+          options={options}
+          onChange={(o) => setDraftValue(field.key, o ? o.value : "")}
+        />
+      )
+    }
+
+    if (field.type === "multi-select") {
+      const selected = (draft[field.key] as string[]) || []
+      return (
+        <Field key={field.key} label={field.name}>
+          {field.options.map((o) => (
+            <Checkbox key={o} field={`${name}.${o}`} checked={selected.includes(o)} onChange={toggleOption(field, o)}>
+              {o}
+            </Checkbox>
+          ))}
+        </Field>
+      )
+    }
+
+    const placeholder = field.type === "date" ? "YYYY-MM-DD" : undefined
+    return (
+      <Input
+        key={field.key}
+        field={name}
+        label={field.name}
+        placeholder={placeholder}
+        value={formatValue(draft[field.key])}
+        onChange={(v) => setDraftValue(field.key, v)}
+      />
+    )
+  }
+
+  if (isEditing) {
+    return (
+      <Form error={error}>
+        {props.fields.map(renderInput)}
+        <HStack>
+          <Button variant="primary" size="small" onClick={save}>
+            <Trans id="action.save">Save</Trans>
+          </Button>
+          <Button variant="tertiary" size="small" onClick={cancelEdit}>
+            <Trans id="action.cancel">Cancel</Trans>
+          </Button>
+        </HStack>
+      </Form>
+    )
+  }
+
+  const filled = props.fields.filter((f) => formatValue(values[f.key]) !== "")
+  if (filled.length === 0 && !canEdit) {
+    return null
+  }
+
+  return (
+    <VStack spacing={1}>
+      {filled.map((f) => (
+        <div key={f.key} className="text-sm">
+          <span className="text-muted">{f.name}:</span> {formatValue(values[f.key])}
+        </div>
+      ))}
+      {canEdit && (
+        <span className="text-link text-sm" onClick={startEdit}>
+          <Trans id="action.edit">Edit</Trans>
+        </span>
+      )}
+    </VStack>
+  )
+}
@@ -4,6 +4,8 @@ import { actions } from "@fider/services"
 import { useFider } from "@fider/hooks"
 import IconPlus from "@fider/assets/images/heroicons-plus.svg"
 import IconCheck from "@fider/assets/images/heroicons-check.svg"
+import IconVolumeOn from "@fider/assets/images/heroicons-volume-on.svg"
+import IconVolumeOff from "@fider/assets/images/heroicons-volume-off.svg"
 import { VStack } from "@fider/components/layout"
 import { Trans } from "@lingui/macro"
 import { Post } from "@fider/models"
@@ -11,11 +13,13 @@ import { Post } from "@fider/models"
 export interface NotificationsPanelProps {
   post: Post
   subscribed: boolean
+  commentsMuted?: boolean
 }
 
 export const FollowButton = (props: NotificationsPanelProps) => {
   const fider = useFider()
   const [subscribed, setSubscribed] = useState(props.subscribed)
+  const [commentsMuted, setCommentsMuted] = useState(props.commentsMuted || false)
 
   const subscribeOrUnsubscribe = async () => {
     const action = subscribed ? actions.unsubscribe : actions.subscribe
@@ -23,6 +27,16 @@ export const FollowButton = (props: NotificationsPanelProps) => {
     const response = await action(props.post.number)
     if (response.ok) {
       setSubscribed(!subscribed)
+      setCommentsMuted(false)
+    }
+  }
+
+  const muteOrUnmuteComments = async () => {
+    const action = commentsMuted ? actions.unmuteComments : actions.muteComments
+
+    const response = await action(props.post.number)
+    if (response.ok) {
+      setCommentsMuted(!commentsMuted)
     }
   }
 
@@ -46,5 +60,17 @@ export const FollowButton = (props: NotificationsPanelProps) => {
     </Button>
   )
 
-  return <VStack>{button}</VStack>
+  const muteButton = subscribed && (
+    <Button variant="tertiary" size="small" onClick={muteOrUnmuteComments} disabled={fider.isReadOnly}>
+      <Icon sprite={commentsMuted ? IconVolumeOn : IconVolumeOff} />
+      <span>{commentsMuted ? <Trans id="label.unmutecomments">Unmute comments</Trans> : <Trans id="label.mutecomments">Mute comments</Trans>}</span>
+    </Button>
+  )
+
+  return (
+    <VStack>
+      {button}
+      {muteButton}
+    </VStack>
+  )
 }
@@ -0,0 +1,55 @@
+import React, { useState } from "react"
+import { Post, UserGroup } from "@fider/models"
+import { Modal, Button, Select, SelectOption } from "@fider/components"
+import { actions } from "@fider/services"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+interface PostGroupModalProps {
+  post: Post
+  groups: UserGroup[]
+  isOpen: boolean
+  onClose: () => void
+}
+
+export const PostGroupModal = (props: PostGroupModalProps) => {
+  const [groupId, setGroupId] = useState<number>(props.post.groupId || 0)
+
+  const handleSave = async () => {
+    const result = await actions.setPostGroup(props.post.number, groupId)
+    if (result.ok) {
+      location.reload()
+    }
+  }
+
+  const options = [{ value: "0", label: i18n._({ id: "showpost.group.everyone", message: "Everyone" }) }].concat(
+    props.groups.map((g) => ({ value: g.id.toString(), label: g.name }))
+  )
+
+  return (
+    <Modal.Window isOpen={props.isOpen} onClose={props.onClose} size="small">
+      <Modal.Header>
+        <Trans id="showpost.group.title">Restrict to group</Trans>
+      </Modal.Header>
+      <Modal.Content>
+        <p className="text-sm text-muted mb-2">
+          <Trans id="showpost.group.description">Only members of the selected group, the author and the staff will be able to see this post.</Trans>
+        </p>
+        <Select
+          field="groupId"
+          options={options}
+          defaultValue={groupId.toString()}
+          onChange={(option: SelectOption | undefined) => setGroupId(option ? parseInt(option.value, 10) : 0)}
+        />
+      </Modal.Content>
+      <Modal.Footer>
+        <Button variant="tertiary" onClick={props.onClose}>
+          <Trans id="action.cancel">Cancel</Trans>
+        </Button>
+        <Button variant="primary" onClick={handleSave}>
+          <Trans id="action.save">Save</Trans>
+        </Button>
+      </Modal.Footer>
+    </Modal.Window>
+  )
+}
@@ -0,0 +1,75 @@
+import React, { useState } from "react"
+import { Post } from "@fider/models"
+import { actions, Failure } from "@fider/services"
+import { Form, Modal, Button, Input, TextArea } from "@fider/components"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+interface ProxyVoteModalProps {
+  post: Post
+  isOpen: boolean
+  onClose: () => void
+  onVoted: () => void
+}
+
+export const ProxyVoteModal = (props: ProxyVoteModalProps) => {
+  const [email, setEmail] = useState("")
+  const [name, setName] = useState("")
+  const [company, setCompany] = useState("")
+  const [note, setNote] = useState("")
+  const [error, setError] = useState<Failure>()
+
+  const close = () => {
+    setEmail("")
+    setName("")
+    setCompany("")
+    setNote("")
+    setError(undefined)
+    props.onClose()
+  }
+
+  const submit = async () => {
+    const result = await actions.addProxyVote(props.post.number, { email, name, company, note })
+    if (result.ok) {
+      close()
+      props.onVoted()
+    } else {
+      setError(result.error)
+    }
+  }
+
+  return (
+    <Modal.Window isOpen={props.isOpen} onClose={close} center={false}>
+      <Modal.Header>
+        <Trans id="modal.proxyvote.header">Vote on behalf of a customer</Trans>
+      </Modal.Header>
+      <Modal.Content>
+        <Form error={error}>
+          <Input field="email" label={i18n._({ id: "modal.proxyvote.email.label", message: "Email" })} value={email} onChange={setEmail}>
+            <p className="text-muted">
+              <Trans id="modal.proxyvote.email.help">If nobody has signed in with this email yet, a contact is created so they can claim the vote later.</Trans>
+            </p>
+          </Input>
+          <Input field="name" label={i18n._({ id: "modal.proxyvote.name.label", message: "Name (optional)" })} value={name} onChange={setName} />
+          <Input field="company" label={i18n._({ id: "modal.proxyvote.company.label", message: "Company (optional)" })} value={company} onChange={setCompany} />
+          <TextArea
+            field="note"
+            label={i18n._({ id: "modal.proxyvote.note.label", message: "Note (optional)" })}
+            placeholder={i18n._({ id: "modal.proxyvote.note.placeholder", message: "e.g. Mentioned during our quarterly review" })}
+            value={note}
+            onChange={setNote}
+          />
+        </Form>
+      </Modal.Content>
+
+      <Modal.Footer>
+        <Button variant="primary" onClick={submit}>
+          <Trans id="modal.proxyvote.submit">Add vote</Trans>
+        </Button>
+        <Button variant="tertiary" onClick={close}>
+          <Trans id="action.cancel">Cancel</Trans>
+        </Button>
+      </Modal.Footer>
+    </Modal.Window>
+  )
+}
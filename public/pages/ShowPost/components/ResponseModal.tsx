@@ -1,6 +1,6 @@
 import React from "react"
 
-import { Modal, Button, DisplayError, Select, Form, TextArea, Field, SelectOption } from "@fider/components"
+import { Modal, Button, DisplayError, Select, Form, TextArea, Field, SelectOption, Checkbox } from "@fider/components"
 import { Post, PostStatus } from "@fider/models"
 
 import { actions, Failure } from "@fider/services"
@@ -18,6 +18,7 @@ interface ResponseModalState {
   status: string
   text: string
   originalNumber: number
+  copyComments: boolean
   error?: Failure
 }
 
@@ -28,12 +29,16 @@ export class ResponseModal extends React.Component<ResponseModalProps, ResponseM
     this.state = {
       status: this.props.post.status,
       originalNumber: 0,
+      copyComments: false,
       text: this.props.post.response ? this.props.post.response.text : "",
     }
   }
 
   private submit = async () => {
-    const result = await actions.respond(this.props.post.number, this.state)
+    const result =
+      this.state.status === PostStatus.Duplicate.value
+        ? await actions.mergePost(this.props.post.number, this.state)
+        : await actions.respond(this.props.post.number, this.state)
     if (result.ok) {
       location.reload()
     } else {
@@ -53,6 +58,10 @@ export class ResponseModal extends React.Component<ResponseModalProps, ResponseM
     this.setState({ originalNumber })
   }
 
+  private setCopyComments = (copyComments: boolean) => {
+    this.setState({ copyComments })
+  }
+
   private setText = (text: string) => {
     this.setState({ text })
   }
@@ -77,6 +86,9 @@ export class ResponseModal extends React.Component<ResponseModalProps, ResponseM
                   <PostSearch exclude={[this.props.post.number]} onChanged={this.setOriginalNumber} />
                 </Field>
                 <DisplayError fields={["originalNumber"]} error={this.state.error} />
+                <Checkbox field="copyComments" checked={this.state.copyComments} onChange={this.setCopyComments}>
+                  <Trans id="showpost.responseform.copycomments">Copy comments to the original post</Trans>
+                </Checkbox>
                 <span className="text-muted">
                   <Trans id="showpost.responseform.message.mergedvotes">Votes from this post will be merged into original post.</Trans>
                 </span>
@@ -0,0 +1,153 @@
+import "./RevisionsModal.scss"
+
+import React, { useEffect, useState } from "react"
+import { Post, Revision, RevisionChange, RevisionDiff } from "@fider/models"
+import { Modal, Button, Loader, UserName, Moment } from "@fider/components"
+import { actions, classSet } from "@fider/services"
+import { useFider } from "@fider/hooks"
+import { HStack, VStack } from "@fider/components/layout"
+import { Trans } from "@lingui/react/macro"
+
+interface RevisionsModalProps {
+  isOpen: boolean
+  post: Post
+  onClose: () => void
+}
+
+const RevisionKindLabel = (props: { revision: Revision }) => {
+  switch (props.revision.kind) {
+    case "post":
+      return <Trans id="modal.revisions.kind.post">Post</Trans>
+    case "response":
+      return <Trans id="modal.revisions.kind.response">Response</Trans>
+    default:
+      return <Trans id="modal.revisions.kind.comment">Comment</Trans>
+  }
+}
+
+const DiffView = (props: { changes: RevisionChange[] }) => {
+  return (
+    <div className="c-revisions-modal__diff">
+      {props.changes.map((c, i) => (
+        <span
+          key={i}
+          className={classSet({
+            "bg-green-100": c.op === "insert",
+            "bg-red-100 c-revisions-modal__delete": c.op === "delete",
+          })}
+        >
+          {c.text}
+        </span>
+      ))}
+    </div>
+  )
+}
+
+const isLatest = (revisions: Revision[], revision: Revision) => {
+  const latest = revisions.find((r) => r.kind === revision.kind && r.commentId === revision.commentId)
+  return latest?.id === revision.id
+}
+
+export const RevisionsModal: React.FC<RevisionsModalProps> = (props) => {
+  const [isLoading, setIsLoading] = useState(true)
+  const [revisions, setRevisions] = useState<Revision[]>([])
+  const [selected, setSelected] = useState<Revision | undefined>()
+  const [diff, setDiff] = useState<RevisionDiff | undefined>()
+
+  const fider = useFider()
+
+  useEffect(() => {
+    if (props.isOpen) {
+      setIsLoading(true)
+      actions.listRevisions(props.post.number).then((response) => {
+        if (response.ok) {
+          setRevisions(response.data)
+          setIsLoading(false)
+          if (response.data.length > 0) {
+            select(response.data[0])
+          }
+        }
+      })
+    }
+  }, [props.isOpen])
+
+  const select = async (revision: Revision) => {
+    setSelected(revision)
+    setDiff(undefined)
+    const response = await actions.getRevisionDiff(props.post.number, revision.id)
+    if (response.ok) {
+      setDiff(response.data)
+    }
+  }
+
+  const restore = async () => {
+    if (!selected) {
+      return
+    }
+
+    const response = await actions.restoreRevision(props.post.number, selected.id)
+    if (response.ok) {
+      location.reload()
+    }
+  }
+
+  return (
+    <Modal.Window isOpen={props.isOpen} center={false} size="large" onClose={props.onClose}>
+      <Modal.Header>
+        <Trans id="modal.revisions.header">Revision history</Trans>
+      </Modal.Header>
+      <Modal.Content>
+        {isLoading && <Loader />}
+        {!isLoading && (
+          <HStack align="start" spacing={4}>
+            <VStack spacing={1} className="c-revisions-modal__list">
+              {revisions.map((r) => (
+                <div
+                  key={r.id}
+                  className={classSet({ "c-revisions-modal__item": true, "c-revisions-modal__item--selected": r.id === selected?.id })}
+                  onClick={() => select(r)}
+                >
+                  <VStack spacing={0}>
+                    <strong>
+                      <RevisionKindLabel revision={r} />
+                    </strong>
+                    <UserName user={r.createdBy} />
+                    <span className="text-muted text-sm">
+                      <Moment locale={fider.currentLocale} date={r.createdAt} />
+                    </span>
+                  </VStack>
+                </div>
+              ))}
+            </VStack>
+            <div className="c-revisions-modal__content">
+              {selected && !diff && <Loader />}
+              {diff && (
+                <VStack>
+                  {diff.to.kind === "post" && (
+                    <h3 className="text-title">
+                      <DiffView changes={diff.title} />
+                    </h3>
+                  )}
+                  <DiffView changes={diff.content} />
+                </VStack>
+              )}
+            </div>
+          </HStack>
+        )}
+      </Modal.Content>
+
+      <Modal.Footer>
+        <HStack>
+          {selected && !isLatest(revisions, selected) && (
+            <Button variant="primary" onClick={restore}>
+              <Trans id="modal.revisions.restore">Restore this revision</Trans>
+            </Button>
+          )}
+          <Button variant="tertiary" onClick={props.onClose}>
+            <Trans id="action.close">Close</Trans>
+          </Button>
+        </HStack>
+      </Modal.Footer>
+    </Modal.Window>
+  )
+}
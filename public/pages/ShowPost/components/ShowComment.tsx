@@ -1,5 +1,5 @@
 import React, { useEffect, useRef, useState } from "react"
-import { Comment, Post } from "@fider/models"
+import { Comment, Post, hasPermission } from "@fider/models"
 import { Reactions, Avatar, UserName, Moment, Form, Button, Markdown, Modal, Dropdown, Icon } from "@fider/components"
 import { HStack } from "@fider/components/layout"
 import { formatDate, Failure, actions, notify, copyToClipboard, classSet, clearUrlHash } from "@fider/services"
@@ -50,7 +50,7 @@ export const ShowComment = (props: ShowCommentProps) => {
 
   const canEditComment = (): boolean => {
     if (fider.session.isAuthenticated) {
-      return fider.session.user.isCollaborator || props.comment.user.id === fider.session.user.id
+      return hasPermission(fider.session.user, "comments:moderate") || props.comment.user.id === fider.session.user.id
     }
     return false
   }
@@ -159,8 +159,9 @@ export const ShowComment = (props: ShowCommentProps) => {
 
   const classList = classSet({
     "flex-grow rounded-md p-2 comment-area": true,
-    "bg-gray-100": !props.highlighted,
+    "bg-gray-100": !props.highlighted && !comment.isInternal,
     "bg-gray-200": props.highlighted,
+    "bg-yellow-50": !props.highlighted && comment.isInternal,
   })
 
   return (
@@ -177,6 +178,12 @@ export const ShowComment = (props: ShowCommentProps) => {
                 <UserName user={comment.user} />{" "}
                 <div className="text-xs">
                   · <Moment locale={fider.currentLocale} date={comment.createdAt} /> {editedMetadata}
+                  {comment.isInternal && (
+                    <>
+                      {" "}
+                      · <Trans id="showpost.comment.internal">Internal note</Trans>
+                    </>
+                  )}
                 </div>
               </HStack>
               {!isEditing && (
@@ -1,5 +1,5 @@
 import React, { useState } from "react"
-import { Post, Tag } from "@fider/models"
+import { Post, Tag, hasPermission } from "@fider/models"
 import { actions } from "@fider/services"
 import { useFider } from "@fider/hooks"
 import { TagsSelect } from "@fider/components/common/TagsSelect"
@@ -11,7 +11,7 @@ export interface TagsPanelProps {
 
 export const TagsPanel = (props: TagsPanelProps) => {
   const fider = useFider()
-  const canEdit = fider.session.isAuthenticated && fider.session.user.isCollaborator && props.tags.length > 0
+  const canEdit = fider.session.isAuthenticated && hasPermission(fider.session.user, "posts:respond") && props.tags.length > 0
 
   const [assignedTags, setAssignedTags] = useState(props.tags.filter((t) => props.post.tags.indexOf(t.slug) >= 0))
 
@@ -1,8 +1,8 @@
-import React, { useState } from "react"
-import { Post, PostStatus } from "@fider/models"
-import { actions } from "@fider/services"
+import React, { useEffect, useState } from "react"
+import { Post, PostStatus, VoteBudget } from "@fider/models"
+import { actions, realtime } from "@fider/services"
 import { Button, Icon, SignInModal } from "@fider/components"
-import { useFider } from "@fider/hooks"
+import { useFider, useRealtime } from "@fider/hooks"
 import IconThumbsUp from "@fider/assets/images/heroicons-thumbsup.svg"
 import IconCheck from "@fider/assets/images/heroicons-check.svg"
 import { Trans } from "@lingui/macro"
@@ -15,10 +15,31 @@ interface VoteSectionProps {
 
 export const VoteSection = (props: VoteSectionProps) => {
   const fider = useFider()
+  const votingMode = fider.session.tenant.votingMode
   const [votes, setVotes] = useState(props.votes)
+  const [weight, setWeight] = useState(props.post.votesWeight)
+  const [myWeight, setMyWeight] = useState(props.post.myVoteWeight)
   const [hasVoted, setHasVoted] = useState(props.post.hasVoted)
+  const [budget, setBudget] = useState<VoteBudget | undefined>()
   const [isSignInModalOpen, setIsSignInModalOpen] = useState(false)
 
+  useEffect(() => {
+    if (votingMode === "budget" && fider.session.isAuthenticated) {
+      actions.getVoteBudget().then((response) => {
+        if (response.ok) {
+          setBudget(response.data)
+        }
+      })
+    }
+  }, [])
+
+  useRealtime<realtime.PostVotesEvent>("post.votes", (data) => {
+    if (data.postNumber === props.post.number) {
+      setVotes(data.votesCount)
+      setWeight(data.votesWeight)
+    }
+  })
+
   const voteOrUndo = async () => {
     if (!fider.session.isAuthenticated) {
       setIsSignInModalOpen(true)
@@ -32,6 +53,29 @@ export const VoteSection = (props: VoteSectionProps) => {
     }
   }
 
+  const changeVote = async (newWeight: number) => {
+    if (!fider.session.isAuthenticated) {
+      setIsSignInModalOpen(true)
+      return
+    }
+
+    const response = newWeight > 0 ? await actions.addVote(props.post.number, newWeight) : await actions.removeVote(props.post.number)
+    if (response.ok) {
+      if (myWeight === 0 && newWeight > 0) {
+        setVotes(votes + 1)
+      } else if (myWeight > 0 && newWeight === 0) {
+        setVotes(votes - 1)
+      }
+      if (budget) {
+        const spent = budget.spent - myWeight + newWeight
+        setBudget({ ...budget, spent, remaining: budget.total - spent })
+      }
+      setWeight(weight - myWeight + newWeight)
+      setMyWeight(newWeight)
+      setHasVoted(newWeight > 0)
+    }
+  }
+
   const hideModal = () => setIsSignInModalOpen(false)
 
   const status = PostStatus.Get(props.post.status)
@@ -40,9 +84,58 @@ export const VoteSection = (props: VoteSectionProps) => {
   const buttonText = hasVoted ? <Trans id="action.voted">Voted!</Trans> : <Trans id="action.vote">Vote for this idea</Trans>
   const icon = hasVoted ? IconCheck : IconThumbsUp
 
-  return (
-    <VStack spacing={4}>
-      <SignInModal isOpen={isSignInModalOpen} onClose={hideModal} />
+  const renderVoteControls = () => {
+    if (votingMode === "importance") {
+      const levels = [
+        { weight: 1, label: <Trans id="showpost.votesection.importance.nicetohave">Nice to have</Trans> },
+        { weight: 2, label: <Trans id="showpost.votesection.importance.important">Important</Trans> },
+        { weight: 3, label: <Trans id="showpost.votesection.importance.critical">Critical</Trans> },
+      ]
+      return (
+        <VStack spacing={2} className="align-self-start">
+          {levels.map((level) => (
+            <Button
+              key={level.weight}
+              variant={myWeight === level.weight ? "primary" : "secondary"}
+              onClick={() => changeVote(myWeight === level.weight ? 0 : level.weight)}
+              disabled={isDisabled}
+              style={{ minWidth: "180px" }}
+            >
+              {level.label}
+            </Button>
+          ))}
+        </VStack>
+      )
+    }
+
+    if (votingMode === "budget") {
+      const maxPerPost = fider.session.tenant.maxVotesPerPost
+      const remaining = budget ? budget.remaining : 0
+      const total = budget ? budget.total : 0
+      const canAdd = myWeight < maxPerPost && (!budget || remaining > 0)
+      return (
+        <VStack spacing={2} className="align-self-start">
+          <HStack spacing={2}>
+            <Button variant="secondary" onClick={() => changeVote(myWeight - 1)} disabled={isDisabled || myWeight === 0}>
+              -
+            </Button>
+            <span className="text-semibold text-lg">{myWeight}</span>
+            <Button variant="primary" onClick={() => changeVote(myWeight + 1)} disabled={isDisabled || !canAdd}>
+              +
+            </Button>
+          </HStack>
+          {budget && (
+            <span className="text-muted text-sm">
+              <Trans id="showpost.votesection.budget.remaining">
+                You have {remaining} of {total} votes left
+              </Trans>
+            </span>
+          )}
+        </VStack>
+      )
+    }
+
+    return (
       <div className="align-self-start">
         <Button variant="primary" onClick={voteOrUndo} disabled={isDisabled} style={{ minWidth: "180px" }}>
           <HStack spacing={2} justify="center" className="w-full">
@@ -50,11 +143,26 @@ export const VoteSection = (props: VoteSectionProps) => {
           </HStack>
         </Button>
       </div>
+    )
+  }
+
+  const isWeighted = votingMode === "budget" || votingMode === "importance"
+
+  return (
+    <VStack spacing={4}>
+      <SignInModal isOpen={isSignInModalOpen} onClose={hideModal} />
+      {renderVoteControls()}
       <HStack align="center">
         <span className="text-semibold text-2xl" style={{ fontSize: "32px", minHeight: "48px" }}>
-          {votes}
+          {isWeighted ? weight : votes}
         </span>
-        <span className="text-semibold text-lg">{votes === 1 ? "Vote" : "Votes"}</span>
+        {isWeighted ? (
+          <span className="text-semibold text-lg">
+            <Trans id="showpost.votesection.points">points from {votes} voters</Trans>
+          </span>
+        ) : (
+          <span className="text-semibold text-lg">{votes === 1 ? "Vote" : "Votes"}</span>
+        )}
       </HStack>
     </VStack>
   )
@@ -15,6 +15,20 @@ interface VotesModalProps {
   onClose?: () => void
 }
 
+const VoteProxy = (props: { vote: Vote }) => {
+  if (!props.vote.proxiedBy) {
+    return null
+  }
+
+  const name = props.vote.proxiedBy.name
+  return (
+    <span className="text-muted text-sm">
+      <Trans id="modal.showvotes.proxiedby">Added by {name}</Trans>
+      {props.vote.note && `: ${props.vote.note}`}
+    </span>
+  )
+}
+
 export const VotesModal: React.FC<VotesModalProps> = (props) => {
   const [isLoading, setIsLoading] = useState(false)
   const [query, setQuery] = useState("")
@@ -22,6 +36,7 @@ export const VotesModal: React.FC<VotesModalProps> = (props) => {
   const [filteredVotes, setFilteredVotes] = useState<Vote[]>([])
 
   const fider = useFider()
+  const isWeighted = fider.session.tenant.votingMode === "budget" || fider.session.tenant.votingMode === "importance"
 
   useEffect(() => {
     if (props.isOpen) {
@@ -72,12 +87,19 @@ export const VotesModal: React.FC<VotesModalProps> = (props) => {
                     <Avatar user={x.user} />
                     <VStack spacing={0}>
                       <UserName user={x.user} />
-                      <span className="text-muted">{x.user.email}</span>
+                      <span className="text-muted">
+                        {x.user.email}
+                        {x.user.company && ` · ${x.user.company.name}`}
+                      </span>
+                      <VoteProxy vote={x} />
                     </VStack>
                   </HStack>
-                  <span className="text-muted">
-                    <Moment locale={fider.currentLocale} date={x.createdAt} />
-                  </span>
+                  <HStack>
+                    {isWeighted && <span className="text-semibold">+{x.weight}</span>}
+                    <span className="text-muted">
+                      <Moment locale={fider.currentLocale} date={x.createdAt} />
+                    </span>
+                  </HStack>
                 </HStack>
               ))}
               {filteredVotes.length === 0 && (
@@ -4,6 +4,7 @@ import { AvatarStack, Button } from "@fider/components"
 import { Fider } from "@fider/services"
 import { useFider } from "@fider/hooks"
 import { VotesModal } from "./VotesModal"
+import { ProxyVoteModal } from "./ProxyVoteModal"
 import { HStack, VStack } from "@fider/components/layout"
 import { Trans } from "@lingui/react/macro"
 
@@ -16,6 +17,7 @@ interface VotesPanelProps {
 export const VotesPanel = (props: VotesPanelProps) => {
   const fider = useFider()
   const [isVotesModalOpen, setIsVotesModalOpen] = useState(false)
+  const [isProxyVoteModalOpen, setIsProxyVoteModalOpen] = useState(false)
   const canShowAll = fider.session.isAuthenticated && Fider.session.user.isCollaborator
 
   const openModal = () => {
@@ -25,12 +27,17 @@ export const VotesPanel = (props: VotesPanelProps) => {
   }
 
   const closeModal = () => setIsVotesModalOpen(false)
+  const openProxyVoteModal = () => setIsProxyVoteModalOpen(true)
+  const closeProxyVoteModal = () => setIsProxyVoteModalOpen(false)
+  const handleProxyVoted = () => location.reload()
 
   const extraVotesCount = props.post.votesCount - props.votes.length
+  const revenue = props.post.votesRevenue
 
   return (
     <VStack spacing={4}>
       <VotesModal post={props.post} isOpen={isVotesModalOpen} onClose={closeModal} />
+      {canShowAll && <ProxyVoteModal post={props.post} isOpen={isProxyVoteModalOpen} onClose={closeProxyVoteModal} onVoted={handleProxyVoted} />}
       {!props.hideTitle && (
         <span className="text-category">
           <Trans id="label.voters">Voters</Trans>
@@ -56,6 +63,16 @@ export const VotesPanel = (props: VotesPanelProps) => {
           <Trans id="label.none">None</Trans>
         </span>
       )}
+      {canShowAll && !!revenue && (
+        <span className="text-muted text-sm">
+          <Trans id="showpost.votespanel.revenue">Revenue: {revenue}</Trans>
+        </span>
+      )}
+      {canShowAll && (
+        <Button variant="tertiary" size="small" onClick={openProxyVoteModal}>
+          <Trans id="showpost.votespanel.proxyvote">Vote on behalf of a customer</Trans>
+        </Button>
+      )}
     </VStack>
   )
 }
@@ -0,0 +1,66 @@
+import React, { useState } from "react"
+import { Button, Form, Input, TenantLogo } from "@fider/components"
+import { actions, Failure, navigator } from "@fider/services"
+import { i18n } from "@lingui/core"
+import { Trans } from "@lingui/react/macro"
+
+interface TwoFactorPageProps {
+  redirect: string
+  isSigningIn: boolean
+}
+
+const TwoFactorPage = (props: TwoFactorPageProps) => {
+  const [code, setCode] = useState("")
+  const [error, setError] = useState<Failure>()
+
+  const verify = async () => {
+    const result = await actions.verifyTwoFactorCode(code)
+    if (result.ok) {
+      navigator.goTo(props.redirect)
+    } else {
+      setError(result.error)
+    }
+  }
+
+  return (
+    <div id="p-two-factor" className="page container w-max-6xl">
+      <div className="h-20 text-center mb-4">
+        <TenantLogo size={100} />
+      </div>
+      <div className="w-max-4xl mx-auto">
+        <p className="text-title">
+          {props.isSigningIn ? (
+            <Trans id="signin.twofactor.title">Enter the code from your authenticator app to finish signing in.</Trans>
+          ) : (
+            <Trans id="signin.twofactor.reauth">Enter the code from your authenticator app to confirm it&apos;s you before continuing.</Trans>
+          )}
+        </p>
+        <Form error={error}>
+          <Input
+            field="code"
+            label={i18n._({ id: "mysettings.twofactor.code", message: "Authentication code" })}
+            placeholder="123456"
+            maxLength={20}
+            autoFocus={true}
+            value={code}
+            onChange={setCode}
+          >
+            <p className="text-muted">
+              <Trans id="signin.twofactor.recovery">Lost your device? Use one of your recovery codes instead.</Trans>
+            </p>
+          </Input>
+          <Button variant="primary" type="submit" onClick={verify}>
+            <Trans id="action.confirm">Confirm</Trans>
+          </Button>
+          {props.isSigningIn && (
+            <Button variant="tertiary" href="/signout">
+              <Trans id="action.cancel">Cancel</Trans>
+            </Button>
+          )}
+        </Form>
+      </div>
+    </div>
+  )
+}
+
+export default TwoFactorPage
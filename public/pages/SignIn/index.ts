@@ -1,3 +1,4 @@
 export * from "./SignIn.page"
 export * from "./CompleteSignInProfile.page"
 export * from "./LoginEmailSent.page"
+export * from "./TwoFactor.page"
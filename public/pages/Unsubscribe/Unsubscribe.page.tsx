@@ -0,0 +1,84 @@
+import React, { useState } from "react"
+import { Button, TenantLogo } from "@fider/components"
+import { actions } from "@fider/services"
+import { Trans } from "@lingui/react/macro"
+
+interface UnsubscribePageProps {
+  token: string
+  event: string
+  post?: {
+    number: number
+    slug: string
+    title: string
+  }
+}
+
+const UnsubscribePage = (props: UnsubscribePageProps) => {
+  const [isUnsubscribed, setIsUnsubscribed] = useState(false)
+
+  const unsubscribe = async () => {
+    const result = await actions.unsubscribeByToken(props.token)
+    if (result.ok) {
+      setIsUnsubscribed(true)
+    }
+  }
+
+  const description = () => {
+    if (props.post) {
+      const title = props.post.title
+      return (
+        <Trans id="unsubscribe.post">
+          You will no longer receive emails about <strong>{title}</strong>.
+        </Trans>
+      )
+    }
+    if (props.event === "event_notification_new_post") {
+      return <Trans id="unsubscribe.event.newpost">You will no longer receive emails when new posts are created.</Trans>
+    }
+    if (props.event === "event_notification_mention") {
+      return <Trans id="unsubscribe.event.mention">You will no longer receive emails when someone mentions you.</Trans>
+    }
+    if (props.event === "event_notification_change_status") {
+      return <Trans id="unsubscribe.event.changestatus">You will no longer receive emails when a post is updated or deleted.</Trans>
+    }
+    if (props.event === "event_notification_new_comment") {
+      return <Trans id="unsubscribe.event.newcomment">You will no longer receive emails when someone leaves a comment.</Trans>
+    }
+    return <Trans id="unsubscribe.all">You will no longer receive email notifications from this site.</Trans>
+  }
+
+  return (
+    <div id="p-unsubscribe" className="page container w-max-6xl">
+      <div className="h-20 text-center mb-4">
+        <TenantLogo size={100} />
+      </div>
+      <div className="w-max-4xl mx-auto text-center">
+        {isUnsubscribed ? (
+          <>
+            <p className="text-title">
+              <Trans id="unsubscribe.success">You have been unsubscribed.</Trans>
+            </p>
+            <p className="text-muted">
+              <Trans id="unsubscribe.settings">
+                You can choose which notifications you receive from your{" "}
+                <a className="text-link" href="/settings">
+                  settings
+                </a>
+                .
+              </Trans>
+            </p>
+          </>
+        ) : (
+          <>
+            <p className="text-title">{description()}</p>
+            <Button variant="primary" onClick={unsubscribe}>
+              <Trans id="label.unsubscribe">Unsubscribe</Trans>
+            </Button>
+          </>
+        )}
+      </div>
+    </div>
+  )
+}
+
+export default UnsubscribePage
@@ -0,0 +1,12 @@
+import { http, Result } from "@fider/services/http"
+import { stringify } from "@fider/services/querystring"
+import { AuditLogEntry } from "@fider/models"
+
+export interface SearchAuditLogsParams {
+  action?: string
+  actorId?: number
+}
+
+export const searchAuditLogs = async (params: SearchAuditLogsParams): Promise<Result<AuditLogEntry[]>> => {
+  return await http.get<AuditLogEntry[]>(`/api/v1/audit-log${stringify({ action: params.action, actorId: params.actorId })}`)
+}
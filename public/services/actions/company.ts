@@ -0,0 +1,28 @@
+import { http, Result } from "@fider/services/http"
+import { Company } from "@fider/models"
+
+interface CompanyInput {
+  name: string
+  mrr: number
+  plan: string
+}
+
+export const listCompanies = async (): Promise<Result<Company[]>> => {
+  return http.get<Company[]>(`/api/v1/companies`)
+}
+
+export const createCompany = async (input: CompanyInput): Promise<Result<Company>> => {
+  return http.post<Company>(`/api/v1/companies`, input).then(http.event("company", "create"))
+}
+
+export const updateCompany = async (id: number, input: CompanyInput): Promise<Result<Company>> => {
+  return http.put<Company>(`/api/v1/companies/${id}`, input).then(http.event("company", "update"))
+}
+
+export const deleteCompany = async (id: number): Promise<Result> => {
+  return http.delete(`/api/v1/companies/${id}`).then(http.event("company", "delete"))
+}
+
+export const setUserCompany = async (userID: number, companyID: number): Promise<Result> => {
+  return http.put(`/api/v1/users/${userID}/company`, { companyID }).then(http.event("user", "set-company"))
+}
@@ -0,0 +1,29 @@
+import { http, Result } from "@fider/services/http"
+import { CustomField, CustomFieldType, CustomFieldValue } from "@fider/models"
+
+interface CustomFieldInput {
+  name: string
+  type: CustomFieldType
+  options: string[]
+  isPublic: boolean
+  position?: number
+}
+
+export const createCustomField = async (input: CustomFieldInput): Promise<Result<CustomField>> => {
+  return http.post<CustomField>(`/api/v1/custom-fields`, input).then(http.event("custom-field", "create"))
+}
+
+export const updateCustomField = async (key: string, input: CustomFieldInput): Promise<Result<CustomField>> => {
+  return http.put<CustomField>(`/api/v1/custom-fields/${key}`, input).then(http.event("custom-field", "update"))
+}
+
+export const deleteCustomField = async (key: string): Promise<Result> => {
+  return http.delete(`/api/v1/custom-fields/${key}`).then(http.event("custom-field", "delete"))
+}
+
+export const setPostCustomFields = async (
+  postNumber: number,
+  fields: { [key: string]: CustomFieldValue | null }
+): Promise<Result<{ [key: string]: CustomFieldValue }>> => {
+  return http.put<{ [key: string]: CustomFieldValue }>(`/api/v1/posts/${postNumber}/custom-fields`, { fields }).then(http.event("post", "set-custom-fields"))
+}
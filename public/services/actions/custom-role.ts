@@ -0,0 +1,27 @@
+import { http, Result } from "@fider/services/http"
+import { CustomRole, Permission } from "@fider/models"
+
+interface CustomRoleInput {
+  name: string
+  permissions: Permission[]
+}
+
+export const listCustomRoles = async (): Promise<Result<CustomRole[]>> => {
+  return http.get<CustomRole[]>(`/api/v1/roles`)
+}
+
+export const createCustomRole = async (input: CustomRoleInput): Promise<Result<CustomRole>> => {
+  return http.post<CustomRole>(`/api/v1/roles`, input).then(http.event("role", "create"))
+}
+
+export const updateCustomRole = async (id: number, input: CustomRoleInput): Promise<Result<CustomRole>> => {
+  return http.put<CustomRole>(`/api/v1/roles/${id}`, input).then(http.event("role", "update"))
+}
+
+export const deleteCustomRole = async (id: number): Promise<Result> => {
+  return http.delete(`/api/v1/roles/${id}`).then(http.event("role", "delete"))
+}
+
+export const setUserCustomRole = async (userID: number, roleID: number): Promise<Result> => {
+  return http.put(`/api/v1/users/${userID}/custom-role`, { roleID }).then(http.event("user", "set-custom-role"))
+}
@@ -1,9 +1,16 @@
 export * from "./user"
 export * from "./tag"
+export * from "./custom-field"
+export * from "./company"
+export * from "./custom-role"
+export * from "./user-group"
 export * from "./post"
+export * from "./revision"
 export * from "./tenant"
 export * from "./notification"
 export * from "./invite"
 export * from "./infra"
 export * from "./webhook"
+export * from "./oauth-client"
 export * from "./billing"
+export * from "./audit-log"
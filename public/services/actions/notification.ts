@@ -18,3 +18,7 @@ export const getAllNotifications = async (): Promise<Result<Notification[]>> =>
 export const markAllAsRead = async (): Promise<Result> => {
   return await http.post("/_api/notifications/read-all")
 }
+
+export const unsubscribeByToken = async (token: string): Promise<Result> => {
+  return await http.post(`/unsubscribe/${token}`)
+}
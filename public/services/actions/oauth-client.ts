@@ -0,0 +1,20 @@
+import { http, Result } from "@fider/services/http"
+import { OAuthAuthorizationRequest, OAuthClient } from "@fider/models"
+
+interface CreateOAuthClientRequest {
+  name: string
+  redirectUris: string[]
+  isConfidential: boolean
+}
+
+export const createOAuthClient = async (request: CreateOAuthClientRequest): Promise<Result<{ client: OAuthClient; clientSecret: string }>> => {
+  return http.post<{ client: OAuthClient; clientSecret: string }>("/_api/admin/oauth-apps", request).then(http.event("oauth-app", "create"))
+}
+
+export const revokeOAuthClient = async (id: number): Promise<Result> => {
+  return http.delete(`/_api/admin/oauth-apps/${id}`).then(http.event("oauth-app", "revoke"))
+}
+
+export const authorizeOAuthClient = async (request: OAuthAuthorizationRequest, approved: boolean): Promise<Result<{ redirectURL: string }>> => {
+  return http.post<{ redirectURL: string }>("/_api/oauth2/authorize", { ...request, approved })
+}
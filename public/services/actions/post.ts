@@ -1,5 +1,5 @@
 import { http, Result, querystring } from "@fider/services"
-import { Post, Vote, ImageUpload, UserNames } from "@fider/models"
+import { Post, Vote, VoteBudget, ImageUpload, UserNames, Comment } from "@fider/models"
 
 export const getAllPosts = async (): Promise<Result<Post[]>> => {
   return await http.get<Post[]>("/api/v1/posts")
@@ -49,8 +49,29 @@ export const deletePost = async (postNumber: number, text: string): Promise<Resu
     .then(http.event("post", "delete"))
 }
 
-export const addVote = async (postNumber: number): Promise<Result> => {
-  return http.post(`/api/v1/posts/${postNumber}/votes`).then(http.event("post", "vote"))
+export const setPostPrivacy = async (postNumber: number, isPrivate: boolean): Promise<Result> => {
+  return http.put(`/api/v1/posts/${postNumber}/privacy`, { isPrivate }).then(http.event("post", "privacy"))
+}
+
+export const setPostGroup = async (postNumber: number, groupId: number): Promise<Result> => {
+  return http.put(`/api/v1/posts/${postNumber}/group`, { groupId }).then(http.event("post", "group"))
+}
+
+export const addVote = async (postNumber: number, weight?: number): Promise<Result> => {
+  return http.post(`/api/v1/posts/${postNumber}/votes`, weight ? { weight } : undefined).then(http.event("post", "vote"))
+}
+
+interface ProxyVoteInput {
+  userID?: number
+  name?: string
+  email?: string
+  company?: string
+  note?: string
+  weight?: number
+}
+
+export const addProxyVote = async (postNumber: number, input: ProxyVoteInput): Promise<Result<{ userID: number }>> => {
+  return http.post<{ userID: number }>(`/api/v1/posts/${postNumber}/votes/proxy`, input).then(http.event("post", "proxy-vote"))
 }
 
 export const removeVote = async (postNumber: number): Promise<Result> => {
@@ -61,6 +82,10 @@ export const toggleVote = async (postNumber: number): Promise<Result<{ voted: bo
   return http.post<{ voted: boolean }>(`/api/v1/posts/${postNumber}/votes/toggle`).then(http.event("post", "toggle-vote"))
 }
 
+export const getVoteBudget = async (): Promise<Result<VoteBudget>> => {
+  return http.get<VoteBudget>(`/api/v1/votes/budget`)
+}
+
 export const subscribe = async (postNumber: number): Promise<Result> => {
   return http.post(`/api/v1/posts/${postNumber}/subscription`).then(http.event("post", "subscribe"))
 }
@@ -69,6 +94,14 @@ export const unsubscribe = async (postNumber: number): Promise<Result> => {
   return http.delete(`/api/v1/posts/${postNumber}/subscription`).then(http.event("post", "unsubscribe"))
 }
 
+export const muteComments = async (postNumber: number): Promise<Result> => {
+  return http.post(`/api/v1/posts/${postNumber}/subscription/mute`).then(http.event("post", "mute-comments"))
+}
+
+export const unmuteComments = async (postNumber: number): Promise<Result> => {
+  return http.delete(`/api/v1/posts/${postNumber}/subscription/mute`).then(http.event("post", "unmute-comments"))
+}
+
 export const listVotes = async (postNumber: number): Promise<Result<Vote[]>> => {
   return http.get<Vote[]>(`/api/v1/posts/${postNumber}/votes`)
 }
@@ -77,8 +110,12 @@ export const getTaggableUsers = async (userFilter: string): Promise<Result<UserN
   return http.get<UserNames[]>(`/api/v1/taggable-users${querystring.stringify({ query: userFilter })}`)
 }
 
-export const createComment = async (postNumber: number, content: string, attachments: ImageUpload[]): Promise<Result> => {
-  return http.post(`/api/v1/posts/${postNumber}/comments`, { content, attachments }).then(http.event("comment", "create"))
+export const listComments = async (postNumber: number): Promise<Result<Comment[]>> => {
+  return http.get<Comment[]>(`/api/v1/posts/${postNumber}/comments`)
+}
+
+export const createComment = async (postNumber: number, content: string, attachments: ImageUpload[], isInternal = false): Promise<Result> => {
+  return http.post(`/api/v1/posts/${postNumber}/comments`, { content, attachments, isInternal }).then(http.event("comment", "create"))
 }
 
 export const updateComment = async (postNumber: number, commentID: number, content: string, attachments: ImageUpload[]): Promise<Result> => {
@@ -112,6 +149,24 @@ export const respond = async (postNumber: number, input: SetResponseInput): Prom
     .then(http.event("post", "respond"))
 }
 
+interface MergePostInput {
+  originalNumber: number
+  copyComments: boolean
+}
+
+export const mergePost = async (postNumber: number, input: MergePostInput): Promise<Result> => {
+  return http
+    .post(`/api/v1/posts/${postNumber}/merge`, {
+      originalNumber: input.originalNumber,
+      copyComments: input.copyComments,
+    })
+    .then(http.event("post", "merge"))
+}
+
+export const revertPostMerge = async (postNumber: number): Promise<Result> => {
+  return http.delete(`/api/v1/posts/${postNumber}/merge`).then(http.event("post", "revert-merge"))
+}
+
 interface CreatePostResponse {
   id: number
   number: number
@@ -119,8 +174,14 @@ interface CreatePostResponse {
   slug: string
 }
 
-export const createPost = async (title: string, description: string, attachments: ImageUpload[], tags: string[]): Promise<Result<CreatePostResponse>> => {
-  return http.post<CreatePostResponse>(`/api/v1/posts`, { title, description, attachments, tags }).then(http.event("post", "create"))
+export const createPost = async (
+  title: string,
+  description: string,
+  attachments: ImageUpload[],
+  tags: string[],
+  isPrivate = false
+): Promise<Result<CreatePostResponse>> => {
+  return http.post<CreatePostResponse>(`/api/v1/posts`, { title, description, attachments, tags, isPrivate }).then(http.event("post", "create"))
 }
 
 export const updatePost = async (postNumber: number, title: string, description: string, attachments: ImageUpload[]): Promise<Result> => {
@@ -0,0 +1,15 @@
+import { http, Result } from "@fider/services/http"
+import { Revision, RevisionDiff } from "@fider/models"
+
+export const listRevisions = async (postNumber: number): Promise<Result<Revision[]>> => {
+  return http.get<Revision[]>(`/api/v1/posts/${postNumber}/revisions`)
+}
+
+export const getRevisionDiff = async (postNumber: number, revisionId: number, compareId?: number): Promise<Result<RevisionDiff>> => {
+  const qs = compareId ? `?compare=${compareId}` : ""
+  return http.get<RevisionDiff>(`/api/v1/posts/${postNumber}/revisions/${revisionId}/diff${qs}`)
+}
+
+export const restoreRevision = async (postNumber: number, revisionId: number): Promise<Result> => {
+  return http.post(`/api/v1/posts/${postNumber}/revisions/${revisionId}/restore`).then(http.event("post", "restore-revision"))
+}
@@ -1,5 +1,6 @@
 import { http, Result } from "@fider/services/http"
-import { UserRole, OAuthConfig, ImageUpload, EmailVerificationKind } from "@fider/models"
+import { UserRole, OAuthConfig, SAMLConfig, ImageUpload, EmailVerificationKind, VotingMode, EmailTemplate, EmailTemplatePreview, OutboundEmail } from "@fider/models"
+import { stringify } from "@fider/services/querystring"
 import { PrivacySettingsPageState } from "@fider/pages/Administration/pages/PrivacySettings.page"
 
 export interface CheckAvailabilityResponse {
@@ -44,12 +45,28 @@ export const updateTenantPrivacy = async (request: PrivacySettingsPageState): Pr
   return await http.post("/_api/admin/settings/privacy", request)
 }
 
+export interface UpdateTenantVotingRequest {
+  votingMode: VotingMode
+  voteBudget: number
+  maxVotesPerPost: number
+}
+
+export const updateTenantVoting = async (request: UpdateTenantVotingRequest): Promise<Result> => {
+  return await http.post("/_api/admin/settings/voting", request)
+}
+
 export const updateTenantEmailAuthAllowed = async (isEmailAuthAllowed: boolean): Promise<Result> => {
   return await http.post("/_api/admin/settings/emailauth", {
     isEmailAuthAllowed,
   })
 }
 
+export const updateTenantTwoFactorRequired = async (isTwoFactorRequired: boolean): Promise<Result> => {
+  return await http.post("/_api/admin/settings/twofactor", {
+    isTwoFactorRequired,
+  })
+}
+
 export const checkAvailability = async (subdomain: string): Promise<Result<CheckAvailabilityResponse>> => {
   return await http.get<CheckAvailabilityResponse>(`/_api/tenants/${subdomain}/availability`)
 }
@@ -83,6 +100,14 @@ export const unblockUser = async (userID: number): Promise<Result> => {
   return await http.delete(`/_api/admin/users/${userID}/block`)
 }
 
+export const revokeSessionsOfUser = async (userID: number): Promise<Result> => {
+  return await http.delete(`/_api/admin/users/${userID}/sessions`)
+}
+
+export const revokeAllSessions = async (): Promise<Result> => {
+  return await http.delete("/_api/admin/sessions")
+}
+
 export const getOAuthConfig = async (provider: string): Promise<Result<OAuthConfig>> => {
   return await http.get<OAuthConfig>(`/_api/admin/oauth/${provider}`)
 }
@@ -100,6 +125,7 @@ export interface CreateEditOAuthConfigRequest {
   jsonUserIDPath: string
   jsonUserNamePath: string
   jsonUserEmailPath: string
+  jsonUserGroupsPath: string
   logo?: ImageUpload
   isTrusted: boolean
 }
@@ -107,3 +133,33 @@ export interface CreateEditOAuthConfigRequest {
 export const saveOAuthConfig = async (request: CreateEditOAuthConfigRequest): Promise<Result> => {
   return await http.post("/_api/admin/oauth", request)
 }
+
+export type SaveSAMLConfigRequest = Omit<SAMLConfig, "idpEntityID" | "idpSSOURL">
+
+export const saveSAMLConfig = async (request: SaveSAMLConfigRequest): Promise<Result> => {
+  return await http.post("/_api/admin/saml", request)
+}
+
+export const supressUserEmail = async (email: string): Promise<Result> => {
+  return await http.post(`/_api/admin/email-suppressions`, { email })
+}
+
+export const unsupressUserEmail = async (userID: number): Promise<Result> => {
+  return await http.delete(`/_api/admin/users/${userID}/email-suppression`)
+}
+
+export const saveEmailTemplate = async (template: EmailTemplate): Promise<Result> => {
+  return await http.post(`/_api/admin/email-templates`, template)
+}
+
+export const deleteEmailTemplate = async (name: string, locale: string): Promise<Result> => {
+  return await http.delete(`/_api/admin/email-templates/${name}/${locale}`)
+}
+
+export const previewEmailTemplate = async (name: string, subject: string, body: string): Promise<Result<EmailTemplatePreview>> => {
+  return await http.post(`/_api/admin/email-templates/preview`, { name, subject, body })
+}
+
+export const searchEmailDeliveries = async (address: string): Promise<Result<OutboundEmail[]>> => {
+  return await http.get<OutboundEmail[]>(`/_api/admin/email-deliveries${stringify({ address })}`)
+}
@@ -0,0 +1,36 @@
+import { http, Result } from "@fider/services/http"
+import { User, UserGroup } from "@fider/models"
+
+interface UserGroupInput {
+  name: string
+  emailDomains: string[]
+  oauthClaim: string
+}
+
+export const listUserGroups = async (): Promise<Result<UserGroup[]>> => {
+  return http.get<UserGroup[]>(`/api/v1/groups`)
+}
+
+export const createUserGroup = async (input: UserGroupInput): Promise<Result<UserGroup>> => {
+  return http.post<UserGroup>(`/api/v1/groups`, input).then(http.event("group", "create"))
+}
+
+export const updateUserGroup = async (id: number, input: UserGroupInput): Promise<Result<UserGroup>> => {
+  return http.put<UserGroup>(`/api/v1/groups/${id}`, input).then(http.event("group", "update"))
+}
+
+export const deleteUserGroup = async (id: number): Promise<Result> => {
+  return http.delete(`/api/v1/groups/${id}`).then(http.event("group", "delete"))
+}
+
+export const listUserGroupMembers = async (id: number): Promise<Result<User[]>> => {
+  return http.get<User[]>(`/api/v1/groups/${id}/members`)
+}
+
+export const addUserGroupMember = async (id: number, userID: number): Promise<Result> => {
+  return http.post(`/api/v1/groups/${id}/members/${userID}`).then(http.event("group", "add-member"))
+}
+
+export const removeUserGroupMember = async (id: number, userID: number): Promise<Result> => {
+  return http.delete(`/api/v1/groups/${id}/members/${userID}`).then(http.event("group", "remove-member"))
+}
@@ -1,5 +1,5 @@
 import { http, Result } from "@fider/services/http"
-import { UserSettings, UserAvatarType, ImageUpload } from "@fider/models"
+import { UserSettings, UserAvatarType, ImageUpload, APIToken, APIScope, UserSession } from "@fider/models"
 
 interface UpdateUserSettings {
   name: string
@@ -25,3 +25,78 @@ export const deleteCurrentAccount = async (): Promise<Result> => {
 export const regenerateAPIKey = async (): Promise<Result<{ apiKey: string }>> => {
   return await http.post<{ apiKey: string }>("/_api/user/regenerate-apikey")
 }
+
+export interface TwoFactorSetup {
+  secret: string
+  url: string
+}
+
+export const setupTwoFactor = async (): Promise<Result<TwoFactorSetup>> => {
+  return await http.post<TwoFactorSetup>("/_api/user/2fa/setup")
+}
+
+export const enableTwoFactor = async (code: string): Promise<Result<{ recoveryCodes: string[] }>> => {
+  return await http.post<{ recoveryCodes: string[] }>("/_api/user/2fa/enable", { code })
+}
+
+export const disableTwoFactor = async (code: string): Promise<Result> => {
+  return await http.post("/_api/user/2fa/disable", { code })
+}
+
+export const regenerateRecoveryCodes = async (code: string): Promise<Result<{ recoveryCodes: string[] }>> => {
+  return await http.post<{ recoveryCodes: string[] }>("/_api/user/2fa/recovery-codes", { code })
+}
+
+export const verifyTwoFactorCode = async (code: string): Promise<Result> => {
+  return await http.post("/_api/signin/2fa", { code })
+}
+
+export const listUserSessions = async (): Promise<Result<{ sessions: UserSession[]; currentSessionId: number }>> => {
+  return await http.get<{ sessions: UserSession[]; currentSessionId: number }>("/_api/user/sessions")
+}
+
+export const revokeUserSession = async (sessionID: number): Promise<Result> => {
+  return await http.delete(`/_api/user/sessions/${sessionID}`)
+}
+
+export const signOutEverywhere = async (): Promise<Result> => {
+  return await http.delete("/_api/user/sessions")
+}
+
+export const savePushSubscription = async (subscription: PushSubscriptionJSON): Promise<Result> => {
+  return await http.post("/_api/user/push-subscriptions", subscription)
+}
+
+export const deletePushSubscription = async (endpoint: string): Promise<Result> => {
+  return await http.delete("/_api/user/push-subscriptions", { endpoint })
+}
+
+export const snoozeNotifications = async (days: number): Promise<Result<{ snoozedUntil: string }>> => {
+  return await http.post<{ snoozedUntil: string }>("/_api/user/snooze", { days })
+}
+
+export const followTag = async (slug: string): Promise<Result> => {
+  return await http.post(`/_api/user/followed-tags/${slug}`)
+}
+
+export const unfollowTag = async (slug: string): Promise<Result> => {
+  return await http.delete(`/_api/user/followed-tags/${slug}`)
+}
+
+interface CreateAPITokenRequest {
+  name: string
+  scopes: APIScope[]
+  expiresInDays: number
+}
+
+export const listAPITokens = async (): Promise<Result<APIToken[]>> => {
+  return await http.get<APIToken[]>("/_api/user/api-tokens")
+}
+
+export const createAPIToken = async (request: CreateAPITokenRequest): Promise<Result<{ token: APIToken; key: string }>> => {
+  return await http.post<{ token: APIToken; key: string }>("/_api/user/api-tokens", request)
+}
+
+export const revokeAPIToken = async (id: number): Promise<Result> => {
+  return await http.delete(`/_api/user/api-tokens/${id}`)
+}
@@ -29,6 +29,8 @@ async function toResult<T>(response: Response): Promise<Result<T>> {
     notify.error("An unexpected error occurred while processing your request.")
   } else if (response.status === 401) {
     notify.error("You need to be authenticated to perform this operation.")
+  } else if (response.status === 403 && body.reauthenticate) {
+    location.href = `/signin/2fa?redirect=${encodeURIComponent(location.pathname + location.search)}`
   } else if (response.status === 403) {
     notify.error("You are not authorized to perform this operation.")
   }
@@ -0,0 +1,50 @@
+import { Fider } from "./fider"
+import { savePushSubscription, deletePushSubscription } from "./actions/user"
+
+const serviceWorkerURL = "/service-worker.js"
+
+const toApplicationServerKey = (base64: string): Uint8Array => {
+  const padding = "=".repeat((4 - (base64.length % 4)) % 4)
+  const raw = window.atob((base64 + padding).replace(/-/g, "+").replace(/_/g, "/"))
+  return Uint8Array.from(raw, (c) => c.charCodeAt(0))
+}
+
+const getSubscription = async (): Promise<PushSubscription | null> => {
+  const registration = await navigator.serviceWorker.getRegistration(serviceWorkerURL)
+  return registration ? await registration.pushManager.getSubscription() : null
+}
+
+export const isSupported = (): boolean => {
+  return !!Fider.settings.vapidPublicKey && "serviceWorker" in navigator && "PushManager" in window && "Notification" in window
+}
+
+export const isSubscribed = async (): Promise<boolean> => {
+  if (!isSupported()) {
+    return false
+  }
+  return (await getSubscription()) !== null
+}
+
+export const subscribe = async (): Promise<boolean> => {
+  if (!isSupported() || (await Notification.requestPermission()) !== "granted") {
+    return false
+  }
+
+  const registration = await navigator.serviceWorker.register(serviceWorkerURL)
+  await navigator.serviceWorker.ready
+  const subscription = await registration.pushManager.subscribe({
+    userVisibleOnly: true,
+    applicationServerKey: toApplicationServerKey(Fider.settings.vapidPublicKey),
+  })
+
+  const result = await savePushSubscription(subscription.toJSON())
+  return result.ok
+}
+
+export const unsubscribe = async (): Promise<void> => {
+  const subscription = isSupported() ? await getSubscription() : null
+  if (subscription) {
+    await deletePushSubscription(subscription.endpoint)
+    await subscription.unsubscribe()
+  }
+}
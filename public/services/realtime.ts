@@ -0,0 +1,76 @@
+export interface CommentCreatedEvent {
+  postNumber: number
+  commentId: number
+}
+
+export interface PostVotesEvent {
+  postNumber: number
+  votesCount: number
+  votesWeight: number
+}
+
+export interface PostStatusEvent {
+  postNumber: number
+  status: string
+}
+
+export interface RoadmapMovedEvent {
+  postNumber: number
+  columnId: number
+  position: number
+}
+
+type Handler = (data: any) => void
+
+const streamURL = "/_api/events"
+const handlers = new Map<string, Set<Handler>>()
+let source: EventSource | undefined
+
+const dispatch = (event: Event) => {
+  const message = event as MessageEvent
+  const data = JSON.parse(message.data)
+  const listeners = handlers.get(message.type)
+  if (listeners) {
+    listeners.forEach((handler) => handler(data))
+  }
+}
+
+export const isSupported = (): boolean => {
+  return "EventSource" in window
+}
+
+// All subscriptions share a single connection, which is opened on the first one and closed after the last one
+export const subscribe = <T>(type: string, handler: (data: T) => void): (() => void) => {
+  if (!isSupported()) {
+    return () => undefined
+  }
+
+  if (!source) {
+    source = new EventSource(streamURL)
+  }
+
+  let listeners = handlers.get(type)
+  if (!listeners) {
+    listeners = new Set<Handler>()
+    handlers.set(type, listeners)
+    source.addEventListener(type, dispatch)
+  }
+  listeners.add(handler)
+
+  return () => {
+    const current = handlers.get(type)
+    if (!current) {
+      return
+    }
+
+    current.delete(handler)
+    if (current.size === 0) {
+      handlers.delete(type)
+      source?.removeEventListener(type, dispatch)
+    }
+    if (handlers.size === 0 && source) {
+      source.close()
+      source = undefined
+    }
+  }
+}
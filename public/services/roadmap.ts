@@ -29,18 +29,20 @@ export const roadmap = {
     return response.data
   },
 
-  async createColumn(name: string, isVisibleToPublic: boolean): Promise<RoadmapColumn> {
+  async createColumn(name: string, isVisibleToPublic: boolean, groupId?: number): Promise<RoadmapColumn> {
     const response = await http.post<RoadmapColumn>("/api/v1/admin/roadmap/columns", {
       name,
       isVisibleToPublic,
+      groupId,
     })
     return response.data
   },
 
-  async updateColumn(id: number, name: string, isVisibleToPublic: boolean): Promise<RoadmapColumn> {
+  async updateColumn(id: number, name: string, isVisibleToPublic: boolean, groupId?: number): Promise<RoadmapColumn> {
     const response = await http.put<RoadmapColumn>(`/api/v1/admin/roadmap/columns/${id}`, {
       name,
       isVisibleToPublic,
+      groupId,
     })
     return response.data
   },
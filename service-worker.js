@@ -0,0 +1,24 @@
+// Shows the push notifications sent by Fider and opens the related page when one is clicked
+self.addEventListener("push", function (event) {
+  if (!event.data) {
+    return
+  }
+
+  var message = event.data.json()
+  event.waitUntil(
+    self.registration.showNotification(message.title, {
+      body: message.body,
+      icon: message.icon,
+      data: { url: message.url },
+    })
+  )
+})
+
+self.addEventListener("notificationclick", function (event) {
+  event.notification.close()
+
+  var url = event.notification.data && event.notification.data.url
+  if (url) {
+    event.waitUntil(self.clients.openWindow(url))
+  }
+})